@@ -0,0 +1,36 @@
+//go:build js && wasm
+
+// Command rpgo-wasm builds the calculation engine as a WebAssembly module.
+//
+// Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o rpgo.wasm ./cmd/rpgo-wasm
+//
+// The module registers a global rpgoCalculate(configJSON) function that returns
+// an object with either a "result" (scenario comparison JSON) or an "error" string.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/rgehrsitz/rpgo/internal/webcalc"
+)
+
+func calculate(this js.Value, args []js.Value) any {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return map[string]any{"error": "rpgoCalculate expects a single configuration JSON string"}
+	}
+
+	result, err := webcalc.Calculate([]byte(args[0].String()))
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	return map[string]any{"result": string(result)}
+}
+
+func main() {
+	js.Global().Set("rpgoCalculate", js.FuncOf(calculate))
+
+	// Keep the Go runtime alive so the exported function stays callable
+	select {}
+}
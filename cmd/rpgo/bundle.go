@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/bundle"
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Package a run into a single archive, or reproduce one",
+	Long: `Package a configuration, its regulatory file, the historical data it uses and
+its results into a single zip archive, and reproduce the run from one elsewhere.
+Bundles are useful for bug reports and for sharing a plan with an advisor.`,
+}
+
+var bundleExportCmd = &cobra.Command{
+	Use:   "export [input-file]",
+	Short: "Package a configuration and its results into a bundle",
+	Long: `Package a configuration, its regulatory file, the historical data files it uses
+and the results of running it into a single zip archive.
+
+With --redact every account balance and cost basis is rounded to two significant
+digits (487,312 becomes 490,000) and the bundled results are those of the redacted
+plan, so an import still reproduces them. Post-run hooks are never bundled.
+
+Examples:
+  ./rpgo bundle export config.yaml -o plan-bundle.zip
+  ./rpgo bundle export config.yaml -r regulatory.yaml --redact -o bug-report.zip`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+		outputFile, _ := cmd.Flags().GetString("output")
+		regulatoryFile, _ := cmd.Flags().GetString("regulatory-config")
+		redact, _ := cmd.Flags().GetBool("redact")
+		if outputFile == "" {
+			outputFile = strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile)) + "-bundle.zip"
+		}
+		if regulatoryFile == "" && fileExists("regulatory.yaml") {
+			regulatoryFile = "regulatory.yaml"
+		}
+
+		configData, err := os.ReadFile(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", inputFile, err)
+			os.Exit(1)
+		}
+		cfg, err := config.NewInputParser().Parse(configData)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+		configData, removedHooks, err := bundle.ConfigForExport(configData, redact)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error preparing the configuration: %v\n", err)
+			os.Exit(1)
+		}
+		var regulatoryData []byte
+		if regulatoryFile != "" {
+			if regulatoryData, err = os.ReadFile(regulatoryFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", regulatoryFile, err)
+				os.Exit(1)
+			}
+		}
+
+		b := bundle.New(inputFile, configData, regulatoryData)
+		b.Manifest.Redacted = redact
+		if _, dataPath := loadHistoricalData(cmd, cfg, inputFile); dataPath != "" {
+			if err := b.AddDataFiles(dataPath, calculation.HistoricalDataFiles()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading historical data: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Run from the bundled files themselves, so the recorded results are exactly what
+		// an import reproduces
+		dir, err := os.MkdirTemp("", "rpgo-bundle-")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(dir)
+		if err := b.Extract(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error staging bundle: %v\n", err)
+			os.Exit(1)
+		}
+		results, err := runBundle(dir, b.Manifest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error running the bundled configuration: %v\n", err)
+			os.Exit(1)
+		}
+		if err := b.SetResults(results); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var buf bytes.Buffer
+		if err := b.Write(&buf); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing bundle: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(outputFile, buf.Bytes(), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Bundled %s with %d scenario(s) and %d historical data file(s) into %s\n",
+			inputFile, len(results.Scenarios), len(b.Manifest.DataFiles), outputFile)
+		if redact {
+			fmt.Println("Balances are redacted to two significant digits")
+		}
+		if removedHooks {
+			fmt.Println("Post-run hooks are left out of the bundled configuration")
+		}
+	},
+}
+
+var bundleImportCmd = &cobra.Command{
+	Use:   "import [bundle-file]",
+	Short: "Extract a bundle and reproduce its run",
+	Long: `Extract a bundle, run its configuration with the bundled regulatory file and
+historical data, and check that the results match the ones recorded in the bundle.
+Exits with status 1 when they differ.
+
+Examples:
+  ./rpgo bundle import plan-bundle.zip
+  ./rpgo bundle import plan-bundle.zip --dir plan --format console`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		bundleFile := args[0]
+		dir, _ := cmd.Flags().GetString("dir")
+		format, _ := cmd.Flags().GetString("format")
+		if dir == "" {
+			dir = strings.TrimSuffix(filepath.Base(bundleFile), filepath.Ext(bundleFile))
+		}
+
+		b, err := bundle.Read(bundleFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading bundle: %v\n", err)
+			os.Exit(1)
+		}
+		if err := b.Extract(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error extracting bundle: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Extracted %s (exported %s) into %s\n", b.Manifest.Source, b.Manifest.CreatedAt.Format("2006-01-02"), dir)
+		if b.Manifest.Redacted {
+			fmt.Fprintf(os.Stderr, "Note: balances in this bundle are redacted\n")
+		}
+
+		results, err := runBundle(dir, b.Manifest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error running the bundled configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if format != "" {
+			f := output.GetFormatterByName(format)
+			if f == nil {
+				fmt.Fprintf(os.Stderr, "Unknown format %q\n", format)
+				os.Exit(1)
+			}
+			data, err := f.Format(results)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(string(data))
+		}
+
+		diffs := bundle.CompareResults(b.Manifest.Scenarios, bundle.Summarize(results))
+		if len(diffs) > 0 {
+			fmt.Fprintf(os.Stderr, "Results differ from the bundle:\n")
+			for _, d := range diffs {
+				fmt.Fprintf(os.Stderr, "  - %s\n", d)
+			}
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Reproduced %d scenario(s): results match the bundle\n", len(results.Scenarios))
+	},
+}
+
+// runBundle runs the configuration of a bundle extracted into dir with its regulatory
+// file and historical data
+func runBundle(dir string, manifest bundle.Manifest) (*domain.ScenarioComparison, error) {
+	parser := config.NewInputParser()
+	configFile := filepath.Join(dir, bundle.ConfigFile)
+	var cfg *domain.Configuration
+	var err error
+	if manifest.Regulatory {
+		cfg, err = parser.LoadFromFileWithRegulatory(configFile, filepath.Join(dir, bundle.RegulatoryFile))
+	} else {
+		cfg, err = parser.LoadFromFile(configFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+	if len(manifest.DataFiles) > 0 {
+		dataPath := filepath.Join(dir, bundle.DataDir)
+		hdm := calculation.NewHistoricalDataManager(dataPath)
+		if err := hdm.LoadAllData(); err == nil {
+			engine.HistoricalData = hdm
+			engine.SetDataPath(dataPath)
+		}
+	}
+	return engine.RunScenarios(cfg)
+}
+
+func init() {
+	bundleExportCmd.Flags().StringP("output", "o", "", "Bundle file to write (default: <input>-bundle.zip)")
+	bundleExportCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file (default: regulatory.yaml when present)")
+	bundleExportCmd.Flags().String("data-path", "", "Historical data directory (default: historical_data_path or the standard locations)")
+	bundleExportCmd.Flags().Bool("redact", false, "Round account balances to two significant digits")
+
+	bundleImportCmd.Flags().String("dir", "", "Directory to extract into (default: the bundle name)")
+	bundleImportCmd.Flags().StringP("format", "f", "", "Also print the reproduced results in this format (e.g. console, json)")
+
+	bundleCmd.AddCommand(bundleExportCmd, bundleImportCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
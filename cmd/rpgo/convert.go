@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [input-file]",
+	Short: "Convert a configuration between YAML and JSON",
+	Long: `Convert an rpgo configuration between YAML and JSON. The input format is
+detected from the file extension (or content); the output defaults to the other
+format, or to the format implied by the --output extension.
+
+Examples:
+  ./rpgo convert config.yaml --output config.json
+  ./rpgo convert config.json --to yaml > config.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+		to, _ := cmd.Flags().GetString("to")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		data, err := os.ReadFile(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", inputFile, err)
+			os.Exit(1)
+		}
+		from := config.DetectFormat(inputFile, data)
+
+		parser := config.NewInputParser()
+		cfg, err := parser.ParseFormat(data, from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		to = strings.ToLower(to)
+		if to == "" && outputFile != "" && config.DetectFormat(outputFile, nil) == config.FormatJSON {
+			to = config.FormatJSON
+		}
+		if to == "" {
+			to = config.FormatJSON
+			if from == config.FormatJSON {
+				to = config.FormatYAML
+			}
+		}
+		if to == "yml" {
+			to = config.FormatYAML
+		}
+
+		out, err := config.EncodeConfiguration(cfg, to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFile == "" {
+			fmt.Print(string(out))
+			return
+		}
+		if err := os.WriteFile(outputFile, out, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Converted %s (%s) to %s (%s)\n", inputFile, from, outputFile, to)
+	},
+}
+
+func init() {
+	convertCmd.Flags().String("to", "", "Output format: yaml or json (default: the other format)")
+	convertCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+
+	rootCmd.AddCommand(convertCmd)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/datafetch"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var historicalFetchCmd = &cobra.Command{
+	Use:   "fetch [data-path]",
+	Short: "Download the latest fund returns, and with --all inflation and COLAs, into the data",
+	Long: `Download the individual funds' share price history from tsp.gov, convert it to
+calendar-year returns, and add the years missing from the fund return files in the
+data directory. With --all, also add CPI-U inflation from the BLS API and Social
+Security COLAs from SSA. Years already on file keep their values unless --replace
+is given, and a year is added only once it is complete.
+
+By default each series is fetched from the last year it has on file. A source that
+can't be reached leaves its files as they were and the others still update; a TSP
+price history downloaded by hand can be read with --file instead.
+
+Examples:
+  # Add the years since the data was last updated
+  ./rpgo historical fetch ./data --all
+
+  # Recompute every fund return since 2010 from share prices
+  ./rpgo historical fetch ./data --from 2010 --replace
+
+  # Use a price history downloaded from tsp.gov
+  ./rpgo historical fetch ./data --file fund-price-history.csv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dataPath := args[0]
+
+		all, _ := cmd.Flags().GetBool("all")
+		from, _ := cmd.Flags().GetInt("from")
+		replace, _ := cmd.Flags().GetBool("replace")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		client := &http.Client{Timeout: timeout}
+		sources := []historicalSource{tspSource(cmd, dataPath, client, from, replace)}
+		if all {
+			sources = append(sources,
+				inflationSource(cmd, dataPath, client, from, replace),
+				colaSource(cmd, dataPath, client, from, replace))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		failed := false
+		for _, source := range sources {
+			updates, err := source.fetch(ctx)
+			if errors.Is(err, context.Canceled) {
+				fmt.Fprintln(os.Stderr, "\nDownload cancelled")
+				os.Exit(130)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not update %s: %v\n", source.name, err)
+				if source.lastYear == 0 {
+					fmt.Fprintf(os.Stderr, "No %s on file in %s yet; try again later\n", source.name, dataPath)
+					failed = true
+				} else {
+					fmt.Fprintf(os.Stderr, "Left the %s in %s unchanged (through %d)\n", source.name, dataPath, source.lastYear)
+				}
+				continue
+			}
+			for _, update := range updates {
+				var changes []string
+				if len(update.Added) > 0 {
+					changes = append(changes, "added "+joinYears(update.Added))
+				}
+				if len(update.Updated) > 0 {
+					changes = append(changes, "replaced "+joinYears(update.Updated))
+				}
+				if len(changes) == 0 {
+					changes = append(changes, "up to date")
+				}
+				fmt.Printf("  %s: %s (through %d)\n", update.Series, strings.Join(changes, ", "), update.LastYear)
+			}
+		}
+
+		// Report series that no longer cover the same years
+		hdm := calculation.NewHistoricalDataManager(dataPath)
+		if err := hdm.LoadAllData(); err == nil {
+			issues, _ := hdm.ValidateDataQuality()
+			for _, issue := range issues {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", issue)
+			}
+			if len(issues) > 0 && !all {
+				fmt.Fprintf(os.Stderr, "Inflation and COLA are fetched with --all\n")
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// historicalSource is one series the fetch command updates
+type historicalSource struct {
+	name string
+	// lastYear is the last year the series has on file before the fetch, 0 if none
+	lastYear int
+	fetch    func(ctx context.Context) ([]datafetch.SeriesUpdate, error)
+}
+
+// tspSource fetches the funds' returns from tsp.gov share prices, or from a price
+// history file
+func tspSource(cmd *cobra.Command, dataPath string, client *http.Client, from int, replace bool) historicalSource {
+	file, _ := cmd.Flags().GetString("file")
+	priceURL, _ := cmd.Flags().GetString("url")
+	lastYear := datafetch.FundsLastYear(dataPath)
+	return historicalSource{
+		name:     "TSP fund returns",
+		lastYear: lastYear,
+		fetch: func(ctx context.Context) ([]datafetch.SeriesUpdate, error) {
+			var prices map[string][]datafetch.PricePoint
+			if file != "" {
+				f, err := os.Open(file)
+				if err != nil {
+					return nil, err
+				}
+				defer f.Close()
+				if prices, err = datafetch.ParsePrices(f); err != nil {
+					return nil, fmt.Errorf("reading %s: %w", file, err)
+				}
+			} else {
+				// The year before the first new one supplies the starting price
+				start := time.Date(firstFetchYear(from, lastYear)-1, time.December, 1, 0, 0, 0, 0, time.UTC)
+				fmt.Printf("Downloading TSP share prices since %s...\n", start.Format("2006-01-02"))
+				var err error
+				if prices, err = datafetch.FetchTSPPrices(ctx, client, priceURL, start, time.Now()); err != nil {
+					return nil, err
+				}
+			}
+
+			returns := make(map[string]map[int]decimal.Decimal, len(prices))
+			for fund, fundPrices := range prices {
+				returns[fund] = keepFrom(datafetch.AnnualReturns(fundPrices), firstFetchYear(from, lastYear))
+			}
+			for _, fund := range datafetch.Funds {
+				if _, ok := prices[fund]; !ok {
+					fmt.Fprintf(os.Stderr, "Warning: no %s Fund prices in the download\n", fund)
+				}
+			}
+			return datafetch.UpdateReturns(dataPath, returns, replace)
+		},
+	}
+}
+
+// inflationSource fetches CPI-U inflation from the BLS API
+func inflationSource(cmd *cobra.Command, dataPath string, client *http.Client, from int, replace bool) historicalSource {
+	apiURL, _ := cmd.Flags().GetString("bls-url")
+	key, _ := cmd.Flags().GetString("bls-key")
+	if key == "" {
+		key = os.Getenv("BLS_API_KEY")
+	}
+	file := filepath.Join(dataPath, datafetch.InflationFile)
+	lastYear := datafetch.SeriesLastYear(file)
+	return historicalSource{
+		name:     "inflation rates",
+		lastYear: lastYear,
+		fetch: func(ctx context.Context) ([]datafetch.SeriesUpdate, error) {
+			first := firstFetchYear(from, lastYear)
+			// The year before the first new one supplies the starting average
+			fmt.Printf("Downloading CPI-U since %d...\n", first-1)
+			averages, err := datafetch.FetchCPI(ctx, client, apiURL, key, first-1, time.Now().Year())
+			if err != nil {
+				return nil, err
+			}
+			rates := keepFrom(datafetch.InflationRates(averages), first)
+			update, err := datafetch.UpdateSeries("Inflation", file, rates, replace)
+			return []datafetch.SeriesUpdate{update}, err
+		},
+	}
+}
+
+// colaSource fetches Social Security COLAs from SSA
+func colaSource(cmd *cobra.Command, dataPath string, client *http.Client, from int, replace bool) historicalSource {
+	colaURL, _ := cmd.Flags().GetString("cola-url")
+	file := filepath.Join(dataPath, datafetch.COLAFile)
+	lastYear := datafetch.SeriesLastYear(file)
+	return historicalSource{
+		name:     "COLAs",
+		lastYear: lastYear,
+		fetch: func(ctx context.Context) ([]datafetch.SeriesUpdate, error) {
+			fmt.Printf("Downloading Social Security COLAs...\n")
+			colas, err := datafetch.FetchCOLA(ctx, client, colaURL)
+			if err != nil {
+				return nil, err
+			}
+			update, err := datafetch.UpdateSeries("COLA", file, keepFrom(colas, firstFetchYear(from, lastYear)), replace)
+			return []datafetch.SeriesUpdate{update}, err
+		},
+	}
+}
+
+// firstFetchYear returns the first year to fetch: --from when given, else the year
+// after the last on file, else the TSP's first full year
+func firstFetchYear(from, lastYear int) int {
+	switch {
+	case from > 0:
+		return from
+	case lastYear > 0:
+		return lastYear + 1
+	default:
+		return 1988
+	}
+}
+
+// keepFrom drops the years before first
+func keepFrom(rates map[int]decimal.Decimal, first int) map[int]decimal.Decimal {
+	for year := range rates {
+		if year < first {
+			delete(rates, year)
+		}
+	}
+	return rates
+}
+
+// joinYears lists years separated by commas
+func joinYears(years []int) string {
+	parts := make([]string, len(years))
+	for i, year := range years {
+		parts[i] = fmt.Sprint(year)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	historicalFetchCmd.Flags().Bool("all", false, "Also fetch CPI-U inflation from BLS and Social Security COLAs from SSA")
+	historicalFetchCmd.Flags().Int("from", 0, "First year to add (default: the year after the last on file)")
+	historicalFetchCmd.Flags().String("file", "", "Read a price history CSV downloaded from tsp.gov instead of downloading")
+	historicalFetchCmd.Flags().Bool("replace", false, "Replace values already on file with those fetched")
+	historicalFetchCmd.Flags().Duration("timeout", datafetch.DefaultTimeout, "Give up on each download after this long")
+	historicalFetchCmd.Flags().String("url", datafetch.TSPPriceURL, "TSP price history download URL")
+	historicalFetchCmd.Flags().String("bls-url", datafetch.BLSAPIURL, "BLS API time series URL")
+	historicalFetchCmd.Flags().String("bls-key", "", "BLS API registration key (default: $BLS_API_KEY); allows longer spans of years per request")
+	historicalFetchCmd.Flags().String("cola-url", datafetch.SSACOLAURL, "SSA COLA history page URL")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var historicalRollingCmd = &cobra.Command{
+	Use:   "rolling [data-path]",
+	Short: "Run a plan through every historical window of returns and inflation",
+	Long: `Project a scenario once for every run of consecutive years in the historical
+data, replaying each window's returns, inflation and COLAs from the first
+projection year, and report the distribution of outcomes with the worst, median
+and best starting years. Years past the window use the plan's assumptions.
+
+Examples:
+  # Every 30-year window for the first scenario
+  ./rpgo historical rolling ./data --window 30 --config config.yaml
+
+  # Ten-year windows for a named scenario, as JSON
+  ./rpgo historical rolling ./data --window 10 --config config.yaml --scenario "Retire 2027" --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dataPath := args[0]
+
+		configFile, _ := cmd.Flags().GetString("config")
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		window, _ := cmd.Flags().GetInt("window")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		if configFile == "" {
+			fmt.Fprintf(os.Stderr, "--config is required\n")
+			os.Exit(1)
+		}
+		if window < 1 {
+			fmt.Fprintf(os.Stderr, "--window must be at least 1, got %d\n", window)
+			os.Exit(1)
+		}
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(configFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(configFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		if scenarioName == "" {
+			scenarioName = cfg.Scenarios[0].Name
+		}
+
+		// Load historical data
+		hdm := calculation.NewHistoricalDataManager(dataPath)
+		if err := hdm.LoadAllData(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading historical data: %v\n", err)
+			os.Exit(1)
+		}
+
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		engine.HistoricalData = hdm
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		result, err := engine.RunRollingPeriods(ctx, cfg, scenarioName, window)
+		exitIfCancelled(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error running rolling periods: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "json":
+			out, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			printRollingPeriods(result)
+		}
+	},
+}
+
+// printRollingPeriods prints the distribution of outcomes across windows and the worst,
+// median and best starting years
+func printRollingPeriods(result *calculation.RollingPeriodResult) {
+	first, last := result.Windows[0], result.Windows[len(result.Windows)-1]
+	fmt.Printf("ROLLING HISTORICAL PERIODS: %s\n", result.ScenarioName)
+	fmt.Printf("=================================================\n\n")
+	fmt.Printf("Windows: %d of %d years, starting %d-%d\n", len(result.Windows), result.Window, first.StartYear, last.StartYear)
+	fmt.Printf("Projection Years: %d\n", result.ProjectionYears)
+	fmt.Printf("Success Rate: %s (TSP lasts the whole projection)\n\n", domain.FormatRate(result.SuccessRate))
+
+	fmt.Printf("%-16s %10s %16s %16s %12s %8s\n", "", "Start", "Lifetime Income", "Final TSP", "Lowest TSP", "TSP Lasts")
+	for _, ranked := range []struct {
+		label  string
+		window calculation.RollingWindow
+	}{{"Worst", result.Worst}, {"Median", result.Median}, {"Best", result.Best}} {
+		w := ranked.window
+		fmt.Printf("%-16s %10d %16s %16s %12s %5d yr\n", ranked.label, w.StartYear,
+			"$"+w.TotalLifetimeIncome.StringFixed(0), "$"+w.FinalTSPBalance.StringFixed(0),
+			"$"+w.LowestTSPBalance.StringFixed(0), w.TSPLongevity)
+	}
+
+	fmt.Printf("\nFinal TSP Balance:\n")
+	for _, p := range []string{"10th", "50th", "90th"} {
+		fmt.Printf("  %s percentile: $%s\n", p, result.FinalTSPBalance[p].StringFixed(0))
+	}
+	fmt.Printf("\nLifetime Income:\n")
+	for _, p := range []string{"10th", "50th", "90th"} {
+		fmt.Printf("  %s percentile: $%s\n", p, result.LifetimeIncome[p].StringFixed(0))
+	}
+}
+
+func init() {
+	historicalRollingCmd.Flags().Int("window", 30, "Years of history in each window (capped at the projection length)")
+	historicalRollingCmd.Flags().StringP("config", "c", "", "Plan configuration file (required)")
+	historicalRollingCmd.Flags().String("scenario", "", "Scenario to run (default: first scenario)")
+	historicalRollingCmd.Flags().StringP("format", "f", "console", "Output format (console, json)")
+	historicalRollingCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [source-file]",
+	Short: "Import a plan from another tool into an rpgo configuration",
+	Long: `Import accounts and income streams exported from a spreadsheet or another
+planning tool and write an equivalent rpgo YAML configuration.
+
+The generic CSV format has four columns: section,name,field,value
+
+  section,name,field,value
+  household,,filing_status,married_filing_jointly
+  participant,Alice,birth_date,1965-03-15
+  participant,Alice,is_federal,true
+  participant,Alice,current_salary,"$145,000"
+  participant,Alice,tsp_contribution_percent,15%
+  assumptions,,state,Pennsylvania
+  scenario,Base,Alice.retirement_date,2027-06-30
+  scenario,Base,Alice.ss_start_age,67
+
+Examples:
+  ./rpgo import accounts.csv --output config.yaml
+  ./rpgo import accounts.csv --from csv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceFile := args[0]
+		from, _ := cmd.Flags().GetString("from")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		parser := config.NewInputParser()
+		cfg, err := parser.ImportFromFile(sourceFile, from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing %s: %v\n", sourceFile, err)
+			os.Exit(1)
+		}
+
+		data, err := config.EncodeConfiguration(cfg, config.FormatYAML)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFile == "" {
+			fmt.Print(string(data))
+			return
+		}
+		if err := os.WriteFile(outputFile, data, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %d participant(s) and %d scenario(s) into %s\n", len(cfg.Household.Participants), len(cfg.Scenarios), outputFile)
+	},
+}
+
+func init() {
+	importCmd.Flags().String("from", "csv", "Source format (csv)")
+	importCmd.Flags().StringP("output", "o", "", "Output YAML file (default: stdout)")
+
+	rootCmd.AddCommand(importCmd)
+}
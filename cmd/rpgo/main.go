@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"runtime/debug"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/rgehrsitz/rpgo/internal/breakeven"
@@ -14,6 +20,7 @@ import (
 	"github.com/rgehrsitz/rpgo/internal/compare"
 	"github.com/rgehrsitz/rpgo/internal/config"
 	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/internal/hooks"
 	"github.com/rgehrsitz/rpgo/internal/output"
 	"github.com/rgehrsitz/rpgo/internal/transform"
 	"github.com/shopspring/decimal"
@@ -64,6 +71,39 @@ var rootCmd = &cobra.Command{
 	Use:   "rpgo",
 	Short: "FERS Retirement Calculator CLI",
 	Long:  "Comprehensive retirement planning calculator for federal employees",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if domain.PercentPrecision < 0 || domain.PercentPrecision > 6 {
+			return fmt.Errorf("--percent-precision must be between 0 and 6")
+		}
+		if !domain.ValidCurrencyRounding(domain.CurrencyRounding) {
+			return fmt.Errorf("--currency-rounding must be %q or %q", domain.CurrencyRoundingHalfUp, domain.CurrencyRoundingBankers)
+		}
+		return nil
+	},
+}
+
+// loadHistoricalData resolves the data directory from --data-path, the configuration's
+// historical_data_path and the standard search locations, then loads historical data
+// from it. It returns a nil manager when no usable data is found.
+func loadHistoricalData(cmd *cobra.Command, cfg *domain.Configuration, inputFile string) (*calculation.HistoricalDataManager, string) {
+	flagPath, _ := cmd.Flags().GetString("data-path")
+	dataPath, err := config.ResolveDataPath(flagPath, cfg.GlobalAssumptions.HistoricalDataPath, inputFile)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		fmt.Printf("Falling back to statistical models...\n")
+		return nil, ""
+	}
+	if dataPath == "" {
+		return nil, ""
+	}
+
+	hdm := calculation.NewHistoricalDataManager(dataPath)
+	if loadErr := hdm.LoadAllData(); loadErr != nil {
+		fmt.Printf("Warning: Could not load historical data from %s: %v\n", dataPath, loadErr)
+		fmt.Printf("Falling back to statistical models...\n")
+		return nil, dataPath
+	}
+	return hdm, dataPath
 }
 
 var calculateCmd = &cobra.Command{
@@ -107,47 +147,70 @@ var calculateCmd = &cobra.Command{
 			}
 		}
 
+		printConfigWarnings(parser)
+
 		// Load historical data if available
-		var hdm *calculation.HistoricalDataManager
-		dataPath := "data" // Default path, could be made configurable
-		if _, err := os.Stat(dataPath); err == nil {
-			hdm = calculation.NewHistoricalDataManager(dataPath)
-			if loadErr := hdm.LoadAllData(); loadErr != nil {
-				fmt.Printf("Warning: Could not load historical data from %s: %v\n", dataPath, loadErr)
-				fmt.Printf("Falling back to statistical models...\n")
-				hdm = nil
-			}
-		}
+		hdm, dataPath := loadHistoricalData(cmd, configData, inputFile)
 
 		// Run calculations
 		engine := calculation.NewCalculationEngineWithConfig(configData.GlobalAssumptions.FederalRules)
 		engine.HistoricalData = hdm // Set the historical data manager
+		if dataPath != "" {
+			engine.SetDataPath(dataPath)
+		}
 		debugMode, _ := cmd.Flags().GetBool("debug")
 		if debugMode {
 			engine.SetLogger(simpleCLILogger{})
 		}
 		engine.Debug = debugMode
+		showTimings, _ := cmd.Flags().GetBool("timings")
+		if showTimings || debugMode {
+			engine.Timings = calculation.NewTimingRecorder()
+		}
 		results, err := engine.RunScenarios(configData)
 		if err != nil {
 			log.Fatal(err)
 		}
+		if showTimings {
+			printTimings(os.Stderr, engine.Timings.Timings())
+		}
 
 		// Generate output
 		outputFormat, _ := cmd.Flags().GetString("format")
+		report := results
+		if redact, _ := cmd.Flags().GetBool("redact"); redact {
+			if report, err = output.RedactResults(results, output.RandomRedactionFactor()); err != nil {
+				log.Fatal(err)
+			}
+		}
 
 		// Get the formatter and write to stdout instead of file
-		if f := output.GetFormatterByName(outputFormat); f != nil {
-			data, err := f.Format(results)
+		if output.NormalizeFormatName(outputFormat) == "html" {
+			collapseAfter, _ := cmd.Flags().GetInt("html-collapse-after")
+			maxSizeMB, _ := cmd.Flags().GetFloat64("html-max-size-mb")
+			if err := streamHTMLReport(htmlFormatterFromFlags(collapseAfter, maxSizeMB), report); err != nil {
+				log.Fatal(err)
+			}
+		} else if f := output.GetFormatterByName(outputFormat); f != nil {
+			data, err := f.Format(report)
 			if err != nil {
 				log.Fatal(err)
 			}
 			fmt.Print(string(data))
 		} else {
 			// Fallback to original GenerateReport for unsupported formats
-			if err := output.GenerateReport(results, outputFormat); err != nil {
+			if err := output.GenerateReport(report, outputFormat); err != nil {
 				log.Fatal(err)
 			}
 		}
+
+		// Hand the results to any post-run hooks; a failing hook does not fail the run
+		if noHooks, _ := cmd.Flags().GetBool("no-hooks"); configData.Hooks != nil && !noHooks {
+			payload := hooks.Payload{InputFile: inputFile, CompletedAt: time.Now(), Results: results}
+			for _, err := range hooks.RunPostRun(context.Background(), configData.Hooks.PostRun, payload) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
 	},
 }
 
@@ -161,10 +224,47 @@ var validateCmd = &cobra.Command{
 		inputFile := args[0]
 
 		parser := config.NewInputParser()
-		_, err := parser.LoadFromFile(inputFile)
+		cfg, err := parser.LoadFromFile(inputFile)
 		if err != nil {
 			log.Fatal(err)
 		}
+		printConfigWarnings(parser)
+
+		// Report structural problems in every scenario, not just the first
+		feasible := true
+		for i := range cfg.Scenarios {
+			if err := calculation.CheckScenarioFeasibility(cfg.Household, &cfg.Scenarios[i], &cfg.GlobalAssumptions); err != nil {
+				var fe *calculation.FeasibilityError
+				if !errors.As(err, &fe) {
+					log.Fatal(err)
+				}
+				feasible = false
+				fmt.Fprintf(os.Stderr, "Scenario %s:\n", fe.Scenario)
+				for _, v := range fe.Violations {
+					fmt.Fprintf(os.Stderr, "  - %s\n", v)
+				}
+			}
+		}
+		if !feasible {
+			os.Exit(1)
+		}
+
+		// Events the projection ends too early to include are legal but silently missing
+		for i := range cfg.Scenarios {
+			scenario := &cfg.Scenarios[i]
+			warnings := calculation.ProjectionHorizonWarnings(cfg.Household, scenario, &cfg.GlobalAssumptions)
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "Warning: scenario %s: %s\n", scenario.Name, w.Message)
+			}
+			if len(warnings) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: scenario %s needs projection_years: %d to include every event\n",
+					scenario.Name, calculation.MinimumProjectionYears(cfg.Household, scenario))
+			}
+			// FEHB ends at retirement without five years of coverage
+			for _, w := range calculation.FEHBEligibilityWarnings(cfg.Household, scenario) {
+				fmt.Fprintf(os.Stderr, "Warning: scenario %s: %s\n", scenario.Name, w.Message)
+			}
+		}
 
 		fmt.Printf("Configuration file %s is valid\n", inputFile)
 	},
@@ -185,20 +285,14 @@ var breakEvenCmd = &cobra.Command{
 		}
 
 		// Load historical data if available
-		var hdm *calculation.HistoricalDataManager
-		dataPath := "data" // Default path, could be made configurable
-		if _, err := os.Stat(dataPath); err == nil {
-			hdm = calculation.NewHistoricalDataManager(dataPath)
-			if loadErr := hdm.LoadAllData(); loadErr != nil {
-				fmt.Printf("Warning: Could not load historical data from %s: %v\n", dataPath, loadErr)
-				fmt.Printf("Falling back to statistical models...\n")
-				hdm = nil
-			}
-		}
+		hdm, dataPath := loadHistoricalData(cmd, config, inputFile)
 
 		// Run break-even analysis
 		engine := calculation.NewCalculationEngineWithConfig(config.GlobalAssumptions.FederalRules)
 		engine.HistoricalData = hdm // Set the historical data manager
+		if dataPath != "" {
+			engine.SetDataPath(dataPath)
+		}
 		debugMode, _ := cmd.Flags().GetBool("debug")
 		if debugMode {
 			engine.SetLogger(simpleCLILogger{})
@@ -212,21 +306,21 @@ var breakEvenCmd = &cobra.Command{
 		// Display results
 		fmt.Println("BREAK-EVEN TSP WITHDRAWAL RATE ANALYSIS")
 		fmt.Println("========================================")
-		fmt.Printf("Target Net Income (Current): $%s\n\n", analysis.TargetNetIncome.StringFixed(2))
+		fmt.Printf("Target Net Income (Current): $%s\n\n", domain.FormatAmount(analysis.TargetNetIncome))
 
 		for _, result := range analysis.Results {
 			fmt.Printf("SCENARIO: %s\n", result.ScenarioName)
 			fmt.Println(strings.Repeat("-", 50))
-			fmt.Printf("Break-Even TSP Withdrawal Rate: %s%%\n", result.BreakEvenWithdrawalRate.Mul(decimal.NewFromInt(100)).StringFixed(2))
+			fmt.Printf("Break-Even TSP Withdrawal Rate: %s\n", domain.FormatRate(result.BreakEvenWithdrawalRate))
 			fmt.Printf("Analysis Year: %d (first full retirement year)\n", result.ProjectedYear)
-			fmt.Printf("Projected Net Income: $%s\n", result.ProjectedNetIncome.StringFixed(2))
-			fmt.Printf("Total TSP Withdrawal: $%s\n", result.TSPWithdrawalAmount.StringFixed(2))
-			fmt.Printf("Remaining TSP Balance: $%s\n", result.TotalTSPBalance.StringFixed(2))
+			fmt.Printf("Projected Net Income: $%s\n", domain.FormatAmount(result.ProjectedNetIncome))
+			fmt.Printf("Total TSP Withdrawal: $%s\n", domain.FormatAmount(result.TSPWithdrawalAmount))
+			fmt.Printf("Remaining TSP Balance: $%s\n", domain.FormatAmount(result.TotalTSPBalance))
 			diff := result.CurrentVsBreakEvenDiff
 			if diff.Abs().LessThan(decimal.NewFromInt(1000)) {
-				fmt.Printf("Income Match: Within $1,000 (difference: $%s)\n", diff.StringFixed(2))
+				fmt.Printf("Income Match: Within $1,000 (difference: $%s)\n", domain.FormatAmount(diff))
 			} else {
-				fmt.Printf("Income Difference: $%s\n", diff.StringFixed(2))
+				fmt.Printf("Income Difference: $%s\n", domain.FormatAmount(diff))
 			}
 			fmt.Println()
 		}
@@ -375,13 +469,9 @@ Examples:
 			fmt.Print(output)
 
 		case "html":
-			formatter := &output.HTMLFormatter{}
-			scenarioComparison := comparisonSet.ToScenarioComparison()
-			output, err := formatter.Format(scenarioComparison)
-			if err != nil {
+			if err := streamHTMLReport(output.HTMLFormatter{}, comparisonSet.ToScenarioComparison()); err != nil {
 				log.Fatalf("Failed to format HTML: %v", err)
 			}
-			fmt.Print(string(output))
 
 		case "table", "console", "":
 			formatter := &compare.TableFormatter{}
@@ -592,11 +682,76 @@ Examples:
 
 // Monte Carlo command removed (legacy)
 
+// htmlFormatterFromFlags builds an HTML formatter from the guardrail flags.
+func htmlFormatterFromFlags(collapseAfter int, maxSizeMB float64) output.HTMLFormatter {
+	f := output.HTMLFormatter{CollapseAfterYears: collapseAfter}
+	if maxSizeMB < 0 {
+		f.SizeWarningBytes = -1
+	} else if maxSizeMB > 0 {
+		f.SizeWarningBytes = int64(maxSizeMB * (1 << 20))
+	}
+	return f
+}
+
+// printConfigWarnings reports values the parser accepted but suspects were mistyped,
+// and configuration values that regulatory.yaml overrode or was overridden by
+func printConfigWarnings(parser *config.InputParser) {
+	for _, warning := range parser.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+// printTimings writes a table of time spent per scenario and subsystem. Taxes and
+// healthcare run inside the projection; the rest of the total is summary analysis.
+func printTimings(w io.Writer, timings []calculation.ScenarioTiming) {
+	ms := func(d time.Duration) string { return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond)) }
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Scenario\tTotal\tProjection\tTaxes\tHealthcare")
+	var sum calculation.ScenarioTiming
+	for _, t := range timings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Scenario, ms(t.Total), ms(t.Projection), ms(t.Taxes), ms(t.Healthcare))
+		sum.Total += t.Total
+		sum.Projection += t.Projection
+		sum.Taxes += t.Taxes
+		sum.Healthcare += t.Healthcare
+	}
+	fmt.Fprintf(tw, "All scenarios\t%s\t%s\t%s\t%s\n", ms(sum.Total), ms(sum.Projection), ms(sum.Taxes), ms(sum.Healthcare))
+	tw.Flush()
+}
+
+// streamHTMLReport renders the HTML report straight to stdout, reporting progress
+// and size guardrail warnings on stderr so the document itself stays clean.
+func streamHTMLReport(f output.HTMLFormatter, results *domain.ScenarioComparison) error {
+	fmt.Fprintf(os.Stderr, "Rendering HTML report for %d scenario(s)...\n", len(results.Scenarios))
+	w := bufio.NewWriter(os.Stdout)
+	size, err := f.Stream(w, results)
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "HTML report complete (%.1f KB)\n", float64(size)/1024)
+	if warning := f.SizeWarning(size); warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	return nil
+}
+
 func init() {
+	rootCmd.PersistentFlags().Int32Var(&domain.PercentPrecision, "percent-precision", domain.DefaultPercentPrecision, "Decimal places shown in percentages")
+	rootCmd.PersistentFlags().StringVar(&domain.CurrencyRounding, "currency-rounding", domain.CurrencyRoundingHalfUp, "How dollar amounts are rounded to cents: half_up or bankers")
+	rootCmd.PersistentFlags().String("data-path", "", "Historical data directory (default: historical_data_path, ./data, $XDG_DATA_HOME/rpgo, $XDG_DATA_DIRS/rpgo)")
+
 	calculateCmd.Flags().StringP("format", "f", "console", "Output format (console, html, json, csv)")
 	calculateCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
 	calculateCmd.Flags().Bool("debug", false, "Enable debug output for detailed calculations")
+	calculateCmd.Flags().Bool("timings", false, "Report time spent per scenario in projection, taxes and healthcare on stderr")
 	calculateCmd.Flags().String("regulatory-config", "", "Path to regulatory config file (default: regulatory.yaml if it exists)")
+	calculateCmd.Flags().Bool("no-hooks", false, "Skip the configuration's post-run hooks")
+	calculateCmd.Flags().Bool("redact", false, "Scale every dollar amount in the report by an undisclosed random factor for sharing")
+	calculateCmd.Flags().Int("html-collapse-after", output.DefaultHTMLCollapseAfterYears, "HTML: years shown per table section before the rest collapse (negative disables)")
+	calculateCmd.Flags().Float64("html-max-size-mb", 0, "HTML: warn when the report exceeds this size in MB (0 = 5 MB default, negative disables)")
 
 	// Break-even command flags
 	breakEvenCmd.Flags().Bool("debug", false, "Enable debug output for detailed calculations")
@@ -682,6 +837,18 @@ func initHistoricalCommand() {
 			fmt.Printf("  TSP Funds: C, S, I, F, G\n")
 			fmt.Printf("  Inflation: CPI-U rates\n")
 			fmt.Printf("  COLA: Social Security rates\n")
+			if hdm.HasMonthlyData() {
+				var funds []string
+				for _, fund := range []string{"C", "S", "I", "F", "G"} {
+					if monthly := hdm.MonthlyTSPReturns[fund]; monthly != nil {
+						funds = append(funds, fmt.Sprintf("%s %d-%d", fund, monthly.MinYear, monthly.MaxYear))
+					}
+				}
+				fmt.Printf("  Monthly Returns: %s\n", strings.Join(funds, ", "))
+			}
+			if hdm.MonthlyInflation != nil {
+				fmt.Printf("  Monthly Inflation: %d-%d\n", hdm.MonthlyInflation.MinYear, hdm.MonthlyInflation.MaxYear)
+			}
 
 			// Validate data quality
 			issues, err := hdm.ValidateDataQuality()
@@ -733,10 +900,10 @@ func initHistoricalCommand() {
 				}
 				stats := dataset.Statistics
 				fmt.Printf("  %s:\n", name)
-				fmt.Printf("    Mean: %s%%\n", stats.Mean.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("    Std Dev: %s%%\n", stats.StdDev.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("    Min: %s%%\n", stats.Min.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("    Max: %s%%\n", stats.Max.Mul(decimal.NewFromInt(100)).StringFixed(3))
+				fmt.Printf("    Mean: %s\n", domain.FormatRate(stats.Mean))
+				fmt.Printf("    Std Dev: %s\n", domain.FormatRate(stats.StdDev))
+				fmt.Printf("    Min: %s\n", domain.FormatRate(stats.Min))
+				fmt.Printf("    Max: %s\n", domain.FormatRate(stats.Max))
 				fmt.Printf("    Years: %d\n", stats.Count)
 				fmt.Println()
 			}
@@ -745,10 +912,10 @@ func initHistoricalCommand() {
 			if hdm.Inflation != nil {
 				stats := hdm.Inflation.Statistics
 				fmt.Println("Inflation (CPI-U):")
-				fmt.Printf("  Mean: %s%%\n", stats.Mean.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("  Std Dev: %s%%\n", stats.StdDev.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("  Min: %s%%\n", stats.Min.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("  Max: %s%%\n", stats.Max.Mul(decimal.NewFromInt(100)).StringFixed(3))
+				fmt.Printf("  Mean: %s\n", domain.FormatRate(stats.Mean))
+				fmt.Printf("  Std Dev: %s\n", domain.FormatRate(stats.StdDev))
+				fmt.Printf("  Min: %s\n", domain.FormatRate(stats.Min))
+				fmt.Printf("  Max: %s\n", domain.FormatRate(stats.Max))
 				fmt.Printf("  Years: %d\n", stats.Count)
 				fmt.Println()
 			}
@@ -757,10 +924,10 @@ func initHistoricalCommand() {
 			if hdm.COLA != nil {
 				stats := hdm.COLA.Statistics
 				fmt.Println("Social Security COLA:")
-				fmt.Printf("  Mean: %s%%\n", stats.Mean.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("  Std Dev: %s%%\n", stats.StdDev.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("  Min: %s%%\n", stats.Min.Mul(decimal.NewFromInt(100)).StringFixed(3))
-				fmt.Printf("  Max: %s%%\n", stats.Max.Mul(decimal.NewFromInt(100)).StringFixed(3))
+				fmt.Printf("  Mean: %s\n", domain.FormatRate(stats.Mean))
+				fmt.Printf("  Std Dev: %s\n", domain.FormatRate(stats.StdDev))
+				fmt.Printf("  Min: %s\n", domain.FormatRate(stats.Min))
+				fmt.Printf("  Max: %s\n", domain.FormatRate(stats.Max))
 				fmt.Printf("  Years: %d\n", stats.Count)
 				fmt.Println()
 			}
@@ -771,7 +938,7 @@ func initHistoricalCommand() {
 	queryCmd := &cobra.Command{
 		Use:   "query [data-path] [year] [fund-type]",
 		Short: "Query specific historical data",
-		Long:  "Query specific historical data for a given year and fund type.\n\nFund types: C, S, I, F, G, inflation, cola\nExample: historical query ./data 2020 C",
+		Long:  "Query specific historical data for a given year and fund type.\n\nFund types: C, S, I, F, G, L2030-L2070, \"L Income\", inflation, cola\nExample: historical query ./data 2020 C",
 		Args:  cobra.ExactArgs(3),
 		Run: func(cmd *cobra.Command, args []string) {
 			dataPath := args[0]
@@ -800,7 +967,7 @@ func initHistoricalCommand() {
 					fmt.Printf("Error: %v\n", err)
 					os.Exit(1)
 				}
-				fmt.Printf("TSP %s Fund Return: %s%%\n", fundType, result.Mul(decimal.NewFromInt(100)).StringFixed(3))
+				fmt.Printf("TSP %s Fund Return: %s\n", fundType, domain.FormatRate(result))
 
 			case "inflation":
 				result, err := hdm.GetInflationRate(year)
@@ -808,7 +975,7 @@ func initHistoricalCommand() {
 					fmt.Printf("Error: %v\n", err)
 					os.Exit(1)
 				}
-				fmt.Printf("Inflation Rate: %s%%\n", result.Mul(decimal.NewFromInt(100)).StringFixed(3))
+				fmt.Printf("Inflation Rate: %s\n", domain.FormatRate(result))
 
 			case "cola":
 				result, err := hdm.GetCOLARate(year)
@@ -816,10 +983,19 @@ func initHistoricalCommand() {
 					fmt.Printf("Error: %v\n", err)
 					os.Exit(1)
 				}
-				fmt.Printf("COLA Rate: %s%%\n", result.Mul(decimal.NewFromInt(100)).StringFixed(3))
+				fmt.Printf("COLA Rate: %s\n", domain.FormatRate(result))
 
 			default:
-				fmt.Printf("Error: Unknown fund type '%s'. Valid types: C, S, I, F, G, inflation, cola\n", fundType)
+				if calculation.IsLifecycleFund(fundType) {
+					result, err := hdm.GetLifecycleReturn(fundType, year, year)
+					if err != nil {
+						fmt.Printf("Error: %v\n", err)
+						os.Exit(1)
+					}
+					fmt.Printf("TSP %s Fund Return (glide path allocation): %s\n", fundType, domain.FormatRate(result))
+					return
+				}
+				fmt.Printf("Error: Unknown fund type '%s'. Valid types: C, S, I, F, G, L2030-L2070, L Income, inflation, cola\n", fundType)
 				os.Exit(1)
 			}
 		},
@@ -848,6 +1024,30 @@ func initHistoricalCommand() {
 			initialBalance, _ := cmd.Flags().GetFloat64("balance")
 			annualWithdrawal, _ := cmd.Flags().GetFloat64("withdrawal")
 			withdrawalStrategy, _ := cmd.Flags().GetString("strategy")
+			blockLength, _ := cmd.Flags().GetInt("block-length")
+			monthly, _ := cmd.Flags().GetBool("monthly")
+			distribution, _ := cmd.Flags().GetString("distribution")
+			outputFormat, _ := cmd.Flags().GetString("format")
+			outputFormat = strings.ToLower(outputFormat)
+			if outputFormat != "console" && outputFormat != "json" {
+				fmt.Printf("Unknown output format %q (valid: console, json)\n", outputFormat)
+				os.Exit(1)
+			}
+			degreesOfFreedom, _ := cmd.Flags().GetInt("degrees-of-freedom")
+			switch distribution {
+			case calculation.ReturnDistributionNormal, calculation.ReturnDistributionStudentT, calculation.ReturnDistributionHistoricalResiduals:
+			default:
+				fmt.Printf("Unknown distribution %q (valid: normal, student_t, historical_residuals)\n", distribution)
+				os.Exit(1)
+			}
+			if distribution == calculation.ReturnDistributionStudentT && degreesOfFreedom <= 2 {
+				fmt.Println("Degrees of freedom must be greater than 2")
+				os.Exit(1)
+			}
+			seed, _ := cmd.Flags().GetInt64("seed")
+			if seed == 0 {
+				seed = time.Now().UnixNano()
+			}
 
 			// Default asset allocation (60% C, 20% S, 10% I, 10% F)
 			assetAllocation := map[string]decimal.Decimal{
@@ -856,13 +1056,24 @@ func initHistoricalCommand() {
 				"I": decimal.NewFromFloat(0.1),
 				"F": decimal.NewFromFloat(0.1),
 			}
+			if lfund, _ := cmd.Flags().GetString("lfund"); lfund != "" {
+				if !calculation.IsLifecycleFund(lfund) {
+					fmt.Printf("Error: Unknown lifecycle fund '%s'. Valid funds: %s\n", lfund, strings.Join(calculation.LifecycleFundNames, ", "))
+					os.Exit(1)
+				}
+				assetAllocation = map[string]decimal.Decimal{lfund: decimal.NewFromInt(1)}
+			}
 
 			// Create Monte Carlo configuration
 			config := calculation.MonteCarloConfig{
 				NumSimulations:     numSimulations,
 				ProjectionYears:    projectionYears,
-				Seed:               time.Now().UnixNano(),
+				Seed:               seed,
 				UseHistorical:      useHistorical,
+				BlockLength:        blockLength,
+				MonthlySteps:       monthly,
+				ReturnDistribution: distribution,
+				DegreesOfFreedom:   degreesOfFreedom,
 				AssetAllocation:    assetAllocation,
 				WithdrawalStrategy: withdrawalStrategy,
 				InitialBalance:     decimal.NewFromFloat(initialBalance),
@@ -877,39 +1088,75 @@ func initHistoricalCommand() {
 				os.Exit(1)
 			}
 
+			if outputFormat == "json" {
+				// Per-simulation outcomes are left out; the percentiles and yearly bands remain
+				summary := *result
+				summary.Simulations = nil
+				out, err := json.MarshalIndent(summary, "", "  ")
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(string(out))
+				return
+			}
+
 			// Display results
 			fmt.Println("🎲 MONTE CARLO SIMULATION RESULTS")
 			fmt.Println("==================================")
-			fmt.Printf("Simulations: %d\n", result.NumSimulations)
+			fmt.Printf("Simulations: %d (seed %d)\n", result.NumSimulations, result.Seed)
 			fmt.Printf("Projection Years: %d\n", result.ProjectionYears)
 			fmt.Printf("Data Source: %s\n", map[bool]string{true: "Historical", false: "Statistical"}[useHistorical])
+			if useHistorical && blockLength > 1 {
+				fmt.Printf("Block Length: %d years\n", blockLength)
+			}
+			if !useHistorical {
+				fmt.Printf("Return Distribution: %s\n", distribution)
+			}
+			if monthly {
+				steps := "Monthly"
+				if useHistorical && hdm.HasMonthlyData() {
+					steps = "Monthly (historical monthly returns where loaded)"
+				}
+				fmt.Printf("Time Step: %s\n", steps)
+			}
 			fmt.Printf("Withdrawal Strategy: %s\n", withdrawalStrategy)
-			fmt.Printf("Initial Balance: $%s\n", result.InitialBalance.StringFixed(2))
-			fmt.Printf("Annual Withdrawal: $%s\n", result.AnnualWithdrawal.StringFixed(2))
+			fmt.Printf("Initial Balance: $%s\n", domain.FormatAmount(result.InitialBalance))
+			fmt.Printf("Annual Withdrawal: $%s\n", domain.FormatAmount(result.AnnualWithdrawal))
 			fmt.Println()
 
 			// Asset allocation
 			fmt.Println("Asset Allocation:")
 			for fund, allocation := range result.AssetAllocation {
-				fmt.Printf("  %s Fund: %s%%\n", fund, allocation.Mul(decimal.NewFromInt(100)).StringFixed(1))
+				fmt.Printf("  %s Fund: %s\n", fund, domain.FormatRate(allocation))
 			}
 			fmt.Println()
 
 			// Success metrics
 			fmt.Println("Success Metrics:")
-			fmt.Printf("  Success Rate: %s%%\n", result.SuccessRate.Mul(decimal.NewFromInt(100)).StringFixed(2))
-			fmt.Printf("  Median Ending Balance: $%s\n", result.MedianEndingBalance.StringFixed(2))
+			fmt.Printf("  Success Rate: %s (±%s, 95%% confidence)\n", domain.FormatRate(result.SuccessRate), domain.FormatRate(result.SuccessRateMargin))
+			fmt.Printf("  Median Ending Balance: $%s\n", domain.FormatAmount(result.MedianEndingBalance))
 			fmt.Println()
 
 			// Percentile ranges
 			fmt.Println("Ending Balance Percentiles:")
-			fmt.Printf("  10th Percentile: $%s\n", result.PercentileRanges.P10.StringFixed(2))
-			fmt.Printf("  25th Percentile: $%s\n", result.PercentileRanges.P25.StringFixed(2))
-			fmt.Printf("  50th Percentile: $%s\n", result.PercentileRanges.P50.StringFixed(2))
-			fmt.Printf("  75th Percentile: $%s\n", result.PercentileRanges.P75.StringFixed(2))
-			fmt.Printf("  90th Percentile: $%s\n", result.PercentileRanges.P90.StringFixed(2))
+			fmt.Printf("  10th Percentile: $%s\n", domain.FormatAmount(result.PercentileRanges.P10))
+			fmt.Printf("  25th Percentile: $%s\n", domain.FormatAmount(result.PercentileRanges.P25))
+			fmt.Printf("  50th Percentile: $%s\n", domain.FormatAmount(result.PercentileRanges.P50))
+			fmt.Printf("  75th Percentile: $%s\n", domain.FormatAmount(result.PercentileRanges.P75))
+			fmt.Printf("  90th Percentile: $%s\n", domain.FormatAmount(result.PercentileRanges.P90))
 			fmt.Println()
 
+			// Historical path behind the P10 outcome, replayable with --seed
+			if useHistorical {
+				if i := result.NearestSimulation(result.PercentileRanges.P10); i >= 0 {
+					fmt.Println("10th Percentile Historical Path:")
+					fmt.Printf("  Simulation: %d (seed %d)\n", i, result.Seed)
+					fmt.Printf("  Years: %v\n", result.Simulations[i].HistoricalPath)
+					fmt.Println()
+				}
+			}
+
 			// Risk assessment
 			fmt.Println("Risk Assessment:")
 			riskLevel, _ := calculateRiskLevel(result.SuccessRate)
@@ -937,28 +1184,43 @@ func initHistoricalCommand() {
 	monteCarloCmd.Flags().BoolP("historical", "d", true, "Use historical data (false for statistical)")
 	monteCarloCmd.Flags().Float64P("balance", "b", 1000000, "Initial portfolio balance")
 	monteCarloCmd.Flags().Float64P("withdrawal", "w", 40000, "Annual withdrawal amount (or percentage as decimal for fixed_percentage strategy, e.g., 0.04 for 4%)")
+	monteCarloCmd.Flags().String("lfund", "", "Invest the whole balance in a TSP Lifecycle fund (L2030-L2070 or \"L Income\") whose allocation glides each year")
+	monteCarloCmd.Flags().Int64("seed", 0, "Random seed for reproducible simulations (0 uses the current time)")
+	monteCarloCmd.Flags().StringP("format", "f", "console", "Output format (console, json); json includes per-year balance and withdrawal percentiles")
+	monteCarloCmd.Flags().String("distribution", "normal", "Distribution of statistical draws (with --historical=false): normal, student_t, or historical_residuals")
+	monteCarloCmd.Flags().Int("degrees-of-freedom", 5, "Degrees of freedom of student_t draws (greater than 2; lower means fatter tails)")
+	monteCarloCmd.Flags().Int("block-length", 1, "Consecutive historical years per draw (block bootstrap); 1 samples each year independently")
+	monteCarloCmd.Flags().Bool("monthly", false, "Step each year month by month, withdrawing monthly; uses monthly fund returns from the data when present")
 	monteCarloCmd.Flags().StringP("strategy", "t", "fixed_amount", "Withdrawal strategy: fixed_amount (constant $), fixed_percentage (% of balance), inflation_adjusted ($ + inflation), guardrails (dynamic)")
 
 	historicalCmd.AddCommand(loadCmd)
 	historicalCmd.AddCommand(statsCmd)
 	historicalCmd.AddCommand(queryCmd)
 	historicalCmd.AddCommand(monteCarloCmd)
+	historicalCmd.AddCommand(historicalRollingCmd)
+	historicalCmd.AddCommand(historicalFetchCmd)
 	rootCmd.AddCommand(historicalCmd)
 
-	// FERS Monte Carlo command
+	// FERS Monte Carlo command (formerly fers-monte-carlo)
 	fersMonteCarloCmd := &cobra.Command{
-		Use:   "fers-monte-carlo [input-file]",
-		Short: "Run comprehensive FERS Monte Carlo simulations",
+		Use:     "monte-carlo [input-file]",
+		Aliases: []string{"fers-monte-carlo"},
+		Short:   "Run comprehensive FERS Monte Carlo simulations",
 		Long: `Run comprehensive FERS Monte Carlo simulations that model market variability
 across all retirement components including TSP returns, inflation, COLA, and FEHB premiums.
 
-This command integrates with the full FERS retirement planning engine to provide
-probabilistic analysis of retirement scenarios.
+This command runs the full household through the FERS retirement planning engine on each
+simulated market path and reports the success rate, percentile lifetime income and TSP
+longevity, and the probability that MAGI crosses an IRMAA threshold.
 
 Examples:
-  ./rpgo fers-monte-carlo config.yaml --scenario "Both Retire in 2025" --simulations 1000
-  ./rpgo fers-monte-carlo config.yaml --scenario "Base" --simulations 5000 --historical
-  ./rpgo fers-monte-carlo config.yaml --scenario "Base" --simulations 1000 --output html`,
+  ./rpgo monte-carlo config.yaml --scenario "Both Retire in 2025" --simulations 1000
+  ./rpgo monte-carlo config.yaml --scenario "Base" --simulations 5000 --historical
+  ./rpgo monte-carlo config.yaml --scenario "Base" --simulations 1000 --output html
+  ./rpgo monte-carlo config.yaml --scenario "Base" --compare "Delay SS" --seed 42
+
+With --compare, every listed scenario is run against the same simulated market paths as
+--scenario (common random numbers) and reported as path-by-path differences from it.`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			inputFile := args[0]
@@ -996,6 +1258,8 @@ Examples:
 			scenarioName, _ := cmd.Flags().GetString("scenario")
 			useHistorical, _ := cmd.Flags().GetBool("historical")
 			outputFormat, _ := cmd.Flags().GetString("format")
+			simulations, _ := cmd.Flags().GetInt("simulations")
+			compareNames, _ := cmd.Flags().GetStringSlice("compare")
 
 			if scenarioName == "" {
 				log.Fatal("--scenario flag is required")
@@ -1004,24 +1268,52 @@ Examples:
 			// Load historical data if requested
 			var historicalData *calculation.HistoricalDataManager
 			if useHistorical {
-				dataPath, _ := cmd.Flags().GetString("data-path")
-				if dataPath == "" {
-					dataPath = "./data" // Default data path
-				}
-				historicalData = calculation.NewHistoricalDataManager(dataPath)
-				if err := historicalData.LoadAllData(); err != nil {
-					fmt.Printf("Warning: Failed to load historical data: %v\n", err)
-					fmt.Printf("Falling back to statistical distributions...\n")
-					historicalData = nil
-				}
+				historicalData, _ = loadHistoricalData(cmd, configData, inputFile)
 			}
 
 			// Create FERS Monte Carlo engine
 			engine := calculation.NewFERSMonteCarloEngine(configData, historicalData)
-
-			// Run simulation
-			ctx := context.Background()
+			engine.SetSimulations(simulations)
+			if cmd.Flags().Changed("seed") {
+				seed, _ := cmd.Flags().GetInt64("seed")
+				engine.SetSeed(seed)
+			}
+			if cmd.Flags().Changed("block-length") {
+				blockLength, _ := cmd.Flags().GetInt("block-length")
+				engine.SetBlockLength(blockLength)
+			}
+			workers, _ := cmd.Flags().GetInt("workers")
+			engine.SetWorkers(workers)
+			tolerance, _ := cmd.Flags().GetFloat64("tolerance")
+			maxSimulations, _ := cmd.Flags().GetInt("max-simulations")
+			if tolerance < 0 || tolerance >= 1 {
+				log.Fatalf("--tolerance must be between 0 and 1, got %v", tolerance)
+			}
+			if tolerance > 0 && len(compareNames) > 0 {
+				log.Fatal("--tolerance is not supported with --compare")
+			}
+			engine.SetTolerance(decimal.NewFromFloat(tolerance), maxSimulations)
+			engine.SetProgress(terminalProgress())
+
+			// Run simulation; Ctrl-C stops it between simulations
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			if len(compareNames) > 0 {
+				comparison, err := engine.RunFERSMonteCarloComparison(ctx, append([]string{scenarioName}, compareNames...))
+				exitIfCancelled(err)
+				if err != nil {
+					log.Fatalf("FERS Monte Carlo comparison failed: %v", err)
+				}
+				switch strings.ToLower(outputFormat) {
+				case "table", "console", "":
+					printFERSMonteCarloComparison(comparison)
+				default:
+					log.Fatalf("Output format %s is not supported with --compare (valid: table)", outputFormat)
+				}
+				return
+			}
 			result, err := engine.RunFERSMonteCarlo(ctx, scenarioName)
+			exitIfCancelled(err)
 			if err != nil {
 				log.Fatalf("FERS Monte Carlo simulation failed: %v", err)
 			}
@@ -1029,8 +1321,16 @@ Examples:
 			// Format and output results
 			switch strings.ToLower(outputFormat) {
 			case "json":
-				// TODO: Implement JSON formatter for FERS Monte Carlo results
-				fmt.Printf("JSON output not yet implemented for FERS Monte Carlo\n")
+				// Per-simulation projections are left out; the percentiles and yearly bands remain
+				summary := *result
+				summary.Simulations = nil
+				summary.MarketConditions = nil
+				out, err := json.MarshalIndent(summary, "", "  ")
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(string(out))
 
 			case "html":
 				// TODO: Implement HTML formatter for FERS Monte Carlo results
@@ -1041,11 +1341,15 @@ Examples:
 				fmt.Printf("🎲 FERS MONTE CARLO SIMULATION RESULTS\n")
 				fmt.Printf("=====================================\n\n")
 				fmt.Printf("Base Scenario: %s\n", result.BaseScenarioName)
-				fmt.Printf("Simulations: %d\n", result.NumSimulations)
+				fmt.Printf("Simulations: %d (seed %d)\n", result.NumSimulations, result.Seed)
 				fmt.Printf("Projection Years: %d\n", result.ProjectionYears)
-				fmt.Printf("Success Rate: %.1f%%\n", result.SuccessRate.Mul(decimal.NewFromInt(100)).InexactFloat64())
+				fmt.Printf("Success Rate: %s (±%s, 95%% confidence)\n", domain.FormatRate(result.SuccessRate), domain.FormatRate(result.SuccessRateMargin))
+				if tolerance > 0 && result.SuccessRateMargin.GreaterThan(decimal.NewFromFloat(tolerance)) {
+					fmt.Printf("⚠️  The success rate did not reach ±%s within %d simulations\n", domain.FormatRate(decimal.NewFromFloat(tolerance)), result.NumSimulations)
+				}
 				fmt.Printf("Median Lifetime Income: $%.0f\n", result.MedianLifetimeIncome.InexactFloat64())
 				fmt.Printf("Median TSP Longevity: %d years\n", result.MedianTSPLongevity)
+				fmt.Printf("IRMAA Breach Probability: %s\n", domain.FormatRate(result.IRMAABreachProbability))
 
 				fmt.Printf("\n📊 PERCENTILE RANGES\n")
 				fmt.Printf("===================\n")
@@ -1087,8 +1391,13 @@ Examples:
 	fersMonteCarloCmd.Flags().String("scenario", "", "Base scenario name for Monte Carlo simulation (required)")
 	fersMonteCarloCmd.Flags().IntP("simulations", "s", 1000, "Number of simulations to run")
 	fersMonteCarloCmd.Flags().Bool("historical", true, "Use historical data (false for statistical distributions)")
-	fersMonteCarloCmd.Flags().String("data-path", "./data", "Path to historical data directory")
 	fersMonteCarloCmd.Flags().StringP("format", "f", "table", "Output format (table, json, html)")
+	fersMonteCarloCmd.Flags().StringSlice("compare", nil, "Scenarios to run on the same market paths as --scenario and compare against it")
+	fersMonteCarloCmd.Flags().Int64("seed", 0, "Seed for the simulated market paths (default: time-based)")
+	fersMonteCarloCmd.Flags().Float64("tolerance", 0, "Add simulations until the success rate's 95% confidence interval is within ± this fraction, e.g. 0.01 (default: run exactly --simulations)")
+	fersMonteCarloCmd.Flags().Int("max-simulations", 0, "Most simulations a --tolerance run may reach (default: 50000)")
+	fersMonteCarloCmd.Flags().Int("workers", 0, "Number of simulations to run at once (default: one per CPU)")
+	fersMonteCarloCmd.Flags().Int("block-length", 0, "Follow a historical path drawn in blocks of this many consecutive years (default: monte_carlo_settings.block_length, or one year held for the whole run)")
 	fersMonteCarloCmd.Flags().String("regulatory-config", "", "Path to regulatory config file (default: regulatory.yaml if it exists)")
 
 	rootCmd.AddCommand(fersMonteCarloCmd)
@@ -1096,6 +1405,55 @@ Examples:
 
 // Helper functions for FERS Monte Carlo
 
+// terminalProgress returns a progress callback that redraws a progress bar on stderr,
+// or nil when stderr is not a terminal so redirected output stays clean
+func terminalProgress() calculation.MonteCarloProgress {
+	if info, err := os.Stderr.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	const width = 30
+	return func(completed, total int) {
+		filled := width * completed / total
+		fmt.Fprintf(os.Stderr, "\r[%s%s] %d/%d simulations", strings.Repeat("█", filled), strings.Repeat("░", width-filled), completed, total)
+		if completed == total {
+			fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", width+40))
+		}
+	}
+}
+
+// exitIfCancelled exits quietly when a Monte Carlo run was interrupted
+func exitIfCancelled(err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "\nMonte Carlo simulation cancelled")
+		os.Exit(130)
+	}
+}
+
+// printFERSMonteCarloComparison prints scenarios run on shared market paths and their
+// paired differences from the first scenario
+func printFERSMonteCarloComparison(comparison *calculation.FERSMonteCarloComparison) {
+	fmt.Printf("🎲 FERS MONTE CARLO COMPARISON (COMMON MARKET PATHS)\n")
+	fmt.Printf("===================================================\n\n")
+	fmt.Printf("Simulations: %d (seed %d)\n\n", comparison.NumSimulations, comparison.Seed)
+
+	fmt.Printf("%-30s %12s %18s %14s\n", "Scenario", "Success", "Median Lifetime", "Median TSP")
+	for _, result := range comparison.Results {
+		fmt.Printf("%-30s %12s %18s %11d yr\n", result.BaseScenarioName,
+			domain.FormatRate(result.SuccessRate),
+			"$"+result.MedianLifetimeIncome.StringFixed(0), result.MedianTSPLongevity)
+	}
+
+	for _, diff := range comparison.Differences {
+		fmt.Printf("\n📊 %s vs %s (%d paired paths)\n", diff.ScenarioName, diff.BaselineName, diff.PairedPaths)
+		fmt.Printf("  Mean lifetime income difference:   $%.0f\n", diff.MeanLifetimeIncomeDiff.InexactFloat64())
+		fmt.Printf("  Median lifetime income difference: $%.0f\n", diff.MedianLifetimeIncomeDiff.InexactFloat64())
+		fmt.Printf("  10th-90th percentile difference:   $%.0f to $%.0f\n",
+			diff.LifetimeIncomeDiff["10th"].InexactFloat64(), diff.LifetimeIncomeDiff["90th"].InexactFloat64())
+		fmt.Printf("  Better on:                         %s of paths\n", domain.FormatRate(diff.WinRate))
+		fmt.Printf("  Success rate difference:           %+.1f points\n", diff.SuccessRateDiff.Mul(decimal.NewFromInt(100)).InexactFloat64())
+	}
+}
+
 func calculateRiskLevel(successRate decimal.Decimal) (string, string) {
 	if successRate.GreaterThanOrEqual(decimal.NewFromFloat(0.95)) {
 		return "🟢 LOW RISK", "95%+ success rate indicates sustainable retirement plan"
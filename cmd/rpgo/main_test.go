@@ -78,7 +78,7 @@ func TestCommandSubcommands(t *testing.T) {
 		"optimize",
 		"plan-roth",
 		"analyze-survivor",
-		"fers-monte-carlo",
+		"monte-carlo",
 	}
 
 	cmd := rootCmd.Commands()
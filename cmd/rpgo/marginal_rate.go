@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/internal/output"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var marginalRateCmd = &cobra.Command{
+	Use:   "marginal-rate [input-file]",
+	Short: "Chart the effective marginal rate on added ordinary income for one year",
+	Long: `Sweep additional ordinary income (a Roth conversion, for example) from $0 up to
+a maximum in fixed steps for one projection year, and report what each step costs in
+federal, state and local tax plus IRMAA surcharges.
+
+The curve shows where Social Security taxation, bracket changes and IRMAA cliffs push
+the effective marginal rate above the bracket rate, which is what matters when sizing
+conversions.
+
+Examples:
+  # Chart the first scenario in 2030, $0-$200k in $1k steps
+  ./rpgo marginal-rate config.yaml --year 2030
+
+  # CSV for a named scenario in $500 steps up to $100k
+  ./rpgo marginal-rate config.yaml --scenario "Retire 2027" --year 2030 --max 100000 --step 500 --format csv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		year, _ := cmd.Flags().GetInt("year")
+		maxIncome, _ := cmd.Flags().GetInt64("max")
+		step, _ := cmd.Flags().GetInt64("step")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		if year == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --year is required\n")
+			os.Exit(1)
+		}
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Find scenario (defaults to the first one)
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		scenario := &cfg.Scenarios[0]
+		if scenarioName != "" {
+			scenario = nil
+			for i := range cfg.Scenarios {
+				if cfg.Scenarios[i].Name == scenarioName {
+					scenario = &cfg.Scenarios[i]
+					break
+				}
+			}
+			if scenario == nil {
+				fmt.Fprintf(os.Stderr, "Error: Scenario '%s' not found\n", scenarioName)
+				os.Exit(1)
+			}
+		}
+
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		curve, err := engine.MarginalRateCurve(context.Background(), cfg, scenario, year, decimal.NewFromInt(maxIncome), decimal.NewFromInt(step))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating marginal rate curve: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "csv":
+			out, err := output.FormatMarginalRateCSV(curve)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(string(out))
+		case "json":
+			out, err := json.MarshalIndent(curve, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			fmt.Print(output.FormatMarginalRateChart(curve))
+		}
+	},
+}
+
+func init() {
+	marginalRateCmd.Flags().StringP("scenario", "s", "", "Scenario to sweep (default: first scenario)")
+	marginalRateCmd.Flags().IntP("year", "y", 0, "Calendar year to add income to (required)")
+	marginalRateCmd.Flags().Int64("max", 200000, "Maximum additional ordinary income")
+	marginalRateCmd.Flags().Int64("step", 1000, "Income step")
+	marginalRateCmd.Flags().StringP("format", "f", "chart", "Output format (chart, csv, json)")
+	marginalRateCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+
+	rootCmd.AddCommand(marginalRateCmd)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var medicareCoverageCmd = &cobra.Command{
+	Use:   "medicare-coverage [input-file]",
+	Short: "Compare keeping FEHB alongside Medicare or dropping to Medigap and Part D",
+	Long: `Compare a participant keeping FEHB as secondary coverage alongside Medicare
+Part B against dropping FEHB at Medicare for a Medigap plan and Part D.
+
+Both coverages are projected for the scenario, with the premium differential and
+each coverage's out-of-pocket assumptions (healthcare.out_of_pocket_fehb and
+healthcare.out_of_pocket_medigap) carried over the projection.
+
+Examples:
+  # Compare the coverages for the first scenario
+  ./rpgo medicare-coverage config.yaml --participant Robert
+
+  # A named scenario, as JSON
+  ./rpgo medicare-coverage config.yaml --scenario "Retire 2027" --participant Robert --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		participantName, _ := cmd.Flags().GetString("participant")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		if scenarioName == "" {
+			scenarioName = cfg.Scenarios[0].Name
+		}
+		if participantName == "" {
+			// Default to the first participant enrolled in FEHB
+			for _, p := range cfg.Household.Participants {
+				if p.FEHBPremiumPerPayPeriod != nil && p.FEHBPremiumPerPayPeriod.IsPositive() {
+					participantName = p.Name
+					break
+				}
+			}
+		}
+
+		coverageConfig, err := calculation.MedicareCoverageConfig(cfg, scenarioName, participantName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		comparison, err := engine.CompareMedicareCoverage(context.Background(), coverageConfig, participantName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing Medicare coverage: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "json":
+			out, err := json.MarshalIndent(comparison, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			printMedicareCoverage(comparison)
+		}
+	},
+}
+
+// printMedicareCoverage prints the lifetime costs and income under each coverage
+func printMedicareCoverage(comparison *calculation.MedicareCoverageComparison) {
+	dollars := func(d decimal.Decimal) string { return "$" + d.StringFixed(0) }
+
+	fmt.Printf("MEDICARE COVERAGE: FEHB VS MEDIGAP (%s)\n", comparison.ParticipantName)
+	fmt.Printf("=================================================\n\n")
+	fmt.Printf("Lifetime (household)  %18s %18s\n", "FEHB + Medicare", "Medigap + Part D")
+	fmt.Printf("Premiums              %18s %18s\n", dollars(comparison.FEHBPremiums), dollars(comparison.MedigapPremiums))
+	fmt.Printf("Out-of-pocket costs   %18s %18s\n", dollars(comparison.FEHBOutOfPocket), dollars(comparison.MedigapOutOfPocket))
+	fmt.Printf("Net income            %18s %18s\n",
+		dollars(comparison.FEHBSecondary.TotalLifetimeIncome), dollars(comparison.Medigap.TotalLifetimeIncome))
+	fmt.Printf("\nMedigap minus FEHB lifetime net income: $%.0f\n", comparison.LifetimeIncomeDifference.InexactFloat64())
+}
+
+func init() {
+	medicareCoverageCmd.Flags().StringP("scenario", "s", "", "Scenario to compare the coverages in (default: first scenario)")
+	medicareCoverageCmd.Flags().StringP("participant", "p", "", "Participant choosing the coverage (default: the first enrolled in FEHB)")
+	medicareCoverageCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	medicareCoverageCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+
+	rootCmd.AddCommand(medicareCoverageCmd)
+}
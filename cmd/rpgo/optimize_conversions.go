@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/internal/output"
+	"github.com/rgehrsitz/rpgo/pkg/dateutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var optimizeConversionsCmd = &cobra.Command{
+	Use:   "optimize-conversions [input-file]",
+	Short: "Search multi-year Roth conversion schedules for the best outcome",
+	Long: `Search schedules of Roth conversions from a participant's traditional TSP, one
+amount per year from the first projection year through the horizon, for the one that
+maximizes ending after-tax wealth or minimizes lifetime tax.
+
+Each year converts at most the room left below the top of the target bracket, and in
+Medicare years stops short of the next IRMAA tier. After-tax wealth is lifetime net
+income plus the ending Roth and taxable balances and the ending traditional balances
+less the terminal tax rate (default: the target bracket rate). The recommended schedule
+is printed as a roth_conversions block for the participant's scenario; conversions the
+scenario already has are replaced.
+
+Examples:
+  # Fill the 22% bracket through 2040 for the first federal participant
+  ./rpgo optimize-conversions config.yaml --target-bracket 22 --horizon 2040
+
+  # Minimize lifetime tax instead, and print only the YAML block
+  ./rpgo optimize-conversions config.yaml --target-bracket 24 --goal lifetime_tax --format yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		participant, _ := cmd.Flags().GetString("participant")
+		targetBracket, _ := cmd.Flags().GetInt("target-bracket")
+		horizon, _ := cmd.Flags().GetInt("horizon")
+		goal, _ := cmd.Flags().GetString("goal")
+		terminalRate, _ := cmd.Flags().GetString("terminal-tax-rate")
+		step, _ := cmd.Flags().GetInt64("step")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Find scenario (defaults to the first one)
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		scenario := &cfg.Scenarios[0]
+		if scenarioName != "" {
+			scenario = nil
+			for i := range cfg.Scenarios {
+				if cfg.Scenarios[i].Name == scenarioName {
+					scenario = &cfg.Scenarios[i]
+					break
+				}
+			}
+			if scenario == nil {
+				fmt.Fprintf(os.Stderr, "Error: Scenario '%s' not found\n", scenarioName)
+				os.Exit(1)
+			}
+		}
+
+		if participant == "" {
+			participant = autoDetectParticipant(cfg)
+			if participant == "" {
+				fmt.Fprintf(os.Stderr, "Error: No participant specified and none found in configuration\n")
+				os.Exit(1)
+			}
+		}
+		var p *domain.Participant
+		for i := range cfg.Household.Participants {
+			if cfg.Household.Participants[i].Name == participant {
+				p = &cfg.Household.Participants[i]
+				break
+			}
+		}
+		if p == nil {
+			fmt.Fprintf(os.Stderr, "Error: Participant '%s' not found\n", participant)
+			os.Exit(1)
+		}
+		if horizon == 0 {
+			// Convert until the year before RMDs begin
+			horizon = p.BirthDate.Year() + dateutil.GetRMDAge(p.BirthDate.Year()) - 1
+		}
+
+		opts := domain.ConversionOptimizerOptions{
+			Participant:     participant,
+			TargetBracket:   targetBracket,
+			Horizon:         horizon,
+			Goal:            goal,
+			Step:            decimal.NewFromInt(step),
+			TerminalTaxRate: decimal.New(int64(targetBracket), -2),
+		}
+		if terminalRate != "" {
+			if opts.TerminalTaxRate, err = decimal.NewFromString(terminalRate); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid terminal tax rate %q\n", terminalRate)
+				os.Exit(1)
+			}
+		}
+
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		result, err := engine.OptimizeRothConversions(context.Background(), cfg, scenario, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error optimizing Roth conversions: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "yaml":
+			fmt.Print(output.FormatRothConversionsYAML(result.Schedule))
+		case "json":
+			out, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			fmt.Print(output.FormatConversionOptimization(result))
+		}
+	},
+}
+
+func init() {
+	optimizeConversionsCmd.Flags().StringP("scenario", "s", "", "Scenario to optimize (default: first scenario)")
+	optimizeConversionsCmd.Flags().StringP("participant", "p", "", "Participant who converts (default: first federal participant)")
+	optimizeConversionsCmd.Flags().IntP("target-bracket", "b", 22, "Highest federal bracket conversions may fill (10, 12, 22, 24, 32, 35, 37)")
+	optimizeConversionsCmd.Flags().Int("horizon", 0, "Last calendar year to convert in (default: the year before RMDs begin)")
+	optimizeConversionsCmd.Flags().String("goal", domain.ConversionGoalAfterTaxWealth, "What to optimize (after_tax_wealth, lifetime_tax)")
+	optimizeConversionsCmd.Flags().String("terminal-tax-rate", "", "Tax rate on traditional balances left at the end, e.g. 0.22 (default: the target bracket rate)")
+	optimizeConversionsCmd.Flags().Int64("step", 1000, "Round conversions down to multiples of this amount")
+	optimizeConversionsCmd.Flags().StringP("format", "f", "table", "Output format (table, yaml, json)")
+	optimizeConversionsCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+
+	rootCmd.AddCommand(optimizeConversionsCmd)
+}
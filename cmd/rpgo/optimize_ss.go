@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/breakeven"
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var optimizeSSCmd = &cobra.Command{
+	Use:   "optimize-ss [input-file]",
+	Short: "Find the best Social Security claiming ages for the household",
+	Long: `Sweep Social Security claiming ages for each participant, individually and
+jointly, and rank the strategies by lifetime net income (present value).
+
+Each strategy also reports its break-even point against claiming at the minimum
+age: the first year cumulative net income catches up after trailing.
+
+Examples:
+  # Sweep ages 62-70 for the first scenario
+  ./rpgo optimize-ss config.yaml
+
+  # Restrict the sweep and emit JSON
+  ./rpgo optimize-ss config.yaml --scenario "Retire 2027" --min-age 64 --max-age 70 --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		minAge, _ := cmd.Flags().GetInt("min-age")
+		maxAge, _ := cmd.Flags().GetInt("max-age")
+		format, _ := cmd.Flags().GetString("format")
+		debug, _ := cmd.Flags().GetBool("debug")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Find scenario (defaults to the first one)
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		scenario := &cfg.Scenarios[0]
+		if scenarioName != "" {
+			scenario = nil
+			for i := range cfg.Scenarios {
+				if cfg.Scenarios[i].Name == scenarioName {
+					scenario = &cfg.Scenarios[i]
+					break
+				}
+			}
+			if scenario == nil {
+				fmt.Fprintf(os.Stderr, "Error: Scenario '%s' not found\n", scenarioName)
+				os.Exit(1)
+			}
+		}
+
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		if debug {
+			engine.SetLogger(simpleCLILogger{})
+			engine.Debug = true
+		}
+		solver := breakeven.NewDefaultSolver(engine)
+
+		result, err := solver.OptimizeSSClaiming(context.Background(), scenario, cfg, minAge, maxAge)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error optimizing Social Security claiming: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "json":
+			formatter := &breakeven.JSONFormatter{Pretty: true}
+			out, err := formatter.FormatSSClaiming(result)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(out)
+		default:
+			formatter := &breakeven.TableFormatter{}
+			fmt.Print(formatter.FormatSSClaiming(result))
+		}
+	},
+}
+
+func init() {
+	optimizeSSCmd.Flags().StringP("scenario", "s", "", "Scenario to optimize (default: first scenario)")
+	optimizeSSCmd.Flags().Int("min-age", 62, "Minimum Social Security claiming age")
+	optimizeSSCmd.Flags().Int("max-age", 70, "Maximum Social Security claiming age")
+	optimizeSSCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	optimizeSSCmd.Flags().BoolP("debug", "d", false, "Enable debug output")
+	optimizeSSCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+
+	rootCmd.AddCommand(optimizeSSCmd)
+}
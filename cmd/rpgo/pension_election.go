@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var pensionElectionCmd = &cobra.Command{
+	Use:   "pension-election [input-file]",
+	Short: "Compare taking an external pension as a lump sum or an annuity",
+	Long: `Compare a participant's external pension taken as the plan's lump-sum offer,
+rolled over to the IRA or TSP, against the monthly annuity.
+
+Both elections are projected for the scenario, then run on the same simulated
+market paths so the comparison shows how often, and by how much, the lump sum
+comes out ahead.
+
+Examples:
+  # Compare the elections for the first scenario
+  ./rpgo pension-election config.yaml --participant Dawn
+
+  # More simulations with a fixed seed, as JSON
+  ./rpgo pension-election config.yaml --scenario "Retire 2027" --participant Dawn --simulations 5000 --seed 42 --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		participantName, _ := cmd.Flags().GetString("participant")
+		simulations, _ := cmd.Flags().GetInt("simulations")
+		useHistorical, _ := cmd.Flags().GetBool("historical")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		if scenarioName == "" {
+			scenarioName = cfg.Scenarios[0].Name
+		}
+		if participantName == "" {
+			// Default to the participant offered a lump sum
+			for _, p := range cfg.Household.Participants {
+				if p.ExternalPension != nil && p.ExternalPension.LumpSum.IsPositive() {
+					participantName = p.Name
+					break
+				}
+			}
+		}
+
+		electionConfig, err := calculation.PensionElectionConfig(cfg, scenarioName, participantName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		comparison, err := engine.ComparePensionElection(ctx, electionConfig, participantName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing pension elections: %v\n", err)
+			os.Exit(1)
+		}
+
+		if simulations > 0 {
+			var historicalData *calculation.HistoricalDataManager
+			if useHistorical {
+				historicalData, _ = loadHistoricalData(cmd, cfg, inputFile)
+			}
+			mc := calculation.NewFERSMonteCarloEngine(electionConfig, historicalData)
+			mc.SetSimulations(simulations)
+			if cmd.Flags().Changed("seed") {
+				seed, _ := cmd.Flags().GetInt64("seed")
+				mc.SetSeed(seed)
+			}
+			mc.SetProgress(terminalProgress())
+			names := []string{electionConfig.Scenarios[0].Name, electionConfig.Scenarios[1].Name}
+			mcCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			comparison.MonteCarlo, err = mc.RunFERSMonteCarloComparison(mcCtx, names)
+			stop()
+			exitIfCancelled(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error simulating pension elections: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		switch strings.ToLower(format) {
+		case "json":
+			out, err := json.MarshalIndent(comparison, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			printPensionElection(comparison)
+		}
+	},
+}
+
+// printPensionElection prints the deterministic and simulated comparison of the elections
+func printPensionElection(comparison *calculation.PensionElectionComparison) {
+	fmt.Printf("PENSION ELECTION: LUMP SUM VS ANNUITY (%s)\n", comparison.ParticipantName)
+	fmt.Printf("=================================================\n\n")
+	fmt.Printf("Lump sum offer:       $%s, rolled over to the %s\n", comparison.LumpSum.StringFixed(0), strings.ToUpper(comparison.Rollover))
+	fmt.Printf("Annuity:              $%s a year\n", comparison.AnnualAnnuity.StringFixed(0))
+	if comparison.AnnualAnnuity.IsPositive() {
+		fmt.Printf("Payout rate:          %s of the lump sum\n",
+			domain.FormatRate(comparison.AnnualAnnuity.Div(comparison.LumpSum)))
+	}
+
+	fmt.Printf("\nProjection            %18s %18s\n", "Annuity", "Lump Sum")
+	fmt.Printf("Lifetime net income   %18s %18s\n",
+		"$"+comparison.Annuity.TotalLifetimeIncome.StringFixed(0), "$"+comparison.LumpSumSummary.TotalLifetimeIncome.StringFixed(0))
+	fmt.Printf("First full year net   %18s %18s\n",
+		"$"+comparison.Annuity.FirstFullYearNetIncome.StringFixed(0), "$"+comparison.LumpSumSummary.FirstFullYearNetIncome.StringFixed(0))
+	fmt.Printf("\nLump sum minus annuity:\n")
+	fmt.Printf("  Lifetime net income:        $%.0f\n", comparison.LifetimeIncomeDifference.InexactFloat64())
+	fmt.Printf("  Ending TSP and IRA savings: $%.0f\n", comparison.EndingBalanceDifference.InexactFloat64())
+
+	if comparison.MonteCarlo != nil {
+		fmt.Println()
+		printFERSMonteCarloComparison(comparison.MonteCarlo)
+	}
+}
+
+func init() {
+	pensionElectionCmd.Flags().StringP("scenario", "s", "", "Scenario to compare the elections in (default: first scenario)")
+	pensionElectionCmd.Flags().StringP("participant", "p", "", "Participant offered the lump sum (default: the first with a lump_sum offer)")
+	pensionElectionCmd.Flags().Int("simulations", 1000, "Number of simulated market paths (0 for the projection only)")
+	pensionElectionCmd.Flags().Int64("seed", 0, "Seed for the simulated market paths (default: time-based)")
+	pensionElectionCmd.Flags().Bool("historical", true, "Use historical data (false for statistical distributions)")
+	pensionElectionCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	pensionElectionCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+
+	rootCmd.AddCommand(pensionElectionCmd)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var sequenceRiskCmd = &cobra.Command{
+	Use:   "sequence-risk [input-file]",
+	Short: "Measure how much of a scenario's risk comes from the order of market returns",
+	Long: `Run a scenario on simulated market paths, each in several orderings of the
+same years: as drawn, worst years first, best years first, and every year at the
+path's average. The orderings share each path's average return, so the gap between
+worst-first and best-first is sequence-of-returns risk alone. The report also splits
+the spread of final TSP balances between the paths' average returns and their
+sequences.
+
+Examples:
+  # Sequence risk of the first scenario
+  ./rpgo sequence-risk config.yaml
+
+  # Five-year historical blocks with a fixed seed, as JSON
+  ./rpgo sequence-risk config.yaml --scenario "Retire 2027" --paths 500 --block-length 5 --seed 42 --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		paths, _ := cmd.Flags().GetInt("paths")
+		useHistorical, _ := cmd.Flags().GetBool("historical")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		if scenarioName == "" {
+			scenarioName = cfg.Scenarios[0].Name
+		}
+		if paths < 1 {
+			fmt.Fprintf(os.Stderr, "--paths must be at least 1, got %d\n", paths)
+			os.Exit(1)
+		}
+
+		var historicalData *calculation.HistoricalDataManager
+		if useHistorical {
+			historicalData, _ = loadHistoricalData(cmd, cfg, inputFile)
+		}
+		engine := calculation.NewFERSMonteCarloEngine(cfg, historicalData)
+		engine.SetSimulations(paths)
+		if cmd.Flags().Changed("seed") {
+			seed, _ := cmd.Flags().GetInt64("seed")
+			engine.SetSeed(seed)
+		}
+		if cmd.Flags().Changed("block-length") {
+			blockLength, _ := cmd.Flags().GetInt("block-length")
+			engine.SetBlockLength(blockLength)
+		}
+		engine.SetProgress(terminalProgress())
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		result, err := engine.RunSequenceRisk(ctx, scenarioName)
+		exitIfCancelled(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error analyzing sequence risk: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "json":
+			out, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			printSequenceRisk(result)
+		}
+	},
+}
+
+// sequenceOrderingLabels names the orderings in console output
+var sequenceOrderingLabels = map[string]string{
+	calculation.SequenceActual:     "As drawn",
+	calculation.SequenceWorstFirst: "Worst years first",
+	calculation.SequenceBestFirst:  "Best years first",
+	calculation.SequenceAverage:    "Average every year",
+}
+
+// printSequenceRisk prints the outcome of each ordering and the split of risk between
+// average returns and sequence
+func printSequenceRisk(result *calculation.SequenceRiskResult) {
+	fmt.Printf("SEQUENCE-OF-RETURNS RISK: %s\n", result.ScenarioName)
+	fmt.Printf("=================================================\n\n")
+	fmt.Printf("Paths: %d (seed %d)\n\n", result.NumPaths, result.Seed)
+
+	fmt.Printf("%-20s %16s %16s %16s %10s\n", "Ordering", "P10 Final TSP", "Median Final TSP", "Median Lifetime", "Depleted")
+	for _, outcome := range result.Orderings {
+		fmt.Printf("%-20s %16s %16s %16s %10s\n", sequenceOrderingLabels[outcome.Ordering],
+			"$"+outcome.FinalTSPBalance["10th"].StringFixed(0),
+			"$"+outcome.FinalTSPBalance["50th"].StringFixed(0),
+			"$"+outcome.MedianLifetimeIncome.StringFixed(0),
+			domain.FormatRate(outcome.DepletionRate))
+	}
+
+	fmt.Printf("\nBest-first minus worst-first final TSP balance:\n")
+	fmt.Printf("  10th percentile: $%s\n", result.BestMinusWorst["10th"].StringFixed(0))
+	fmt.Printf("  50th percentile: $%s\n", result.BestMinusWorst["50th"].StringFixed(0))
+	fmt.Printf("  90th percentile: $%s\n", result.BestMinusWorst["90th"].StringFixed(0))
+
+	fmt.Printf("\nSpread of final TSP balances explained by:\n")
+	fmt.Printf("  Average returns: %s\n", domain.FormatRate(result.AverageReturnShare))
+	fmt.Printf("  Sequence:        %s\n", domain.FormatRate(result.SequenceShare))
+}
+
+func init() {
+	sequenceRiskCmd.Flags().StringP("scenario", "s", "", "Scenario to analyze (default: first scenario)")
+	sequenceRiskCmd.Flags().Int("paths", 200, "Number of simulated market paths, each run in every ordering")
+	sequenceRiskCmd.Flags().Int64("seed", 0, "Seed for the simulated market paths (default: time-based)")
+	sequenceRiskCmd.Flags().Bool("historical", true, "Use historical data (false for statistical distributions)")
+	sequenceRiskCmd.Flags().Int("block-length", 0, "Draw historical paths in blocks of this many consecutive years (default: monte_carlo_settings.block_length, or independent years)")
+	sequenceRiskCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	sequenceRiskCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+
+	rootCmd.AddCommand(sequenceRiskCmd)
+}
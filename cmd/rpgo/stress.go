@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var stressTestCmd = &cobra.Command{
+	Use:   "stress-test [input-file]",
+	Short: "Replay historical crises and synthetic market shocks through a scenario",
+	Long: `Project a scenario through named market crises and compare each outcome with
+the baseline projection. A crisis is a window of history replayed from its first
+year, or a synthetic shock applied at the start of the projection; afterwards the
+projection returns to the baseline assumptions.
+
+Built-in crises: 1973, 2000, 2008 (history from that year), stagflation (five years
+of 9% inflation) and crash (an immediate -40% equity return with 8% inflation). Any
+other year replays history from that year, and a shock can be written as terms
+joined by "+": equity, bonds, cash and inflation rates, and years (default 1).
+
+Examples:
+  # The built-in crises for the first scenario
+  ./rpgo stress-test config.yaml
+
+  # Named crises and a custom two-year shock
+  ./rpgo stress-test config.yaml --scenario "Retire 2027" --scenarios 1973,2008,equity=-30%+inflation=6%+years=2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile := args[0]
+
+		scenarioName, _ := cmd.Flags().GetString("scenario")
+		specs, _ := cmd.Flags().GetStringSlice("scenarios")
+		format, _ := cmd.Flags().GetString("format")
+		regulatoryConfig, _ := cmd.Flags().GetString("regulatory-config")
+
+		// Load configuration
+		parser := config.NewInputParser()
+		var cfg *domain.Configuration
+		var err error
+
+		if regulatoryConfig != "" {
+			cfg, err = parser.LoadFromFileWithRegulatory(inputFile, regulatoryConfig)
+		} else {
+			cfg, err = parser.LoadFromFile(inputFile)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Scenarios) == 0 {
+			fmt.Fprintf(os.Stderr, "No scenarios found in configuration\n")
+			os.Exit(1)
+		}
+		if scenarioName == "" {
+			scenarioName = cfg.Scenarios[0].Name
+		}
+
+		stresses := make([]calculation.StressScenario, 0, len(specs))
+		needsHistory := false
+		for _, spec := range specs {
+			stress, err := calculation.ParseStressScenario(spec)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			needsHistory = needsHistory || stress.HistoricalStart != 0
+			stresses = append(stresses, stress)
+		}
+
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+		if needsHistory {
+			engine.HistoricalData, _ = loadHistoricalData(cmd, cfg, inputFile)
+		}
+		if engine.HistoricalData == nil && needsHistory {
+			// Without data the historical crises are skipped, keeping the synthetic shocks
+			synthetic := stresses[:0]
+			for _, stress := range stresses {
+				if stress.HistoricalStart != 0 {
+					fmt.Fprintf(os.Stderr, "Skipping %s: no historical data loaded\n", stress.Name)
+					continue
+				}
+				synthetic = append(synthetic, stress)
+			}
+			stresses = synthetic
+			if len(stresses) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: no stress tests left to run without historical data (see --data-path)\n")
+				os.Exit(1)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		result, err := engine.RunStressTests(ctx, cfg, scenarioName, stresses)
+		exitIfCancelled(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error running stress tests: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch strings.ToLower(format) {
+		case "json":
+			out, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		default:
+			printStressTests(result)
+		}
+	},
+}
+
+// printStressTests prints each stress outcome beside the baseline
+func printStressTests(result *calculation.StressTestResult) {
+	fmt.Printf("STRESS TESTS: %s\n", result.ScenarioName)
+	fmt.Printf("=================================================\n\n")
+
+	fmt.Printf("%-22s %6s %16s %14s %16s %14s %12s %8s\n", "Crisis", "Years", "Lifetime Income", "Change", "Final TSP", "Change", "Lowest TSP", "TSP Lasts")
+	row := func(outcome calculation.StressOutcome, change bool) {
+		incomeChange, tspChange := "", ""
+		if change {
+			incomeChange = "$" + outcome.LifetimeIncomeChange.StringFixed(0)
+			tspChange = "$" + outcome.FinalTSPBalanceChange.StringFixed(0)
+		}
+		fmt.Printf("%-22s %6d %16s %14s %16s %14s %12s %5d yr\n", outcome.Name, outcome.StressYears,
+			"$"+outcome.TotalLifetimeIncome.StringFixed(0), incomeChange,
+			"$"+outcome.FinalTSPBalance.StringFixed(0), tspChange,
+			"$"+outcome.LowestTSPBalance.StringFixed(0), outcome.TSPLongevity)
+	}
+	row(result.Baseline, false)
+	for _, outcome := range result.Stresses {
+		row(outcome, true)
+	}
+
+	fmt.Println()
+	for _, outcome := range result.Stresses {
+		description := outcome.Description
+		if n := len(outcome.HistoricalYears); n > 0 {
+			description += fmt.Sprintf(" (%d-%d)", outcome.HistoricalYears[0], outcome.HistoricalYears[n-1])
+		}
+		fmt.Printf("  %s: %s\n", outcome.Name, description)
+	}
+}
+
+func init() {
+	stressTestCmd.Flags().String("scenario", "", "Scenario to stress (default: first scenario)")
+	stressTestCmd.Flags().StringSlice("scenarios", calculation.DefaultStressScenarios, "Crises to replay: built-in names, years, or shocks like equity=-40%+inflation=8%")
+	stressTestCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	stressTestCmd.Flags().StringP("regulatory-config", "r", "", "Path to regulatory configuration file")
+	stressTestCmd.Flags().String("data-path", "", "Path to historical data (default: ./data, or next to the input file)")
+
+	rootCmd.AddCommand(stressTestCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 )
 
@@ -37,8 +38,7 @@ func (tf *TableFormatter) Format(result *OptimizationResult) string {
 		sb.WriteString(fmt.Sprintf("Retirement Date:     %s\n", result.OptimalRetirementDate.Format("January 2, 2006")))
 	}
 	if result.OptimalTSPRate != nil {
-		pct := result.OptimalTSPRate.Mul(decimal.NewFromInt(100))
-		sb.WriteString(fmt.Sprintf("TSP Withdrawal Rate: %s%%\n", pct.StringFixed(2)))
+		sb.WriteString(fmt.Sprintf("TSP Withdrawal Rate: %s\n", domain.FormatRate(*result.OptimalTSPRate)))
 	}
 	if result.OptimalSSAge != nil {
 		sb.WriteString(fmt.Sprintf("SS Claiming Age:     %d\n", *result.OptimalSSAge))
@@ -52,6 +52,9 @@ func (tf *TableFormatter) Format(result *OptimizationResult) string {
 	sb.WriteString("PROJECTED RESULTS\n")
 	sb.WriteString(strings.Repeat("-", 80) + "\n")
 	sb.WriteString(fmt.Sprintf("First Year Net Income: $%s\n", tf.formatCurrency(result.FirstYearNetIncome)))
+	if result.FirstFullRetirementYear > 0 {
+		sb.WriteString(fmt.Sprintf("First Full Year (%d): $%s\n", result.FirstFullRetirementYear, tf.formatCurrency(result.FirstFullYearNetIncome)))
+	}
 	sb.WriteString(fmt.Sprintf("Lifetime Income:       $%s\n", tf.formatCurrency(result.LifetimeIncome)))
 	sb.WriteString(fmt.Sprintf("TSP Longevity:         %d years\n", result.TSPLongevity))
 	sb.WriteString(fmt.Sprintf("Lifetime Taxes:        $%s\n", tf.formatCurrency(result.LifetimeTaxes)))
@@ -79,8 +82,9 @@ func (tf *TableFormatter) Format(result *OptimizationResult) string {
 		sb.WriteString("TARGET INCOME MATCH\n")
 		sb.WriteString(strings.Repeat("-", 80) + "\n")
 		sb.WriteString(fmt.Sprintf("Target Income:    $%s\n", tf.formatCurrency(*result.Request.Constraints.TargetIncome)))
-		sb.WriteString(fmt.Sprintf("Achieved Income:  $%s\n", tf.formatCurrency(result.FirstYearNetIncome)))
-		diff := result.FirstYearNetIncome.Sub(*result.Request.Constraints.TargetIncome)
+		achieved := retirementNetIncome(result.ScenarioSummary)
+		sb.WriteString(fmt.Sprintf("Achieved Income:  $%s\n", tf.formatCurrency(achieved)))
+		diff := achieved.Sub(*result.Request.Constraints.TargetIncome)
 		sb.WriteString(fmt.Sprintf("Difference:       %s$%s\n", tf.deltaSymbol(diff), tf.formatCurrency(diff.Abs())))
 		sb.WriteString("\n")
 	}
@@ -147,6 +151,69 @@ func (tf *TableFormatter) FormatMultiDimensional(result *MultiDimensionalResult)
 	return sb.String()
 }
 
+// FormatSSClaiming formats a Social Security claiming age sweep
+func (tf *TableFormatter) FormatSSClaiming(result *SSClaimingResult) string {
+	var sb strings.Builder
+
+	sb.WriteString("SOCIAL SECURITY CLAIMING STRATEGY\n")
+	sb.WriteString(strings.Repeat("=", 80) + "\n")
+	sb.WriteString(fmt.Sprintf("Scenario:      %s\n", result.ScenarioName))
+	sb.WriteString(fmt.Sprintf("Claiming Ages: %d-%d\n", result.MinAge, result.MaxAge))
+	sb.WriteString("\n")
+
+	if result.Optimal != nil {
+		sb.WriteString("OPTIMAL HOUSEHOLD STRATEGY\n")
+		sb.WriteString(strings.Repeat("-", 80) + "\n")
+		for _, name := range result.Participants {
+			sb.WriteString(fmt.Sprintf("%-20s claim at %d\n", tf.truncate(name, 20), result.Optimal.ClaimingAges[name]))
+		}
+		sb.WriteString(fmt.Sprintf("Lifetime Net Income (PV): $%s\n", tf.formatCurrency(result.Optimal.LifetimeIncome)))
+		sb.WriteString(fmt.Sprintf("Lifetime SS Benefits:     $%s\n", tf.formatCurrency(result.Optimal.LifetimeSSBenefits)))
+		sb.WriteString("\n")
+	}
+
+	if len(result.Participants) > 1 {
+		sb.WriteString("TOP JOINT STRATEGIES\n")
+		tf.writeSSStrategies(&sb, result.Participants, result.Joint, 10)
+	}
+	for _, name := range result.Participants {
+		sb.WriteString(fmt.Sprintf("INDIVIDUAL SWEEP - %s\n", name))
+		tf.writeSSStrategies(&sb, result.Participants, result.Individual[name], 0)
+	}
+
+	return sb.String()
+}
+
+func (tf *TableFormatter) writeSSStrategies(sb *strings.Builder, participants []string, strategies []SSClaimingStrategy, limit int) {
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	sb.WriteString(fmt.Sprintf("%-5s %-20s %15s %15s %20s\n", "Rank", "Claiming Ages", "Lifetime Net", "Lifetime SS", "Break-Even"))
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	for i, st := range strategies {
+		if limit > 0 && i >= limit {
+			break
+		}
+		ages := make([]string, 0, len(participants))
+		for _, name := range participants {
+			ages = append(ages, fmt.Sprintf("%d", st.ClaimingAges[name]))
+		}
+		breakEven := "-"
+		if st.BreakEvenYear > 0 {
+			beAges := make([]string, 0, len(participants))
+			for _, name := range participants {
+				beAges = append(beAges, fmt.Sprintf("%d", st.BreakEvenAges[name]))
+			}
+			breakEven = fmt.Sprintf("%d (age %s)", st.BreakEvenYear, strings.Join(beAges, "/"))
+		}
+		sb.WriteString(fmt.Sprintf("%-5d %-20s %15s %15s %20s\n",
+			st.Rank,
+			strings.Join(ages, "/"),
+			"$"+tf.formatShort(st.LifetimeIncome),
+			"$"+tf.formatShort(st.LifetimeSSBenefits),
+			breakEven))
+	}
+	sb.WriteString("\n")
+}
+
 // JSONFormatter formats results as JSON
 type JSONFormatter struct {
 	Pretty bool
@@ -188,6 +255,24 @@ func (jf *JSONFormatter) FormatMultiDimensional(result *MultiDimensionalResult)
 	return string(data), nil
 }
 
+// FormatSSClaiming formats a Social Security claiming age sweep as JSON
+func (jf *JSONFormatter) FormatSSClaiming(result *SSClaimingResult) (string, error) {
+	var data []byte
+	var err error
+
+	if jf.Pretty {
+		data, err = json.MarshalIndent(result, "", "  ")
+	} else {
+		data, err = json.Marshal(result)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
 // Helper methods
 
 func (tf *TableFormatter) formatStatus(success bool) string {
@@ -198,7 +283,7 @@ func (tf *TableFormatter) formatStatus(success bool) string {
 }
 
 func (tf *TableFormatter) formatCurrency(d decimal.Decimal) string {
-	return d.StringFixed(2)
+	return domain.FormatAmount(d)
 }
 
 func (tf *TableFormatter) formatShort(d decimal.Decimal) string {
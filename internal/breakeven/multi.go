@@ -106,8 +106,7 @@ func (s *Solver) generateMultiDimensionalRecommendations(result *MultiDimensiona
 		rec := fmt.Sprintf("To maximize lifetime income: Optimize %s",
 			result.BestByIncome.Request.Target)
 		if result.BestByIncome.OptimalTSPRate != nil {
-			rec += fmt.Sprintf(" (%.2f%% withdrawal rate)",
-				result.BestByIncome.OptimalTSPRate.InexactFloat64()*100)
+			rec += fmt.Sprintf(" (%s withdrawal rate)", domain.FormatRate(*result.BestByIncome.OptimalTSPRate))
 		}
 		if result.BestByIncome.OptimalRetirementDate != nil {
 			rec += fmt.Sprintf(" (retire %s)",
@@ -130,7 +130,7 @@ func (s *Solver) optimizeTSPRate(ctx context.Context, req OptimizationRequest) (
 
 		// Check if goal is met
 		if req.Goal == GoalMatchIncome && req.Constraints.TargetIncome != nil {
-			diff := summary.FirstYearNetIncome.Sub(*req.Constraints.TargetIncome)
+			diff := retirementNetIncome(summary).Sub(*req.Constraints.TargetIncome)
 			if diff.Abs().LessThan(req.Tolerance) {
 				result.Success = true
 				result.ConvergenceInfo = fmt.Sprintf("Converged to target income within $%s", req.Tolerance.StringFixed(0))
@@ -362,6 +362,19 @@ func (s *Solver) optimizeTSPBalance(ctx context.Context, req OptimizationRequest
 	}
 }
 
+// retirementNetIncome is the net income a target income is matched against: the first
+// full year of retirement, or the first projection year when the projection has none. A
+// transition year's part-year salary would otherwise stand in for retirement income.
+func retirementNetIncome(summary *domain.ScenarioSummary) decimal.Decimal {
+	if summary == nil {
+		return decimal.Zero
+	}
+	if summary.FirstFullRetirementYear > 0 {
+		return summary.FirstFullYearNetIncome
+	}
+	return summary.FirstYearNetIncome
+}
+
 // evaluateResult creates an optimization result from a scenario summary
 func (s *Solver) evaluateResult(
 	req OptimizationRequest,
@@ -379,6 +392,9 @@ func (s *Solver) evaluateResult(
 		LifetimeIncome:     summary.TotalLifetimeIncome,
 		TSPLongevity:       summary.TSPLongevity,
 		LifetimeTaxes:      s.calculateLifetimeTaxes(summary),
+
+		FirstFullRetirementYear: summary.FirstFullRetirementYear,
+		FirstFullYearNetIncome:  summary.FirstFullYearNetIncome,
 	}
 
 	// Set optimal parameters
@@ -412,8 +428,8 @@ func (s *Solver) isBetter(a, b *OptimizationResult, goal OptimizationGoal) bool
 		if a.Request.Constraints.TargetIncome == nil {
 			return false
 		}
-		aDiff := a.FirstYearNetIncome.Sub(*a.Request.Constraints.TargetIncome).Abs()
-		bDiff := b.FirstYearNetIncome.Sub(*b.Request.Constraints.TargetIncome).Abs()
+		aDiff := retirementNetIncome(a.ScenarioSummary).Sub(*a.Request.Constraints.TargetIncome).Abs()
+		bDiff := retirementNetIncome(b.ScenarioSummary).Sub(*b.Request.Constraints.TargetIncome).Abs()
 		return aDiff.LessThan(bDiff)
 	default:
 		return false
@@ -0,0 +1,242 @@
+package breakeven
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/internal/transform"
+	"github.com/shopspring/decimal"
+)
+
+// SSClaimingStrategy is one combination of Social Security claiming ages and its projected outcome
+type SSClaimingStrategy struct {
+	Rank               int             `json:"rank"`
+	ClaimingAges       map[string]int  `json:"claiming_ages"`
+	LifetimeIncome     decimal.Decimal `json:"lifetime_net_income"` // present value, as reported by the scenario summary
+	FirstYearNetIncome decimal.Decimal `json:"first_year_net_income"`
+	LifetimeSSBenefits decimal.Decimal `json:"lifetime_ss_benefits"`
+	TSPLongevity       int             `json:"tsp_longevity"`
+
+	// Net income in the first year spent fully retired, past any transition year
+	FirstFullYearNetIncome decimal.Decimal `json:"first_full_year_net_income"`
+
+	// Break-even against claiming at the minimum age: the first year cumulative
+	// net income catches up after having trailed. Zero when it never trails.
+	BreakEvenYear int            `json:"break_even_year,omitempty"`
+	BreakEvenAges map[string]int `json:"break_even_ages,omitempty"`
+
+	projection []domain.AnnualCashFlow
+}
+
+// SSClaimingResult holds the ranked claiming strategies for a household
+type SSClaimingResult struct {
+	ScenarioName string   `json:"scenario"`
+	Participants []string `json:"participants"`
+	MinAge       int      `json:"min_age"`
+	MaxAge       int      `json:"max_age"`
+
+	// Individual sweeps vary one participant while the others keep the scenario's ages
+	Individual map[string][]SSClaimingStrategy `json:"individual"`
+	// Joint sweeps every combination of claiming ages across participants
+	Joint   []SSClaimingStrategy `json:"joint"`
+	Optimal *SSClaimingStrategy  `json:"optimal,omitempty"`
+}
+
+// OptimizeSSClaiming sweeps Social Security claiming ages minAge..maxAge for each
+// participant with a benefit, individually and jointly, and ranks the strategies
+// by lifetime net income.
+func (s *Solver) OptimizeSSClaiming(
+	ctx context.Context,
+	scenario *domain.GenericScenario,
+	config *domain.Configuration,
+	minAge, maxAge int,
+) (*SSClaimingResult, error) {
+	if scenario == nil || config == nil {
+		return nil, &BreakEvenError{Operation: "optimize_ss_claiming", Message: "scenario and configuration are required"}
+	}
+	if minAge < 62 || maxAge > 70 || minAge > maxAge {
+		return nil, &BreakEvenError{
+			Operation: "optimize_ss_claiming",
+			Message:   fmt.Sprintf("invalid claiming age range %d-%d (must be within 62-70)", minAge, maxAge),
+		}
+	}
+
+	births := make(map[string]time.Time)
+	var participants []string
+	for _, p := range config.Household.Participants {
+		if _, ok := scenario.ParticipantScenarios[p.Name]; !ok || !p.SSBenefitFRA.IsPositive() {
+			continue
+		}
+		participants = append(participants, p.Name)
+		births[p.Name] = p.BirthDate
+	}
+	if len(participants) == 0 {
+		return nil, &BreakEvenError{Operation: "optimize_ss_claiming", Message: "no participants with Social Security benefits in scenario"}
+	}
+
+	result := &SSClaimingResult{
+		ScenarioName: scenario.Name,
+		Participants: participants,
+		MinAge:       minAge,
+		MaxAge:       maxAge,
+		Individual:   make(map[string][]SSClaimingStrategy),
+	}
+
+	for _, name := range participants {
+		var strategies []SSClaimingStrategy
+		for age := minAge; age <= maxAge; age++ {
+			strategy, err := s.evaluateSSClaiming(ctx, scenario, config, map[string]int{name: age})
+			if err != nil {
+				return nil, err
+			}
+			if strategy != nil {
+				strategies = append(strategies, *strategy)
+			}
+		}
+		result.Individual[name] = rankSSStrategies(strategies, births)
+	}
+
+	if len(participants) == 1 {
+		result.Joint = result.Individual[participants[0]]
+	} else {
+		var strategies []SSClaimingStrategy
+		for _, ages := range claimingAgeCombinations(participants, minAge, maxAge) {
+			strategy, err := s.evaluateSSClaiming(ctx, scenario, config, ages)
+			if err != nil {
+				return nil, err
+			}
+			if strategy != nil {
+				strategies = append(strategies, *strategy)
+			}
+		}
+		result.Joint = rankSSStrategies(strategies, births)
+	}
+
+	if len(result.Joint) == 0 {
+		return nil, &BreakEvenError{Operation: "optimize_ss_claiming", Message: "no claiming strategies could be evaluated"}
+	}
+	result.Optimal = &result.Joint[0]
+	return result, nil
+}
+
+// evaluateSSClaiming runs the scenario with the given claiming ages applied. A nil
+// strategy means the projection failed for this combination and it is skipped.
+func (s *Solver) evaluateSSClaiming(
+	ctx context.Context,
+	scenario *domain.GenericScenario,
+	config *domain.Configuration,
+	ages map[string]int,
+) (*SSClaimingStrategy, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	var transforms []transform.ScenarioTransform
+	claimingAges := make(map[string]int, len(scenario.ParticipantScenarios))
+	for name, ps := range scenario.ParticipantScenarios {
+		claimingAges[name] = ps.SSStartAge
+	}
+	for name, age := range ages {
+		transforms = append(transforms, &transform.DelaySSClaim{Participant: name, NewAge: age})
+		claimingAges[name] = age
+	}
+
+	modified, err := transform.ApplyTransforms(scenario, transforms)
+	if err != nil {
+		return nil, &BreakEvenError{Operation: "optimize_ss_claiming", Message: "failed to apply claiming ages", Cause: err}
+	}
+	summary, err := s.CalcEngine.RunGenericScenario(ctx, config, modified)
+	if err != nil {
+		return nil, nil
+	}
+
+	ssTotal := decimal.Zero
+	for i := range summary.Projection {
+		ssTotal = ssTotal.Add(summary.Projection[i].GetTotalSSBenefit())
+	}
+	return &SSClaimingStrategy{
+		ClaimingAges:       claimingAges,
+		LifetimeIncome:     summary.TotalLifetimeIncome,
+		FirstYearNetIncome: summary.FirstYearNetIncome,
+		LifetimeSSBenefits: ssTotal,
+		TSPLongevity:       summary.TSPLongevity,
+		projection:         summary.Projection,
+
+		FirstFullYearNetIncome: summary.FirstFullYearNetIncome,
+	}, nil
+}
+
+// claimingAgeCombinations enumerates every claiming age combination across participants
+func claimingAgeCombinations(participants []string, minAge, maxAge int) []map[string]int {
+	combos := []map[string]int{{}}
+	for _, name := range participants {
+		var next []map[string]int
+		for _, combo := range combos {
+			for age := minAge; age <= maxAge; age++ {
+				c := make(map[string]int, len(combo)+1)
+				for k, v := range combo {
+					c[k] = v
+				}
+				c[name] = age
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// rankSSStrategies computes break-even points against the earliest claiming
+// strategy (the first evaluated) and sorts by lifetime net income.
+func rankSSStrategies(strategies []SSClaimingStrategy, births map[string]time.Time) []SSClaimingStrategy {
+	if len(strategies) == 0 {
+		return strategies
+	}
+	baseline := strategies[0].projection
+	for i := range strategies {
+		year := breakEvenYear(baseline, strategies[i].projection)
+		if year == 0 {
+			continue
+		}
+		strategies[i].BreakEvenYear = year
+		strategies[i].BreakEvenAges = make(map[string]int, len(births))
+		for name, birth := range births {
+			strategies[i].BreakEvenAges[name] = year - birth.Year()
+		}
+	}
+
+	sort.SliceStable(strategies, func(i, j int) bool {
+		return strategies[i].LifetimeIncome.GreaterThan(strategies[j].LifetimeIncome)
+	})
+	for i := range strategies {
+		strategies[i].Rank = i + 1
+	}
+	return strategies
+}
+
+// breakEvenYear returns the first calendar year in which the candidate's cumulative
+// net income reaches the baseline's after having trailed it, or 0 if it never trails
+// or never catches up.
+func breakEvenYear(baseline, candidate []domain.AnnualCashFlow) int {
+	n := len(baseline)
+	if len(candidate) < n {
+		n = len(candidate)
+	}
+	baseCum, candCum := decimal.Zero, decimal.Zero
+	trailed := false
+	for i := 0; i < n; i++ {
+		baseCum = baseCum.Add(baseline[i].NetIncome)
+		candCum = candCum.Add(candidate[i].NetIncome)
+		if candCum.LessThan(baseCum) {
+			trailed = true
+		} else if trailed {
+			return candidate[i].Date.Year()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package breakeven
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestSolver_OptimizeSSClaiming(t *testing.T) {
+	cfg, err := config.NewInputParser().LoadFromFile("../../test/testdata/generic_example_config.yaml")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	solver := NewDefaultSolver(calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules))
+
+	result, err := solver.OptimizeSSClaiming(context.Background(), &cfg.Scenarios[0], cfg, 66, 68)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Participants) != 2 {
+		t.Fatalf("expected 2 participants, got %d", len(result.Participants))
+	}
+	if len(result.Joint) != 9 {
+		t.Fatalf("expected 3x3 joint strategies, got %d", len(result.Joint))
+	}
+	for _, name := range result.Participants {
+		if len(result.Individual[name]) != 3 {
+			t.Errorf("expected 3 individual strategies for %s, got %d", name, len(result.Individual[name]))
+		}
+	}
+	for i, st := range result.Joint {
+		if st.Rank != i+1 {
+			t.Errorf("expected rank %d, got %d", i+1, st.Rank)
+		}
+		if i > 0 && st.LifetimeIncome.GreaterThan(result.Joint[i-1].LifetimeIncome) {
+			t.Errorf("joint strategies not sorted by lifetime income at rank %d", st.Rank)
+		}
+	}
+	if result.Optimal == nil || result.Optimal.Rank != 1 {
+		t.Fatal("expected optimal strategy to be the top-ranked joint strategy")
+	}
+
+	// Repeated runs must rank identically
+	again, err := solver.OptimizeSSClaiming(context.Background(), &cfg.Scenarios[0], cfg, 66, 68)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range result.Joint {
+		if !result.Joint[i].LifetimeIncome.Equal(again.Joint[i].LifetimeIncome) {
+			t.Fatalf("non-deterministic lifetime income at rank %d", i+1)
+		}
+	}
+}
+
+func TestSolver_OptimizeSSClaiming_InvalidRange(t *testing.T) {
+	solver := NewDefaultSolver(&calculation.CalculationEngine{})
+	_, err := solver.OptimizeSSClaiming(context.Background(), &domain.GenericScenario{}, &domain.Configuration{}, 61, 70)
+	if err == nil {
+		t.Error("expected error for claiming age below 62")
+	}
+}
+
+func TestBreakEvenYear(t *testing.T) {
+	flows := func(values ...int64) []domain.AnnualCashFlow {
+		out := make([]domain.AnnualCashFlow, len(values))
+		for i, v := range values {
+			out[i] = domain.AnnualCashFlow{Date: time.Date(2030+i, 1, 1, 0, 0, 0, 0, time.UTC), NetIncome: decimal.NewFromInt(v)}
+		}
+		return out
+	}
+	baseline := flows(100, 100, 100, 100)
+
+	if got := breakEvenYear(baseline, flows(50, 150, 150, 150)); got != 2031 {
+		t.Errorf("expected break-even in 2031, got %d", got)
+	}
+	if got := breakEvenYear(baseline, flows(120, 100, 100, 100)); got != 0 {
+		t.Errorf("expected no break-even when never trailing, got %d", got)
+	}
+	if got := breakEvenYear(baseline, flows(0, 0, 150, 150)); got != 0 {
+		t.Errorf("expected no break-even when never catching up, got %d", got)
+	}
+}
@@ -101,6 +101,10 @@ type OptimizationResult struct {
 	TSPLongevity        int                     `json:"tsp_longevity"`
 	LifetimeTaxes       decimal.Decimal         `json:"lifetime_taxes"`
 
+	// First year spent fully retired, past any transition year with part-year salary
+	FirstFullRetirementYear int             `json:"first_full_retirement_year,omitempty"`
+	FirstFullYearNetIncome  decimal.Decimal `json:"first_full_year_net_income"`
+
 	// Comparison to base (if applicable)
 	BaseScenarioSummary *domain.ScenarioSummary `json:"base_scenario_summary,omitempty"`
 	IncomeDiffFromBase  decimal.Decimal         `json:"income_diff_from_base,omitempty"`
@@ -0,0 +1,282 @@
+// Package bundle packages a configuration, its regulatory file, the historical data it
+// uses and its results into a single zip archive, so a run can be reproduced elsewhere
+// for a bug report or an advisor.
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Archive layout
+const (
+	ManifestFile   = "manifest.json"
+	ConfigFile     = "config.yaml"
+	RegulatoryFile = "regulatory.yaml"
+	ResultsFile    = "results.json"
+	DataDir        = "data"
+)
+
+// FormatVersion is the bundle layout this version of rpgo writes and reads
+const FormatVersion = 1
+
+// maxFileSize bounds each file read from an archive
+const maxFileSize = 256 << 20
+
+// Manifest describes a bundle and records the key results of the bundled run, which an
+// import compares against its own run
+type Manifest struct {
+	FormatVersion int              `json:"formatVersion"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	Source        string           `json:"source"` // name of the exported configuration file
+	Regulatory    bool             `json:"regulatory"`
+	DataFiles     []string         `json:"dataFiles,omitempty"` // historical data files under data/
+	Redacted      bool             `json:"redacted"`
+	Scenarios     []ScenarioResult `json:"scenarios"`
+}
+
+// ScenarioResult is the part of a scenario's results a reproduction must match
+type ScenarioResult struct {
+	Name                string          `json:"name"`
+	FirstYearNetIncome  decimal.Decimal `json:"firstYearNetIncome"`
+	TotalLifetimeIncome decimal.Decimal `json:"totalLifetimeIncome"`
+	FinalTSPBalance     decimal.Decimal `json:"finalTspBalance"`
+	TSPLongevity        int             `json:"tspLongevity"`
+}
+
+// Bundle is a manifest and the files archived with it, by slash-separated archive path
+type Bundle struct {
+	Manifest Manifest
+	Files    map[string][]byte
+}
+
+// New creates a bundle of a configuration and, when regulatoryData is not nil, its
+// regulatory file
+func New(source string, configData, regulatoryData []byte) *Bundle {
+	b := &Bundle{
+		Manifest: Manifest{FormatVersion: FormatVersion, CreatedAt: time.Now().UTC(), Source: filepath.Base(source)},
+		Files:    map[string][]byte{ConfigFile: configData},
+	}
+	if regulatoryData != nil {
+		b.Manifest.Regulatory = true
+		b.Files[RegulatoryFile] = regulatoryData
+	}
+	return b
+}
+
+// AddDataFiles adds the historical data files, relative to dataPath, that exist there
+func (b *Bundle) AddDataFiles(dataPath string, files []string) error {
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(dataPath, rel))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		b.Files[path.Join(DataDir, name)] = data
+		b.Manifest.DataFiles = append(b.Manifest.DataFiles, name)
+	}
+	return nil
+}
+
+// SetResults records the run's results: the full report and the key results in the manifest
+func (b *Bundle) SetResults(results *domain.ScenarioComparison) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding results: %w", err)
+	}
+	b.Files[ResultsFile] = data
+	b.Manifest.Scenarios = Summarize(results)
+	return nil
+}
+
+// Write writes the bundle as a zip archive
+func (b *Bundle) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, ManifestFile, manifest); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeZipFile(zw, name, b.Files[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// Read reads a bundle from a zip archive
+func Read(filename string) (*Bundle, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a bundle: %w", filename, err)
+	}
+
+	b := &Bundle{Files: make(map[string][]byte)}
+	var manifest []byte
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if !validArchivePath(f.Name) {
+			return nil, fmt.Errorf("bundle contains an unsafe path %q", f.Name)
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		if f.Name == ManifestFile {
+			manifest = data
+			continue
+		}
+		b.Files[f.Name] = data
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%s has no %s", filename, ManifestFile)
+	}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ManifestFile, err)
+	}
+	if b.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("bundle format %d is newer than this version of rpgo supports (%d)", b.Manifest.FormatVersion, FormatVersion)
+	}
+	if _, ok := b.Files[ConfigFile]; !ok {
+		return nil, fmt.Errorf("%s has no %s", filename, ConfigFile)
+	}
+	return b, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxFileSize>>20)
+	}
+	return data, nil
+}
+
+// validArchivePath reports whether name stays inside the directory a bundle is extracted to
+func validArchivePath(name string) bool {
+	if name == "" || strings.Contains(name, `\`) || path.IsAbs(name) {
+		return false
+	}
+	clean := path.Clean(name)
+	return clean == name && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// Extract writes the bundle's files, including the manifest, into dir
+func (b *Bundle) Extract(dir string) error {
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{ManifestFile: manifest}
+	for name, data := range b.Files {
+		files[name] = data
+	}
+	for name, data := range files {
+		if !validArchivePath(name) {
+			return fmt.Errorf("unsafe path %q", name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Summarize returns the key results of each scenario
+func Summarize(results *domain.ScenarioComparison) []ScenarioResult {
+	if results == nil {
+		return nil
+	}
+	summary := make([]ScenarioResult, 0, len(results.Scenarios))
+	for _, s := range results.Scenarios {
+		summary = append(summary, ScenarioResult{
+			Name:                s.Name,
+			FirstYearNetIncome:  s.FirstYearNetIncome,
+			TotalLifetimeIncome: s.TotalLifetimeIncome,
+			FinalTSPBalance:     s.FinalTSPBalance,
+			TSPLongevity:        s.TSPLongevity,
+		})
+	}
+	return summary
+}
+
+// resultTolerance absorbs rounding in results that went through JSON
+var resultTolerance = decimal.NewFromFloat(0.01)
+
+// CompareResults lists how a reproduction's key results differ from the bundled ones.
+// An empty list means the run was reproduced.
+func CompareResults(expected, actual []ScenarioResult) []string {
+	var diffs []string
+	if len(expected) != len(actual) {
+		diffs = append(diffs, fmt.Sprintf("expected %d scenarios, got %d", len(expected), len(actual)))
+	}
+	for i := 0; i < min(len(expected), len(actual)); i++ {
+		e, a := expected[i], actual[i]
+		if e.Name != a.Name {
+			diffs = append(diffs, fmt.Sprintf("scenario %d: expected %q, got %q", i+1, e.Name, a.Name))
+			continue
+		}
+		amount := func(metric string, want, got decimal.Decimal) {
+			if want.Sub(got).Abs().GreaterThan(resultTolerance) {
+				diffs = append(diffs, fmt.Sprintf("%s: %s expected $%s, got $%s", e.Name, metric, domain.FormatAmount(want), domain.FormatAmount(got)))
+			}
+		}
+		amount("first year net income", e.FirstYearNetIncome, a.FirstYearNetIncome)
+		amount("lifetime income", e.TotalLifetimeIncome, a.TotalLifetimeIncome)
+		amount("final TSP balance", e.FinalTSPBalance, a.FinalTSPBalance)
+		if e.TSPLongevity != a.TSPLongevity {
+			diffs = append(diffs, fmt.Sprintf("%s: TSP longevity expected %d years, got %d", e.Name, e.TSPLongevity, a.TSPLongevity))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,134 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	dataPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataPath, "tsp-returns"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataPath, "tsp-returns", "c-fund-annual.csv"), []byte("year,return\n2024,0.25\n"), 0o644))
+
+	b := New("plans/config.yaml", []byte("household: {}\n"), []byte("federal_rules: {}\n"))
+	require.NoError(t, b.AddDataFiles(dataPath, []string{"tsp-returns/c-fund-annual.csv", "inflation/cpi-annual.csv"}))
+	assert.Equal(t, []string{"tsp-returns/c-fund-annual.csv"}, b.Manifest.DataFiles, "missing data files are skipped")
+	require.NoError(t, b.SetResults(&domain.ScenarioComparison{Scenarios: []domain.ScenarioSummary{
+		{Name: "Early", FirstYearNetIncome: decimal.NewFromInt(91000), TSPLongevity: 30},
+	}}))
+
+	archive := filepath.Join(t.TempDir(), "bundle.zip")
+	var buf bytes.Buffer
+	require.NoError(t, b.Write(&buf))
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o644))
+
+	read, err := Read(archive)
+	require.NoError(t, err)
+	assert.Equal(t, "config.yaml", read.Manifest.Source)
+	assert.True(t, read.Manifest.Regulatory)
+	assert.Empty(t, CompareResults(b.Manifest.Scenarios, read.Manifest.Scenarios))
+	assert.Equal(t, b.Files, read.Files)
+
+	dir := t.TempDir()
+	require.NoError(t, read.Extract(dir))
+	for _, name := range []string{ManifestFile, ConfigFile, RegulatoryFile, ResultsFile, "data/tsp-returns/c-fund-annual.csv"} {
+		assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+}
+
+func TestReadRejectsUnsafePaths(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	require.NoError(t, writeZipFile(zw, ManifestFile, []byte(`{"formatVersion":1}`)))
+	require.NoError(t, writeZipFile(zw, ConfigFile, []byte("household: {}\n")))
+	require.NoError(t, writeZipFile(zw, "../evil.sh", []byte("rm -rf /")))
+	require.NoError(t, zw.Close())
+	archive := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o644))
+
+	_, err := Read(archive)
+	assert.ErrorContains(t, err, "unsafe path")
+
+	for _, name := range []string{"../x", "/etc/passwd", `data\..\x`, "data/../../x", ""} {
+		assert.False(t, validArchivePath(name), name)
+	}
+	assert.True(t, validArchivePath("data/cola/ss-cola-annual.csv"))
+}
+
+func TestRedactBalances(t *testing.T) {
+	config := []byte(`household:
+  participants:
+    - name: "Robert"
+      tsp_balance_traditional: 487312.55 # as of last statement
+      tsp_balance_roth: 12345
+      taxable_account_balance: 0
+      taxable_account_basis: 61234
+      current_salary: 145000
+`)
+	redacted, err := RedactBalances(config)
+	require.NoError(t, err)
+	out := string(redacted)
+	assert.Contains(t, out, "tsp_balance_traditional: 490000 # as of last statement")
+	assert.Contains(t, out, "tsp_balance_roth: 12000")
+	assert.Contains(t, out, "taxable_account_balance: 0")
+	assert.Contains(t, out, "taxable_account_basis: 61000")
+	assert.Contains(t, out, "current_salary: 145000", "only balances are redacted")
+}
+
+func TestExportedBundleHasNoHooks(t *testing.T) {
+	config := []byte(`household:
+  participants:
+    - name: "Robert"
+      tsp_balance_traditional: 487312
+hooks:
+  post_run:
+    - command: "curl -H 'Authorization: Bearer secret' https://example.com"
+scenarios: []
+`)
+	data, removed, err := ConfigForExport(config, false)
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	var buf bytes.Buffer
+	require.NoError(t, New("config.yaml", data, nil).Write(&buf))
+	archive := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o644))
+	read, err := Read(archive)
+	require.NoError(t, err)
+	bundled := string(read.Files[ConfigFile])
+	assert.NotContains(t, bundled, "hooks")
+	assert.NotContains(t, bundled, "secret")
+	assert.Contains(t, bundled, "tsp_balance_traditional: 487312")
+	assert.Contains(t, bundled, "scenarios: []")
+
+	// Redaction still applies, and a configuration without hooks is bundled as written
+	redacted, removed, err := ConfigForExport(config, true)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	assert.Contains(t, string(redacted), "tsp_balance_traditional: 490000")
+	plain := []byte("household: {}\n")
+	data, removed, err = ConfigForExport(plain, false)
+	require.NoError(t, err)
+	assert.False(t, removed)
+	assert.Equal(t, plain, data)
+}
+
+func TestCompareResults(t *testing.T) {
+	expected := []ScenarioResult{{Name: "Early", FirstYearNetIncome: decimal.NewFromInt(91000), TSPLongevity: 30}}
+	assert.Empty(t, CompareResults(expected, []ScenarioResult{{Name: "Early", FirstYearNetIncome: decimal.NewFromFloat(91000.004), TSPLongevity: 30}}))
+
+	diffs := CompareResults(expected, []ScenarioResult{{Name: "Early", FirstYearNetIncome: decimal.NewFromInt(90500), TSPLongevity: 28}})
+	assert.Equal(t, []string{
+		"Early: first year net income expected $91000.00, got $90500.00",
+		"Early: TSP longevity expected 30 years, got 28",
+	}, diffs)
+	assert.Len(t, CompareResults(expected, nil), 1)
+}
@@ -0,0 +1,124 @@
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedSignificantDigits is how many leading digits of a balance survive redaction
+const redactedSignificantDigits = 2
+
+// ConfigForExport returns the configuration as it is bundled: without its hooks, which
+// would run the sender's commands or post to the sender's URLs on the recipient's
+// machine, and with balances redacted when redact is set. removedHooks reports whether
+// there were hooks to leave out. A configuration without hooks is bundled unchanged
+// unless it is redacted.
+func ConfigForExport(configData []byte, redact bool) (data []byte, removedHooks bool, err error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(configData, &doc); err != nil {
+		return nil, false, fmt.Errorf("parsing configuration: %w", err)
+	}
+	removedHooks = removeHooks(&doc)
+	if redact {
+		if err := redactNode(&doc); err != nil {
+			return nil, false, err
+		}
+	}
+	if !removedHooks && !redact {
+		return configData, false, nil
+	}
+	data, err = encodeYAML(&doc)
+	return data, removedHooks, err
+}
+
+// removeHooks deletes the top-level hooks block, reporting whether there was one
+func removeHooks(doc *yaml.Node) bool {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return false
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "hooks" {
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RedactBalances returns the configuration with every account balance and cost basis
+// rounded to two significant digits, e.g. 487,312 becomes 490,000. The plan keeps its
+// shape for a bug report without revealing the exact figures. Comments and layout are
+// kept.
+func RedactBalances(configData []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(configData, &doc); err != nil {
+		return nil, fmt.Errorf("parsing configuration: %w", err)
+	}
+	if err := redactNode(&doc); err != nil {
+		return nil, err
+	}
+	return encodeYAML(&doc)
+}
+
+// encodeYAML writes a parsed document back out with the two-space indent configurations use
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func redactNode(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && value.Tag != "!!null" && isBalanceKey(key.Value) {
+				amount, err := decimal.NewFromString(strings.ReplaceAll(value.Value, ",", ""))
+				if err != nil {
+					return fmt.Errorf("%s: %q is not an amount", key.Value, value.Value)
+				}
+				value.Value = roundSignificant(amount, redactedSignificantDigits).String()
+				if value.Style == 0 {
+					value.Tag = "" // 487312.55 rounds to an int, not a float
+				}
+				continue
+			}
+			if err := redactNode(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := redactNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isBalanceKey reports whether a configuration key holds an account balance or basis
+func isBalanceKey(key string) bool {
+	return strings.Contains(key, "balance") || key == "taxable_account_basis"
+}
+
+// roundSignificant rounds amount to the given number of significant digits
+func roundSignificant(amount decimal.Decimal, digits int) decimal.Decimal {
+	whole := amount.Abs().Truncate(0)
+	if whole.IsZero() {
+		return amount.Round(0)
+	}
+	places := len(whole.String()) - digits
+	return amount.Round(-int32(max(places, 0)))
+}
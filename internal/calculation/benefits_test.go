@@ -262,3 +262,73 @@ func TestPartTimeWorkCalculator_CalculatePartTimeWorkForYear_ActivePeriod(t *tes
 func decimalPtr(d decimal.Decimal) *decimal.Decimal {
 	return &d
 }
+
+func TestProjectionFEHBPremiumConversion(t *testing.T) {
+	hire := time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	salary := decimal.NewFromInt(100000)
+	high3 := decimal.NewFromInt(95000)
+	fehb := decimal.NewFromInt(300)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:                    "Morgan",
+			IsFederal:               true,
+			BirthDate:               time.Date(1964, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:                &hire,
+			CurrentSalary:           &salary,
+			High3Salary:             &high3,
+			FEHBPremiumPerPayPeriod: &fehb,
+			IsPrimaryFEHBHolder:     true,
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "fehb",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Morgan": {ParticipantName: "Morgan", RetirementDate: &retire, SSStartAge: 67},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+
+	// Working year: all 26 pay periods of premiums are pre-tax
+	assert.True(t, projection[0].FEHBPreTaxPremium.Equal(decimal.NewFromInt(7800)), "got %s", projection[0].FEHBPreTaxPremium)
+	assert.True(t, projection[0].FEHBRetroactivePremium.IsZero())
+
+	// Retirement year: only the working half is pre-tax, and one month is collected retroactively
+	assert.InDelta(t, 3900, projection[1].FEHBPreTaxPremium.InexactFloat64(), 50)
+	assert.True(t, projection[1].FEHBRetroactivePremium.Equal(decimal.NewFromInt(650)), "got %s", projection[1].FEHBRetroactivePremium)
+	assert.True(t, projection[1].FEHBPremium.Equal(decimal.NewFromInt(8450)), "got %s", projection[1].FEHBPremium)
+
+	// Retired: premiums are withheld from the annuity after tax
+	assert.True(t, projection[2].FEHBPreTaxPremium.IsZero())
+	assert.True(t, projection[2].FEHBRetroactivePremium.IsZero())
+}
+
+func TestProjectionFEHBPlanPremiumGrowth(t *testing.T) {
+	fehb, growth := decimal.NewFromInt(300), decimal.NewFromFloat(0.1)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:                    "Morgan",
+			BirthDate:               time.Date(1964, 1, 1, 0, 0, 0, 0, time.UTC),
+			FEHBPremiumPerPayPeriod: &fehb,
+			IsPrimaryFEHBHolder:     true,
+		}},
+	}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Morgan": {ParticipantName: "Morgan", RetirementDate: &retire, SSStartAge: 67},
+	}}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 2, FEHBPremiumInflation: decimal.NewFromFloat(0.05)}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	assert.Equal(t, "8190", projection[1].FEHBPremium.String())
+
+	// The plan's premium growth replaces the FEHB premium inflation assumption
+	household.Participants[0].FEHBPremiumGrowth = &growth
+	projection = NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	assert.Equal(t, "7800", projection[0].FEHBPremium.String())
+	assert.Equal(t, "8580", projection[1].FEHBPremium.String())
+}
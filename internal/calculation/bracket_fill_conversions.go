@@ -0,0 +1,107 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// bracketFillConversionMaxTrials bounds how many conversions the solver tries for a year
+const bracketFillConversionMaxTrials = 10
+
+var (
+	// bracketFillConversionTolerance is how close, in dollars, a year's taxable income must
+	// come to the top of the target bracket
+	bracketFillConversionTolerance = decimal.NewFromInt(1)
+	// bracketFillConversionMinSlope is the least taxable income a dollar of conversion must
+	// add for the solver to keep adjusting the year
+	bracketFillConversionMinSlope = decimal.NewFromFloat(0.05)
+)
+
+// autoConversionParticipant returns the participant whose traditional TSP the bracket_fill
+// strategy converts to Roth, or "" when the scenario does not convert automatically
+func autoConversionParticipant(household *domain.Household, scenario *domain.GenericScenario) string {
+	if household == nil || scenario == nil || scenario.WithdrawalSequencing == nil {
+		return ""
+	}
+	ws := scenario.WithdrawalSequencing
+	if ws.Strategy != "bracket_fill" || !ws.AutoRothConversions || ws.TargetBracket == nil {
+		return ""
+	}
+	if ws.ConversionParticipant != "" {
+		return ws.ConversionParticipant
+	}
+	for _, p := range household.Participants {
+		if _, ok := scenario.ParticipantScenarios[p.Name]; ok {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// fillBracketsWithConversions projects a bracket_fill scenario that converts traditional
+// TSP money to Roth automatically. Each year converts whatever brings federal taxable
+// income to the top of the target bracket less the buffer, so years of low ordinary
+// income convert the most and years already past the bracket convert nothing. A year's
+// conversion changes the balances, and so the RMDs and room, of every later year, so the
+// solver settles the years in order. A conversion can make more Social Security taxable,
+// so each year is re-projected with trial conversions, moving by a secant step on the
+// year's conversion-to-taxable income slope. A year whose taxable income stops
+// responding, because the traditional balance is exhausted or a MAGI ceiling clips the
+// conversion, keeps its last conversion.
+func (ce *CalculationEngine) fillBracketsWithConversions(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions, federalRules domain.FederalRules) []domain.AnnualCashFlow {
+	type trial struct {
+		conversion, taxableIncome decimal.Decimal
+	}
+
+	ws := scenario.WithdrawalSequencing
+	participant := autoConversionParticipant(household, scenario)
+	buffer := decimalZero
+	if ws.BracketBuffer != nil {
+		buffer = decimal.NewFromInt(int64(*ws.BracketBuffer))
+	}
+
+	schedule := make(map[int]decimal.Decimal)
+	project := func() []domain.AnnualCashFlow {
+		return ce.GenerateAnnualProjectionGeneric(household, withConversions(scenario, participant, schedule), assumptions, federalRules)
+	}
+
+	projection := project()
+	for i := range projection {
+		year := projection[i].Date.Year()
+		var previous *trial
+		for attempt := 0; attempt < bracketFillConversionMaxTrials; attempt++ {
+			cf := &projection[i]
+			top, ok := federalBracketTop(ce.TaxCalc.FederalTaxCalc, cf.FederalFilingStatus, *ws.TargetBracket)
+			if !ok {
+				return projection
+			}
+			conversion := schedule[year]
+			gap := top.Sub(buffer).Sub(cf.FederalTaxableIncome)
+			if gap.Abs().LessThan(bracketFillConversionTolerance) || (conversion.IsZero() && gap.IsNegative()) {
+				break
+			}
+
+			next := conversion.Add(gap)
+			if previous != nil {
+				slope := cf.FederalTaxableIncome.Sub(previous.taxableIncome).Div(conversion.Sub(previous.conversion))
+				if slope.LessThan(bracketFillConversionMinSlope) {
+					break
+				}
+				next = conversion.Add(gap.Div(slope))
+			}
+			next = decimal.Max(next.Floor(), decimalZero)
+			if next.Equal(conversion) {
+				break
+			}
+
+			previous = &trial{conversion: conversion, taxableIncome: cf.FederalTaxableIncome}
+			if next.IsZero() {
+				delete(schedule, year)
+			} else {
+				schedule[year] = next
+			}
+			projection = project()
+		}
+	}
+	return projection
+}
@@ -0,0 +1,42 @@
+package calculation
+
+import (
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBracketFillAutoRothConversions(t *testing.T) {
+	config, scenario := conversionOptimizerConfig()
+	config.Household.Participants[0].SSBenefit70 = decimal.NewFromInt(3000)
+	bracket, buffer := 12, 1000
+	scenario.WithdrawalSequencing = &domain.WithdrawalSequencingConfig{Strategy: "bracket_fill", TargetBracket: &bracket, BracketBuffer: &buffer, AutoRothConversions: true}
+	ce := NewCalculationEngine()
+	projection := ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	require.Len(t, projection, 10)
+
+	// Every year fills the 12% bracket to $1,000 below its top, including the years
+	// conversions make Social Security taxable and the years of RMDs
+	top, _ := federalBracketTop(ce.TaxCalc.FederalTaxCalc, "single", 12)
+	target := top.Sub(decimal.NewFromInt(1000))
+	for _, cf := range projection {
+		if cf.RothConversions.IsZero() {
+			assert.True(t, cf.FederalTaxableIncome.GreaterThanOrEqual(target), "%d: %s", cf.Date.Year(), cf.FederalTaxableIncome)
+			continue
+		}
+		assert.True(t, cf.FederalTaxableIncome.Sub(target).Abs().LessThan(decimal.NewFromInt(2)), "%d: %s", cf.Date.Year(), cf.FederalTaxableIncome)
+	}
+	assert.True(t, projection[0].RothConversions.GreaterThan(projection[3].RothConversions), "conversions shrink once Social Security begins")
+	assert.True(t, projection[9].RothBalance.IsPositive())
+	assert.Nil(t, scenario.ParticipantScenarios["Gail"].RothConversions, "the scenario is not modified")
+
+	// Without the option bracket_fill converts nothing
+	scenario.WithdrawalSequencing.AutoRothConversions = false
+	projection = ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	for _, cf := range projection {
+		assert.True(t, cf.RothConversions.IsZero())
+	}
+}
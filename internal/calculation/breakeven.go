@@ -118,7 +118,7 @@ func (ce *CalculationEngine) CalculateBreakEvenAnalysis(config *domain.Configura
 
 		// Sum all participant TSP withdrawals
 		withdrawalTotal := decimal.Zero
-		for _, w := range yearData.TSPWithdrawals {
+		for _, w := range yearData.TSPWithdrawals.All() {
 			withdrawalTotal = withdrawalTotal.Add(w)
 		}
 		results[i] = BreakEvenResult{
@@ -0,0 +1,100 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// bucketsWithdrawal returns the participant's full-year TSP withdrawal under the buckets
+// strategy: the initial rate of the balance, raised by inflation each later year. The
+// first year splits the TSP into its cash, bond and equity buckets. After a year the TSP
+// gained, equities refill the cash and bond buckets to their targets; after a loss
+// equities are left to recover and bonds refill the cash bucket instead.
+func (st *participantState) bucketsWithdrawal(ps domain.ParticipantScenario, infl decimal.Decimal) decimal.Decimal {
+	sizes := ps.Buckets.WithDefaults()
+	if st.bucketWithdrawal.IsZero() {
+		st.bucketWithdrawal = st.tspBalance.Mul(*ps.TSPWithdrawalRate)
+		st.buckets = domain.BucketBalances{Equities: st.tspBalance}
+		st.refillBuckets(sizes, true)
+		return st.bucketWithdrawal
+	}
+	st.bucketWithdrawal = st.bucketWithdrawal.Mul(onePlus(infl))
+	st.scaleBuckets()
+	st.refillBuckets(sizes, !st.lastTSPReturn.IsNegative())
+	return st.bucketWithdrawal
+}
+
+// refillBuckets tops up the cash bucket, then in an up market the bond bucket, to their
+// targets in years of withdrawals. Equities pay for both in an up market; in a down market
+// only bonds refill cash.
+func (st *participantState) refillBuckets(sizes domain.BucketStrategy, upMarket bool) {
+	cashTarget := st.bucketWithdrawal.Mul(decimal.NewFromInt(int64(sizes.CashYears)))
+	bondTarget := st.bucketWithdrawal.Mul(decimal.NewFromInt(int64(sizes.BondYears)))
+	if !upMarket {
+		topUpBucket(&st.buckets.Cash, &st.buckets.Bonds, cashTarget)
+		return
+	}
+	topUpBucket(&st.buckets.Cash, &st.buckets.Equities, cashTarget)
+	topUpBucket(&st.buckets.Bonds, &st.buckets.Equities, bondTarget)
+}
+
+// topUpBucket moves money from source into bucket until it reaches target or source runs out
+func topUpBucket(bucket, source *decimal.Decimal, target decimal.Decimal) {
+	move := decimal.Min(decimal.Max(target.Sub(*bucket), decimalZero), *source)
+	*bucket = bucket.Add(move)
+	*source = source.Sub(move)
+}
+
+// growBuckets settles the year's flows into the buckets and grows them: the net TSP
+// outflow comes from cash first, then bonds, then equities, and net inflows such as
+// contributions go to equities. Cash earns cashReturn, bonds bondReturn and equities the
+// market's TSP return. It returns the blended return of the whole balance.
+func (st *participantState) growBuckets(marketReturn, cashReturn, bondReturn decimal.Decimal) decimal.Decimal {
+	outflow := st.buckets.Total().Sub(st.tspBalance)
+	if outflow.IsNegative() {
+		st.buckets.Equities = st.buckets.Equities.Sub(outflow)
+	} else {
+		for _, bucket := range []*decimal.Decimal{&st.buckets.Cash, &st.buckets.Bonds, &st.buckets.Equities} {
+			taken := decimal.Min(outflow, *bucket)
+			*bucket = bucket.Sub(taken)
+			outflow = outflow.Sub(taken)
+		}
+	}
+
+	before := st.buckets.Total()
+	if !before.IsPositive() {
+		st.buckets = domain.BucketBalances{}
+		return marketReturn
+	}
+	st.buckets.Cash = st.buckets.Cash.Mul(onePlus(cashReturn))
+	st.buckets.Bonds = st.buckets.Bonds.Mul(onePlus(bondReturn))
+	st.buckets.Equities = st.buckets.Equities.Mul(onePlus(marketReturn))
+	return st.buckets.Total().Div(before).Sub(decimalOne)
+}
+
+// scaleBuckets rescales the buckets to the TSP balance, keeping their proportions, so
+// they match it exactly after rounding in growth and changes made outside the strategy
+func (st *participantState) scaleBuckets() {
+	total := st.buckets.Total()
+	if !total.IsPositive() {
+		st.buckets = domain.BucketBalances{Equities: st.tspBalance}
+		return
+	}
+	if total.Equal(st.tspBalance) {
+		return
+	}
+	ratio := st.tspBalance.Div(total)
+	st.buckets.Cash = st.buckets.Cash.Mul(ratio)
+	st.buckets.Bonds = st.buckets.Bonds.Mul(ratio)
+	st.buckets.Equities = st.tspBalance.Sub(st.buckets.Cash).Sub(st.buckets.Bonds)
+}
+
+// usesBuckets reports whether any participant of the scenario uses the buckets strategy
+func usesBuckets(scenario *domain.GenericScenario) bool {
+	for _, ps := range scenario.ParticipantScenarios {
+		if ps.TSPWithdrawalStrategy == "buckets" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketsWithdrawal(t *testing.T) {
+	rate := decimal.NewFromFloat(0.04)
+	ps := domain.ParticipantScenario{ParticipantName: "Gail", TSPWithdrawalStrategy: "buckets", TSPWithdrawalRate: &rate}
+	infl := decimal.NewFromFloat(0.03)
+	st := &participantState{tspBalance: decimal.NewFromInt(1000000)}
+
+	// Two years of withdrawals in cash, five in bonds and the rest in equities
+	assert.Equal(t, "40000", st.bucketsWithdrawal(ps, infl).String())
+	assert.Equal(t, "80000", st.buckets.Cash.String())
+	assert.Equal(t, "200000", st.buckets.Bonds.String())
+	assert.Equal(t, "720000", st.buckets.Equities.String())
+
+	// The withdrawal comes from cash while equities fall 20%
+	st.tspBalance = decimal.NewFromInt(960000)
+	blended := st.growBuckets(decimal.NewFromFloat(-0.20), decimal.NewFromFloat(0.02), decimal.NewFromFloat(0.04))
+	assert.Equal(t, "40800", st.buckets.Cash.String())
+	assert.Equal(t, "208000", st.buckets.Bonds.String())
+	assert.Equal(t, "576000", st.buckets.Equities.String())
+	st.tspBalance = decimal.NewFromInt(960000).Mul(onePlus(blended))
+	st.lastTSPReturn = decimal.NewFromFloat(-0.20)
+
+	// After the loss bonds refill cash and equities are left to recover
+	down := *st
+	assert.Equal(t, "41200", down.bucketsWithdrawal(ps, infl).String())
+	assert.Equal(t, "82400", down.buckets.Cash.Round(2).String())
+	assert.Equal(t, "166400", down.buckets.Bonds.Round(2).String())
+	assert.Equal(t, "576000", down.buckets.Equities.Round(2).String())
+
+	// After a gain equities refill cash; bonds are already above their target
+	up := *st
+	up.lastTSPReturn = decimal.NewFromFloat(0.10)
+	up.bucketsWithdrawal(ps, infl)
+	assert.Equal(t, "82400", up.buckets.Cash.Round(2).String())
+	assert.Equal(t, "208000", up.buckets.Bonds.Round(2).String())
+	assert.Equal(t, "534400", up.buckets.Equities.Round(2).String())
+
+	// Custom sizes
+	custom := &participantState{tspBalance: decimal.NewFromInt(1000000)}
+	ps.Buckets = &domain.BucketStrategy{CashYears: 1, BondYears: 3}
+	custom.bucketsWithdrawal(ps, infl)
+	assert.Equal(t, "40000", custom.buckets.Cash.String())
+	assert.Equal(t, "120000", custom.buckets.Bonds.String())
+}
+
+func TestBucketsStrategyInProjection(t *testing.T) {
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(600000)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	rate := decimal.NewFromFloat(0.05)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+			ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70,
+			TSPWithdrawalStrategy: "buckets", TSPWithdrawalRate: &rate,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         3,
+		InflationRate:           decimal.NewFromFloat(0.03),
+		TSPReturnPostRetirement: decimal.NewFromFloat(-0.10),
+	}
+	assumptions.TSPStatisticalModels.GFund.Mean = decimal.NewFromFloat(0.02)
+	assumptions.TSPStatisticalModels.FFund.Mean = decimal.NewFromFloat(0.04)
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 3)
+
+	// $30,000 of $60,000 cash is spent; cash earns the G fund, bonds the F fund and
+	// equities the 10% loss
+	first := projection[0].Buckets["Gail"]
+	assert.Equal(t, "30600", first.Cash.Round(2).String())
+	assert.Equal(t, "156000", first.Bonds.Round(2).String())
+	assert.Equal(t, "351000", first.Equities.Round(2).String())
+	assert.True(t, first.Total().Equal(projection[0].TSPBalances.Get("Gail")))
+
+	// In the down market the withdrawal comes from cash refilled by bonds; equities
+	// are not sold
+	second := projection[1].Buckets["Gail"]
+	assert.Equal(t, "30900", projection[1].TSPWithdrawals.Get("Gail").String())
+	assert.Equal(t, "31518", second.Cash.Round(2).String())
+	assert.Equal(t, "129792", second.Bonds.Round(2).String())
+	assert.Equal(t, "315900", second.Equities.Round(2).String())
+	assert.True(t, second.Total().Round(2).Equal(projection[1].TSPBalances.Get("Gail").Round(2)))
+}
@@ -0,0 +1,93 @@
+package calculation
+
+import (
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// cashFlowEventOwner returns whose accounts a cash flow event uses: its participant, the
+// first participant by default, or after their death the first survivor. It is empty when
+// nobody is alive.
+func cashFlowEventOwner(e domain.CashFlowEvent, household *domain.Household, aliveNames []string) string {
+	if len(aliveNames) == 0 {
+		return ""
+	}
+	owner := e.Participant
+	if owner == "" && len(household.Participants) > 0 {
+		owner = household.Participants[0].Name
+	}
+	for _, name := range aliveNames {
+		if name == owner {
+			return owner
+		}
+	}
+	return aliveNames[0]
+}
+
+// applyCashFlowEvent deposits an inflow to the taxable account or pays an outflow from the
+// event's account. It returns the amount moved, the ordinary income of traditional money
+// paid out and the capital gains realized selling taxable investments. Taxes on the
+// inflow itself follow its tax treatment and are left to the caller.
+func (st *participantState) applyCashFlowEvent(e domain.CashFlowEvent) (moved, ordinary, gains decimal.Decimal) {
+	if e.Inflow() {
+		st.taxableBalance = st.taxableBalance.Add(e.Amount)
+		st.taxableBasis = st.taxableBasis.Add(e.Amount)
+		return e.Amount, decimalZero, decimalZero
+	}
+
+	remaining := e.Amount
+	switch e.Account {
+	case domain.CashFlowAccountTSP:
+		fromTraditional := decimal.Min(remaining, st.tspBalanceTraditional)
+		st.tspBalanceTraditional = st.tspBalanceTraditional.Sub(fromTraditional)
+		fromRoth := decimal.Min(remaining.Sub(fromTraditional), st.tspBalanceRoth)
+		st.tspBalanceRoth = st.tspBalanceRoth.Sub(fromRoth)
+		st.rothTSPDistributed = st.rothTSPDistributed.Add(fromRoth)
+		st.tspBalance = st.tspBalance.Sub(fromTraditional).Sub(fromRoth)
+		ordinary = fromTraditional
+		moved = fromTraditional.Add(fromRoth)
+	case domain.CashFlowAccountIRA:
+		fromTraditional := decimal.Min(remaining, st.iraBalanceTraditional)
+		st.iraBalanceTraditional = st.iraBalanceTraditional.Sub(fromTraditional)
+		fromRoth := decimal.Min(remaining.Sub(fromTraditional), st.iraBalanceRoth)
+		st.iraBalanceRoth = st.iraBalanceRoth.Sub(fromRoth)
+		st.rothIRADistributed = st.rothIRADistributed.Add(fromRoth)
+		ordinary = fromTraditional
+		moved = fromTraditional.Add(fromRoth)
+	default:
+		if st.taxableBalance.IsPositive() {
+			moved = decimal.Min(remaining, st.taxableBalance)
+			basisUsed := st.taxableBasis.Mul(moved).Div(st.taxableBalance)
+			gains = moved.Sub(basisUsed)
+			st.taxableBasis = st.taxableBasis.Sub(basisUsed)
+			st.taxableBalance = st.taxableBalance.Sub(moved)
+		}
+	}
+	return moved, ordinary, gains
+}
+
+// cashFlowEventAccount names the account an outflow is paid from, for messages
+func cashFlowEventAccount(e domain.CashFlowEvent) string {
+	switch e.Account {
+	case domain.CashFlowAccountTSP:
+		return "TSP"
+	case domain.CashFlowAccountIRA:
+		return "IRA"
+	}
+	return "taxable account"
+}
+
+// outflowShortfallWarning reports an outflow the account could not fully pay
+func outflowShortfallWarning(p *domain.Participant, e domain.CashFlowEvent, paid decimal.Decimal, year int) domain.EngineWarning {
+	unpaid := e.Amount.Sub(paid)
+	return domain.EngineWarning{
+		Year:        year,
+		Participant: p.Name,
+		Code:        domain.WarningOutflowShortfall,
+		Amount:      unpaid,
+		Message: fmt.Sprintf("%s: %s of $%s exceeded the %s balance; $%s unpaid",
+			p.Label(), e.Name, e.Amount.StringFixed(0), cashFlowEventAccount(e), unpaid.StringFixed(0)),
+	}
+}
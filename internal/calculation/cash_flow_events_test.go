@@ -0,0 +1,76 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cashFlowEventHousehold() *domain.Household {
+	taxable, basis := decimal.NewFromInt(100000), decimal.NewFromInt(50000)
+	ira := decimal.NewFromInt(40000)
+	return &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Gail", BirthDate: time.Date(1958, 5, 1, 0, 0, 0, 0, time.UTC),
+			TaxableAccountBalance: &taxable, TaxableAccountBasis: &basis, IRABalanceTraditional: &ira,
+		}},
+	}
+}
+
+func projectCashFlowEvents(t *testing.T, events ...domain.CashFlowEvent) []domain.AnnualCashFlow {
+	t.Helper()
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70}},
+		CashFlowEvents:       events,
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(cashFlowEventHousehold(), scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 3)
+	return projection
+}
+
+func TestCashFlowEventInflows(t *testing.T) {
+	base := projectCashFlowEvents(t)
+	gain := decimal.NewFromInt(40000)
+	projection := projectCashFlowEvents(t,
+		domain.CashFlowEvent{Name: "Inheritance", Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Type: domain.CashFlowInflow, Amount: decimal.NewFromInt(200000)},
+		domain.CashFlowEvent{Name: "Home sale", Date: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), Type: domain.CashFlowInflow, Amount: decimal.NewFromInt(300000),
+			TaxTreatment: domain.CashFlowCapitalGain, TaxableAmount: &gain},
+	)
+
+	assert.True(t, projection[0].OneTimeInflows.IsZero())
+	assert.Equal(t, "500000", projection[1].OneTimeInflows.String())
+	// Only the taxable part of the home sale is a gain; the inheritance is tax free
+	assert.Equal(t, "40000", projection[1].CapitalGainsRealized.Sub(base[1].CapitalGainsRealized).String())
+	assert.True(t, projection[1].FederalTax.GreaterThanOrEqual(base[1].FederalTax))
+}
+
+func TestCashFlowEventOutflows(t *testing.T) {
+	base := projectCashFlowEvents(t)
+	projection := projectCashFlowEvents(t,
+		domain.CashFlowEvent{Name: "New roof", Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), Type: domain.CashFlowOutflow, Amount: decimal.NewFromInt(20000)},
+		domain.CashFlowEvent{Name: "Car", Date: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Type: domain.CashFlowOutflow, Amount: decimal.NewFromInt(60000), Account: domain.CashFlowAccountIRA},
+	)
+
+	first := projection[0]
+	assert.Equal(t, "20000", first.OneTimeOutflows.String())
+	// Selling $20,000 of a position half gains realizes $10,000 of gains
+	assert.Equal(t, "10000", first.CapitalGainsRealized.Sub(base[0].CapitalGainsRealized).String())
+
+	// The IRA holds less than the car costs: the traditional money is taxed and the rest unpaid
+	second := projection[1]
+	assert.True(t, second.OneTimeOutflows.LessThan(decimal.NewFromInt(60000)))
+	assert.True(t, second.IRABalances.Get("Gail").IsZero())
+	assert.True(t, second.FederalTax.GreaterThan(base[1].FederalTax))
+	require.Len(t, second.Warnings, 1)
+	assert.Equal(t, domain.WarningOutflowShortfall, second.Warnings[0].Code)
+	assert.Contains(t, second.Warnings[0].Message, "Car of $60000 exceeded the IRA balance")
+}
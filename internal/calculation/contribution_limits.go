@@ -0,0 +1,38 @@
+package calculation
+
+import "github.com/shopspring/decimal"
+
+// 2025 IRS limits on TSP elective deferrals, held constant like the tax brackets
+var (
+	electiveDeferralLimit = decimal.NewFromInt(23500)
+	catchUpLimit          = decimal.NewFromInt(7500)  // ages 50 and over
+	superCatchUpLimit     = decimal.NewFromInt(11250) // ages 60 through 63, replacing the regular catch-up
+)
+
+// ElectiveDeferralLimit returns the most a participant reaching age by the end of the
+// year may defer into the TSP, including catch-up contributions
+func ElectiveDeferralLimit(age int) decimal.Decimal {
+	switch {
+	case age >= 60 && age <= 63:
+		return electiveDeferralLimit.Add(superCatchUpLimit)
+	case age >= 50:
+		return electiveDeferralLimit.Add(catchUpLimit)
+	default:
+		return electiveDeferralLimit
+	}
+}
+
+// 2025 IRS limits on combined traditional and Roth IRA contributions
+var (
+	iraContributionLimit = decimal.NewFromInt(7000)
+	iraCatchUpLimit      = decimal.NewFromInt(1000) // ages 50 and over
+)
+
+// IRAContributionLimit returns the most a participant reaching age by the end of the year
+// may contribute to traditional and Roth IRAs combined
+func IRAContributionLimit(age int) decimal.Decimal {
+	if age >= 50 {
+		return iraContributionLimit.Add(iraCatchUpLimit)
+	}
+	return iraContributionLimit
+}
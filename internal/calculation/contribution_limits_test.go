@@ -0,0 +1,44 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElectiveDeferralLimit(t *testing.T) {
+	assert.Equal(t, "23500", ElectiveDeferralLimit(49).String())
+	assert.Equal(t, "31000", ElectiveDeferralLimit(50).String())
+	assert.Equal(t, "34750", ElectiveDeferralLimit(60).String())
+	assert.Equal(t, "34750", ElectiveDeferralLimit(63).String())
+	assert.Equal(t, "31000", ElectiveDeferralLimit(64).String())
+}
+
+func TestProjectionCapsTSPDeferrals(t *testing.T) {
+	value := func(v float64) *decimal.Decimal { d := decimal.NewFromFloat(v); return &d }
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{Name: "Alex", IsFederal: true, BirthDate: time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: value(200000), TSPContributionPercent: value(0.15)},
+			{Name: "Blair", IsFederal: true, BirthDate: time.Date(1964, 1, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: value(200000), TSPContributionPercent: value(0.15)},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 1}
+	ce := NewCalculationEngine()
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 1)
+	cf := projection[0]
+
+	// $30,000 configured: Alex is held to $23,500 while Blair, turning 61, may defer it all
+	assert.Equal(t, "23500", cf.ParticipantTSPContributions.Get("Alex").String())
+	assert.Equal(t, "30000", cf.ParticipantTSPContributions.Get("Blair").String())
+	require.Len(t, cf.Warnings, 1)
+	assert.Equal(t, domain.WarningContributionLimit, cf.Warnings[0].Code)
+	assert.Equal(t, "Alex", cf.Warnings[0].Participant)
+	assert.Equal(t, "6500", cf.Warnings[0].Amount.String())
+}
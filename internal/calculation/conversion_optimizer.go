@@ -0,0 +1,210 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// conversionOptimizerPasses bounds how many times the optimizer revisits every year
+const conversionOptimizerPasses = 3
+
+// conversionCandidateShares are the fractions of a year's conversion room the optimizer tries
+var conversionCandidateShares = []decimal.Decimal{
+	decimal.Zero, decimal.NewFromFloat(0.25), decimal.NewFromFloat(0.5), decimal.NewFromFloat(0.75), decimal.NewFromInt(1),
+}
+
+// defaultConversionStep rounds candidate conversions down to whole thousands
+var defaultConversionStep = decimal.NewFromInt(1000)
+
+// OptimizeRothConversions searches for the multi-year schedule of Roth conversions from a
+// participant's traditional TSP that best meets the goal: the most after-tax wealth at the
+// end of the projection, or the least lifetime tax including IRMAA, net of premium tax
+// credits. Each year from the first projection year through the horizon converts between
+// nothing and the room left below the top of the target bracket, stopping short of the
+// next IRMAA tier in Medicare years and of the premium tax credit cliff in years with a
+// credit. The search starts from no conversions and revisits each year in turn, keeping the
+// candidate amount that most improves the goal with every other year's conversion fixed,
+// until a pass changes nothing. Conversions the scenario already schedules are replaced.
+func (ce *CalculationEngine) OptimizeRothConversions(ctx context.Context, config *domain.Configuration, scenario *domain.GenericScenario, opts domain.ConversionOptimizerOptions) (*domain.ConversionOptimization, error) {
+	if _, ok := scenario.ParticipantScenarios[opts.Participant]; !ok {
+		return nil, fmt.Errorf("participant %s not found in scenario %s", opts.Participant, scenario.Name)
+	}
+	if opts.Goal != domain.ConversionGoalAfterTaxWealth && opts.Goal != domain.ConversionGoalLifetimeTax {
+		return nil, fmt.Errorf("goal must be %s or %s", domain.ConversionGoalAfterTaxWealth, domain.ConversionGoalLifetimeTax)
+	}
+	if opts.TerminalTaxRate.IsNegative() || opts.TerminalTaxRate.GreaterThan(decimalOne) {
+		return nil, fmt.Errorf("terminal tax rate must be between 0 and 100%%")
+	}
+	step := opts.Step
+	if !step.IsPositive() {
+		step = defaultConversionStep
+	}
+	if _, ok := federalBracketTop(ce.TaxCalc.FederalTaxCalc, "", opts.TargetBracket); !ok {
+		return nil, fmt.Errorf("no %d%% federal bracket", opts.TargetBracket)
+	}
+	if err := CheckScenarioFeasibility(config.Household, scenario, &config.GlobalAssumptions); err != nil {
+		return nil, err
+	}
+
+	schedule := make(map[int]decimal.Decimal)
+	projections := 0
+	project := func() []domain.AnnualCashFlow {
+		projections++
+		return ce.GenerateAnnualProjectionGeneric(config.Household, withConversions(scenario, opts.Participant, schedule), &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	}
+	score := func(totals domain.ConversionOutcomeTotals) decimal.Decimal {
+		if opts.Goal == domain.ConversionGoalLifetimeTax {
+			return totals.LifetimeTax.Sub(totals.LifetimePremiumTaxCredit).Neg()
+		}
+		return totals.AfterTaxWealth
+	}
+
+	projection := project()
+	if len(projection) == 0 {
+		return nil, fmt.Errorf("scenario %s has no projection years", scenario.Name)
+	}
+	firstYear := projection[0].Date.Year()
+	if opts.Horizon < firstYear {
+		return nil, fmt.Errorf("horizon %d is before the first projection year %d", opts.Horizon, firstYear)
+	}
+	baseline := conversionOutcomeTotals(projection, opts.TerminalTaxRate)
+	best, bestScore := baseline, score(baseline)
+
+	for pass := 0; pass < conversionOptimizerPasses; pass++ {
+		improved := false
+		for i := range projection {
+			year := projection[i].Date.Year()
+			if year > opts.Horizon {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			current := schedule[year]
+			room := conversionRoom(&projection[i], ce.TaxCalc.FederalTaxCalc, opts.TargetBracket, current)
+			if i > 0 {
+				room = decimal.Min(room, projection[i-1].TraditionalBalance)
+			}
+			for _, share := range conversionCandidateShares {
+				amount := room.Mul(share).Div(step).Floor().Mul(step)
+				if amount.Equal(current) {
+					continue
+				}
+				schedule[year] = amount
+				candidate := project()
+				totals := conversionOutcomeTotals(candidate, opts.TerminalTaxRate)
+				if s := score(totals); s.Sub(bestScore).GreaterThan(decimalOne) {
+					best, bestScore, projection, current = totals, s, candidate, amount
+					improved = true
+				}
+			}
+			if current.IsZero() {
+				delete(schedule, year)
+			} else {
+				schedule[year] = current
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	years := make([]int, 0, len(schedule))
+	for year := range schedule {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	result := &domain.ConversionOptimization{
+		Scenario:      scenario.Name,
+		Participant:   opts.Participant,
+		Goal:          opts.Goal,
+		TargetBracket: opts.TargetBracket,
+		FirstYear:     firstYear,
+		Horizon:       opts.Horizon,
+		Baseline:      baseline,
+		Optimized:     best,
+		Projections:   projections,
+	}
+	for _, year := range years {
+		result.Schedule.Conversions = append(result.Schedule.Conversions, domain.RothConversion{Year: year, Amount: schedule[year], Source: "traditional_tsp"})
+	}
+	return result, nil
+}
+
+// withConversions returns a copy of the scenario in which the participant converts the
+// scheduled amounts and nothing else
+func withConversions(scenario *domain.GenericScenario, participant string, schedule map[int]decimal.Decimal) *domain.GenericScenario {
+	copied := scenario.DeepCopy()
+	if copied.WithdrawalSequencing != nil {
+		copied.WithdrawalSequencing.AutoRothConversions = false
+	}
+	ps := copied.ParticipantScenarios[participant]
+	ps.RothConversions = nil
+	if len(schedule) > 0 {
+		ps.RothConversions = &domain.RothConversionSchedule{}
+		for year, amount := range schedule {
+			ps.RothConversions.Conversions = append(ps.RothConversions.Conversions, domain.RothConversion{Year: year, Amount: amount, Source: "traditional_tsp"})
+		}
+	}
+	copied.ParticipantScenarios[participant] = ps
+	return copied
+}
+
+// conversionRoom is how much a year could convert, counting its current conversion: the
+// room below the top of the target bracket and, when someone is on Medicare two years
+// later, below the next IRMAA tier. A year with a premium tax credit keeps the credit by staying below its cliff.
+func conversionRoom(cf *domain.AnnualCashFlow, ftc *FederalTaxCalculator, targetBracket int, current decimal.Decimal) decimal.Decimal {
+	top, _ := federalBracketTop(ftc, cf.FederalFilingStatus, targetBracket)
+	room := top.Sub(cf.FederalTaxableIncome).Add(current)
+	if cf.IRMAADistanceToNext.IsPositive() {
+		room = decimal.Min(room, cf.IRMAADistanceToNext.Add(current).Sub(decimalOne))
+	}
+	if cf.PremiumTaxCredit.IsPositive() {
+		room = decimal.Min(room, cf.ACACliffDistance.Add(current))
+	}
+	return decimal.Max(room, decimalZero)
+}
+
+// federalBracketTop returns the top of the federal bracket taxed at ratePercent
+func federalBracketTop(ftc *FederalTaxCalculator, filingStatus string, ratePercent int) (decimal.Decimal, bool) {
+	brackets := ftc.Brackets
+	if filingStatus == "single" && len(ftc.BracketsSingle) > 0 {
+		brackets = ftc.BracketsSingle
+	}
+	rate := decimal.New(int64(ratePercent), -2)
+	for _, b := range brackets {
+		if b.Rate.Equal(rate) {
+			return b.Max, true
+		}
+	}
+	return decimalZero, false
+}
+
+// conversionOutcomeTotals summarizes a projection for the conversion optimizer
+func conversionOutcomeTotals(projection []domain.AnnualCashFlow, terminalTaxRate decimal.Decimal) domain.ConversionOutcomeTotals {
+	var totals domain.ConversionOutcomeTotals
+	if len(projection) == 0 {
+		return totals
+	}
+	for i := range projection {
+		totals.AfterTaxWealth = totals.AfterTaxWealth.Add(projection[i].NetIncome)
+		totals.TotalConversions = totals.TotalConversions.Add(projection[i].RothConversions)
+		totals.LifetimePremiumTaxCredit = totals.LifetimePremiumTaxCredit.Add(projection[i].PremiumTaxCredit)
+	}
+	taxes := SummarizeLifetimeTaxes(projection)
+	totals.LifetimeTax = taxes.Total
+	totals.LifetimeIRMAA = taxes.IRMAA
+
+	last := projection[len(projection)-1]
+	totals.EndingTraditional = last.TraditionalBalance
+	totals.EndingRoth = last.RothBalance
+	totals.EndingTaxable = last.TaxableBalance
+	totals.AfterTaxWealth = totals.AfterTaxWealth.Add(last.RothBalance).Add(last.TaxableBalance).
+		Add(last.TraditionalBalance.Mul(decimalOne.Sub(terminalTaxRate)))
+	return totals
+}
@@ -0,0 +1,82 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conversionOptimizerConfig() (*domain.Configuration, *domain.GenericScenario) {
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(1000000)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	config := &domain.Configuration{
+		Household: household,
+		GlobalAssumptions: domain.GlobalAssumptions{
+			ProjectionYears: 10, TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+		},
+		Scenarios: []domain.GenericScenario{{
+			Name: "Retire",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+				ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70, TSPWithdrawalStrategy: "rmd_only",
+			}},
+		}},
+	}
+	return config, &config.Scenarios[0]
+}
+
+func TestRothConversionKeepsTSPGrowth(t *testing.T) {
+	config, scenario := conversionOptimizerConfig()
+	ce := NewCalculationEngine()
+	base := ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	converted := ce.GenerateAnnualProjectionGeneric(config.Household, withConversions(scenario, "Gail", map[int]decimal.Decimal{2027: decimal.NewFromInt(50000)}), &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+
+	// A conversion moves money inside the TSP: the balance keeps its growth and the
+	// converted amount is taxed but not spendable
+	assert.Equal(t, base[2].TSPBalances.Get("Gail").StringFixed(2), converted[2].TSPBalances.Get("Gail").StringFixed(2))
+	assert.Equal(t, "50000", converted[2].RothConversions.String())
+	assert.True(t, converted[2].RothBalance.GreaterThan(decimal.NewFromInt(50000)))
+	assert.True(t, converted[2].FederalTax.GreaterThan(base[2].FederalTax))
+	assert.True(t, converted[2].NetIncome.LessThan(base[2].NetIncome))
+	assert.Equal(t, base[2].TraditionalBalance.Add(base[2].RothBalance).StringFixed(2), converted[2].TraditionalBalance.Add(converted[2].RothBalance).StringFixed(2))
+}
+
+func TestOptimizeRothConversions(t *testing.T) {
+	config, scenario := conversionOptimizerConfig()
+	ce := NewCalculationEngine()
+	opts := domain.ConversionOptimizerOptions{
+		Participant: "Gail", TargetBracket: 22, Horizon: 2030,
+		Goal: domain.ConversionGoalAfterTaxWealth, TerminalTaxRate: decimal.NewFromFloat(0.32),
+	}
+	result, err := ce.OptimizeRothConversions(context.Background(), config, scenario, opts)
+	require.NoError(t, err)
+
+	// With the ending balance taxed at 32%, converting at up to 22% pays off
+	require.NotEmpty(t, result.Schedule.Conversions)
+	assert.True(t, result.Optimized.AfterTaxWealth.GreaterThan(result.Baseline.AfterTaxWealth))
+	assert.True(t, result.Optimized.EndingRoth.IsPositive())
+	top, _ := federalBracketTop(ce.TaxCalc.FederalTaxCalc, "single", 22)
+	for _, c := range result.Schedule.Conversions {
+		assert.LessOrEqual(t, c.Year, 2030)
+		assert.True(t, c.Amount.LessThanOrEqual(top), "%d: %s", c.Year, c.Amount)
+		assert.True(t, c.Amount.Mod(decimal.NewFromInt(1000)).IsZero())
+	}
+	assert.True(t, scenario.ParticipantScenarios["Gail"].RothConversions == nil, "the scenario is not modified")
+
+	// Converting only adds tax when the ending balance is never taxed
+	opts.Goal = domain.ConversionGoalLifetimeTax
+	result, err = ce.OptimizeRothConversions(context.Background(), config, scenario, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Schedule.Conversions)
+	assert.True(t, result.Optimized.LifetimeTax.Equal(result.Baseline.LifetimeTax))
+
+	opts.TargetBracket = 23
+	_, err = ce.OptimizeRothConversions(context.Background(), config, scenario, opts)
+	assert.ErrorContains(t, err, "no 23% federal bracket")
+}
@@ -0,0 +1,145 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// saversCreditContributionLimit is the most contributed per person that earns the Saver's Credit
+var saversCreditContributionLimit = decimal.NewFromInt(2000)
+
+// saversCreditTiers hold the 2025 AGI limits for each Saver's Credit rate
+var saversCreditTiers = []struct {
+	rate        decimal.Decimal
+	jointLimit  decimal.Decimal
+	singleLimit decimal.Decimal
+}{
+	{decimal.NewFromFloat(0.50), decimal.NewFromInt(47500), decimal.NewFromInt(23750)},
+	{decimal.NewFromFloat(0.20), decimal.NewFromInt(51000), decimal.NewFromInt(25500)},
+	{decimal.NewFromFloat(0.10), decimal.NewFromInt(79000), decimal.NewFromInt(39500)},
+}
+
+// Federal poverty guidelines for the premium tax credit: the first person plus each
+// additional household member
+var (
+	povertyLineFirstPerson = decimal.NewFromInt(15650)
+	povertyLinePerPerson   = decimal.NewFromInt(5500)
+)
+
+// premiumTaxCreditPercentages give the share of household income expected toward the
+// benchmark plan at income levels measured as a multiple of the poverty line. The share
+// rises linearly within each band; households above 400% of the poverty line get no credit.
+var premiumTaxCreditPercentages = []struct {
+	fpl  decimal.Decimal
+	rate decimal.Decimal
+}{
+	{decimal.NewFromFloat(1.00), decimal.RequireFromString("0.0210")},
+	{decimal.NewFromFloat(1.33), decimal.RequireFromString("0.0314")},
+	{decimal.NewFromFloat(1.50), decimal.RequireFromString("0.0419")},
+	{decimal.NewFromFloat(2.00), decimal.RequireFromString("0.0660")},
+	{decimal.NewFromFloat(2.50), decimal.RequireFromString("0.0844")},
+	{decimal.NewFromFloat(3.00), decimal.RequireFromString("0.0996")},
+	{decimal.NewFromFloat(4.00), decimal.RequireFromString("0.0996")},
+}
+
+// SaversCredit returns the retirement savings contributions credit for a household with
+// the given AGI and each contributor's elective deferrals. The credit is nonrefundable, so
+// callers limit it to the tax owed.
+func SaversCredit(agi decimal.Decimal, contributions []decimal.Decimal, filingStatus string) decimal.Decimal {
+	rate := decimal.Zero
+	for _, tier := range saversCreditTiers {
+		limit := tier.jointLimit
+		if filingStatus == "single" {
+			limit = tier.singleLimit
+		}
+		if agi.LessThanOrEqual(limit) {
+			rate = tier.rate
+			break
+		}
+	}
+	if rate.IsZero() {
+		return decimal.Zero
+	}
+	eligible := decimal.Zero
+	for _, c := range contributions {
+		eligible = eligible.Add(decimal.Min(decimal.Max(c, decimal.Zero), saversCreditContributionLimit))
+	}
+	return eligible.Mul(rate)
+}
+
+// premiumTaxCreditPercentage returns the share of income a household is expected to pay
+// toward the benchmark plan, or false when income is outside 100-400% of the poverty line
+func premiumTaxCreditPercentage(magi decimal.Decimal, householdSize int) (decimal.Decimal, bool) {
+	fpl := magi.Div(povertyLine(householdSize))
+	table := premiumTaxCreditPercentages
+	if fpl.LessThan(table[0].fpl) || fpl.GreaterThan(table[len(table)-1].fpl) {
+		return decimal.Zero, false
+	}
+	for i := 1; i < len(table); i++ {
+		lo, hi := table[i-1], table[i]
+		if fpl.LessThan(hi.fpl) {
+			if i == 1 {
+				return lo.rate, true // flat below 133%
+			}
+			w := fpl.Sub(lo.fpl).Div(hi.fpl.Sub(lo.fpl))
+			return lo.rate.Add(hi.rate.Sub(lo.rate).Mul(w)), true
+		}
+	}
+	return table[len(table)-1].rate, true
+}
+
+// povertyLine returns the federal poverty guideline for a household of householdSize
+func povertyLine(householdSize int) decimal.Decimal {
+	return povertyLineFirstPerson.Add(povertyLinePerPerson.Mul(decimal.NewFromInt(int64(max(householdSize, 1) - 1))))
+}
+
+// premiumTaxCreditCliff returns the most ACA modified AGI that still earns a premium tax
+// credit: 400% of the poverty line. A dollar more loses the whole credit.
+func premiumTaxCreditCliff(householdSize int) decimal.Decimal {
+	return povertyLine(householdSize).Mul(premiumTaxCreditPercentages[len(premiumTaxCreditPercentages)-1].fpl)
+}
+
+// PremiumTaxCredit returns the credit toward marketplace coverage for a household whose
+// benchmark premium is benchmark, limited to the premium itself. Income is the ACA
+// modified AGI: AGI plus the untaxed part of Social Security benefits.
+func PremiumTaxCredit(benchmark, magi decimal.Decimal, householdSize int) decimal.Decimal {
+	if !benchmark.IsPositive() {
+		return decimal.Zero
+	}
+	pct, ok := premiumTaxCreditPercentage(magi, householdSize)
+	if !ok {
+		return decimal.Zero
+	}
+	return decimal.Max(benchmark.Sub(magi.Mul(pct)), decimal.Zero)
+}
+
+// marketplacePremium sums the benchmark plan premiums of participants buying coverage on
+// the exchange before Medicare, for the months before Medicare starts: the configured
+// benchmark premium, or else the premium of the plan bought. COBRA premiums, also reported
+// as marketplace premiums, earn no credit.
+func (hcc *HealthcareCostCalculator) marketplacePremium(participants []domain.Participant, ages domain.ParticipantValues[int], year int) decimal.Decimal {
+	total := decimal.Zero
+	for i := range participants {
+		p := &participants[i]
+		if p.Healthcare == nil || p.Healthcare.PreMedicareCoverage != "marketplace" {
+			continue
+		}
+		if p.Healthcare.MarketplaceBenchmarkPremium.IsPositive() {
+			benchmark := *p.Healthcare
+			benchmark.PreMedicareMonthlyPremium = benchmark.MarketplaceBenchmarkPremium
+			q := *p
+			q.Healthcare = &benchmark
+			p = &q
+		}
+		breakdown := hcc.CalculateHealthcareCosts(p, ages.Get(p.Name), year, decimal.Zero, "")
+		total = total.Add(breakdown.MarketplacePremium)
+	}
+	return total
+}
+
+// adjustedGrossIncome totals the income the federal return taxes before deductions
+func adjustedGrossIncome(taxable domain.TaxableIncome) decimal.Decimal {
+	return taxable.Salary.Add(taxable.FERSPension).Add(taxable.TSPWithdrawalsTrad).
+		Add(taxable.TaxableSSBenefits).Add(taxable.OtherTaxableIncome).
+		Add(taxable.LongTermCapitalGains).Add(taxable.QualifiedDividends)
+}
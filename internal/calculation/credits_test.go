@@ -0,0 +1,116 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaversCredit(t *testing.T) {
+	contributions := []decimal.Decimal{decimal.NewFromInt(5000), decimal.NewFromInt(1000)}
+
+	// 50% of the first $2,000 each, then 20% and 10% as AGI rises
+	assert.Equal(t, "1500", SaversCredit(decimal.NewFromInt(40000), contributions, "married_filing_jointly").String())
+	assert.Equal(t, "600", SaversCredit(decimal.NewFromInt(50000), contributions, "married_filing_jointly").String())
+	assert.Equal(t, "300", SaversCredit(decimal.NewFromInt(60000), contributions, "married_filing_jointly").String())
+	assert.True(t, SaversCredit(decimal.NewFromInt(80000), contributions, "married_filing_jointly").IsZero())
+	assert.True(t, SaversCredit(decimal.NewFromInt(40000), contributions, "single").IsZero())
+}
+
+func TestPremiumTaxCredit(t *testing.T) {
+	benchmark := decimal.NewFromInt(20000)
+
+	// A couple at 200% of the poverty line ($42,300) pays 6.6% of income
+	assert.Equal(t, "17208.2", PremiumTaxCredit(benchmark, decimal.NewFromInt(42300), 2).String())
+
+	// Below the poverty line or above 400% of it there is no credit
+	assert.True(t, PremiumTaxCredit(benchmark, decimal.NewFromInt(20000), 2).IsZero())
+	assert.True(t, PremiumTaxCredit(benchmark, decimal.NewFromInt(90000), 2).IsZero())
+
+	// No credit when the expected contribution covers the benchmark premium
+	assert.True(t, PremiumTaxCredit(decimal.NewFromInt(2000), decimal.NewFromInt(42300), 2).IsZero())
+}
+
+func TestProjectionAppliesLeanYearCredits(t *testing.T) {
+	amount := func(v float64) *decimal.Decimal { d := decimal.NewFromFloat(v); return &d }
+	marketplace := &domain.HealthcareConfig{PreMedicareCoverage: "marketplace", PreMedicareMonthlyPremium: decimal.NewFromInt(800)}
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{Name: "Alex", BirthDate: time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC), IsFederal: true, CurrentSalary: amount(45000), TSPContributionPercent: amount(0.05), Healthcare: marketplace},
+			{Name: "Blair", BirthDate: time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC), Healthcare: marketplace},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 1}
+	ce := NewCalculationEngine()
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 1)
+	cf := projection[0]
+
+	// $2,250 of contributions earn 50% of the first $2,000
+	assert.Equal(t, "1000", cf.SaversCredit.String())
+	assert.Equal(t, "500", cf.FederalTax.String())
+
+	// At about 213% of the poverty line most of the $19,200 premium is covered
+	assert.True(t, cf.PremiumTaxCredit.GreaterThan(decimal.NewFromInt(15000)))
+	assert.True(t, cf.HealthcareCosts.Total.Equal(cf.HealthcareCosts.MarketplacePremium.Sub(cf.PremiumTaxCredit)))
+}
+
+func TestProjectionMarketplaceAfterFEHB(t *testing.T) {
+	premium, tsp := decimal.NewFromInt(250), decimal.NewFromInt(300000)
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{Name: "Sam", BirthDate: time.Date(1960, 1, 15, 0, 0, 0, 0, time.UTC), IsPrimaryFEHBHolder: true, FEHBPremiumPerPayPeriod: &premium,
+				TSPBalanceTraditional: &tsp, ExternalPension: &domain.ExternalPension{MonthlyBenefit: decimal.NewFromInt(4000), StartAge: 62}},
+			{Name: "Casey", BirthDate: time.Date(1966, 1, 15, 0, 0, 0, 0, time.UTC), Healthcare: &domain.HealthcareConfig{
+				PreMedicareCoverage: "fehb", PreMedicareMonthlyPremium: decimal.NewFromInt(900), MarketplaceBenchmarkPremium: decimal.NewFromInt(700)}},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 1}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	project := func(coverage string, conversion int64) domain.AnnualCashFlow {
+		sam := domain.ParticipantScenario{ParticipantName: "Sam", RetirementDate: &retire, SSStartAge: 70, MedicareCoverage: coverage}
+		if conversion > 0 {
+			sam.RothConversions = &domain.RothConversionSchedule{Conversions: []domain.RothConversion{
+				{Year: ProjectionBaseYear, Amount: decimal.NewFromInt(conversion), Source: "traditional_tsp"}}}
+		}
+		scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Sam":   sam,
+			"Casey": {ParticipantName: "Casey", SSStartAge: 70},
+		}}
+		ce := NewCalculationEngine()
+		ce.ProjectionCache = nil
+		projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+		require.Len(t, projection, 1)
+		return projection[0]
+	}
+
+	// While Sam's FEHB enrollment lasts it covers Casey
+	kept := project(domain.MedicareCoverageFEHBSecondary, 0)
+	assert.True(t, kept.HealthcareCosts.MarketplacePremium.IsZero())
+	assert.True(t, kept.PremiumTaxCredit.IsZero())
+
+	// Dropping FEHB for Medigap leaves Casey to buy marketplace coverage, with a credit
+	// figured against the $700 benchmark plan
+	cf := project(domain.MedicareCoverageMedigap, 0)
+	assert.Equal(t, "10800", cf.HealthcareCosts.MarketplacePremium.String())
+	assert.True(t, cf.PremiumTaxCredit.IsPositive())
+	assert.True(t, cf.PremiumTaxCredit.LessThan(decimal.NewFromInt(8400)))
+	assert.Equal(t, "48000", cf.ACAMAGI.String())
+	assert.Equal(t, premiumTaxCreditCliff(2).Sub(cf.ACAMAGI).String(), cf.ACACliffDistance.String())
+
+	// The optimizer's room stops at the cliff, which a larger conversion crosses, losing
+	// the whole credit
+	ce := NewCalculationEngine()
+	room := conversionRoom(&cf, ce.TaxCalc.FederalTaxCalc, 22, decimal.Zero)
+	assert.Equal(t, cf.ACACliffDistance.String(), room.String())
+	converted := project(domain.MedicareCoverageMedigap, 50000)
+	assert.True(t, converted.PremiumTaxCredit.IsZero())
+	assert.True(t, converted.ACACliffDistance.IsZero())
+}
@@ -0,0 +1,46 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// SummarizeDignityFloor reports how well guaranteed income covers essential expenses
+// over the retired years of a projection; working years are paid for by salary and
+// are skipped. It returns nil when no retired year carries essential expenses.
+func SummarizeDignityFloor(projection []domain.AnnualCashFlow) *domain.DignityFloorSummary {
+	var summary *domain.DignityFloorSummary
+	coveredFrom := -1
+	for i := range projection {
+		cf := &projection[i]
+		if !cf.IsRetired || cf.GetTotalSalary().IsPositive() || !cf.EssentialExpenses.IsPositive() {
+			continue
+		}
+		if summary == nil {
+			summary = &domain.DignityFloorSummary{MinCoverage: cf.FloorCoverage, MinCoverageYear: cf.Date.Year()}
+		}
+		summary.YearsProjected++
+		if cf.FloorCoverage.LessThan(summary.MinCoverage) {
+			summary.MinCoverage = cf.FloorCoverage
+			summary.MinCoverageYear = cf.Date.Year()
+		}
+
+		if cf.FloorCoverage.GreaterThanOrEqual(decimalOne) {
+			summary.YearsCovered++
+			if coveredFrom < 0 {
+				coveredFrom = i
+			}
+		} else {
+			coveredFrom = -1
+		}
+	}
+
+	if summary != nil && coveredFrom >= 0 {
+		cf := &projection[coveredFrom]
+		summary.CoveredFromYear = cf.Date.Year()
+		summary.CoveredFromAges = make(map[string]int)
+		for _, name := range cf.GetLivingParticipants() {
+			summary.CoveredFromAges[name] = cf.Ages.Get(name)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,49 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeDignityFloor(t *testing.T) {
+	index := domain.NewParticipantIndex([]string{"Alex"})
+	coverage := []float64{0.8, 1.1, 0.95, 1.0, 1.2}
+	projection := make([]domain.AnnualCashFlow, len(coverage))
+	for i, c := range coverage {
+		cf := domain.NewAnnualCashFlowWithIndex(i+1, time.Date(2030+i, 1, 1, 0, 0, 0, 0, time.UTC), index)
+		cf.Ages.Set("Alex", 67+i)
+		cf.IsRetired = true
+		cf.EssentialExpenses = decimal.NewFromInt(50000)
+		cf.FloorCoverage = decimal.NewFromFloat(c)
+		projection[i] = *cf
+	}
+
+	floor := SummarizeDignityFloor(projection)
+	require.NotNil(t, floor)
+	assert.Equal(t, 3, floor.YearsCovered)
+	assert.Equal(t, 5, floor.YearsProjected)
+	assert.Equal(t, "0.8", floor.MinCoverage.String())
+	assert.Equal(t, 2030, floor.MinCoverageYear)
+	// The dip in 2032 restarts the covered run
+	assert.Equal(t, 2033, floor.CoveredFromYear)
+	assert.Equal(t, map[string]int{"Alex": 70}, floor.CoveredFromAges)
+
+	// Working years are left out of the summary
+	projection[0].IsRetired = false
+	floor = SummarizeDignityFloor(projection)
+	assert.Equal(t, 4, floor.YearsProjected)
+	assert.Equal(t, "0.95", floor.MinCoverage.String())
+
+	projection[4].FloorCoverage = decimal.NewFromFloat(0.9)
+	assert.Zero(t, SummarizeDignityFloor(projection).CoveredFromYear)
+
+	for i := range projection {
+		projection[i].EssentialExpenses = decimal.Zero
+	}
+	assert.Nil(t, SummarizeDignityFloor(projection))
+}
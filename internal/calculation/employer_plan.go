@@ -0,0 +1,145 @@
+package calculation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// employerPlanBalance returns the participant's employer plan balance, vested or not
+func (st *participantState) employerPlanBalance() decimal.Decimal {
+	return st.planBalanceTraditional.Add(st.planBalanceRoth).Add(st.planBalanceEmployer)
+}
+
+// initEmployerPlan loads the participant's starting employer plan balances
+func (st *participantState) initEmployerPlan(plan *domain.EmployerPlan) {
+	if plan == nil {
+		return
+	}
+	employer := decimal.Min(decimal.Max(plan.BalanceEmployer, decimalZero), plan.BalanceTraditional)
+	st.planBalanceTraditional = plan.BalanceTraditional.Sub(employer)
+	st.planBalanceEmployer = employer
+	st.planBalanceRoth = plan.BalanceRoth
+}
+
+// contributeEmployerPlan makes a year's deferrals to the participant's employer plan and
+// the employer's match. Pre-tax deferrals go first; together they may not exceed room,
+// the part of the elective deferral limit left after any TSP contributions.
+func (st *participantState) contributeEmployerPlan(cf *domain.AnnualCashFlow, p *domain.Participant, salary, room decimal.Decimal, year int) {
+	plan := p.EmployerPlan
+	if plan == nil || !salary.IsPositive() || st.planSeparated {
+		return
+	}
+	room = decimal.Max(room, decimalZero)
+	requested := decimalZero
+	deferral := func(percent decimal.Decimal) decimal.Decimal {
+		if !percent.IsPositive() {
+			return decimalZero
+		}
+		amount := salary.Mul(percent)
+		requested = requested.Add(amount)
+		amount = decimal.Min(amount, room)
+		room = room.Sub(amount)
+		return amount
+	}
+	preTax := deferral(plan.ContributionPercent)
+	roth := deferral(plan.RothContributionPercent)
+	st.planBalanceTraditional = st.planBalanceTraditional.Add(preTax)
+	st.planBalanceRoth = st.planBalanceRoth.Add(roth)
+	cf.EmployerPlanContributions.Set(p.Name, preTax.Add(roth))
+	cf.EmployerPlanPreTax = cf.EmployerPlanPreTax.Add(preTax)
+
+	// The match follows what was actually deferred, tier by tier
+	deferredPercent := preTax.Add(roth).Div(salary)
+	matchPercent := decimalZero
+	for _, tier := range plan.Match {
+		if !deferredPercent.IsPositive() {
+			break
+		}
+		matched := decimal.Min(deferredPercent, decimal.Max(tier.PercentOfPay, decimalZero))
+		matchPercent = matchPercent.Add(matched.Mul(tier.Rate))
+		deferredPercent = deferredPercent.Sub(matched)
+	}
+	st.planBalanceEmployer = st.planBalanceEmployer.Add(salary.Mul(matchPercent))
+
+	if excess := requested.Sub(preTax).Sub(roth); excess.GreaterThanOrEqual(decimalOne) {
+		cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+			Year:        year,
+			Participant: p.Name,
+			Code:        domain.WarningContributionLimit,
+			Amount:      excess,
+			Message: fmt.Sprintf("%s: configured employer plan deferrals exceed the elective deferral limit; $%s not contributed",
+				p.Label(), excess.StringFixed(0)),
+		})
+	}
+}
+
+// growEmployerPlan applies a year's return to every employer plan balance
+func (st *participantState) growEmployerPlan(rate decimal.Decimal) {
+	st.planBalanceTraditional = st.planBalanceTraditional.Mul(onePlus(rate))
+	st.planBalanceRoth = st.planBalanceRoth.Mul(onePlus(rate))
+	st.planBalanceEmployer = st.planBalanceEmployer.Mul(onePlus(rate))
+}
+
+// employerPlanVestedShare returns the share of employer money vested after service
+// through asOf
+func employerPlanVestedShare(p *domain.Participant, asOf time.Time) decimal.Decimal {
+	plan := p.EmployerPlan
+	if plan == nil || plan.VestingYears <= 0 {
+		return decimalOne
+	}
+	start := plan.ServiceStartDate
+	if start == nil {
+		start = p.HireDate
+	}
+	if start == nil {
+		return decimalOne
+	}
+	years := asOf.Year() - start.Year()
+	if asOf.YearDay() < start.YearDay() {
+		years--
+	}
+	switch plan.Vesting {
+	case domain.VestingCliff:
+		if years >= plan.VestingYears {
+			return decimalOne
+		}
+		return decimalZero
+	case domain.VestingGraded:
+		if years >= plan.VestingYears {
+			return decimalOne
+		}
+		return decimal.NewFromInt(int64(max(years, 0))).Div(decimal.NewFromInt(int64(plan.VestingYears)))
+	default:
+		return decimalOne
+	}
+}
+
+// separateFromEmployerPlan rolls the vested employer plan balance over to the
+// participant's IRAs on leaving the employer at separation. Unvested employer money is
+// forfeited and reported.
+func (st *participantState) separateFromEmployerPlan(cf *domain.AnnualCashFlow, p *domain.Participant, separation time.Time, year int) {
+	if st.planSeparated {
+		return
+	}
+	st.planSeparated = true
+	vested := st.planBalanceEmployer.Mul(employerPlanVestedShare(p, separation))
+	forfeited := st.planBalanceEmployer.Sub(vested)
+	st.iraBalanceTraditional = st.iraBalanceTraditional.Add(st.planBalanceTraditional).Add(vested)
+	st.iraBalanceRoth = st.iraBalanceRoth.Add(st.planBalanceRoth)
+	st.rothIRA.contribute(st.planBalanceRoth)
+	st.planBalanceTraditional, st.planBalanceRoth, st.planBalanceEmployer = decimalZero, decimalZero, decimalZero
+
+	if forfeited.GreaterThanOrEqual(decimalOne) {
+		cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+			Year:        year,
+			Participant: p.Name,
+			Code:        domain.WarningVestingForfeiture,
+			Amount:      forfeited,
+			Message: fmt.Sprintf("%s: $%s of unvested employer contributions forfeited on leaving the employer",
+				p.Label(), forfeited.StringFixed(0)),
+		})
+	}
+}
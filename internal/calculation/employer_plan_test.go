@@ -0,0 +1,96 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionEmployerPlanMatchAndVesting(t *testing.T) {
+	value := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	serviceStart := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &domain.EmployerPlan{
+		BalanceTraditional:  decimal.NewFromInt(50000),
+		BalanceEmployer:     decimal.NewFromInt(10000),
+		ContributionPercent: decimal.NewFromFloat(0.06),
+		Match: []domain.EmployerMatchTier{
+			{Rate: decimal.NewFromInt(1), PercentOfPay: decimal.NewFromFloat(0.03)},
+			{Rate: decimal.NewFromFloat(0.5), PercentOfPay: decimal.NewFromFloat(0.02)},
+		},
+		Vesting:          domain.VestingGraded,
+		VestingYears:     5,
+		ServiceStartDate: &serviceStart,
+	}
+	participant := domain.Participant{Name: "Sam", BirthDate: time.Date(1980, 6, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: value(100000)}
+	withPlan := participant
+	withPlan.EmployerPlan = plan
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         3,
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	retire := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Sam": {ParticipantName: "Sam", RetirementDate: &retire, SSStartAge: 67},
+	}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	project := func(p domain.Participant) []domain.AnnualCashFlow {
+		household := &domain.Household{FilingStatus: "single", Participants: []domain.Participant{p}}
+		projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+		require.Len(t, projection, 3)
+		return projection
+	}
+	projection := project(withPlan)
+	base := project(participant)
+
+	// 6% deferred is matched 100% on the first 3% and 50% on the next 2%: a 4% match
+	first := projection[0]
+	assert.Equal(t, "6000", first.EmployerPlanContributions.Get("Sam").String())
+	expected := decimal.NewFromInt(50000 + 6000 + 4000).Mul(decimal.NewFromFloat(1.05))
+	assert.Equal(t, expected.StringFixed(2), first.EmployerPlanBalances.Get("Sam").StringFixed(2))
+
+	// Pre-tax deferrals are excluded from taxable wages and withheld from take-home pay
+	assert.Equal(t, "-6000.00", first.FederalTaxableIncome.Sub(base[0].FederalTaxableIncome).StringFixed(2))
+	assert.True(t, first.CalculateTotalDeductions().GreaterThan(base[0].CalculateTotalDeductions()))
+
+	// Leaving at the end of 2026 after four full years of service vests 80% of the
+	// employer money; the rest is forfeited and the vested balance rolls over to the IRA
+	second := projection[1]
+	employer := decimal.NewFromInt(10000).Mul(decimal.NewFromFloat(1.05)).Add(decimal.NewFromInt(4000).Mul(decimal.NewFromFloat(1.05))).
+		Add(second.Salaries.Get("Sam").Mul(decimal.NewFromFloat(0.04))).Mul(decimal.NewFromFloat(1.05))
+	forfeited := employer.Mul(decimal.NewFromFloat(0.2))
+	require.Len(t, second.Warnings, 1)
+	assert.Equal(t, domain.WarningVestingForfeiture, second.Warnings[0].Code)
+	assert.Equal(t, forfeited.StringFixed(2), second.Warnings[0].Amount.StringFixed(2))
+	assert.True(t, second.EmployerPlanBalances.Get("Sam").IsZero())
+	rolled := second.IRABalances.Get("Sam")
+	assert.True(t, rolled.IsPositive())
+	assert.True(t, projection[2].EmployerPlanContributions.Get("Sam").IsZero())
+}
+
+func TestEmployerPlanDeferralLimit(t *testing.T) {
+	p := &domain.Participant{Name: "Sam", EmployerPlan: &domain.EmployerPlan{
+		ContributionPercent:     decimal.NewFromFloat(0.10),
+		RothContributionPercent: decimal.NewFromFloat(0.10),
+		Match:                   []domain.EmployerMatchTier{{Rate: decimal.NewFromFloat(0.5), PercentOfPay: decimal.NewFromFloat(0.06)}},
+	}}
+	cf := domain.NewAnnualCashFlow(1, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), []string{"Sam"})
+	st := &participantState{}
+
+	// $5,000 of the $23,500 limit already went to the TSP: $10,000 pre-tax fits and the
+	// Roth deferral is held to the remaining $8,500
+	st.contributeEmployerPlan(cf, p, decimal.NewFromInt(100000), ElectiveDeferralLimit(45).Sub(decimal.NewFromInt(5000)), 2025)
+
+	assert.Equal(t, "18500", cf.EmployerPlanContributions.Get("Sam").String())
+	assert.Equal(t, "10000", cf.EmployerPlanPreTax.String())
+	assert.Equal(t, "8500", st.planBalanceRoth.String())
+	assert.Equal(t, "3000", st.planBalanceEmployer.String())
+	require.Len(t, cf.Warnings, 1)
+	assert.Equal(t, domain.WarningContributionLimit, cf.Warnings[0].Code)
+	assert.Equal(t, "1500", cf.Warnings[0].Amount.String())
+}
@@ -23,14 +23,19 @@ func NewNetIncomeCalculator(taxCalc *ComprehensiveTaxCalculator, logger Logger)
 
 // CalculationEngine orchestrates all retirement calculations
 type CalculationEngine struct {
-	TaxCalc               *ComprehensiveTaxCalculator
-	MedicareCalc          *MedicareCalculator
-	LifecycleFundLoader   *LifecycleFundLoader
-	NetIncomeCalc         *NetIncomeCalculator
-	HistoricalData        *HistoricalDataManager
-	MonteCarloFundReturns map[string]decimal.Decimal // Monte Carlo generated fund returns for TSP allocation calculations
-	Debug                 bool                       // Enable debug output for detailed calculations
-	Logger                Logger
+	TaxCalc                  *ComprehensiveTaxCalculator
+	MedicareCalc             *MedicareCalculator
+	LifecycleFundLoader      *LifecycleFundLoader
+	NetIncomeCalc            *NetIncomeCalculator
+	HistoricalData           *HistoricalDataManager
+	ProjectionCache          *ProjectionCache           // Working years shared across scenarios; nil disables caching
+	MonteCarloFundReturns    map[string]decimal.Decimal // Monte Carlo generated fund returns for TSP allocation calculations
+	AdditionalOrdinaryIncome map[int]decimal.Decimal    // Ordinary income added to a calendar year's taxes, used by marginal rate sweeps
+	Debug                    bool                       // Enable debug output for detailed calculations
+	solvedWithdrawals        map[int]decimal.Decimal    // Trial spending_target TSP withdrawals by calendar year, set by the solver
+	magiReserve              map[int]decimal.Decimal    // MAGI held back below a scenario's ceiling by calendar year, raised when a projection overshoots it
+	Timings                  *TimingRecorder            // Per-scenario and per-subsystem run times; nil disables timing
+	Logger                   Logger
 }
 
 // NewCalculationEngine creates a new calculation engine
@@ -38,10 +43,11 @@ func NewCalculationEngine() *CalculationEngine {
 	taxCalc := NewComprehensiveTaxCalculator()
 	logger := NopLogger{}
 	return &CalculationEngine{
-		TaxCalc:       taxCalc,
-		MedicareCalc:  NewMedicareCalculator(),
-		NetIncomeCalc: NewNetIncomeCalculator(taxCalc, logger),
-		Logger:        logger,
+		TaxCalc:         taxCalc,
+		MedicareCalc:    NewMedicareCalculator(),
+		NetIncomeCalc:   NewNetIncomeCalculator(taxCalc, logger),
+		ProjectionCache: NewProjectionCache(),
+		Logger:          logger,
 	}
 }
 
@@ -54,6 +60,7 @@ func NewCalculationEngineWithConfig(federalRules domain.FederalRules) *Calculati
 		MedicareCalc:        NewMedicareCalculatorWithConfig(federalRules.MedicareConfig),
 		LifecycleFundLoader: NewLifecycleFundLoader("data"),
 		NetIncomeCalc:       NewNetIncomeCalculator(taxCalc, logger),
+		ProjectionCache:     NewProjectionCache(),
 		Logger:              logger,
 	}
 
@@ -75,6 +82,17 @@ func (ce *CalculationEngine) SetLogger(l Logger) {
 	ce.Logger = l
 }
 
+// SetDataPath points the engine at a data directory and reloads lifecycle fund allocations from it.
+func (ce *CalculationEngine) SetDataPath(dataPath string) {
+	ce.LifecycleFundLoader = NewLifecycleFundLoader(dataPath)
+	if ce.ProjectionCache != nil {
+		ce.ProjectionCache.Reset()
+	}
+	if err := ce.LifecycleFundLoader.LoadAllLifecycleFunds(); err != nil {
+		ce.Logger.Warnf("Failed to load lifecycle fund data: %v", err)
+	}
+}
+
 // RunScenarioAuto automatically detects the configuration format and runs the appropriate scenario
 func (ce *CalculationEngine) RunScenarioAuto(ctx context.Context, config *domain.Configuration, scenarioIndex int) (*domain.ScenarioSummary, error) {
 	if scenarioIndex >= len(config.Scenarios) {
@@ -85,6 +103,11 @@ func (ce *CalculationEngine) RunScenarioAuto(ctx context.Context, config *domain
 
 // RunGenericScenario calculates a complete retirement scenario using the generic household format
 func (ce *CalculationEngine) RunGenericScenario(ctx context.Context, config *domain.Configuration, scenario *domain.GenericScenario) (*domain.ScenarioSummary, error) {
+	if err := CheckScenarioFeasibility(config.Household, scenario, &config.GlobalAssumptions); err != nil {
+		return nil, err
+	}
+	defer ce.startTiming(scenario, TimingTotal)()
+
 	// Use the new generic projection method directly
 	projection := ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
 
@@ -108,7 +131,7 @@ func (ce *CalculationEngine) RunGenericScenario(ctx context.Context, config *dom
 	netIncome2040 := ce.getNetIncomeForYear(projection, 2040)
 
 	// Calculate pre-retirement baseline projections with COLA growth
-	currentNetIncome := ce.calculateCurrentNetIncomeGeneric(config.Household)
+	currentNetIncome := ce.currentNetIncome(config.Household)
 	preRetirement2030 := ce.projectPreRetirementNetIncome(currentNetIncome, 2030, config.GlobalAssumptions.COLAGeneralRate)
 	preRetirement2035 := ce.projectPreRetirementNetIncome(currentNetIncome, 2035, config.GlobalAssumptions.COLAGeneralRate)
 	preRetirement2040 := ce.projectPreRetirementNetIncome(currentNetIncome, 2040, config.GlobalAssumptions.COLAGeneralRate)
@@ -127,6 +150,17 @@ func (ce *CalculationEngine) RunGenericScenario(ctx context.Context, config *dom
 		PreRetirementNet2040: preRetirement2040,
 	}
 
+	for i := range projection {
+		if projection[i].IsTransitionYear() {
+			summary.TransitionYear = projection[i].Date.Year()
+			break
+		}
+	}
+	if i := domain.FirstFullRetirementYear(projection); i >= 0 {
+		summary.FirstFullRetirementYear = projection[i].Date.Year()
+		summary.FirstFullYearNetIncome = projection[i].NetIncome
+	}
+
 	// Calculate total lifetime income (present value)
 	var totalPV decimal.Decimal
 	discountRate := decimal.NewFromFloat(0.03) // 3% discount rate
@@ -161,12 +195,20 @@ func (ce *CalculationEngine) RunGenericScenario(ctx context.Context, config *dom
 	mc := NewMedicareCalculator()
 	summary.IRMAAAnalysis = AnalyzeIRMAARisk(projection, isMarried, mc)
 
+	for i := range projection {
+		summary.Warnings = append(summary.Warnings, projection[i].Warnings...)
+	}
+	if summary.HorizonWarnings = ProjectionHorizonWarnings(config.Household, scenario, &config.GlobalAssumptions); len(summary.HorizonWarnings) > 0 {
+		summary.MinimumProjectionYears = MinimumProjectionYears(config.Household, scenario)
+	}
+	summary.DignityFloor = SummarizeDignityFloor(projection)
+	summary.Spending = SummarizeSpending(projection)
+	summary.LifetimeTaxes = SummarizeLifetimeTaxes(projection)
+	summary.SurvivorElections = SurvivorElectionSummaries(config.Household, scenario)
+
 	return summary, nil
 }
 
-// RunScenario calculates a complete retirement scenario (legacy format)
-// (Legacy RunScenario removed)
-
 // getNetIncomeForYear finds the net income for a specific calendar year in the projection
 func (ce *CalculationEngine) getNetIncomeForYear(projection []domain.AnnualCashFlow, targetYear int) decimal.Decimal {
 	for _, year := range projection {
@@ -224,6 +266,21 @@ func (ce *CalculationEngine) calculateDeterministicSuccessRate(projection []doma
 	return successRate
 }
 
+// currentNetIncome returns the household's current net income, computed once per
+// household when the projection cache is enabled
+func (ce *CalculationEngine) currentNetIncome(household *domain.Household) decimal.Decimal {
+	if ce.ProjectionCache == nil {
+		return ce.calculateCurrentNetIncomeGeneric(household)
+	}
+	key := householdCacheKey(household)
+	if key == "" {
+		return ce.calculateCurrentNetIncomeGeneric(household)
+	}
+	return ce.ProjectionCache.baseline(key, func() decimal.Decimal {
+		return ce.calculateCurrentNetIncomeGeneric(household)
+	})
+}
+
 // calculateCurrentNetIncomeGeneric calculates current net income for a household using generic participants
 func (ce *CalculationEngine) calculateCurrentNetIncomeGeneric(household *domain.Household) decimal.Decimal {
 	// Calculate gross income from all participants
@@ -317,14 +374,22 @@ func (ce *CalculationEngine) RunScenarios(config *domain.Configuration) (*domain
 		}
 		scenarios[i] = *summary
 	}
+	if ce.Debug {
+		for _, t := range ce.Timings.Timings() {
+			ce.Logger.Debugf("Timing %s: total %s, projection %s, taxes %s, healthcare %s",
+				t.Scenario, t.Total.Round(time.Microsecond), t.Projection.Round(time.Microsecond),
+				t.Taxes.Round(time.Microsecond), t.Healthcare.Round(time.Microsecond))
+		}
+	}
 
 	// Calculate baseline (current net income) - use format-appropriate method
-	baselineNetIncome := ce.calculateCurrentNetIncomeGeneric(config.Household)
+	baselineNetIncome := ce.currentNetIncome(config.Household)
 
 	comparison := &domain.ScenarioComparison{
 		BaselineNetIncome: baselineNetIncome,
 		Scenarios:         scenarios,
 		Assumptions:       config.GlobalAssumptions.GenerateAssumptions(),
+		ParticipantLabels: config.Household.ParticipantLabels(),
 	}
 
 	// Generate impact analysis
@@ -369,21 +434,21 @@ func (nic *NetIncomeCalculator) Calculate(robert, dawn *domain.Employee, debug b
 	if debug {
 		nic.Logger.Debugf("CURRENT NET INCOME CALCULATION BREAKDOWN (LEGACY):")
 		nic.Logger.Debugf("===================================================")
-		nic.Logger.Debugf("Participant 1 Salary:   $%s", robert.CurrentSalary.StringFixed(2))
-		nic.Logger.Debugf("Participant 2 Salary:   $%s", dawn.CurrentSalary.StringFixed(2))
-		nic.Logger.Debugf("Combined Gross Income:  $%s", grossIncome.StringFixed(2))
+		nic.Logger.Debugf("Participant 1 Salary:   $%s", domain.FormatAmount(robert.CurrentSalary))
+		nic.Logger.Debugf("Participant 2 Salary:   $%s", domain.FormatAmount(dawn.CurrentSalary))
+		nic.Logger.Debugf("Combined Gross Income:  $%s", domain.FormatAmount(grossIncome))
 		nic.Logger.Debugf("")
 		nic.Logger.Debugf("DEDUCTIONS:")
-		nic.Logger.Debugf("  Federal Tax:          $%s", federalTax.StringFixed(2))
-		nic.Logger.Debugf("  State Tax:            $%s", stateTax.StringFixed(2))
-		nic.Logger.Debugf("  Local Tax:            $%s", localTax.StringFixed(2))
-		nic.Logger.Debugf("  FICA Tax:             $%s", ficaTax.StringFixed(2))
-		nic.Logger.Debugf("  FEHB Premium:         $%s", fehbPremium.StringFixed(2))
-		nic.Logger.Debugf("  TSP Contributions:    $%s", tspContributions.StringFixed(2))
-		nic.Logger.Debugf("  Total Deductions:     $%s", federalTax.Add(stateTax).Add(localTax).Add(ficaTax).Add(fehbPremium).Add(tspContributions).StringFixed(2))
+		nic.Logger.Debugf("  Federal Tax:          $%s", domain.FormatAmount(federalTax))
+		nic.Logger.Debugf("  State Tax:            $%s", domain.FormatAmount(stateTax))
+		nic.Logger.Debugf("  Local Tax:            $%s", domain.FormatAmount(localTax))
+		nic.Logger.Debugf("  FICA Tax:             $%s", domain.FormatAmount(ficaTax))
+		nic.Logger.Debugf("  FEHB Premium:         $%s", domain.FormatAmount(fehbPremium))
+		nic.Logger.Debugf("  TSP Contributions:    $%s", domain.FormatAmount(tspContributions))
+		nic.Logger.Debugf("  Total Deductions:     $%s", domain.FormatAmount(federalTax.Add(stateTax).Add(localTax).Add(ficaTax).Add(fehbPremium).Add(tspContributions)))
 		nic.Logger.Debugf("")
-		nic.Logger.Debugf("CURRENT NET TAKE-HOME:  $%s", netIncome.StringFixed(2))
-		nic.Logger.Debugf("Monthly Take-Home:      $%s", netIncome.Div(decimal.NewFromInt(12)).StringFixed(2))
+		nic.Logger.Debugf("CURRENT NET TAKE-HOME:  $%s", domain.FormatAmount(netIncome))
+		nic.Logger.Debugf("Monthly Take-Home:      $%s", domain.FormatAmount(netIncome.Div(decimal.NewFromInt(12))))
 		nic.Logger.Debugf("")
 	}
 
@@ -109,6 +109,168 @@ func TestCalculationEngine_RunScenarioAuto_ValidIndex(t *testing.T) {
 	assert.Equal(t, "test-scenario", result.Name, "Should have correct scenario name")
 }
 
+func TestCalculationEngine_WithdrawalShortfallWarnings(t *testing.T) {
+	retire := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := decimal.NewFromInt(5000)
+	config := &domain.Configuration{
+		Household: &domain.Household{
+			FilingStatus: "single",
+			Participants: []domain.Participant{{
+				Name:                   "Pat",
+				BirthDate:              time.Date(1963, 1, 1, 0, 0, 0, 0, time.UTC),
+				HireDate:               timePtr(time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)),
+				CurrentSalary:          decimalPtr(decimal.NewFromInt(90000)),
+				High3Salary:            decimalPtr(decimal.NewFromInt(88000)),
+				TSPBalanceTraditional:  decimalPtr(decimal.NewFromInt(100000)),
+				TSPBalanceRoth:         decimalPtr(decimal.Zero),
+				TSPContributionPercent: decimalPtr(decimal.Zero),
+				IsFederal:              true,
+			}},
+		},
+		GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 5},
+		Scenarios: []domain.GenericScenario{{
+			Name: "overspend",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Pat": {ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67, TSPWithdrawalStrategy: "need_based", TSPWithdrawalTargetMonthly: &target},
+			},
+		}},
+	}
+
+	result, err := NewCalculationEngine().RunScenarioAuto(context.Background(), config, 0)
+	assert.NoError(t, err)
+
+	// $60k a year from a $100k balance with no growth runs out in the second year
+	if assert.Len(t, result.Warnings, 4) {
+		first := result.Warnings[0]
+		assert.Equal(t, 2026, first.Year)
+		assert.Equal(t, "Pat", first.Participant)
+		assert.Equal(t, domain.WarningWithdrawalShortfall, first.Code)
+		assert.True(t, first.Amount.Equal(decimal.NewFromInt(20000)), "got %s", first.Amount)
+		assert.True(t, result.Warnings[3].Amount.Equal(decimal.NewFromInt(60000)))
+	}
+	assert.Empty(t, result.Projection[0].Warnings)
+	assert.Len(t, result.Projection[1].Warnings, 1)
+}
+
+func TestCalculationEngine_MilitaryDeposit(t *testing.T) {
+	retire := time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC)
+	newConfig := func(deposit *domain.MilitaryDeposit) *domain.Configuration {
+		return &domain.Configuration{
+			Household: &domain.Household{
+				FilingStatus: "single",
+				Participants: []domain.Participant{{
+					Name:                   "Pat",
+					BirthDate:              time.Date(1963, 1, 1, 0, 0, 0, 0, time.UTC),
+					HireDate:               timePtr(time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)),
+					CurrentSalary:          decimalPtr(decimal.NewFromInt(90000)),
+					High3Salary:            decimalPtr(decimal.NewFromInt(88000)),
+					TSPBalanceTraditional:  decimalPtr(decimal.NewFromInt(300000)),
+					TSPBalanceRoth:         decimalPtr(decimal.Zero),
+					TSPContributionPercent: decimalPtr(decimal.Zero),
+					IsFederal:              true,
+					MilitaryDeposit:        deposit,
+				}},
+			},
+			GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 5},
+			Scenarios: []domain.GenericScenario{{
+				Name: "buyback",
+				ParticipantScenarios: map[string]domain.ParticipantScenario{
+					"Pat": {ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67},
+				},
+			}},
+		}
+	}
+
+	engine := NewCalculationEngine()
+	base, err := engine.RunScenarioAuto(context.Background(), newConfig(nil), 0)
+	assert.NoError(t, err)
+	deposit := &domain.MilitaryDeposit{Years: decimal.NewFromInt(4), Amount: decimal.NewFromInt(12000), PaymentYear: 2026, PaidFrom: domain.MilitaryDepositFromTSP}
+	buyback, err := engine.RunScenarioAuto(context.Background(), newConfig(deposit), 0)
+	assert.NoError(t, err)
+
+	// Four more years on an $88k high-3 at the 1.1% multiplier
+	extra := buyback.Projection[4].GetTotalPension().Sub(base.Projection[4].GetTotalPension())
+	assert.Equal(t, "3872", extra.Round(0).String())
+
+	// The deposit comes out of the TSP in 2026 and is taxed as ordinary income
+	assert.True(t, buyback.Projection[1].MilitaryDeposits.Equal(decimal.NewFromInt(12000)))
+	assert.True(t, buyback.Projection[0].MilitaryDeposits.IsZero())
+	assert.True(t, buyback.Projection[1].GetTotalTSPBalance().LessThan(base.Projection[1].GetTotalTSPBalance()))
+	assert.True(t, buyback.Projection[1].FederalTax.GreaterThan(base.Projection[1].FederalTax))
+
+	// Declining the deposit in a scenario matches having no military service
+	declined := newConfig(deposit)
+	ps := declined.Scenarios[0].ParticipantScenarios["Pat"]
+	ps.DeclineMilitaryDeposit = true
+	declined.Scenarios[0].ParticipantScenarios["Pat"] = ps
+	skipped, err := engine.RunScenarioAuto(context.Background(), declined, 0)
+	assert.NoError(t, err)
+	assert.True(t, skipped.Projection[4].GetTotalPension().Equal(base.Projection[4].GetTotalPension()))
+	assert.True(t, skipped.Projection[1].MilitaryDeposits.IsZero())
+	assert.NotNil(t, declined.Household.Participants[0].MilitaryDeposit)
+
+	// Without enough in the taxable account the unpaid remainder is flagged
+	deposit.PaidFrom = domain.MilitaryDepositFromTaxable
+	short, err := engine.RunScenarioAuto(context.Background(), newConfig(deposit), 0)
+	assert.NoError(t, err)
+	if assert.Len(t, short.Warnings, 1) {
+		assert.Equal(t, domain.WarningDepositShortfall, short.Warnings[0].Code)
+		assert.True(t, short.Warnings[0].Amount.Equal(decimal.NewFromInt(12000)))
+	}
+}
+
+func TestCalculationEngine_AlreadyRetiredParticipant(t *testing.T) {
+	config := &domain.Configuration{
+		Household: &domain.Household{
+			FilingStatus: "single",
+			Participants: []domain.Participant{{
+				Name:                  "Pat",
+				BirthDate:             time.Date(1960, 3, 1, 0, 0, 0, 0, time.UTC),
+				HireDate:              timePtr(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)),
+				TSPBalanceTraditional: decimalPtr(decimal.NewFromInt(200000)),
+				TSPBalanceRoth:        decimalPtr(decimal.Zero),
+				SSBenefitFRA:          decimal.NewFromInt(2000),
+				IsFederal:             true,
+				CurrentRetirement: &domain.CurrentRetirement{
+					RetirementDate:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+					AnnualAnnuity:    decimal.NewFromInt(30000),
+					SSMonthlyBenefit: decimal.NewFromInt(2000),
+				},
+			}},
+		},
+		GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 3, COLAGeneralRate: decimal.NewFromFloat(0.02)},
+		Scenarios: []domain.GenericScenario{{
+			Name: "retired",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Pat": {ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "4_percent_rule"},
+			},
+		}},
+	}
+
+	summary, err := NewCalculationEngine().RunScenarioAuto(context.Background(), config, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	first := summary.Projection[0]
+	assert.True(t, first.IsRetired)
+	assert.True(t, first.GetTotalSalary().IsZero())
+	assert.True(t, first.TotalTSPContributions.IsZero())
+	// Benefits in payment are taken as-is in the first year and COLA-adjusted after
+	assert.Equal(t, "30000", first.Pensions.Get("Pat").String())
+	assert.Equal(t, "24000", first.SSBenefits.Get("Pat").String())
+	assert.Equal(t, "30600", summary.Projection[1].Pensions.Get("Pat").String())
+	assert.True(t, first.TSPWithdrawals.Get("Pat").IsPositive())
+
+	// A retiree cannot be given a new retirement date
+	ps := config.Scenarios[0].ParticipantScenarios["Pat"]
+	ps.RetirementDate = timePtr(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	config.Scenarios[0].ParticipantScenarios["Pat"] = ps
+	_, err = NewCalculationEngine().RunScenarioAuto(context.Background(), config, 0)
+	var fe *FeasibilityError
+	assert.ErrorAs(t, err, &fe)
+}
+
 // Helper functions for creating pointers
 func timePtr(t time.Time) *time.Time {
 	return &t
@@ -134,3 +296,155 @@ func (tl *TestLogger) Warnf(format string, args ...interface{}) {
 func (tl *TestLogger) Errorf(format string, args ...interface{}) {
 	tl.messages = append(tl.messages, "ERROR: "+format)
 }
+
+func TestCalculationEngine_DignityFloor(t *testing.T) {
+	retire := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := &domain.Configuration{
+		Household: &domain.Household{
+			FilingStatus:      "single",
+			EssentialExpenses: decimal.NewFromInt(20000),
+			Participants: []domain.Participant{{
+				Name:                   "Pat",
+				BirthDate:              time.Date(1963, 1, 1, 0, 0, 0, 0, time.UTC),
+				HireDate:               timePtr(time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)),
+				CurrentSalary:          decimalPtr(decimal.NewFromInt(90000)),
+				High3Salary:            decimalPtr(decimal.NewFromInt(88000)),
+				TSPBalanceTraditional:  decimalPtr(decimal.NewFromInt(100000)),
+				TSPBalanceRoth:         decimalPtr(decimal.Zero),
+				TSPContributionPercent: decimalPtr(decimal.Zero),
+				IsFederal:              true,
+			}},
+		},
+		GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 5, InflationRate: decimal.NewFromFloat(0.03)},
+		Scenarios: []domain.GenericScenario{{
+			Name: "pension floor",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Pat": {ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67},
+			},
+		}},
+	}
+
+	summary, err := NewCalculationEngine().RunScenarioAuto(context.Background(), config, 0)
+	assert.NoError(t, err)
+
+	for _, cf := range summary.Projection {
+		assert.True(t, cf.GuaranteedIncome.Equal(cf.GetGuaranteedIncome()))
+		assert.True(t, cf.FloorCoverage.Equal(cf.GuaranteedIncome.Div(cf.EssentialExpenses).Round(4)))
+	}
+	// Essential expenses grow with inflation
+	assert.Equal(t, "20600.00", summary.Projection[1].EssentialExpenses.StringFixed(2))
+
+	// A 30-year FERS annuity on an $88k high-3 covers $20k of essentials from the start
+	assert.NotNil(t, summary.DignityFloor)
+	assert.Equal(t, 5, summary.DignityFloor.YearsCovered)
+	assert.Equal(t, 2025, summary.DignityFloor.CoveredFromYear)
+	assert.Equal(t, map[string]int{"Pat": 62}, summary.DignityFloor.CoveredFromAges)
+}
+
+func TestCalculationEngine_SurvivorFEHBEnrollment(t *testing.T) {
+	newConfig := func(deceased string) *domain.Configuration {
+		deathAge := 67 // 2027 for Pat, 2029 for Sam
+		return &domain.Configuration{
+			Household: &domain.Household{
+				FilingStatus: "married_filing_jointly",
+				Participants: []domain.Participant{
+					{
+						Name:                    "Pat",
+						BirthDate:               time.Date(1960, 3, 1, 0, 0, 0, 0, time.UTC),
+						HireDate:                timePtr(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)),
+						TSPBalanceTraditional:   decimalPtr(decimal.Zero),
+						TSPBalanceRoth:          decimalPtr(decimal.Zero),
+						IsFederal:               true,
+						IsPrimaryFEHBHolder:     true,
+						FEHBPremiumPerPayPeriod: decimalPtr(decimal.NewFromInt(400)),
+						CurrentRetirement: &domain.CurrentRetirement{
+							RetirementDate:  time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+							AnnualAnnuity:   decimal.NewFromInt(40000),
+							SurvivorAnnuity: decimal.NewFromInt(20000),
+						},
+					},
+					{Name: "Sam", BirthDate: time.Date(1962, 5, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 5},
+			Scenarios: []domain.GenericScenario{{
+				Name: "first death",
+				ParticipantScenarios: map[string]domain.ParticipantScenario{
+					"Pat": {ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "4_percent_rule"},
+					"Sam": {ParticipantName: "Sam", SSStartAge: 67},
+				},
+				Mortality: &domain.GenericScenarioMortality{
+					Participants: map[string]*domain.MortalitySpec{deceased: {DeathAge: &deathAge}},
+				},
+			}},
+		}
+	}
+	premiums := func(config *domain.Configuration) []string {
+		summary, err := NewCalculationEngine().RunScenarioAuto(context.Background(), config, 0)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		var out []string
+		for _, cf := range summary.Projection {
+			out = append(out, cf.FEHBPremium.String())
+		}
+		return out
+	}
+
+	// The enrollee survives: the enrollment drops to self only (45% of self plus one)
+	assert.Equal(t, []string{"10400", "10400", "10400", "10400", "4680"}, premiums(newConfig("Sam")))
+
+	// The enrollee dies: the survivor annuity carries coverage over at self only
+	assert.Equal(t, []string{"10400", "10400", "4680", "4680", "4680"}, premiums(newConfig("Pat")))
+
+	// The scenario can keep the enrollment unchanged, and a configured self only premium is used
+	config := newConfig("Pat")
+	config.Scenarios[0].Mortality.Assumptions = &domain.MortalityAssumptions{FEHBSurvivorEnrollment: domain.FEHBSurvivorEnrollmentUnchanged}
+	assert.Equal(t, []string{"10400", "10400", "10400", "10400", "10400"}, premiums(config))
+
+	config = newConfig("Pat")
+	config.Household.Participants[0].FEHBSelfOnlyPremiumPerPayPeriod = decimalPtr(decimal.NewFromInt(150))
+	assert.Equal(t, []string{"10400", "10400", "3900", "3900", "3900"}, premiums(config))
+
+	// Without a survivor annuity, FEHB coverage ends with the enrollee
+	config = newConfig("Pat")
+	config.Household.Participants[0].CurrentRetirement.SurvivorAnnuity = decimal.Zero
+	assert.Equal(t, []string{"10400", "10400", "0", "0", "0"}, premiums(config))
+}
+
+func TestCalculationEngine_FirstFullRetirementYear(t *testing.T) {
+	retire := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	config := &domain.Configuration{
+		Household: &domain.Household{
+			FilingStatus: "single",
+			Participants: []domain.Participant{{
+				Name:                   "Pat",
+				BirthDate:              time.Date(1963, 1, 1, 0, 0, 0, 0, time.UTC),
+				HireDate:               timePtr(time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)),
+				CurrentSalary:          decimalPtr(decimal.NewFromInt(90000)),
+				High3Salary:            decimalPtr(decimal.NewFromInt(88000)),
+				TSPBalanceTraditional:  decimalPtr(decimal.NewFromInt(100000)),
+				TSPBalanceRoth:         decimalPtr(decimal.Zero),
+				TSPContributionPercent: decimalPtr(decimal.Zero),
+				IsFederal:              true,
+			}},
+		},
+		GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 4},
+		Scenarios: []domain.GenericScenario{{
+			Name: "mid-year",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Pat": {ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67},
+			},
+		}},
+	}
+
+	summary, err := NewCalculationEngine().RunScenarioAuto(context.Background(), config, 0)
+	assert.NoError(t, err)
+
+	// 2026 has both salary and pension; 2027 is the first year fully retired
+	startYear := summary.Projection[0].Date.Year()
+	assert.True(t, summary.Projection[2026-startYear].IsTransitionYear())
+	assert.Equal(t, 2026, summary.TransitionYear)
+	assert.Equal(t, 2027, summary.FirstFullRetirementYear)
+	assert.True(t, summary.FirstFullYearNetIncome.Equal(summary.Projection[2027-startYear].NetIncome))
+}
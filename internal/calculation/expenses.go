@@ -0,0 +1,66 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// expenseCategoryAmount returns the category's spending in projection year yr: the annual
+// amount grown from the first projection year at its own inflation rate, or by growth,
+// the cumulative general inflation, and zero outside its start and end years
+func expenseCategoryAmount(e domain.ExpenseCategory, startYear, yr int, growth decimal.Decimal) decimal.Decimal {
+	if !e.ActiveIn(startYear + yr) {
+		return decimalZero
+	}
+	if e.InflationRate != nil {
+		return e.AnnualAmount.Mul(onePlus(*e.InflationRate).Pow(decimal.NewFromInt(int64(yr))))
+	}
+	return e.AnnualAmount.Mul(growth)
+}
+
+// spendingForYear returns each expense category's spending in projection year yr, their
+// total and the total of the essential categories. Every category is scaled by factor,
+// the spending phase's and a lone survivor's adjustments.
+func spendingForYear(expenses []domain.ExpenseCategory, startYear, yr int, growth, factor decimal.Decimal) (byCategory map[string]decimal.Decimal, target, essential decimal.Decimal) {
+	byCategory = make(map[string]decimal.Decimal, len(expenses))
+	for _, e := range expenses {
+		amount := expenseCategoryAmount(e, startYear, yr, growth).Mul(factor)
+		byCategory[e.Name] = amount
+		target = target.Add(amount)
+		if e.Essential {
+			essential = essential.Add(amount)
+		}
+	}
+	return byCategory, target, essential
+}
+
+// SummarizeSpending reports how well net income funds the spending target over a
+// projection. It returns nil when no year has a spending target.
+func SummarizeSpending(projection []domain.AnnualCashFlow) *domain.SpendingSummary {
+	var summary *domain.SpendingSummary
+	for i := range projection {
+		cf := &projection[i]
+		if !cf.SpendingTarget.IsPositive() {
+			continue
+		}
+		if summary == nil {
+			summary = &domain.SpendingSummary{}
+		}
+		summary.YearsProjected++
+		summary.TotalTarget = summary.TotalTarget.Add(cf.SpendingTarget)
+		if !cf.SpendingSurplus.IsNegative() {
+			summary.YearsFunded++
+			continue
+		}
+		shortfall := cf.SpendingSurplus.Neg()
+		summary.TotalShortfall = summary.TotalShortfall.Add(shortfall)
+		if summary.FirstShortfallYear == 0 {
+			summary.FirstShortfallYear = cf.Date.Year()
+		}
+		if shortfall.GreaterThan(summary.LargestShortfall) {
+			summary.LargestShortfall = shortfall
+			summary.LargestShortfallYear = cf.Date.Year()
+		}
+	}
+	return summary
+}
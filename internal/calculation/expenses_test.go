@@ -0,0 +1,92 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendingForYear(t *testing.T) {
+	healthcare := decimal.NewFromFloat(0.05)
+	travel := decimal.Zero
+	expenses := []domain.ExpenseCategory{
+		{Name: "housing", AnnualAmount: decimal.NewFromInt(24000), EndYear: 2026, Essential: true},
+		{Name: "healthcare", AnnualAmount: decimal.NewFromInt(10000), InflationRate: &healthcare, Essential: true},
+		{Name: "travel", AnnualAmount: decimal.NewFromInt(8000), InflationRate: &travel},
+	}
+	growth := decimal.NewFromFloat(1.0609) // two years of 3% general inflation
+
+	byCategory, target, essential := spendingForYear(expenses, 2025, 2, growth, decimalOne)
+	// The mortgage is paid off after 2026; healthcare grows at its own 5%, travel is flat
+	assert.True(t, byCategory["housing"].IsZero())
+	assert.Equal(t, "11025", byCategory["healthcare"].String())
+	assert.Equal(t, "8000", byCategory["travel"].String())
+	assert.Equal(t, "19025", target.String())
+	assert.Equal(t, "11025", essential.String())
+
+	byCategory, target, _ = spendingForYear(expenses, 2025, 1, decimal.NewFromFloat(1.03), decimal.NewFromFloat(0.75))
+	// Housing follows general inflation; a lone survivor spends 75%
+	assert.Equal(t, "18540", byCategory["housing"].String())
+	assert.Equal(t, "6000", byCategory["travel"].String())
+	assert.Equal(t, "32415", target.String())
+
+	// Amounts keep full precision, so the target is exactly the sum of its categories
+	byCategory, target, _ = spendingForYear(expenses, 2025, 1, decimal.NewFromFloat(1.0333), decimal.NewFromFloat(0.3333))
+	assert.Equal(t, "8265.57336", byCategory["housing"].String())
+	assert.True(t, target.Equal(byCategory["housing"].Add(byCategory["healthcare"]).Add(byCategory["travel"])))
+}
+
+func TestSummarizeSpending(t *testing.T) {
+	index := domain.NewParticipantIndex([]string{"Alex"})
+	surplus := []int64{5000, -2000, 1000, -7000}
+	projection := make([]domain.AnnualCashFlow, len(surplus))
+	for i, s := range surplus {
+		cf := domain.NewAnnualCashFlowWithIndex(i+1, time.Date(2030+i, 1, 1, 0, 0, 0, 0, time.UTC), index)
+		cf.SpendingTarget = decimal.NewFromInt(60000)
+		cf.SpendingSurplus = decimal.NewFromInt(s)
+		projection[i] = *cf
+	}
+
+	spending := SummarizeSpending(projection)
+	require.NotNil(t, spending)
+	assert.Equal(t, 2, spending.YearsFunded)
+	assert.Equal(t, 4, spending.YearsProjected)
+	assert.Equal(t, "240000", spending.TotalTarget.String())
+	assert.Equal(t, "9000", spending.TotalShortfall.String())
+	assert.Equal(t, 2031, spending.FirstShortfallYear)
+	assert.Equal(t, "7000", spending.LargestShortfall.String())
+	assert.Equal(t, 2033, spending.LargestShortfallYear)
+
+	for i := range projection {
+		projection[i].SpendingTarget = decimal.Zero
+	}
+	assert.Nil(t, SummarizeSpending(projection))
+}
+
+func TestProjectionSpendingTarget(t *testing.T) {
+	household := cashFlowEventHousehold()
+	household.Expenses = []domain.ExpenseCategory{
+		{Name: "housing", AnnualAmount: decimal.NewFromInt(20000), Essential: true},
+		{Name: "travel", AnnualAmount: decimal.NewFromInt(500000)},
+	}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 2}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 2)
+
+	first := projection[0]
+	assert.Equal(t, "520000", first.SpendingTarget.String())
+	assert.Equal(t, first.NetIncome.Sub(first.SpendingTarget).String(), first.SpendingSurplus.String())
+	assert.True(t, first.SpendingSurplus.IsNegative())
+	// Without essential_expenses the essential categories make up the dignity floor
+	assert.Equal(t, "20000", first.EssentialExpenses.String())
+}
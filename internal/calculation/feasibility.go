@@ -0,0 +1,141 @@
+package calculation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/pkg/dateutil"
+)
+
+// FeasibilityError lists every structural problem found in a scenario so they can
+// be fixed together instead of one run at a time
+type FeasibilityError struct {
+	Scenario   string
+	Violations []string
+}
+
+func (e *FeasibilityError) Error() string {
+	return fmt.Sprintf("scenario %s is not feasible: %s", e.Scenario, strings.Join(e.Violations, "; "))
+}
+
+// CheckScenarioFeasibility verifies a scenario is structurally consistent with the
+// household and projection horizon before it is projected. Problems that would
+// otherwise produce silently odd projections (a retirement before hire or for someone
+// already retired, a Roth conversion, tax election or death date outside the horizon)
+// are collected and returned as a single *FeasibilityError.
+func CheckScenarioFeasibility(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions) error {
+	if household == nil || scenario == nil {
+		return nil
+	}
+
+	firstYear := ProjectionBaseYear
+	lastYear := ProjectionBaseYear
+	if years := ScenarioProjectionYears(scenario, assumptions); years > 0 {
+		lastYear = ProjectionBaseYear + years - 1
+	}
+
+	participants := make(map[string]*domain.Participant, len(household.Participants))
+	for i := range household.Participants {
+		participants[household.Participants[i].Name] = &household.Participants[i]
+	}
+
+	var violations []string
+	for _, name := range domain.SortedMapKeys(scenario.ParticipantScenarios) {
+		ps := scenario.ParticipantScenarios[name]
+		p, ok := participants[name]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("%s: participant not found in household", name))
+			continue
+		}
+
+		if cr := p.CurrentRetirement; cr != nil {
+			if cr.RetirementDate.Year() >= firstYear {
+				violations = append(violations, fmt.Sprintf("%s: already retired on %s, but the projection starts in %d (use a scenario retirement_date instead)",
+					name, cr.RetirementDate.Format("2006-01-02"), firstYear))
+			}
+			if ps.RetirementDate != nil || ps.SeparationDate != nil {
+				violations = append(violations, fmt.Sprintf("%s: already retired, so the scenario cannot set a retirement or separation date", name))
+			}
+		}
+		if ps.RetirementDate != nil && p.HireDate != nil && !ps.RetirementDate.After(*p.HireDate) {
+			violations = append(violations, fmt.Sprintf("%s: retirement date %s is not after hire date %s",
+				name, ps.RetirementDate.Format("2006-01-02"), p.HireDate.Format("2006-01-02")))
+		}
+		if mandatory := p.MandatoryRetirementAge(); mandatory > 0 && ps.RetirementDate != nil && p.Age(*ps.RetirementDate) > mandatory {
+			violations = append(violations, fmt.Sprintf("%s: retirement at age %d is past the special provision mandatory retirement age %d",
+				name, p.Age(*ps.RetirementDate), mandatory))
+		}
+		if ps.SeparationDate != nil && ps.AnnuityStartAge > 0 && p.IsCSRS() {
+			if ps.AnnuityStartAge < 62 {
+				violations = append(violations, fmt.Sprintf("%s: CSRS deferred annuity cannot start before 62 (got %d)", name, ps.AnnuityStartAge))
+			}
+		} else if ps.SeparationDate != nil && ps.AnnuityStartAge > 0 && p.IsFederal && p.HireDate != nil {
+			service := p.YearsOfService(*ps.SeparationDate)
+			if _, ok := DeferredAnnuityReduction(ps.AnnuityStartAge, dateutil.MinimumRetirementAge(p.BirthDate), service); !ok {
+				violations = append(violations, fmt.Sprintf("%s: deferred annuity cannot start at %d with %s years of service (needs 62 with 5, 60 with 20, or MRA with 10)",
+					name, ps.AnnuityStartAge, service.StringFixed(1)))
+			}
+		}
+		if md := p.MilitaryDeposit; md != nil && md.Amount.IsPositive() && !ps.DeclineMilitaryDeposit {
+			paid := militaryDepositYear(md, firstYear)
+			if paid < firstYear || paid > lastYear {
+				violations = append(violations, fmt.Sprintf("%s: military deposit payment year %d is outside the projection %d-%d (use amount 0 if already paid)",
+					name, paid, firstYear, lastYear))
+			}
+			separation := ps.RetirementDate
+			if ps.SeparationDate != nil {
+				separation = ps.SeparationDate
+			}
+			if separation != nil && paid > separation.Year() {
+				violations = append(violations, fmt.Sprintf("%s: military deposit must be paid before separation in %d (got %d)", name, separation.Year(), paid))
+			}
+		}
+		if ps.SSStartAge != 0 && (ps.SSStartAge < 62 || ps.SSStartAge > 70) {
+			violations = append(violations, fmt.Sprintf("%s: Social Security start age %d is outside 62-70", name, ps.SSStartAge))
+		}
+		if ps.RothConversions != nil {
+			for _, conversion := range ps.RothConversions.Conversions {
+				if conversion.Year < firstYear || conversion.Year > lastYear {
+					violations = append(violations, fmt.Sprintf("%s: Roth conversion year %d is outside the projection horizon %d-%d",
+						name, conversion.Year, firstYear, lastYear))
+				}
+			}
+		}
+	}
+
+	if scenario.Mortality != nil {
+		for _, name := range domain.SortedMapKeys(scenario.Mortality.Participants) {
+			spec := scenario.Mortality.Participants[name]
+			if _, ok := participants[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s: mortality specified for participant not in household", name))
+				continue
+			}
+			if spec != nil && spec.DeathDate != nil {
+				if year := spec.DeathDate.Year(); year < firstYear || year > lastYear {
+					violations = append(violations, fmt.Sprintf("%s: death date %s is outside the projection horizon %d-%d",
+						name, spec.DeathDate.Format("2006-01-02"), firstYear, lastYear))
+				}
+			}
+		}
+	}
+
+	for _, election := range scenario.TaxElections {
+		if election.Year < firstYear || election.Year > lastYear {
+			violations = append(violations, fmt.Sprintf("tax election year %d is outside the projection horizon %d-%d",
+				election.Year, firstYear, lastYear))
+		}
+	}
+
+	for _, e := range scenario.CashFlowEvents {
+		if year := e.Date.Year(); year < firstYear || year > lastYear {
+			violations = append(violations, fmt.Sprintf("cash flow event %q on %s is outside the projection horizon %d-%d",
+				e.Name, e.Date.Format("2006-01-02"), firstYear, lastYear))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &FeasibilityError{Scenario: scenario.Name, Violations: violations}
+	}
+	return nil
+}
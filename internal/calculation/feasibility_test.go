@@ -0,0 +1,156 @@
+package calculation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckScenarioFeasibility_ReportsAllViolations(t *testing.T) {
+	hire := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(1989, 6, 1, 0, 0, 0, 0, time.UTC)
+	death := time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		Participants: []domain.Participant{{Name: "Alice", BirthDate: time.Date(1965, 1, 1, 0, 0, 0, 0, time.UTC), HireDate: &hire}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 10}
+	scenario := &domain.GenericScenario{
+		Name: "Broken",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {
+				ParticipantName: "Alice",
+				RetirementDate:  &retire,
+				SSStartAge:      60,
+				RothConversions: &domain.RothConversionSchedule{Conversions: []domain.RothConversion{
+					{Year: 2030, Amount: decimal.NewFromInt(10000)},
+					{Year: 2040, Amount: decimal.NewFromInt(10000)},
+				}},
+			},
+			"Bob": {ParticipantName: "Bob"},
+		},
+		Mortality: &domain.GenericScenarioMortality{
+			Participants: map[string]*domain.MortalitySpec{"Alice": {DeathDate: &death}},
+		},
+		TaxElections: []domain.TaxElection{{Year: 2030, Deduction: domain.DeductionItemized}, {Year: 2024, Deduction: domain.DeductionStandard}},
+	}
+
+	err := CheckScenarioFeasibility(household, scenario, assumptions)
+	var fe *FeasibilityError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, "Broken", fe.Scenario)
+	assert.Equal(t, []string{
+		"Alice: retirement date 1989-06-01 is not after hire date 1990-01-01",
+		"Alice: Social Security start age 60 is outside 62-70",
+		"Alice: Roth conversion year 2040 is outside the projection horizon 2025-2034",
+		"Bob: participant not found in household",
+		"Alice: death date 2090-01-01 is outside the projection horizon 2025-2034",
+		"tax election year 2024 is outside the projection horizon 2025-2034",
+	}, fe.Violations)
+
+	// The engine refuses to project an infeasible scenario
+	config := &domain.Configuration{Household: household, GlobalAssumptions: *assumptions}
+	_, err = NewCalculationEngine().RunGenericScenario(context.Background(), config, scenario)
+	assert.True(t, errors.As(err, &fe))
+}
+
+func TestCheckScenarioFeasibility_ValidScenario(t *testing.T) {
+	hire := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		Participants: []domain.Participant{{Name: "Alice", HireDate: &hire}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "OK",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", RetirementDate: &retire, SSStartAge: 67},
+		},
+	}
+
+	assert.NoError(t, CheckScenarioFeasibility(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 20}))
+}
+
+func TestCheckScenarioFeasibility_DeferredAnnuityEligibility(t *testing.T) {
+	hire := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	separation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		Participants: []domain.Participant{{Name: "Alice", IsFederal: true, BirthDate: time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC), HireDate: &hire}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "Deferred",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", SeparationDate: &separation, AnnuityStartAge: 60, SSStartAge: 67},
+		},
+	}
+
+	err := CheckScenarioFeasibility(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 10})
+	var fe *FeasibilityError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, []string{
+		"Alice: deferred annuity cannot start at 60 with 8.0 years of service (needs 62 with 5, 60 with 20, or MRA with 10)",
+	}, fe.Violations)
+
+	ps := scenario.ParticipantScenarios["Alice"]
+	ps.AnnuityStartAge = 62
+	scenario.ParticipantScenarios["Alice"] = ps
+	assert.NoError(t, CheckScenarioFeasibility(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 10}))
+}
+
+func TestCheckScenarioFeasibility_MandatoryRetirementAge(t *testing.T) {
+	hire := time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		Participants: []domain.Participant{{
+			Name:             "Jordan",
+			IsFederal:        true,
+			SpecialProvision: domain.SpecialProvisionAirTrafficController,
+			BirthDate:        time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:         &hire,
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "Late",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Jordan": {ParticipantName: "Jordan", RetirementDate: &retire, SSStartAge: 67},
+		},
+	}
+
+	err := CheckScenarioFeasibility(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 20})
+	var fe *FeasibilityError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, []string{"Jordan: retirement at age 57 is past the special provision mandatory retirement age 56"}, fe.Violations)
+}
+
+func TestCheckScenarioFeasibility_MilitaryDepositAfterSeparation(t *testing.T) {
+	hire := time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		Participants: []domain.Participant{{
+			Name:      "Jordan",
+			IsFederal: true,
+			BirthDate: time.Date(1965, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:  &hire,
+			MilitaryDeposit: &domain.MilitaryDeposit{
+				Years:       decimal.NewFromInt(3),
+				Amount:      decimal.NewFromInt(9000),
+				PaymentYear: 2028,
+			},
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "Late deposit",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Jordan": {ParticipantName: "Jordan", RetirementDate: &retire, SSStartAge: 67},
+		},
+	}
+
+	err := CheckScenarioFeasibility(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 20})
+	var fe *FeasibilityError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, []string{"Jordan: military deposit must be paid before separation in 2027 (got 2028)"}, fe.Violations)
+}
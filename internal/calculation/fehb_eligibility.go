@@ -0,0 +1,68 @@
+package calculation
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// fehbFirstOpportunityDays is how long a new employee has to enroll in FEHB; coverage
+// starting within it counts as enrolled since the first opportunity
+const fehbFirstOpportunityDays = 60
+
+// FEHBContinuesIntoRetirement reports whether a federal employee retiring on retirement
+// can carry FEHB into retirement: enrolled for the five years before retiring, or, with
+// less service, since their first opportunity. Coverage is assumed to start at hire when
+// no coverage start date is given.
+func FEHBContinuesIntoRetirement(p *domain.Participant, retirement time.Time) bool {
+	if p.HireDate == nil {
+		return true
+	}
+	start := *p.HireDate
+	if p.FEHBCoverageStartDate != nil {
+		start = *p.FEHBCoverageStartDate
+	}
+	return !start.After(retirement.AddDate(-5, 0, 0)) || !start.After(p.HireDate.AddDate(0, 0, fehbFirstOpportunityDays))
+}
+
+// fehbFiveYearRuleWarning reports a participant who loses FEHB at retirement
+func fehbFiveYearRuleWarning(p *domain.Participant, retirement time.Time) domain.EngineWarning {
+	start := *p.HireDate
+	if p.FEHBCoverageStartDate != nil {
+		start = *p.FEHBCoverageStartDate
+	}
+	return domain.EngineWarning{
+		Year:        retirement.Year(),
+		Participant: p.Name,
+		Code:        domain.WarningFEHBFiveYearRule,
+		Message: fmt.Sprintf("%s: FEHB coverage since %s is short of the five years before retirement on %s, so FEHB ends at retirement",
+			p.Label(), start.Format("2006-01-02"), retirement.Format("2006-01-02")),
+	}
+}
+
+// FEHBEligibilityWarnings reports federal employees enrolled in FEHB who cannot carry it
+// into retirement on the scenario's retirement date. Retirees and deferred annuitants,
+// who cannot continue FEHB anyway, are not checked.
+func FEHBEligibilityWarnings(household *domain.Household, scenario *domain.GenericScenario) []domain.EngineWarning {
+	if household == nil || scenario == nil {
+		return nil
+	}
+	var warnings []domain.EngineWarning
+	for _, name := range slices.Sorted(maps.Keys(scenario.ParticipantScenarios)) {
+		ps := scenario.ParticipantScenarios[name]
+		for i := range household.Participants {
+			p := &household.Participants[i]
+			if p.Name != name || !p.IsFederal || p.HireDate == nil || p.CurrentRetirement != nil ||
+				p.FEHBPremiumPerPayPeriod == nil || ps.RetirementDate == nil || (ps.SeparationDate != nil && ps.AnnuityStartAge > 0) {
+				continue
+			}
+			if !FEHBContinuesIntoRetirement(p, *ps.RetirementDate) {
+				warnings = append(warnings, fehbFiveYearRuleWarning(p, *ps.RetirementDate))
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,87 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFEHBContinuesIntoRetirement(t *testing.T) {
+	hire := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	p := &domain.Participant{Name: "Morgan", HireDate: &hire}
+
+	// Covered since hire: eligible even with less than five years
+	assert.True(t, FEHBContinuesIntoRetirement(p, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Enrolled later than the first opportunity: needs the full five years
+	late := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.FEHBCoverageStartDate = &late
+	assert.False(t, FEHBContinuesIntoRetirement(p, retire))
+	assert.True(t, FEHBContinuesIntoRetirement(p, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Within 60 days of hire counts as the first opportunity
+	prompt := hire.AddDate(0, 0, 45)
+	p.FEHBCoverageStartDate = &prompt
+	assert.True(t, FEHBContinuesIntoRetirement(p, retire))
+}
+
+func TestProjectionFEHBFiveYearRule(t *testing.T) {
+	hire := time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	coverage := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	salary := decimal.NewFromInt(100000)
+	high3 := decimal.NewFromInt(95000)
+	fehb := decimal.NewFromInt(300)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:                    "Morgan",
+			IsFederal:               true,
+			BirthDate:               time.Date(1964, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:                &hire,
+			CurrentSalary:           &salary,
+			High3Salary:             &high3,
+			FEHBPremiumPerPayPeriod: &fehb,
+			FEHBCoverageStartDate:   &coverage,
+			IsPrimaryFEHBHolder:     true,
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "fehb",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Morgan": {ParticipantName: "Morgan", RetirementDate: &retire, SSStartAge: 67},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+
+	warnings := FEHBEligibilityWarnings(household, scenario)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, domain.WarningFEHBFiveYearRule, warnings[0].Code)
+
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 3)
+
+	// Premiums stop at retirement, with nothing collected retroactively
+	assert.True(t, projection[0].FEHBPremium.Equal(decimal.NewFromInt(7800)), "got %s", projection[0].FEHBPremium)
+	assert.True(t, projection[1].FEHBRetroactivePremium.IsZero())
+	assert.True(t, projection[2].FEHBPremium.IsZero(), "got %s", projection[2].FEHBPremium)
+	var codes []string
+	for _, w := range projection[1].Warnings {
+		codes = append(codes, w.Code)
+	}
+	assert.Contains(t, codes, domain.WarningFEHBFiveYearRule)
+
+	// Covered for five years before retiring: FEHB continues
+	earlier := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	household.Participants[0].FEHBCoverageStartDate = &earlier
+	assert.Empty(t, FEHBEligibilityWarnings(household, scenario))
+	projection = ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	assert.True(t, projection[2].FEHBPremium.IsPositive())
+}
@@ -92,16 +92,73 @@ func determineMultiplier(retirementAge int, serviceYears decimal.Decimal) decima
 	return decimal.NewFromFloat(0.010)
 }
 
+// SpecialProvisionEligible reports whether a special provision employee (law enforcement,
+// firefighter, air traffic controller) can retire immediately under the enhanced rules:
+// age 50 with 20 years of service, or any age with 25 years
+func SpecialProvisionEligible(age int, serviceYears decimal.Decimal) bool {
+	if serviceYears.GreaterThanOrEqual(decimal.NewFromInt(25)) {
+		return true
+	}
+	return age >= 50 && serviceYears.GreaterThanOrEqual(decimal.NewFromInt(20))
+}
+
+// SpecialProvisionPension computes the unreduced special provision annuity:
+// 1.7% of high-3 for each of the first 20 years of service plus 1% for each year beyond
+func SpecialProvisionPension(high3, serviceYears decimal.Decimal) decimal.Decimal {
+	twenty := decimal.NewFromInt(20)
+	enhanced := decimal.Min(serviceYears, twenty)
+	pension := high3.Mul(enhanced).Mul(decimal.NewFromFloat(0.017))
+	if serviceYears.GreaterThan(twenty) {
+		pension = pension.Add(high3.Mul(serviceYears.Sub(twenty)).Mul(decimal.NewFromFloat(0.01)))
+	}
+	return pension
+}
+
+// CalculateCSRSPension computes the unreduced CSRS annuity: 1.5% of high-3 for each of the
+// first 5 years of service, 1.75% for the next 5 and 2% beyond, capped at 80% of high-3
+func CalculateCSRSPension(high3, serviceYears decimal.Decimal) decimal.Decimal {
+	five := decimal.NewFromInt(5)
+	ten := decimal.NewFromInt(10)
+	first := decimal.Min(serviceYears, five)
+	second := decimal.Max(decimal.Zero, decimal.Min(serviceYears, ten).Sub(five))
+	rest := decimal.Max(decimal.Zero, serviceYears.Sub(ten))
+
+	rate := first.Mul(decimal.NewFromFloat(0.015)).
+		Add(second.Mul(decimal.NewFromFloat(0.0175))).
+		Add(rest.Mul(decimal.NewFromFloat(0.02)))
+	return high3.Mul(decimal.Min(rate, decimal.NewFromFloat(0.80)))
+}
+
+// CSRSOffsetReduction returns the annual reduction applied to a CSRS Offset annuity once
+// the annuitant reaches 62: the Social Security benefit at 62 attributable to offset
+// service, approximated as the service share of a 40-year career
+func CSRSOffsetReduction(ssBenefitAt62, serviceYears decimal.Decimal) decimal.Decimal {
+	share := decimal.Min(serviceYears.Div(decimal.NewFromInt(40)), decimal.NewFromInt(1))
+	return ssBenefitAt62.Mul(decimal.NewFromInt(12)).Mul(share)
+}
+
+// ApplyCSRSPensionCOLA applies the full CPI increase at any age; annuities are never reduced
+func ApplyCSRSPensionCOLA(currentPension, inflationRate decimal.Decimal) decimal.Decimal {
+	if inflationRate.LessThan(decimal.Zero) {
+		return currentPension
+	}
+	return currentPension.Mul(decimal.NewFromInt(1).Add(inflationRate))
+}
+
 // ApplyFERSPensionCOLA applies the FERS COLA rules
 // COLA is not applied until the annuitant reaches age 62
 // Annual COLA Rules:
 // - If CPI change (inflation) is 2% or less, COLA is the actual CPI change
 // - If CPI change is between 2% and 3%, COLA is 2%
 // - If CPI change is greater than 3%, COLA is CPI change minus 1%
+// - If CPI change is negative, there is no COLA (annuities are never reduced)
 func ApplyFERSPensionCOLA(currentPension decimal.Decimal, inflationRate decimal.Decimal, annuitantAge int) decimal.Decimal {
 	if annuitantAge < 62 {
 		return currentPension // No COLA until age 62
 	}
+	if inflationRate.LessThan(decimal.Zero) {
+		return currentPension
+	}
 
 	var colaRate decimal.Decimal
 	if inflationRate.LessThanOrEqual(decimal.NewFromFloat(0.02)) {
@@ -212,6 +269,23 @@ func ValidateFERSEligibility(employee *domain.Employee, retirementDate time.Time
 	return false, "Not eligible for immediate annuity"
 }
 
+// DeferredAnnuityReduction returns the age reduction for a deferred FERS annuity
+// beginning at startAge after separating with serviceYears, and whether that start
+// age is allowed. Starting at 62 with 5+ years or at 60 with 20+ years is unreduced;
+// starting at MRA with 10+ years (MRA+10) is reduced 5% per year under 62.
+func DeferredAnnuityReduction(startAge, mra int, serviceYears decimal.Decimal) (decimal.Decimal, bool) {
+	switch {
+	case startAge >= 62 && serviceYears.GreaterThanOrEqual(decimal.NewFromInt(5)):
+		return decimal.Zero, true
+	case startAge >= 60 && serviceYears.GreaterThanOrEqual(decimal.NewFromInt(20)):
+		return decimal.Zero, true
+	case startAge >= mra && serviceYears.GreaterThanOrEqual(decimal.NewFromInt(10)):
+		return decimal.NewFromInt(int64(62 - startAge)).Mul(decimal.NewFromFloat(0.05)), true
+	default:
+		return decimal.Zero, false
+	}
+}
+
 // CalculatePensionReduction calculates any reduction in pension benefits
 func CalculatePensionReduction(employee *domain.Employee, retirementDate time.Time) decimal.Decimal {
 	age := employee.Age(retirementDate)
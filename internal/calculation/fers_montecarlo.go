@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"runtime"
+	"slices"
 	"sync"
 	"time"
 
@@ -17,14 +19,31 @@ type FERSMonteCarloEngine struct {
 	historicalData    *HistoricalDataManager
 	calculationEngine *CalculationEngine
 	config            FERSMonteCarloConfig
+	// shocks draws the statistical fund returns and inflation jointly
+	shocks *marketShocks
+	// progress, when set, is told each time a simulation finishes
+	progress MonteCarloProgress
 }
 
+// MonteCarloProgress reports that completed of total simulations have finished. Calls
+// are serialized, so it needn't be safe for concurrent use.
+type MonteCarloProgress func(completed, total int)
+
 // FERSMonteCarloConfig holds configuration for FERS Monte Carlo simulations
 type FERSMonteCarloConfig struct {
 	NumSimulations  int
 	ProjectionYears int
 	Seed            int64
 	UseHistorical   bool
+	// BlockLength, when above 1, gives each simulation a year-by-year historical path
+	// drawn in blocks of this many consecutive years instead of a single year
+	BlockLength int
+	// Workers bounds how many simulations run at once (0 uses GOMAXPROCS)
+	Workers int
+	// Tolerance, when positive, adds simulations until the success rate's 95% confidence
+	// interval is within ± Tolerance or MaxSimulations (0 for the default) have run
+	Tolerance      decimal.Decimal
+	MaxSimulations int
 
 	// Market variability settings
 	TSPReturnVariability decimal.Decimal // Standard deviation for TSP returns
@@ -32,6 +51,12 @@ type FERSMonteCarloConfig struct {
 	COLAVariability      decimal.Decimal // Standard deviation for COLA
 	FEHBVariability      decimal.Decimal // Standard deviation for FEHB premiums
 
+	// Bounds applied to every generated value, historical or statistical
+	TSPReturnBounds     domain.RateBounds
+	InflationBounds     domain.RateBounds
+	COLABounds          domain.RateBounds
+	FEHBInflationBounds domain.RateBounds
+
 	// Monte Carlo specific settings
 	MaxReasonableIncome  decimal.Decimal // Cap for unrealistic income scenarios
 	DefaultTSPAllocation domain.TSPAllocation
@@ -39,15 +64,26 @@ type FERSMonteCarloConfig struct {
 
 // FERSMonteCarloResult represents comprehensive results from FERS Monte Carlo simulation
 type FERSMonteCarloResult struct {
-	BaseScenarioName     string                     `json:"baseScenarioName"`
-	NumSimulations       int                        `json:"numSimulations"`
-	ProjectionYears      int                        `json:"projectionYears"`
-	SuccessRate          decimal.Decimal            `json:"successRate"`
-	MedianLifetimeIncome decimal.Decimal            `json:"medianLifetimeIncome"`
-	MedianTSPLongevity   int                        `json:"medianTSPLongevity"`
-	PercentileRanges     FERSPercentileRanges       `json:"percentileRanges"`
-	Simulations          []FERSMonteCarloSimulation `json:"simulations"`
-	MarketConditions     []MarketCondition          `json:"marketConditions"`
+	BaseScenarioName string `json:"baseScenarioName"`
+	NumSimulations   int    `json:"numSimulations"`
+	ProjectionYears  int    `json:"projectionYears"`
+	// Seed is the seed the market paths were drawn from; the same seed repeats the run
+	Seed        int64           `json:"seed"`
+	SuccessRate decimal.Decimal `json:"successRate"`
+	// SuccessRateStdErr is the standard error of the success rate, and SuccessRateMargin
+	// the half-width of its 95% (Wilson score) confidence interval
+	SuccessRateStdErr    decimal.Decimal      `json:"successRateStdErr"`
+	SuccessRateMargin    decimal.Decimal      `json:"successRateMargin"`
+	MedianLifetimeIncome decimal.Decimal      `json:"medianLifetimeIncome"`
+	MedianTSPLongevity   int                  `json:"medianTSPLongevity"`
+	PercentileRanges     FERSPercentileRanges `json:"percentileRanges"`
+	// IRMAABreachProbability is the share of simulations whose MAGI crosses an IRMAA
+	// threshold in at least one year
+	IRMAABreachProbability decimal.Decimal `json:"irmaaBreachProbability"`
+	// YearlyBands holds each projection year's TSP balance and net income percentiles
+	YearlyBands      []FERSYearBands            `json:"yearlyBands"`
+	Simulations      []FERSMonteCarloSimulation `json:"simulations,omitempty"`
+	MarketConditions []MarketCondition          `json:"marketConditions,omitempty"`
 }
 
 // FERSMonteCarloSimulation represents a single FERS Monte Carlo simulation outcome
@@ -66,6 +102,20 @@ type MarketCondition struct {
 	InflationRate decimal.Decimal            `json:"inflationRate"`
 	COLARate      decimal.Decimal            `json:"colaRate"`
 	FEHBInflation decimal.Decimal            `json:"fehbInflation"`
+	// HistoricalYear is the year of history the returns, inflation and COLA were
+	// drawn from when UseHistorical is enabled (zero for statistical draws)
+	HistoricalYear int `json:"historicalYear,omitempty"`
+	// Path holds every projection year's conditions when the simulation runs a
+	// block-bootstrapped historical path; the fields above are then its first year
+	Path []domain.MarketYear `json:"path,omitempty"`
+}
+
+// FERSYearBands holds the percentiles across simulations of one projection year, for
+// charting the funding cone
+type FERSYearBands struct {
+	Year       int                        `json:"year"`
+	TSPBalance map[string]decimal.Decimal `json:"tspBalance"` // 10th, 25th, 50th, 75th, 90th percentiles
+	NetIncome  map[string]decimal.Decimal `json:"netIncome"`  // 10th, 25th, 50th, 75th, 90th percentiles
 }
 
 // FERSPercentileRanges represents percentile ranges for FERS Monte Carlo results
@@ -82,111 +132,284 @@ func NewFERSMonteCarloEngine(baseConfig *domain.Configuration, historicalData *H
 		baseConfig:        baseConfig,
 		historicalData:    historicalData,
 		calculationEngine: NewCalculationEngineWithConfig(baseConfig.GlobalAssumptions.FederalRules),
-		config: FERSMonteCarloConfig{
-			NumSimulations:       1000,
-			ProjectionYears:      30,
-			Seed:                 time.Now().UnixNano(),
-			UseHistorical:        true,
-			TSPReturnVariability: decimal.NewFromFloat(0.05),     // 5% standard deviation (reduced from 15%)
-			InflationVariability: decimal.NewFromFloat(0.01),     // 1% standard deviation (reduced from 2%)
-			COLAVariability:      decimal.NewFromFloat(0.005),    // 0.5% standard deviation (reduced from 1%)
-			FEHBVariability:      decimal.NewFromFloat(0.02),     // 2% standard deviation (reduced from 5%)
-			MaxReasonableIncome:  decimal.NewFromFloat(10000000), // $10M cap (increased from $500K)
-			DefaultTSPAllocation: domain.TSPAllocation{
-				CFund: decimal.NewFromFloat(0.6),
-				SFund: decimal.NewFromFloat(0.2),
-				IFund: decimal.NewFromFloat(0.1),
-				FFund: decimal.NewFromFloat(0.1),
-				GFund: decimal.Zero,
-			},
+		config:            defaultFERSMonteCarloConfig(baseConfig.GlobalAssumptions.MonteCarloSettings),
+		shocks:            newMarketShocks(baseConfig.GlobalAssumptions.MonteCarloSettings, historicalData),
+	}
+}
+
+// defaultFERSMonteCarloConfig returns the default simulation settings, with the rate
+// bounds of the configuration's Monte Carlo settings
+func defaultFERSMonteCarloConfig(settings domain.MonteCarloSettings) FERSMonteCarloConfig {
+	return FERSMonteCarloConfig{
+		NumSimulations:       1000,
+		ProjectionYears:      30,
+		Seed:                 time.Now().UnixNano(),
+		UseHistorical:        true,
+		BlockLength:          settings.BlockLength,
+		TSPReturnVariability: decimal.NewFromFloat(0.05),     // 5% standard deviation (reduced from 15%)
+		InflationVariability: decimal.NewFromFloat(0.01),     // 1% standard deviation (reduced from 2%)
+		COLAVariability:      decimal.NewFromFloat(0.005),    // 0.5% standard deviation (reduced from 1%)
+		FEHBVariability:      decimal.NewFromFloat(0.02),     // 2% standard deviation (reduced from 5%)
+		MaxReasonableIncome:  decimal.NewFromFloat(10000000), // $10M cap (increased from $500K)
+		TSPReturnBounds:      rateBoundsOrDefault(settings.TSPReturnBounds, -0.5),
+		InflationBounds:      rateBoundsOrDefault(settings.InflationBounds, -0.05),
+		COLABounds:           rateBoundsOrDefault(settings.COLABounds, -0.02),
+		FEHBInflationBounds:  rateBoundsOrDefault(settings.FEHBInflationBounds, 0),
+		DefaultTSPAllocation: domain.TSPAllocation{
+			CFund: decimal.NewFromFloat(0.6),
+			SFund: decimal.NewFromFloat(0.2),
+			IFund: decimal.NewFromFloat(0.1),
+			FFund: decimal.NewFromFloat(0.1),
+			GFund: decimal.Zero,
 		},
 	}
 }
 
+// rateBoundsOrDefault returns the configured bounds, or a floor-only default when unset
+func rateBoundsOrDefault(configured *domain.RateBounds, defaultFloor float64) domain.RateBounds {
+	if configured != nil {
+		return *configured
+	}
+	floor := decimal.NewFromFloat(defaultFloor)
+	return domain.RateBounds{Floor: &floor}
+}
+
+// SetSimulations sets the number of simulated market paths
+func (fmce *FERSMonteCarloEngine) SetSimulations(n int) {
+	if n > 0 {
+		fmce.config.NumSimulations = n
+	}
+}
+
+// SetSeed fixes the seed the market paths are drawn from, so a run can be repeated
+func (fmce *FERSMonteCarloEngine) SetSeed(seed int64) {
+	fmce.config.Seed = seed
+}
+
+// SetBlockLength sets how many consecutive historical years each block of a
+// simulation's market path takes; 1 or less holds one sampled year for the whole run
+func (fmce *FERSMonteCarloEngine) SetBlockLength(n int) {
+	fmce.config.BlockLength = n
+}
+
+// SetWorkers bounds how many simulations run at once; 0 or less uses GOMAXPROCS
+func (fmce *FERSMonteCarloEngine) SetWorkers(n int) {
+	fmce.config.Workers = n
+}
+
+// SetProgress sets the callback told each time a simulation finishes
+func (fmce *FERSMonteCarloEngine) SetProgress(progress MonteCarloProgress) {
+	fmce.progress = progress
+}
+
+// Seed returns the seed the market paths are drawn from
+func (fmce *FERSMonteCarloEngine) Seed() int64 {
+	return fmce.config.Seed
+}
+
 // RunFERSMonteCarlo runs a comprehensive FERS Monte Carlo simulation
 func (fmce *FERSMonteCarloEngine) RunFERSMonteCarlo(ctx context.Context, baseScenarioName string) (*FERSMonteCarloResult, error) {
-	// Find base scenario
-	var baseScenario *domain.GenericScenario
+	baseScenario, err := fmce.findScenario(baseScenarioName)
+	if err != nil {
+		return nil, err
+	}
+
+	marketConditions := fmce.marketPaths(0, fmce.config.NumSimulations)
+	simulations, err := fmce.runScenarioOnPaths(ctx, baseScenario, marketConditions, fmce.progressReporter(0, len(marketConditions)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Extend the run until the success rate is as precise as requested. The added paths
+	// continue the seed's sequence, so the result matches a run of the final count.
+	for more := fmce.additionalSimulations(simulations); more > 0; more = fmce.additionalSimulations(simulations) {
+		n := len(marketConditions)
+		paths := fmce.marketPaths(n, n+more)
+		extra, err := fmce.runScenarioOnPaths(ctx, baseScenario, paths, fmce.progressReporter(n, n+more))
+		if err != nil {
+			return nil, err
+		}
+		for i := range extra {
+			extra[i].SimulationID = n + i
+		}
+		marketConditions = append(marketConditions, paths...)
+		simulations = append(simulations, extra...)
+	}
+
+	// Calculate summary statistics
+	result := fmce.calculateFERSSummary(simulations, marketConditions, baseScenarioName)
+	result.NumSimulations = len(simulations)
+
+	return result, nil
+}
+
+// findScenario looks up a scenario of the base configuration by name
+func (fmce *FERSMonteCarloEngine) findScenario(name string) (*domain.GenericScenario, error) {
 	for i := range fmce.baseConfig.Scenarios {
-		if fmce.baseConfig.Scenarios[i].Name == baseScenarioName {
-			baseScenario = &fmce.baseConfig.Scenarios[i]
-			break
+		if fmce.baseConfig.Scenarios[i].Name == name {
+			return &fmce.baseConfig.Scenarios[i], nil
 		}
 	}
-	if baseScenario == nil {
-		return nil, fmt.Errorf("base scenario '%s' not found", baseScenarioName)
+	return nil, fmt.Errorf("base scenario '%s' not found", name)
+}
+
+// marketPaths draws the market conditions of simulations from through to-1. Each comes
+// from its own source seeded by the simulation index, so the paths depend only on the seed.
+func (fmce *FERSMonteCarloEngine) marketPaths(from, to int) []MarketCondition {
+	conditions := make([]MarketCondition, to-from)
+	for i := range conditions {
+		conditions[i] = fmce.generateMarketConditions(rand.New(rand.NewSource(fmce.config.Seed + int64(from+i))))
 	}
+	return conditions
+}
 
-	// Run simulations in parallel
-	simulations := make([]FERSMonteCarloSimulation, fmce.config.NumSimulations)
-	marketConditions := make([]MarketCondition, fmce.config.NumSimulations)
+// runScenarioOnPaths runs the scenario once per market path on a bounded pool of
+// workers and returns the simulations in path order so each stays paired with the
+// conditions it faced. It stops handing out paths once ctx is done and returns its error.
+func (fmce *FERSMonteCarloEngine) runScenarioOnPaths(ctx context.Context, scenario *domain.GenericScenario, marketConditions []MarketCondition, done func()) ([]FERSMonteCarloSimulation, error) {
+	simulations := make([]FERSMonteCarloSimulation, len(marketConditions))
 
-	var wg sync.WaitGroup
-	simChan := make(chan FERSMonteCarloSimulation, fmce.config.NumSimulations)
-	marketChan := make(chan MarketCondition, fmce.config.NumSimulations)
+	workers := fmce.config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(marketConditions))
 
-	// Generate simulations concurrently
-	for i := 0; i < fmce.config.NumSimulations; i++ {
+	paths := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(simID int) {
+		go func() {
 			defer wg.Done()
-
-			// Create a new random source for this simulation
-			simRNG := rand.New(rand.NewSource(fmce.config.Seed + int64(simID)))
-
-			// Generate market conditions for this simulation
-			marketCondition := fmce.generateMarketConditions(simRNG)
-			marketChan <- marketCondition
-
-			// Run single FERS simulation
-			simulation, err := fmce.runSingleFERSSimulation(ctx, baseScenario, marketCondition, simID)
-			if err != nil {
-				// Create failed simulation
-				simulation = &FERSMonteCarloSimulation{
-					SimulationID:    simID,
-					MarketCondition: marketCondition,
-					Success:         false,
-					FailureReason:   err.Error(),
+			for simID := range paths {
+				// Run single FERS simulation
+				simulation, err := fmce.runSingleFERSSimulation(ctx, scenario, marketConditions[simID], simID)
+				if err != nil {
+					// Create failed simulation
+					simulation = &FERSMonteCarloSimulation{
+						SimulationID:    simID,
+						MarketCondition: marketConditions[simID],
+						Success:         false,
+						FailureReason:   err.Error(),
+					}
 				}
+				simulations[simID] = *simulation
+				done()
 			}
-
-			simChan <- *simulation
-		}(i)
+		}()
 	}
 
-	// Wait for all simulations to complete
-	go func() {
-		wg.Wait()
-		close(simChan)
-		close(marketChan)
-	}()
-
-	// Collect results
-	for i := 0; i < fmce.config.NumSimulations; i++ {
-		simulations[i] = <-simChan
-		marketConditions[i] = <-marketChan
+dispatch:
+	for simID := range marketConditions {
+		select {
+		case paths <- simID:
+		case <-ctx.Done():
+			break dispatch
+		}
 	}
+	close(paths)
+	wg.Wait()
 
-	// Calculate summary statistics
-	result := fmce.calculateFERSSummary(simulations, marketConditions, baseScenarioName)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return simulations, nil
+}
 
-	return result, nil
+// progressReporter returns a function to call as each simulation finishes, which passes
+// the running count, starting after completed, of total to the progress callback
+func (fmce *FERSMonteCarloEngine) progressReporter(completed, total int) func() {
+	var mu sync.Mutex
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		if fmce.progress != nil {
+			fmce.progress(completed, total)
+		}
+	}
 }
 
 // generateMarketConditions creates market conditions for a single simulation
 func (fmce *FERSMonteCarloEngine) generateMarketConditions(rng *rand.Rand) MarketCondition {
+	if fmce.config.UseHistorical && fmce.historicalData != nil && fmce.config.BlockLength > 1 {
+		if path, err := fmce.historicalData.SampleHistoricalPath(rng, fmce.pathYears(), fmce.config.BlockLength); err == nil {
+			return fmce.historicalPathConditions(path, rng)
+		}
+	}
+
+	// Draw one historical year per simulation from the simulation's own source so the
+	// path is reproducible from the seed and every component comes from the same year
+	historicalYear := 0
+	if fmce.config.UseHistorical && fmce.historicalData != nil {
+		if year, err := fmce.historicalData.SampleHistoricalYear(rng); err == nil {
+			historicalYear = year
+		}
+	}
+
+	return fmce.marketConditionForYear(historicalYear, rng)
+}
+
+// pathYears returns how many years a simulated market path covers: the configuration's
+// projection horizon, or the engine's default when the configuration sets none
+func (fmce *FERSMonteCarloEngine) pathYears() int {
+	if years := fmce.baseConfig.GlobalAssumptions.ProjectionYears; years > 0 {
+		return years
+	}
+	return fmce.config.ProjectionYears
+}
+
+// historicalPathConditions builds the market conditions of a simulation that follows a
+// historical path year by year
+func (fmce *FERSMonteCarloEngine) historicalPathConditions(path []int, rng *rand.Rand) MarketCondition {
+	var first MarketCondition
+	marketPath := make([]domain.MarketYear, len(path))
+	for i, historicalYear := range path {
+		year := fmce.marketConditionForYear(historicalYear, rng)
+		if i == 0 {
+			first = year
+		}
+		marketPath[i] = domain.MarketYear{
+			InflationRate: year.InflationRate,
+			COLARate:      year.COLARate,
+			FEHBInflation: year.FEHBInflation,
+			FundReturns: domain.TSPFundReturns{
+				CFund: year.TSPReturns["C"],
+				SFund: year.TSPReturns["S"],
+				IFund: year.TSPReturns["I"],
+				FFund: year.TSPReturns["F"],
+				GFund: year.TSPReturns["G"],
+			},
+			HistoricalYear: historicalYear,
+		}
+		if fmce.config.UseHistorical && historicalYear != 0 {
+			marketPath[i].MonthlyFundReturns, _ = fmce.historicalData.MonthlyFundReturns(historicalYear)
+		}
+	}
+	first.Path = marketPath
+	return first
+}
+
+// marketConditionForYear draws the market conditions of one simulated year, from
+// historicalYear when sampling history
+func (fmce *FERSMonteCarloEngine) marketConditionForYear(historicalYear int, rng *rand.Rand) MarketCondition {
+	if historicalYear == 0 {
+		return fmce.statisticalMarketCondition(rng)
+	}
+
 	condition := MarketCondition{
-		TSPReturns:    make(map[string]decimal.Decimal),
-		InflationRate: fmce.generateInflationRate(rng),
-		COLARate:      fmce.generateCOLARate(rng),
-		FEHBInflation: fmce.generateFEHBInflation(rng),
+		TSPReturns:     make(map[string]decimal.Decimal),
+		InflationRate:  fmce.generateInflationRate(historicalYear, rng),
+		COLARate:       fmce.generateCOLARate(historicalYear, rng),
+		FEHBInflation:  fmce.generateFEHBInflation(rng),
+		HistoricalYear: historicalYear,
 	}
 
 	// Generate TSP fund returns
 	funds := []string{"C", "S", "I", "F", "G"}
 	for _, fund := range funds {
 		if fmce.config.UseHistorical {
-			condition.TSPReturns[fund] = fmce.generateHistoricalTSPReturn(fund, rng)
+			condition.TSPReturns[fund] = fmce.generateHistoricalTSPReturn(fund, historicalYear, rng)
 		} else {
 			condition.TSPReturns[fund] = fmce.generateStatisticalTSPReturn(fund, rng)
 		}
@@ -195,38 +418,59 @@ func (fmce *FERSMonteCarloEngine) generateMarketConditions(rng *rand.Rand) Marke
 	return condition
 }
 
+// statisticalMarketCondition draws a year's market conditions from the statistical
+// models, with the fund returns and inflation drawn jointly so they move together
+func (fmce *FERSMonteCarloEngine) statisticalMarketCondition(rng *rand.Rand) MarketCondition {
+	z := fmce.marketShocks().draw(rng)
+
+	inflation := fmce.baseConfig.GlobalAssumptions.InflationRate.Add(decimal.NewFromFloat(z[5]).Mul(fmce.config.InflationVariability))
+	condition := MarketCondition{
+		TSPReturns:    make(map[string]decimal.Decimal),
+		InflationRate: fmce.config.InflationBounds.Clamp(inflation),
+		COLARate:      fmce.generateCOLARate(0, rng),
+		FEHBInflation: fmce.generateFEHBInflation(rng),
+	}
+	for i, fund := range domain.MarketFactors[:5] {
+		r := statisticalTSPMeans[fund].Add(decimal.NewFromFloat(z[i]).Mul(fmce.config.TSPReturnVariability))
+		condition.TSPReturns[fund] = fmce.config.TSPReturnBounds.Clamp(r)
+	}
+	return condition
+}
+
+// marketShocks returns the shocks statistical draws are scaled from
+func (fmce *FERSMonteCarloEngine) marketShocks() *marketShocks {
+	if fmce.shocks == nil {
+		return defaultMarketShocks
+	}
+	return fmce.shocks
+}
+
 // generateInflationRate generates a random inflation rate
-func (fmce *FERSMonteCarloEngine) generateInflationRate(rng *rand.Rand) decimal.Decimal {
+func (fmce *FERSMonteCarloEngine) generateInflationRate(historicalYear int, rng *rand.Rand) decimal.Decimal {
 	baseRate := fmce.baseConfig.GlobalAssumptions.InflationRate
 	if fmce.config.UseHistorical {
 		// Use historical inflation data if available
-		return fmce.generateHistoricalInflationRate(rng)
+		return fmce.generateHistoricalInflationRate(historicalYear, rng)
 	}
 
 	// Generate using normal distribution around base rate
 	variability := fmce.config.InflationVariability
 	randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 	result := baseRate.Add(randomFactor)
-	if result.LessThan(decimal.NewFromFloat(-0.05)) {
-		result = decimal.NewFromFloat(-0.05) // Cap at -5%
-	}
-	return result
+	return fmce.config.InflationBounds.Clamp(result)
 }
 
 // generateCOLARate generates a random COLA rate
-func (fmce *FERSMonteCarloEngine) generateCOLARate(rng *rand.Rand) decimal.Decimal {
+func (fmce *FERSMonteCarloEngine) generateCOLARate(historicalYear int, rng *rand.Rand) decimal.Decimal {
 	baseRate := fmce.baseConfig.GlobalAssumptions.COLAGeneralRate
 	if fmce.config.UseHistorical {
-		return fmce.generateHistoricalCOLARate(rng)
+		return fmce.generateHistoricalCOLARate(historicalYear, rng)
 	}
 
 	variability := fmce.config.COLAVariability
 	randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 	result := baseRate.Add(randomFactor)
-	if result.LessThan(decimal.NewFromFloat(-0.02)) {
-		result = decimal.NewFromFloat(-0.02) // Cap at -2%
-	}
-	return result
+	return fmce.config.COLABounds.Clamp(result)
 }
 
 // generateFEHBInflation generates a random FEHB inflation rate
@@ -235,21 +479,12 @@ func (fmce *FERSMonteCarloEngine) generateFEHBInflation(rng *rand.Rand) decimal.
 	variability := fmce.config.FEHBVariability
 	randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 	result := baseRate.Add(randomFactor)
-	if result.LessThan(decimal.NewFromFloat(0.0)) {
-		result = decimal.NewFromFloat(0.0) // Cap at 0%
-	}
-	return result
+	return fmce.config.FEHBInflationBounds.Clamp(result)
 }
 
 // generateHistoricalTSPReturn generates TSP return using historical data
-func (fmce *FERSMonteCarloEngine) generateHistoricalTSPReturn(fund string, rng *rand.Rand) decimal.Decimal {
-	if fmce.historicalData == nil {
-		return fmce.generateStatisticalTSPReturn(fund, rng)
-	}
-
-	// Get a random historical year
-	year, err := fmce.historicalData.GetRandomHistoricalYear()
-	if err != nil {
+func (fmce *FERSMonteCarloEngine) generateHistoricalTSPReturn(fund string, year int, rng *rand.Rand) decimal.Decimal {
+	if fmce.historicalData == nil || year == 0 {
 		return fmce.generateStatisticalTSPReturn(fund, rng)
 	}
 
@@ -259,56 +494,37 @@ func (fmce *FERSMonteCarloEngine) generateHistoricalTSPReturn(fund string, rng *
 		return fmce.generateStatisticalTSPReturn(fund, rng)
 	}
 
-	return returnRate
+	return fmce.config.TSPReturnBounds.Clamp(returnRate)
+}
+
+// statisticalTSPMeans are the long-term average returns the statistical fund returns
+// are drawn around
+var statisticalTSPMeans = map[string]decimal.Decimal{
+	"C": decimal.NewFromFloat(0.10), // 10% average
+	"S": decimal.NewFromFloat(0.12), // 12% average
+	"I": decimal.NewFromFloat(0.08), // 8% average
+	"F": decimal.NewFromFloat(0.05), // 5% average
+	"G": decimal.NewFromFloat(0.03), // 3% average
 }
 
 // generateStatisticalTSPReturn generates TSP return using statistical distribution
 func (fmce *FERSMonteCarloEngine) generateStatisticalTSPReturn(fund string, rng *rand.Rand) decimal.Decimal {
-	// Base returns by fund (long-term averages)
-	baseReturns := map[string]decimal.Decimal{
-		"C": decimal.NewFromFloat(0.10), // 10% average
-		"S": decimal.NewFromFloat(0.12), // 12% average
-		"I": decimal.NewFromFloat(0.08), // 8% average
-		"F": decimal.NewFromFloat(0.05), // 5% average
-		"G": decimal.NewFromFloat(0.03), // 3% average
-	}
-
-	baseReturn := baseReturns[fund]
+	baseReturn := statisticalTSPMeans[fund]
 	variability := fmce.config.TSPReturnVariability
-	randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
+	randomFactor := decimal.NewFromFloat(fmce.marketShocks().drawFactor(marketFactorIndex(fund), rng)).Mul(variability)
 	result := baseReturn.Add(randomFactor)
-	if result.LessThan(decimal.NewFromFloat(-0.5)) {
-		result = decimal.NewFromFloat(-0.5) // Cap at -50%
-	}
-	return result
+	return fmce.config.TSPReturnBounds.Clamp(result)
 }
 
 // generateHistoricalInflationRate generates inflation rate using historical data
-func (fmce *FERSMonteCarloEngine) generateHistoricalInflationRate(rng *rand.Rand) decimal.Decimal {
-	if fmce.historicalData == nil {
-		// Fall back to statistical generation
-		baseRate := fmce.baseConfig.GlobalAssumptions.InflationRate
-		variability := fmce.config.InflationVariability
-		randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
-		result := baseRate.Add(randomFactor)
-		if result.LessThan(decimal.NewFromFloat(-0.05)) {
-			result = decimal.NewFromFloat(-0.05) // Cap at -5%
-		}
-		return result
-	}
-
-	// Get a random historical year
-	year, err := fmce.historicalData.GetRandomHistoricalYear()
-	if err != nil {
+func (fmce *FERSMonteCarloEngine) generateHistoricalInflationRate(year int, rng *rand.Rand) decimal.Decimal {
+	if fmce.historicalData == nil || year == 0 {
 		// Fall back to statistical generation
 		baseRate := fmce.baseConfig.GlobalAssumptions.InflationRate
 		variability := fmce.config.InflationVariability
 		randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 		result := baseRate.Add(randomFactor)
-		if result.LessThan(decimal.NewFromFloat(-0.05)) {
-			result = decimal.NewFromFloat(-0.05) // Cap at -5%
-		}
-		return result
+		return fmce.config.InflationBounds.Clamp(result)
 	}
 
 	// Get inflation rate for that year
@@ -319,41 +535,21 @@ func (fmce *FERSMonteCarloEngine) generateHistoricalInflationRate(rng *rand.Rand
 		variability := fmce.config.InflationVariability
 		randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 		result := baseRate.Add(randomFactor)
-		if result.LessThan(decimal.NewFromFloat(-0.05)) {
-			result = decimal.NewFromFloat(-0.05) // Cap at -5%
-		}
-		return result
+		return fmce.config.InflationBounds.Clamp(result)
 	}
 
-	return inflationRate
+	return fmce.config.InflationBounds.Clamp(inflationRate)
 }
 
 // generateHistoricalCOLARate generates COLA rate using historical data
-func (fmce *FERSMonteCarloEngine) generateHistoricalCOLARate(rng *rand.Rand) decimal.Decimal {
-	if fmce.historicalData == nil {
+func (fmce *FERSMonteCarloEngine) generateHistoricalCOLARate(year int, rng *rand.Rand) decimal.Decimal {
+	if fmce.historicalData == nil || year == 0 {
 		// Fall back to statistical generation
 		baseRate := fmce.baseConfig.GlobalAssumptions.COLAGeneralRate
 		variability := fmce.config.COLAVariability
 		randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 		result := baseRate.Add(randomFactor)
-		if result.LessThan(decimal.NewFromFloat(-0.02)) {
-			result = decimal.NewFromFloat(-0.02) // Cap at -2%
-		}
-		return result
-	}
-
-	// Get a random historical year
-	year, err := fmce.historicalData.GetRandomHistoricalYear()
-	if err != nil {
-		// Fall back to statistical generation
-		baseRate := fmce.baseConfig.GlobalAssumptions.COLAGeneralRate
-		variability := fmce.config.COLAVariability
-		randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
-		result := baseRate.Add(randomFactor)
-		if result.LessThan(decimal.NewFromFloat(-0.02)) {
-			result = decimal.NewFromFloat(-0.02) // Cap at -2%
-		}
-		return result
+		return fmce.config.COLABounds.Clamp(result)
 	}
 
 	// Get COLA rate for that year
@@ -364,13 +560,10 @@ func (fmce *FERSMonteCarloEngine) generateHistoricalCOLARate(rng *rand.Rand) dec
 		variability := fmce.config.COLAVariability
 		randomFactor := decimal.NewFromFloat(rng.NormFloat64()).Mul(variability)
 		result := baseRate.Add(randomFactor)
-		if result.LessThan(decimal.NewFromFloat(-0.02)) {
-			result = decimal.NewFromFloat(-0.02) // Cap at -2%
-		}
-		return result
+		return fmce.config.COLABounds.Clamp(result)
 	}
 
-	return colaRate
+	return fmce.config.COLABounds.Clamp(colaRate)
 }
 
 // runSingleFERSSimulation runs a single FERS simulation with given market conditions
@@ -429,8 +622,20 @@ func (fmce *FERSMonteCarloEngine) createModifiedConfig(marketCondition MarketCon
 	modifiedConfig.GlobalAssumptions.COLAGeneralRate = marketCondition.COLARate
 	modifiedConfig.GlobalAssumptions.FEHBPremiumInflation = marketCondition.FEHBInflation
 
-	// Modify TSP returns (this would require extending the domain model)
-	// For now, we'll use the base returns and let the calculation engine handle variability
+	// Grow each participant's TSP at the simulated fund returns weighted by their allocation,
+	// as the deterministic fund-level model does with expected returns
+	modifiedConfig.GlobalAssumptions.TSPReturnModel = TSPReturnModelFund
+	modifiedConfig.GlobalAssumptions.TSPFundReturns = &domain.TSPFundReturns{
+		CFund: marketCondition.TSPReturns["C"],
+		SFund: marketCondition.TSPReturns["S"],
+		IFund: marketCondition.TSPReturns["I"],
+		FFund: marketCondition.TSPReturns["F"],
+		GFund: marketCondition.TSPReturns["G"],
+	}
+	modifiedConfig.GlobalAssumptions.MarketPath = marketCondition.Path
+	if !modifiedConfig.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation.Total().IsPositive() {
+		modifiedConfig.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation = fmce.config.DefaultTSPAllocation
+	}
 
 	return &modifiedConfig
 }
@@ -445,6 +650,16 @@ func (fmce *FERSMonteCarloEngine) calculateFERSSummary(simulations []FERSMonteCa
 		}
 	}
 	successRate := decimal.NewFromFloat(float64(successCount)).Div(decimal.NewFromFloat(float64(len(simulations))))
+	stdErr := successRateStdErr(successRate, len(simulations))
+
+	// Count every simulation that breaches an IRMAA threshold, successful or not
+	breachCount := 0
+	for _, sim := range simulations {
+		if analysis := sim.ScenarioSummary.IRMAAAnalysis; analysis != nil && len(analysis.YearsWithBreaches) > 0 {
+			breachCount++
+		}
+	}
+	irmaaBreachProbability := decimal.NewFromInt(int64(breachCount)).Div(decimal.NewFromInt(int64(len(simulations))))
 
 	// Calculate percentiles for lifetime income
 	lifetimeIncomes := make([]decimal.Decimal, 0, len(simulations))
@@ -478,15 +693,63 @@ func (fmce *FERSMonteCarloEngine) calculateFERSSummary(simulations []FERSMonteCa
 	}
 
 	return &FERSMonteCarloResult{
-		BaseScenarioName:     baseScenarioName,
-		NumSimulations:       fmce.config.NumSimulations,
-		ProjectionYears:      fmce.config.ProjectionYears,
-		SuccessRate:          successRate,
-		MedianLifetimeIncome: medianLifetimeIncome,
-		MedianTSPLongevity:   medianTSPLongevity,
-		PercentileRanges:     percentileRanges,
-		Simulations:          simulations,
-		MarketConditions:     marketConditions,
+		BaseScenarioName:       baseScenarioName,
+		NumSimulations:         fmce.config.NumSimulations,
+		ProjectionYears:        fmce.config.ProjectionYears,
+		Seed:                   fmce.config.Seed,
+		SuccessRate:            successRate,
+		SuccessRateStdErr:      stdErr,
+		SuccessRateMargin:      successRateMargin(successRate, len(simulations)),
+		MedianLifetimeIncome:   medianLifetimeIncome,
+		MedianTSPLongevity:     medianTSPLongevity,
+		PercentileRanges:       percentileRanges,
+		IRMAABreachProbability: irmaaBreachProbability,
+		YearlyBands:            calculateYearlyBands(simulations),
+		Simulations:            simulations,
+		MarketConditions:       marketConditions,
+	}
+}
+
+// calculateYearlyBands calculates the TSP balance and net income percentiles of every
+// projection year across the simulations that produced a projection
+func calculateYearlyBands(simulations []FERSMonteCarloSimulation) []FERSYearBands {
+	years := 0
+	for _, sim := range simulations {
+		years = max(years, len(sim.ScenarioSummary.Projection))
+	}
+
+	bands := make([]FERSYearBands, 0, years)
+	for y := 0; y < years; y++ {
+		var balances, incomes []decimal.Decimal
+		calendarYear := 0
+		for _, sim := range simulations {
+			if projection := sim.ScenarioSummary.Projection; y < len(projection) {
+				balances = append(balances, projection[y].TotalTSPBalance())
+				incomes = append(incomes, projection[y].NetIncome)
+				calendarYear = projection[y].Date.Year()
+			}
+		}
+		bands = append(bands, FERSYearBands{
+			Year:       calendarYear,
+			TSPBalance: sortedPercentiles(balances),
+			NetIncome:  sortedPercentiles(incomes),
+		})
+	}
+	return bands
+}
+
+// sortedPercentiles sorts values and returns their 10th, 25th, 50th, 75th and 90th percentiles
+func sortedPercentiles(values []decimal.Decimal) map[string]decimal.Decimal {
+	if len(values) == 0 {
+		return calculatePercentiles(values)
+	}
+	slices.SortFunc(values, func(a, b decimal.Decimal) int { return a.Cmp(b) })
+	return map[string]decimal.Decimal{
+		"10th": getPercentile(values, 0.1),
+		"25th": getPercentile(values, 0.25),
+		"50th": getPercentile(values, 0.5),
+		"75th": getPercentile(values, 0.75),
+		"90th": getPercentile(values, 0.9),
 	}
 }
 
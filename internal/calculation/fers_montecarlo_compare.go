@@ -0,0 +1,105 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// FERSMonteCarloComparison holds Monte Carlo results for several scenarios run against the
+// same simulated market paths (common random numbers). Because every scenario faces
+// identical returns, inflation and COLAs on a given path, differences between them reflect
+// the strategies rather than sampling noise.
+type FERSMonteCarloComparison struct {
+	Seed             int64                   `json:"seed"`
+	NumSimulations   int                     `json:"numSimulations"`
+	MarketConditions []MarketCondition       `json:"marketConditions"`
+	Results          []*FERSMonteCarloResult `json:"results"`
+	Differences      []FERSPairedDifference  `json:"differences"` // each alternative against the first scenario
+}
+
+// FERSPairedDifference summarizes path-by-path differences between an alternative and the
+// baseline scenario. Only paths both scenarios completed are paired.
+type FERSPairedDifference struct {
+	ScenarioName             string                     `json:"scenarioName"`
+	BaselineName             string                     `json:"baselineName"`
+	PairedPaths              int                        `json:"pairedPaths"`
+	MeanLifetimeIncomeDiff   decimal.Decimal            `json:"meanLifetimeIncomeDiff"`
+	MedianLifetimeIncomeDiff decimal.Decimal            `json:"medianLifetimeIncomeDiff"`
+	LifetimeIncomeDiff       map[string]decimal.Decimal `json:"lifetimeIncomeDiff"` // 10th, 25th, 50th, 75th, 90th percentiles
+	WinRate                  decimal.Decimal            `json:"winRate"`            // share of paired paths where the alternative has more lifetime income
+	SuccessRateDiff          decimal.Decimal            `json:"successRateDiff"`
+}
+
+// RunFERSMonteCarloComparison runs each named scenario against one shared set of market
+// paths. The first scenario is the baseline the others are differenced against.
+func (fmce *FERSMonteCarloEngine) RunFERSMonteCarloComparison(ctx context.Context, scenarioNames []string) (*FERSMonteCarloComparison, error) {
+	if len(scenarioNames) < 2 {
+		return nil, fmt.Errorf("a comparison needs at least two scenarios, got %d", len(scenarioNames))
+	}
+
+	marketConditions := fmce.marketPaths(0, fmce.config.NumSimulations)
+	comparison := &FERSMonteCarloComparison{
+		Seed:             fmce.config.Seed,
+		NumSimulations:   fmce.config.NumSimulations,
+		MarketConditions: marketConditions,
+	}
+	done := fmce.progressReporter(0, len(scenarioNames)*len(marketConditions))
+	for _, name := range scenarioNames {
+		scenario, err := fmce.findScenario(name)
+		if err != nil {
+			return nil, err
+		}
+		simulations, err := fmce.runScenarioOnPaths(ctx, scenario, marketConditions, done)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Results = append(comparison.Results, fmce.calculateFERSSummary(simulations, marketConditions, name))
+	}
+
+	baseline := comparison.Results[0]
+	for _, alternative := range comparison.Results[1:] {
+		comparison.Differences = append(comparison.Differences, pairedDifference(baseline, alternative))
+	}
+	return comparison, nil
+}
+
+// pairedDifference differences an alternative's lifetime income against the baseline's on
+// each path both completed
+func pairedDifference(baseline, alternative *FERSMonteCarloResult) FERSPairedDifference {
+	diffs := make([]decimal.Decimal, 0, len(baseline.Simulations))
+	total := decimal.Zero
+	wins := 0
+	for i := range baseline.Simulations {
+		if i >= len(alternative.Simulations) {
+			break
+		}
+		base, alt := baseline.Simulations[i].ScenarioSummary, alternative.Simulations[i].ScenarioSummary
+		if len(base.Projection) == 0 || len(alt.Projection) == 0 {
+			continue
+		}
+		diff := alt.TotalLifetimeIncome.Sub(base.TotalLifetimeIncome)
+		diffs = append(diffs, diff)
+		total = total.Add(diff)
+		if diff.IsPositive() {
+			wins++
+		}
+	}
+
+	result := FERSPairedDifference{
+		ScenarioName:    alternative.BaseScenarioName,
+		BaselineName:    baseline.BaseScenarioName,
+		PairedPaths:     len(diffs),
+		SuccessRateDiff: alternative.SuccessRate.Sub(baseline.SuccessRate),
+	}
+	if len(diffs) == 0 {
+		return result
+	}
+	count := decimal.NewFromInt(int64(len(diffs)))
+	result.MeanLifetimeIncomeDiff = total.Div(count)
+	result.WinRate = decimal.NewFromInt(int64(wins)).Div(count)
+	result.LifetimeIncomeDiff = calculatePercentiles(diffs)
+	result.MedianLifetimeIncomeDiff = calculateMedian(diffs)
+	return result
+}
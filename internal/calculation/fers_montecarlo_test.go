@@ -2,7 +2,10 @@ package calculation
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math/rand"
+	"runtime"
 	"testing"
 	"time"
 
@@ -153,6 +156,7 @@ func TestFERSMonteCarloEngine_calculateFERSSummary(t *testing.T) {
 				TSPLongevity:        25,
 				Year5NetIncome:      decimal.NewFromFloat(150000),
 				Year10NetIncome:     decimal.NewFromFloat(160000),
+				IRMAAAnalysis:       &domain.IRMAAAnalysis{YearsWithBreaches: []int{2031}},
 			},
 		},
 		{
@@ -223,6 +227,12 @@ func TestFERSMonteCarloEngine_calculateFERSSummary(t *testing.T) {
 	if len(result.PercentileRanges.TSPLongevity) != 5 {
 		t.Errorf("Expected 5 percentile ranges for TSP longevity, got %d", len(result.PercentileRanges.TSPLongevity))
 	}
+
+	// One of the three simulations crosses an IRMAA threshold
+	expectedBreach := decimal.NewFromInt(1).Div(decimal.NewFromInt(3))
+	if !result.IRMAABreachProbability.Equal(expectedBreach) {
+		t.Errorf("Expected IRMAABreachProbability to be %v, got %v", expectedBreach, result.IRMAABreachProbability)
+	}
 }
 
 func TestFERSMonteCarloEngine_RunFERSMonteCarlo_InvalidScenario(t *testing.T) {
@@ -299,3 +309,378 @@ func createTestConfig() *domain.Configuration {
 		},
 	}
 }
+
+func TestFERSMonteCarloEngine_generateMarketConditionsRecordsHistoricalYear(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+
+	engine := NewFERSMonteCarloEngine(createTestConfig(), hdm)
+	first := engine.generateMarketConditions(rand.New(rand.NewSource(7)))
+	second := engine.generateMarketConditions(rand.New(rand.NewSource(7)))
+
+	if first.HistoricalYear == 0 {
+		t.Fatal("Expected the sampled historical year to be recorded")
+	}
+	if first.HistoricalYear != second.HistoricalYear {
+		t.Errorf("Expected the same seed to draw the same year, got %d and %d", first.HistoricalYear, second.HistoricalYear)
+	}
+	expected, err := hdm.GetTSPReturn("C", first.HistoricalYear)
+	if err != nil {
+		t.Fatalf("Failed to get C fund return: %v", err)
+	}
+	if !first.TSPReturns["C"].Equal(expected) {
+		t.Errorf("Expected C fund return from %d (%s), got %s", first.HistoricalYear, expected, first.TSPReturns["C"])
+	}
+}
+
+func TestFERSMonteCarloEngine_ConfiguredBounds(t *testing.T) {
+	config := createTestConfig()
+	zero := decimal.Zero
+	ceiling := decimal.NewFromFloat(0.15)
+	config.GlobalAssumptions.MonteCarloSettings.COLABounds = &domain.RateBounds{Floor: &zero}
+	config.GlobalAssumptions.MonteCarloSettings.TSPReturnBounds = &domain.RateBounds{Ceiling: &ceiling}
+
+	engine := NewFERSMonteCarloEngine(config, nil)
+	engine.config.UseHistorical = false
+	engine.config.COLAVariability = decimal.NewFromFloat(0.05)
+	engine.config.TSPReturnVariability = decimal.NewFromFloat(0.2)
+
+	rng := rand.New(rand.NewSource(99))
+	for i := 0; i < 200; i++ {
+		condition := engine.generateMarketConditions(rng)
+		if condition.COLARate.LessThan(decimal.Zero) {
+			t.Fatalf("Expected COLA floored at 0, got %s", condition.COLARate)
+		}
+		for fund, r := range condition.TSPReturns {
+			if r.GreaterThan(ceiling) {
+				t.Fatalf("Expected %s return capped at 15%%, got %s", fund, r)
+			}
+		}
+	}
+
+	// Unconfigured bounds keep the default floors
+	if !engine.config.InflationBounds.Floor.Equal(decimal.NewFromFloat(-0.05)) {
+		t.Errorf("Expected default inflation floor of -5%%, got %s", engine.config.InflationBounds.Floor)
+	}
+}
+
+func TestFERSMonteCarloEngine_createModifiedConfigUsesFundReturns(t *testing.T) {
+	engine := NewFERSMonteCarloEngine(createTestConfig(), nil)
+	condition := MarketCondition{TSPReturns: map[string]decimal.Decimal{
+		"C": decimal.NewFromFloat(0.12),
+		"S": decimal.NewFromFloat(0.15),
+		"I": decimal.NewFromFloat(0.08),
+		"F": decimal.NewFromFloat(0.03),
+		"G": decimal.NewFromFloat(0.02),
+	}}
+
+	modified := engine.createModifiedConfig(condition)
+	assumptions := modified.GlobalAssumptions
+	if assumptions.TSPReturnModel != TSPReturnModelFund {
+		t.Fatalf("Expected the fund return model, got %q", assumptions.TSPReturnModel)
+	}
+	if assumptions.TSPFundReturns == nil || !assumptions.TSPFundReturns.SFund.Equal(decimal.NewFromFloat(0.15)) {
+		t.Errorf("Expected simulated S fund return of 15%%, got %+v", assumptions.TSPFundReturns)
+	}
+	// An unset default allocation falls back to the engine's default
+	if !assumptions.MonteCarloSettings.DefaultTSPAllocation.CFund.Equal(decimal.NewFromFloat(0.6)) {
+		t.Errorf("Expected the 60%% C fund default allocation, got %s", assumptions.MonteCarloSettings.DefaultTSPAllocation.CFund)
+	}
+	if engine.baseConfig.GlobalAssumptions.TSPFundReturns != nil {
+		t.Error("Expected the base configuration to be left unchanged")
+	}
+}
+
+func TestFERSMonteCarloEngine_RunFERSMonteCarloComparisonSharesPaths(t *testing.T) {
+	config := createTestConfig()
+	delayed := config.Scenarios[0]
+	delayed.Name = "Delayed SS"
+	delayed.ParticipantScenarios = map[string]domain.ParticipantScenario{
+		"Test Participant": {ParticipantName: "Test Participant", SSStartAge: 67},
+	}
+	config.Scenarios = append(config.Scenarios, delayed)
+
+	engine := NewFERSMonteCarloEngine(config, nil)
+	engine.SetSimulations(4)
+	engine.SetSeed(42)
+
+	comparison, err := engine.RunFERSMonteCarloComparison(context.Background(), []string{"Test Scenario", "Delayed SS"})
+	if err != nil {
+		t.Fatalf("Comparison failed: %v", err)
+	}
+	if len(comparison.Results) != 2 || len(comparison.Differences) != 1 {
+		t.Fatalf("Expected 2 results and 1 difference, got %d and %d", len(comparison.Results), len(comparison.Differences))
+	}
+	if comparison.Differences[0].PairedPaths != 4 {
+		t.Errorf("Expected every path to be paired, got %d", comparison.Differences[0].PairedPaths)
+	}
+	for _, result := range comparison.Results {
+		for i, sim := range result.Simulations {
+			if !sim.MarketCondition.InflationRate.Equal(comparison.MarketConditions[i].InflationRate) ||
+				!sim.MarketCondition.TSPReturns["C"].Equal(comparison.MarketConditions[i].TSPReturns["C"]) {
+				t.Errorf("%s path %d did not face the shared market conditions", result.BaseScenarioName, i)
+			}
+		}
+	}
+
+	// The same seed reproduces the paths
+	single, err := engine.RunFERSMonteCarlo(context.Background(), "Delayed SS")
+	if err != nil {
+		t.Fatalf("Single run failed: %v", err)
+	}
+	for i, condition := range single.MarketConditions {
+		if !condition.TSPReturns["S"].Equal(comparison.MarketConditions[i].TSPReturns["S"]) {
+			t.Errorf("Path %d differs between runs with the same seed", i)
+		}
+	}
+
+	if _, err := engine.RunFERSMonteCarloComparison(context.Background(), []string{"Test Scenario"}); err == nil {
+		t.Error("Expected an error comparing a single scenario")
+	}
+}
+
+func TestPairedDifference(t *testing.T) {
+	result := func(name string, incomes ...int64) *FERSMonteCarloResult {
+		r := &FERSMonteCarloResult{BaseScenarioName: name, SuccessRate: decimal.NewFromFloat(0.5)}
+		for i, income := range incomes {
+			summary := domain.ScenarioSummary{TotalLifetimeIncome: decimal.NewFromInt(income)}
+			if income > 0 {
+				summary.Projection = []domain.AnnualCashFlow{{}}
+			}
+			r.Simulations = append(r.Simulations, FERSMonteCarloSimulation{SimulationID: i, ScenarioSummary: summary})
+		}
+		return r
+	}
+	baseline := result("Base", 100, 200, 300, 400)
+	alternative := result("Alt", 150, 190, 0, 450)
+	alternative.SuccessRate = decimal.NewFromFloat(0.75)
+
+	diff := pairedDifference(baseline, alternative)
+
+	// The third path did not complete for the alternative and is not paired
+	if diff.PairedPaths != 3 {
+		t.Fatalf("Expected 3 paired paths, got %d", diff.PairedPaths)
+	}
+	if !diff.MeanLifetimeIncomeDiff.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected mean difference 30, got %s", diff.MeanLifetimeIncomeDiff)
+	}
+	if !diff.MedianLifetimeIncomeDiff.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected median difference 50, got %s", diff.MedianLifetimeIncomeDiff)
+	}
+	if diff.WinRate.StringFixed(4) != "0.6667" {
+		t.Errorf("Expected win rate 2/3, got %s", diff.WinRate)
+	}
+	if !diff.SuccessRateDiff.Equal(decimal.NewFromFloat(0.25)) {
+		t.Errorf("Expected success rate difference 0.25, got %s", diff.SuccessRateDiff)
+	}
+}
+
+func TestFERSMonteCarloEngine_BlockBootstrapPath(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+
+	config := createTestConfig()
+	config.GlobalAssumptions.ProjectionYears = 10
+	engine := NewFERSMonteCarloEngine(config, hdm)
+	engine.SetBlockLength(3)
+
+	condition := engine.generateMarketConditions(rand.New(rand.NewSource(11)))
+	if len(condition.Path) != 10 {
+		t.Fatalf("Expected a 10-year path, got %d years", len(condition.Path))
+	}
+	if condition.HistoricalYear != condition.Path[0].HistoricalYear {
+		t.Errorf("Expected the first year's conditions at the top level, got %d and %d", condition.HistoricalYear, condition.Path[0].HistoricalYear)
+	}
+	// Years within each three-year block are consecutive, wrapping 2023 back to 2020
+	for i, year := range condition.Path {
+		if i%3 == 0 {
+			continue
+		}
+		prev := condition.Path[i-1].HistoricalYear
+		if year.HistoricalYear != prev+1 && !(prev == 2023 && year.HistoricalYear == 2020) {
+			t.Errorf("Year %d: expected %d to follow %d within its block", i, year.HistoricalYear, prev)
+		}
+		expected, err := hdm.GetInflationRate(year.HistoricalYear)
+		if err != nil {
+			t.Fatalf("Failed to get inflation rate: %v", err)
+		}
+		if !year.InflationRate.Equal(expected) {
+			t.Errorf("Year %d: expected %d inflation %s, got %s", i, year.HistoricalYear, expected, year.InflationRate)
+		}
+	}
+
+	if modified := engine.createModifiedConfig(condition); len(modified.GlobalAssumptions.MarketPath) != 10 {
+		t.Errorf("Expected the path passed to the projection, got %d years", len(modified.GlobalAssumptions.MarketPath))
+	}
+
+	// Without a block length each simulation holds one sampled year
+	engine.SetBlockLength(1)
+	if single := engine.generateMarketConditions(rand.New(rand.NewSource(11))); single.Path != nil {
+		t.Errorf("Expected no path without a block length, got %d years", len(single.Path))
+	}
+}
+
+func TestProjectionFollowsMarketPath(t *testing.T) {
+	config := createTestConfig()
+	engine := NewFERSMonteCarloEngine(config, nil)
+	returns := map[string]decimal.Decimal{"C": decimal.NewFromFloat(0.07), "S": decimal.NewFromFloat(0.07), "I": decimal.NewFromFloat(0.07), "F": decimal.NewFromFloat(0.07), "G": decimal.NewFromFloat(0.07)}
+
+	run := func(path []domain.MarketYear) domain.ScenarioSummary {
+		modified := engine.createModifiedConfig(MarketCondition{
+			TSPReturns:    returns,
+			InflationRate: decimal.NewFromFloat(0.025),
+			COLARate:      decimal.NewFromFloat(0.025),
+			FEHBInflation: decimal.NewFromFloat(0.04),
+			Path:          path,
+		})
+		summary, err := engine.calculationEngine.RunGenericScenario(context.Background(), modified, &config.Scenarios[0])
+		if err != nil {
+			t.Fatalf("Scenario failed: %v", err)
+		}
+		return *summary
+	}
+
+	steady := run(nil)
+	crash := domain.TSPFundReturns{CFund: decimal.NewFromFloat(-0.3), SFund: decimal.NewFromFloat(-0.3), IFund: decimal.NewFromFloat(-0.3), FFund: decimal.NewFromFloat(-0.3), GFund: decimal.NewFromFloat(-0.3)}
+	path := make([]domain.MarketYear, 3)
+	for i := range path {
+		path[i] = domain.MarketYear{InflationRate: decimal.NewFromFloat(0.025), COLARate: decimal.NewFromFloat(0.025), FEHBInflation: decimal.NewFromFloat(0.04), FundReturns: crash}
+	}
+	bear := run(path)
+
+	// Three years of losses leave less TSP than steady returns; later years revert to the assumed rates
+	if !bear.Projection[2].TotalTSPBalance().LessThan(steady.Projection[2].TotalTSPBalance()) {
+		t.Errorf("Expected a smaller TSP balance after a three-year bear market, got %s vs %s",
+			bear.Projection[2].TotalTSPBalance(), steady.Projection[2].TotalTSPBalance())
+	}
+}
+
+func TestFERSMonteCarloEngine_YearlyBands(t *testing.T) {
+	engine := NewFERSMonteCarloEngine(createTestConfig(), nil)
+	engine.SetSimulations(6)
+	engine.SetSeed(5)
+	engine.config.UseHistorical = false
+
+	result, err := engine.RunFERSMonteCarlo(context.Background(), "Test Scenario")
+	if err != nil {
+		t.Fatalf("Simulation failed: %v", err)
+	}
+	projection := result.Simulations[0].ScenarioSummary.Projection
+	if len(result.YearlyBands) != len(projection) {
+		t.Fatalf("Expected a band for each of %d projection years, got %d", len(projection), len(result.YearlyBands))
+	}
+	for i, band := range result.YearlyBands {
+		if band.Year != projection[i].Date.Year() {
+			t.Errorf("Band %d: expected calendar year %d, got %d", i, projection[i].Date.Year(), band.Year)
+		}
+		for _, series := range []map[string]decimal.Decimal{band.TSPBalance, band.NetIncome} {
+			if series["10th"].GreaterThan(series["50th"]) || series["50th"].GreaterThan(series["90th"]) {
+				t.Errorf("%d: percentiles out of order: %v", band.Year, series)
+			}
+		}
+	}
+	// Market variability fans the TSP balances out over time
+	last := result.YearlyBands[len(result.YearlyBands)-1].TSPBalance
+	if !last["90th"].GreaterThan(last["10th"]) {
+		t.Errorf("Expected the final year's TSP balances to spread, got %v", last)
+	}
+}
+
+func TestFERSMonteCarloEngine_SameSeedRepeatsRun(t *testing.T) {
+	run := func(seed int64, procs int) []byte {
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+		engine := NewFERSMonteCarloEngine(createTestConfig(), nil)
+		engine.SetSimulations(8)
+		engine.SetSeed(seed)
+		result, err := engine.RunFERSMonteCarlo(context.Background(), "Test Scenario")
+		if err != nil {
+			t.Fatalf("Simulation failed: %v", err)
+		}
+		if result.Seed != seed {
+			t.Errorf("Expected the result to report seed %d, got %d", seed, result.Seed)
+		}
+		out, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Failed to marshal result: %v", err)
+		}
+		return out
+	}
+
+	// The simulations finish in a different order on one thread than on many, but
+	// each draws only from its own seeded source
+	serial := run(7, 1)
+	parallel := run(7, runtime.NumCPU())
+	if string(serial) != string(parallel) {
+		t.Error("Expected the same seed to give identical results regardless of scheduling")
+	}
+	if string(run(8, runtime.NumCPU())) == string(parallel) {
+		t.Error("Expected a different seed to give different results")
+	}
+}
+
+func TestFERSMonteCarloEngine_ProgressAndCancellation(t *testing.T) {
+	engine := NewFERSMonteCarloEngine(createTestConfig(), nil)
+	engine.SetSimulations(12)
+	engine.SetWorkers(3)
+	engine.SetSeed(1)
+
+	var reported []int
+	engine.SetProgress(func(completed, total int) {
+		if total != 12 {
+			t.Errorf("Expected a total of 12 simulations, got %d", total)
+		}
+		reported = append(reported, completed)
+	})
+	if _, err := engine.RunFERSMonteCarlo(context.Background(), "Test Scenario"); err != nil {
+		t.Fatalf("Simulation failed: %v", err)
+	}
+	for i, completed := range reported {
+		if completed != i+1 {
+			t.Fatalf("Expected progress to count up one simulation at a time, got %v", reported)
+		}
+	}
+	if len(reported) != 12 {
+		t.Errorf("Expected 12 progress reports, got %d", len(reported))
+	}
+
+	// Cancelling partway through stops handing out simulations
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := 0
+	engine.SetProgress(func(completed, total int) {
+		finished = completed
+		if completed == 2 {
+			cancel()
+		}
+	})
+	if _, err := engine.RunFERSMonteCarlo(ctx, "Test Scenario"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the run to be cancelled, got %v", err)
+	}
+	if finished >= 12 {
+		t.Errorf("Expected the cancelled run to stop early, but %d simulations finished", finished)
+	}
+
+	// A comparison counts every scenario's simulations toward one total
+	engine.SetProgress(func(completed, total int) {
+		if total != 24 {
+			t.Errorf("Expected a comparison total of 24 simulations, got %d", total)
+		}
+	})
+	if _, err := engine.RunFERSMonteCarloComparison(ctx, []string{"Test Scenario", "Test Scenario"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected an already cancelled comparison to fail, got %v", err)
+	}
+	if _, err := engine.RunFERSMonteCarloComparison(context.Background(), []string{"Test Scenario", "Test Scenario"}); err != nil {
+		t.Errorf("Comparison failed: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalculateFERSPension(t *testing.T) {
@@ -82,6 +83,13 @@ func TestApplyFERSPensionCOLA(t *testing.T) {
 			annuitantAge:    62,
 			expectedPension: decimal.NewFromInt(30900), // 30000 * 1.03 (4% - 1%)
 		},
+		{
+			name:            "No COLA when CPI falls",
+			currentPension:  decimal.NewFromInt(30000),
+			inflationRate:   decimal.NewFromFloat(-0.01),
+			annuitantAge:    65,
+			expectedPension: decimal.NewFromInt(30000),
+		},
 	}
 
 	for _, tt := range tests {
@@ -374,3 +382,216 @@ func TestFERSEligibilityScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestDeferredAnnuityReduction(t *testing.T) {
+	tests := []struct {
+		name              string
+		startAge          int
+		serviceYears      int64
+		expectedReduction float64
+		expectedAllowed   bool
+	}{
+		{"Age 62 with 5 years", 62, 5, 0, true},
+		{"Age 60 with 20 years", 60, 20, 0, true},
+		{"Age 60 with 15 years is MRA+10", 60, 15, 0.10, true},
+		{"MRA 57 with 10 years", 57, 10, 0.25, true},
+		{"Age 60 with 8 years", 60, 8, 0, false},
+		{"Below MRA", 56, 25, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reduction, ok := DeferredAnnuityReduction(tt.startAge, 57, decimal.NewFromInt(tt.serviceYears))
+			assert.Equal(t, tt.expectedAllowed, ok)
+			assert.True(t, reduction.Equal(decimal.NewFromFloat(tt.expectedReduction)),
+				"Expected %v, got %s", tt.expectedReduction, reduction)
+		})
+	}
+}
+
+func TestProjectionDeferredAnnuity(t *testing.T) {
+	hire := time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC)
+	separation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	salary := decimal.NewFromInt(120000)
+	high3 := decimal.NewFromInt(100000)
+	fehb := decimal.NewFromInt(300)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:                    "Casey",
+			IsFederal:               true,
+			BirthDate:               time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:                &hire,
+			CurrentSalary:           &salary,
+			High3Salary:             &high3,
+			SSBenefit62:             decimal.NewFromInt(1500),
+			SSBenefitFRA:            decimal.NewFromInt(2100),
+			SSBenefit70:             decimal.NewFromInt(2600),
+			FEHBPremiumPerPayPeriod: &fehb,
+			IsPrimaryFEHBHolder:     true,
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "deferred",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Casey": {ParticipantName: "Casey", SeparationDate: &separation, AnnuityStartAge: 62, SSStartAge: 67},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 14}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	if len(projection) != 14 {
+		t.Fatalf("expected 14 projection years, got %d", len(projection))
+	}
+
+	for i, cf := range projection {
+		year := cf.Date.Year()
+		if year >= 2026 {
+			assert.True(t, cf.Salaries.Get("Casey").IsZero(), "no salary after separation in %d", year)
+			assert.True(t, cf.FEHBPremium.IsZero(), "no FEHB continuation in %d", year)
+		}
+		assert.True(t, cf.FERSSupplements.Get("Casey").IsZero(), "no FERS supplement in %d", year)
+		if year < 2037 {
+			assert.True(t, cf.Pensions.Get("Casey").IsZero(), "annuity deferred until 62, got pension in %d", year)
+		} else if year == 2037 {
+			// 21 years x 1.1% (62 with 20+ years) x $100k High-3
+			assert.InDelta(t, 23100, cf.Pensions.Get("Casey").InexactFloat64(), 50, "index %d", i)
+		}
+	}
+}
+
+func TestSpecialProvisionRules(t *testing.T) {
+	high3 := decimal.NewFromInt(100000)
+
+	// 1.7% for the first 20 years, 1% after
+	assert.True(t, SpecialProvisionPension(high3, decimal.NewFromInt(20)).Equal(decimal.NewFromInt(34000)))
+	assert.True(t, SpecialProvisionPension(high3, decimal.NewFromInt(25)).Equal(decimal.NewFromInt(39000)))
+	assert.True(t, SpecialProvisionPension(high3, decimal.NewFromInt(15)).Equal(decimal.NewFromInt(25500)))
+
+	assert.True(t, SpecialProvisionEligible(50, decimal.NewFromInt(20)))
+	assert.True(t, SpecialProvisionEligible(45, decimal.NewFromInt(25)))
+	assert.False(t, SpecialProvisionEligible(49, decimal.NewFromInt(22)))
+	assert.False(t, SpecialProvisionEligible(55, decimal.NewFromInt(19)))
+}
+
+func TestProjectionSpecialProvision(t *testing.T) {
+	hire := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	salary := decimal.NewFromInt(110000)
+	high3 := decimal.NewFromInt(100000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:             "Riley",
+			IsFederal:        true,
+			SpecialProvision: domain.SpecialProvisionLawEnforcement,
+			BirthDate:        time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:         &hire,
+			CurrentSalary:    &salary,
+			High3Salary:      &high3,
+			SSBenefit62:      decimal.NewFromInt(1600),
+			SSBenefitFRA:     decimal.NewFromInt(2200),
+			SSBenefit70:      decimal.NewFromInt(2700),
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "leo",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Riley": {ParticipantName: "Riley", RetirementDate: &retire, SSStartAge: 67},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3, COLAGeneralRate: decimal.NewFromFloat(0.025)}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 3)
+
+	// Retiring at 51 with 26 years: 20 x 1.7% + 6 x 1% of $100k
+	assert.InDelta(t, 40000, projection[1].Pensions.Get("Riley").InexactFloat64(), 50)
+	// Supplement starts below the MRA and the pension receives COLAs before 62 (2.5% CPI -> 2%)
+	assert.True(t, projection[1].FERSSupplements.Get("Riley").IsPositive())
+	assert.InDelta(t, 40800, projection[2].Pensions.Get("Riley").InexactFloat64(), 50)
+
+	// The supplement is taxed as pension income, so the return agrees with the published AGI
+	retired := projection[2]
+	require.True(t, retired.FERSSupplements.Get("Riley").IsPositive())
+	assert.Equal(t, retired.MAGIBreakdown.AGI.String(), retired.FederalTaxableIncome.Add(retired.FederalStandardDeduction).String())
+}
+
+func TestCalculateCSRSPension(t *testing.T) {
+	high3 := decimal.NewFromInt(100000)
+	tests := []struct {
+		serviceYears int64
+		expected     int64
+	}{
+		{3, 4500},   // 3 x 1.5%
+		{10, 16250}, // 5 x 1.5% + 5 x 1.75%
+		{30, 56250}, // + 20 x 2%
+		{42, 80000}, // capped at 80%
+	}
+	for _, tt := range tests {
+		got := CalculateCSRSPension(high3, decimal.NewFromInt(tt.serviceYears))
+		assert.True(t, got.Equal(decimal.NewFromInt(tt.expected)), "%d years: expected %d, got %s", tt.serviceYears, tt.expected, got)
+	}
+
+	assert.True(t, CSRSOffsetReduction(decimal.NewFromInt(1500), decimal.NewFromInt(20)).Equal(decimal.NewFromInt(9000)))
+	assert.True(t, ApplyCSRSPensionCOLA(high3, decimal.NewFromFloat(0.04)).Equal(decimal.NewFromInt(104000)))
+}
+
+func TestProjectionCSRS(t *testing.T) {
+	hire := time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	salary := decimal.NewFromInt(110000)
+	high3 := decimal.NewFromInt(100000)
+	zero := decimal.Zero
+	contribution := decimal.NewFromFloat(0.05)
+	participant := func(name, system string) domain.Participant {
+		return domain.Participant{
+			Name:                   name,
+			IsFederal:              true,
+			RetirementSystem:       system,
+			BirthDate:              time.Date(1966, 1, 1, 0, 0, 0, 0, time.UTC),
+			HireDate:               &hire,
+			CurrentSalary:          &salary,
+			High3Salary:            &high3,
+			TSPBalanceTraditional:  &zero,
+			TSPBalanceRoth:         &zero,
+			TSPContributionPercent: &contribution,
+			SSBenefit62:            decimal.NewFromInt(1500),
+			SSBenefitFRA:           decimal.NewFromInt(2100),
+			SSBenefit70:            decimal.NewFromInt(2600),
+		}
+	}
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{participant("Offset", domain.RetirementSystemCSRSOffset), participant("Pure", domain.RetirementSystemCSRS)},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "csrs",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Offset": {ParticipantName: "Offset", RetirementDate: &retire, SSStartAge: 62, TSPWithdrawalStrategy: "4_percent_rule"},
+			"Pure":   {ParticipantName: "Pure", RetirementDate: &retire, SSStartAge: 62, TSPWithdrawalStrategy: "4_percent_rule"},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 5, COLAGeneralRate: decimal.NewFromFloat(0.025)}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 5)
+
+	// 2025: employee contributions only, no agency automatic or matching contributions
+	assert.InDelta(t, 5500, projection[0].TSPBalances.Get("Pure").InexactFloat64(), 1)
+
+	// 2026: 41 years is 5 x 1.5% + 5 x 1.75% + 31 x 2% = 78.25% of high-3; no FERS supplement under CSRS
+	assert.InDelta(t, 78250, projection[1].Pensions.Get("Pure").InexactFloat64(), 50)
+	assert.True(t, projection[1].FERSSupplements.Get("Pure").IsZero())
+	// 2027: full CPI COLA before 62
+	assert.InDelta(t, 80206, projection[2].Pensions.Get("Pure").InexactFloat64(), 50)
+	// 2028: CSRS Offset annuity reduced at 62 by the service share of the age-62 benefit
+	assert.InDelta(t, 82211, projection[3].Pensions.Get("Pure").InexactFloat64(), 50)
+	assert.InDelta(t, 82211-18000, projection[3].Pensions.Get("Offset").InexactFloat64(), 50)
+
+	// Pure CSRS has no Social Security; CSRS Offset does
+	for _, cf := range projection {
+		assert.True(t, cf.SSBenefits.Get("Pure").IsZero(), "pure CSRS Social Security in %d", cf.Date.Year())
+	}
+	assert.True(t, projection[3].SSBenefits.Get("Offset").IsPositive())
+}
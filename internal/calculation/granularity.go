@@ -0,0 +1,102 @@
+package calculation
+
+import (
+	"math"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Projection granularity options for GlobalAssumptions.ProjectionGranularity.
+// The step applies to TSP growth only; salary and pension proration, RMDs and the
+// IRMAA lookback are figured for the whole year.
+const (
+	GranularityAnnual     = "annual"
+	GranularitySemiAnnual = "semi_annual"
+	GranularityQuarterly  = "quarterly"
+	GranularityMonthly    = "monthly"
+
+	// DefaultSubAnnualYears is how many leading years use the finer step when unset
+	DefaultSubAnnualYears = 5
+)
+
+// periodsPerYear returns how many steps projection year yr is divided into.
+// Only the first SubAnnualYears use the finer granularity; later years are annual.
+func periodsPerYear(assumptions *domain.GlobalAssumptions, yr int) int {
+	if assumptions == nil {
+		return 1
+	}
+	limit := assumptions.SubAnnualYears
+	if limit <= 0 {
+		limit = DefaultSubAnnualYears
+	}
+	if yr >= limit {
+		return 1
+	}
+	switch assumptions.ProjectionGranularity {
+	case GranularitySemiAnnual:
+		return 2
+	case GranularityQuarterly:
+		return 4
+	case GranularityMonthly:
+		return 12
+	default:
+		return 1
+	}
+}
+
+// growBalanceInPeriods grows a balance through a year split into equal periods.
+// The year's net cash flow (contributions less withdrawals) is spread evenly across
+// the periods, and each period compounds at the equivalent periodic rate. Periods
+// starting before switchDate use preRate and the rest use postRate, so a mid-year
+// retirement earns the pre-retirement return only while still working.
+// It returns the ending balance and the balance at the end of each period.
+func growBalanceInPeriods(start, netFlow decimal.Decimal, periods int, yearStart time.Time, switchDate *time.Time, preRate, postRate decimal.Decimal) (decimal.Decimal, []decimal.Decimal) {
+	if periods < 1 {
+		periods = 1
+	}
+	monthsPerPeriod := 12 / periods
+
+	rates := make([]decimal.Decimal, periods)
+	for i := range rates {
+		rate := postRate
+		periodStart := yearStart.AddDate(0, i*monthsPerPeriod, 0)
+		if switchDate != nil && periodStart.Before(*switchDate) {
+			rate = preRate
+		}
+		rates[i] = periodicRate(rate, periods)
+	}
+	return growBalanceAtRates(start, netFlow, rates)
+}
+
+// growBalanceAtRates grows a balance through a year split into one period per rate,
+// each rate being that period's own return, such as a month of history. The year's
+// net cash flow is spread evenly across the periods as in growBalanceInPeriods.
+func growBalanceAtRates(start, netFlow decimal.Decimal, rates []decimal.Decimal) (decimal.Decimal, []decimal.Decimal) {
+	flowPerPeriod := netFlow.Div(decimal.NewFromInt(int64(len(rates))))
+
+	balance := start
+	ends := make([]decimal.Decimal, len(rates))
+	for i, rate := range rates {
+		balance = balance.Add(flowPerPeriod)
+		if balance.LessThan(decimalZero) {
+			balance = decimalZero
+		}
+		balance = balance.Mul(onePlus(rate))
+		ends[i] = balance
+	}
+	return balance, ends
+}
+
+// periodicRate converts an annual rate to the compounding-equivalent rate per period
+func periodicRate(annual decimal.Decimal, periods int) decimal.Decimal {
+	if periods <= 1 {
+		return annual
+	}
+	base := 1 + annual.InexactFloat64()
+	if base <= 0 {
+		return decimal.NewFromInt(-1)
+	}
+	return decimal.NewFromFloat(math.Pow(base, 1/float64(periods)) - 1)
+}
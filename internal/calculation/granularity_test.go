@@ -0,0 +1,106 @@
+package calculation
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodsPerYear(t *testing.T) {
+	assumptions := &domain.GlobalAssumptions{ProjectionGranularity: GranularityQuarterly}
+	assert.Equal(t, 4, periodsPerYear(assumptions, 0))
+	assert.Equal(t, 4, periodsPerYear(assumptions, DefaultSubAnnualYears-1))
+	assert.Equal(t, 1, periodsPerYear(assumptions, DefaultSubAnnualYears))
+
+	assumptions = &domain.GlobalAssumptions{ProjectionGranularity: GranularitySemiAnnual, SubAnnualYears: 2}
+	assert.Equal(t, 2, periodsPerYear(assumptions, 1))
+	assert.Equal(t, 1, periodsPerYear(assumptions, 2))
+
+	assert.Equal(t, 12, periodsPerYear(&domain.GlobalAssumptions{ProjectionGranularity: GranularityMonthly}, 0))
+	assert.Equal(t, 1, periodsPerYear(&domain.GlobalAssumptions{}, 0))
+}
+
+func TestGrowBalanceInPeriods(t *testing.T) {
+	yearStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := decimal.NewFromInt(100000)
+	rate := decimal.NewFromFloat(0.08)
+
+	// With no cash flow, quarterly compounding at the equivalent rate matches a full year
+	end, periodEnds := growBalanceInPeriods(start, decimal.Zero, 4, yearStart, nil, rate, rate)
+	require.Len(t, periodEnds, 4)
+	assert.InDelta(t, 108000.0, end.InexactFloat64(), 0.01)
+	assert.True(t, periodEnds[3].Equal(end))
+
+	// Withdrawals spread through the year leave more invested than a start-of-year withdrawal
+	withdrawal := decimal.NewFromInt(-20000)
+	spread, _ := growBalanceInPeriods(start, withdrawal, 4, yearStart, nil, rate, rate)
+	upfront := start.Add(withdrawal).Mul(onePlus(rate))
+	assert.True(t, spread.GreaterThan(upfront))
+
+	// A July retirement earns the pre-retirement rate for the first half only
+	retire := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	split, _ := growBalanceInPeriods(start, decimal.Zero, 2, yearStart, &retire, decimal.NewFromFloat(0.10), decimal.NewFromFloat(0.04))
+	expected := 100000 * 1.048808848 * 1.019803903
+	assert.InDelta(t, expected, split.InexactFloat64(), 1.0)
+}
+
+func TestGrowBalanceAtRates(t *testing.T) {
+	start := decimal.NewFromInt(100000)
+	crash := decimal.NewFromFloat(-0.3)
+	early := append([]decimal.Decimal{crash}, make([]decimal.Decimal, 11)...)
+	late := append(make([]decimal.Decimal, 11), crash)
+
+	// Without cash flow only the year's compounded return matters
+	endEarly, periodEnds := growBalanceAtRates(start, decimal.Zero, early)
+	require.Len(t, periodEnds, 12)
+	endLate, _ := growBalanceAtRates(start, decimal.Zero, late)
+	assert.InDelta(t, 70000.0, endEarly.InexactFloat64(), 0.01)
+	assert.True(t, endEarly.Equal(endLate))
+
+	// Withdrawals taken before a late crash leave less of it to lose
+	withdrawal := decimal.NewFromInt(-24000)
+	endEarly, _ = growBalanceAtRates(start, withdrawal, early)
+	endLate, _ = growBalanceAtRates(start, withdrawal, late)
+	assert.InDelta(t, (100000.0-2000)*0.7-22000, endEarly.InexactFloat64(), 0.01)
+	assert.InDelta(t, (100000.0-24000)*0.7, endLate.InexactFloat64(), 0.01)
+}
+
+func TestProjectionFollowsMonthlyMarketPath(t *testing.T) {
+	returns := func(r float64) domain.TSPFundReturns {
+		rate := decimal.NewFromFloat(r)
+		return domain.TSPFundReturns{CFund: rate, SFund: rate, IFund: rate, FFund: rate, GFund: rate}
+	}
+	crashYear := func(crashMonth int) domain.MarketYear {
+		months := slices.Repeat([]domain.TSPFundReturns{returns(0)}, 12)
+		months[crashMonth] = returns(-0.3)
+		return domain.MarketYear{
+			InflationRate:      decimal.NewFromFloat(0.025),
+			COLARate:           decimal.NewFromFloat(0.025),
+			FEHBInflation:      decimal.NewFromFloat(0.04),
+			FundReturns:        returns(-0.3),
+			MonthlyFundReturns: months,
+		}
+	}
+	run := func(granularity string, year domain.MarketYear) decimal.Decimal {
+		config := createTestConfig()
+		config.GlobalAssumptions.TSPReturnModel = TSPReturnModelFund
+		config.GlobalAssumptions.ProjectionGranularity = granularity
+		config.GlobalAssumptions.MarketPath = []domain.MarketYear{year}
+		summary, err := NewCalculationEngine().RunGenericScenario(context.Background(), config, &config.Scenarios[0])
+		require.NoError(t, err)
+		return summary.Projection[0].TotalTSPBalance()
+	}
+
+	// Stepped monthly, the month of the crash changes how it meets the year's cash flows
+	january, december := run(GranularityMonthly, crashYear(0)), run(GranularityMonthly, crashYear(11))
+	assert.False(t, january.Equal(december), "expected the crash month to matter, both ended at %s", january)
+
+	// Annual steps see only the year's return
+	assert.True(t, run(GranularityAnnual, crashYear(0)).Equal(run(GranularityAnnual, crashYear(11))))
+}
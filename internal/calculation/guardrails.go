@@ -0,0 +1,50 @@
+package calculation
+
+import (
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// guardrailsWithdrawal returns the participant's full-year TSP withdrawal under the
+// Guyton-Klinger decision rules. The first year withdraws the initial rate of the
+// balance. Each later year raises the prior withdrawal by inflation, except after a
+// losing year while the current rate is above the initial one. A current rate beyond the
+// upper guardrail then cuts the withdrawal, reported as a warning, and one beyond the
+// lower guardrail raises it.
+func (st *participantState) guardrailsWithdrawal(cf *domain.AnnualCashFlow, p *domain.Participant, ps domain.ParticipantScenario, infl decimal.Decimal, year int) decimal.Decimal {
+	initialRate := *ps.TSPWithdrawalRate
+	if st.guardrailWithdrawal.IsZero() {
+		st.guardrailWithdrawal = st.tspBalance.Mul(initialRate)
+		return st.guardrailWithdrawal
+	}
+	if !st.tspBalance.IsPositive() {
+		return st.guardrailWithdrawal
+	}
+
+	rules := ps.Guardrails.WithDefaults()
+	withdrawal := st.guardrailWithdrawal
+	if !st.lastTSPReturn.IsNegative() || withdrawal.Div(st.tspBalance).LessThanOrEqual(initialRate) {
+		withdrawal = withdrawal.Mul(onePlus(infl))
+	}
+
+	rate := withdrawal.Div(st.tspBalance)
+	switch {
+	case rate.GreaterThan(initialRate.Mul(onePlus(rules.UpperBand))):
+		cut := withdrawal.Mul(rules.CutPercent)
+		withdrawal = withdrawal.Sub(cut)
+		cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+			Year:        year,
+			Participant: p.Name,
+			Code:        domain.WarningGuardrailCut,
+			Amount:      cut,
+			Message: fmt.Sprintf("%s: withdrawal rate of %s passed the upper guardrail; TSP withdrawal cut %s to $%s",
+				p.Label(), domain.FormatRate(rate), domain.FormatRate(rules.CutPercent), withdrawal.StringFixed(0)),
+		})
+	case rate.LessThan(initialRate.Mul(decimalOne.Sub(rules.LowerBand))):
+		withdrawal = withdrawal.Mul(onePlus(rules.RaisePercent))
+	}
+	st.guardrailWithdrawal = withdrawal
+	return withdrawal
+}
@@ -0,0 +1,85 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardrailsWithdrawal(t *testing.T) {
+	p := &domain.Participant{Name: "Gail"}
+	rate := decimal.NewFromFloat(0.05)
+	ps := domain.ParticipantScenario{ParticipantName: "Gail", TSPWithdrawalStrategy: "guardrails", TSPWithdrawalRate: &rate}
+	infl := decimal.NewFromFloat(0.03)
+	index := domain.NewParticipantIndex([]string{"Gail"})
+	st := &participantState{}
+
+	year := func(balance, lastReturn float64) (decimal.Decimal, *domain.AnnualCashFlow) {
+		cf := domain.NewAnnualCashFlowWithIndex(0, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), index)
+		st.tspBalance = decimal.NewFromFloat(balance)
+		st.lastTSPReturn = decimal.NewFromFloat(lastReturn)
+		return st.guardrailsWithdrawal(cf, p, ps, infl, 2030), cf
+	}
+
+	withdrawal, _ := year(1000000, 0.05)
+	assert.Equal(t, "50000", withdrawal.String(), "the initial rate of the balance")
+
+	// After a loss the rate of 7.1% is above the initial rate: no inflation raise, and
+	// past the 6% upper guardrail the withdrawal is cut 10%
+	withdrawal, cf := year(700000, -0.10)
+	assert.Equal(t, "45000", withdrawal.String())
+	require.Len(t, cf.Warnings, 1)
+	assert.Equal(t, domain.WarningGuardrailCut, cf.Warnings[0].Code)
+	assert.Equal(t, "5000", cf.Warnings[0].Amount.String())
+
+	// Inflation brings it to $46,350, a rate of 3.1% below the 4% lower guardrail: raised 10%
+	withdrawal, cf = year(1500000, 0.20)
+	assert.Equal(t, "50985", withdrawal.String())
+	assert.Empty(t, cf.Warnings)
+
+	// Between the guardrails only inflation applies
+	withdrawal, _ = year(1000000, 0.05)
+	assert.Equal(t, "52514.55", withdrawal.String())
+
+	// Custom rules: a 50% upper band tolerates a rate of 7.1%
+	ps.Guardrails = &domain.GuardrailRules{UpperBand: decimal.NewFromFloat(0.5)}
+	withdrawal, cf = year(750000, 0.05)
+	assert.Equal(t, "54089.9865", withdrawal.String())
+	assert.Empty(t, cf.Warnings)
+}
+
+func TestGuardrailRulesDefaults(t *testing.T) {
+	var rules *domain.GuardrailRules
+	assert.Equal(t, "0.2", rules.WithDefaults().UpperBand.String())
+	assert.Equal(t, "0.1", rules.WithDefaults().RaisePercent.String())
+	custom := (&domain.GuardrailRules{CutPercent: decimal.NewFromFloat(0.05)}).WithDefaults()
+	assert.Equal(t, "0.05", custom.CutPercent.String())
+	assert.Equal(t, "0.2", custom.LowerBand.String())
+}
+
+func TestGuardrailsStrategyInProjection(t *testing.T) {
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(600000)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	rate := decimal.NewFromFloat(0.05)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+			ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70,
+			TSPWithdrawalStrategy: "guardrails", TSPWithdrawalRate: &rate,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3, InflationRate: decimal.NewFromFloat(0.03)}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 3)
+
+	assert.Equal(t, "30000", projection[0].TSPWithdrawals.Get("Gail").String())
+	// With no return the balance shrinks, but the rate stays inside the guardrails
+	assert.Equal(t, "30900", projection[1].TSPWithdrawals.Get("Gail").String())
+}
@@ -1,6 +1,8 @@
 package calculation
 
 import (
+	"time"
+
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 )
@@ -9,7 +11,6 @@ import (
 type HealthcareCostCalculator struct {
 	MedicareCalc   *MedicareCalculator
 	PartDCosts     domain.MedicarePartDCosts
-	PartDIRMAA     []domain.MedicarePartDIRMAA
 	MedigapCosts   domain.MedigapCosts
 	InflationRates domain.HealthcareInflationRates
 }
@@ -19,7 +20,6 @@ func NewHealthcareCostCalculator() *HealthcareCostCalculator {
 	return &HealthcareCostCalculator{
 		MedicareCalc:   NewMedicareCalculator(),
 		PartDCosts:     domain.DefaultMedicarePartDCosts(),
-		PartDIRMAA:     domain.DefaultMedicarePartDIRMAA(),
 		MedigapCosts:   domain.DefaultMedigapCosts(),
 		InflationRates: domain.DefaultHealthcareInflationRates(),
 	}
@@ -29,14 +29,12 @@ func NewHealthcareCostCalculator() *HealthcareCostCalculator {
 func NewHealthcareCostCalculatorWithConfig(
 	medicareConfig domain.MedicareConfig,
 	partDCosts domain.MedicarePartDCosts,
-	partDIRMAA []domain.MedicarePartDIRMAA,
 	medigapCosts domain.MedigapCosts,
 	inflationRates domain.HealthcareInflationRates,
 ) *HealthcareCostCalculator {
 	return &HealthcareCostCalculator{
 		MedicareCalc:   NewMedicareCalculatorWithConfig(medicareConfig),
 		PartDCosts:     partDCosts,
-		PartDIRMAA:     partDIRMAA,
 		MedigapCosts:   medigapCosts,
 		InflationRates: inflationRates,
 	}
@@ -60,12 +58,24 @@ func (hcc *HealthcareCostCalculator) CalculateHealthcareCosts(
 		healthcare = &defaultConfig
 	}
 
-	if age < 65 {
-		// Pre-Medicare coverage
-		hcc.calculatePreMedicareCosts(participant, healthcare, year, &breakdown)
-	} else {
-		// Medicare coverage (age 65+)
-		hcc.calculateMedicareCosts(participant, healthcare, age, year, magi, filingStatus, &breakdown)
+	// Coverage switches to Medicare in the eligibility month, so the year it begins is split
+	// between pre-Medicare and Medicare costs
+	medicareMonths := 0
+	if age >= 65 {
+		medicareMonths = 12
+	}
+	if !participant.BirthDate.IsZero() {
+		medicareMonths = MedicareMonthsInYear(participant.BirthDate, year)
+	}
+	if medicareMonths < 12 {
+		pre := domain.HealthcareCostBreakdown{}
+		hcc.calculatePreMedicareCosts(participant, healthcare, year, &pre)
+		prorateHealthcareCosts(&breakdown, pre, 12-medicareMonths)
+	}
+	if medicareMonths > 0 {
+		medicare := domain.HealthcareCostBreakdown{}
+		hcc.calculateMedicareCosts(participant, healthcare, max(age, 65), year, magi, filingStatus, &medicare)
+		prorateHealthcareCosts(&breakdown, medicare, medicareMonths)
 	}
 
 	// Calculate total
@@ -73,11 +83,29 @@ func (hcc *HealthcareCostCalculator) CalculateHealthcareCosts(
 		Add(breakdown.MarketplacePremium).
 		Add(breakdown.MedicarePartB).
 		Add(breakdown.MedicarePartD).
+		Add(breakdown.DrugCosts).
+		Add(breakdown.OutOfPocket).
 		Add(breakdown.Medigap)
 
 	return breakdown
 }
 
+// prorateHealthcareCosts adds months twelfths of the annual costs in annual to breakdown
+func prorateHealthcareCosts(breakdown *domain.HealthcareCostBreakdown, annual domain.HealthcareCostBreakdown, months int) {
+	share := decimal.NewFromInt(int64(months)).Div(decimal.NewFromInt(12))
+	if months == 12 {
+		share = decimal.NewFromInt(1)
+	}
+	breakdown.FEHBPremium = breakdown.FEHBPremium.Add(annual.FEHBPremium.Mul(share))
+	breakdown.MarketplacePremium = breakdown.MarketplacePremium.Add(annual.MarketplacePremium.Mul(share))
+	breakdown.MedicarePartB = breakdown.MedicarePartB.Add(annual.MedicarePartB.Mul(share))
+	breakdown.PartBLatePenalty = breakdown.PartBLatePenalty.Add(annual.PartBLatePenalty.Mul(share))
+	breakdown.MedicarePartD = breakdown.MedicarePartD.Add(annual.MedicarePartD.Mul(share))
+	breakdown.DrugCosts = breakdown.DrugCosts.Add(annual.DrugCosts.Mul(share))
+	breakdown.OutOfPocket = breakdown.OutOfPocket.Add(annual.OutOfPocket.Mul(share))
+	breakdown.Medigap = breakdown.Medigap.Add(annual.Medigap.Mul(share))
+}
+
 // calculatePreMedicareCosts calculates healthcare costs before Medicare eligibility
 func (hcc *HealthcareCostCalculator) calculatePreMedicareCosts(
 	participant *domain.Participant,
@@ -90,7 +118,7 @@ func (hcc *HealthcareCostCalculator) calculatePreMedicareCosts(
 		// Use FEHB premium from participant
 		if participant.FEHBPremiumPerPayPeriod != nil {
 			basePremium := participant.FEHBPremiumPerPayPeriod.Mul(decimal.NewFromInt(26)) // 26 pay periods
-			inflatedPremium := hcc.inflateFromBase(basePremium, year, hcc.InflationRates.FEHB)
+			inflatedPremium := hcc.inflateFromBase(basePremium, year, hcc.fehbGrowth(participant))
 			breakdown.FEHBPremium = inflatedPremium
 		}
 	case "marketplace":
@@ -109,7 +137,7 @@ func (hcc *HealthcareCostCalculator) calculatePreMedicareCosts(
 		// Retiree plan (if available)
 		if participant.FEHBPremiumPerPayPeriod != nil {
 			basePremium := participant.FEHBPremiumPerPayPeriod.Mul(decimal.NewFromInt(26))
-			inflatedPremium := hcc.inflateFromBase(basePremium, year, hcc.InflationRates.FEHB)
+			inflatedPremium := hcc.inflateFromBase(basePremium, year, hcc.fehbGrowth(participant))
 			breakdown.FEHBPremium = inflatedPremium
 		}
 	}
@@ -127,8 +155,22 @@ func (hcc *HealthcareCostCalculator) calculateMedicareCosts(
 ) {
 	isMarried := filingStatus == "married_filing_jointly"
 
+	// A chosen coverage decides the plans bought alongside Part B. FEHB kept as secondary
+	// coverage covers drugs, so no Part D or Medigap plan is bought; its premium is charged
+	// with the enrollment's other FEHB premiums.
+	partB, partD, medigapPlan, keepFEHB := healthcare.MedicarePartB, healthcare.MedicarePartD, healthcare.MedigapPlan, !healthcare.DropFEHBAt65
+	switch healthcare.MedicareCoverage {
+	case domain.MedicareCoverageFEHBSecondary:
+		partB, partD, medigapPlan, keepFEHB = true, false, "", false
+	case domain.MedicareCoverageMedigap:
+		partB, partD, keepFEHB = true, true, false
+		if medigapPlan == "" {
+			medigapPlan = domain.DefaultHealthcareConfig().MedigapPlan
+		}
+	}
+
 	// Medicare Part B
-	if healthcare.MedicarePartB {
+	if partB {
 		basePremium := decimal.NewFromFloat(174.70) // 2025 standard Part B premium
 		annualBasePremium := basePremium.Mul(decimal.NewFromInt(12))
 		inflatedPremium := hcc.inflateFromBase(annualBasePremium, year, hcc.InflationRates.MedicareB)
@@ -137,11 +179,23 @@ func (hcc *HealthcareCostCalculator) calculateMedicareCosts(
 		irmaaSurcharge := hcc.MedicareCalc.calculateIRMAASurcharge(magi, isMarried)
 		annualIRMAA := irmaaSurcharge.Mul(decimal.NewFromInt(12))
 
-		breakdown.MedicarePartB = inflatedPremium.Add(annualIRMAA)
+		// A late enrollment penalty raises the standard premium for life
+		penalty := inflatedPremium.Mul(healthcare.PartBLatePenalty)
+		breakdown.MedicarePartB = inflatedPremium.Add(penalty).Add(annualIRMAA)
+		breakdown.PartBLatePenalty = penalty
+
+		// Delayed enrollment: no Part B before it starts
+		if healthcare.PartBEnrollmentAge > 65 && !participant.BirthDate.IsZero() {
+			enrollment := PartBEnrollmentDate(participant.BirthDate, healthcare.PartBEnrollmentAge)
+			if share := partBShareOfYear(enrollment, year); share.LessThan(decimal.NewFromInt(1)) {
+				breakdown.MedicarePartB = breakdown.MedicarePartB.Mul(share)
+				breakdown.PartBLatePenalty = breakdown.PartBLatePenalty.Mul(share)
+			}
+		}
 	}
 
 	// Medicare Part D
-	if healthcare.MedicarePartD {
+	if partD {
 		var basePremium decimal.Decimal
 		switch healthcare.MedicarePartDPlan {
 		case "standard":
@@ -156,59 +210,60 @@ func (hcc *HealthcareCostCalculator) calculateMedicareCosts(
 		inflatedPremium := hcc.inflateFromBase(annualBasePremium, year, hcc.InflationRates.MedicareD)
 
 		// Add Part D IRMAA surcharge
-		partDIRMAA := hcc.calculatePartDIRMAA(magi, isMarried)
+		partDIRMAA := hcc.MedicareCalc.calculatePartDIRMAASurcharge(magi, isMarried)
 		annualPartDIRMAA := partDIRMAA.Mul(decimal.NewFromInt(12))
 
 		breakdown.MedicarePartD = inflatedPremium.Add(annualPartDIRMAA)
 	}
 
+	// Out-of-pocket drug costs, which Part D, or FEHB kept as secondary coverage, caps at
+	// Part D's out-of-pocket maximum
+	coverage := healthcare.Coverage("")
+	if healthcare.DrugCosts.IsPositive() {
+		drugCosts := hcc.inflateFromBase(healthcare.DrugCosts, year, hcc.InflationRates.MedicareD)
+		capped := partD || healthcare.MedicareCoverage == domain.MedicareCoverageFEHBSecondary
+		if capped && hcc.PartDCosts.OutOfPocketMax.IsPositive() {
+			drugCosts = decimal.Min(drugCosts, hcc.inflateFromBase(hcc.PartDCosts.OutOfPocketMax, year, hcc.InflationRates.MedicareD))
+		}
+		breakdown.DrugCosts = drugCosts
+	}
+
+	// Out-of-pocket medical costs the coverage leaves to the participant
+	outOfPocket := healthcare.OutOfPocketMedigap
+	if coverage == domain.MedicareCoverageFEHBSecondary {
+		outOfPocket = healthcare.OutOfPocketFEHB
+	}
+	if outOfPocket.IsPositive() {
+		breakdown.OutOfPocket = hcc.inflateFromBase(outOfPocket, year, hcc.InflationRates.MedicareB)
+	}
+
 	// Medigap
-	if healthcare.MedigapPlan != "" {
-		baseCost := hcc.getMedigapBaseCost(healthcare.MedigapPlan, age)
+	if medigapPlan != "" {
+		baseCost := hcc.getMedigapBaseCost(medigapPlan, age)
 		annualBaseCost := baseCost.Mul(decimal.NewFromInt(12))
 		inflatedCost := hcc.inflateFromBase(annualBaseCost, year, hcc.InflationRates.Medigap)
 		breakdown.Medigap = inflatedCost
 	}
 
 	// FEHB (if not dropped at 65)
-	if !healthcare.DropFEHBAt65 && participant.FEHBPremiumPerPayPeriod != nil {
+	if keepFEHB && participant.FEHBPremiumPerPayPeriod != nil {
 		basePremium := participant.FEHBPremiumPerPayPeriod.Mul(decimal.NewFromInt(26))
-		inflatedPremium := hcc.inflateFromBase(basePremium, year, hcc.InflationRates.FEHB)
+		inflatedPremium := hcc.inflateFromBase(basePremium, year, hcc.fehbGrowth(participant))
 		breakdown.FEHBPremium = inflatedPremium
 	}
 }
 
-// calculatePartDIRMAA calculates Medicare Part D IRMAA surcharge
-func (hcc *HealthcareCostCalculator) calculatePartDIRMAA(magi decimal.Decimal, isMarried bool) decimal.Decimal {
-	var totalSurcharge decimal.Decimal
-
-	for _, threshold := range hcc.PartDIRMAA {
-		var incomeThreshold decimal.Decimal
-		if isMarried {
-			incomeThreshold = threshold.IncomeThresholdJoint
-		} else {
-			incomeThreshold = threshold.IncomeThresholdSingle
-		}
-
-		if magi.GreaterThan(incomeThreshold) {
-			totalSurcharge = totalSurcharge.Add(threshold.MonthlySurcharge)
-		} else {
-			break
-		}
-	}
-
-	return totalSurcharge
-}
-
 // getMedigapBaseCost gets the base cost for a Medigap plan at a specific age
 func (hcc *HealthcareCostCalculator) getMedigapBaseCost(planType string, age int) decimal.Decimal {
 	// For now, use Plan G costs as default
 	baseCost := hcc.MedigapCosts.BaseCost
 
-	// Apply age-based multiplier
+	// Apply the multiplier for the highest age threshold reached (map order is random)
 	ageMultiplier := decimal.NewFromFloat(1.0)
+	bestThreshold := -1
 	for ageThreshold, multiplier := range hcc.MedigapCosts.AgeRates {
-		if age >= ageThreshold {
+		if age >= ageThreshold && ageThreshold > bestThreshold {
+			bestThreshold = ageThreshold
 			ageMultiplier = multiplier
 		}
 	}
@@ -232,7 +287,7 @@ func (hcc *HealthcareCostCalculator) inflateFromBase(baseAmount decimal.Decimal,
 // CalculateHouseholdHealthcareCosts calculates total healthcare costs for a household
 func (hcc *HealthcareCostCalculator) CalculateHouseholdHealthcareCosts(
 	participants []domain.Participant,
-	ages map[string]int,
+	ages domain.ParticipantValues[int],
 	year int,
 	magi decimal.Decimal,
 	filingStatus string,
@@ -241,13 +296,16 @@ func (hcc *HealthcareCostCalculator) CalculateHouseholdHealthcareCosts(
 	householdBreakdown := domain.HealthcareCostBreakdown{}
 
 	for _, participant := range participants {
-		age := ages[participant.Name]
+		age := ages.Get(participant.Name)
 		participantBreakdown := hcc.CalculateHealthcareCosts(&participant, age, year, magi, filingStatus)
 
 		householdBreakdown.FEHBPremium = householdBreakdown.FEHBPremium.Add(participantBreakdown.FEHBPremium)
 		householdBreakdown.MarketplacePremium = householdBreakdown.MarketplacePremium.Add(participantBreakdown.MarketplacePremium)
 		householdBreakdown.MedicarePartB = householdBreakdown.MedicarePartB.Add(participantBreakdown.MedicarePartB)
+		householdBreakdown.PartBLatePenalty = householdBreakdown.PartBLatePenalty.Add(participantBreakdown.PartBLatePenalty)
 		householdBreakdown.MedicarePartD = householdBreakdown.MedicarePartD.Add(participantBreakdown.MedicarePartD)
+		householdBreakdown.DrugCosts = householdBreakdown.DrugCosts.Add(participantBreakdown.DrugCosts)
+		householdBreakdown.OutOfPocket = householdBreakdown.OutOfPocket.Add(participantBreakdown.OutOfPocket)
 		householdBreakdown.Medigap = householdBreakdown.Medigap.Add(participantBreakdown.Medigap)
 	}
 
@@ -255,7 +313,90 @@ func (hcc *HealthcareCostCalculator) CalculateHouseholdHealthcareCosts(
 		Add(householdBreakdown.MarketplacePremium).
 		Add(householdBreakdown.MedicarePartB).
 		Add(householdBreakdown.MedicarePartD).
+		Add(householdBreakdown.DrugCosts).
+		Add(householdBreakdown.OutOfPocket).
 		Add(householdBreakdown.Medigap)
 
 	return householdBreakdown
 }
+
+// FEHBSelfOnlyPremiumFactor approximates a self only FEHB premium as a share of the self plus
+// one or family premium for the same plan, used when no self only premium is configured
+var FEHBSelfOnlyPremiumFactor = decimal.NewFromFloat(0.45)
+
+// fehbGrowth returns the yearly growth of a participant's FEHB premium: their plan's, when
+// known, or the FEHB inflation rate
+func (hcc *HealthcareCostCalculator) fehbGrowth(participant *domain.Participant) decimal.Decimal {
+	if participant.FEHBPremiumGrowth != nil {
+		return *participant.FEHBPremiumGrowth
+	}
+	return hcc.InflationRates.FEHB
+}
+
+// fehbSelfOnlyFactor returns the ratio of an enrollee's self only premium to the premium
+// they pay now
+func fehbSelfOnlyFactor(p *domain.Participant) decimal.Decimal {
+	if p.FEHBSelfOnlyPremiumPerPayPeriod != nil && p.FEHBPremiumPerPayPeriod != nil && p.FEHBPremiumPerPayPeriod.IsPositive() {
+		return p.FEHBSelfOnlyPremiumPerPayPeriod.Div(*p.FEHBPremiumPerPayPeriod)
+	}
+	return FEHBSelfOnlyPremiumFactor
+}
+
+// medicareCoverage returns the participant's coverage alongside Medicare when the scenario or
+// the healthcare settings choose one, or "" when the other settings decide
+func medicareCoverage(p *domain.Participant, ps domain.ParticipantScenario) string {
+	if ps.MedicareCoverage != "" {
+		return ps.MedicareCoverage
+	}
+	if p.Healthcare != nil {
+		return p.Healthcare.MedicareCoverage
+	}
+	return ""
+}
+
+// partBShareOfYear returns the share of year covered by Part B that starts on enrollment
+func partBShareOfYear(enrollment time.Time, year int) decimal.Decimal {
+	switch {
+	case year < enrollment.Year():
+		return decimal.Zero
+	case year > enrollment.Year():
+		return decimal.NewFromInt(1)
+	}
+	return decimal.NewFromInt(int64(13 - int(enrollment.Month()))).Div(decimal.NewFromInt(12))
+}
+
+// partBEmploymentCoverageEnd returns when the participant's coverage through current
+// employment ends under the scenario: the latest retirement or separation of a working
+// federal employee whose FEHB enrollment covers them, or zero when none does. Only such
+// coverage excuses delaying Part B; FEHB kept in retirement does not.
+func partBEmploymentCoverageEnd(household *domain.Household, psMap map[string]domain.ParticipantScenario, p *domain.Participant) time.Time {
+	var end time.Time
+	for i := range household.Participants {
+		q := &household.Participants[i]
+		if !q.IsFederal || q.CurrentRetirement != nil || q.FEHBPremiumPerPayPeriod == nil ||
+			(q.Name != p.Name && !q.IsPrimaryFEHBHolder) {
+			continue
+		}
+		ps := psMap[q.Name]
+		leaves := ps.RetirementDate
+		if ps.SeparationDate != nil {
+			leaves = ps.SeparationDate
+		}
+		if leaves != nil && leaves.After(end) {
+			end = *leaves
+		}
+	}
+	return end
+}
+
+// partBEnrollmentAge returns the age the participant enrolls in Part B: the scenario's, if
+// set, or the healthcare settings'
+func partBEnrollmentAge(p *domain.Participant, ps domain.ParticipantScenario) int {
+	if ps.PartBEnrollmentAge > 0 {
+		return ps.PartBEnrollmentAge
+	}
+	if p.Healthcare != nil {
+		return p.Healthcare.PartBEnrollmentAge
+	}
+	return 0
+}
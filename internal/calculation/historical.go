@@ -2,12 +2,15 @@ package calculation
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
@@ -56,6 +59,11 @@ type HistoricalDataManager struct {
 	COLA      *HistoricalDataSet `json:"cola"`
 	DataPath  string             `json:"dataPath"`
 	IsLoaded  bool               `json:"isLoaded"`
+
+	// Optional monthly series: fund returns keyed by fund letter, and inflation. Their
+	// complete years fill in any the annual files lack.
+	MonthlyTSPReturns map[string]*MonthlyDataSet `json:"monthlyTSPReturns,omitempty"`
+	MonthlyInflation  *MonthlyDataSet            `json:"monthlyInflation,omitempty"`
 }
 
 // NewHistoricalDataManager creates a new historical data manager
@@ -73,6 +81,11 @@ func (hdm *HistoricalDataManager) LoadAllData() error {
 		return nil // Already loaded
 	}
 
+	// Load monthly data first so its complete years can fill in the annual series
+	if err := hdm.loadMonthlyData(); err != nil {
+		return fmt.Errorf("failed to load monthly data: %w", err)
+	}
+
 	// Load TSP fund data
 	if err := hdm.loadTSPFundData(); err != nil {
 		return fmt.Errorf("failed to load TSP fund data: %w", err)
@@ -92,6 +105,26 @@ func (hdm *HistoricalDataManager) LoadAllData() error {
 	return nil
 }
 
+// HistoricalDataFiles lists the files, relative to the data path, that LoadAllData reads.
+// The monthly files are optional.
+func HistoricalDataFiles() []string {
+	return []string{
+		filepath.Join("tsp-returns", "c-fund-annual.csv"),
+		filepath.Join("tsp-returns", "s-fund-annual.csv"),
+		filepath.Join("tsp-returns", "i-fund-annual.csv"),
+		filepath.Join("tsp-returns", "f-fund-annual.csv"),
+		filepath.Join("tsp-returns", "g-fund-annual.csv"),
+		filepath.Join("inflation", "cpi-annual.csv"),
+		filepath.Join("cola", "ss-cola-annual.csv"),
+		filepath.Join("tsp-returns", "c-fund-monthly.csv"),
+		filepath.Join("tsp-returns", "s-fund-monthly.csv"),
+		filepath.Join("tsp-returns", "i-fund-monthly.csv"),
+		filepath.Join("tsp-returns", "f-fund-monthly.csv"),
+		filepath.Join("tsp-returns", "g-fund-monthly.csv"),
+		filepath.Join("inflation", "cpi-monthly.csv"),
+	}
+}
+
 // loadTSPFundData loads all TSP fund historical returns
 func (hdm *HistoricalDataManager) loadTSPFundData() error {
 	funds := map[string]string{
@@ -104,8 +137,13 @@ func (hdm *HistoricalDataManager) loadTSPFundData() error {
 
 	for fundName, fileName := range funds {
 		filePath := filepath.Join(hdm.DataPath, "tsp-returns", fileName)
-		dataset, err := hdm.loadCSVData(filePath, fundName, "TSP "+fundName+" Fund Annual Returns", "TSP.gov")
-		if err != nil {
+		description := "TSP " + fundName + " Fund Annual Returns"
+		dataset, err := hdm.loadCSVData(filePath, fundName, description, "TSP.gov")
+		monthly := hdm.MonthlyTSPReturns[strings.ToUpper(fundName[:1])]
+		if err != nil && (monthly == nil || !errors.Is(err, os.ErrNotExist)) {
+			return fmt.Errorf("failed to load %s: %w", fundName, err)
+		}
+		if dataset, err = hdm.withAnnualized(dataset, monthly, fundName, description, "TSP.gov"); err != nil {
 			return fmt.Errorf("failed to load %s: %w", fundName, err)
 		}
 
@@ -130,7 +168,10 @@ func (hdm *HistoricalDataManager) loadTSPFundData() error {
 func (hdm *HistoricalDataManager) loadInflationData() error {
 	filePath := filepath.Join(hdm.DataPath, "inflation", "cpi-annual.csv")
 	dataset, err := hdm.loadCSVData(filePath, "inflation", "CPI-U Annual Inflation Rates", "BLS.gov")
-	if err != nil {
+	if err != nil && (hdm.MonthlyInflation == nil || !errors.Is(err, os.ErrNotExist)) {
+		return fmt.Errorf("failed to load inflation data: %w", err)
+	}
+	if dataset, err = hdm.withAnnualized(dataset, hdm.MonthlyInflation, "inflation", "CPI-U Annual Inflation Rates", "BLS.gov"); err != nil {
 		return fmt.Errorf("failed to load inflation data: %w", err)
 	}
 	hdm.Inflation = dataset
@@ -169,7 +210,6 @@ func (hdm *HistoricalDataManager) loadCSVData(filePath, name, description, sourc
 	}
 
 	var dataPoints []HistoricalDataPoint
-	var values []decimal.Decimal
 
 	// Read data rows
 	for {
@@ -199,13 +239,23 @@ func (hdm *HistoricalDataManager) loadCSVData(filePath, name, description, sourc
 			Year: year,
 			Data: value,
 		})
-		values = append(values, value)
 	}
 
 	if len(dataPoints) == 0 {
 		return nil, fmt.Errorf("no valid data points found in %s", filePath)
 	}
 
+	return hdm.newHistoricalDataSet(dataPoints, name, description, source), nil
+}
+
+// newHistoricalDataSet creates a HistoricalDataSet of the data points with their
+// statistics and year range
+func (hdm *HistoricalDataManager) newHistoricalDataSet(dataPoints []HistoricalDataPoint, name, description, source string) *HistoricalDataSet {
+	values := make([]decimal.Decimal, len(dataPoints))
+	for i, dp := range dataPoints {
+		values[i] = dp.Data
+	}
+
 	// Calculate statistics
 	stats := hdm.calculateStatistics(values, dataPoints)
 
@@ -229,7 +279,7 @@ func (hdm *HistoricalDataManager) loadCSVData(filePath, name, description, sourc
 		MinYear:     minYear,
 		MaxYear:     maxYear,
 		Statistics:  stats,
-	}, nil
+	}
 }
 
 // calculateStatistics calculates statistical measures for the dataset
@@ -339,6 +389,25 @@ func (hdm *HistoricalDataManager) GetTSPReturn(fundName string, year int) (decim
 	return decimal.Zero, fmt.Errorf("no data found for fund %s in year %d", fundName, year)
 }
 
+// GetLifecycleReturn returns an L fund's return for a historical year: the year's
+// individual fund returns weighted by the fund's glide path allocation in allocationYear.
+// Passing the historical year for both reproduces a target-date fund's own history.
+func (hdm *HistoricalDataManager) GetLifecycleReturn(fundName string, historicalYear, allocationYear int) (decimal.Decimal, error) {
+	allocation, err := LifecycleAllocation(fundName, allocationYear)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	returns := make(map[string]decimal.Decimal, 5)
+	for fund := range allocationWeights(allocation) {
+		r, err := hdm.GetTSPReturn(fund, historicalYear)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		returns[fund] = r
+	}
+	return weightedReturn(allocation, returns), nil
+}
+
 // GetInflationRate returns the historical inflation rate for a specific year
 func (hdm *HistoricalDataManager) GetInflationRate(year int) (decimal.Decimal, error) {
 	if !hdm.IsLoaded || hdm.Inflation == nil {
@@ -386,6 +455,48 @@ func (hdm *HistoricalDataManager) GetRandomHistoricalYear() (int, error) {
 	return hdm.TSPFunds.CFund.DataPoints[middleIndex].Year, nil
 }
 
+// SampleHistoricalYear draws a year from the available historical data using rng,
+// so a simulation seeded the same way always draws the same sequence of years
+func (hdm *HistoricalDataManager) SampleHistoricalYear(rng *rand.Rand) (int, error) {
+	if !hdm.IsLoaded || hdm.TSPFunds.CFund == nil {
+		return 0, fmt.Errorf("historical data not loaded")
+	}
+
+	dataPoints := hdm.TSPFunds.CFund.DataPoints
+	if len(dataPoints) == 0 {
+		return 0, fmt.Errorf("no historical data available")
+	}
+
+	return dataPoints[rng.Intn(len(dataPoints))].Year, nil
+}
+
+// SampleHistoricalPath draws a sequence of years historical years by circular block
+// bootstrap: each block starts at a random year and runs blockLength consecutive years,
+// wrapping from the last year of data to the first, so multi-year runs such as 1973-74
+// or 2000-02 stay intact. A block length of 1 or less draws every year independently.
+func (hdm *HistoricalDataManager) SampleHistoricalPath(rng *rand.Rand, years, blockLength int) ([]int, error) {
+	if !hdm.IsLoaded || hdm.TSPFunds.CFund == nil {
+		return nil, fmt.Errorf("historical data not loaded")
+	}
+
+	dataPoints := hdm.TSPFunds.CFund.DataPoints
+	if len(dataPoints) == 0 {
+		return nil, fmt.Errorf("no historical data available")
+	}
+	if blockLength < 1 {
+		blockLength = 1
+	}
+
+	path := make([]int, 0, years)
+	for len(path) < years {
+		start := rng.Intn(len(dataPoints))
+		for i := 0; i < blockLength && len(path) < years; i++ {
+			path = append(path, dataPoints[(start+i)%len(dataPoints)].Year)
+		}
+	}
+	return path, nil
+}
+
 // GetAvailableYears returns the range of available years for historical data
 func (hdm *HistoricalDataManager) GetAvailableYears() (int, int, error) {
 	if !hdm.IsLoaded || hdm.TSPFunds.CFund == nil {
@@ -435,6 +546,17 @@ func (hdm *HistoricalDataManager) ValidateDataQuality() ([]string, error) {
 				issues = append(issues, fmt.Sprintf("%s has %d data points, expected %d", fundName, len(dataset.DataPoints), expectedYears))
 			}
 		}
+
+		// Years without inflation or COLA can't be replayed or sampled as a whole
+		for _, series := range []struct {
+			name    string
+			dataset *HistoricalDataSet
+		}{{"Inflation", hdm.Inflation}, {"COLA", hdm.COLA}} {
+			if series.dataset != nil && (series.dataset.MinYear > minYear || series.dataset.MaxYear < maxYear) {
+				issues = append(issues, fmt.Sprintf("%s data covers %d-%d, fund returns cover %d-%d",
+					series.name, series.dataset.MinYear, series.dataset.MaxYear, minYear, maxYear))
+			}
+		}
 	}
 
 	return issues, nil
@@ -0,0 +1,282 @@
+package calculation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// MonthlyDataPoint represents a single month's historical data
+type MonthlyDataPoint struct {
+	Year  int             `json:"year"`
+	Month int             `json:"month"`
+	Data  decimal.Decimal `json:"data"`
+}
+
+// MonthlyDataSet represents a series of monthly rates, such as a fund's monthly returns
+// or month-over-month inflation
+type MonthlyDataSet struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Source      string             `json:"source"`
+	DataPoints  []MonthlyDataPoint `json:"dataPoints"`
+	MinYear     int                `json:"minYear"`
+	MaxYear     int                `json:"maxYear"`
+
+	// months indexes the rates by year, January first; a month without data is nil
+	months map[int]*[12]*decimal.Decimal
+}
+
+// monthlyFunds maps each TSP fund to its monthly return file
+var monthlyFunds = map[string]string{
+	"C": "c-fund-monthly.csv",
+	"S": "s-fund-monthly.csv",
+	"I": "i-fund-monthly.csv",
+	"F": "f-fund-monthly.csv",
+	"G": "g-fund-monthly.csv",
+}
+
+// Rate returns the rate of one month, 1 for January
+func (ds *MonthlyDataSet) Rate(year, month int) (decimal.Decimal, bool) {
+	if ds == nil || month < 1 || month > 12 {
+		return decimal.Zero, false
+	}
+	months, ok := ds.months[year]
+	if !ok || months[month-1] == nil {
+		return decimal.Zero, false
+	}
+	return *months[month-1], true
+}
+
+// Year returns the twelve monthly rates of a year, or false unless every month has data
+func (ds *MonthlyDataSet) Year(year int) ([]decimal.Decimal, bool) {
+	if ds == nil {
+		return nil, false
+	}
+	months, ok := ds.months[year]
+	if !ok {
+		return nil, false
+	}
+	rates := make([]decimal.Decimal, 12)
+	for i, rate := range months {
+		if rate == nil {
+			return nil, false
+		}
+		rates[i] = *rate
+	}
+	return rates, true
+}
+
+// Annualized compounds the months of every complete year into that year's rate. For
+// inflation this is the December-over-December change.
+func (ds *MonthlyDataSet) Annualized() map[int]decimal.Decimal {
+	annual := map[int]decimal.Decimal{}
+	for year := range ds.months {
+		if rates, ok := ds.Year(year); ok {
+			annual[year] = compoundRates(rates)
+		}
+	}
+	return annual
+}
+
+// compoundRates returns the rate of successive periods compounded together
+func compoundRates(rates []decimal.Decimal) decimal.Decimal {
+	growth := decimal.NewFromInt(1)
+	for _, rate := range rates {
+		growth = growth.Mul(onePlus(rate))
+	}
+	return growth.Sub(decimal.NewFromInt(1))
+}
+
+// loadMonthlyData loads the optional monthly fund return and inflation files. A series
+// without a file is skipped.
+func (hdm *HistoricalDataManager) loadMonthlyData() error {
+	hdm.MonthlyTSPReturns = map[string]*MonthlyDataSet{}
+	for fund, fileName := range monthlyFunds {
+		filePath := filepath.Join(hdm.DataPath, "tsp-returns", fileName)
+		dataset, err := loadMonthlyCSVData(filePath, fund, "TSP "+fund+" Fund Monthly Returns", "TSP.gov")
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", fileName, err)
+		}
+		hdm.MonthlyTSPReturns[fund] = dataset
+	}
+
+	filePath := filepath.Join(hdm.DataPath, "inflation", "cpi-monthly.csv")
+	dataset, err := loadMonthlyCSVData(filePath, "inflation", "CPI-U Monthly Inflation Rates", "BLS.gov")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to load monthly inflation data: %w", err)
+	}
+	hdm.MonthlyInflation = dataset
+	return nil
+}
+
+// loadMonthlyCSVData loads a Year,Month,Value file into a MonthlyDataSet
+func loadMonthlyCSVData(filePath, name, description, source string) (*MonthlyDataSet, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if len(header) < 3 {
+		return nil, fmt.Errorf("invalid CSV format: expected Year, Month and value columns")
+	}
+
+	ds := &MonthlyDataSet{Name: name, Description: description, Source: source, months: map[int]*[12]*decimal.Decimal{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data row: %w", err)
+		}
+		if len(record) < 3 {
+			continue // Skip malformed rows
+		}
+		year, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			continue // Skip rows with invalid year
+		}
+		month, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil || month < 1 || month > 12 {
+			continue // Skip rows with invalid month
+		}
+		value, err := decimal.NewFromString(strings.TrimSpace(record[2]))
+		if err != nil {
+			continue // Skip rows with invalid value
+		}
+		ds.DataPoints = append(ds.DataPoints, MonthlyDataPoint{Year: year, Month: month, Data: value})
+	}
+	if len(ds.DataPoints) == 0 {
+		return nil, fmt.Errorf("no valid data points found in %s", filePath)
+	}
+
+	slices.SortStableFunc(ds.DataPoints, func(a, b MonthlyDataPoint) int {
+		if a.Year != b.Year {
+			return a.Year - b.Year
+		}
+		return a.Month - b.Month
+	})
+	ds.MinYear, ds.MaxYear = ds.DataPoints[0].Year, ds.DataPoints[len(ds.DataPoints)-1].Year
+	for i := range ds.DataPoints {
+		dp := &ds.DataPoints[i]
+		if ds.months[dp.Year] == nil {
+			ds.months[dp.Year] = &[12]*decimal.Decimal{}
+		}
+		ds.months[dp.Year][dp.Month-1] = &dp.Data
+	}
+	return ds, nil
+}
+
+// withAnnualized returns the annual dataset extended with the complete years of the
+// monthly series that it lacks; years the annual file has keep its rate. With no
+// annual dataset the annual series comes from the monthly one alone.
+func (hdm *HistoricalDataManager) withAnnualized(dataset *HistoricalDataSet, monthly *MonthlyDataSet, name, description, source string) (*HistoricalDataSet, error) {
+	if monthly == nil {
+		return dataset, nil
+	}
+	var dataPoints []HistoricalDataPoint
+	if dataset != nil {
+		dataPoints = slices.Clone(dataset.DataPoints)
+		name, description, source = dataset.Name, dataset.Description, dataset.Source
+	}
+	annual := monthly.Annualized()
+	for _, year := range slices.Sorted(maps.Keys(annual)) {
+		if !slices.ContainsFunc(dataPoints, func(dp HistoricalDataPoint) bool { return dp.Year == year }) {
+			dataPoints = append(dataPoints, HistoricalDataPoint{Year: year, Data: annual[year]})
+		}
+	}
+	if dataset != nil && len(dataPoints) == len(dataset.DataPoints) {
+		return dataset, nil
+	}
+	if len(dataPoints) == 0 {
+		return nil, fmt.Errorf("no complete year of monthly %s data", name)
+	}
+	slices.SortStableFunc(dataPoints, func(a, b HistoricalDataPoint) int { return a.Year - b.Year })
+	return hdm.newHistoricalDataSet(dataPoints, name, description, source), nil
+}
+
+// HasMonthlyData reports whether any fund has monthly returns loaded
+func (hdm *HistoricalDataManager) HasMonthlyData() bool {
+	return len(hdm.MonthlyTSPReturns) > 0
+}
+
+// GetMonthlyTSPReturn returns a fund's historical return for one month, 1 for January
+func (hdm *HistoricalDataManager) GetMonthlyTSPReturn(fundName string, year, month int) (decimal.Decimal, error) {
+	if !hdm.IsLoaded {
+		return decimal.Zero, fmt.Errorf("historical data not loaded")
+	}
+	fund := strings.ToUpper(strings.TrimSuffix(strings.ToLower(fundName), "_fund"))
+	dataset, ok := hdm.MonthlyTSPReturns[fund]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no monthly data for fund %s", fundName)
+	}
+	rate, ok := dataset.Rate(year, month)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no monthly data for %s fund in %d-%02d", fundName, year, month)
+	}
+	return rate, nil
+}
+
+// GetMonthlyInflationRate returns the historical inflation of one month, 1 for January
+func (hdm *HistoricalDataManager) GetMonthlyInflationRate(year, month int) (decimal.Decimal, error) {
+	if !hdm.IsLoaded {
+		return decimal.Zero, fmt.Errorf("historical data not loaded")
+	}
+	rate, ok := hdm.MonthlyInflation.Rate(year, month)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no monthly inflation data for %d-%02d", year, month)
+	}
+	return rate, nil
+}
+
+// MonthlyFundReturns returns the twelve monthly returns of every fund in a historical
+// year, or false unless each fund has all twelve months
+func (hdm *HistoricalDataManager) MonthlyFundReturns(year int) ([]domain.TSPFundReturns, bool) {
+	if hdm == nil || !hdm.HasMonthlyData() {
+		return nil, false
+	}
+	funds := map[string][]decimal.Decimal{}
+	for fund := range monthlyFunds {
+		rates, ok := hdm.MonthlyTSPReturns[fund].Year(year)
+		if !ok {
+			return nil, false
+		}
+		funds[fund] = rates
+	}
+	months := make([]domain.TSPFundReturns, 12)
+	for m := range months {
+		months[m] = domain.TSPFundReturns{
+			CFund: funds["C"][m],
+			SFund: funds["S"][m],
+			IFund: funds["I"][m],
+			FFund: funds["F"][m],
+			GFund: funds["G"][m],
+		}
+	}
+	return months, true
+}
+
+// fundReturnMap returns fund returns keyed by fund letter
+func fundReturnMap(r domain.TSPFundReturns) map[string]decimal.Decimal {
+	return map[string]decimal.Decimal{"C": r.CFund, "S": r.SFund, "I": r.IFund, "F": r.FFund, "G": r.GFund}
+}
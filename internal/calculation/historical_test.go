@@ -1,8 +1,12 @@
 package calculation
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/shopspring/decimal"
@@ -220,6 +224,20 @@ func TestValidateDataQuality(t *testing.T) {
 	if len(issues) > 0 {
 		t.Errorf("Expected no quality issues, got: %v", issues)
 	}
+
+	// Inflation that stops before the fund returns is reported
+	cpiFile := filepath.Join(testDataPath, "inflation", "cpi-annual.csv")
+	if err := os.WriteFile(cpiFile, []byte("Year,InflationRate\n2020,0.012\n2021,0.047\n2022,0.065"), 0644); err != nil {
+		t.Fatalf("Failed to write inflation data: %v", err)
+	}
+	hdm = NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load data: %v", err)
+	}
+	issues, _ = hdm.ValidateDataQuality()
+	if len(issues) != 1 || issues[0] != "Inflation data covers 2020-2022, fund returns cover 2020-2023" {
+		t.Errorf("Expected an inflation coverage issue, got: %v", issues)
+	}
 }
 
 func TestStatisticsCalculation(t *testing.T) {
@@ -286,3 +304,119 @@ func createTestDataFiles(dataPath string) error {
 
 	return nil
 }
+
+func TestSampleHistoricalPath(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load all data: %v", err)
+	}
+
+	path, err := hdm.SampleHistoricalPath(rand.New(rand.NewSource(3)), 9, 4)
+	if err != nil {
+		t.Fatalf("Failed to sample path: %v", err)
+	}
+	if len(path) != 9 {
+		t.Fatalf("Expected 9 years, got %d", len(path))
+	}
+	// With four years of data, a four-year block runs through every year once, wrapping
+	for i := range path {
+		if i%4 == 0 {
+			continue
+		}
+		want := path[i-1] + 1
+		if want > 2023 {
+			want = 2020
+		}
+		if path[i] != want {
+			t.Errorf("Year %d: expected %d to follow %d, got %d", i, want, path[i-1], path[i])
+		}
+	}
+
+	again, _ := hdm.SampleHistoricalPath(rand.New(rand.NewSource(3)), 9, 4)
+	for i := range path {
+		if path[i] != again[i] {
+			t.Fatalf("Expected the same seed to draw the same path, got %v and %v", path, again)
+		}
+	}
+
+	if _, err := NewHistoricalDataManager(testDataPath).SampleHistoricalPath(rand.New(rand.NewSource(3)), 5, 2); err == nil {
+		t.Error("Expected an error sampling before data is loaded")
+	}
+}
+
+func TestMonthlyHistoricalData(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+
+	// 2023 repeats the annual file's year; 2024 is new; 2025 has only two months
+	var b strings.Builder
+	b.WriteString("Year,Month,Return\n")
+	for _, year := range []int{2023, 2024} {
+		for month := 1; month <= 12; month++ {
+			fmt.Fprintf(&b, "%d,%d,0.01\n", year, month)
+		}
+	}
+	b.WriteString("2025,1,0.02\n2025,2,-0.01\n")
+	for _, fund := range []string{"c", "s", "i", "f", "g"} {
+		if err := os.WriteFile(filepath.Join(testDataPath, "tsp-returns", fund+"-fund-monthly.csv"), []byte(b.String()), 0644); err != nil {
+			t.Fatalf("Failed to write monthly data: %v", err)
+		}
+	}
+	// Monthly inflation alone stands in for a missing annual file
+	if err := os.Remove(filepath.Join(testDataPath, "inflation", "cpi-annual.csv")); err != nil {
+		t.Fatalf("Failed to remove annual inflation: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(testDataPath, "inflation", "cpi-monthly.csv"), []byte(strings.ReplaceAll(b.String(), "0.01", "0.002")), 0644); err != nil {
+		t.Fatalf("Failed to write monthly inflation: %v", err)
+	}
+
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load data: %v", err)
+	}
+	if !hdm.HasMonthlyData() {
+		t.Fatal("Expected monthly data to be loaded")
+	}
+
+	// A year in the annual file keeps its rate; a complete monthly year is compounded
+	if r, _ := hdm.GetTSPReturn("C", 2023); !r.Equal(decimal.RequireFromString("0.264")) {
+		t.Errorf("Expected the annual 2023 return, got %s", r)
+	}
+	annual := math.Pow(1.01, 12) - 1
+	if r, err := hdm.GetTSPReturn("C", 2024); err != nil || math.Abs(r.InexactFloat64()-annual) > 1e-9 {
+		t.Errorf("Expected 2024 compounded from its months to %.6f, got %s (%v)", annual, r, err)
+	}
+	if _, err := hdm.GetTSPReturn("C", 2025); err == nil {
+		t.Error("Expected no annual return for a partial year")
+	}
+	if hdm.TSPFunds.CFund.MaxYear != 2024 || hdm.TSPFunds.CFund.Statistics.Count != 5 {
+		t.Errorf("Expected 2020-2024 in the C fund, got through %d with %d years", hdm.TSPFunds.CFund.MaxYear, hdm.TSPFunds.CFund.Statistics.Count)
+	}
+	if r, err := hdm.GetInflationRate(2024); err != nil || math.Abs(r.InexactFloat64()-(math.Pow(1.002, 12)-1)) > 1e-9 {
+		t.Errorf("Expected inflation annualized from its months, got %s (%v)", r, err)
+	}
+
+	if r, err := hdm.GetMonthlyTSPReturn("s_fund", 2025, 2); err != nil || !r.Equal(decimal.RequireFromString("-0.01")) {
+		t.Errorf("Expected the February 2025 S fund return, got %s (%v)", r, err)
+	}
+	if _, err := hdm.GetMonthlyTSPReturn("C", 2025, 3); err == nil {
+		t.Error("Expected an error for a month without data")
+	}
+	if r, err := hdm.GetMonthlyInflationRate(2024, 6); err != nil || !r.Equal(decimal.RequireFromString("0.002")) {
+		t.Errorf("Expected June 2024 inflation, got %s (%v)", r, err)
+	}
+
+	months, ok := hdm.MonthlyFundReturns(2024)
+	if !ok || len(months) != 12 || !months[11].GFund.Equal(decimal.RequireFromString("0.01")) {
+		t.Errorf("Expected twelve months of 2024 returns, got %v", months)
+	}
+	if _, ok := hdm.MonthlyFundReturns(2025); ok {
+		t.Error("Expected no monthly returns for a partial year")
+	}
+}
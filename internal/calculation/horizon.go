@@ -0,0 +1,140 @@
+package calculation
+
+import (
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/pkg/dateutil"
+)
+
+// horizonEvent is a dated event that shapes a scenario's outcome
+type horizonEvent struct {
+	participant string
+	label       string
+	year        int
+}
+
+// ProjectionHorizonWarnings reports the scenario's key events that fall after the
+// projection ends: retirements, deferred annuities, Social Security claims, the start of
+// RMDs, TSP and income annuity purchases and deaths by age. Their effects are otherwise silently
+// missing from the results. Each warning names the projection_years that would include
+// the event.
+func ProjectionHorizonWarnings(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions) []domain.EngineWarning {
+	years := ScenarioProjectionYears(scenario, assumptions)
+	if household == nil || scenario == nil || years <= 0 {
+		return nil
+	}
+	lastYear := ProjectionBaseYear + years - 1
+
+	var warnings []domain.EngineWarning
+	for _, event := range scenarioHorizonEvents(household, scenario) {
+		if event.year <= lastYear {
+			continue
+		}
+		warnings = append(warnings, domain.EngineWarning{
+			Year:        event.year,
+			Participant: event.participant,
+			Code:        domain.WarningHorizonTruncated,
+			Message: fmt.Sprintf("%s: %s in %d falls after the projection ends in %d; projection_years: %d would include it",
+				event.participant, event.label, event.year, lastYear, event.year-ProjectionBaseYear+1),
+		})
+	}
+	return warnings
+}
+
+// ScenarioProjectionYears returns how many years a scenario is projected: its own
+// projection_years when set, otherwise the global one
+func ScenarioProjectionYears(scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions) int {
+	if scenario != nil && scenario.ProjectionYears > 0 {
+		return scenario.ProjectionYears
+	}
+	if assumptions != nil {
+		return assumptions.ProjectionYears
+	}
+	return 0
+}
+
+// MinimumProjectionYears returns the projection_years needed to include every key event
+// of the scenario
+func MinimumProjectionYears(household *domain.Household, scenario *domain.GenericScenario) int {
+	years := 0
+	if household == nil || scenario == nil {
+		return years
+	}
+	for _, event := range scenarioHorizonEvents(household, scenario) {
+		years = max(years, event.year-ProjectionBaseYear+1)
+	}
+	return years
+}
+
+// scenarioHorizonEvents lists the dated events of each participant in the scenario
+func scenarioHorizonEvents(household *domain.Household, scenario *domain.GenericScenario) []horizonEvent {
+	var events []horizonEvent
+	for i := range household.Participants {
+		p := &household.Participants[i]
+		ps, ok := scenario.ParticipantScenarios[p.Name]
+		if !ok {
+			continue
+		}
+		label := p.Label()
+		add := func(event string, year int) {
+			events = append(events, horizonEvent{participant: label, label: event, year: year})
+		}
+		birthYear := p.BirthDate.Year()
+
+		switch {
+		case ps.SeparationDate != nil:
+			add("separation", ps.SeparationDate.Year())
+			if ps.AnnuityStartAge > 0 {
+				add(fmt.Sprintf("deferred annuity at %d", ps.AnnuityStartAge), birthYear+ps.AnnuityStartAge)
+			}
+		case ps.RetirementDate != nil:
+			add("retirement", ps.RetirementDate.Year())
+		}
+
+		claimed := p.CurrentRetirement != nil && p.CurrentRetirement.SSMonthlyBenefit.IsPositive()
+		if !claimed && (p.SSBenefitFRA.IsPositive() || p.SSBenefit62.IsPositive()) {
+			ssStartAge := ps.SSStartAge
+			if ssStartAge == 0 {
+				ssStartAge = 67
+			}
+			add(fmt.Sprintf("Social Security claim at %d", ssStartAge), birthYear+ssStartAge)
+		}
+
+		if hasTraditionalSavings(p) {
+			rmdAge := dateutil.GetRMDAge(birthYear)
+			add(fmt.Sprintf("start of RMDs at %d", rmdAge), birthYear+rmdAge)
+		}
+
+		if ps.TSPAnnuity != nil && ps.TSPAnnuity.PurchaseAge > 0 {
+			add(fmt.Sprintf("TSP annuity purchase at %d", ps.TSPAnnuity.PurchaseAge), birthYear+ps.TSPAnnuity.PurchaseAge)
+		}
+		for _, purchase := range ps.AnnuityPurchases {
+			add("purchase of "+purchase.Label(), birthYear+purchase.PurchaseAge)
+			if purchase.IncomeStartAge > purchase.PurchaseAge {
+				add("first payment of "+purchase.Label(), birthYear+purchase.IncomeStartAge)
+			}
+		}
+
+		if scenario.Mortality != nil {
+			if spec := scenario.Mortality.Participants[p.Name]; spec != nil && spec.DeathDate == nil && spec.DeathAge != nil {
+				add(fmt.Sprintf("death at %d", *spec.DeathAge), birthYear+*spec.DeathAge)
+			}
+		}
+	}
+	return events
+}
+
+// hasTraditionalSavings reports whether the participant holds tax-deferred savings that
+// will be subject to RMDs
+func hasTraditionalSavings(p *domain.Participant) bool {
+	switch {
+	case p.TSPBalanceTraditional != nil && p.TSPBalanceTraditional.IsPositive():
+		return true
+	case p.IRABalanceTraditional != nil && p.IRABalanceTraditional.IsPositive():
+		return true
+	case p.EmployerPlan != nil && p.EmployerPlan.BalanceTraditional.IsPositive():
+		return true
+	}
+	return p.TSPContributionPercent != nil && p.TSPContributionPercent.IsPositive()
+}
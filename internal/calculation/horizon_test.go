@@ -0,0 +1,75 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionHorizonWarnings(t *testing.T) {
+	balance := decimal.NewFromInt(500000)
+	retire := time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC)
+	deathAge := 90
+	household := &domain.Household{Participants: []domain.Participant{{
+		Name:                  "Alice",
+		BirthDate:             time.Date(1968, 5, 1, 0, 0, 0, 0, time.UTC),
+		SSBenefitFRA:          decimal.NewFromInt(2500),
+		TSPBalanceTraditional: &balance,
+	}}}
+	scenario := &domain.GenericScenario{
+		Name: "Base",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", RetirementDate: &retire, SSStartAge: 70},
+		},
+		Mortality: &domain.GenericScenarioMortality{
+			Participants: map[string]*domain.MortalitySpec{"Alice": {DeathAge: &deathAge}},
+		},
+	}
+
+	// A 15 year projection ends in 2039: the claim in 2038 is included, the RMDs from 2043
+	// and death in 2058 are not
+	warnings := ProjectionHorizonWarnings(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 15})
+	require.Len(t, warnings, 2)
+	assert.Equal(t, domain.WarningHorizonTruncated, warnings[0].Code)
+	assert.Equal(t, 2043, warnings[0].Year)
+	assert.Equal(t, "Alice: start of RMDs at 75 in 2043 falls after the projection ends in 2039; projection_years: 19 would include it", warnings[0].Message)
+	assert.Equal(t, 2058, warnings[1].Year)
+	assert.Equal(t, 34, MinimumProjectionYears(household, scenario))
+
+	assert.Empty(t, ProjectionHorizonWarnings(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 34}))
+
+	// The scenario's own horizon takes precedence over the global one
+	scenario.ProjectionYears = 34
+	assert.Empty(t, ProjectionHorizonWarnings(household, scenario, &domain.GlobalAssumptions{ProjectionYears: 15}))
+}
+
+func TestScenarioProjectionYears(t *testing.T) {
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+	longevity := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {ParticipantName: "Gail", SSStartAge: 70}},
+		ProjectionYears:      5,
+	}
+	assert.Equal(t, 5, ScenarioProjectionYears(longevity, assumptions))
+	assert.Equal(t, 3, ScenarioProjectionYears(&domain.GenericScenario{}, assumptions))
+	assert.Zero(t, ScenarioProjectionYears(nil, nil))
+
+	ce := NewCalculationEngine()
+	projection := ce.GenerateAnnualProjectionGeneric(cashFlowEventHousehold(), longevity, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 5)
+	assert.Equal(t, 2029, projection[4].Date.Year())
+	// Shorter and longer horizons of the same plan agree on the years they share
+	longevity.ProjectionYears = 0
+	short := ce.GenerateAnnualProjectionGeneric(cashFlowEventHousehold(), longevity, assumptions, assumptions.FederalRules)
+	require.Len(t, short, 3)
+	longevity.ProjectionYears = 6
+	long := ce.GenerateAnnualProjectionGeneric(cashFlowEventHousehold(), longevity, assumptions, assumptions.FederalRules)
+	require.Len(t, long, 6)
+	for i := range short {
+		assert.Equal(t, short[i].NetIncome.String(), projection[i].NetIncome.String())
+		assert.Equal(t, short[i].NetIncome.String(), long[i].NetIncome.String())
+	}
+}
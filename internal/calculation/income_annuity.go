@@ -0,0 +1,151 @@
+package calculation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// expectedReturnMultiples are the IRS Table V (ordinary life annuities, one life) expected
+// return multiples by age at the annuity starting date, ages 50 through 90. They set how
+// fast a nonqualified annuity returns its premium tax free.
+var expectedReturnMultiples = []float64{
+	33.1, 32.2, 31.3, 30.4, 29.5, 28.6, 27.7, 26.8, 25.9, 25.0, // 50-59
+	24.2, 23.3, 22.5, 21.6, 20.8, 20.0, 19.2, 18.4, 17.6, 16.8, // 60-69
+	16.0, 15.3, 14.6, 13.9, 13.2, 12.5, 11.9, 11.2, 10.6, 10.0, // 70-79
+	9.5, 8.9, 8.4, 7.9, 7.4, 6.9, 6.5, 6.1, 5.7, 5.3, // 80-89
+	5.0, // 90
+}
+
+// ExpectedReturnMultiple returns the Table V expected return multiple for an annuitant's
+// age at the annuity starting date, clamped to ages 50 through 90
+func ExpectedReturnMultiple(age int) decimal.Decimal {
+	i := min(max(age-50, 0), len(expectedReturnMultiples)-1)
+	return decimal.NewFromFloat(expectedReturnMultiples[i])
+}
+
+// incomeAnnuity is a purchased income annuity in force
+type incomeAnnuity struct {
+	label           string
+	annual          decimal.Decimal // payments for a full year
+	cola            decimal.Decimal
+	survivorPercent decimal.Decimal // share of the payment continuing to the spouse
+	startYear       int             // projection year of the first payment
+	firstYearMonths int             // payments in the first year
+	increasedYear   int             // last projection year the COLA was applied
+	exclusionRatio  decimal.Decimal // share of each nonqualified payment that returns premium
+	premiumLeft     decimal.Decimal // premium not yet returned tax free
+	survivorOnly    bool            // the annuitant has died
+}
+
+// incomeAnnuityIncomeStart returns the first payment month of an annuity bought on
+// purchaseDate: the month after the purchase, or after the birthday at IncomeStartAge
+// for a deferred annuity
+func incomeAnnuityIncomeStart(purchase domain.IncomeAnnuityPurchase, p *domain.Participant, purchaseDate time.Time) time.Time {
+	start := purchaseDate
+	if purchase.IncomeStartAge > purchase.PurchaseAge {
+		start = p.BirthDate.AddDate(purchase.IncomeStartAge, 0, 0)
+	}
+	return time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+}
+
+// purchaseIncomeAnnuity pays the premium from the purchase's source and puts the annuity
+// in force. A qualified purchase moves traditional IRA, then traditional TSP, money into
+// the annuity without tax; a nonqualified purchase sells taxable investments, realizing
+// their gains. It returns the premium paid, which is less than asked when the source
+// runs short, and the capital gains realized.
+func (st *participantState) purchaseIncomeAnnuity(purchase domain.IncomeAnnuityPurchase, p *domain.Participant, purchaseDate time.Time, startYear int) (paid, gains decimal.Decimal) {
+	remaining := purchase.Premium
+	qualified := purchase.Source == domain.AnnuitySourceTraditional
+	if qualified {
+		fromIRA := decimal.Min(remaining, st.iraBalanceTraditional)
+		st.iraBalanceTraditional = st.iraBalanceTraditional.Sub(fromIRA)
+		fromTSP := decimal.Min(remaining.Sub(fromIRA), st.tspBalanceTraditional)
+		st.tspBalanceTraditional = st.tspBalanceTraditional.Sub(fromTSP)
+		st.tspBalance = st.tspBalance.Sub(fromTSP)
+		paid = fromIRA.Add(fromTSP)
+	} else if st.taxableBalance.IsPositive() {
+		paid = decimal.Min(remaining, st.taxableBalance)
+		basisUsed := st.taxableBasis.Mul(paid).Div(st.taxableBalance)
+		gains = paid.Sub(basisUsed)
+		st.taxableBasis = st.taxableBasis.Sub(basisUsed)
+		st.taxableBalance = st.taxableBalance.Sub(paid)
+	}
+	if !paid.IsPositive() {
+		return decimalZero, decimalZero
+	}
+
+	incomeStart := incomeAnnuityIncomeStart(purchase, p, purchaseDate)
+	annuity := incomeAnnuity{
+		label:           purchase.Label(),
+		annual:          paid.Mul(purchase.PayoutRate),
+		cola:            purchase.COLA,
+		survivorPercent: purchase.SurvivorPercent,
+		startYear:       max(incomeStart.Year()-startYear, 0),
+		firstYearMonths: 12 - int(incomeStart.Month()) + 1,
+	}
+	if incomeStart.Year() < startYear {
+		annuity.firstYearMonths = 12
+	}
+	annuity.increasedYear = annuity.startYear
+	if !qualified && annuity.annual.IsPositive() {
+		// The premium is the investment in the contract, recovered over the expected
+		// return: the first year's payments times the Table V multiple at the start age
+		expectedReturn := annuity.annual.Mul(ExpectedReturnMultiple(p.Age(incomeStart)))
+		annuity.exclusionRatio = decimal.Min(paid.Div(expectedReturn), decimalOne)
+		annuity.premiumLeft = paid
+	}
+	st.incomeAnnuities = append(st.incomeAnnuities, annuity)
+	return paid, gains
+}
+
+// payIncomeAnnuities returns the year's payments from the participant's income annuities
+// and the part of them that returns premium tax free. After the annuitant's death only the
+// survivor share is paid; the annuities are dropped when nobody survives to receive it.
+func (st *participantState) payIncomeAnnuities(yr int, survivorsAlive bool) (payments, taxFree decimal.Decimal) {
+	kept := st.incomeAnnuities[:0]
+	for _, a := range st.incomeAnnuities {
+		if a.survivorOnly && (!survivorsAlive || !a.survivorPercent.IsPositive()) {
+			continue
+		}
+		kept = append(kept, a)
+		if yr < a.startYear {
+			continue
+		}
+		a := &kept[len(kept)-1]
+		if yr > a.increasedYear {
+			a.annual = a.annual.Mul(onePlus(a.cola))
+			a.increasedYear = yr
+		}
+		payment := a.annual
+		if yr == a.startYear {
+			payment = payment.Mul(decimal.NewFromInt(int64(a.firstYearMonths))).Div(decimal.NewFromInt(12))
+		}
+		if a.survivorOnly {
+			payment = payment.Mul(a.survivorPercent)
+		}
+		if a.premiumLeft.IsPositive() {
+			excluded := decimal.Min(payment.Mul(a.exclusionRatio), a.premiumLeft)
+			a.premiumLeft = a.premiumLeft.Sub(excluded)
+			taxFree = taxFree.Add(excluded)
+		}
+		payments = payments.Add(payment)
+	}
+	st.incomeAnnuities = kept
+	return payments, taxFree
+}
+
+// premiumShortfallWarning reports an annuity purchase the source balance could not cover
+func premiumShortfallWarning(p *domain.Participant, purchase domain.IncomeAnnuityPurchase, paid decimal.Decimal, year int) domain.EngineWarning {
+	unpaid := purchase.Premium.Sub(paid)
+	return domain.EngineWarning{
+		Year:        year,
+		Participant: p.Name,
+		Code:        domain.WarningPremiumShortfall,
+		Amount:      unpaid,
+		Message: fmt.Sprintf("%s: %s premium of $%s exceeded the %s balance; bought $%s",
+			p.Label(), purchase.Label(), purchase.Premium.StringFixed(0), purchase.Source, paid.StringFixed(0)),
+	}
+}
@@ -0,0 +1,104 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionIncomeAnnuityPurchases(t *testing.T) {
+	taxable, basis := decimal.NewFromInt(300000), decimal.NewFromInt(200000)
+	ira := decimal.NewFromInt(300000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Dawn", BirthDate: time.Date(1960, 3, 1, 0, 0, 0, 0, time.UTC),
+			TaxableAccountBalance: &taxable, TaxableAccountBasis: &basis, IRABalanceTraditional: &ira,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Dawn": {ParticipantName: "Dawn", RetirementDate: &retire, SSStartAge: 70, AnnuityPurchases: []domain.IncomeAnnuityPurchase{
+			{Name: "SPIA", Premium: decimal.NewFromInt(100000), Source: domain.AnnuitySourceTaxable, PurchaseAge: 66,
+				PayoutRate: decimal.NewFromFloat(0.07), COLA: decimal.NewFromFloat(0.02)},
+			{Name: "DIA", Premium: decimal.NewFromInt(50000), Source: domain.AnnuitySourceTraditional, PurchaseAge: 65, IncomeStartAge: 67,
+				PayoutRate: decimal.NewFromFloat(0.1)},
+		}},
+	}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 3)
+
+	// The deferred annuity is bought in 2025 from the IRA without tax and pays nothing yet
+	assert.Equal(t, "50000", projection[0].IncomeAnnuityPremiums.String())
+	assert.True(t, projection[0].GetTotalIncomeAnnuity().IsZero())
+
+	// The immediate annuity bought on the 2026 birthday pays from April. Its premium comes
+	// back tax free over the Table V multiple of 19.2 at 66.
+	assert.Equal(t, "100000", projection[1].IncomeAnnuityPremiums.String())
+	assert.True(t, projection[1].CapitalGainsRealized.GreaterThanOrEqual(decimal.RequireFromString("33333.33")))
+	assert.Equal(t, "5250.00", projection[1].GetTotalIncomeAnnuity().StringFixed(2))
+	assert.Equal(t, "3906.25", projection[1].IncomeAnnuityTaxFree.StringFixed(2))
+
+	// In 2027 the immediate annuity pays a full year with its COLA and the deferred annuity
+	// begins in April, fully taxable
+	assert.Equal(t, "10890.00", projection[2].IncomeAnnuities.Get("Dawn").StringFixed(2)) // 7,140 + 3,750
+	assert.Equal(t, "5312.50", projection[2].IncomeAnnuityTaxFree.StringFixed(2))
+	assert.True(t, projection[2].GuaranteedIncome.GreaterThanOrEqual(decimal.NewFromInt(10890)))
+}
+
+func TestIncomeAnnuitySurvivorPayments(t *testing.T) {
+	st := &participantState{incomeAnnuities: []incomeAnnuity{
+		{annual: decimal.NewFromInt(10000), survivorPercent: decimal.NewFromFloat(0.5), firstYearMonths: 12},
+		{annual: decimal.NewFromInt(6000), firstYearMonths: 12},
+	}}
+	payments, taxFree := st.payIncomeAnnuities(0, true)
+	assert.Equal(t, "16000", payments.String())
+	assert.True(t, taxFree.IsZero())
+
+	// After the annuitant's death only the joint annuity's survivor share continues
+	for i := range st.incomeAnnuities {
+		st.incomeAnnuities[i].survivorOnly = true
+	}
+	payments, _ = st.payIncomeAnnuities(1, true)
+	assert.Equal(t, "5000", payments.String())
+	require.Len(t, st.incomeAnnuities, 1)
+
+	payments, _ = st.payIncomeAnnuities(2, false)
+	assert.True(t, payments.IsZero())
+	assert.Empty(t, st.incomeAnnuities)
+}
+
+func TestPremiumShortfallWarning(t *testing.T) {
+	cash := decimal.NewFromInt(40000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Dawn", BirthDate: time.Date(1960, 3, 1, 0, 0, 0, 0, time.UTC), TaxableAccountBalance: &cash, TaxableAccountBasis: &cash,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 1}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Dawn": {ParticipantName: "Dawn", RetirementDate: &retire, SSStartAge: 70, AnnuityPurchases: []domain.IncomeAnnuityPurchase{
+			{Premium: decimal.NewFromInt(100000), Source: domain.AnnuitySourceTaxable, PurchaseAge: 65, PayoutRate: decimal.NewFromFloat(0.07)},
+		}},
+	}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 1)
+	assert.Equal(t, "40000", projection[0].IncomeAnnuityPremiums.String())
+	require.Len(t, projection[0].Warnings, 1)
+	assert.Equal(t, domain.WarningPremiumShortfall, projection[0].Warnings[0].Code)
+	assert.Equal(t, "60000", projection[0].Warnings[0].Amount.String())
+	assert.Equal(t, "Dawn: income annuity at 65 premium of $100000 exceeded the taxable balance; bought $40000", projection[0].Warnings[0].Message)
+}
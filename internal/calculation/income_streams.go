@@ -0,0 +1,64 @@
+package calculation
+
+import (
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// incomeStreamMonths returns the number of months of year the stream pays, counting the
+// months of its start and end dates
+func incomeStreamMonths(s domain.IncomeStream, year int) int {
+	first, last := time.January, time.December
+	if s.StartDate != nil {
+		switch {
+		case s.StartDate.Year() > year:
+			return 0
+		case s.StartDate.Year() == year:
+			first = s.StartDate.Month()
+		}
+	}
+	if s.EndDate != nil {
+		switch {
+		case s.EndDate.Year() < year:
+			return 0
+		case s.EndDate.Year() == year:
+			last = s.EndDate.Month()
+		}
+	}
+	return max(int(last-first)+1, 0)
+}
+
+// incomeStreamAmount returns the stream's income in projection year yr: the annual amount
+// grown from the first projection year at its growth rate, or inflation, and prorated by
+// the months it pays
+func incomeStreamAmount(s domain.IncomeStream, startYear, yr int, inflation decimal.Decimal) decimal.Decimal {
+	months := incomeStreamMonths(s, startYear+yr)
+	if months == 0 {
+		return decimalZero
+	}
+	growth := inflation
+	if s.GrowthRate != nil {
+		growth = *s.GrowthRate
+	}
+	amount := s.AnnualAmount.Mul(onePlus(growth).Pow(decimal.NewFromInt(int64(yr))))
+	if months < 12 {
+		amount = amount.Mul(decimal.NewFromInt(int64(months))).Div(decimal.NewFromInt(12))
+	}
+	return amount
+}
+
+// otherIncomeForYear returns a participant's earned and passive income from their income
+// streams in projection year yr
+func otherIncomeForYear(streams []domain.IncomeStream, startYear, yr int, inflation decimal.Decimal) (earned, passive decimal.Decimal) {
+	for _, s := range streams {
+		amount := incomeStreamAmount(s, startYear, yr, inflation)
+		if s.Earned() {
+			earned = earned.Add(amount)
+		} else {
+			passive = passive.Add(amount)
+		}
+	}
+	return earned, passive
+}
@@ -0,0 +1,71 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomeStreamMonths(t *testing.T) {
+	start := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2028, 9, 30, 0, 0, 0, 0, time.UTC)
+	stream := domain.IncomeStream{Type: domain.IncomeStreamRental, StartDate: &start, EndDate: &end}
+
+	assert.Equal(t, 0, incomeStreamMonths(stream, 2025))
+	assert.Equal(t, 9, incomeStreamMonths(stream, 2026))
+	assert.Equal(t, 12, incomeStreamMonths(stream, 2027))
+	assert.Equal(t, 9, incomeStreamMonths(stream, 2028))
+	assert.Equal(t, 0, incomeStreamMonths(stream, 2029))
+}
+
+func TestSelfEmploymentTax(t *testing.T) {
+	fica := NewFICACalculator2025()
+	// 15.3% of 92.35% of the earnings
+	assert.Equal(t, "7064.78", fica.CalculateSelfEmploymentTax(decimal.NewFromInt(50000), decimal.Zero).StringFixed(2))
+	// Wages of $170,000 leave $6,100 of the Social Security wage base
+	assert.Equal(t, "2095.48", fica.CalculateSelfEmploymentTax(decimal.NewFromInt(50000), decimal.NewFromInt(170000)).StringFixed(2))
+	assert.True(t, fica.CalculateSelfEmploymentTax(decimal.NewFromInt(400), decimal.Zero).IsZero())
+}
+
+func TestProjectionIncomeStreams(t *testing.T) {
+	consultingEnd := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	growth := decimal.NewFromFloat(0.03)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Dawn", BirthDate: time.Date(1962, 3, 1, 0, 0, 0, 0, time.UTC),
+			OtherIncome: []domain.IncomeStream{
+				{Name: "Consulting", Type: domain.IncomeStreamConsulting, AnnualAmount: decimal.NewFromInt(50000), EndDate: &consultingEnd},
+				{Name: "Duplex", Type: domain.IncomeStreamRental, AnnualAmount: decimal.NewFromInt(24000), GrowthRate: &growth},
+			},
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Dawn": {ParticipantName: "Dawn", RetirementDate: &retire, SSStartAge: 70},
+	}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 3)
+
+	first := projection[0]
+	assert.Equal(t, "74000", first.OtherIncome.Get("Dawn").String())
+	assert.Equal(t, "50000", first.SelfEmploymentIncome.Get("Dawn").String())
+	assert.Equal(t, "7064.78", first.SelfEmploymentTax.StringFixed(2))
+	assert.True(t, first.FICATax.Equal(first.SelfEmploymentTax), "no wages, so FICA is only self-employment tax")
+	assert.True(t, first.LocalTax.IsPositive(), "consulting profits are subject to the local earned income tax")
+	assert.True(t, first.FederalTax.IsPositive())
+
+	// The consulting ends in June 2026 and the rent grows 3% a year
+	assert.Equal(t, "49720.00", projection[1].OtherIncome.Get("Dawn").StringFixed(2))
+	assert.Equal(t, "25461.60", projection[2].OtherIncome.Get("Dawn").StringFixed(2))
+	assert.True(t, projection[2].SelfEmploymentTax.IsZero())
+	assert.True(t, projection[2].LocalTax.IsZero(), "rent is not earned income")
+}
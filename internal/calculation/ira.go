@@ -0,0 +1,97 @@
+package calculation
+
+import (
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// iraBalance returns the participant's combined traditional and Roth IRA balance
+func (st *participantState) iraBalance() decimal.Decimal {
+	return st.iraBalanceTraditional.Add(st.iraBalanceRoth)
+}
+
+// contributeIRA makes the participant's configured IRA contributions for a year with
+// salary. Traditional contributions go first; together they may not exceed the IRA limit
+// or the year's salary, and any excess is reported rather than contributed.
+func (st *participantState) contributeIRA(cf *domain.AnnualCashFlow, p *domain.Participant, salary decimal.Decimal, age, year int) {
+	if !salary.IsPositive() || (p.IRAContributionTraditional == nil && p.IRAContributionRoth == nil) {
+		return
+	}
+	limit := decimal.Min(IRAContributionLimit(age), salary)
+	room := limit
+	contribute := func(configured *decimal.Decimal) decimal.Decimal {
+		if configured == nil || !configured.IsPositive() {
+			return decimalZero
+		}
+		amount := decimal.Min(*configured, room)
+		room = room.Sub(amount)
+		return amount
+	}
+	requested := decimalZero
+	for _, configured := range []*decimal.Decimal{p.IRAContributionTraditional, p.IRAContributionRoth} {
+		if configured != nil && configured.IsPositive() {
+			requested = requested.Add(*configured)
+		}
+	}
+	traditional := contribute(p.IRAContributionTraditional)
+	roth := contribute(p.IRAContributionRoth)
+	st.iraBalanceTraditional = st.iraBalanceTraditional.Add(traditional)
+	st.iraBalanceRoth = st.iraBalanceRoth.Add(roth)
+	st.rothIRA.contribute(roth)
+	cf.IRAContributions.Set(p.Name, traditional.Add(roth))
+	cf.IRATraditionalContributions = cf.IRATraditionalContributions.Add(traditional)
+
+	if excess := requested.Sub(traditional).Sub(roth); excess.GreaterThanOrEqual(decimalOne) {
+		cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+			Year:        year,
+			Participant: p.Name,
+			Code:        domain.WarningContributionLimit,
+			Amount:      excess,
+			Message: fmt.Sprintf("%s: configured IRA contributions exceed the $%s IRA limit; $%s not contributed",
+				p.Label(), limit.StringFixed(0), excess.StringFixed(0)),
+		})
+	}
+}
+
+// withdrawIRATraditional distributes up to amount from the traditional IRA and returns
+// the amount distributed
+func (st *participantState) withdrawIRATraditional(cf *domain.AnnualCashFlow, p *domain.Participant, amount decimal.Decimal) decimal.Decimal {
+	amount = decimal.Min(decimal.Max(amount, decimalZero), st.iraBalanceTraditional)
+	st.iraBalanceTraditional = st.iraBalanceTraditional.Sub(amount)
+	cf.IRAWithdrawalsTraditional.Set(p.Name, cf.IRAWithdrawalsTraditional.Get(p.Name).Add(amount))
+	return amount
+}
+
+// withdrawIRARoth distributes up to amount from the Roth IRA and returns the amount
+// distributed
+func (st *participantState) withdrawIRARoth(cf *domain.AnnualCashFlow, p *domain.Participant, amount decimal.Decimal) decimal.Decimal {
+	amount = decimal.Min(decimal.Max(amount, decimalZero), st.iraBalanceRoth)
+	st.iraBalanceRoth = st.iraBalanceRoth.Sub(amount)
+	st.rothIRADistributed = st.rothIRADistributed.Add(amount)
+	cf.IRAWithdrawalsRoth.Set(p.Name, cf.IRAWithdrawalsRoth.Get(p.Name).Add(amount))
+	return amount
+}
+
+// withdrawIRA distributes up to amount from the IRAs, traditional before Roth, and
+// returns the amount distributed
+func (st *participantState) withdrawIRA(cf *domain.AnnualCashFlow, p *domain.Participant, amount decimal.Decimal) decimal.Decimal {
+	traditional := st.withdrawIRATraditional(cf, p, amount)
+	return traditional.Add(st.withdrawIRARoth(cf, p, amount.Sub(traditional)))
+}
+
+// satisfyIRARMD tops the year's traditional IRA distributions up to the required minimum.
+// IRA RMDs are computed separately from the TSP's and are due whether or not the
+// participant still works.
+func (st *participantState) satisfyIRARMD(cf *domain.AnnualCashFlow, p *domain.Participant, rmd decimal.Decimal) {
+	if taken := cf.IRAWithdrawalsTraditional.Get(p.Name); rmd.GreaterThan(taken) {
+		st.withdrawIRATraditional(cf, p, rmd.Sub(taken))
+	}
+}
+
+// growIRA applies a year's return to both IRA balances
+func (st *participantState) growIRA(rate decimal.Decimal) {
+	st.iraBalanceTraditional = st.iraBalanceTraditional.Mul(onePlus(rate))
+	st.iraBalanceRoth = st.iraBalanceRoth.Mul(onePlus(rate))
+}
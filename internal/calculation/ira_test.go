@@ -0,0 +1,113 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionIRAContributionsAndRMDs(t *testing.T) {
+	value := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	participant := domain.Participant{Name: "Pat", BirthDate: time.Date(1951, 6, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: value(50000)}
+	withIRA := participant
+	withIRA.IRABalanceTraditional = value(200000)
+	withIRA.IRABalanceRoth = value(50000)
+	withIRA.IRAContributionTraditional = value(9000)
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         1,
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	project := func(p domain.Participant) domain.AnnualCashFlow {
+		household := &domain.Household{FilingStatus: "single", Participants: []domain.Participant{p}}
+		projection := ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+		require.Len(t, projection, 1)
+		return projection[0]
+	}
+	cf := project(withIRA)
+
+	// Still working at 73: $9,000 configured is held to the $8,000 limit, and the IRA's RMD
+	// is taken even though the participant has not retired
+	assert.Equal(t, "8000", cf.IRAContributions.Get("Pat").String())
+	require.Len(t, cf.Warnings, 1)
+	assert.Equal(t, domain.WarningContributionLimit, cf.Warnings[0].Code)
+	assert.Equal(t, "1000", cf.Warnings[0].Amount.String())
+	rmd := decimal.NewFromInt(200000).Div(decimal.NewFromFloat(26.5))
+	assert.True(t, cf.IRAWithdrawalsTraditional.Get("Pat").Equal(rmd))
+	assert.True(t, cf.IRAWithdrawalsRoth.Get("Pat").IsZero())
+	expected := decimal.NewFromInt(208000).Sub(rmd).Add(decimal.NewFromInt(50000)).Mul(decimal.NewFromFloat(1.05))
+	assert.Equal(t, expected.StringFixed(2), cf.IRABalances.Get("Pat").StringFixed(2))
+
+	// The RMD is taxable income and the traditional contribution is deducted
+	base := project(participant)
+	assert.Equal(t, rmd.Sub(decimal.NewFromInt(8000)).StringFixed(2), cf.FederalTaxableIncome.Sub(base.FederalTaxableIncome).StringFixed(2))
+	assert.True(t, cf.TotalGrossIncome.Sub(base.TotalGrossIncome).Equal(rmd))
+}
+
+func TestProjectionDrawsIRAsWhenTSPRunsOut(t *testing.T) {
+	value := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Pat", BirthDate: time.Date(1962, 3, 1, 0, 0, 0, 0, time.UTC),
+			TSPBalanceTraditional: value(30000), TSPBalanceRoth: value(0),
+			IRABalanceTraditional: value(20000), IRABalanceRoth: value(100000),
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	monthly := decimal.NewFromInt(2000)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Pat": {ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 70, TSPWithdrawalStrategy: "need_based", TSPWithdrawalTargetMonthly: &monthly},
+	}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 3)
+
+	// Without a sequence the TSP is spent first and the IRAs cover the rest, traditional
+	// before Roth, with no shortfall
+	second := projection[1]
+	assert.Equal(t, "6000", second.TSPWithdrawals.Get("Pat").String())
+	assert.Equal(t, "18000", second.IRAWithdrawalsTraditional.Get("Pat").String())
+	assert.True(t, second.IRAWithdrawalsRoth.Get("Pat").IsZero())
+	third := projection[2]
+	assert.Equal(t, "2000", third.IRAWithdrawalsTraditional.Get("Pat").String())
+	assert.Equal(t, "22000", third.IRAWithdrawalsRoth.Get("Pat").String())
+	for _, cf := range projection {
+		assert.Empty(t, cf.Warnings)
+	}
+
+	// With a sequence the Roth IRA can be drawn ahead of the TSP
+	scenario.WithdrawalSequencing = &domain.WithdrawalSequencingConfig{Strategy: "custom", CustomSequence: []string{"ira_roth", "traditional"}}
+	projection = ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	assert.Equal(t, "24000", projection[0].IRAWithdrawalsRoth.Get("Pat").String())
+	assert.True(t, projection[0].TSPWithdrawals.Get("Pat").IsZero())
+	assert.Equal(t, "30000", projection[0].TotalTSPBalance().String())
+}
+
+func TestTaxableSocialSecurityCountsIRAs(t *testing.T) {
+	ce := NewCalculationEngine()
+	cf := domain.NewAnnualCashFlowWithIndex(0, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), domain.NewParticipantIndex([]string{"Pat"}))
+	cf.SSBenefits.Set("Pat", decimal.NewFromInt(24000))
+	taxableSS := func() string {
+		return ce.taxableSocialSecurity(cf, decimal.Zero, decimal.Zero, "single").String()
+	}
+	assert.Equal(t, "0", taxableSS())
+
+	// A $30,000 traditional IRA draw puts provisional income at $42,000:
+	// half of $9,000 above the first threshold plus 85% of $8,000 above the second
+	cf.IRAWithdrawalsTraditional.Set("Pat", decimal.NewFromInt(30000))
+	assert.Equal(t, "11300", taxableSS())
+
+	// A deductible $7,000 contribution lowers it to $35,000
+	cf.IRATraditionalContributions = decimal.NewFromInt(7000)
+	assert.Equal(t, "5350", taxableSS())
+}
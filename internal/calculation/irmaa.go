@@ -12,8 +12,9 @@ const (
 	IRMAAWarningDistance = 10000
 )
 
-// CalculateMAGI calculates Modified Adjusted Gross Income for IRMAA purposes
-// MAGI for IRMAA includes:
+// CalculateMAGI estimates Modified Adjusted Gross Income from the income a year has
+// accumulated so far, before the taxable share of Social Security is known; magiBreakdown
+// gives the year's final MAGI. It includes:
 // - Wages and salaries
 // - Pensions (FERS, etc.)
 // - Traditional TSP withdrawals (not Roth)
@@ -25,20 +26,32 @@ func CalculateMAGI(acf *domain.AnnualCashFlow) decimal.Decimal {
 
 	// Add all salaries
 	magi = magi.Add(acf.GetTotalSalary())
+	// FEHB premiums paid through premium conversion and pre-tax employer plan deferrals
+	// are excluded from wages
+	magi = magi.Sub(acf.FEHBPreTaxPremium).Sub(acf.EmployerPlanPreTax)
 
 	// Add all pensions
 	magi = magi.Add(acf.GetTotalPension())
 	magi = magi.Add(acf.GetTotalSurvivorPension())
 
-	// Add Traditional TSP withdrawals
-	// Note: In a real implementation, we'd need to distinguish between
-	// Traditional and Roth TSP withdrawals. For now, we'll assume
-	// all TSP withdrawals are from Traditional accounts.
-	magi = magi.Add(acf.GetTotalTSPWithdrawal())
+	// Add TSP withdrawals; Roth withdrawals are tax free but for the earnings of
+	// nonqualified ones
+	magi = magi.Add(acf.GetTotalTSPWithdrawal()).Sub(acf.WithdrawalRoth).Add(acf.RothTaxableEarnings)
+	// Qualified charitable distributions are excluded from income
+	magi = magi.Sub(acf.QualifiedCharitableDistributions)
 
 	// Add FERS supplement (if any)
 	magi = magi.Add(acf.GetTotalFERSSupplement())
 
+	// TSP life annuity payments are taxed like TSP withdrawals
+	magi = magi.Add(acf.GetTotalTSPAnnuity())
+
+	// Purchased income annuities count except for a nonqualified annuity's return of premium
+	magi = magi.Add(acf.GetTaxableIncomeAnnuity())
+
+	// Traditional IRA distributions are taxable; deductible contributions reduce income
+	magi = magi.Add(acf.GetTotalTraditionalIRAWithdrawal()).Sub(acf.IRATraditionalContributions)
+
 	// Taxable portion of Social Security is already calculated in the tax engine
 	// For IRMAA purposes, we need to add the taxable SS benefits
 	// Note: The actual taxable SS calculation is complex and done elsewhere
@@ -47,9 +60,57 @@ func CalculateMAGI(acf *domain.AnnualCashFlow) decimal.Decimal {
 	taxableSSBenefits := acf.GetTotalSSBenefit().Mul(decimal.NewFromFloat(0.85))
 	magi = magi.Add(taxableSSBenefits)
 
+	// Realized long-term capital gains from taxable account withdrawals
+	magi = magi.Add(acf.CapitalGainsRealized)
+
 	return magi
 }
 
+// irmaaLookbackYears is how many years before a surcharge the MAGI it is based on is earned
+const irmaaLookbackYears = 2
+
+// lookbackMAGI returns the MAGI that sets year yr's IRMAA surcharges: the projection's own
+// from two years earlier, or the household's MAGI history for years before the projection
+func lookbackMAGI(household *domain.Household, projection []domain.AnnualCashFlow, yr, startYear int) decimal.Decimal {
+	if yr >= irmaaLookbackYears {
+		return projection[yr-irmaaLookbackYears].MAGI
+	}
+	return household.MAGIHistory[startYear+yr-irmaaLookbackYears]
+}
+
+// medicareWithinLookback reports whether a living participant is on Medicare by the year
+// the year's MAGI sets surcharges for
+func medicareWithinLookback(cf *domain.AnnualCashFlow) bool {
+	for _, name := range cf.GetLivingParticipants() {
+		if cf.Ages.Get(name)+irmaaLookbackYears >= 65 {
+			return true
+		}
+	}
+	return false
+}
+
+// magiBreakdown traces the year's MAGI from its income, given the taxable part of Social
+// Security, ordinary income outside the plan and tax-exempt interest
+func magiBreakdown(cf *domain.AnnualCashFlow, taxableSS, otherIncome, taxExemptInterest decimal.Decimal) domain.MAGIBreakdown {
+	b := domain.MAGIBreakdown{
+		Wages:    cf.GetTotalSalary().Sub(cf.FEHBPreTaxPremium).Sub(cf.EmployerPlanPreTax),
+		Pensions: cf.GetTotalPension().Add(cf.GetTotalSurvivorPension()).Add(cf.GetTotalFERSSupplement()),
+		RetirementDistributions: cf.GetTotalTSPWithdrawal().Sub(cf.WithdrawalRoth).Add(cf.RothTaxableEarnings).
+			Sub(cf.QualifiedCharitableDistributions).Add(cf.GetTotalTSPAnnuity()).Add(cf.GetTaxableIncomeAnnuity()).
+			Add(cf.GetTotalTraditionalIRAWithdrawal()),
+		TaxableSocialSecurity: taxableSS,
+		CapitalGains:          cf.CapitalGainsRealized,
+		OtherIncome:           otherIncome,
+		IRADeduction:          cf.IRATraditionalContributions,
+		TaxExemptInterest:     taxExemptInterest,
+		UntaxedSocialSecurity: cf.GetTotalSSBenefit().Sub(taxableSS),
+	}
+	b.AGI = b.Wages.Add(b.Pensions).Add(b.RetirementDistributions).Add(b.TaxableSocialSecurity).
+		Add(b.CapitalGains).Add(b.OtherIncome).Sub(b.IRADeduction)
+	b.MAGI = b.AGI.Add(b.TaxExemptInterest)
+	return b
+}
+
 // CalculateIRMAARiskStatus determines the IRMAA risk status for a given year
 // based on MAGI and filing status
 func CalculateIRMAARiskStatus(
@@ -142,7 +203,8 @@ func getTierName(tier int) string {
 	}
 }
 
-// AnalyzeIRMAARisk performs a comprehensive IRMAA risk analysis across all projection years
+// AnalyzeIRMAARisk performs a comprehensive IRMAA risk analysis across all projection years,
+// judging each year's MAGI by the surcharges it sets two years later
 func AnalyzeIRMAARisk(
 	projection []domain.AnnualCashFlow,
 	isMarriedFilingJointly bool,
@@ -167,11 +229,17 @@ func AnalyzeIRMAARisk(
 		}
 	}
 
-	for _, acf := range projection {
-		// Skip years where participant is not Medicare-eligible
-		if !acf.IsMedicareEligible {
+	for i, acf := range projection {
+		// A year's MAGI sets the surcharges two years later; skip it when no one is on
+		// Medicare then (the last two years are judged by their own eligibility)
+		medicare := acf.IsMedicareEligible
+		if i+irmaaLookbackYears < len(projection) {
+			medicare = projection[i+irmaaLookbackYears].IsMedicareEligible
+		}
+		if !medicare {
 			continue
 		}
+		surchargeYear := acf.Year + irmaaLookbackYears
 
 		magi := acf.MAGI
 		if magi.IsZero() {
@@ -198,19 +266,22 @@ func AnalyzeIRMAARisk(
 			if isMarriedFilingJointly {
 				personsCount = decimal.NewFromInt(2)
 			}
-			annualCost := monthlySurcharge.Mul(decimal.NewFromInt(12)).Mul(personsCount)
+			partDSurcharge := mc.calculatePartDIRMAASurcharge(magi, isMarriedFilingJointly)
+			annualCost := monthlySurcharge.Add(partDSurcharge).Mul(decimal.NewFromInt(12)).Mul(personsCount)
 			analysis.TotalIRMAACost = analysis.TotalIRMAACost.Add(annualCost)
 
 			// Add to high risk years
 			analysis.HighRiskYears = append(analysis.HighRiskYears, domain.IRMAAYearRisk{
-				Year:                acf.Year,
-				MAGI:                magi,
-				Threshold:           firstThreshold,
-				DistanceToThreshold: distanceToNext.Neg(), // Negative because we're over
-				RiskStatus:          riskStatus,
-				TierLevel:           tierLevel,
-				MonthlySurcharge:    monthlySurcharge,
-				AnnualCost:          annualCost,
+				Year:                  acf.Year,
+				SurchargeYear:         surchargeYear,
+				MAGI:                  magi,
+				Threshold:             firstThreshold,
+				DistanceToThreshold:   distanceToNext.Neg(), // Negative because we're over
+				RiskStatus:            riskStatus,
+				TierLevel:             tierLevel,
+				MonthlySurcharge:      monthlySurcharge,
+				MonthlySurchargePartD: partDSurcharge,
+				AnnualCost:            annualCost,
 			})
 		} else if riskStatus == domain.IRMAARiskWarning {
 			analysis.YearsWithWarnings = append(analysis.YearsWithWarnings, acf.Year)
@@ -218,6 +289,7 @@ func AnalyzeIRMAARisk(
 			// Add to high risk years
 			analysis.HighRiskYears = append(analysis.HighRiskYears, domain.IRMAAYearRisk{
 				Year:                acf.Year,
+				SurchargeYear:       surchargeYear,
 				MAGI:                magi,
 				Threshold:           firstThreshold,
 				DistanceToThreshold: distanceToNext,
@@ -86,9 +86,9 @@ func createHighIncomeProjection() []domain.AnnualCashFlow {
 			Year:               year,
 			Date:               baseDate.AddDate(i, 0, 0),
 			IsMedicareEligible: i >= 2, // Medicare eligible starting year 3
-			Pensions:           map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(90000)},
-			TSPWithdrawals:     map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(110000)}, // Higher withdrawal to ensure breach
-			SSBenefits:         map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(50000)},
+			Pensions:           domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(90000)}),
+			TSPWithdrawals:     domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(110000)}), // Higher withdrawal to ensure breach
+			SSBenefits:         domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(50000)}),
 		}
 
 		// Calculate MAGI
@@ -110,9 +110,9 @@ func createModerateIncomeProjection() []domain.AnnualCashFlow {
 			Year:               year,
 			Date:               baseDate.AddDate(i, 0, 0),
 			IsMedicareEligible: i >= 2,
-			Pensions:           map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(80000)},
-			TSPWithdrawals:     map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(90000)},
-			SSBenefits:         map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(42000)},
+			Pensions:           domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(80000)}),
+			TSPWithdrawals:     domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(90000)}),
+			SSBenefits:         domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(42000)}),
 		}
 
 		// Calculate MAGI - designed to be within $10K of 206K married threshold to trigger warnings
@@ -134,9 +134,9 @@ func createConservativeIncomeProjection() []domain.AnnualCashFlow {
 			Year:               year,
 			Date:               baseDate.AddDate(i, 0, 0),
 			IsMedicareEligible: i >= 2,
-			Pensions:           map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(50000)},
-			TSPWithdrawals:     map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(40000)},
-			SSBenefits:         map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(30000)},
+			Pensions:           domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(50000)}),
+			TSPWithdrawals:     domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(40000)}),
+			SSBenefits:         domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(30000)}),
 		}
 
 		// Calculate MAGI - will be around $140K (well below $206K threshold)
@@ -228,9 +228,9 @@ func TestIRMAAThresholdExamples(t *testing.T) {
 	for _, ex := range examples {
 		t.Run(ex.name, func(t *testing.T) {
 			acf := domain.AnnualCashFlow{
-				Pensions:       map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(ex.pension)},
-				TSPWithdrawals: map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(ex.tspWithdrawal)},
-				SSBenefits:     map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(ex.ssBenefit)},
+				Pensions:       domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(ex.pension)}),
+				TSPWithdrawals: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(ex.tspWithdrawal)}),
+				SSBenefits:     domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Retiree": decimal.NewFromInt(ex.ssBenefit)}),
 			}
 
 			magi := CalculateMAGI(&acf)
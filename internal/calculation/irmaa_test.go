@@ -6,6 +6,7 @@ import (
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCalculateMAGI(t *testing.T) {
@@ -19,9 +20,9 @@ func TestCalculateMAGI(t *testing.T) {
 			acf: &domain.AnnualCashFlow{
 				Year:           1,
 				Date:           time.Now(),
-				Pensions:       map[string]decimal.Decimal{"Alice": decimal.NewFromInt(50000)},
-				TSPWithdrawals: map[string]decimal.Decimal{"Alice": decimal.NewFromInt(20000)},
-				SSBenefits:     map[string]decimal.Decimal{"Alice": decimal.NewFromInt(30000)},
+				Pensions:       domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(50000)}),
+				TSPWithdrawals: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(20000)}),
+				SSBenefits:     domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(30000)}),
 			},
 			// MAGI = 50000 (pension) + 20000 (TSP) + 25500 (85% of SS) = 95500
 			expected: decimal.NewFromInt(95500),
@@ -31,18 +32,18 @@ func TestCalculateMAGI(t *testing.T) {
 			acf: &domain.AnnualCashFlow{
 				Year: 1,
 				Date: time.Now(),
-				Pensions: map[string]decimal.Decimal{
+				Pensions: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{
 					"Alice": decimal.NewFromInt(50000),
 					"Bob":   decimal.NewFromInt(45000),
-				},
-				TSPWithdrawals: map[string]decimal.Decimal{
+				}),
+				TSPWithdrawals: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{
 					"Alice": decimal.NewFromInt(20000),
 					"Bob":   decimal.NewFromInt(15000),
-				},
-				SSBenefits: map[string]decimal.Decimal{
+				}),
+				SSBenefits: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{
 					"Alice": decimal.NewFromInt(30000),
 					"Bob":   decimal.NewFromInt(28000),
-				},
+				}),
 			},
 			// MAGI = (50000+45000) + (20000+15000) + 0.85*(30000+28000) = 179300
 			expected: decimal.NewFromInt(179300),
@@ -52,8 +53,8 @@ func TestCalculateMAGI(t *testing.T) {
 			acf: &domain.AnnualCashFlow{
 				Year:       1,
 				Date:       time.Now(),
-				Salaries:   map[string]decimal.Decimal{"Alice": decimal.NewFromInt(100000)},
-				SSBenefits: map[string]decimal.Decimal{},
+				Salaries:   domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(100000)}),
+				SSBenefits: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{}),
 			},
 			// MAGI = 100000 (salary) = 100000
 			expected: decimal.NewFromInt(100000),
@@ -63,9 +64,9 @@ func TestCalculateMAGI(t *testing.T) {
 			acf: &domain.AnnualCashFlow{
 				Year:            1,
 				Date:            time.Now(),
-				Pensions:        map[string]decimal.Decimal{"Alice": decimal.NewFromInt(40000)},
-				FERSSupplements: map[string]decimal.Decimal{"Alice": decimal.NewFromInt(10000)},
-				TSPWithdrawals:  map[string]decimal.Decimal{"Alice": decimal.NewFromInt(15000)},
+				Pensions:        domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(40000)}),
+				FERSSupplements: domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(10000)}),
+				TSPWithdrawals:  domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Alice": decimal.NewFromInt(15000)}),
 			},
 			// MAGI = 40000 + 10000 + 15000 = 65000
 			expected: decimal.NewFromInt(65000),
@@ -299,3 +300,66 @@ func TestAnalyzeIRMAARisk_NoBreaches(t *testing.T) {
 		t.Error("Expected recommendations even with no breaches")
 	}
 }
+
+func TestProjectionIRMAALookback(t *testing.T) {
+	config, scenario := conversionOptimizerConfig()
+	config.GlobalAssumptions.ProjectionYears = 4
+	config.Household.MAGIHistory = map[int]decimal.Decimal{2023: decimal.NewFromInt(300000)}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	base := ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	converted := ce.GenerateAnnualProjectionGeneric(config.Household, withConversions(scenario, "Gail", map[int]decimal.Decimal{2026: decimal.NewFromInt(300000)}), &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+
+	// The first year's surcharges come from the MAGI history; the second year has none
+	assert.Equal(t, "300000", base[0].IRMAAMAGI.String())
+	assert.True(t, base[0].IRMAASurcharge.IsPositive())
+	assert.True(t, base[0].HealthcareCosts.MedicarePartB.GreaterThan(base[1].HealthcareCosts.MedicarePartB))
+	assert.True(t, base[1].IRMAASurcharge.IsZero())
+
+	// A conversion raises the tier its year's MAGI sets, but the surcharge is paid two years later
+	assert.NotEqual(t, "None", converted[1].IRMAALevel)
+	assert.Equal(t, base[1].IRMAASurcharge.String(), converted[1].IRMAASurcharge.String())
+	assert.Equal(t, base[1].HealthcareCosts.Total.StringFixed(2), converted[1].HealthcareCosts.Total.StringFixed(2))
+	assert.Equal(t, converted[1].MAGI.String(), converted[3].IRMAAMAGI.String())
+	assert.True(t, converted[3].IRMAASurcharge.GreaterThan(base[3].IRMAASurcharge))
+	assert.True(t, converted[3].HealthcareCosts.MedicarePartB.GreaterThan(base[3].HealthcareCosts.MedicarePartB))
+	assert.True(t, converted[3].HealthcareCosts.MedicarePartD.GreaterThan(base[3].HealthcareCosts.MedicarePartD))
+}
+
+func TestProjectionMAGIBreakdown(t *testing.T) {
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Lee", BirthDate: time.Date(1958, 3, 10, 0, 0, 0, 0, time.UTC),
+			SSBenefitFRA: decimal.NewFromInt(2500), SSBenefit62: decimal.NewFromInt(1800), SSBenefit70: decimal.NewFromInt(3100),
+			ExternalPension: &domain.ExternalPension{MonthlyBenefit: decimal.NewFromInt(2500), StartAge: 62},
+		}},
+	}
+	retire := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Lee": {ParticipantName: "Lee", RetirementDate: &retire, SSStartAge: 67},
+	}}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 1}
+	project := func() domain.AnnualCashFlow {
+		ce := NewCalculationEngine()
+		ce.ProjectionCache = nil
+		return ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)[0]
+	}
+
+	// MAGI uses the taxable share of Social Security the tax calculation finds, not an estimate
+	cf := project()
+	b := cf.MAGIBreakdown
+	assert.True(t, b.Pensions.IsPositive())
+	assert.True(t, b.TaxableSocialSecurity.LessThan(cf.GetTotalSSBenefit().Mul(decimal.NewFromFloat(0.85))))
+	assert.Equal(t, b.Pensions.Add(b.TaxableSocialSecurity).String(), b.AGI.String())
+	assert.Equal(t, b.AGI.String(), cf.MAGI.String())
+	assert.Equal(t, cf.MAGI.Add(b.UntaxedSocialSecurity).String(), cf.ACAMAGI.String())
+
+	// Tax-exempt interest is added back, and makes more of Social Security taxable
+	household.TaxExemptInterest = decimal.NewFromInt(20000)
+	withInterest := project()
+	assert.Equal(t, "20000", withInterest.MAGIBreakdown.TaxExemptInterest.String())
+	assert.True(t, withInterest.MAGIBreakdown.TaxableSocialSecurity.GreaterThan(b.TaxableSocialSecurity))
+	assert.True(t, withInterest.MAGI.Sub(cf.MAGI).GreaterThan(decimal.NewFromInt(20000)))
+	assert.Equal(t, withInterest.MAGIBreakdown.AGI.Add(withInterest.MAGIBreakdown.TaxExemptInterest).String(), withInterest.MAGI.String())
+}
@@ -27,17 +27,15 @@ func NewLifecycleFundLoader(dataPath string) *LifecycleFundLoader {
 	}
 }
 
-// LoadAllLifecycleFunds loads all available lifecycle fund data
+// LoadAllLifecycleFunds loads all available lifecycle fund data. Funds without an
+// allocation file are skipped; they fall back to the built-in glide path.
 func (lfl *LifecycleFundLoader) LoadAllLifecycleFunds() error {
-	fundFiles := []string{
-		"l2030_allocation.csv",
-		"l2035_allocation.csv",
-		"l2040_allocation.csv",
-		"lincome_allocation.csv",
-	}
-
-	for _, filename := range fundFiles {
-		fundName := strings.TrimSuffix(filename, "_allocation.csv")
+	for _, name := range LifecycleFundNames {
+		fundName, _ := lifecycleFundKey(name)
+		filename := fundName + "_allocation.csv"
+		if _, err := os.Stat(filepath.Join(lfl.DataPath, "tsp-returns", filename)); os.IsNotExist(err) {
+			continue
+		}
 		if err := lfl.loadLifecycleFund(fundName, filename); err != nil {
 			return fmt.Errorf("failed to load %s: %w", filename, err)
 		}
@@ -159,37 +157,58 @@ func (lfl *LifecycleFundLoader) GetAllocationAtDate(fundName string, targetDate
 	}
 
 	// Find the closest date in the allocation data
-	var closestDate string
+	var closest *domain.TSPAllocationDataPoint
 	var minDiff time.Duration
 
-	for dateStr := range fund.AllocationData {
-		date, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			continue
-		}
-
-		diff := targetDate.Sub(date)
-		if diff < 0 {
-			diff = -diff
-		}
-
-		if closestDate == "" || diff < minDiff {
-			closestDate = dateStr
-			minDiff = diff
+	for yearKey := range fund.AllocationData {
+		points := fund.AllocationData[yearKey]
+		for i := range points {
+			date, err := time.Parse("2006-01-02", points[i].Date)
+			if err != nil {
+				continue
+			}
+
+			diff := targetDate.Sub(date)
+			if diff < 0 {
+				diff = -diff
+			}
+
+			if closest == nil || diff < minDiff {
+				closest = &points[i]
+				minDiff = diff
+			}
 		}
 	}
 
-	if closestDate == "" {
+	if closest == nil {
 		return nil, fmt.Errorf("no allocation data found for fund %s", fundName)
 	}
 
-	// Return the allocation for the closest date
-	allocationData := fund.AllocationData[closestDate]
-	if len(allocationData) == 0 {
-		return nil, fmt.Errorf("no allocation data found for date %s", closestDate)
-	}
+	return &closest.Allocation, nil
+}
 
-	return &allocationData[0].Allocation, nil
+// AllocationForYear returns a fund's first published allocation in a calendar year.
+// ok is false when the fund was not loaded or has no data for that year.
+func (lfl *LifecycleFundLoader) AllocationForYear(fundName string, year int) (alloc domain.TSPAllocation, ok bool) {
+	key, valid := lifecycleFundKey(fundName)
+	if !valid {
+		return domain.TSPAllocation{}, false
+	}
+	fund, exists := lfl.Funds[key]
+	if !exists {
+		return domain.TSPAllocation{}, false
+	}
+	points := fund.AllocationData[strconv.Itoa(year)]
+	if len(points) == 0 {
+		return domain.TSPAllocation{}, false
+	}
+	first := points[0]
+	for _, p := range points[1:] {
+		if p.Date < first.Date {
+			first = p
+		}
+	}
+	return first.Allocation, true
 }
 
 // parseQuarterlyDate parses dates like "July 2005", "October 2005"
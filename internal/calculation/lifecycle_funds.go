@@ -0,0 +1,223 @@
+package calculation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// TSP return models for GlobalAssumptions.TSPReturnModel
+const (
+	TSPReturnModelFlat = "flat"
+	TSPReturnModelFund = "fund"
+)
+
+// LifecycleFundNames lists the TSP Lifecycle funds the built-in glide path supports
+var LifecycleFundNames = []string{
+	domain.LifecycleIncomeFund,
+	"L2030", "L2035", "L2040", "L2045", "L2050", "L2055", "L2060", "L2065", "L2070",
+}
+
+// lifecycleGlidePoint anchors the glide path at a number of years before the target date
+type lifecycleGlidePoint struct {
+	yearsToTarget int
+	allocation    domain.TSPAllocation
+}
+
+// lifecycleGlidePath approximates the published L fund allocations. Allocations
+// between anchors are interpolated linearly; at the target date a fund holds the
+// L Income allocation and funds 40 or more years out are almost entirely equities.
+var lifecycleGlidePath = []lifecycleGlidePoint{
+	{0, tspAllocation("0.15", "0.035", "0.09", "0.06", "0.665")},
+	{5, tspAllocation("0.305", "0.08", "0.20", "0.065", "0.35")},
+	{15, tspAllocation("0.38", "0.11", "0.25", "0.05", "0.21")},
+	{25, tspAllocation("0.44", "0.13", "0.30", "0.03", "0.10")},
+	{40, tspAllocation("0.50", "0.15", "0.34", "0.005", "0.005")},
+}
+
+func tspAllocation(c, s, i, f, g string) domain.TSPAllocation {
+	return domain.TSPAllocation{
+		CFund: decimal.RequireFromString(c),
+		SFund: decimal.RequireFromString(s),
+		IFund: decimal.RequireFromString(i),
+		FFund: decimal.RequireFromString(f),
+		GFund: decimal.RequireFromString(g),
+	}
+}
+
+// LifecycleAllocation returns the glide path allocation of an L fund during calendar year
+func LifecycleAllocation(fundName string, year int) (domain.TSPAllocation, error) {
+	fund := domain.TSPLifecycleFund{FundName: fundName}
+	target, ok := fund.TargetYear()
+	if !ok {
+		return domain.TSPAllocation{}, fmt.Errorf("unknown lifecycle fund: %s", fundName)
+	}
+	if target == 0 {
+		return lifecycleGlidePath[0].allocation, nil
+	}
+	yearsToTarget := target - year
+	if yearsToTarget <= 0 {
+		return lifecycleGlidePath[0].allocation, nil
+	}
+	for i := 1; i < len(lifecycleGlidePath); i++ {
+		lo, hi := lifecycleGlidePath[i-1], lifecycleGlidePath[i]
+		if yearsToTarget > hi.yearsToTarget {
+			continue
+		}
+		w := decimal.NewFromInt(int64(yearsToTarget - lo.yearsToTarget)).Div(decimal.NewFromInt(int64(hi.yearsToTarget - lo.yearsToTarget)))
+		return blendAllocations(lo.allocation, hi.allocation, w), nil
+	}
+	return lifecycleGlidePath[len(lifecycleGlidePath)-1].allocation, nil
+}
+
+// blendAllocations moves each fund share the fraction w of the way from a to b
+func blendAllocations(a, b domain.TSPAllocation, w decimal.Decimal) domain.TSPAllocation {
+	mix := func(x, y decimal.Decimal) decimal.Decimal { return x.Add(y.Sub(x).Mul(w)) }
+	return domain.TSPAllocation{
+		CFund: mix(a.CFund, b.CFund),
+		SFund: mix(a.SFund, b.SFund),
+		IFund: mix(a.IFund, b.IFund),
+		FFund: mix(a.FFund, b.FFund),
+		GFund: mix(a.GFund, b.GFund),
+	}
+}
+
+// scheduledAllocation returns a scenario allocation schedule's allocation for a calendar
+// year. Age steps apply in the year the participant reaches the age and retirement steps
+// in the year of retirement or separation; ok is false when no step applies, such as a
+// schedule anchored only at retirement for a participant who never retires.
+func scheduledAllocation(schedule []domain.AllocationStep, birthYear int, retirementDate *time.Time, year int) (allocation domain.TSPAllocation, ok bool) {
+	type anchor struct {
+		year       int
+		allocation domain.TSPAllocation
+	}
+	var anchors []anchor
+	for _, step := range schedule {
+		if step.AtRetirement {
+			if retirementDate != nil {
+				anchors = append(anchors, anchor{retirementDate.Year(), step.Allocation})
+			}
+			continue
+		}
+		anchors = append(anchors, anchor{birthYear + step.Age, step.Allocation})
+	}
+	if len(anchors) == 0 {
+		return domain.TSPAllocation{}, false
+	}
+	slices.SortStableFunc(anchors, func(a, b anchor) int { return a.year - b.year })
+
+	if year <= anchors[0].year {
+		return anchors[0].allocation, true
+	}
+	for i := 1; i < len(anchors); i++ {
+		lo, hi := anchors[i-1], anchors[i]
+		if year > hi.year {
+			continue
+		}
+		w := decimal.NewFromInt(int64(year - lo.year)).Div(decimal.NewFromInt(int64(hi.year - lo.year)))
+		return blendAllocations(lo.allocation, hi.allocation, w), true
+	}
+	return anchors[len(anchors)-1].allocation, true
+}
+
+// IsLifecycleFund reports whether fundName names an L fund
+func IsLifecycleFund(fundName string) bool {
+	_, ok := (&domain.TSPLifecycleFund{FundName: fundName}).TargetYear()
+	return ok
+}
+
+// allocationWeights returns the allocation keyed by single-letter fund name
+func allocationWeights(a domain.TSPAllocation) map[string]decimal.Decimal {
+	return map[string]decimal.Decimal{"C": a.CFund, "S": a.SFund, "I": a.IFund, "F": a.FFund, "G": a.GFund}
+}
+
+// weightedReturn combines per-fund returns keyed by single-letter fund name
+func weightedReturn(a domain.TSPAllocation, returns map[string]decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	for fund, weight := range allocationWeights(a) {
+		total = total.Add(returns[fund].Mul(weight))
+	}
+	return total
+}
+
+// lifecycleAllocation returns an L fund's allocation for a calendar year, preferring
+// published allocations loaded from the data directory over the built-in glide path
+func (ce *CalculationEngine) lifecycleAllocation(fundName string, year int) (domain.TSPAllocation, error) {
+	if ce.LifecycleFundLoader != nil {
+		if alloc, ok := ce.LifecycleFundLoader.AllocationForYear(fundName, year); ok {
+			return alloc, nil
+		}
+	}
+	return LifecycleAllocation(fundName, year)
+}
+
+// expectedFundReturns returns the expected annual return of each TSP fund for the
+// fund-level return model: the configured fund returns when set, otherwise historical
+// means when data is loaded, otherwise the configured statistical models
+func (ce *CalculationEngine) expectedFundReturns(assumptions *domain.GlobalAssumptions) map[string]decimal.Decimal {
+	if r := assumptions.TSPFundReturns; r != nil {
+		return map[string]decimal.Decimal{"C": r.CFund, "S": r.SFund, "I": r.IFund, "F": r.FFund, "G": r.GFund}
+	}
+	models := assumptions.TSPStatisticalModels
+	returns := map[string]decimal.Decimal{
+		"C": models.CFund.Mean,
+		"S": models.SFund.Mean,
+		"I": models.IFund.Mean,
+		"F": models.FFund.Mean,
+		"G": models.GFund.Mean,
+	}
+	if hdm := ce.HistoricalData; hdm != nil && hdm.IsLoaded && hdm.TSPFunds != nil {
+		for fund, dataset := range map[string]*HistoricalDataSet{
+			"C": hdm.TSPFunds.CFund,
+			"S": hdm.TSPFunds.SFund,
+			"I": hdm.TSPFunds.IFund,
+			"F": hdm.TSPFunds.FFund,
+			"G": hdm.TSPFunds.GFund,
+		} {
+			if dataset != nil && dataset.Statistics.Count > 0 {
+				returns[fund] = dataset.Statistics.Mean
+			}
+		}
+	}
+	return returns
+}
+
+// participantFundReturn returns a participant's expected TSP return for a calendar
+// year under the fund-level model. The scenario's allocation schedule takes precedence
+// over a Lifecycle fund, which takes precedence over an explicit allocation, which
+// takes precedence over the Monte Carlo default. ok is false when none of them
+// allocates anything, leaving the flat returns in effect.
+func (ce *CalculationEngine) participantFundReturn(p *domain.Participant, schedule []domain.AllocationStep, retirementDate *time.Time, year int, assumptions *domain.GlobalAssumptions, fundReturns map[string]decimal.Decimal) (rate decimal.Decimal, ok bool) {
+	allocation := assumptions.MonteCarloSettings.DefaultTSPAllocation
+	if p.TSPAllocation != nil {
+		allocation = *p.TSPAllocation
+	}
+	if p.TSPLifecycleFund != nil {
+		if alloc, err := ce.lifecycleAllocation(p.TSPLifecycleFund.FundName, year); err == nil {
+			allocation = alloc
+		}
+	}
+	if alloc, scheduled := scheduledAllocation(schedule, p.BirthDate.Year(), retirementDate, year); scheduled {
+		allocation = alloc
+	}
+	if !allocation.Total().IsPositive() {
+		return decimal.Zero, false
+	}
+	return weightedReturn(allocation, fundReturns), true
+}
+
+// lifecycleFundKey normalizes an L fund name to the loader's file key ("l2040", "lincome")
+func lifecycleFundKey(fundName string) (string, bool) {
+	target, ok := (&domain.TSPLifecycleFund{FundName: fundName}).TargetYear()
+	if !ok {
+		return "", false
+	}
+	if target == 0 {
+		return "lincome", true
+	}
+	return "l" + strconv.Itoa(target), true
+}
@@ -0,0 +1,189 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleAllocationGlidePath(t *testing.T) {
+	income, err := LifecycleAllocation("L Income", 2030)
+	require.NoError(t, err)
+	assert.True(t, income.GFund.Equal(decimal.RequireFromString("0.665")))
+
+	// A fund at or past its target date holds the L Income allocation
+	past, err := LifecycleAllocation("L2030", 2035)
+	require.NoError(t, err)
+	assert.Equal(t, income, past)
+
+	// Equity share falls every year as the target date approaches
+	prev := decimal.NewFromInt(2)
+	for year := 2020; year <= 2050; year++ {
+		alloc, err := LifecycleAllocation("L2050", year)
+		require.NoError(t, err)
+		assert.True(t, alloc.Total().Equal(decimal.NewFromInt(1)), "allocation for %d sums to %s", year, alloc.Total())
+		equity := alloc.CFund.Add(alloc.SFund).Add(alloc.IFund)
+		assert.True(t, equity.LessThanOrEqual(prev), "equity rose in %d", year)
+		prev = equity
+	}
+
+	mid, err := LifecycleAllocation("L2040", 2030)
+	require.NoError(t, err)
+	assert.True(t, mid.GFund.Equal(decimal.RequireFromString("0.28")), "10 years out interpolates between anchors, got %s", mid.GFund)
+
+	_, err = LifecycleAllocation("L2032", 2030)
+	assert.Error(t, err)
+}
+
+func TestIsLifecycleFund(t *testing.T) {
+	for _, name := range LifecycleFundNames {
+		assert.True(t, IsLifecycleFund(name), name)
+	}
+	assert.True(t, IsLifecycleFund("l income"))
+	assert.False(t, IsLifecycleFund("C"))
+	assert.False(t, IsLifecycleFund("L2075"))
+}
+
+func TestMonteCarloLifecycleFundReturn(t *testing.T) {
+	mcs := &MonteCarloSimulator{}
+	market := MarketData{TSPReturns: map[string]decimal.Decimal{
+		"C": decimal.NewFromFloat(0.10),
+		"S": decimal.NewFromFloat(0.10),
+		"I": decimal.NewFromFloat(0.10),
+		"F": decimal.NewFromFloat(0.02),
+		"G": decimal.NewFromFloat(0.02),
+	}}
+	allocation := map[string]decimal.Decimal{"L2050": decimal.NewFromInt(1)}
+
+	early := mcs.calculatePortfolioReturn(allocation, market, 2020)
+	late := mcs.calculatePortfolioReturn(allocation, market, 2050)
+	assert.True(t, early.GreaterThan(late), "early %s should exceed late %s", early, late)
+
+	income, _ := LifecycleAllocation(domain.LifecycleIncomeFund, 2050)
+	assert.True(t, late.Equal(weightedReturn(income, market.TSPReturns)))
+}
+
+func TestParticipantFundReturn(t *testing.T) {
+	ce := NewCalculationEngine()
+	assumptions := &domain.GlobalAssumptions{}
+	returns := map[string]decimal.Decimal{
+		"C": decimal.NewFromFloat(0.10),
+		"S": decimal.NewFromFloat(0.10),
+		"I": decimal.NewFromFloat(0.10),
+		"F": decimal.NewFromFloat(0.04),
+		"G": decimal.NewFromFloat(0.04),
+	}
+
+	// Without any allocation the flat returns stay in effect
+	_, ok := ce.participantFundReturn(&domain.Participant{}, nil, nil, 2030, assumptions, returns)
+	assert.False(t, ok)
+
+	p := &domain.Participant{TSPAllocation: &domain.TSPAllocation{GFund: decimal.NewFromInt(1)}}
+	rate, ok := ce.participantFundReturn(p, nil, nil, 2030, assumptions, returns)
+	assert.True(t, ok)
+	assert.True(t, rate.Equal(decimal.NewFromFloat(0.04)))
+
+	p.TSPLifecycleFund = &domain.TSPLifecycleFund{FundName: "L2070"}
+	rate, _ = ce.participantFundReturn(p, nil, nil, 2030, assumptions, returns)
+	assert.True(t, rate.GreaterThan(decimal.NewFromFloat(0.09)))
+}
+
+func TestProjectionFundReturnModel(t *testing.T) {
+	balance := decimal.NewFromInt(100000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:                  "Pat",
+			BirthDate:             time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+			TSPBalanceTraditional: &balance,
+			TSPAllocation:         &domain.TSPAllocation{CFund: decimal.NewFromFloat(0.5), GFund: decimal.NewFromFloat(0.5)},
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         2,
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+		TSPReturnModel:          TSPReturnModelFund,
+		TSPFundReturns: &domain.TSPFundReturns{
+			CFund: decimal.NewFromFloat(0.10),
+			GFund: decimal.NewFromFloat(0.02),
+		},
+	}
+	ce := NewCalculationEngine()
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	assert.Equal(t, "106000.00", projection[0].TSPBalances.Get("Pat").StringFixed(2))
+
+	// Without an allocation the flat return applies
+	household.Participants[0].TSPAllocation = nil
+	projection = ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	assert.Equal(t, "105000.00", projection[0].TSPBalances.Get("Pat").StringFixed(2))
+}
+
+func TestScheduledAllocation(t *testing.T) {
+	stocks := domain.TSPAllocation{CFund: decimal.NewFromFloat(0.7), GFund: decimal.NewFromFloat(0.3)}
+	balanced := domain.TSPAllocation{CFund: decimal.NewFromFloat(0.5), GFund: decimal.NewFromFloat(0.5)}
+	schedule := []domain.AllocationStep{
+		{AtRetirement: true, Allocation: stocks},
+		{Age: 70, Allocation: balanced},
+	}
+	retirement := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	// 70/30 until the 2025 retirement, then stepping to 50/50 by 70 in 2030
+	alloc, ok := scheduledAllocation(schedule, 1960, &retirement, 2020)
+	assert.True(t, ok)
+	assert.True(t, alloc.CFund.Equal(decimal.NewFromFloat(0.7)))
+	alloc, _ = scheduledAllocation(schedule, 1960, &retirement, 2027)
+	assert.True(t, alloc.CFund.Equal(decimal.NewFromFloat(0.62)), alloc.CFund.String())
+	assert.True(t, alloc.GFund.Equal(decimal.NewFromFloat(0.38)), alloc.GFund.String())
+	alloc, _ = scheduledAllocation(schedule, 1960, &retirement, 2040)
+	assert.True(t, alloc.CFund.Equal(decimal.NewFromFloat(0.5)))
+
+	// Without a retirement date only the age step applies
+	alloc, ok = scheduledAllocation(schedule, 1960, nil, 2020)
+	assert.True(t, ok)
+	assert.True(t, alloc.CFund.Equal(decimal.NewFromFloat(0.5)))
+
+	_, ok = scheduledAllocation(schedule[:1], 1960, nil, 2020)
+	assert.False(t, ok)
+}
+
+func TestProjectionAllocationSchedule(t *testing.T) {
+	balance := decimal.NewFromInt(100000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:                  "Pat",
+			BirthDate:             time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+			TSPBalanceTraditional: &balance,
+			TSPAllocation:         &domain.TSPAllocation{GFund: decimal.NewFromInt(1)},
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         1,
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+		TSPReturnModel:          TSPReturnModelFund,
+		TSPFundReturns: &domain.TSPFundReturns{
+			CFund: decimal.NewFromFloat(0.10),
+			GFund: decimal.NewFromFloat(0.02),
+		},
+	}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Pat": {ParticipantName: "Pat", SSStartAge: 67, TSPAllocationSchedule: []domain.AllocationStep{
+			{Age: 60, Allocation: domain.TSPAllocation{CFund: decimal.NewFromInt(1)}},
+		}},
+	}}
+	ce := NewCalculationEngine()
+
+	// The schedule overrides the participant's all-G allocation
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	assert.Equal(t, "110000.00", projection[0].TSPBalances.Get("Pat").StringFixed(2))
+
+	projection = ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	assert.Equal(t, "102000.00", projection[0].TSPBalances.Get("Pat").StringFixed(2))
+}
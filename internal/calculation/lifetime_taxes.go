@@ -0,0 +1,27 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// yearTaxes breaks down the taxes of a single projection year by type
+func yearTaxes(cf *domain.AnnualCashFlow) domain.TaxTotals {
+	t := domain.TaxTotals{
+		Federal: cf.FederalTax,
+		State:   cf.StateTax,
+		Local:   cf.LocalTax,
+		FICA:    cf.FICATax,
+		IRMAA:   annualIRMAACost(cf),
+	}
+	t.Total = t.Federal.Add(t.State).Add(t.Local).Add(t.FICA).Add(t.IRMAA)
+	return t
+}
+
+// SummarizeLifetimeTaxes returns the taxes paid across a projection, the running totals
+// of its final year
+func SummarizeLifetimeTaxes(projection []domain.AnnualCashFlow) domain.TaxTotals {
+	if len(projection) == 0 {
+		return domain.TaxTotals{}
+	}
+	return projection[len(projection)-1].CumulativeTaxes
+}
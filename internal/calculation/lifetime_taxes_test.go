@@ -0,0 +1,48 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYearTaxes(t *testing.T) {
+	index := domain.NewParticipantIndex([]string{"Alex", "Blair"})
+	cf := domain.NewAnnualCashFlowWithIndex(1, time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC), index)
+	cf.Ages.Set("Alex", 66)
+	cf.Ages.Set("Blair", 63)
+	cf.FederalTax = decimal.NewFromInt(12000)
+	cf.StateTax = decimal.NewFromInt(3000)
+	cf.LocalTax = decimal.NewFromInt(500)
+	cf.FICATax = decimal.NewFromInt(1500)
+	cf.IRMAASurcharge = decimal.NewFromInt(70)
+
+	taxes := yearTaxes(cf)
+	// Only Alex is on Medicare and pays the surcharge
+	assert.Equal(t, "840", taxes.IRMAA.String())
+	assert.Equal(t, "17840", taxes.Total.String())
+}
+
+func TestProjectionCumulativeTaxes(t *testing.T) {
+	cfg := projectionCacheConfig()
+	summary, err := NewCalculationEngine().RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[0])
+	require.NoError(t, err)
+
+	var running domain.TaxTotals
+	for i := range summary.Projection {
+		cf := &summary.Projection[i]
+		running = running.Add(yearTaxes(cf))
+		require.Equal(t, running, cf.CumulativeTaxes, cf.Date.Year())
+	}
+	assert.Equal(t, running, summary.LifetimeTaxes)
+	assert.True(t, summary.LifetimeTaxes.Federal.IsPositive())
+	assert.True(t, summary.LifetimeTaxes.Total.Equal(summary.LifetimeTaxes.Federal.
+		Add(summary.LifetimeTaxes.State).Add(summary.LifetimeTaxes.Local).
+		Add(summary.LifetimeTaxes.FICA).Add(summary.LifetimeTaxes.IRMAA)))
+	assert.Equal(t, domain.TaxTotals{}, SummarizeLifetimeTaxes(nil))
+}
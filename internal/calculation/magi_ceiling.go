@@ -0,0 +1,95 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// magiCeilingMaxPasses bounds how many times a scenario with a MAGI ceiling is re-projected
+const magiCeilingMaxPasses = 10
+
+// magiCeilingTolerance is how far, in dollars, a year's MAGI may exceed its ceiling
+var magiCeilingTolerance = decimal.NewFromInt(1)
+
+// magiCeilingAmount returns the most MAGI the scenario allows in a year, or nil when it
+// sets no ceiling. A ceiling below an IRMAA tier stops a dollar short of the tier's
+// threshold for the household's filing status.
+func magiCeilingAmount(scenario *domain.GenericScenario, household *domain.Household) *decimal.Decimal {
+	if scenario == nil || scenario.MAGICeiling == nil {
+		return nil
+	}
+	mc := scenario.MAGICeiling
+	var ceiling decimal.Decimal
+	switch {
+	case mc.Amount != nil:
+		ceiling = *mc.Amount
+	case mc.BelowIRMAATier > 0:
+		thresholds := NewMedicareCalculator().IRMAAThresholds
+		if len(thresholds) == 0 {
+			return nil
+		}
+		threshold := thresholds[min(mc.BelowIRMAATier, len(thresholds))-1]
+		ceiling = threshold.IncomeThresholdSingle
+		if household.FilingStatus == "married_filing_jointly" {
+			ceiling = threshold.IncomeThresholdJoint
+		}
+		ceiling = ceiling.Sub(decimalOne)
+	default:
+		return nil
+	}
+	ceiling = decimal.Max(ceiling.Sub(mc.Buffer), decimalZero)
+	return &ceiling
+}
+
+// magiHeadroom is how much more MAGI a year may take on before reaching the ceiling,
+// counting the income already in the cash flow and the reserve held back for the year
+func (ce *CalculationEngine) magiHeadroom(cf *domain.AnnualCashFlow, ceiling decimal.Decimal) decimal.Decimal {
+	headroom := ceiling.Sub(CalculateMAGI(cf))
+	if ce != nil {
+		headroom = headroom.Sub(ce.magiReserve[cf.Date.Year()])
+	}
+	return decimal.Max(headroom, decimalZero)
+}
+
+// projectWithinMAGICeiling projects a scenario, keeping each year's MAGI at or below the
+// scenario's ceiling. Conversions and sequenced withdrawals are clipped as they are made
+// against the MAGI the year has so far, but income counted later in the year, such as a
+// spouse's pension or household rental income, can still carry MAGI over. Such a year is
+// re-projected holding back its overshoot as a reserve, until every year is within the
+// ceiling or clipping no longer lowers its MAGI, as when a required minimum distribution
+// alone exceeds it.
+func (ce *CalculationEngine) projectWithinMAGICeiling(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions, federalRules domain.FederalRules) []domain.AnnualCashFlow {
+	if ce == nil || ce.magiReserve != nil || magiCeilingAmount(scenario, household) == nil {
+		return ce.generateAnnualProjection(household, scenario, assumptions, federalRules)
+	}
+
+	limited := *ce
+	limited.magiReserve = make(map[int]decimal.Decimal)
+	previous := make(map[int]decimal.Decimal)
+	settled := make(map[int]bool)
+
+	projection := limited.generateAnnualProjection(household, scenario, assumptions, federalRules)
+	for pass := 0; pass < magiCeilingMaxPasses; pass++ {
+		changed := false
+		for i := range projection {
+			cf := &projection[i]
+			year := cf.Date.Year()
+			over := cf.MAGI.Sub(cf.MAGICeiling)
+			if settled[year] || over.LessThanOrEqual(magiCeilingTolerance) {
+				continue
+			}
+			if prev, ok := previous[year]; ok && prev.Sub(cf.MAGI).LessThan(magiCeilingTolerance) {
+				settled[year] = true
+				continue
+			}
+			previous[year] = cf.MAGI
+			limited.magiReserve[year] = limited.magiReserve[year].Add(over)
+			changed = true
+		}
+		if !changed {
+			break
+		}
+		projection = limited.generateAnnualProjection(household, scenario, assumptions, federalRules)
+	}
+	return projection
+}
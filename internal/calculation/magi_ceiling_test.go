@@ -0,0 +1,62 @@
+package calculation
+
+import (
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMAGICeilingClipsRothConversions(t *testing.T) {
+	config, scenario := conversionOptimizerConfig()
+	ce := NewCalculationEngine()
+	converted := withConversions(scenario, "Gail", map[int]decimal.Decimal{2027: decimal.NewFromInt(200000), 2028: decimal.NewFromInt(20000)})
+	converted.MAGICeiling = &domain.MAGICeiling{BelowIRMAATier: 1, Buffer: decimal.NewFromInt(5000)}
+	projection := ce.GenerateAnnualProjectionGeneric(config.Household, converted, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	require.Len(t, projection, 10)
+
+	// The single first-tier threshold is $103,000; the conversion stops $5,001 short of it
+	ceiling := decimal.NewFromInt(97999)
+	for _, cf := range projection {
+		assert.True(t, cf.MAGICeiling.Equal(ceiling), "%d: %s", cf.Date.Year(), cf.MAGICeiling)
+		assert.True(t, cf.MAGI.LessThanOrEqual(ceiling.Add(magiCeilingTolerance)), "%d: MAGI %s", cf.Date.Year(), cf.MAGI)
+	}
+	assert.True(t, projection[2].RothConversions.IsPositive())
+	assert.True(t, projection[2].RothConversions.LessThan(decimal.NewFromInt(200000)))
+	assert.Equal(t, "None", projection[2].IRMAALevel)
+	// A conversion with room to spare is made in full
+	assert.Equal(t, "20000", projection[3].RothConversions.String())
+}
+
+func TestMAGICeilingShiftsSequencedWithdrawalsToRoth(t *testing.T) {
+	config, scenario := conversionOptimizerConfig()
+	roth := decimal.NewFromInt(200000)
+	config.Household.Participants[0].TSPBalanceRoth = &roth
+	monthly := decimal.NewFromInt(6000)
+	ps := scenario.ParticipantScenarios["Gail"]
+	ps.TSPWithdrawalStrategy, ps.TSPWithdrawalTargetMonthly = "need_based", &monthly
+	scenario.ParticipantScenarios["Gail"] = ps
+	scenario.WithdrawalSequencing = &domain.WithdrawalSequencingConfig{Strategy: "standard"}
+
+	ce := NewCalculationEngine()
+	base := ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+	limit := decimal.NewFromInt(30000)
+	scenario.MAGICeiling = &domain.MAGICeiling{Amount: &limit}
+	limited := ce.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+
+	// Taxable shares, half of them gains, run out in the second year and the rest of the
+	// need comes from the traditional TSP; under the ceiling it comes from the Roth instead
+	assert.True(t, base[1].MAGI.GreaterThan(limit))
+	// Required minimum distributions from 2032 cannot be clipped
+	for _, cf := range limited[:7] {
+		assert.True(t, cf.MAGI.LessThanOrEqual(limit.Add(magiCeilingTolerance)), "%d: MAGI %s", cf.Date.Year(), cf.MAGI)
+	}
+	assert.True(t, limited[1].WithdrawalRoth.IsPositive())
+	assert.True(t, limited[1].WithdrawalTraditional.LessThan(base[1].WithdrawalTraditional))
+	assert.Equal(t, base[1].WithdrawalTaxable.Add(base[1].WithdrawalTraditional).Add(base[1].WithdrawalRoth).StringFixed(2),
+		limited[1].WithdrawalTaxable.Add(limited[1].WithdrawalTraditional).Add(limited[1].WithdrawalRoth).StringFixed(2))
+	// Roth withdrawals are tax free
+	assert.True(t, limited[1].FederalTax.LessThan(base[1].FederalTax))
+}
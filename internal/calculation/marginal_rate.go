@@ -0,0 +1,107 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// MarginalRateCurve sweeps additional ordinary income from zero to maxIncome in steps for
+// one calendar year and reports the year's federal, state and local taxes and the IRMAA
+// surcharges its MAGI sets two years later at each point. Social Security taxation,
+// bracket changes and IRMAA tiers all show up in the marginal rate; other years are ignored.
+func (ce *CalculationEngine) MarginalRateCurve(ctx context.Context, config *domain.Configuration, scenario *domain.GenericScenario, year int, maxIncome, step decimal.Decimal) (*domain.MarginalRateCurve, error) {
+	if !step.IsPositive() {
+		return nil, fmt.Errorf("step must be positive")
+	}
+	if maxIncome.IsNegative() {
+		return nil, fmt.Errorf("maximum additional income cannot be negative")
+	}
+	if err := CheckScenarioFeasibility(config.Household, scenario, &config.GlobalAssumptions); err != nil {
+		return nil, err
+	}
+
+	curve := &domain.MarginalRateCurve{Scenario: scenario.Name, Year: year, Step: step}
+	var base decimal.Decimal
+	for amount := decimalZero; amount.LessThanOrEqual(maxIncome); amount = amount.Add(step) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sweep := *ce
+		sweep.AdditionalOrdinaryIncome = map[int]decimal.Decimal{year: amount}
+		projection := sweep.GenerateAnnualProjectionGeneric(config.Household, scenario, &config.GlobalAssumptions, config.GlobalAssumptions.FederalRules)
+		cf := findProjectionYear(projection, year)
+		if cf == nil {
+			return nil, fmt.Errorf("year %d is outside the projection", year)
+		}
+
+		point := domain.MarginalRatePoint{
+			AdditionalIncome:      amount,
+			FederalTax:            cf.FederalTax,
+			StateLocalTax:         cf.StateTax.Add(cf.LocalTax),
+			TaxableSocialSecurity: cf.TaxableSocialSecurity,
+			MAGI:                  cf.MAGI,
+			IRMAATier:             cf.IRMAALevel,
+			IRMAACost:             irmaaCostOfMAGI(cf),
+		}
+		point.TotalCost = point.FederalTax.Add(point.StateLocalTax).Add(point.IRMAACost)
+
+		if n := len(curve.Points); n == 0 {
+			curve.FilingStatus = cf.FederalFilingStatus
+			base = point.TotalCost
+		} else {
+			point.MarginalRate = point.TotalCost.Sub(curve.Points[n-1].TotalCost).Div(step).Round(4)
+			point.AverageRate = point.TotalCost.Sub(base).Div(amount).Round(4)
+		}
+		curve.Points = append(curve.Points, point)
+	}
+	return curve, nil
+}
+
+// findProjectionYear returns the projection row for a calendar year, or nil
+func findProjectionYear(projection []domain.AnnualCashFlow, year int) *domain.AnnualCashFlow {
+	for i := range projection {
+		if projection[i].Date.Year() == year {
+			return &projection[i]
+		}
+	}
+	return nil
+}
+
+// annualIRMAACost converts the year's monthly per-person Part B and Part D IRMAA surcharges
+// to the household's annual cost, counting each living participant on Medicare
+func annualIRMAACost(cf *domain.AnnualCashFlow) decimal.Decimal {
+	surcharge := cf.IRMAASurcharge.Add(cf.IRMAASurchargePartD)
+	if !surcharge.IsPositive() {
+		return decimalZero
+	}
+	enrolled := 0
+	for _, name := range cf.GetLivingParticipants() {
+		if cf.Ages.Get(name) >= 65 {
+			enrolled++
+		}
+	}
+	return surcharge.Mul(decimalTwelve).Mul(decimal.NewFromInt(int64(enrolled)))
+}
+
+// irmaaCostOfMAGI converts the monthly per-person Part B and Part D IRMAA surcharges the
+// year's MAGI sets two years later to the household's annual cost, counting each living
+// participant on Medicare by then
+func irmaaCostOfMAGI(cf *domain.AnnualCashFlow) decimal.Decimal {
+	married := cf.FederalFilingStatus == "married_filing_jointly"
+	mc := NewMedicareCalculator()
+	surcharge := mc.calculateIRMAASurcharge(cf.MAGI, married).Add(mc.calculatePartDIRMAASurcharge(cf.MAGI, married))
+	if !surcharge.IsPositive() {
+		return decimalZero
+	}
+	enrolled := 0
+	for _, name := range cf.GetLivingParticipants() {
+		if cf.Ages.Get(name)+irmaaLookbackYears >= 65 {
+			enrolled++
+		}
+	}
+	return surcharge.Mul(decimalTwelve).Mul(decimal.NewFromInt(int64(enrolled)))
+}
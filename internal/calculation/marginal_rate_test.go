@@ -0,0 +1,64 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarginalRateCurve_SocialSecurityTaxation(t *testing.T) {
+	config := &domain.Configuration{
+		Household: &domain.Household{
+			FilingStatus: "single",
+			Participants: []domain.Participant{{
+				Name:                  "Pat",
+				BirthDate:             time.Date(1958, 3, 1, 0, 0, 0, 0, time.UTC),
+				HireDate:              timePtr(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)),
+				TSPBalanceTraditional: decimalPtr(decimal.Zero),
+				TSPBalanceRoth:        decimalPtr(decimal.Zero),
+				SSBenefitFRA:          decimal.NewFromInt(2000),
+				IsFederal:             true,
+				CurrentRetirement: &domain.CurrentRetirement{
+					RetirementDate:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+					AnnualAnnuity:    decimal.NewFromInt(20000),
+					SSMonthlyBenefit: decimal.NewFromInt(2000),
+				},
+			}},
+		},
+		GlobalAssumptions: domain.GlobalAssumptions{ProjectionYears: 3},
+		Scenarios: []domain.GenericScenario{{
+			Name: "retired",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Pat": {ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "4_percent_rule"},
+			},
+		}},
+	}
+
+	curve, err := NewCalculationEngine().MarginalRateCurve(context.Background(), config, &config.Scenarios[0], 2025, decimal.NewFromInt(40000), decimal.NewFromInt(1000))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, curve.Points, 41)
+	assert.Equal(t, "single", curve.FilingStatus)
+
+	// Provisional income starts at $32,000, inside the 50% band: each added dollar
+	// makes fifty cents of benefits taxable until 85% phases in
+	first, last := curve.Points[0], curve.Points[len(curve.Points)-1]
+	assert.True(t, first.TaxableSocialSecurity.IsPositive())
+	assert.True(t, last.TaxableSocialSecurity.Equal(decimal.NewFromInt(20400)), last.TaxableSocialSecurity.String())
+
+	// The torpedo pushes the marginal rate past the 12% bracket rate
+	maxRate := decimal.Zero
+	for _, p := range curve.Points[1:] {
+		assert.True(t, p.TotalCost.GreaterThanOrEqual(first.TotalCost))
+		maxRate = decimal.Max(maxRate, p.MarginalRate)
+	}
+	assert.True(t, maxRate.GreaterThan(decimal.NewFromFloat(0.12)), maxRate.String())
+
+	_, err = NewCalculationEngine().MarginalRateCurve(context.Background(), config, &config.Scenarios[0], 2040, decimal.NewFromInt(1000), decimal.NewFromInt(1000))
+	assert.Error(t, err)
+}
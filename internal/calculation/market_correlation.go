@@ -0,0 +1,160 @@
+package calculation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// minCorrelationYears is the fewest years of complete history a correlation estimate
+// is drawn from
+const minCorrelationYears = 10
+
+// defaultMarketCorrelation approximates the long-run correlation of annual C, S, I, F
+// and G fund returns and inflation, used when none is configured or can be estimated
+var defaultMarketCorrelation = newCorrelationMatrix([][]float64{
+	{1.00, 0.88, 0.80, 0.10, 0.00, -0.10},
+	{0.88, 1.00, 0.75, 0.05, -0.05, -0.05},
+	{0.80, 0.75, 1.00, 0.05, 0.00, -0.05},
+	{0.10, 0.05, 0.05, 1.00, 0.30, -0.20},
+	{0.00, -0.05, 0.00, 0.30, 1.00, 0.40},
+	{-0.10, -0.05, -0.05, -0.20, 0.40, 1.00},
+})
+
+// defaultCorrelatedNormals draws with the built-in correlation matrix
+var defaultCorrelatedNormals, _ = newCorrelatedNormals(defaultMarketCorrelation)
+
+// newCorrelationMatrix converts rows of float correlations to a correlation matrix
+func newCorrelationMatrix(rows [][]float64) domain.CorrelationMatrix {
+	m := make(domain.CorrelationMatrix, len(rows))
+	for i, row := range rows {
+		m[i] = make([]decimal.Decimal, len(row))
+		for j, v := range row {
+			m[i][j] = decimal.NewFromFloat(v)
+		}
+	}
+	return m
+}
+
+// correlatedNormals draws standard normal values, one per market factor, correlated as
+// the matrix its Cholesky factor came from
+type correlatedNormals struct {
+	factor [][]float64
+}
+
+// newCorrelatedNormals factors a correlation matrix for drawing
+func newCorrelatedNormals(m domain.CorrelationMatrix) (*correlatedNormals, error) {
+	factor, err := m.Cholesky()
+	if err != nil {
+		return nil, err
+	}
+	return &correlatedNormals{factor: factor}, nil
+}
+
+// draw returns one correlated standard normal value per market factor
+func (c *correlatedNormals) draw(rng *rand.Rand) []float64 {
+	independent := make([]float64, len(c.factor))
+	for i := range independent {
+		independent[i] = rng.NormFloat64()
+	}
+	correlated := make([]float64, len(c.factor))
+	for i, row := range c.factor {
+		for j := 0; j <= i; j++ {
+			correlated[i] += row[j] * independent[j]
+		}
+	}
+	return correlated
+}
+
+// marketCorrelation returns the correlated draws of the configured correlation matrix,
+// otherwise one estimated from loaded historical data, otherwise the built-in matrix
+func marketCorrelation(configured domain.CorrelationMatrix, hdm *HistoricalDataManager) *correlatedNormals {
+	if len(configured) > 0 {
+		if c, err := newCorrelatedNormals(configured); err == nil {
+			return c
+		}
+	}
+	if hdm != nil && hdm.IsLoaded {
+		if estimated, err := EstimateMarketCorrelation(hdm); err == nil {
+			if c, err := newCorrelatedNormals(estimated); err == nil {
+				return c
+			}
+		}
+	}
+	return defaultCorrelatedNormals
+}
+
+// EstimateMarketCorrelation estimates the correlation of the market factors from the
+// historical years in which every fund return and inflation is available
+func EstimateMarketCorrelation(hdm *HistoricalDataManager) (domain.CorrelationMatrix, error) {
+	if hdm == nil || !hdm.IsLoaded || hdm.TSPFunds.CFund == nil {
+		return nil, fmt.Errorf("historical data not loaded")
+	}
+
+	var samples [][]float64
+	for _, point := range hdm.TSPFunds.CFund.DataPoints {
+		sample, ok := marketFactorSample(hdm, point.Year)
+		if ok {
+			samples = append(samples, sample)
+		}
+	}
+	if len(samples) < minCorrelationYears {
+		return nil, fmt.Errorf("need %d years of complete history to estimate correlations, have %d", minCorrelationYears, len(samples))
+	}
+
+	n := len(domain.MarketFactors)
+	means := make([]float64, n)
+	for _, sample := range samples {
+		for i, v := range sample {
+			means[i] += v / float64(len(samples))
+		}
+	}
+	cov := make([][]float64, n)
+	for i := range cov {
+		cov[i] = make([]float64, n)
+		for j := range cov[i] {
+			for _, sample := range samples {
+				cov[i][j] += (sample[i] - means[i]) * (sample[j] - means[j])
+			}
+		}
+	}
+	corr := make([][]float64, n)
+	for i := range corr {
+		corr[i] = make([]float64, n)
+		for j := range corr[i] {
+			if i == j {
+				corr[i][j] = 1
+				continue
+			}
+			if denom := math.Sqrt(cov[i][i] * cov[j][j]); denom > 0 {
+				corr[i][j] = math.Round(cov[i][j]/denom*10000) / 10000
+			}
+		}
+	}
+
+	m := newCorrelationMatrix(corr)
+	if _, err := m.Cholesky(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// marketFactorSample returns a historical year's value of every market factor
+func marketFactorSample(hdm *HistoricalDataManager, year int) ([]float64, bool) {
+	sample := make([]float64, 0, len(domain.MarketFactors))
+	for _, fund := range domain.MarketFactors[:5] {
+		r, err := hdm.GetTSPReturn(fund, year)
+		if err != nil {
+			return nil, false
+		}
+		sample = append(sample, r.InexactFloat64())
+	}
+	inflation, err := hdm.GetInflationRate(year)
+	if err != nil {
+		return nil, false
+	}
+	return append(sample, inflation.InexactFloat64()), true
+}
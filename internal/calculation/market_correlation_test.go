@@ -0,0 +1,115 @@
+package calculation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleCorrelation returns the correlation of two series
+func sampleCorrelation(x, y []float64) float64 {
+	var mx, my float64
+	for i := range x {
+		mx += x[i] / float64(len(x))
+		my += y[i] / float64(len(y))
+	}
+	var sxy, sxx, syy float64
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+		syy += (y[i] - my) * (y[i] - my)
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+func TestCorrelatedNormalsFollowMatrix(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	var c, s, g, infl []float64
+	for i := 0; i < 20000; i++ {
+		z := defaultCorrelatedNormals.draw(rng)
+		c, s, g, infl = append(c, z[0]), append(s, z[1]), append(g, z[4]), append(infl, z[5])
+	}
+	assert.InDelta(t, 0.88, sampleCorrelation(c, s), 0.02)
+	assert.InDelta(t, 0.40, sampleCorrelation(g, infl), 0.03)
+}
+
+func TestEstimateMarketCorrelation(t *testing.T) {
+	// Four years of history are too few to estimate from, leaving the built-in matrix
+	shortPath := t.TempDir()
+	require.NoError(t, createTestDataFiles(shortPath))
+	short := NewHistoricalDataManager(shortPath)
+	require.NoError(t, short.LoadAllData())
+	_, err := EstimateMarketCorrelation(short)
+	assert.ErrorContains(t, err, "years of complete history")
+	assert.Same(t, defaultCorrelatedNormals, marketCorrelation(nil, short))
+
+	// Twelve years where the S fund tracks the C fund
+	hdm := loadSyntheticHistory(t)
+
+	estimated, err := EstimateMarketCorrelation(hdm)
+	require.NoError(t, err)
+	require.NoError(t, estimated.Validate())
+	assert.Greater(t, estimated[0][1].InexactFloat64(), 0.95, "S fund tracks the C fund")
+	assert.True(t, estimated[1][0].Equal(estimated[0][1]))
+	assert.NotSame(t, defaultCorrelatedNormals, marketCorrelation(nil, hdm))
+}
+
+func TestFERSMonteCarloEngine_StatisticalReturnsAreCorrelated(t *testing.T) {
+	config := createTestConfig()
+	config.GlobalAssumptions.MonteCarloSettings.CorrelationMatrix = newCorrelationMatrix([][]float64{
+		{1, 0.2, 0.95, 0, 0, 0},
+		{0.2, 1, 0.2, 0, 0, 0},
+		{0.95, 0.2, 1, 0, 0, 0},
+		{0, 0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 1, 0},
+		{0, 0, 0, 0, 0, 1},
+	})
+	engine := NewFERSMonteCarloEngine(config, nil)
+	engine.config.UseHistorical = false
+
+	rng := rand.New(rand.NewSource(17))
+	var c, i, f []float64
+	for n := 0; n < 5000; n++ {
+		condition := engine.generateMarketConditions(rng)
+		c = append(c, condition.TSPReturns["C"].InexactFloat64())
+		i = append(i, condition.TSPReturns["I"].InexactFloat64())
+		f = append(f, condition.TSPReturns["F"].InexactFloat64())
+	}
+	assert.InDelta(t, 0.95, sampleCorrelation(c, i), 0.02)
+	assert.InDelta(t, 0, sampleCorrelation(c, f), 0.05)
+}
+
+// loadSyntheticHistory loads twelve years of smooth synthetic history, 2000-2011, in
+// which the S fund tracks the C fund
+func loadSyntheticHistory(t *testing.T) *HistoricalDataManager {
+	t.Helper()
+	dataPath := t.TempDir()
+	series := map[string]func(i float64) float64{
+		"tsp-returns/c-fund-annual.csv": func(i float64) float64 { return 0.1 + 0.15*math.Sin(i*1.3) },
+		"tsp-returns/s-fund-annual.csv": func(i float64) float64 { return 0.11 + 0.165*math.Sin(i*1.3) + 0.02*math.Cos(i*2.7) },
+		"tsp-returns/i-fund-annual.csv": func(i float64) float64 { return 0.05 + 0.1*math.Sin(i*0.7) },
+		"tsp-returns/f-fund-annual.csv": func(i float64) float64 { return 0.04 + 0.03*math.Cos(i*1.9) },
+		"tsp-returns/g-fund-annual.csv": func(i float64) float64 { return 0.03 + 0.005*math.Sin(i*3.1) },
+		"inflation/cpi-annual.csv":      func(i float64) float64 { return 0.025 + 0.01*math.Cos(i*0.4) },
+		"cola/ss-cola-annual.csv":       func(i float64) float64 { return 0.025 },
+	}
+	for file, f := range series {
+		var b strings.Builder
+		b.WriteString("Year,Value\n")
+		for year := 2000; year < 2012; year++ {
+			fmt.Fprintf(&b, "%d,%.4f\n", year, f(float64(year-2000)))
+		}
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dataPath, file)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dataPath, file), []byte(b.String()), 0644))
+	}
+	hdm := NewHistoricalDataManager(dataPath)
+	require.NoError(t, hdm.LoadAllData())
+	return hdm
+}
@@ -7,49 +7,57 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// MedicareCalculator handles Medicare Part B premium calculations including IRMAA
+// MedicareCalculator handles Medicare Part B and Part D premium calculations including IRMAA
 type MedicareCalculator struct {
-	BasePremium2025 decimal.Decimal
-	IRMAAThresholds []IRMAAThreshold
+	BasePremium2025      decimal.Decimal
+	PartDBasePremium2025 decimal.Decimal // national base beneficiary premium
+	IRMAAThresholds      []IRMAAThreshold
 }
 
-// IRMAAThreshold represents an IRMAA income threshold and corresponding surcharge
+// IRMAAThreshold represents an IRMAA income threshold and corresponding surcharges
 type IRMAAThreshold struct {
 	IncomeThresholdSingle decimal.Decimal // For single filers
 	IncomeThresholdJoint  decimal.Decimal // For married filing jointly
-	MonthlySurcharge      decimal.Decimal // Additional monthly premium per person
+	MonthlySurcharge      decimal.Decimal // Additional monthly Part B premium per person
+	MonthlySurchargePartD decimal.Decimal // Additional monthly Part D premium per person
 }
 
 // NewMedicareCalculator creates a new Medicare calculator with 2025 rates
 func NewMedicareCalculator() *MedicareCalculator {
 	return &MedicareCalculator{
-		BasePremium2025: decimal.NewFromFloat(185.00), // 2025 base Part B premium
+		BasePremium2025:      decimal.NewFromFloat(185.00), // 2025 base Part B premium
+		PartDBasePremium2025: decimal.NewFromFloat(36.78),  // 2025 Part D base beneficiary premium
 		IRMAAThresholds: []IRMAAThreshold{
 			// 2025 IRMAA thresholds (based on 2023 MAGI)
 			{
 				IncomeThresholdSingle: decimal.NewFromInt(103000),
 				IncomeThresholdJoint:  decimal.NewFromInt(206000),
 				MonthlySurcharge:      decimal.NewFromFloat(69.90),
+				MonthlySurchargePartD: decimal.NewFromFloat(12.90),
 			},
 			{
 				IncomeThresholdSingle: decimal.NewFromInt(129000),
 				IncomeThresholdJoint:  decimal.NewFromInt(258000),
 				MonthlySurcharge:      decimal.NewFromFloat(174.70),
+				MonthlySurchargePartD: decimal.NewFromFloat(33.20),
 			},
 			{
 				IncomeThresholdSingle: decimal.NewFromInt(161000),
 				IncomeThresholdJoint:  decimal.NewFromInt(322000),
 				MonthlySurcharge:      decimal.NewFromFloat(279.50),
+				MonthlySurchargePartD: decimal.NewFromFloat(53.50),
 			},
 			{
 				IncomeThresholdSingle: decimal.NewFromInt(193000),
 				IncomeThresholdJoint:  decimal.NewFromInt(386000),
 				MonthlySurcharge:      decimal.NewFromFloat(384.30),
+				MonthlySurchargePartD: decimal.NewFromFloat(73.80),
 			},
 			{
 				IncomeThresholdSingle: decimal.NewFromInt(500000),
 				IncomeThresholdJoint:  decimal.NewFromInt(750000),
 				MonthlySurcharge:      decimal.NewFromFloat(489.10),
+				MonthlySurchargePartD: decimal.NewFromFloat(81.90),
 			},
 		},
 	}
@@ -64,12 +72,14 @@ func NewMedicareCalculatorWithConfig(config domain.MedicareConfig) *MedicareCalc
 			IncomeThresholdSingle: threshold.IncomeThresholdSingle,
 			IncomeThresholdJoint:  threshold.IncomeThresholdJoint,
 			MonthlySurcharge:      threshold.MonthlySurcharge,
+			MonthlySurchargePartD: threshold.MonthlySurchargePartD,
 		})
 	}
 
 	return &MedicareCalculator{
-		BasePremium2025: config.BasePremium2025,
-		IRMAAThresholds: thresholds,
+		BasePremium2025:      config.BasePremium2025,
+		PartDBasePremium2025: config.PartDBasePremium2025,
+		IRMAAThresholds:      thresholds,
 	}
 }
 
@@ -138,6 +148,30 @@ func (mc *MedicareCalculator) calculateIRMAASurcharge(estimatedMAGI decimal.Deci
 	return totalSurcharge
 }
 
+// CalculateAnnualPartDCost calculates the annual Part D premium at the base beneficiary
+// premium plus the Part D IRMAA surcharge
+func (mc *MedicareCalculator) CalculateAnnualPartDCost(estimatedMAGI decimal.Decimal, isMarriedFilingJointly bool) decimal.Decimal {
+	monthly := mc.PartDBasePremium2025.Add(mc.calculatePartDIRMAASurcharge(estimatedMAGI, isMarriedFilingJointly))
+	return monthly.Mul(decimal.NewFromInt(12))
+}
+
+// calculatePartDIRMAASurcharge calculates the Part D IRMAA surcharge based on MAGI, which
+// uses the Part B income tiers
+func (mc *MedicareCalculator) calculatePartDIRMAASurcharge(estimatedMAGI decimal.Decimal, isMarriedFilingJointly bool) decimal.Decimal {
+	var totalSurcharge decimal.Decimal
+	for _, threshold := range mc.IRMAAThresholds {
+		incomeThreshold := threshold.IncomeThresholdSingle
+		if isMarriedFilingJointly {
+			incomeThreshold = threshold.IncomeThresholdJoint
+		}
+		if !estimatedMAGI.GreaterThan(incomeThreshold) {
+			break
+		}
+		totalSurcharge = totalSurcharge.Add(threshold.MonthlySurchargePartD)
+	}
+	return totalSurcharge
+}
+
 // CalculateMedicarePremiumWithInflation calculates Medicare premium with inflation adjustment
 func (mc *MedicareCalculator) CalculateMedicarePremiumWithInflation(estimatedMAGI decimal.Decimal, isMarriedFilingJointly bool, yearsFrom2025 int) decimal.Decimal {
 	// Base calculation
@@ -170,3 +204,57 @@ func IsMedicareEligible(birthDate, atDate time.Time) bool {
 	}
 	return age >= 65
 }
+
+// MedicareEligibilityDate returns the first day of Medicare coverage: the first of the month
+// someone turns 65, or of the month before when the birthday falls on the 1st
+func MedicareEligibilityDate(birthDate time.Time) time.Time {
+	eligible := time.Date(birthDate.Year()+65, birthDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if birthDate.Day() == 1 {
+		eligible = eligible.AddDate(0, -1, 0)
+	}
+	return eligible
+}
+
+// MedicareMonthsInYear returns the months of year covered by Medicare for someone born on
+// birthDate, from 0 before the eligibility year to 12 after it
+func MedicareMonthsInYear(birthDate time.Time, year int) int {
+	eligible := MedicareEligibilityDate(birthDate)
+	switch {
+	case year < eligible.Year():
+		return 0
+	case year > eligible.Year():
+		return 12
+	default:
+		return 13 - int(eligible.Month())
+	}
+}
+
+// PartBEnrollmentDate returns when Part B coverage starts for someone born on birthDate who
+// enrolls at age: the month they reach it, or Medicare eligibility when age is 65 or less
+func PartBEnrollmentDate(birthDate time.Time, age int) time.Time {
+	return MedicareEligibilityDate(birthDate).AddDate(max(age-65, 0), 0, 0)
+}
+
+// partBSpecialEnrollmentMonths is how long after coverage through current employment ends
+// Part B can be taken without penalty
+const partBSpecialEnrollmentMonths = 8
+
+// PartBLateEnrollmentPenalty returns the lifetime Part B late enrollment penalty, as a share
+// of the standard premium, for someone born on birthDate whose Part B starts on enrollment:
+// 10% for each full 12 months they could have had Part B but went without. Months covered
+// through current employment, which ends on employmentCoverageEnd (zero for none), do not
+// count, and enrolling within 8 months after it ends carries no penalty.
+func PartBLateEnrollmentPenalty(birthDate, enrollment, employmentCoverageEnd time.Time) decimal.Decimal {
+	start := MedicareEligibilityDate(birthDate)
+	if employmentCoverageEnd.After(start) {
+		start = time.Date(employmentCoverageEnd.Year(), employmentCoverageEnd.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if !enrollment.After(start.AddDate(0, partBSpecialEnrollmentMonths, 0)) {
+			return decimal.Zero
+		}
+	}
+	months := (enrollment.Year()-start.Year())*12 + int(enrollment.Month()) - int(start.Month())
+	if months < 12 {
+		return decimal.Zero
+	}
+	return decimal.NewFromFloat(0.10).Mul(decimal.NewFromInt(int64(months / 12)))
+}
@@ -0,0 +1,113 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// MedicareCoverageComparison compares a participant keeping FEHB alongside Medicare against
+// dropping it for a Medigap plan and Part D
+type MedicareCoverageComparison struct {
+	ParticipantName string `json:"participantName"`
+
+	// Deterministic projections of each coverage
+	FEHBSecondary *domain.ScenarioSummary `json:"fehbSecondary"`
+	Medigap       *domain.ScenarioSummary `json:"medigap"`
+
+	// Lifetime premiums (FEHB, Part B, Part D and Medigap, with IRMAA) and out-of-pocket
+	// costs (medical and drug) under each coverage, for the household
+	FEHBPremiums       decimal.Decimal `json:"fehbPremiums"`
+	FEHBOutOfPocket    decimal.Decimal `json:"fehbOutOfPocket"`
+	MedigapPremiums    decimal.Decimal `json:"medigapPremiums"`
+	MedigapOutOfPocket decimal.Decimal `json:"medigapOutOfPocket"`
+
+	// Medigap minus FEHB lifetime net income
+	LifetimeIncomeDifference decimal.Decimal `json:"lifetimeIncomeDifference"`
+}
+
+// MedicareCoverageConfig returns a copy of config whose scenarios are the named scenario
+// with the participant keeping FEHB alongside Medicare and dropping it for Medigap, in
+// that order
+func MedicareCoverageConfig(config *domain.Configuration, scenarioName, participantName string) (*domain.Configuration, error) {
+	var participant *domain.Participant
+	for i := range config.Household.Participants {
+		if config.Household.Participants[i].Name == participantName {
+			participant = &config.Household.Participants[i]
+		}
+	}
+	if participant == nil {
+		return nil, fmt.Errorf("participant '%s' not found", participantName)
+	}
+	if participant.FEHBPremiumPerPayPeriod == nil || !participant.FEHBPremiumPerPayPeriod.IsPositive() {
+		return nil, fmt.Errorf("participant '%s' has no FEHB premium to keep alongside Medicare", participantName)
+	}
+
+	var base *domain.GenericScenario
+	for i := range config.Scenarios {
+		if config.Scenarios[i].Name == scenarioName {
+			base = &config.Scenarios[i]
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("scenario '%s' not found", scenarioName)
+	}
+	if _, ok := base.ParticipantScenarios[participantName]; !ok {
+		return nil, fmt.Errorf("scenario '%s' has no retirement plan for %s", scenarioName, participantName)
+	}
+
+	cover := func(coverage, label string) domain.GenericScenario {
+		scenario := base.DeepCopy()
+		scenario.Name = fmt.Sprintf("%s (%s)", scenarioName, label)
+		ps := scenario.ParticipantScenarios[participantName]
+		ps.MedicareCoverage = coverage
+		scenario.ParticipantScenarios[participantName] = ps
+		return *scenario
+	}
+	coverageConfig := *config
+	coverageConfig.Scenarios = []domain.GenericScenario{
+		cover(domain.MedicareCoverageFEHBSecondary, "FEHB + Medicare"),
+		cover(domain.MedicareCoverageMedigap, "Medigap + Part D"),
+	}
+	return &coverageConfig, nil
+}
+
+// CompareMedicareCoverage projects both scenarios of a configuration built by
+// MedicareCoverageConfig and compares Medigap against keeping FEHB
+func (ce *CalculationEngine) CompareMedicareCoverage(ctx context.Context, coverageConfig *domain.Configuration, participantName string) (*MedicareCoverageComparison, error) {
+	if len(coverageConfig.Scenarios) != 2 {
+		return nil, fmt.Errorf("a Medicare coverage comparison needs the FEHB and Medigap scenarios, got %d", len(coverageConfig.Scenarios))
+	}
+	fehb, err := ce.RunGenericScenario(ctx, coverageConfig, &coverageConfig.Scenarios[0])
+	if err != nil {
+		return nil, fmt.Errorf("FEHB projection failed: %w", err)
+	}
+	medigap, err := ce.RunGenericScenario(ctx, coverageConfig, &coverageConfig.Scenarios[1])
+	if err != nil {
+		return nil, fmt.Errorf("Medigap projection failed: %w", err)
+	}
+
+	comparison := &MedicareCoverageComparison{
+		ParticipantName:          participantName,
+		FEHBSecondary:            fehb,
+		Medigap:                  medigap,
+		LifetimeIncomeDifference: medigap.TotalLifetimeIncome.Sub(fehb.TotalLifetimeIncome),
+	}
+	comparison.FEHBPremiums, comparison.FEHBOutOfPocket = lifetimeHealthcareCosts(fehb)
+	comparison.MedigapPremiums, comparison.MedigapOutOfPocket = lifetimeHealthcareCosts(medigap)
+	return comparison, nil
+}
+
+// lifetimeHealthcareCosts sums the household's health insurance premiums and out-of-pocket
+// costs over the projection
+func lifetimeHealthcareCosts(summary *domain.ScenarioSummary) (premiums, outOfPocket decimal.Decimal) {
+	for i := range summary.Projection {
+		cf := &summary.Projection[i]
+		hc := cf.HealthcareCosts
+		premiums = premiums.Add(cf.FEHBPremium).Add(hc.MarketplacePremium).Add(hc.MedicarePartB).Add(hc.MedicarePartD).Add(hc.Medigap)
+		outOfPocket = outOfPocket.Add(hc.OutOfPocket).Add(hc.DrugCosts)
+	}
+	return premiums, outOfPocket
+}
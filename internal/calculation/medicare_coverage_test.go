@@ -0,0 +1,99 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionMedicareCoverage(t *testing.T) {
+	premium := decimal.NewFromInt(300)
+	healthcare := domain.DefaultHealthcareConfig()
+	healthcare.DrugCosts = decimal.NewFromInt(3000)
+	healthcare.OutOfPocketFEHB = decimal.NewFromInt(1500)
+	healthcare.OutOfPocketMedigap = decimal.NewFromInt(300)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Robert", BirthDate: time.Date(1961, 6, 15, 0, 0, 0, 0, time.UTC),
+			IsPrimaryFEHBHolder: true, FEHBPremiumPerPayPeriod: &premium, Healthcare: &healthcare,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         3,
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	project := func(coverage string) []domain.AnnualCashFlow {
+		scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Robert": {ParticipantName: "Robert", RetirementDate: &retire, SSStartAge: 70, MedicareCoverage: coverage},
+		}}
+		ce := NewCalculationEngine()
+		ce.ProjectionCache = nil
+		projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+		require.Len(t, projection, 3)
+		return projection
+	}
+	fehb := project(domain.MedicareCoverageFEHBSecondary)
+	medigap := project(domain.MedicareCoverageMedigap)
+
+	// Keeping FEHB, Robert pays its premium and Part B, with FEHB covering drugs up to
+	// Part D's out-of-pocket maximum
+	full := fehb[2]
+	assert.True(t, full.FEHBPremium.IsPositive())
+	assert.True(t, full.HealthcareCosts.MedicarePartB.IsPositive())
+	assert.True(t, full.HealthcareCosts.MedicarePartD.IsZero())
+	assert.True(t, full.HealthcareCosts.Medigap.IsZero())
+	assert.Equal(t, "2205.00", full.HealthcareCosts.DrugCosts.StringFixed(2)) // $2,000 inflated two years
+	assert.Equal(t, "1653.75", full.HealthcareCosts.OutOfPocket.StringFixed(2))
+
+	// Dropping to Medigap ends the FEHB premium when Medicare starts in June 2026 and buys
+	// Plan G and Part D instead
+	assert.Equal(t, fehb[0].FEHBPremium.String(), medigap[0].FEHBPremium.String())
+	assert.Equal(t, fehb[1].FEHBPremium.Mul(decimal.NewFromInt(5)).Div(decimalTwelve).StringFixed(2), medigap[1].FEHBPremium.StringFixed(2))
+	full = medigap[2]
+	assert.True(t, full.FEHBPremium.IsZero())
+	assert.True(t, full.HealthcareCosts.MedicarePartD.IsPositive())
+	assert.True(t, full.HealthcareCosts.Medigap.IsPositive())
+	assert.Equal(t, "330.75", full.HealthcareCosts.OutOfPocket.StringFixed(2))
+}
+
+func TestMedicareCoverageConfig(t *testing.T) {
+	premium := decimal.NewFromInt(300)
+	config := &domain.Configuration{
+		Household: &domain.Household{Participants: []domain.Participant{
+			{Name: "Robert", IsFederal: true, FEHBPremiumPerPayPeriod: &premium},
+			{Name: "Dawn"},
+		}},
+		Scenarios: []domain.GenericScenario{{
+			Name: "Base",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Robert": {ParticipantName: "Robert", SSStartAge: 67},
+				"Dawn":   {ParticipantName: "Dawn", SSStartAge: 67},
+			},
+		}},
+	}
+
+	coverageConfig, err := MedicareCoverageConfig(config, "Base", "Robert")
+	require.NoError(t, err)
+	require.Len(t, coverageConfig.Scenarios, 2)
+	assert.Equal(t, "Base (FEHB + Medicare)", coverageConfig.Scenarios[0].Name)
+	assert.Equal(t, domain.MedicareCoverageFEHBSecondary, coverageConfig.Scenarios[0].ParticipantScenarios["Robert"].MedicareCoverage)
+	assert.Equal(t, "Base (Medigap + Part D)", coverageConfig.Scenarios[1].Name)
+	assert.Equal(t, domain.MedicareCoverageMedigap, coverageConfig.Scenarios[1].ParticipantScenarios["Robert"].MedicareCoverage)
+	assert.Empty(t, config.Scenarios[0].ParticipantScenarios["Robert"].MedicareCoverage, "the original scenario is unchanged")
+
+	_, err = MedicareCoverageConfig(config, "Base", "Dawn")
+	assert.Error(t, err, "Dawn has no FEHB enrollment")
+	_, err = MedicareCoverageConfig(config, "Missing", "Robert")
+	assert.Error(t, err)
+
+	_, err = NewCalculationEngine().CompareMedicareCoverage(context.Background(), config, "Robert")
+	assert.Error(t, err, "the comparison needs both coverage scenarios")
+}
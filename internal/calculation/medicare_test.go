@@ -2,8 +2,12 @@ package calculation
 
 import (
 	"testing"
+	"time"
 
+	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMedicareCalculator_CalculatePartBPremium(t *testing.T) {
@@ -169,6 +173,160 @@ func TestEstimateMAGI(t *testing.T) {
 	}
 }
 
+func TestMedicareMonthsInYear(t *testing.T) {
+	tests := []struct {
+		name      string
+		birthDate time.Time
+		year      int
+		months    int
+	}{
+		{"before eligibility year", time.Date(1961, 8, 15, 0, 0, 0, 0, time.UTC), 2025, 0},
+		{"turns 65 in August", time.Date(1961, 8, 15, 0, 0, 0, 0, time.UTC), 2026, 5},
+		{"after eligibility year", time.Date(1961, 8, 15, 0, 0, 0, 0, time.UTC), 2027, 12},
+		{"born on the 1st starts the month before", time.Date(1961, 8, 1, 0, 0, 0, 0, time.UTC), 2026, 6},
+		{"born January 1st is eligible the prior December", time.Date(1961, 1, 1, 0, 0, 0, 0, time.UTC), 2026, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.months, MedicareMonthsInYear(tt.birthDate, tt.year))
+		})
+	}
+	assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), MedicareEligibilityDate(time.Date(1961, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestMedicareCalculator_CalculateAnnualPartDCost(t *testing.T) {
+	mc := NewMedicareCalculator()
+
+	// The base beneficiary premium alone below the first tier
+	assert.Equal(t, "441.36", mc.CalculateAnnualPartDCost(decimal.NewFromInt(50000), true).StringFixed(2))
+	// Above the second joint tier both surcharges apply: (36.78 + 12.90 + 33.20) * 12
+	assert.Equal(t, "994.56", mc.CalculateAnnualPartDCost(decimal.NewFromInt(260000), true).StringFixed(2))
+	// The same MAGI is past every tier but the last for a single filer
+	assert.Equal(t, "2522.16", mc.CalculateAnnualPartDCost(decimal.NewFromInt(260000), false).StringFixed(2))
+}
+
+func TestHealthcareCostsPartDAndDrugCosts(t *testing.T) {
+	hcc := NewHealthcareCostCalculator()
+	participant := &domain.Participant{
+		Name:       "Pat",
+		BirthDate:  time.Date(1955, 5, 10, 0, 0, 0, 0, time.UTC),
+		Healthcare: &domain.HealthcareConfig{MedicarePartD: true, MedicarePartDPlan: "standard", DrugCosts: decimal.NewFromInt(1500)},
+	}
+
+	// The plan premium plus the Part D IRMAA surcharge, and drug costs below the cap
+	costs := hcc.CalculateHealthcareCosts(participant, 70, 2025, decimal.NewFromInt(110000), "single")
+	assert.Equal(t, "574.80", costs.MedicarePartD.StringFixed(2)) // (35 + 12.90) * 12
+	assert.Equal(t, "1500", costs.DrugCosts.String())
+	assert.Equal(t, costs.MedicarePartD.Add(costs.DrugCosts).StringFixed(2), costs.Total.StringFixed(2))
+
+	// Part D caps out-of-pocket costs; without it they are paid in full
+	participant.Healthcare.DrugCosts = decimal.NewFromInt(6000)
+	costs = hcc.CalculateHealthcareCosts(participant, 70, 2025, decimal.Zero, "single")
+	assert.Equal(t, "2000", costs.DrugCosts.String())
+	participant.Healthcare.MedicarePartD = false
+	costs = hcc.CalculateHealthcareCosts(participant, 70, 2025, decimal.Zero, "single")
+	assert.Equal(t, "6000", costs.DrugCosts.String())
+	assert.True(t, costs.MedicarePartD.IsZero())
+}
+
+func TestHealthcareCostsProratedInMedicareYear(t *testing.T) {
+	hcc := NewHealthcareCostCalculator()
+	fehb := decimal.NewFromInt(100)
+	participant := &domain.Participant{
+		Name:                    "Pat",
+		BirthDate:               time.Date(1960, 10, 20, 0, 0, 0, 0, time.UTC),
+		FEHBPremiumPerPayPeriod: &fehb,
+		Healthcare:              &domain.HealthcareConfig{PreMedicareCoverage: "fehb", MedicarePartB: true, DropFEHBAt65: true},
+	}
+
+	// Medicare begins October 1, 2025: nine months of FEHB, then three of Part B
+	costs := hcc.CalculateHealthcareCosts(participant, 64, 2025, decimal.Zero, "single")
+	assert.Equal(t, "1950", costs.FEHBPremium.String())
+	partB := decimal.NewFromFloat(174.70).Mul(decimal.NewFromInt(12))
+	assert.Equal(t, partB.Div(decimal.NewFromInt(4)).StringFixed(2), costs.MedicarePartB.StringFixed(2))
+
+	// Keeping FEHB alongside Medicare costs a full year of premiums
+	participant.Healthcare.DropFEHBAt65 = false
+	costs = hcc.CalculateHealthcareCosts(participant, 64, 2025, decimal.Zero, "single")
+	assert.Equal(t, "2600", costs.FEHBPremium.String())
+
+	// The next year is all Medicare
+	costs = hcc.CalculateHealthcareCosts(participant, 65, 2026, decimal.Zero, "single")
+	assert.Equal(t, hcc.inflateFromBase(partB, 2026, hcc.InflationRates.MedicareB).StringFixed(2), costs.MedicarePartB.StringFixed(2))
+}
+
+func TestPartBLateEnrollmentPenalty(t *testing.T) {
+	birth := time.Date(1959, 6, 15, 0, 0, 0, 0, time.UTC) // eligible June 2024
+	tests := []struct {
+		name        string
+		age         int
+		coverageEnd time.Time
+		penalty     string
+	}{
+		{"enrolled at 65", 65, time.Time{}, "0"},
+		{"under a year late", 66, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "0"},
+		{"three years late", 68, time.Time{}, "0.3"},
+		{"coverage before 65 does not count", 68, time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC), "0.3"},
+		{"within the special enrollment period", 68, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), "0"},
+		{"after the special enrollment period", 69, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), "0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollment := PartBEnrollmentDate(birth, tt.age)
+			assert.Equal(t, tt.penalty, PartBLateEnrollmentPenalty(birth, enrollment, tt.coverageEnd).String())
+		})
+	}
+}
+
+func TestProjectionPartBLatePenalty(t *testing.T) {
+	healthcare := domain.DefaultHealthcareConfig()
+	healthcare.MedicarePartD = false
+	healthcare.MedigapPlan = ""
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:       "Pat",
+			BirthDate:  time.Date(1959, 6, 15, 0, 0, 0, 0, time.UTC),
+			Healthcare: &healthcare,
+		}},
+	}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Pat": {ParticipantName: "Pat", SSStartAge: 70, PartBEnrollmentAge: 68},
+	}}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 4}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 4)
+	hcc := NewHealthcareCostCalculator()
+	partB := func(year int) decimal.Decimal {
+		return hcc.inflateFromBase(decimal.NewFromFloat(174.70).Mul(decimal.NewFromInt(12)), year, hcc.InflationRates.MedicareB)
+	}
+
+	// No Part B until June 2027, then the standard premium plus 30% for three years late
+	assert.True(t, projection[1].HealthcareCosts.MedicarePartB.IsZero())
+	assert.Equal(t, partB(2027).Mul(decimal.NewFromFloat(1.3)).Mul(decimal.NewFromInt(7)).Div(decimal.NewFromInt(12)).StringFixed(2),
+		projection[2].HealthcareCosts.MedicarePartB.StringFixed(2))
+	assert.Equal(t, partB(2028).Mul(decimal.NewFromFloat(0.3)).StringFixed(2), projection[3].HealthcareCosts.PartBLatePenalty.StringFixed(2))
+	require.Len(t, projection[2].Warnings, 1)
+	assert.Equal(t, domain.WarningPartBLatePenalty, projection[2].Warnings[0].Code)
+
+	// Working with FEHB until the end of 2026 excuses the delay
+	fehb := decimal.NewFromInt(200)
+	hire := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	retire := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	household.Participants[0].IsFederal = true
+	household.Participants[0].HireDate = &hire
+	household.Participants[0].FEHBPremiumPerPayPeriod = &fehb
+	household.Participants[0].IsPrimaryFEHBHolder = true
+	ps := scenario.ParticipantScenarios["Pat"]
+	ps.RetirementDate = &retire
+	scenario.ParticipantScenarios["Pat"] = ps
+	projection = ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	assert.True(t, projection[3].HealthcareCosts.PartBLatePenalty.IsZero())
+}
+
 // TestMedicareRealWorldScenario tests Medicare calculations with realistic Robert/Dawn income levels
 func TestMedicareRealWorldScenario(t *testing.T) {
 	mc := NewMedicareCalculator()
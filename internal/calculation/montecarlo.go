@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 )
 
@@ -17,6 +19,15 @@ type MonteCarloSimulator struct {
 	ProjectionYears int
 	Seed            int64
 	UseHistorical   bool // If true, sample from historical data; if false, use statistical distributions
+	// BlockLength is how many consecutive historical years each draw takes, keeping
+	// multi-year market runs intact; 1 or less samples every year independently
+	BlockLength int
+	// MonthlySteps compounds each simulated year month by month, taking the year's
+	// withdrawal in twelve installments
+	MonthlySteps bool
+
+	// shocks draws the statistical fund returns and inflation jointly
+	shocks *marketShocks
 }
 
 // MonteCarloConfig holds configuration for Monte Carlo simulations
@@ -25,7 +36,13 @@ type MonteCarloConfig struct {
 	ProjectionYears    int
 	Seed               int64
 	UseHistorical      bool
-	AssetAllocation    map[string]decimal.Decimal // Fund allocation percentages
+	BlockLength        int                        // Consecutive historical years per draw (block bootstrap); 1 or less samples years independently
+	CorrelationMatrix  domain.CorrelationMatrix   // Correlation of statistical draws; estimated from history when unset
+	ReturnDistribution string                     // Statistical shock distribution: normal (default), student_t, or historical_residuals
+	DegreesOfFreedom   int                        // Student-t degrees of freedom (default 5)
+	AssetAllocation    map[string]decimal.Decimal // Fund allocation percentages; keys are C/S/I/F/G or L fund names
+	StartYear          int                        // Calendar year of the first simulated year, used for L fund glide paths (default: current year)
+	MonthlySteps       bool                       // Step each year month by month, with historical monthly returns where loaded
 	WithdrawalStrategy string
 	InitialBalance     decimal.Decimal
 	AnnualWithdrawal   decimal.Decimal
@@ -33,8 +50,12 @@ type MonteCarloConfig struct {
 
 // MonteCarloResult represents the results of a Monte Carlo simulation
 type MonteCarloResult struct {
-	Simulations         []SimulationOutcome        `json:"simulations"`
-	SuccessRate         decimal.Decimal            `json:"success_rate"`
+	Simulations []SimulationOutcome `json:"simulations,omitempty"`
+	SuccessRate decimal.Decimal     `json:"success_rate"`
+	// SuccessRateStdErr is the standard error of the success rate, and SuccessRateMargin
+	// the half-width of its 95% (Wilson score) confidence interval
+	SuccessRateStdErr   decimal.Decimal            `json:"success_rate_std_err"`
+	SuccessRateMargin   decimal.Decimal            `json:"success_rate_margin"`
 	MedianEndingBalance decimal.Decimal            `json:"median_ending_balance"`
 	PercentileRanges    PercentileRanges           `json:"percentile_ranges"`
 	NumSimulations      int                        `json:"num_simulations"`
@@ -43,6 +64,20 @@ type MonteCarloResult struct {
 	WithdrawalStrategy  string                     `json:"withdrawal_strategy"`
 	InitialBalance      decimal.Decimal            `json:"initial_balance"`
 	AnnualWithdrawal    decimal.Decimal            `json:"annual_withdrawal"`
+	Seed                int64                      `json:"seed"`
+	BlockLength         int                        `json:"block_length,omitempty"`
+	MonthlySteps        bool                       `json:"monthly_steps,omitempty"`
+	// YearlyPercentiles holds each simulated year's balance and withdrawal percentiles
+	YearlyPercentiles []YearPercentiles `json:"yearly_percentiles"`
+}
+
+// YearPercentiles holds the percentiles across simulations of one simulated year, for
+// charting how the spread of outcomes widens over time. A depleted portfolio counts as
+// a zero balance and withdrawal in the years after it ran out.
+type YearPercentiles struct {
+	Year       int              `json:"year"`
+	Balance    PercentileRanges `json:"balance"`
+	Withdrawal PercentileRanges `json:"withdrawal"`
 }
 
 // SimulationOutcome represents a single Monte Carlo simulation outcome
@@ -53,6 +88,9 @@ type SimulationOutcome struct {
 	Success         bool            `json:"success"`
 	MaxDrawdown     decimal.Decimal `json:"max_drawdown"`
 	TotalWithdrawn  decimal.Decimal `json:"total_withdrawn"`
+	// HistoricalPath lists the historical year sampled for each simulated year when
+	// UseHistorical is enabled, so an outcome can be traced back to the history behind it
+	HistoricalPath []int `json:"historical_path,omitempty"`
 }
 
 // YearOutcome represents a single year's outcome in a Monte Carlo simulation
@@ -63,6 +101,8 @@ type YearOutcome struct {
 	Return     decimal.Decimal `json:"return"`
 	Inflation  decimal.Decimal `json:"inflation"`
 	COLA       decimal.Decimal `json:"cola"`
+
+	HistoricalYear int `json:"historical_year,omitempty"`
 }
 
 // PercentileRanges represents percentile ranges for Monte Carlo results
@@ -91,6 +131,13 @@ func NewMonteCarloSimulator(historicalData *HistoricalDataManager, config MonteC
 		ProjectionYears: config.ProjectionYears,
 		Seed:            config.Seed,
 		UseHistorical:   config.UseHistorical,
+		BlockLength:     config.BlockLength,
+		MonthlySteps:    config.MonthlySteps,
+		shocks: newMarketShocks(domain.MonteCarloSettings{
+			CorrelationMatrix:  config.CorrelationMatrix,
+			ReturnDistribution: config.ReturnDistribution,
+			DegreesOfFreedom:   config.DegreesOfFreedom,
+		}, historicalData),
 	}
 }
 
@@ -100,10 +147,6 @@ func (mcs *MonteCarloSimulator) RunSimulation(config MonteCarloConfig) (*MonteCa
 		return nil, fmt.Errorf("historical data not loaded")
 	}
 
-	// Set random seed (using modern Go approach)
-	// Note: In Go 1.20+, global rand is automatically seeded
-	// For reproducible results with specific seeds, a local random source would be needed
-
 	// Run simulations in parallel
 	results := make([]SimulationOutcome, mcs.NumSimulations)
 	var wg sync.WaitGroup
@@ -116,7 +159,10 @@ func (mcs *MonteCarloSimulator) RunSimulation(config MonteCarloConfig) (*MonteCa
 			semaphore <- struct{}{}        // Acquire semaphore
 			defer func() { <-semaphore }() // Release semaphore
 
-			outcome := mcs.runSingleSimulation(config)
+			// Each simulation gets its own source so its draws, including the
+			// historical path, can be reproduced from Seed and the simulation index
+			rng := rand.New(rand.NewSource(mcs.Seed + int64(simIndex)))
+			outcome := mcs.runSingleSimulation(config, rng)
 			results[simIndex] = outcome
 		}(i)
 	}
@@ -125,12 +171,15 @@ func (mcs *MonteCarloSimulator) RunSimulation(config MonteCarloConfig) (*MonteCa
 
 	// Calculate aggregate statistics
 	successRate := mcs.calculateSuccessRate(results)
+	stdErr := successRateStdErr(successRate, len(results))
 	medianEndingBalance := mcs.calculateMedianEndingBalance(results)
 	percentileRanges := mcs.calculatePercentileRanges(results)
 
 	return &MonteCarloResult{
 		Simulations:         results,
 		SuccessRate:         successRate,
+		SuccessRateStdErr:   stdErr,
+		SuccessRateMargin:   successRateMargin(successRate, len(results)),
 		MedianEndingBalance: medianEndingBalance,
 		PercentileRanges:    percentileRanges,
 		NumSimulations:      mcs.NumSimulations,
@@ -139,36 +188,65 @@ func (mcs *MonteCarloSimulator) RunSimulation(config MonteCarloConfig) (*MonteCa
 		WithdrawalStrategy:  config.WithdrawalStrategy,
 		InitialBalance:      config.InitialBalance,
 		AnnualWithdrawal:    config.AnnualWithdrawal,
+		Seed:                mcs.Seed,
+		BlockLength:         mcs.BlockLength,
+		MonthlySteps:        mcs.MonthlySteps,
+		YearlyPercentiles:   mcs.calculateYearlyPercentiles(results),
 	}, nil
 }
 
 // runSingleSimulation runs a single Monte Carlo simulation
-func (mcs *MonteCarloSimulator) runSingleSimulation(config MonteCarloConfig) SimulationOutcome {
+func (mcs *MonteCarloSimulator) runSingleSimulation(config MonteCarloConfig, rng *rand.Rand) SimulationOutcome {
 	currentBalance := config.InitialBalance
 	var yearOutcomes []YearOutcome
 	var totalWithdrawn decimal.Decimal
+	var historicalPath []int
 	maxDrawdown := decimal.Zero
 	peakBalance := currentBalance
+	startYear := config.StartYear
+	if startYear == 0 {
+		startYear = time.Now().Year()
+	}
+
+	// A block bootstrap draws the whole historical path up front so runs of consecutive
+	// years stay together
+	var blockPath []int
+	if mcs.UseHistorical && mcs.BlockLength > 1 {
+		blockPath, _ = mcs.HistoricalData.SampleHistoricalPath(rng, mcs.ProjectionYears, mcs.BlockLength)
+	}
 
 	for year := 1; year <= mcs.ProjectionYears; year++ {
 		// Sample market conditions
-		marketData := mcs.sampleMarketConditions()
+		var marketData MarketData
+		if blockPath != nil {
+			marketData = mcs.historicalMarketConditions(blockPath[year-1], rng)
+		} else {
+			marketData = mcs.sampleMarketConditions(rng)
+		}
+		if marketData.HistoricalYear != 0 {
+			historicalPath = append(historicalPath, marketData.HistoricalYear)
+		}
 
-		// Calculate portfolio return based on asset allocation
-		portfolioReturn := mcs.calculatePortfolioReturn(config.AssetAllocation, marketData)
+		var portfolioReturn, withdrawal decimal.Decimal
+		if mcs.MonthlySteps {
+			portfolioReturn, withdrawal, currentBalance = mcs.stepMonthly(config, currentBalance, year, marketData, startYear+year-1)
+		} else {
+			// Calculate portfolio return based on asset allocation
+			portfolioReturn = mcs.calculatePortfolioReturn(config.AssetAllocation, marketData, startYear+year-1)
 
-		// Apply market returns
-		growth := currentBalance.Mul(portfolioReturn)
-		currentBalance = currentBalance.Add(growth)
+			// Apply market returns
+			growth := currentBalance.Mul(portfolioReturn)
+			currentBalance = currentBalance.Add(growth)
 
-		// Calculate withdrawal (considering market conditions and inflation)
-		withdrawal := mcs.calculateDynamicWithdrawal(config, currentBalance, year, marketData)
+			// Calculate withdrawal (considering market conditions and inflation)
+			withdrawal = mcs.calculateDynamicWithdrawal(config, currentBalance, year, marketData)
 
-		// Apply withdrawal
-		if withdrawal.GreaterThan(currentBalance) {
-			withdrawal = currentBalance
+			// Apply withdrawal
+			if withdrawal.GreaterThan(currentBalance) {
+				withdrawal = currentBalance
+			}
+			currentBalance = currentBalance.Sub(withdrawal)
 		}
-		currentBalance = currentBalance.Sub(withdrawal)
 		totalWithdrawn = totalWithdrawn.Add(withdrawal)
 
 		// Track drawdown
@@ -187,6 +265,8 @@ func (mcs *MonteCarloSimulator) runSingleSimulation(config MonteCarloConfig) Sim
 			Return:     portfolioReturn,
 			Inflation:  marketData.Inflation,
 			COLA:       marketData.COLA,
+
+			HistoricalYear: marketData.HistoricalYear,
 		})
 
 		// Check if portfolio is depleted
@@ -204,40 +284,81 @@ func (mcs *MonteCarloSimulator) runSingleSimulation(config MonteCarloConfig) Sim
 		Success:         success,
 		MaxDrawdown:     maxDrawdown,
 		TotalWithdrawn:  totalWithdrawn,
+		HistoricalPath:  historicalPath,
+	}
+}
+
+// stepMonthly runs one simulated year month by month. The year's withdrawal is set
+// from the starting balance and comes out in twelve installments, each after that
+// month's return. Months use the year's historical monthly returns when loaded, and
+// otherwise each fund's annual return spread evenly by compounding. It returns the
+// year's compounded return, the amount withdrawn and the ending balance.
+func (mcs *MonteCarloSimulator) stepMonthly(config MonteCarloConfig, balance decimal.Decimal, year int, marketData MarketData, calendarYear int) (decimal.Decimal, decimal.Decimal, decimal.Decimal) {
+	months := marketData.MonthlyTSPReturns
+	if len(months) != 12 {
+		spread := make(map[string]decimal.Decimal, len(marketData.TSPReturns))
+		for fund, r := range marketData.TSPReturns {
+			spread[fund] = periodicRate(r, 12)
+		}
+		months = slices.Repeat([]map[string]decimal.Decimal{spread}, 12)
+	}
+
+	installment := mcs.calculateDynamicWithdrawal(config, balance, year, marketData).Div(decimal.NewFromInt(12))
+	growth := decimal.NewFromInt(1)
+	withdrawn := decimal.Zero
+	for _, returns := range months {
+		monthReturn := mcs.calculatePortfolioReturn(config.AssetAllocation, MarketData{TSPReturns: returns}, calendarYear)
+		growth = growth.Mul(onePlus(monthReturn))
+		balance = balance.Mul(onePlus(monthReturn))
+		withdrawal := decimal.Min(installment, balance)
+		balance = balance.Sub(withdrawal)
+		withdrawn = withdrawn.Add(withdrawal)
 	}
+	return growth.Sub(decimal.NewFromInt(1)), withdrawn, balance
 }
 
 // MarketData represents market conditions for a given year
 type MarketData struct {
 	TSPReturns map[string]decimal.Decimal
-	Inflation  decimal.Decimal
-	COLA       decimal.Decimal
+	// MonthlyTSPReturns are the year's fund returns month by month, January first, when
+	// a historical year has all twelve
+	MonthlyTSPReturns []map[string]decimal.Decimal
+	Inflation         decimal.Decimal
+	COLA              decimal.Decimal
+	// HistoricalYear is the year of history sampled, or zero for statistical draws
+	HistoricalYear int
 }
 
 // sampleMarketConditions samples market conditions
-func (mcs *MonteCarloSimulator) sampleMarketConditions() MarketData {
+func (mcs *MonteCarloSimulator) sampleMarketConditions(rng *rand.Rand) MarketData {
 	if mcs.UseHistorical {
-		return mcs.sampleHistoricalMarketConditions()
+		return mcs.sampleHistoricalMarketConditions(rng)
 	} else {
-		return mcs.generateStatisticalMarketConditions()
+		return mcs.generateStatisticalMarketConditions(rng)
 	}
 }
 
 // sampleHistoricalMarketConditions samples from historical data
-func (mcs *MonteCarloSimulator) sampleHistoricalMarketConditions() MarketData {
+func (mcs *MonteCarloSimulator) sampleHistoricalMarketConditions(rng *rand.Rand) MarketData {
 	// Get available years
 	minYear, maxYear, err := mcs.HistoricalData.GetAvailableYears()
 	if err != nil {
 		// Fallback to statistical generation
-		return mcs.generateStatisticalMarketConditions()
+		return mcs.generateStatisticalMarketConditions(rng)
 	}
 
 	// Randomly select a historical year
-	historicalYear := minYear + rand.Intn(maxYear-minYear+1)
+	historicalYear := minYear + rng.Intn(maxYear-minYear+1)
+
+	return mcs.historicalMarketConditions(historicalYear, rng)
+}
 
-	// Get historical data for that year
+// historicalMarketConditions returns the market conditions of a historical year, drawing
+// statistically for any series without data for that year
+func (mcs *MonteCarloSimulator) historicalMarketConditions(historicalYear int, rng *rand.Rand) MarketData {
 	marketData := MarketData{
-		TSPReturns: make(map[string]decimal.Decimal),
+		TSPReturns:     make(map[string]decimal.Decimal),
+		HistoricalYear: historicalYear,
 	}
 
 	// Sample TSP fund returns
@@ -247,7 +368,13 @@ func (mcs *MonteCarloSimulator) sampleHistoricalMarketConditions() MarketData {
 			marketData.TSPReturns[fund] = returnRate
 		} else {
 			// Fallback to statistical generation for this fund
-			marketData.TSPReturns[fund] = mcs.generateStatisticalReturn(fund)
+			marketData.TSPReturns[fund] = mcs.generateStatisticalReturn(fund, rng)
+		}
+	}
+
+	if months, ok := mcs.HistoricalData.MonthlyFundReturns(historicalYear); ok {
+		for _, month := range months {
+			marketData.MonthlyTSPReturns = append(marketData.MonthlyTSPReturns, fundReturnMap(month))
 		}
 	}
 
@@ -255,40 +382,60 @@ func (mcs *MonteCarloSimulator) sampleHistoricalMarketConditions() MarketData {
 	if inflation, err := mcs.HistoricalData.GetInflationRate(historicalYear); err == nil {
 		marketData.Inflation = inflation
 	} else {
-		marketData.Inflation = mcs.generateStatisticalInflation()
+		marketData.Inflation = mcs.generateStatisticalInflation(rng)
 	}
 
 	if cola, err := mcs.HistoricalData.GetCOLARate(historicalYear); err == nil {
 		marketData.COLA = cola
 	} else {
-		marketData.COLA = mcs.generateStatisticalCOLA()
+		marketData.COLA = mcs.generateStatisticalCOLA(rng)
 	}
 
 	return marketData
 }
 
 // generateStatisticalMarketConditions generates market conditions using statistical distributions
-func (mcs *MonteCarloSimulator) generateStatisticalMarketConditions() MarketData {
+func (mcs *MonteCarloSimulator) generateStatisticalMarketConditions(rng *rand.Rand) MarketData {
 	marketData := MarketData{
 		TSPReturns: make(map[string]decimal.Decimal),
 	}
 
-	// Generate returns for each fund
-	funds := []string{"C", "S", "I", "F", "G"}
-	for _, fund := range funds {
-		marketData.TSPReturns[fund] = mcs.generateStatisticalReturn(fund)
+	// Draw the fund returns and inflation jointly so they move together as they have
+	shocks := mcs.shocks
+	if shocks == nil {
+		shocks = defaultMarketShocks
 	}
-
-	marketData.Inflation = mcs.generateStatisticalInflation()
-	marketData.COLA = mcs.generateStatisticalCOLA()
+	z := shocks.draw(rng)
+	for i, fund := range domain.MarketFactors[:5] {
+		mean, stdDev := statisticalReturnModel(fund)
+		marketData.TSPReturns[fund] = mean.Add(decimal.NewFromFloat(z[i]).Mul(stdDev))
+	}
+	marketData.Inflation = statisticalInflationMean.Add(decimal.NewFromFloat(z[5]).Mul(statisticalInflationStdDev))
+	marketData.COLA = mcs.generateStatisticalCOLA(rng)
 
 	return marketData
 }
 
 // generateStatisticalReturn generates a statistical return for a given fund
-func (mcs *MonteCarloSimulator) generateStatisticalReturn(fund string) decimal.Decimal {
-	// Use historical statistics if available, otherwise use reasonable defaults
-	var mean, stdDev decimal.Decimal
+func (mcs *MonteCarloSimulator) generateStatisticalReturn(fund string, rng *rand.Rand) decimal.Decimal {
+	mean, stdDev := statisticalReturnModel(fund)
+	if mcs.shocks != nil {
+		return mean.Add(decimal.NewFromFloat(mcs.shocks.drawFactor(marketFactorIndex(fund), rng)).Mul(stdDev))
+	}
+
+	// Generate normal distribution (simplified)
+	// In a production system, you might want to use a more sophisticated distribution
+	u1 := rng.Float64()
+	u2 := rng.Float64()
+	z := mcs.boxMullerTransform(u1, u2)
+
+	// Convert to decimal and apply mean/std dev
+	zDecimal := decimal.NewFromFloat(z)
+	return mean.Add(zDecimal.Mul(stdDev))
+}
+
+// statisticalReturnModel returns the mean and standard deviation of a fund's annual return
+func statisticalReturnModel(fund string) (mean, stdDev decimal.Decimal) {
 
 	switch fund {
 	case "C":
@@ -310,25 +457,21 @@ func (mcs *MonteCarloSimulator) generateStatisticalReturn(fund string) decimal.D
 		mean = decimal.NewFromFloat(0.08)   // 8% default mean
 		stdDev = decimal.NewFromFloat(0.15) // 15% default std dev
 	}
-
-	// Generate normal distribution (simplified)
-	// In a production system, you might want to use a more sophisticated distribution
-	u1 := rand.Float64()
-	u2 := rand.Float64()
-	z := mcs.boxMullerTransform(u1, u2)
-
-	// Convert to decimal and apply mean/std dev
-	zDecimal := decimal.NewFromFloat(z)
-	return mean.Add(zDecimal.Mul(stdDev))
+	return mean, stdDev
 }
 
+var (
+	statisticalInflationMean   = decimal.NewFromFloat(0.0259) // 2.59% historical mean
+	statisticalInflationStdDev = decimal.NewFromFloat(0.0137) // 1.37% historical std dev
+)
+
 // generateStatisticalInflation generates statistical inflation rate
-func (mcs *MonteCarloSimulator) generateStatisticalInflation() decimal.Decimal {
-	mean := decimal.NewFromFloat(0.0259)   // 2.59% historical mean
-	stdDev := decimal.NewFromFloat(0.0137) // 1.37% historical std dev
+func (mcs *MonteCarloSimulator) generateStatisticalInflation(rng *rand.Rand) decimal.Decimal {
+	mean := statisticalInflationMean
+	stdDev := statisticalInflationStdDev
 
-	u1 := rand.Float64()
-	u2 := rand.Float64()
+	u1 := rng.Float64()
+	u2 := rng.Float64()
 	z := mcs.boxMullerTransform(u1, u2)
 
 	zDecimal := decimal.NewFromFloat(z)
@@ -336,12 +479,12 @@ func (mcs *MonteCarloSimulator) generateStatisticalInflation() decimal.Decimal {
 }
 
 // generateStatisticalCOLA generates statistical COLA rate
-func (mcs *MonteCarloSimulator) generateStatisticalCOLA() decimal.Decimal {
+func (mcs *MonteCarloSimulator) generateStatisticalCOLA(rng *rand.Rand) decimal.Decimal {
 	mean := decimal.NewFromFloat(0.0255)   // 2.55% historical mean
 	stdDev := decimal.NewFromFloat(0.0182) // 1.82% historical std dev
 
-	u1 := rand.Float64()
-	u2 := rand.Float64()
+	u1 := rng.Float64()
+	u2 := rng.Float64()
 	z := mcs.boxMullerTransform(u1, u2)
 
 	zDecimal := decimal.NewFromFloat(z)
@@ -360,13 +503,16 @@ func (mcs *MonteCarloSimulator) sqrt(x float64) float64 {
 	return math.Sqrt(x)
 }
 
-// calculatePortfolioReturn calculates the weighted portfolio return based on asset allocation
-func (mcs *MonteCarloSimulator) calculatePortfolioReturn(allocation map[string]decimal.Decimal, marketData MarketData) decimal.Decimal {
+// calculatePortfolioReturn calculates the weighted portfolio return based on asset allocation.
+// An L fund's share is spread across the individual funds by its allocation in calendarYear.
+func (mcs *MonteCarloSimulator) calculatePortfolioReturn(allocation map[string]decimal.Decimal, marketData MarketData, calendarYear int) decimal.Decimal {
 	var portfolioReturn decimal.Decimal
 
 	for fund, weight := range allocation {
 		if returnRate, exists := marketData.TSPReturns[fund]; exists {
 			portfolioReturn = portfolioReturn.Add(returnRate.Mul(weight))
+		} else if lifecycle, err := LifecycleAllocation(fund, calendarYear); err == nil {
+			portfolioReturn = portfolioReturn.Add(weightedReturn(lifecycle, marketData.TSPReturns).Mul(weight))
 		}
 	}
 
@@ -418,6 +564,21 @@ func (mcs *MonteCarloSimulator) calculateGuardrailsWithdrawal(config MonteCarloC
 	return baseWithdrawal
 }
 
+// NearestSimulation returns the index of the simulation whose ending balance is
+// closest to target, e.g. to inspect the path behind the P10 outcome
+func (r *MonteCarloResult) NearestSimulation(target decimal.Decimal) int {
+	nearest := -1
+	var nearestDistance decimal.Decimal
+	for i, sim := range r.Simulations {
+		distance := sim.EndingBalance.Sub(target).Abs()
+		if nearest == -1 || distance.LessThan(nearestDistance) {
+			nearest = i
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
 // calculateSuccessRate calculates the percentage of successful simulations
 func (mcs *MonteCarloSimulator) calculateSuccessRate(simulations []SimulationOutcome) decimal.Decimal {
 	successCount := 0
@@ -455,18 +616,44 @@ func (mcs *MonteCarloSimulator) calculatePercentileRanges(simulations []Simulati
 		balances[i] = sim.EndingBalance
 	}
 
-	// Sort balances
-	mcs.sortBalances(balances)
+	return percentileRangesOf(balances)
+}
 
-	// Calculate percentiles
-	n := len(balances)
+// calculateYearlyPercentiles calculates the balance and withdrawal percentiles of every
+// simulated year
+func (mcs *MonteCarloSimulator) calculateYearlyPercentiles(simulations []SimulationOutcome) []YearPercentiles {
+	yearly := make([]YearPercentiles, mcs.ProjectionYears)
+	for y := range yearly {
+		balances := make([]decimal.Decimal, len(simulations))
+		withdrawals := make([]decimal.Decimal, len(simulations))
+		for i, sim := range simulations {
+			if y < len(sim.YearOutcomes) {
+				balances[i] = sim.YearOutcomes[y].Balance
+				withdrawals[i] = sim.YearOutcomes[y].Withdrawal
+			}
+		}
+		yearly[y] = YearPercentiles{
+			Year:       y + 1,
+			Balance:    percentileRangesOf(balances),
+			Withdrawal: percentileRangesOf(withdrawals),
+		}
+	}
+	return yearly
+}
 
+// percentileRangesOf returns the percentile ranges of values, which it sorts
+func percentileRangesOf(values []decimal.Decimal) PercentileRanges {
+	n := len(values)
+	if n == 0 {
+		return PercentileRanges{}
+	}
+	slices.SortFunc(values, func(a, b decimal.Decimal) int { return a.Cmp(b) })
 	return PercentileRanges{
-		P10: balances[n/10],
-		P25: balances[n/4],
-		P50: balances[n/2],
-		P75: balances[3*n/4],
-		P90: balances[9*n/10],
+		P10: values[n/10],
+		P25: values[n/4],
+		P50: values[n/2],
+		P75: values[3*n/4],
+		P90: values[9*n/10],
 	}
 }
 
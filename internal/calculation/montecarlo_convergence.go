@@ -0,0 +1,81 @@
+package calculation
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// confidenceZ is the normal quantile of a two-sided 95% confidence interval
+const confidenceZ = 1.96
+
+// defaultMaxSimulations caps a run extended to meet a success-rate tolerance
+const defaultMaxSimulations = 50000
+
+// successRateStdErr returns the standard error of a success rate estimated from n
+// simulations, sqrt(p(1-p)/n)
+func successRateStdErr(rate decimal.Decimal, n int) decimal.Decimal {
+	if n == 0 {
+		return decimal.Zero
+	}
+	p := rate.InexactFloat64()
+	return decimal.NewFromFloat(math.Sqrt(p * (1 - p) / float64(n))).Round(6)
+}
+
+// successRateMargin returns the half-width of the success rate's 95% Wilson score
+// interval, which unlike ±1.96 standard errors stays open when every simulation, or
+// none, succeeds
+func successRateMargin(rate decimal.Decimal, n int) decimal.Decimal {
+	return decimal.NewFromFloat(wilsonMargin(rate.InexactFloat64(), n)).Round(6)
+}
+
+// wilsonMargin returns the half-width of the 95% Wilson score interval of a proportion p
+// observed in n trials
+func wilsonMargin(p float64, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	z2 := confidenceZ * confidenceZ
+	nf := float64(n)
+	return confidenceZ / (1 + z2/nf) * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))
+}
+
+// SetTolerance extends each run past the configured simulation count, up to
+// maxSimulations (0 for the default), until the success rate's 95% confidence interval
+// is within ± tolerance. A zero tolerance runs exactly the configured count.
+func (fmce *FERSMonteCarloEngine) SetTolerance(tolerance decimal.Decimal, maxSimulations int) {
+	fmce.config.Tolerance = tolerance
+	fmce.config.MaxSimulations = maxSimulations
+}
+
+// additionalSimulations returns how many more simulations should bring the success
+// rate's 95% Wilson interval within the tolerance, or zero once it is within it or
+// the run has reached its maximum
+func (fmce *FERSMonteCarloEngine) additionalSimulations(simulations []FERSMonteCarloSimulation) int {
+	tolerance := fmce.config.Tolerance.InexactFloat64()
+	n := len(simulations)
+	if tolerance <= 0 || n == 0 {
+		return 0
+	}
+	maxSimulations := fmce.config.MaxSimulations
+	if maxSimulations <= 0 {
+		maxSimulations = defaultMaxSimulations
+	}
+
+	successes := 0
+	for _, sim := range simulations {
+		if sim.Success {
+			successes++
+		}
+	}
+	p := float64(successes) / float64(n)
+	if wilsonMargin(p, n) <= tolerance {
+		return 0
+	}
+
+	// Aim for the count the current estimate needs, growing by at least half so a noisy
+	// or all-success early estimate can't stall the run in many small rounds
+	needed := int(math.Ceil(confidenceZ * confidenceZ * p * (1 - p) / (tolerance * tolerance)))
+	needed = min(max(needed, n+n/2), maxSimulations)
+	return max(needed-n, 0)
+}
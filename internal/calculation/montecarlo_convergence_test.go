@@ -0,0 +1,58 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuccessRateUncertainty(t *testing.T) {
+	// sqrt(0.8 * 0.2 / 100)
+	assert.True(t, successRateStdErr(decimal.NewFromFloat(0.8), 100).Equal(decimal.NewFromFloat(0.04)))
+	assert.True(t, successRateStdErr(decimal.NewFromFloat(0.8), 0).IsZero())
+
+	// Close to ±1.96 standard errors for a middling rate and many simulations
+	assert.InDelta(t, 1.96*0.005, successRateMargin(decimal.NewFromFloat(0.5), 10000).InexactFloat64(), 0.0001)
+
+	// Every simulation succeeding still leaves an interval, narrowing as runs are added
+	all20 := successRateMargin(decimal.NewFromInt(1), 20)
+	all200 := successRateMargin(decimal.NewFromInt(1), 200)
+	assert.True(t, all20.IsPositive())
+	assert.True(t, all200.LessThan(all20))
+}
+
+func TestFERSMonteCarloEngine_ExtendsToTolerance(t *testing.T) {
+	run := func(simulations int, tolerance float64, maxSimulations int) *FERSMonteCarloResult {
+		engine := NewFERSMonteCarloEngine(createTestConfig(), nil)
+		engine.SetSimulations(simulations)
+		engine.SetSeed(11)
+		engine.SetTolerance(decimal.NewFromFloat(tolerance), maxSimulations)
+		result, err := engine.RunFERSMonteCarlo(context.Background(), "Test Scenario")
+		require.NoError(t, err)
+		return result
+	}
+
+	// A loose tolerance is already met
+	loose := run(10, 0.2, 0)
+	assert.Equal(t, 10, loose.NumSimulations)
+	assert.True(t, loose.SuccessRateMargin.LessThanOrEqual(decimal.NewFromFloat(0.2)))
+
+	// A tight one adds simulations up to the cap
+	extended := run(8, 0.001, 30)
+	require.Equal(t, 30, extended.NumSimulations)
+	require.Len(t, extended.Simulations, 30)
+	for i, sim := range extended.Simulations {
+		assert.Equal(t, i, sim.SimulationID)
+	}
+
+	// The added paths continue the seed's sequence, so the run matches a fixed run of 30
+	fixed := run(30, 0, 0)
+	assert.True(t, fixed.SuccessRate.Equal(extended.SuccessRate))
+	assert.True(t, fixed.MedianLifetimeIncome.Equal(extended.MedianLifetimeIncome))
+	for i := range fixed.MarketConditions {
+		assert.True(t, fixed.MarketConditions[i].TSPReturns["C"].Equal(extended.MarketConditions[i].TSPReturns["C"]), "path %d", i)
+	}
+}
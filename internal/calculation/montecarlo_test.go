@@ -1,7 +1,13 @@
 package calculation
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/shopspring/decimal"
@@ -416,3 +422,271 @@ func TestMonteCarloErrorHandling(t *testing.T) {
 		t.Error("Expected error when historical data is not loaded")
 	}
 }
+
+func TestMonteCarloSimulatorHistoricalPathIsReproducible(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+	minYear, maxYear, _ := hdm.GetAvailableYears()
+
+	config := MonteCarloConfig{
+		NumSimulations:     20,
+		ProjectionYears:    10,
+		Seed:               42,
+		UseHistorical:      true,
+		AssetAllocation:    map[string]decimal.Decimal{"C": decimal.NewFromInt(1)},
+		WithdrawalStrategy: "fixed_amount",
+		InitialBalance:     decimal.NewFromInt(1000000),
+		AnnualWithdrawal:   decimal.NewFromInt(40000),
+	}
+
+	first, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+	second, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+
+	for i, sim := range first.Simulations {
+		if len(sim.HistoricalPath) != len(sim.YearOutcomes) {
+			t.Fatalf("simulation %d: expected one historical year per simulated year, got %d for %d", i, len(sim.HistoricalPath), len(sim.YearOutcomes))
+		}
+		for y, year := range sim.HistoricalPath {
+			if year < minYear || year > maxYear {
+				t.Errorf("simulation %d: historical year %d outside %d-%d", i, year, minYear, maxYear)
+			}
+			if sim.YearOutcomes[y].HistoricalYear != year {
+				t.Errorf("simulation %d year %d: outcome year %d does not match path %d", i, y+1, sim.YearOutcomes[y].HistoricalYear, year)
+			}
+		}
+		if fmt.Sprint(sim.HistoricalPath) != fmt.Sprint(second.Simulations[i].HistoricalPath) {
+			t.Errorf("simulation %d: same seed produced different paths %v and %v", i, sim.HistoricalPath, second.Simulations[i].HistoricalPath)
+		}
+		if !sim.EndingBalance.Equal(second.Simulations[i].EndingBalance) {
+			t.Errorf("simulation %d: same seed produced different ending balances", i)
+		}
+	}
+}
+
+func TestMonteCarloSimulatorBlockBootstrap(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+	minYear, maxYear, _ := hdm.GetAvailableYears()
+
+	config := MonteCarloConfig{
+		NumSimulations:     10,
+		ProjectionYears:    10,
+		Seed:               42,
+		UseHistorical:      true,
+		BlockLength:        3,
+		AssetAllocation:    map[string]decimal.Decimal{"C": decimal.NewFromInt(1)},
+		WithdrawalStrategy: "fixed_amount",
+		InitialBalance:     decimal.NewFromInt(1000000),
+		AnnualWithdrawal:   decimal.NewFromInt(40000),
+	}
+
+	result, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+	if result.BlockLength != 3 {
+		t.Errorf("Expected the block length recorded, got %d", result.BlockLength)
+	}
+	for i, sim := range result.Simulations {
+		if len(sim.HistoricalPath) != 10 {
+			t.Fatalf("simulation %d: expected 10 historical years, got %d", i, len(sim.HistoricalPath))
+		}
+		// Years within each block are consecutive, wrapping from the last year to the first
+		for y := range sim.HistoricalPath {
+			if y%3 == 0 {
+				continue
+			}
+			want := sim.HistoricalPath[y-1] + 1
+			if want > maxYear {
+				want = minYear
+			}
+			if sim.HistoricalPath[y] != want {
+				t.Errorf("simulation %d year %d: expected %d within the block, got %d", i, y+1, want, sim.HistoricalPath[y])
+			}
+		}
+	}
+}
+
+func TestMonteCarloSimulatorYearlyPercentiles(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+
+	// A withdrawal this large depletes many portfolios partway through
+	config := MonteCarloConfig{
+		NumSimulations:     50,
+		ProjectionYears:    15,
+		Seed:               9,
+		UseHistorical:      false,
+		AssetAllocation:    map[string]decimal.Decimal{"C": decimal.NewFromInt(1)},
+		WithdrawalStrategy: "fixed_amount",
+		InitialBalance:     decimal.NewFromInt(1000000),
+		AnnualWithdrawal:   decimal.NewFromInt(110000),
+	}
+	result, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+
+	if len(result.YearlyPercentiles) != 15 {
+		t.Fatalf("Expected 15 yearly percentile bands, got %d", len(result.YearlyPercentiles))
+	}
+	for i, year := range result.YearlyPercentiles {
+		if year.Year != i+1 {
+			t.Errorf("Expected year %d, got %d", i+1, year.Year)
+		}
+		for _, band := range []PercentileRanges{year.Balance, year.Withdrawal} {
+			if band.P10.GreaterThan(band.P50) || band.P50.GreaterThan(band.P90) {
+				t.Errorf("Year %d: percentiles out of order: %+v", year.Year, band)
+			}
+		}
+	}
+	// The final year's balance band matches the ending balance percentiles, with
+	// depleted portfolios counted as zero
+	final := result.YearlyPercentiles[14].Balance
+	if !final.P50.Equal(result.PercentileRanges.P50) || !final.P10.Equal(result.PercentileRanges.P10) {
+		t.Errorf("Expected the final band %+v to match the ending percentiles %+v", final, result.PercentileRanges)
+	}
+}
+
+func TestMonteCarloSimulatorSameSeedRepeatsRun(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+
+	run := func(useHistorical bool, procs int) []byte {
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+		config := MonteCarloConfig{
+			NumSimulations:     40,
+			ProjectionYears:    20,
+			Seed:               2024,
+			UseHistorical:      useHistorical,
+			BlockLength:        2,
+			AssetAllocation:    map[string]decimal.Decimal{"C": decimal.NewFromFloat(0.6), "F": decimal.NewFromFloat(0.4)},
+			WithdrawalStrategy: "inflation_adjusted",
+			InitialBalance:     decimal.NewFromInt(1000000),
+			AnnualWithdrawal:   decimal.NewFromInt(45000),
+			StartYear:          2025,
+		}
+		result, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+		if err != nil {
+			t.Fatalf("Failed to run simulation: %v", err)
+		}
+		out, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Failed to marshal result: %v", err)
+		}
+		return out
+	}
+
+	for _, useHistorical := range []bool{true, false} {
+		if string(run(useHistorical, 1)) != string(run(useHistorical, runtime.NumCPU())) {
+			t.Errorf("historical=%v: expected the same seed to give identical results regardless of scheduling", useHistorical)
+		}
+	}
+}
+
+func TestMonteCarloSimulatorMonthlySteps(t *testing.T) {
+	testDataPath := t.TempDir()
+	if err := createTestDataFiles(testDataPath); err != nil {
+		t.Fatalf("Failed to create test data files: %v", err)
+	}
+	hdm := NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+
+	config := MonteCarloConfig{
+		NumSimulations:     5,
+		ProjectionYears:    5,
+		Seed:               42,
+		UseHistorical:      true,
+		AssetAllocation:    map[string]decimal.Decimal{"C": decimal.NewFromInt(1)},
+		WithdrawalStrategy: "fixed_amount",
+		InitialBalance:     decimal.NewFromInt(1000000),
+		AnnualWithdrawal:   decimal.NewFromInt(48000),
+	}
+	annual, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+	config.MonthlySteps = true
+	monthly, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+	if !monthly.MonthlySteps {
+		t.Error("Expected monthly steps recorded in the result")
+	}
+
+	for i, sim := range monthly.Simulations {
+		first := sim.YearOutcomes[0]
+		if first.HistoricalYear != annual.Simulations[i].YearOutcomes[0].HistoricalYear {
+			t.Fatalf("simulation %d: expected the same seed to draw the same year", i)
+		}
+		// Spreading an annual return over the months compounds back to it
+		yearReturn, _ := hdm.GetTSPReturn("C", first.HistoricalYear)
+		if math.Abs(first.Return.Sub(yearReturn).InexactFloat64()) > 1e-9 {
+			t.Errorf("simulation %d: expected a %s return, got %s", i, yearReturn, first.Return)
+		}
+		if !first.Withdrawal.Equal(decimal.NewFromInt(48000)) {
+			t.Errorf("simulation %d: expected the year's withdrawal in installments, got %s", i, first.Withdrawal)
+		}
+	}
+
+	// Monthly history, when loaded, replaces the spread-out annual returns
+	var b strings.Builder
+	b.WriteString("Year,Month,Return\n")
+	for _, year := range []int{2020, 2021, 2022, 2023} {
+		for month := 1; month <= 12; month++ {
+			fmt.Fprintf(&b, "%d,%d,0.01\n", year, month)
+		}
+	}
+	for _, fund := range []string{"c", "s", "i", "f", "g"} {
+		if err := os.WriteFile(filepath.Join(testDataPath, "tsp-returns", fund+"-fund-monthly.csv"), []byte(b.String()), 0644); err != nil {
+			t.Fatalf("Failed to write monthly data: %v", err)
+		}
+	}
+	hdm = NewHistoricalDataManager(testDataPath)
+	if err := hdm.LoadAllData(); err != nil {
+		t.Fatalf("Failed to load historical data: %v", err)
+	}
+	result, err := NewMonteCarloSimulator(hdm, config).RunSimulation(config)
+	if err != nil {
+		t.Fatalf("Failed to run simulation: %v", err)
+	}
+	want := math.Pow(1.01, 12) - 1
+	for _, outcome := range result.Simulations[0].YearOutcomes {
+		if math.Abs(outcome.Return.InexactFloat64()-want) > 1e-9 {
+			t.Errorf("Year %d: expected the monthly history's %.6f return, got %s", outcome.Year, want, outcome.Return)
+		}
+	}
+}
@@ -43,24 +43,24 @@ func TestMortalityBasic(t *testing.T) {
 	after := projection[deathYearIdx]
 	next := projection[deathYearIdx+1]
 
-	if !after.IsDeceased["robert"] {
+	if !after.IsDeceased.Get("robert") {
 		t.Fatalf("expected Robert deceased in death year")
 	}
-	if before.IsDeceased["robert"] {
+	if before.IsDeceased.Get("robert") {
 		t.Fatalf("Robert should be alive year before death")
 	}
 
 	// Robert pension and SS should be zero after death year start
-	if after.Pensions["robert"].GreaterThan(decimal.Zero) {
-		t.Fatalf("pension should cease after death; got %s", after.Pensions["robert"])
+	if after.Pensions.Get("robert").GreaterThan(decimal.Zero) {
+		t.Fatalf("pension should cease after death; got %s", after.Pensions.Get("robert"))
 	}
-	if after.SSBenefits["robert"].GreaterThan(decimal.Zero) {
-		t.Fatalf("SS should cease after death; got %s", after.SSBenefits["robert"])
+	if after.SSBenefits.Get("robert").GreaterThan(decimal.Zero) {
+		t.Fatalf("SS should cease after death; got %s", after.SSBenefits.Get("robert"))
 	}
 
 	// Survivor (Dawn) should have SS at least her own prior-year or Robert's whichever higher (simplified rule)
-	if after.SSBenefits["dawn"].LessThan(before.SSBenefits["dawn"]) {
-		t.Fatalf("survivor SS should not decrease; before %s after %s", before.SSBenefits["dawn"], after.SSBenefits["dawn"])
+	if after.SSBenefits.Get("dawn").LessThan(before.SSBenefits.Get("dawn")) {
+		t.Fatalf("survivor SS should not decrease; before %s after %s", before.SSBenefits.Get("dawn"), after.SSBenefits.Get("dawn"))
 	}
 
 	// Filing status should switch the year AFTER death per next_year policy
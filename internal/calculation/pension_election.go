@@ -0,0 +1,122 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// PensionElectionComparison compares taking a participant's external pension as its lump
+// sum, rolled over to the IRA or TSP, against the annuity
+type PensionElectionComparison struct {
+	ParticipantName string          `json:"participantName"`
+	LumpSum         decimal.Decimal `json:"lumpSum"`
+	AnnualAnnuity   decimal.Decimal `json:"annualAnnuity"` // first year, after any survivor reduction
+	Rollover        string          `json:"rollover"`
+
+	// Deterministic projections of each election
+	Annuity        *domain.ScenarioSummary `json:"annuity"`
+	LumpSumSummary *domain.ScenarioSummary `json:"lumpSumSummary"`
+
+	// Lump sum minus annuity: lifetime net income, and retirement savings (TSP and IRAs)
+	// left at the end of the projection
+	LifetimeIncomeDifference decimal.Decimal `json:"lifetimeIncomeDifference"`
+	EndingBalanceDifference  decimal.Decimal `json:"endingBalanceDifference"`
+
+	// Both elections run on shared simulated market paths; nil when not simulated
+	MonteCarlo *FERSMonteCarloComparison `json:"monteCarlo,omitempty"`
+}
+
+// PensionElectionConfig returns a copy of config whose scenarios are the named scenario
+// with the participant's external pension taken as the annuity and as the lump sum, in
+// that order
+func PensionElectionConfig(config *domain.Configuration, scenarioName, participantName string) (*domain.Configuration, error) {
+	var participant *domain.Participant
+	for i := range config.Household.Participants {
+		if config.Household.Participants[i].Name == participantName {
+			participant = &config.Household.Participants[i]
+		}
+	}
+	if participant == nil {
+		return nil, fmt.Errorf("participant '%s' not found", participantName)
+	}
+	if participant.ExternalPension == nil || !participant.ExternalPension.LumpSum.IsPositive() {
+		return nil, fmt.Errorf("participant '%s' has no external pension with a lump_sum offer", participantName)
+	}
+
+	var base *domain.GenericScenario
+	for i := range config.Scenarios {
+		if config.Scenarios[i].Name == scenarioName {
+			base = &config.Scenarios[i]
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("scenario '%s' not found", scenarioName)
+	}
+	if _, ok := base.ParticipantScenarios[participantName]; !ok {
+		return nil, fmt.Errorf("scenario '%s' has no retirement plan for %s", scenarioName, participantName)
+	}
+
+	elect := func(election, label string) domain.GenericScenario {
+		scenario := base.DeepCopy()
+		scenario.Name = fmt.Sprintf("%s (%s)", scenarioName, label)
+		ps := scenario.ParticipantScenarios[participantName]
+		ps.PensionElection = election
+		scenario.ParticipantScenarios[participantName] = ps
+		return *scenario
+	}
+	electionConfig := *config
+	electionConfig.Scenarios = []domain.GenericScenario{
+		elect(domain.PensionElectionAnnuity, "annuity"),
+		elect(domain.PensionElectionLumpSum, "lump sum"),
+	}
+	return &electionConfig, nil
+}
+
+// ComparePensionElection projects both scenarios of a configuration built by
+// PensionElectionConfig and compares the lump sum against the annuity
+func (ce *CalculationEngine) ComparePensionElection(ctx context.Context, electionConfig *domain.Configuration, participantName string) (*PensionElectionComparison, error) {
+	if len(electionConfig.Scenarios) != 2 {
+		return nil, fmt.Errorf("a pension election comparison needs the annuity and lump sum scenarios, got %d", len(electionConfig.Scenarios))
+	}
+	annuity, err := ce.RunGenericScenario(ctx, electionConfig, &electionConfig.Scenarios[0])
+	if err != nil {
+		return nil, fmt.Errorf("annuity projection failed: %w", err)
+	}
+	lumpSum, err := ce.RunGenericScenario(ctx, electionConfig, &electionConfig.Scenarios[1])
+	if err != nil {
+		return nil, fmt.Errorf("lump sum projection failed: %w", err)
+	}
+
+	comparison := &PensionElectionComparison{
+		ParticipantName:          participantName,
+		Rollover:                 domain.PensionRolloverIRA,
+		Annuity:                  annuity,
+		LumpSumSummary:           lumpSum,
+		LifetimeIncomeDifference: lumpSum.TotalLifetimeIncome.Sub(annuity.TotalLifetimeIncome),
+		EndingBalanceDifference:  endingRetirementSavings(lumpSum).Sub(endingRetirementSavings(annuity)),
+	}
+	for i := range electionConfig.Household.Participants {
+		p := &electionConfig.Household.Participants[i]
+		if p.Name != participantName || p.ExternalPension == nil {
+			continue
+		}
+		comparison.LumpSum = p.ExternalPension.LumpSum
+		comparison.AnnualAnnuity, _ = applyExternalSurvivorElection(p.ExternalPension)
+		if p.ExternalPension.LumpSumRollover == domain.PensionRolloverTSP {
+			comparison.Rollover = domain.PensionRolloverTSP
+		}
+	}
+	return comparison, nil
+}
+
+// endingRetirementSavings returns the TSP and IRA balances left in the projection's last year
+func endingRetirementSavings(summary *domain.ScenarioSummary) decimal.Decimal {
+	if len(summary.Projection) == 0 {
+		return decimal.Zero
+	}
+	last := &summary.Projection[len(summary.Projection)-1]
+	return last.TotalTSPBalance().Add(last.GetTotalIRABalance())
+}
@@ -0,0 +1,84 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionExternalPensionLumpSum(t *testing.T) {
+	salary := decimal.NewFromInt(80000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name: "Dawn", BirthDate: time.Date(1962, 3, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: &salary,
+			ExternalPension: &domain.ExternalPension{
+				MonthlyBenefit: decimal.NewFromInt(1000),
+				StartAge:       62,
+				LumpSum:        decimal.NewFromInt(150000),
+			},
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         2,
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Dawn": {ParticipantName: "Dawn", RetirementDate: &retire, SSStartAge: 70},
+	}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	annuity := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, annuity, 2)
+	assert.Equal(t, "12000", annuity[0].Pensions.Get("Dawn").String())
+	assert.True(t, annuity[0].GetTotalIRABalance().IsZero())
+
+	// The scenario's election overrides the plan's: the lump sum is rolled to the IRA and no
+	// annuity is paid
+	ps := scenario.ParticipantScenarios["Dawn"]
+	ps.PensionElection = domain.PensionElectionLumpSum
+	scenario.ParticipantScenarios["Dawn"] = ps
+	lumpSum := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, lumpSum, 2)
+	assert.True(t, lumpSum[0].Pensions.Get("Dawn").IsZero())
+	assert.Equal(t, "157500", lumpSum[0].GetTotalIRABalance().StringFixed(0))
+	assert.True(t, lumpSum[0].FederalTaxableIncome.LessThan(annuity[0].FederalTaxableIncome), "the rollover is not taxable")
+}
+
+func TestPensionElectionConfig(t *testing.T) {
+	config := &domain.Configuration{
+		Household: &domain.Household{Participants: []domain.Participant{
+			{Name: "Dawn", ExternalPension: &domain.ExternalPension{MonthlyBenefit: decimal.NewFromInt(1000), LumpSum: decimal.NewFromInt(150000)}},
+			{Name: "Robert", IsFederal: true},
+		}},
+		Scenarios: []domain.GenericScenario{{
+			Name: "Base",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Dawn":   {ParticipantName: "Dawn", SSStartAge: 67},
+				"Robert": {ParticipantName: "Robert", SSStartAge: 67},
+			},
+		}},
+	}
+
+	electionConfig, err := PensionElectionConfig(config, "Base", "Dawn")
+	require.NoError(t, err)
+	require.Len(t, electionConfig.Scenarios, 2)
+	assert.Equal(t, "Base (annuity)", electionConfig.Scenarios[0].Name)
+	assert.Equal(t, domain.PensionElectionAnnuity, electionConfig.Scenarios[0].ParticipantScenarios["Dawn"].PensionElection)
+	assert.Equal(t, "Base (lump sum)", electionConfig.Scenarios[1].Name)
+	assert.Equal(t, domain.PensionElectionLumpSum, electionConfig.Scenarios[1].ParticipantScenarios["Dawn"].PensionElection)
+	assert.Empty(t, config.Scenarios[0].ParticipantScenarios["Dawn"].PensionElection, "the original scenario is unchanged")
+	assert.Len(t, config.Scenarios, 1)
+
+	_, err = PensionElectionConfig(config, "Base", "Robert")
+	assert.ErrorContains(t, err, "no external pension")
+	_, err = PensionElectionConfig(config, "Missing", "Dawn")
+	assert.ErrorContains(t, err, "not found")
+}
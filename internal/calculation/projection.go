@@ -1,11 +1,13 @@
 package calculation
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/rgehrsitz/rpgo/internal/sequencing"
+	"github.com/rgehrsitz/rpgo/pkg/dateutil"
 	"github.com/shopspring/decimal"
 )
 
@@ -53,6 +55,73 @@ func tspContribForYear(wagesYear decimal.Decimal, contribPct decimal.Decimal, pa
 	}
 }
 
+// participantState carries one participant's balances and benefit status from year to year
+type participantState struct {
+	currentSalary              decimal.Decimal
+	retired                    bool
+	retirementYear             *int
+	retirementDate             *time.Time
+	pensionAnnual              decimal.Decimal
+	pensionStartYear           *int
+	pensionStartDate           *time.Time // deferred annuity commencement (nil = retirement date)
+	deferredAnnuityAge         int        // >0 when separating early for a deferred annuity
+	pensionLumpSum             bool       // external pension taken as a lump sum at retirement
+	survivorPension            decimal.Decimal
+	survivorPensionIncome      decimal.Decimal
+	survivorPensionLastUpdated int
+	survivorPensionDistributed bool
+	ssStartAge                 int
+	ssStarted                  bool
+	ssAnnualFull               decimal.Decimal
+	ssStartYear                *int
+	spousalAnnual              decimal.Decimal
+	spousalStartYear           *int
+	survivorSSBase             decimal.Decimal // deceased spouse's benefit, COLA-adjusted
+	survivorSSBaseSet          bool
+	survivorSSAnnual           decimal.Decimal
+	survivorSSStartYear        *int
+	tspBalance                 decimal.Decimal // total (legacy)
+	tspBalanceTraditional      decimal.Decimal // new split tracking
+	tspBalanceRoth             decimal.Decimal // new split tracking
+	taxableBalance             decimal.Decimal // taxable brokerage aggregate per participant
+	taxableBasis               decimal.Decimal // cost basis
+	iraBalanceTraditional      decimal.Decimal // IRAs outside the TSP
+	iraBalanceRoth             decimal.Decimal
+	planBalanceTraditional     decimal.Decimal // non-federal employer plan: employee pre-tax deferrals
+	planBalanceRoth            decimal.Decimal // employee Roth deferrals
+	planBalanceEmployer        decimal.Decimal // employer contributions, subject to vesting
+	planSeparated              bool            // employer plan rolled over at separation
+	tspWithdrawalBase          decimal.Decimal
+	fehbPremium                decimal.Decimal
+	fehbForfeited              bool // FEHB ended at retirement under the five-year rule
+	fersSupplementAnnual       decimal.Decimal
+	fersSupplementStartYear    *int
+	specialProvisionRetiree    bool            // COLAs apply before age 62
+	csrsOffsetAnnual           decimal.Decimal // CSRS Offset reduction taken from the annuity at 62
+	csrsOffsetApplied          bool
+	fehbRetroactiveDue         bool            // one month of FEHB premiums owed once the annuity is paid
+	fehbSurvivorAdjusted       bool            // FEHB enrollment already adjusted for a spouse's death
+	fehbSelfOnly               bool            // FEHB enrollment dropped to self only at a spouse's death
+	tspAnnuityAnnual           decimal.Decimal // TSP life annuity payments for a full year
+	tspAnnuitySurvivorPercent  decimal.Decimal // share of the payment continuing to the spouse
+	tspAnnuityIncreasing       bool
+	tspAnnuityYear             int // projection year of the purchase
+	tspAnnuityPurchased        bool
+	incomeAnnuities            []incomeAnnuity // purchased income annuities in force
+	seppAnnual                 decimal.Decimal // locked SEPP payment, zero until the schedule starts
+	seppLockEnd                time.Time       // payments may not change before this date
+	seppDistributions          decimal.Decimal // SEPP payments taken before 59½
+	seppBroken                 bool
+	rothTSP                    rothLedger // contribution basis and conversions of the Roth TSP
+	rothIRA                    rothLedger
+	rothTSPDistributed         decimal.Decimal // Roth TSP distributions of the year, not yet settled
+	rothIRADistributed         decimal.Decimal
+	guardrailWithdrawal        decimal.Decimal // last full-year guardrails withdrawal, zero until the first
+	lastTSPReturn              decimal.Decimal // TSP return of the previous year
+	bucketWithdrawal           decimal.Decimal // last full-year buckets withdrawal, zero until the first
+	buckets                    domain.BucketBalances
+}
+
 // SSMonthsPaidInYear returns the number of benefit payments in `year` if claiming at `claimAgeYears`
 // Rule: first payment is the month AFTER the claim month (SSA timing)
 func SSMonthsPaidInYear(dob time.Time, claimAgeYears int, year int) int {
@@ -67,19 +136,46 @@ func SSMonthsPaidInYear(dob time.Time, claimAgeYears int, year int) int {
 	return 12 - int(firstPaid.Month()) + 1
 }
 
+// syncTSPSplit scales the traditional and Roth TSP balances to the total after
+// contributions, growth and transfers, which are tracked only in the total. A balance with
+// no split yet is traditional.
+func (st *participantState) syncTSPSplit() {
+	split := st.tspBalanceTraditional.Add(st.tspBalanceRoth)
+	if !split.IsPositive() {
+		st.tspBalanceTraditional, st.tspBalanceRoth = st.tspBalance, decimalZero
+		return
+	}
+	st.tspBalanceTraditional = st.tspBalanceTraditional.Mul(st.tspBalance).Div(split)
+	st.tspBalanceRoth = st.tspBalance.Sub(st.tspBalanceTraditional)
+}
+
 // GenerateAnnualProjectionGeneric produces a projection for the generic participant model.
 func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions, federalRules domain.FederalRules) []domain.AnnualCashFlow {
+	if ce != nil && ce.TaxCalc != nil && autoConversionParticipant(household, scenario) != "" {
+		return ce.fillBracketsWithConversions(household, scenario, assumptions, federalRules)
+	}
+	if ce != nil && ce.solvedWithdrawals == nil && usesSpendingTarget(scenario) {
+		return ce.solveSpendingTarget(household, scenario, assumptions, federalRules)
+	}
+	return ce.projectWithinMAGICeiling(household, scenario, assumptions, federalRules)
+}
+
+// generateAnnualProjection projects the scenario year by year
+func (ce *CalculationEngine) generateAnnualProjection(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions, federalRules domain.FederalRules) []domain.AnnualCashFlow {
 	if household == nil || assumptions == nil || len(household.Participants) == 0 {
 		return nil
 	}
 
+	defer ce.startTiming(scenario, TimingProjection)()
+
+	household = withoutDeclinedMilitaryDeposits(household, scenario)
 	participantNames := make([]string, len(household.Participants))
 	for i, p := range household.Participants {
 		participantNames[i] = p.Name
 	}
 
 	startYear := ProjectionBaseYear
-	years := assumptions.ProjectionYears
+	years := ScenarioProjectionYears(scenario, assumptions)
 	if years <= 0 {
 		years = 1
 	}
@@ -148,32 +244,6 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 	}
 	secondTierMatchRate := decimal.NewFromFloat(0.5)
 
-	type participantState struct {
-		currentSalary              decimal.Decimal
-		retired                    bool
-		retirementYear             *int
-		retirementDate             *time.Time
-		pensionAnnual              decimal.Decimal
-		pensionStartYear           *int
-		survivorPension            decimal.Decimal
-		survivorPensionIncome      decimal.Decimal
-		survivorPensionLastUpdated int
-		survivorPensionDistributed bool
-		ssStartAge                 int
-		ssStarted                  bool
-		ssAnnualFull               decimal.Decimal
-		ssStartYear                *int
-		tspBalance                 decimal.Decimal // total (legacy)
-		tspBalanceTraditional      decimal.Decimal // new split tracking
-		tspBalanceRoth             decimal.Decimal // new split tracking
-		taxableBalance             decimal.Decimal // taxable brokerage aggregate per participant
-		taxableBasis               decimal.Decimal // cost basis
-		tspWithdrawalBase          decimal.Decimal
-		fehbPremium                decimal.Decimal
-		fersSupplementAnnual       decimal.Decimal
-		fersSupplementStartYear    *int
-	}
-
 	states := make(map[string]*participantState, len(household.Participants))
 	for i := range household.Participants {
 		p := &household.Participants[i]
@@ -193,10 +263,23 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 				*st.retirementYear = ry
 				st.retirementDate = ps.RetirementDate
 			}
+			if ps.SeparationDate != nil && ps.AnnuityStartAge > 0 {
+				ry := ps.SeparationDate.Year() - startYear
+				if ry < 0 {
+					ry = 0
+				}
+				st.retirementYear = new(int)
+				*st.retirementYear = ry
+				st.retirementDate = ps.SeparationDate
+				st.deferredAnnuityAge = ps.AnnuityStartAge
+			}
 			if ps.SSStartAge >= 62 && ps.SSStartAge <= 70 {
 				st.ssStartAge = ps.SSStartAge
 			}
 		}
+		if p.ExternalPension != nil {
+			st.pensionLumpSum = p.ExternalPension.ElectsLumpSum(psMap[p.Name].PensionElection)
+		}
 
 		if st.retirementYear == nil && p.EmploymentEndDate != nil {
 			ry := p.EmploymentEndDate.Year() - startYear
@@ -224,12 +307,46 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 		if p.TaxableAccountBasis != nil {
 			st.taxableBasis = st.taxableBasis.Add(*p.TaxableAccountBasis)
 		}
+		if p.IRABalanceTraditional != nil {
+			st.iraBalanceTraditional = *p.IRABalanceTraditional
+		}
+		if p.IRABalanceRoth != nil {
+			st.iraBalanceRoth = *p.IRABalanceRoth
+		}
+		st.rothTSP = newRothLedger(p.TSPBalanceRoth, p.TSPRothBasis, p.TSPRothStartYear)
+		st.rothIRA = newRothLedger(p.IRABalanceRoth, p.IRARothBasis, p.IRARothStartYear)
+		st.initEmployerPlan(p.EmployerPlan)
 		st.tspBalance = st.tspBalanceTraditional.Add(st.tspBalanceRoth)
 
 		if p.IsPrimaryFEHBHolder && p.FEHBPremiumPerPayPeriod != nil {
 			st.fehbPremium = p.FEHBPremiumPerPayPeriod.Mul(decimal.NewFromInt(26))
 		}
 
+		if cr := p.CurrentRetirement; cr != nil {
+			// Already retired: benefits in payment replace the salary and annuity computation
+			first := 0
+			retirementDate := cr.RetirementDate
+			retirementYear := retirementDate.Year() - startYear // before the projection, so never matched as the retirement year
+			st.currentSalary = decimalZero
+			st.retired = true
+			st.retirementYear = &retirementYear
+			st.retirementDate = &retirementDate
+			st.pensionStartYear = &first
+			st.pensionAnnual = cr.AnnualAnnuity
+			st.survivorPension = cr.SurvivorAnnuity
+			st.tspWithdrawalBase = st.tspBalance
+			st.specialProvisionRetiree = p.IsSpecialProvision()
+			if cr.FERSSupplement.IsPositive() {
+				st.fersSupplementAnnual = cr.FERSSupplement
+				st.fersSupplementStartYear = &first
+			}
+			if cr.SSMonthlyBenefit.IsPositive() {
+				st.ssStarted = true
+				st.ssAnnualFull = cr.SSMonthlyBenefit.Mul(decimalTwelve)
+				st.ssStartYear = &first
+			}
+		}
+
 		states[p.Name] = st
 	}
 
@@ -268,41 +385,138 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 	}
 
 	cola := assumptions.COLAGeneralRate
+	// Social Security COLAs floor at 0%; a negative CPI still applies to salaries and expenses
+	ssCOLA := SSCOLARate(cola)
 	infl := assumptions.InflationRate
 	fehbInfl := assumptions.FEHBPremiumInflation
 	preRetReturn := assumptions.TSPReturnPreRetirement
 	postRetReturn := assumptions.TSPReturnPostRetirement
+	var fundReturns map[string]decimal.Decimal
+	if assumptions.TSPReturnModel == TSPReturnModelFund {
+		fundReturns = ce.expectedFundReturns(assumptions)
+	}
+	// The buckets strategy holds its cash bucket in the G fund and its bonds in the F fund
+	var bucketCashReturn, bucketBondReturn decimal.Decimal
+	if usesBuckets(scenario) {
+		bucketReturns := fundReturns
+		if bucketReturns == nil && ce != nil {
+			bucketReturns = ce.expectedFundReturns(assumptions)
+		}
+		bucketCashReturn, bucketBondReturn = bucketReturns["G"], bucketReturns["F"]
+	}
+	// Participants residing in different states are taxed by their own states
+	homeState, _ := domain.NormalizeState(assumptions.CurrentLocation.State)
+	residents := residentStates(household, assumptions.CurrentLocation.State)
+	stateRules := normalizedStateRules(federalRules.StateLocalTaxConfig.StateRules)
+
+	var phaseParticipant *domain.Participant
+	if scenario != nil && scenario.SpendingPhases != nil && len(scenario.SpendingPhases.Phases) > 0 {
+		phaseParticipant = spendingPhaseParticipant(household, scenario.SpendingPhases)
+	}
 
 	projection := make([]domain.AnnualCashFlow, years)
+	itemizedGrowth := decimalOne // cumulative inflation applied to itemized deduction amounts and essential expenses
+	// One participant index is shared by every year's per-participant values
+	participantIndex := domain.NewParticipantIndex(participantNames)
+
+	// Years before the scenario's first decision match every other scenario of this
+	// household; resume from the longest cached run of them
+	firstYr := 0
+	sharedYears := 0
+	cacheKey := ""
+	// A simulated market path is seldom projected twice, so it isn't worth caching
+	if ce != nil && ce.ProjectionCache != nil && len(assumptions.MarketPath) == 0 {
+		sharedYears = scenarioDivergenceYear(household, scenario, states, deathYears, startYear, years, ce.AdditionalOrdinaryIncome)
+		if sharedYears > 0 {
+			cacheKey = projectionCacheKey(ce, household, scenario, assumptions, federalRules)
+		}
+		if cacheKey != "" {
+			if prefix := ce.ProjectionCache.longestPrefix(cacheKey, sharedYears); prefix != nil {
+				firstYr = prefix.restore(projection, states, participantIndex)
+				itemizedGrowth = prefix.itemizedGrowth
+			}
+		}
+	}
+
+	// Conversions and sequenced withdrawals are clipped to the scenario's MAGI ceiling
+	magiCeiling := magiCeilingAmount(scenario, household)
+
+	// Running tax totals continue from the last restored year
+	var cumulativeTaxes domain.TaxTotals
+	if firstYr > 0 {
+		cumulativeTaxes = projection[firstYr-1].CumulativeTaxes
+	}
 
-	for yr := 0; yr < years; yr++ {
+	for yr := firstYr; yr < years; yr++ {
+		if cacheKey != "" && yr == sharedYears && yr > firstYr {
+			ce.ProjectionCache.storePrefix(cacheKey, newProjectionPrefix(projection[:yr], states, itemizedGrowth, participantNames))
+		}
 		yearDate := time.Date(startYear+yr, 1, 1, 0, 0, 0, 0, time.UTC)
 		yearEnd := time.Date(startYear+yr, 12, 31, 23, 59, 59, 0, time.UTC)
-		cf := domain.NewAnnualCashFlow(yr, yearDate, participantNames)
+		// A simulated market path replaces the assumed rates year by year
+		var monthlyReturns []map[string]decimal.Decimal
+		if yr < len(assumptions.MarketPath) {
+			market := assumptions.MarketPath[yr]
+			cola, infl, fehbInfl = market.COLARate, market.InflationRate, market.FEHBInflation
+			ssCOLA = SSCOLARate(cola)
+			pathReturns := fundReturnMap(market.FundReturns)
+			if fundReturns != nil {
+				fundReturns = pathReturns
+				if len(market.MonthlyFundReturns) == 12 {
+					for _, month := range market.MonthlyFundReturns {
+						monthlyReturns = append(monthlyReturns, fundReturnMap(month))
+					}
+				}
+			}
+			if usesBuckets(scenario) {
+				bucketCashReturn, bucketBondReturn = pathReturns["G"], pathReturns["F"]
+			}
+		}
+		cf := domain.NewAnnualCashFlowWithIndex(yr, yearDate, participantIndex)
+		periods := periodsPerYear(assumptions, yr)
+		if periods > 1 {
+			cf.PeriodTSPBalances = make([]decimal.Decimal, periods)
+		}
 		transferPool := decimalZero
+		// IRAs of a participant dying this year, which the survivor treats as their own
+		iraTransferTraditional, iraTransferRoth := decimalZero, decimalZero
+		depositTaxableIncome := decimalZero // traditional TSP money withdrawn to pay military deposits
+		eventTaxableIncome := decimalZero   // ordinary income of cash flow events and of traditional money paying them
+		// Income streams by tax character, and the self-employment tax on the earned income
+		selfEmploymentIncome, passiveIncome, selfEmploymentTax := decimalZero, decimalZero, decimalZero
 		aliveNames := aliveParticipantsForYear(household, deathYears, yr)
 		singleSurvivorName := ""
 		if len(aliveNames) == 1 {
 			singleSurvivorName = aliveNames[0]
 		}
+		// Spending phases scale spending-driven withdrawals and the spending target
+		phaseFactor := decimalOne
+		if phaseParticipant != nil {
+			cf.SpendingPhase, phaseFactor = spendingPhaseAt(scenario.SpendingPhases.Phases, phaseParticipant.Age(yearEnd))
+			cf.SpendingFactor = phaseFactor.Round(4)
+		}
 
 		for i := range household.Participants {
 			p := &household.Participants[i]
 			st := states[p.Name]
 
 			if st.fehbPremium.GreaterThan(decimalZero) && yr > 0 {
-				st.fehbPremium = st.fehbPremium.Mul(onePlus(fehbInfl))
+				growth := fehbInfl
+				if p.FEHBPremiumGrowth != nil {
+					growth = *p.FEHBPremiumGrowth
+				}
+				st.fehbPremium = st.fehbPremium.Mul(onePlus(growth))
 			}
 
 			age := p.Age(yearDate)
 			ageEnd := p.Age(yearEnd)
-			cf.Ages[p.Name] = age
+			cf.Ages.Set(p.Name, age)
 			if st.survivorPensionIncome.GreaterThan(decimalZero) {
 				if st.survivorPensionLastUpdated < yr {
 					st.survivorPensionIncome = st.survivorPensionIncome.Mul(onePlus(cola))
 					st.survivorPensionLastUpdated = yr
 				}
-				cf.SurvivorPensions[p.Name] = st.survivorPensionIncome
+				cf.SurvivorPensions.Set(p.Name, st.survivorPensionIncome)
 			}
 
 			var deathIdx *int
@@ -311,9 +525,14 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 			}
 
 			isDeceased := deathIdx != nil && yr >= *deathIdx
-			cf.IsDeceased[p.Name] = isDeceased
+			cf.IsDeceased.Set(p.Name, isDeceased)
 
 			if isDeceased {
+				if st.deferredAnnuityAge > 0 && st.pensionStartYear != nil && yr < *st.pensionStartYear {
+					// Death before a deferred annuity begins forfeits the annuity and survivor benefit
+					st.pensionAnnual = decimalZero
+					st.survivorPension = decimalZero
+				}
 				if !st.survivorPensionDistributed && st.survivorPension.GreaterThan(decimalZero) {
 					if len(aliveNames) > 0 {
 						share := st.survivorPension
@@ -324,23 +543,166 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 							recipientState := states[name]
 							recipientState.survivorPensionIncome = recipientState.survivorPensionIncome.Add(share)
 							recipientState.survivorPensionLastUpdated = yr
-							cf.SurvivorPensions[name] = cf.SurvivorPensions[name].Add(share)
+							cf.SurvivorPensions.Set(name, cf.SurvivorPensions.Get(name).Add(share))
 						}
 					}
 					st.survivorPensionDistributed = true
 					st.survivorPension = decimalZero
 				}
+				if st.tspAnnuityAnnual.IsPositive() {
+					// A joint life TSP annuity continues its survivor share to the spouse
+					survivorPayment := st.advanceTSPAnnuity(yr, infl).Mul(st.tspAnnuitySurvivorPercent)
+					if len(aliveNames) == 0 || !survivorPayment.IsPositive() {
+						st.tspAnnuityAnnual = decimalZero
+					} else {
+						share := survivorPayment.Div(decimal.NewFromInt(int64(len(aliveNames))))
+						for _, name := range aliveNames {
+							cf.TSPAnnuities.Set(name, cf.TSPAnnuities.Get(name).Add(share))
+						}
+					}
+				}
+				if len(st.incomeAnnuities) > 0 {
+					// Purchased annuities continue their survivor share to the spouse
+					for i := range st.incomeAnnuities {
+						st.incomeAnnuities[i].survivorOnly = true
+					}
+					payments, taxFree := st.payIncomeAnnuities(yr, len(aliveNames) > 0)
+					if payments.IsPositive() {
+						share := payments.Div(decimal.NewFromInt(int64(len(aliveNames))))
+						for _, name := range aliveNames {
+							cf.IncomeAnnuities.Set(name, cf.IncomeAnnuities.Get(name).Add(share))
+						}
+						cf.IncomeAnnuityTaxFree = cf.IncomeAnnuityTaxFree.Add(taxFree)
+					}
+				}
+				if len(p.OtherIncome) > 0 && len(aliveNames) > 0 {
+					// Passive income, such as rent from property, passes to the survivors
+					_, passive := otherIncomeForYear(p.OtherIncome, startYear, yr, infl)
+					share := passive.Div(decimal.NewFromInt(int64(len(aliveNames))))
+					for _, name := range aliveNames {
+						cf.OtherIncome.Set(name, cf.OtherIncome.Get(name).Add(share))
+					}
+					passiveIncome = passiveIncome.Add(passive)
+				}
 				if tspTransferMode == "merge" && deathIdx != nil && yr == *deathIdx && st.tspBalance.GreaterThan(decimalZero) {
 					transferPool = transferPool.Add(st.tspBalance)
 				}
+				if tspTransferMode == "merge" && deathIdx != nil && yr == *deathIdx {
+					// An employer plan passes to the beneficiary with employer money fully vested
+					iraTransferTraditional = iraTransferTraditional.Add(st.iraBalanceTraditional).Add(st.planBalanceTraditional).Add(st.planBalanceEmployer)
+					iraTransferRoth = iraTransferRoth.Add(st.iraBalanceRoth).Add(st.planBalanceRoth)
+				}
 				st.tspBalance = decimalZero
-				cf.Salaries[p.Name] = decimalZero
-				cf.Pensions[p.Name] = decimalZero
-				cf.SSBenefits[p.Name] = decimalZero
-				cf.TSPBalances[p.Name] = decimalZero
+				st.iraBalanceTraditional, st.iraBalanceRoth = decimalZero, decimalZero
+				st.planBalanceTraditional, st.planBalanceRoth, st.planBalanceEmployer = decimalZero, decimalZero, decimalZero
+				cf.Salaries.Set(p.Name, decimalZero)
+				cf.Pensions.Set(p.Name, decimalZero)
+				cf.SSBenefits.Set(p.Name, decimalZero)
+				cf.TSPBalances.Set(p.Name, decimalZero)
 				continue
 			}
 
+			if md := p.MilitaryDeposit; md != nil && md.Amount.IsPositive() && startYear+yr == militaryDepositYear(md, startYear) {
+				// The deposit is paid from savings: selling taxable investments realizes gains,
+				// while a TSP in-service withdrawal of traditional money is ordinary income
+				remaining := md.Amount
+				if md.PaidFrom == domain.MilitaryDepositFromTSP {
+					fromTraditional := decimal.Min(remaining, st.tspBalanceTraditional)
+					st.tspBalanceTraditional = st.tspBalanceTraditional.Sub(fromTraditional)
+					depositTaxableIncome = depositTaxableIncome.Add(fromTraditional)
+					fromRoth := decimal.Min(remaining.Sub(fromTraditional), st.tspBalanceRoth)
+					st.tspBalanceRoth = st.tspBalanceRoth.Sub(fromRoth)
+					st.rothTSPDistributed = st.rothTSPDistributed.Add(fromRoth)
+					st.tspBalance = st.tspBalanceTraditional.Add(st.tspBalanceRoth)
+					remaining = remaining.Sub(fromTraditional).Sub(fromRoth)
+				} else if st.taxableBalance.IsPositive() {
+					sold := decimal.Min(remaining, st.taxableBalance)
+					basisUsed := st.taxableBasis.Mul(sold).Div(st.taxableBalance)
+					cf.CapitalGainsRealized = cf.CapitalGainsRealized.Add(sold.Sub(basisUsed))
+					st.taxableBasis = st.taxableBasis.Sub(basisUsed)
+					st.taxableBalance = st.taxableBalance.Sub(sold)
+					remaining = remaining.Sub(sold)
+				}
+				cf.MilitaryDeposits = cf.MilitaryDeposits.Add(md.Amount.Sub(remaining))
+				if remaining.GreaterThanOrEqual(decimalOne) {
+					cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+						Year:        startYear + yr,
+						Participant: p.Name,
+						Code:        domain.WarningDepositShortfall,
+						Amount:      remaining,
+						Message: fmt.Sprintf("%s: military deposit of $%s exceeded the %s balance; $%s unpaid",
+							p.Label(), md.Amount.StringFixed(0), militaryDepositSource(md), remaining.StringFixed(0)),
+					})
+				}
+			}
+
+			if scenario != nil {
+				for _, e := range scenario.CashFlowEvents {
+					if e.Date.Year() != startYear+yr || cashFlowEventOwner(e, household, aliveNames) != p.Name {
+						continue
+					}
+					moved, ordinary, gains := st.applyCashFlowEvent(e)
+					if e.Inflow() {
+						cf.OneTimeInflows = cf.OneTimeInflows.Add(moved)
+						if e.TaxTreatment == domain.CashFlowCapitalGain {
+							gains = e.TaxedAmount()
+						} else {
+							ordinary = e.TaxedAmount()
+						}
+					} else {
+						cf.OneTimeOutflows = cf.OneTimeOutflows.Add(moved)
+						if e.Amount.Sub(moved).GreaterThanOrEqual(decimalOne) {
+							cf.Warnings = append(cf.Warnings, outflowShortfallWarning(p, e, moved, startYear+yr))
+						}
+					}
+					eventTaxableIncome = eventTaxableIncome.Add(ordinary)
+					cf.CapitalGainsRealized = cf.CapitalGainsRealized.Add(gains)
+				}
+			}
+
+			if ps, ok := psMap[p.Name]; ok && ps.TSPAnnuity != nil && !st.tspAnnuityPurchased {
+				if date, ok := tspAnnuityPurchaseDate(ps.TSPAnnuity, p, st, startYear); ok && max(date.Year(), startYear) == startYear+yr {
+					if date.Year() < startYear {
+						date = yearDate
+					}
+					purchased := st.purchaseTSPAnnuity(ps.TSPAnnuity, p.Age(date), yr)
+					cf.TSPAnnuityPurchases = cf.TSPAnnuityPurchases.Add(purchased)
+					st.tspAnnuityPurchased = true
+					if purchased.IsPositive() {
+						// Monthly payments begin the month after the purchase
+						months := decimal.NewFromInt(int64(12 - int(date.Month())))
+						cf.TSPAnnuities.Set(p.Name, cf.TSPAnnuities.Get(p.Name).Add(st.tspAnnuityAnnual.Mul(months).Div(decimal.NewFromInt(12))))
+					}
+				}
+			} else if st.tspAnnuityAnnual.IsPositive() {
+				cf.TSPAnnuities.Set(p.Name, cf.TSPAnnuities.Get(p.Name).Add(st.advanceTSPAnnuity(yr, infl)))
+			}
+
+			for _, purchase := range psMap[p.Name].AnnuityPurchases {
+				date := p.BirthDate.AddDate(purchase.PurchaseAge, 0, 0)
+				if max(date.Year(), startYear) != startYear+yr {
+					continue
+				}
+				if date.Year() < startYear {
+					date = yearDate
+				}
+				paid, gains := st.purchaseIncomeAnnuity(purchase, p, date, startYear)
+				cf.IncomeAnnuityPremiums = cf.IncomeAnnuityPremiums.Add(paid)
+				cf.CapitalGainsRealized = cf.CapitalGainsRealized.Add(gains)
+				if purchase.Premium.Sub(paid).GreaterThanOrEqual(decimalOne) {
+					cf.Warnings = append(cf.Warnings, premiumShortfallWarning(p, purchase, paid, startYear+yr))
+				}
+			}
+			if len(st.incomeAnnuities) > 0 {
+				payments, taxFree := st.payIncomeAnnuities(yr, true)
+				cf.IncomeAnnuities.Set(p.Name, cf.IncomeAnnuities.Get(p.Name).Add(payments))
+				cf.IncomeAnnuityTaxFree = cf.IncomeAnnuityTaxFree.Add(taxFree)
+			}
+
+			tspStartOfYear := st.tspBalance
+			// IRA RMDs are based on the prior year-end balance, before this year's contributions
+			iraRMD := NewRMDCalculator(p.BirthDate.Year()).CalculateRMD(st.iraBalanceTraditional, age)
+
 			if st.retirementYear == nil || yr <= *st.retirementYear {
 				if yr > 0 && st.currentSalary.GreaterThan(decimalZero) {
 					st.currentSalary = st.currentSalary.Mul(onePlus(cola))
@@ -362,7 +724,11 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 					workFraction = decimalZero
 				}
 			}
-			cf.Salaries[p.Name] = salaryForYear
+			cf.Salaries.Set(p.Name, salaryForYear)
+			if p.IsFederal && st.fehbPremium.GreaterThan(decimalZero) && workFraction.GreaterThan(decimalZero) {
+				// Premium conversion: FEHB premiums withheld from pay are pre-tax
+				cf.FEHBPreTaxPremium = cf.FEHBPreTaxPremium.Add(st.fehbPremium.Mul(workFraction))
+			}
 
 			// Calculate part-time work impact
 			var participantScenario domain.ParticipantScenario
@@ -377,7 +743,7 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 				*p,
 				participantScenario,
 				startYear+yr,
-				cf.Ages[p.Name],
+				cf.Ages.Get(p.Name),
 			)
 			if err != nil {
 				// Log error and continue with default values
@@ -388,18 +754,29 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 			}
 
 			// Update cash flow with part-time work data
-			cf.IsPartTime[p.Name] = partTimeAnalysis.IsPartTime
+			cf.IsPartTime.Set(p.Name, partTimeAnalysis.IsPartTime)
 			if partTimeAnalysis.IsPartTime {
-				cf.PartTimeSalary[p.Name] = partTimeAnalysis.AnnualSalary
-				cf.PartTimeTSPContributions[p.Name] = partTimeAnalysis.TSPContributions
-				cf.FERSSupplementReduction[p.Name] = partTimeAnalysis.FERSSupplementReduction
+				cf.PartTimeSalary.Set(p.Name, partTimeAnalysis.AnnualSalary)
+				cf.PartTimeTSPContributions.Set(p.Name, partTimeAnalysis.TSPContributions)
+				cf.FERSSupplementReduction.Set(p.Name, partTimeAnalysis.FERSSupplementReduction)
 
 				// Override salary with part-time salary if working part-time
-				cf.Salaries[p.Name] = partTimeAnalysis.AnnualSalary
+				cf.Salaries.Set(p.Name, partTimeAnalysis.AnnualSalary)
 			} else {
-				cf.PartTimeSalary[p.Name] = decimalZero
-				cf.PartTimeTSPContributions[p.Name] = decimalZero
-				cf.FERSSupplementReduction[p.Name] = decimalZero
+				cf.PartTimeSalary.Set(p.Name, decimalZero)
+				cf.PartTimeTSPContributions.Set(p.Name, decimalZero)
+				cf.FERSSupplementReduction.Set(p.Name, decimalZero)
+			}
+
+			if len(p.OtherIncome) > 0 {
+				earned, passive := otherIncomeForYear(p.OtherIncome, startYear, yr, infl)
+				cf.OtherIncome.Set(p.Name, cf.OtherIncome.Get(p.Name).Add(earned).Add(passive))
+				cf.SelfEmploymentIncome.Set(p.Name, earned)
+				selfEmploymentIncome = selfEmploymentIncome.Add(earned)
+				passiveIncome = passiveIncome.Add(passive)
+				if ce != nil && ce.TaxCalc != nil {
+					selfEmploymentTax = selfEmploymentTax.Add(ce.TaxCalc.FICATaxCalc.CalculateSelfEmploymentTax(earned, cf.Salaries.Get(p.Name)))
+				}
 			}
 
 			retiredThisYear := st.retirementYear != nil && yr == *st.retirementYear
@@ -422,26 +799,65 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 				*startYr = yr
 				st.pensionStartYear = startYr
 
-				if p.IsFederal && p.High3Salary != nil && p.HireDate != nil {
+				if st.deferredAnnuityAge > 0 {
+					// Deferred annuity: no FEHB continuation, no supplement, pension begins at the start age
+					pension, survivor := calculateDeferredParticipantPension(p, *st.retirementDate, st.deferredAnnuityAge)
+					st.pensionAnnual = pension
+					st.survivorPension = survivor
+					st.fehbPremium = decimalZero
+					annuityStart := p.BirthDate.AddDate(st.deferredAnnuityAge, 0, 0)
+					st.pensionStartDate = &annuityStart
+					deferredYr := annuityStart.Year() - startYear
+					if deferredYr < yr {
+						deferredYr = yr
+					}
+					st.pensionStartYear = &deferredYr
+				} else if p.IsFederal && p.High3Salary != nil && p.HireDate != nil {
 					pension, survivor := calculateParticipantPension(p, *st.retirementDate)
 					st.pensionAnnual = pension
 					st.survivorPension = survivor
+					if st.fehbPremium.IsPositive() && !FEHBContinuesIntoRetirement(p, *st.retirementDate) {
+						// Too few years of coverage: FEHB ends at retirement
+						st.fehbPremium = decimalZero
+						st.fehbForfeited = true
+						cf.Warnings = append(cf.Warnings, fehbFiveYearRuleWarning(p, *st.retirementDate))
+					}
+					// FEHB moves from payroll to annuity withholding; premiums for the interim
+					// pay period are collected retroactively from the first annuity payments
+					st.fehbRetroactiveDue = st.fehbPremium.GreaterThan(decimalZero)
 
-					// Calculate FERS Special Retirement Supplement if eligible
 					retirementAge := p.Age(*st.retirementDate)
-					if retirementAge < 62 && p.SSBenefit62.GreaterThan(decimalZero) {
-						serviceYears := p.YearsOfService(*st.retirementDate)
-						st.fersSupplementAnnual = CalculateFERSSpecialRetirementSupplement(p.SSBenefit62, serviceYears, retirementAge)
-						st.fersSupplementStartYear = startYr
+					serviceYears := p.YearsOfService(*st.retirementDate)
+					if p.IsCSRS() {
+						// CSRS annuitants get no supplement; CSRS Offset annuities are reduced at 62
+						if p.RetirementSystem == domain.RetirementSystemCSRSOffset {
+							st.csrsOffsetAnnual = CSRSOffsetReduction(p.SSBenefit62, serviceYears)
+						}
+					} else {
+						// Calculate FERS Special Retirement Supplement if eligible: from the MRA, or
+						// immediately for special provision retirees
+						st.specialProvisionRetiree = p.IsSpecialProvision() && SpecialProvisionEligible(retirementAge, serviceYears)
+						supplementEligible := st.specialProvisionRetiree || retirementAge >= dateutil.MinimumRetirementAge(p.BirthDate)
+						if retirementAge < 62 && supplementEligible && p.SSBenefit62.GreaterThan(decimalZero) {
+							st.fersSupplementAnnual = CalculateFERSSpecialRetirementSupplement(p.SSBenefit62, serviceYears, retirementAge)
+							st.fersSupplementStartYear = startYr
+						}
 					}
+				} else if p.ExternalPension != nil && st.pensionLumpSum {
+					st.rollOverPensionLumpSum(p.ExternalPension)
 				} else if p.ExternalPension != nil {
-					st.pensionAnnual = p.ExternalPension.MonthlyBenefit.Mul(decimalTwelve)
+					st.pensionAnnual, st.survivorPension = applyExternalSurvivorElection(p.ExternalPension)
 				}
 			}
 
+			// Employee deferrals, including part-time contributions, stop at the IRS limit
+			deferralLimit := ElectiveDeferralLimit(startYear + yr - p.BirthDate.Year())
+			deferralExcess := decimalZero
 			employeeContributionAmount := decimalZero
 			if salaryForYear.GreaterThan(decimalZero) && p.IsFederal {
-				if autoContributionPercent.GreaterThan(decimalZero) {
+				// CSRS employees contribute to the TSP without agency automatic or matching contributions
+				agencyContributions := !p.IsCSRS()
+				if agencyContributions && autoContributionPercent.GreaterThan(decimalZero) {
 					autoContribution := salaryForYear.Mul(autoContributionPercent)
 					if autoContribution.GreaterThan(decimalZero) {
 						st.tspBalance = st.tspBalance.Add(autoContribution)
@@ -465,13 +881,17 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 
 					// Use policy-aware contribution calculation
 					employeeContribution := tspContribForYear(salaryForYear, employeePct, 26, st.retirementDate, yr, startYear, policy)
+					if employeeContribution.GreaterThan(deferralLimit) {
+						deferralExcess = employeeContribution.Sub(deferralLimit)
+						employeeContribution = deferralLimit
+					}
 					if employeeContribution.GreaterThan(decimalZero) {
 						st.tspBalance = st.tspBalance.Add(employeeContribution)
 						employeeContributionAmount = employeeContributionAmount.Add(employeeContribution)
 					}
 
 					matchContribution := decimalZero
-					if matchPoolPercent.GreaterThan(decimalZero) {
+					if agencyContributions && matchPoolPercent.GreaterThan(decimalZero) {
 						firstTierEmployee := employeePct
 						if firstTierEmployee.GreaterThan(firstTierCapPercent) {
 							firstTierEmployee = firstTierCapPercent
@@ -516,23 +936,52 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 			}
 
 			if employeeContributionAmount.GreaterThan(decimalZero) {
-				cf.ParticipantTSPContributions[p.Name] = cf.ParticipantTSPContributions[p.Name].Add(employeeContributionAmount)
+				cf.ParticipantTSPContributions.Set(p.Name, cf.ParticipantTSPContributions.Get(p.Name).Add(employeeContributionAmount))
 			}
 
 			// Add part-time TSP contributions
-			if cf.PartTimeTSPContributions[p.Name].GreaterThan(decimalZero) {
-				cf.ParticipantTSPContributions[p.Name] = cf.ParticipantTSPContributions[p.Name].Add(cf.PartTimeTSPContributions[p.Name])
-				st.tspBalance = st.tspBalance.Add(cf.PartTimeTSPContributions[p.Name])
+			if partTime := cf.PartTimeTSPContributions.Get(p.Name); partTime.GreaterThan(decimalZero) {
+				if room := decimal.Max(deferralLimit.Sub(employeeContributionAmount), decimalZero); partTime.GreaterThan(room) {
+					deferralExcess = deferralExcess.Add(partTime.Sub(room))
+					partTime = room
+					cf.PartTimeTSPContributions.Set(p.Name, partTime)
+				}
+				cf.ParticipantTSPContributions.Set(p.Name, cf.ParticipantTSPContributions.Get(p.Name).Add(partTime))
+				st.tspBalance = st.tspBalance.Add(partTime)
+			}
+			if deferralExcess.GreaterThanOrEqual(decimalOne) {
+				cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+					Year:        startYear + yr,
+					Participant: p.Name,
+					Code:        domain.WarningContributionLimit,
+					Amount:      deferralExcess,
+					Message: fmt.Sprintf("%s: configured TSP contributions exceed the $%s elective deferral limit; $%s not contributed",
+						p.Label(), deferralLimit.StringFixed(0), deferralExcess.StringFixed(0)),
+				})
+			}
+			st.contributeEmployerPlan(cf, p, salaryForYear, deferralLimit.Sub(cf.ParticipantTSPContributions.Get(p.Name)), startYear+yr)
+			st.contributeIRA(cf, p, cf.Salaries.Get(p.Name), startYear+yr-p.BirthDate.Year(), startYear+yr)
+
+			// Special provision retirees receive COLAs at any age
+			colaAge := age
+			if st.specialProvisionRetiree && colaAge < 62 {
+				colaAge = 62
 			}
 
-			if st.retired && st.pensionAnnual.GreaterThan(decimalZero) {
+			if st.retired && st.pensionAnnual.GreaterThan(decimalZero) && (st.pensionStartYear == nil || yr >= *st.pensionStartYear) {
 				pensionValue := st.pensionAnnual
 				if st.pensionStartYear != nil && yr > *st.pensionStartYear {
-					if p.IsFederal {
-						pensionValue = applyParticipantFERSCOLA(pensionValue, cola, age)
+					if p.IsCSRS() {
+						pensionValue = ApplyCSRSPensionCOLA(pensionValue, cola)
 						st.pensionAnnual = pensionValue
 						if st.survivorPension.GreaterThan(decimalZero) {
-							st.survivorPension = applyParticipantFERSCOLA(st.survivorPension, cola, age)
+							st.survivorPension = ApplyCSRSPensionCOLA(st.survivorPension, cola)
+						}
+					} else if p.IsFederal {
+						pensionValue = applyParticipantFERSCOLA(pensionValue, cola, colaAge)
+						st.pensionAnnual = pensionValue
+						if st.survivorPension.GreaterThan(decimalZero) {
+							st.survivorPension = applyParticipantFERSCOLA(st.survivorPension, cola, colaAge)
 						}
 					} else if p.ExternalPension != nil {
 						pensionValue = pensionValue.Mul(onePlus(p.ExternalPension.COLAAdjustment))
@@ -549,12 +998,29 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 					}
 				}
 
+				// CSRS Offset: from 62 the annuity drops by the Social Security attributable to offset service
+				if st.csrsOffsetAnnual.GreaterThan(decimalZero) && !st.csrsOffsetApplied && age >= 62 {
+					st.pensionAnnual = decimal.Max(decimalZero, st.pensionAnnual.Sub(st.csrsOffsetAnnual))
+					pensionValue = st.pensionAnnual
+					st.csrsOffsetApplied = true
+				}
+
 				if st.pensionStartYear != nil && yr == *st.pensionStartYear {
-					fractionWorked := computeWorkFraction(st.retirementDate, yearDate)
+					startDate := st.retirementDate
+					if st.pensionStartDate != nil {
+						startDate = st.pensionStartDate
+					}
+					fractionWorked := computeWorkFraction(startDate, yearDate)
 					pensionValue = st.pensionAnnual.Mul(decimalOne.Sub(fractionWorked))
 				}
 
-				cf.Pensions[p.Name] = pensionValue
+				cf.Pensions.Set(p.Name, pensionValue)
+
+				// Collect the retroactive premium once at least a month of annuity is paid
+				if st.fehbRetroactiveDue && !cf.IsDeceased.Get(p.Name) && pensionValue.GreaterThanOrEqual(st.pensionAnnual.Div(decimalTwelve)) {
+					cf.FEHBRetroactivePremium = cf.FEHBRetroactivePremium.Add(st.fehbPremium.Div(decimalTwelve))
+					st.fehbRetroactiveDue = false
+				}
 			}
 
 			// Handle FERS Special Retirement Supplement
@@ -563,7 +1029,7 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 				// Apply COLA to FERS supplement (same as pension COLA rules)
 				if st.fersSupplementStartYear != nil && yr > *st.fersSupplementStartYear {
 					if p.IsFederal {
-						st.fersSupplementAnnual = applyParticipantFERSCOLA(st.fersSupplementAnnual, cola, age)
+						st.fersSupplementAnnual = applyParticipantFERSCOLA(st.fersSupplementAnnual, cola, colaAge)
 					} else {
 						st.fersSupplementAnnual = st.fersSupplementAnnual.Mul(onePlus(cola))
 					}
@@ -577,25 +1043,25 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 					fersSupplementValue = st.fersSupplementAnnual.Mul(decimalOne.Sub(fractionWorked))
 				}
 			}
-			cf.FERSSupplements[p.Name] = fersSupplementValue
+			cf.FERSSupplements.Set(p.Name, fersSupplementValue)
 
 			// Apply FERS supplement reduction due to part-time work earnings
-			if cf.FERSSupplementReduction[p.Name].GreaterThan(decimalZero) {
-				reducedSupplement := fersSupplementValue.Sub(cf.FERSSupplementReduction[p.Name])
+			if cf.FERSSupplementReduction.Get(p.Name).GreaterThan(decimalZero) {
+				reducedSupplement := fersSupplementValue.Sub(cf.FERSSupplementReduction.Get(p.Name))
 				if reducedSupplement.LessThan(decimalZero) {
 					reducedSupplement = decimalZero
 				}
-				cf.FERSSupplements[p.Name] = reducedSupplement
+				cf.FERSSupplements.Set(p.Name, reducedSupplement)
 			}
 
 			ssBenefit := decimalZero
 			if st.ssStarted {
 				if st.ssStartYear != nil && yr > *st.ssStartYear {
-					st.ssAnnualFull = st.ssAnnualFull.Mul(onePlus(cola))
+					st.ssAnnualFull = st.ssAnnualFull.Mul(onePlus(ssCOLA))
 				}
 				ssBenefit = st.ssAnnualFull
 			} else if ageEnd >= st.ssStartAge {
-				fullAnnual := computeSSAnnualBenefit(p, st.ssStartAge)
+				fullAnnual := computeSSAnnualBenefit(p, st.ssStartAge, federalRules.SocialSecurityRules)
 				benefit := fullAnnual
 				benefit = computeSSBirthdayProration(benefit, p, st.ssStartAge, st.retirementYear, st.retirementDate, yr, yearDate, yearEnd, age, ageEnd)
 				benefit = computeSSRetirementAdjustment(benefit, fullAnnual, p, st.ssStartAge, st.retirementYear, st.retirementDate, yr, yearDate)
@@ -609,11 +1075,18 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 				}
 			}
 			if st.ssStarted {
-				cf.SSBenefits[p.Name] = ssBenefit
+				// Earnings test for claimers below FRA, exempt amounts indexed with inflation
+				wageIndex := onePlus(infl).Pow(decimal.NewFromInt(int64(yr)))
+				withheld := EarningsTestWithholding(ssBenefit, cf.Salaries.Get(p.Name).Add(cf.SelfEmploymentIncome.Get(p.Name)), p.BirthDate, startYear+yr, wageIndex, federalRules.SocialSecurityRules)
+				cf.SSEarningsTestWithheld.Set(p.Name, withheld)
+				cf.SSBenefits.Set(p.Name, ssBenefit.Sub(withheld))
 			}
 
 			// Calculate withdrawal using sequencing strategy
-			if st.retired && (st.tspBalance.GreaterThan(decimalZero) || (p.TaxableAccountBalance != nil && p.TaxableAccountBalance.GreaterThan(decimalZero))) {
+			st.syncTSPSplit()
+			seppScheduled := decimalZero       // the year's locked SEPP payment
+			tspTraditionalDrawn := decimalZero // pre-tax TSP money withdrawn, before conversions
+			if st.retired && (st.tspBalance.GreaterThan(decimalZero) || st.taxableBalance.GreaterThan(decimalZero) || st.iraBalance().GreaterThan(decimalZero)) {
 				withdrawal := decimalZero
 
 				// Check for RMD requirement first
@@ -633,15 +1106,44 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 						} else if st.retirementYear != nil && yr > *st.retirementYear {
 							st.tspWithdrawalBase = st.tspWithdrawalBase.Mul(onePlus(infl))
 						}
-						withdrawal = st.tspWithdrawalBase.Mul(decimal.NewFromFloat(0.04))
+						withdrawal = st.tspWithdrawalBase.Mul(decimal.NewFromFloat(0.04)).Mul(phaseFactor)
 					case "need_based":
 						if ps.TSPWithdrawalTargetMonthly != nil {
-							withdrawal = ps.TSPWithdrawalTargetMonthly.Mul(decimalTwelve)
+							withdrawal = ps.TSPWithdrawalTargetMonthly.Mul(decimalTwelve).Mul(phaseFactor)
 						}
 					case "variable_percentage":
 						if ps.TSPWithdrawalRate != nil {
 							withdrawal = st.tspBalance.Mul(*ps.TSPWithdrawalRate)
 						}
+					case "guardrails":
+						if ps.TSPWithdrawalRate != nil {
+							withdrawal = st.guardrailsWithdrawal(cf, p, ps, infl, startYear+yr)
+						}
+					case "rmd_only":
+						// Nothing beyond the required minimum, added below
+					case "spending_target":
+						if ce != nil {
+							withdrawal = ce.solvedWithdrawals[startYear+yr]
+						}
+					case "vpw":
+						allocation := VPWDefaultStockAllocation
+						if ps.VPWStockAllocation != nil {
+							allocation = *ps.VPWStockAllocation
+						}
+						withdrawal = st.tspBalance.Mul(VPWPercentage(age, allocation))
+					case "buckets":
+						if ps.TSPWithdrawalRate != nil {
+							withdrawal = st.bucketsWithdrawal(ps, infl)
+						}
+					case "sepp":
+						if st.seppAnnual.IsZero() {
+							firstPayment := time.Date(startYear+yr, 1, 1, 0, 0, 0, 0, time.UTC)
+							if retiredThisYear {
+								firstPayment = *st.retirementDate
+							}
+							st.startSEPP(p, ps, ageEnd, firstPayment)
+						}
+						withdrawal = st.seppAnnual
 					}
 
 					// Ensure RMD is met if required
@@ -649,14 +1151,19 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 						withdrawal = rmdAmount
 					}
 				}
-				if retiredThisYear && withdrawal.GreaterThan(decimalZero) {
+				// Solved withdrawals are already the year's amount
+				withdrawalStrategy := psMap[p.Name].TSPWithdrawalStrategy
+				solved := withdrawalStrategy == "spending_target"
+				if retiredThisYear && !solved && withdrawal.GreaterThan(decimalZero) {
 					withdrawal = withdrawal.Mul(retiredFraction)
 				}
-				if singleSurvivorName != "" && p.Name == singleSurvivorName {
+				seppScheduled = withdrawal
+				if singleSurvivorName != "" && p.Name == singleSurvivorName && withdrawalStrategy != "rmd_only" && !solved && !(st.seppAnnual.IsPositive() && yearDate.Before(st.seppLockEnd)) {
 					if survivorSpendingFactor.LessThan(decimalOne) {
 						withdrawal = withdrawal.Mul(survivorSpendingFactor)
 					}
 				}
+				requestedWithdrawal := withdrawal
 				// Use sequencing strategy if withdrawal sequencing is configured
 				if scenario.WithdrawalSequencing != nil && withdrawal.GreaterThan(decimalZero) {
 					// Determine if this is an RMD year
@@ -668,9 +1175,14 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 						rmdAmount = st.tspBalanceTraditional.Div(decimal.NewFromFloat(27.4))
 					}
 
-					// Create withdrawal sources
+					// Create withdrawal sources from projected (not configured) taxable balances
+					pView := *p
+					pView.TaxableAccountBalance = &st.taxableBalance
+					pView.TaxableAccountBasis = &st.taxableBasis
+					pView.IRABalanceTraditional = &st.iraBalanceTraditional
+					pView.IRABalanceRoth = &st.iraBalanceRoth
 					sources := sequencing.CreateWithdrawalSources(
-						p,
+						&pView,
 						st.tspBalanceTraditional,
 						st.tspBalanceRoth,
 						isRMDYear,
@@ -679,7 +1191,7 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 
 					// Create strategy context
 					currentOrdinaryIncome := cf.GetTotalPension().Add(cf.GetTotalSalary())
-					magiCurrent := cf.MAGI
+					magiCurrent := CalculateMAGI(cf)
 					ctx := sequencing.CreateStrategyContext(
 						withdrawal,
 						currentOrdinaryIncome,
@@ -691,22 +1203,27 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 					// Create and execute strategy
 					strategy := sequencing.CreateStrategy(scenario.WithdrawalSequencing)
 					plan := strategy.Plan(sources, ctx)
+					if magiCeiling != nil {
+						plan = sequencing.LimitMAGI(plan, sources, ce.magiHeadroom(cf, *magiCeiling))
+					}
 
 					// Apply the withdrawal plan
 					totalWithdrawn := decimalZero
 					taxableWithdrawn := decimalZero
+					gainsRealized := decimalZero
 					traditionalWithdrawn := decimalZero
 					rothWithdrawn := decimalZero
 
 					for _, allocation := range plan.Allocations {
 						switch allocation.Source {
 						case "taxable":
-							if p.TaxableAccountBalance != nil {
-								withdrawAmount := allocation.Gross
-								if withdrawAmount.GreaterThan(*p.TaxableAccountBalance) {
-									withdrawAmount = *p.TaxableAccountBalance
-								}
-								*p.TaxableAccountBalance = p.TaxableAccountBalance.Sub(withdrawAmount)
+							withdrawAmount := decimal.Min(allocation.Gross, st.taxableBalance)
+							if withdrawAmount.GreaterThan(decimalZero) {
+								// Basis is recovered pro rata; the remainder is a realized long-term gain
+								basisUsed := st.taxableBasis.Mul(withdrawAmount).Div(st.taxableBalance)
+								gainsRealized = gainsRealized.Add(withdrawAmount.Sub(basisUsed))
+								st.taxableBasis = st.taxableBasis.Sub(basisUsed)
+								st.taxableBalance = st.taxableBalance.Sub(withdrawAmount)
 								taxableWithdrawn = taxableWithdrawn.Add(withdrawAmount)
 								totalWithdrawn = totalWithdrawn.Add(withdrawAmount)
 							}
@@ -724,20 +1241,31 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 								withdrawAmount = st.tspBalanceRoth
 							}
 							st.tspBalanceRoth = st.tspBalanceRoth.Sub(withdrawAmount)
+							st.rothTSPDistributed = st.rothTSPDistributed.Add(withdrawAmount)
 							rothWithdrawn = rothWithdrawn.Add(withdrawAmount)
 							totalWithdrawn = totalWithdrawn.Add(withdrawAmount)
+						case "ira_traditional":
+							totalWithdrawn = totalWithdrawn.Add(st.withdrawIRATraditional(cf, p, allocation.Gross))
+						case "ira_roth":
+							totalWithdrawn = totalWithdrawn.Add(st.withdrawIRARoth(cf, p, allocation.Gross))
 						}
 					}
 
 					// Update balances and cash flow
 					st.tspBalance = st.tspBalanceTraditional.Add(st.tspBalanceRoth)
-					cf.TSPWithdrawals[p.Name] = traditionalWithdrawn.Add(rothWithdrawn)
+					cf.TSPWithdrawals.Set(p.Name, traditionalWithdrawn.Add(rothWithdrawn))
 					cf.WithdrawalTaxable = cf.WithdrawalTaxable.Add(taxableWithdrawn)
+					cf.CapitalGainsRealized = cf.CapitalGainsRealized.Add(gainsRealized)
 					cf.WithdrawalTraditional = cf.WithdrawalTraditional.Add(traditionalWithdrawn)
+					tspTraditionalDrawn = traditionalWithdrawn
 					cf.WithdrawalRoth = cf.WithdrawalRoth.Add(rothWithdrawn)
+					addWithdrawalShortfall(cf, p, startYear+yr, requestedWithdrawal, totalWithdrawn)
 				} else {
-					// Fallback to proportional withdrawal if no sequencing configured
+					// Fallback to proportional withdrawal if no sequencing configured; what
+					// the TSP cannot cover comes from the IRAs
+					fromIRA := decimalZero
 					if withdrawal.GreaterThan(st.tspBalance) {
+						fromIRA = st.withdrawIRA(cf, p, withdrawal.Sub(st.tspBalance))
 						withdrawal = st.tspBalance
 					}
 
@@ -762,10 +1290,22 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 					if st.tspBalanceRoth.LessThan(decimalZero) {
 						st.tspBalanceRoth = decimalZero
 					}
+					st.rothTSPDistributed = st.rothTSPDistributed.Add(rothPortion)
 					st.tspBalance = st.tspBalance.Sub(withdrawal)
-					cf.TSPWithdrawals[p.Name] = withdrawal
+					cf.TSPWithdrawals.Set(p.Name, withdrawal)
 					cf.WithdrawalTraditional = cf.WithdrawalTraditional.Add(tradPortion)
+					tspTraditionalDrawn = tradPortion
 					cf.WithdrawalRoth = cf.WithdrawalRoth.Add(rothPortion)
+					addWithdrawalShortfall(cf, p, startYear+yr, requestedWithdrawal, withdrawal.Add(fromIRA))
+				}
+			} else if st.retired && !cf.IsDeceased.Get(p.Name) {
+				// Balances are exhausted: a need-based target still goes unmet every year
+				if ps, ok := psMap[p.Name]; ok && ps.TSPWithdrawalStrategy == "need_based" && ps.TSPWithdrawalTargetMonthly != nil {
+					requested := ps.TSPWithdrawalTargetMonthly.Mul(decimalTwelve).Mul(phaseFactor)
+					if singleSurvivorName != "" && p.Name == singleSurvivorName && survivorSpendingFactor.LessThan(decimalOne) {
+						requested = requested.Mul(survivorSpendingFactor)
+					}
+					addWithdrawalShortfall(cf, p, startYear+yr, requested, decimalZero)
 				}
 			}
 
@@ -779,37 +1319,218 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 						if conversionAmount.GreaterThan(st.tspBalanceTraditional) {
 							conversionAmount = st.tspBalanceTraditional
 						}
+						if magiCeiling != nil {
+							conversionAmount = decimal.Min(conversionAmount, ce.magiHeadroom(cf, *magiCeiling))
+						}
 
 						// Move from Traditional to Roth
 						st.tspBalanceTraditional = st.tspBalanceTraditional.Sub(conversionAmount)
 						st.tspBalanceRoth = st.tspBalanceRoth.Add(conversionAmount)
-
-						// Update total TSP balance
-						st.tspBalance = st.tspBalanceTraditional.Add(st.tspBalanceRoth)
+						st.rothTSP.convert(currentYear, conversionAmount)
 
 						// Add conversion amount to taxable income for this year
 						// This will be picked up in the tax calculation
-						cf.TSPWithdrawals[p.Name] = cf.TSPWithdrawals[p.Name].Add(conversionAmount)
+						cf.TSPWithdrawals.Set(p.Name, cf.TSPWithdrawals.Get(p.Name).Add(conversionAmount))
+						cf.RothConversions = cf.RothConversions.Add(conversionAmount)
 					}
 				}
 			}
 
+			// Qualified charitable distributions count toward the year's pre-tax distributions,
+			// never Roth money or a conversion; any shortfall is drawn from the traditional IRA
+			// and then the traditional TSP. The IRS allows QCDs only from IRAs, so counting TSP
+			// money is a simplification for participants without enough in one.
+			if ps, exists := psMap[p.Name]; exists && ps.QCDs != nil {
+				qcd := qcdForYear(ps.QCDs, p.BirthDate, startYear+yr)
+				covered := cf.IRAWithdrawalsTraditional.Get(p.Name).Add(tspTraditionalDrawn)
+				if qcd.GreaterThan(covered) {
+					covered = covered.Add(st.withdrawIRATraditional(cf, p, qcd.Sub(covered)))
+					extra := decimal.Min(qcd.Sub(covered), st.tspBalanceTraditional)
+					st.tspBalanceTraditional = st.tspBalanceTraditional.Sub(extra)
+					st.tspBalance = st.tspBalance.Sub(extra)
+					cf.TSPWithdrawals.Set(p.Name, cf.TSPWithdrawals.Get(p.Name).Add(extra))
+					cf.WithdrawalTraditional = cf.WithdrawalTraditional.Add(extra)
+					qcd = covered.Add(extra)
+				}
+				cf.QualifiedCharitableDistributions = cf.QualifiedCharitableDistributions.Add(qcd)
+			}
+			st.satisfyIRARMD(cf, p, iraRMD)
+			if st.retired {
+				st.checkSEPP(cf, p, yr, startYear, yearDate, seppScheduled)
+			}
+			st.settleRothDistributions(cf, p, startYear+yr)
+
 			growthRate := preRetReturn
 			if st.retired {
 				growthRate = postRetReturn
 			}
-			if !st.tspBalance.IsZero() {
+			fundRate := false
+			if fundReturns != nil {
+				if rate, ok := ce.participantFundReturn(p, psMap[p.Name].TSPAllocationSchedule, st.retirementDate, startYear+yr, assumptions, fundReturns); ok {
+					growthRate, fundRate = rate, true
+				}
+			}
+			marketReturn := growthRate
+			if !st.bucketWithdrawal.IsZero() {
+				growthRate = st.growBuckets(marketReturn, bucketCashReturn, bucketBondReturn)
+			}
+			if periods > 1 {
+				// Spread the year's flows across sub-periods; in the retirement year the
+				// pre-retirement return applies only to periods before the retirement date
+				var switchDate *time.Time
+				preRate := growthRate
+				if retiredThisYear && !fundRate {
+					switchDate = st.retirementDate
+					preRate = preRetReturn
+				}
+				var periodEnds []decimal.Decimal
+				if periods == 12 && fundRate && monthlyReturns != nil && st.bucketWithdrawal.IsZero() {
+					// A path with a year of monthly history steps through its months
+					rates := make([]decimal.Decimal, 12)
+					for m, returns := range monthlyReturns {
+						rates[m], _ = ce.participantFundReturn(p, psMap[p.Name].TSPAllocationSchedule, st.retirementDate, startYear+yr, assumptions, returns)
+					}
+					st.tspBalance, periodEnds = growBalanceAtRates(tspStartOfYear, st.tspBalance.Sub(tspStartOfYear), rates)
+				} else {
+					st.tspBalance, periodEnds = growBalanceInPeriods(tspStartOfYear, st.tspBalance.Sub(tspStartOfYear), periods, yearDate, switchDate, preRate, growthRate)
+				}
+				for i, bal := range periodEnds {
+					cf.PeriodTSPBalances[i] = cf.PeriodTSPBalances[i].Add(bal)
+				}
+			} else if !st.tspBalance.IsZero() {
 				st.tspBalance = st.tspBalance.Mul(onePlus(growthRate))
 			}
-			cf.TSPBalances[p.Name] = st.tspBalance
+			st.lastTSPReturn = marketReturn
+			st.syncTSPSplit()
+			cf.TSPBalances.Set(p.Name, st.tspBalance)
+			if !st.bucketWithdrawal.IsZero() {
+				st.scaleBuckets()
+				if cf.Buckets == nil {
+					cf.Buckets = make(map[string]domain.BucketBalances)
+				}
+				cf.Buckets[p.Name] = st.buckets
+			}
+
+			// IRAs are invested outside the TSP funds and earn the general return
+			iraRate := preRetReturn
+			if st.retired {
+				iraRate = postRetReturn
+			}
+			st.growIRA(iraRate)
+			st.growEmployerPlan(iraRate)
+			if st.retired && p.EmployerPlan != nil {
+				separation := yearDate
+				if st.retirementDate != nil {
+					separation = *st.retirementDate
+				}
+				st.separateFromEmployerPlan(cf, p, separation, startYear+yr)
+			}
+			cf.IRABalances.Set(p.Name, st.iraBalance())
+			cf.EmployerPlanBalances.Set(p.Name, st.employerPlanBalance())
+		}
+
+		// Spousal benefits start once both spouses have filed for their own benefits.
+		// In the first year only the months both were receiving benefits are paid.
+		if len(household.Participants) == 2 {
+			ownBenefitFraction := func(name string) decimal.Decimal {
+				st := states[name]
+				if st.ssStartYear == nil || *st.ssStartYear != yr || st.ssAnnualFull.IsZero() {
+					return decimalOne
+				}
+				paid := cf.SSBenefits.Get(name).Add(cf.SSEarningsTestWithheld.Get(name)).Sub(cf.SpousalSSBenefits.Get(name))
+				return paid.Div(st.ssAnnualFull)
+			}
+			for i := range household.Participants {
+				p := &household.Participants[i]
+				spouse := &household.Participants[1-i]
+				st, spouseSt := states[p.Name], states[spouse.Name]
+				if !p.ClaimSpousalBenefit || cf.IsDeceased.Get(p.Name) || cf.IsDeceased.Get(spouse.Name) || !st.ssStarted || !spouseSt.ssStarted {
+					continue
+				}
+				if st.spousalStartYear == nil {
+					spousePIA := spouse.SSBenefitFRA.Sub(WEPMonthlyReduction(spouse, federalRules.SocialSecurityRules))
+					monthly := CalculateSpousalMonthlyBenefit(p.SSBenefitFRA, spousePIA, p.Age(yearEnd), dateutil.FullRetirementAge(p.BirthDate))
+					monthly = ApplyGPO(monthly, p, federalRules.SocialSecurityRules)
+					if monthly.IsZero() {
+						continue
+					}
+					st.spousalAnnual = monthly.Mul(decimalTwelve)
+					st.spousalStartYear = new(int)
+					*st.spousalStartYear = yr
+				} else if yr > *st.spousalStartYear {
+					st.spousalAnnual = st.spousalAnnual.Mul(onePlus(ssCOLA))
+				}
+
+				spousal := st.spousalAnnual
+				if yr == *st.spousalStartYear {
+					spousal = spousal.Mul(decimal.Min(ownBenefitFraction(p.Name), ownBenefitFraction(spouse.Name)))
+				}
+				cf.SpousalSSBenefits.Set(p.Name, spousal)
+				cf.SSBenefits.Set(p.Name, cf.SSBenefits.Get(p.Name).Add(spousal))
+			}
+		}
+
+		// Survivor benefits: once a spouse dies the survivor receives the higher of their
+		// own benefit or the deceased's, starting when the survivor files or reaches FRA
+		if len(household.Participants) == 2 {
+			for i := range household.Participants {
+				p := &household.Participants[i]
+				spouse := &household.Participants[1-i]
+				st, spouseSt := states[p.Name], states[spouse.Name]
+				if cf.IsDeceased.Get(p.Name) || !cf.IsDeceased.Get(spouse.Name) {
+					continue
+				}
+
+				if !st.survivorSSBaseSet {
+					if spouseSt.ssStarted {
+						st.survivorSSBase = spouseSt.ssAnnualFull
+						if spouseSt.ssStartYear != nil && yr > *spouseSt.ssStartYear {
+							st.survivorSSBase = st.survivorSSBase.Mul(onePlus(ssCOLA))
+						}
+					} else {
+						// Deceased had not filed: their benefit at death, with delayed credits to 70
+						spouseFRA := dateutil.FullRetirementAge(spouse.BirthDate)
+						deathAge := spouse.Age(yearDate)
+						if deathAge < spouseFRA {
+							deathAge = spouseFRA
+						}
+						if deathAge > 70 {
+							deathAge = 70
+						}
+						st.survivorSSBase = computeSSAnnualBenefit(spouse, deathAge, federalRules.SocialSecurityRules)
+					}
+					st.survivorSSBaseSet = true
+				} else if st.survivorSSStartYear == nil {
+					st.survivorSSBase = st.survivorSSBase.Mul(onePlus(ssCOLA))
+				}
+
+				fra := dateutil.FullRetirementAge(p.BirthDate)
+				if st.survivorSSStartYear == nil {
+					if ageEnd := p.Age(yearEnd); ageEnd < 60 || (!st.ssStarted && ageEnd < fra) {
+						continue
+					}
+					annual := CalculateSurvivorSSBenefit(st.survivorSSBase, p.Age(yearEnd), fra)
+					st.survivorSSAnnual = ApplyGPO(annual.Div(decimalTwelve), p, federalRules.SocialSecurityRules).Mul(decimalTwelve)
+					st.survivorSSStartYear = new(int)
+					*st.survivorSSStartYear = yr
+				} else if yr > *st.survivorSSStartYear {
+					st.survivorSSAnnual = st.survivorSSAnnual.Mul(onePlus(ssCOLA))
+				}
+
+				own := cf.SSBenefits.Get(p.Name)
+				if st.survivorSSAnnual.GreaterThan(own) {
+					cf.SurvivorSSBenefits.Set(p.Name, st.survivorSSAnnual.Sub(own))
+					cf.SSBenefits.Set(p.Name, st.survivorSSAnnual)
+				}
+			}
 		}
 
 		// Check if any participant is in an RMD year (household-level)
 		cf.IsRMDYear = false
 		cf.RMDAmount = decimalZero
 		for _, name := range participantNames {
-			if !cf.IsDeceased[name] {
-				age := cf.Ages[name]
+			if !cf.IsDeceased.Get(name) {
+				age := cf.Ages.Get(name)
 				if age >= 73 {
 					cf.IsRMDYear = true
 					// Calculate RMD for the participant with Traditional TSP balance
@@ -832,7 +1553,7 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 
 		livingNames := make([]string, 0, len(participantNames))
 		for _, name := range participantNames {
-			if !cf.IsDeceased[name] {
+			if !cf.IsDeceased.Get(name) {
 				livingNames = append(livingNames, name)
 			}
 		}
@@ -840,8 +1561,62 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 			share := transferPool.Div(decimal.NewFromInt(int64(len(livingNames))))
 			for _, name := range livingNames {
 				st := states[name]
+				rothBefore := st.tspBalanceRoth
 				st.tspBalance = st.tspBalance.Add(share)
-				cf.TSPBalances[name] = st.tspBalance
+				st.syncTSPSplit()
+				st.rothTSP.contribute(st.tspBalanceRoth.Sub(rothBefore))
+				cf.TSPBalances.Set(name, st.tspBalance)
+			}
+		}
+		if iraTransferTraditional.Add(iraTransferRoth).GreaterThan(decimalZero) && len(livingNames) > 0 {
+			count := decimal.NewFromInt(int64(len(livingNames)))
+			for _, name := range livingNames {
+				st := states[name]
+				st.iraBalanceTraditional = st.iraBalanceTraditional.Add(iraTransferTraditional.Div(count))
+				st.iraBalanceRoth = st.iraBalanceRoth.Add(iraTransferRoth.Div(count))
+				st.rothIRA.contribute(iraTransferRoth.Div(count))
+				cf.IRABalances.Set(name, st.iraBalance())
+			}
+		}
+		for _, name := range livingNames {
+			st := states[name]
+			cf.TraditionalBalance = cf.TraditionalBalance.Add(st.tspBalanceTraditional).Add(st.iraBalanceTraditional).Add(st.planBalanceTraditional).Add(st.planBalanceEmployer)
+			cf.RothBalance = cf.RothBalance.Add(st.tspBalanceRoth).Add(st.iraBalanceRoth).Add(st.planBalanceRoth)
+			cf.TaxableBalance = cf.TaxableBalance.Add(st.taxableBalance)
+		}
+
+		// At the first death the survivor's FEHB enrollment drops to self only unless the
+		// scenario keeps it unchanged. Coverage under a deceased enrollee continues only when
+		// the survivor receives a survivor annuity; spouses with their own enrollments are
+		// already self only.
+		if singleSurvivorName != "" && len(household.Participants) > 1 && !states[singleSurvivorName].fehbSurvivorAdjusted {
+			survivorState := states[singleSurvivorName]
+			survivorState.fehbSurvivorAdjusted = true
+			keepEnrollment := scenario != nil && scenario.Mortality != nil && scenario.Mortality.Assumptions != nil &&
+				scenario.Mortality.Assumptions.FEHBSurvivorEnrollment == domain.FEHBSurvivorEnrollmentUnchanged
+
+			enrollments := 0
+			var enrollee *domain.Participant
+			for i := range household.Participants {
+				q := &household.Participants[i]
+				if !states[q.Name].fehbPremium.IsPositive() {
+					continue
+				}
+				enrollments++
+				if q.Name == singleSurvivorName {
+					enrollee = q // the survivor's own enrollment takes precedence
+				} else if enrollee == nil && survivorState.survivorPensionIncome.IsPositive() {
+					enrollee = q
+				}
+			}
+			if enrollee != nil {
+				premium := states[enrollee.Name].fehbPremium
+				if !keepEnrollment && enrollments == 1 {
+					premium = premium.Mul(fehbSelfOnlyFactor(enrollee))
+					survivorState.fehbSelfOnly = true
+				}
+				states[enrollee.Name].fehbPremium = decimalZero
+				survivorState.fehbPremium = premium
 			}
 		}
 
@@ -850,12 +1625,21 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 		tspContributionTotal := decimalZero
 		for _, name := range participantNames {
 			st := states[name]
-			if !cf.IsDeceased[name] && st.fehbPremium.GreaterThan(decimalZero) {
-				fehbTotal = fehbTotal.Add(st.fehbPremium)
+			if !cf.IsDeceased.Get(name) && st.fehbPremium.GreaterThan(decimalZero) {
+				premium := st.fehbPremium
+				// Dropping FEHB for Medigap ends the premium once Medicare starts
+				for i := range household.Participants {
+					if p := &household.Participants[i]; p.Name == name && medicareCoverage(p, psMap[name]) == domain.MedicareCoverageMedigap {
+						if medicareMonths := MedicareMonthsInYear(p.BirthDate, startYear+yr); medicareMonths > 0 {
+							premium = premium.Mul(decimal.NewFromInt(int64(12 - medicareMonths))).Div(decimalTwelve)
+						}
+					}
+				}
+				fehbTotal = fehbTotal.Add(premium)
 			}
-			tspContributionTotal = tspContributionTotal.Add(cf.ParticipantTSPContributions[name])
+			tspContributionTotal = tspContributionTotal.Add(cf.ParticipantTSPContributions.Get(name))
 		}
-		cf.FEHBPremium = fehbTotal
+		cf.FEHBPremium = fehbTotal.Add(cf.FEHBRetroactivePremium)
 		cf.TotalTSPContributions = tspContributionTotal
 
 		livingCount := len(livingNames)
@@ -874,37 +1658,95 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 		// Calculate comprehensive healthcare costs
 		healthcareCalc := NewHealthcareCostCalculator()
 
+		// An FEHB enrollment covers the household until the enrollee drops it for Medigap;
+		// without one, participants counting on FEHB before Medicare buy marketplace coverage
+		fehbEnrolled := false
+		for _, name := range livingNames {
+			if states[name].fehbPremium.IsPositive() {
+				for i := range household.Participants {
+					if p := &household.Participants[i]; p.Name == name {
+						fehbEnrolled = fehbEnrolled || medicareCoverage(p, psMap[name]) != domain.MedicareCoverageMedigap ||
+							MedicareMonthsInYear(p.BirthDate, startYear+yr) < 12
+					}
+				}
+			}
+		}
+
 		// Get living participants for healthcare calculation
 		livingParticipants := make([]domain.Participant, 0, len(livingNames))
 		for _, name := range livingNames {
 			for _, p := range household.Participants {
 				if p.Name == name {
+					if st := states[name]; (st.deferredAnnuityAge > 0 && st.retired) || st.fehbForfeited {
+						p.FEHBPremiumPerPayPeriod = nil // FEHB cannot be continued into a deferred annuity, or without five years of coverage
+					}
+					if st := states[name]; st.fehbSelfOnly && p.FEHBPremiumPerPayPeriod != nil {
+						selfOnly := p.FEHBPremiumPerPayPeriod.Mul(fehbSelfOnlyFactor(&p))
+						p.FEHBPremiumPerPayPeriod = &selfOnly
+					}
+					if coverage := medicareCoverage(&p, psMap[name]); coverage != "" {
+						healthcare := domain.DefaultHealthcareConfig()
+						if p.Healthcare != nil {
+							healthcare = *p.Healthcare
+						}
+						healthcare.MedicareCoverage = coverage
+						p.Healthcare = &healthcare
+					}
+					if age := partBEnrollmentAge(&p, psMap[name]); age > 65 {
+						healthcare := domain.DefaultHealthcareConfig()
+						if p.Healthcare != nil {
+							healthcare = *p.Healthcare
+						}
+						enrollment := PartBEnrollmentDate(p.BirthDate, age)
+						healthcare.PartBEnrollmentAge = age
+						healthcare.PartBLatePenalty = PartBLateEnrollmentPenalty(p.BirthDate, enrollment, partBEmploymentCoverageEnd(household, psMap, &p))
+						p.Healthcare = &healthcare
+						if enrollment.Year() == startYear+yr && healthcare.PartBLatePenalty.IsPositive() {
+							cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+								Year:        startYear + yr,
+								Participant: name,
+								Code:        domain.WarningPartBLatePenalty,
+								Message: fmt.Sprintf("%s: enrolling in Part B at %d without coverage through current employment adds a %s%% lifetime penalty to the Part B premium",
+									p.Label(), age, healthcare.PartBLatePenalty.Mul(decimal.NewFromInt(100)).String()),
+							})
+						}
+					}
+					if hc := p.Healthcare; !fehbEnrolled && hc != nil && hc.PreMedicareCoverage == "fehb" && hc.PreMedicareMonthlyPremium.IsPositive() {
+						healthcare := *hc
+						healthcare.PreMedicareCoverage = "marketplace"
+						p.Healthcare = &healthcare
+					}
 					livingParticipants = append(livingParticipants, p)
 					break
 				}
 			}
 		}
 
-		// Calculate household healthcare costs
+		// Calculate household healthcare costs and the marketplace benchmark premium. IRMAA
+		// surcharges are based on MAGI from two years earlier.
+		cf.IRMAAMAGI = lookbackMAGI(household, projection, yr, startYear)
+		stopHealthcare := ce.startTiming(scenario, TimingHealthcare)
 		cf.HealthcareCosts = healthcareCalc.CalculateHouseholdHealthcareCosts(
 			livingParticipants,
 			cf.Ages,
 			startYear+yr,
-			cf.MAGI,
+			cf.IRMAAMAGI,
 			filingStatus,
 		)
+		benchmark := healthcareCalc.marketplacePremium(livingParticipants, cf.Ages, startYear+yr)
+		stopHealthcare()
 
 		seniors := 0
 		// Sort participant names for deterministic processing order
 		var ageNames []string
-		for name := range cf.Ages {
+		for _, name := range cf.Ages.Names() {
 			ageNames = append(ageNames, name)
 		}
 		sort.Strings(ageNames)
 
 		for _, name := range ageNames {
-			age := cf.Ages[name]
-			if cf.IsDeceased[name] {
+			age := cf.Ages.Get(name)
+			if cf.IsDeceased.Get(name) {
 				continue
 			}
 			if age >= 65 {
@@ -912,20 +1754,44 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 			}
 		}
 
+		// Ordinary income outside the plan: TSP money paid toward military deposits, cash flow
+		// events, income streams less the deductible half of self-employment tax, plus any
+		// income a marginal rate sweep adds to this year
+		otherTaxableIncome := depositTaxableIncome.Add(eventTaxableIncome).Add(selfEmploymentIncome).Add(passiveIncome).Sub(selfEmploymentTax.Div(decimal.NewFromInt(2)))
+		if ce != nil {
+			otherTaxableIncome = otherTaxableIncome.Add(ce.AdditionalOrdinaryIncome[startYear+yr])
+		}
+
+		if yr > 0 {
+			itemizedGrowth = itemizedGrowth.Mul(onePlus(infl))
+		}
+		taxExemptInterest := household.TaxExemptInterest.Mul(itemizedGrowth)
+
+		stopTaxes := ce.startTiming(scenario, TimingTaxes)
 		taxable := domain.TaxableIncome{
-			Salary:             cf.GetTotalSalary(),
-			FERSPension:        cf.GetTotalPension(),
-			TSPWithdrawalsTrad: cf.GetTotalTSPWithdrawal(),
-			TaxableSSBenefits:  cf.GetTotalSSBenefit(),
-			OtherTaxableIncome: decimalZero,
+			Salary:             decimal.Max(decimalZero, cf.GetTotalSalary().Sub(cf.FEHBPreTaxPremium).Sub(cf.EmployerPlanPreTax).Sub(cf.IRATraditionalContributions)),
+			FERSPension:        cf.GetTotalPension().Add(cf.GetTotalSurvivorPension()).Add(cf.GetTotalFERSSupplement()),
+			TSPWithdrawalsTrad: cf.GetTotalTSPWithdrawal().Sub(cf.WithdrawalRoth).Sub(cf.QualifiedCharitableDistributions).Add(cf.GetTotalTSPAnnuity()).Add(cf.GetTaxableIncomeAnnuity()).Add(cf.GetTotalTraditionalIRAWithdrawal()).Add(cf.RothTaxableEarnings),
+			TaxableSSBenefits:  ce.taxableSocialSecurity(cf, otherTaxableIncome, taxExemptInterest, filingStatus),
+			OtherTaxableIncome: otherTaxableIncome,
 			WageIncome:         cf.GetTotalSalary(),
 			InterestIncome:     decimalZero,
+
+			SelfEmploymentIncome: selfEmploymentIncome,
+			PassiveIncome:        passiveIncome,
+
+			LongTermCapitalGains: cf.CapitalGainsRealized,
 		}
 
+		// MAGI is complete once Social Security's taxable share is known, before the credits
+		// and surcharges that depend on it
+		cf.MAGIBreakdown = magiBreakdown(cf, taxable.TaxableSSBenefits, otherTaxableIncome, taxExemptInterest)
+		cf.MAGI = cf.MAGIBreakdown.MAGI
+
 		isRetiredHousehold := true
 		for _, name := range participantNames {
 			st := states[name]
-			if !cf.IsDeceased[name] && !st.retired {
+			if !cf.IsDeceased.Get(name) && !st.retired {
 				isRetiredHousehold = false
 				break
 			}
@@ -933,17 +1799,90 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 		cf.IsRetired = isRetiredHousehold
 
 		if ce != nil && ce.TaxCalc != nil {
-			cf.FederalTax = ce.TaxCalc.calculateFederalTaxWithStatus(taxable, filingStatus, seniors)
-			cf.StateTax = ce.TaxCalc.StateTaxCalc.CalculateTax(taxable, isRetiredHousehold)
+			// State and local taxes come first so they can be itemized on the federal return
 			hasWageIncome := taxable.WageIncome.GreaterThan(decimalZero)
-			applyRetiredExemption := isRetiredHousehold && !hasWageIncome
-			cf.LocalTax = ce.TaxCalc.LocalTaxCalc.CalculateEIT(taxable.WageIncome, applyRetiredExemption)
+			// The local earned income tax also applies to net profits from self-employment
+			earnedIncome := taxable.WageIncome.Add(taxable.SelfEmploymentIncome)
+			if residents == nil {
+				cf.StateTax = ce.TaxCalc.StateTaxCalc.CalculateTax(taxable, isRetiredHousehold)
+				applyRetiredExemption := isRetiredHousehold && !earnedIncome.IsPositive()
+				cf.LocalTax = ce.TaxCalc.LocalTaxCalc.CalculateEIT(earnedIncome, applyRetiredExemption)
+			} else {
+				// Each state taxes its resident's share of the joint income; the local
+				// earned income tax applies to wages of participants living at home
+				living := len(cf.GetLivingParticipants())
+				localWages := decimalZero
+				cf.StateTax = decimalZero
+				for i := range household.Participants {
+					p := &household.Participants[i]
+					if cf.IsDeceased.Get(p.Name) {
+						continue
+					}
+					income := participantTaxableIncome(cf, p.Name, taxable, living)
+					tax, known := ce.TaxCalc.residentStateTax(residents[p.Name], stateRules, income, states[p.Name].retired)
+					if !known && yr == 0 {
+						cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+							Year:        startYear + yr,
+							Participant: p.Name,
+							Code:        domain.WarningUnknownStateTax,
+							Message:     fmt.Sprintf("%s: no tax rules for %s; state income tax not computed", p.Label(), residents[p.Name]),
+						})
+					}
+					cf.StateTax = cf.StateTax.Add(tax)
+					if residents[p.Name] == homeState {
+						localWages = localWages.Add(income.WageIncome).Add(income.SelfEmploymentIncome)
+					}
+				}
+				cf.LocalTax = ce.TaxCalc.LocalTaxCalc.CalculateEIT(localWages, isRetiredHousehold && localWages.IsZero())
+			}
+
+			if itemized := household.ItemizedDeductions; itemized != nil {
+				if itemized.MortgagePayoffYear == 0 || startYear+yr <= itemized.MortgagePayoffYear {
+					taxable.MortgageInterest = itemized.MortgageInterest
+				}
+				taxable.SALTPaid = cf.StateTax.Add(cf.LocalTax).Add(itemized.PropertyTaxes.Mul(itemizedGrowth))
+				taxable.CharitableContributions = itemized.CharitableContributions.Mul(itemizedGrowth)
+			}
+
+			deductionElection := ""
+			if election := scenario.TaxElectionFor(startYear + yr); election != nil {
+				deductionElection = election.Deduction
+			}
+			fedResult := ce.TaxCalc.calculateFederalTaxElected(taxable, filingStatus, seniors, deductionElection)
+			cf.FederalTax = fedResult.Tax
+			cf.FederalTaxableIncome = fedResult.TaxableIncome
+			cf.TaxableSocialSecurity = taxable.TaxableSSBenefits
+			cf.FederalStandardDeduction = fedResult.StandardDeduction
+			cf.FederalItemizedDeduction = fedResult.ItemizedDeduction
+			cf.FederalDeductionMethod = "standard"
+			if fedResult.Itemized {
+				cf.FederalDeductionMethod = "itemized"
+			}
+			cf.NetInvestmentIncomeTax = ce.TaxCalc.FederalTaxCalc.CalculateNIITForIncome(taxable, filingStatus)
+
+			// Credits that matter in lean years: the Saver's Credit on TSP contributions
+			// offsets tax owed, and the premium tax credit pays part of marketplace coverage
+			agi := adjustedGrossIncome(taxable)
+			contributions := make([]decimal.Decimal, 0, len(participantNames))
+			for _, name := range participantNames {
+				if !cf.IsDeceased.Get(name) {
+					contributions = append(contributions, cf.ParticipantTSPContributions.Get(name))
+				}
+			}
+			cf.SaversCredit = decimal.Min(SaversCredit(agi, contributions, filingStatus), cf.FederalTax)
+			cf.FederalTax = cf.FederalTax.Sub(cf.SaversCredit).Add(cf.EarlyDistributionTax)
+			cf.ACAMAGI = cf.MAGIBreakdown.ACAMAGI()
+			cf.PremiumTaxCredit = decimal.Min(PremiumTaxCredit(benchmark, cf.ACAMAGI, len(livingParticipants)), cf.HealthcareCosts.MarketplacePremium)
+			cf.HealthcareCosts.Total = cf.HealthcareCosts.Total.Sub(cf.PremiumTaxCredit)
+			if cf.PremiumTaxCredit.IsPositive() {
+				cf.ACACliffDistance = premiumTaxCreditCliff(len(livingParticipants)).Sub(cf.ACAMAGI)
+			}
 			if hasWageIncome {
 				// Calculate FICA per person with separate wage-base caps
 				participantWages := make([]decimal.Decimal, 0, len(participantNames))
 				for _, name := range participantNames {
-					if !cf.IsDeceased[name] {
-						participantWages = append(participantWages, cf.Salaries[name])
+					if !cf.IsDeceased.Get(name) {
+						participantWages = append(participantWages, cf.Salaries.Get(name))
 					}
 				}
 
@@ -959,35 +1898,61 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 			} else {
 				cf.FICATax = decimalZero
 			}
+			cf.SelfEmploymentTax = selfEmploymentTax
+			cf.FICATax = cf.FICATax.Add(selfEmploymentTax)
 		}
+		stopTaxes()
 
 		cf.TotalGrossIncome = cf.CalculateTotalIncome()
 		cf.CalculateNetIncome()
 
+		cf.GuaranteedIncome = cf.GetGuaranteedIncome()
+		spendingFactor := phaseFactor
+		if len(participantNames) > 1 && len(aliveNames) == 1 {
+			spendingFactor = spendingFactor.Mul(survivorSpendingFactor)
+		}
+		if len(household.Expenses) > 0 {
+			var essential decimal.Decimal
+			cf.Expenses, cf.SpendingTarget, essential = spendingForYear(household.Expenses, startYear, yr, itemizedGrowth, spendingFactor)
+			cf.SpendingSurplus = cf.NetIncome.Sub(cf.SpendingTarget)
+			if household.EssentialExpenses.IsZero() && essential.IsPositive() {
+				cf.EssentialExpenses = essential
+				cf.FloorCoverage = cf.GuaranteedIncome.Div(essential).Round(4)
+			}
+		} else if target := configuredSpendingTarget(scenario); target != nil {
+			cf.SpendingTarget = target.Mul(itemizedGrowth).Mul(spendingFactor)
+			cf.SpendingSurplus = cf.NetIncome.Sub(cf.SpendingTarget)
+		}
+		if household.EssentialExpenses.IsPositive() {
+			cf.EssentialExpenses = household.EssentialExpenses.Mul(itemizedGrowth)
+			cf.FloorCoverage = cf.GuaranteedIncome.Div(cf.EssentialExpenses).Round(4)
+		}
+
 		// Determine Medicare eligibility (any participant age 65+)
 		cf.IsMedicareEligible = false
 		// Sort participant names for deterministic processing order
 		var medicareNames []string
-		for name := range cf.Ages {
+		for _, name := range cf.Ages.Names() {
 			medicareNames = append(medicareNames, name)
 		}
 		sort.Strings(medicareNames)
 		for _, name := range medicareNames {
-			if cf.Ages[name] >= 65 {
+			if cf.Ages.Get(name) >= 65 {
 				cf.IsMedicareEligible = true
 				break
 			}
 		}
 
-		// Calculate MAGI for IRMAA determination
-		cf.MAGI = CalculateMAGI(cf)
-
-		// Calculate IRMAA risk if Medicare eligible
+		// The surcharges paid this year follow MAGI from two years earlier; this year's MAGI
+		// sets the tier of anyone on Medicare two years from now
+		isMarried := household.FilingStatus == "married_filing_jointly"
+		mc := NewMedicareCalculator()
 		if cf.IsMedicareEligible {
-			isMarried := household.FilingStatus == "married_filing_jointly"
-			mc := NewMedicareCalculator()
-
-			risk, tier, surcharge, distance := CalculateIRMAARiskStatus(
+			cf.IRMAASurcharge = mc.calculateIRMAASurcharge(cf.IRMAAMAGI, isMarried)
+			cf.IRMAASurchargePartD = mc.calculatePartDIRMAASurcharge(cf.IRMAAMAGI, isMarried)
+		}
+		if medicareWithinLookback(cf) {
+			risk, tier, _, distance := CalculateIRMAARiskStatus(
 				cf.MAGI,
 				isMarried,
 				mc,
@@ -995,16 +1960,69 @@ func (ce *CalculationEngine) GenerateAnnualProjectionGeneric(household *domain.H
 
 			cf.IRMAARiskStatus = string(risk)
 			cf.IRMAALevel = tier
-			cf.IRMAASurcharge = surcharge
 			cf.IRMAADistanceToNext = distance
 		}
 
+		cumulativeTaxes = cumulativeTaxes.Add(yearTaxes(cf))
+		cf.CumulativeTaxes = cumulativeTaxes
+
 		projection[yr] = *cf
 	}
+	if cacheKey != "" && sharedYears == years && years > firstYr {
+		ce.ProjectionCache.storePrefix(cacheKey, newProjectionPrefix(projection, states, itemizedGrowth, participantNames))
+	}
+	// Years restored from the cache may come from a scenario with another ceiling
+	for i := range projection {
+		projection[i].MAGICeiling = decimalZero
+		if magiCeiling != nil {
+			projection[i].MAGICeiling = *magiCeiling
+		}
+	}
 
 	return projection
 }
 
+// withoutDeclinedMilitaryDeposits returns the household with military deposits removed
+// for participants whose scenario declines them, copying only when something changes
+func withoutDeclinedMilitaryDeposits(household *domain.Household, scenario *domain.GenericScenario) *domain.Household {
+	if scenario == nil {
+		return household
+	}
+	var copied *domain.Household
+	for i := range household.Participants {
+		p := &household.Participants[i]
+		if p.MilitaryDeposit == nil || !scenario.ParticipantScenarios[p.Name].DeclineMilitaryDeposit {
+			continue
+		}
+		if copied == nil {
+			h := *household
+			h.Participants = append([]domain.Participant(nil), household.Participants...)
+			copied = &h
+		}
+		copied.Participants[i].MilitaryDeposit = nil
+	}
+	if copied == nil {
+		return household
+	}
+	return copied
+}
+
+// militaryDepositYear returns the calendar year a military deposit is paid
+func militaryDepositYear(md *domain.MilitaryDeposit, startYear int) int {
+	if md.PaymentYear > 0 {
+		return md.PaymentYear
+	}
+	return startYear
+}
+
+// militaryDepositSource names the balance a military deposit is paid from
+func militaryDepositSource(md *domain.MilitaryDeposit) string {
+	if md.PaidFrom == domain.MilitaryDepositFromTSP {
+		return "TSP"
+	}
+	return "taxable account"
+}
+
 func computeWorkFraction(retirementDate *time.Time, yearStart time.Time) decimal.Decimal {
 	if retirementDate == nil {
 		return decimal.NewFromFloat(0.5)
@@ -1036,11 +2054,28 @@ func computeWorkFraction(retirementDate *time.Time, yearStart time.Time) decimal
 	return decimal.NewFromFloat(fraction)
 }
 
-func computeSSAnnualBenefit(p *domain.Participant, startAge int) decimal.Decimal {
-	return computeSSMonthlyBenefit(p, startAge).Mul(decimalTwelve)
+func computeSSAnnualBenefit(p *domain.Participant, startAge int, rules domain.SocialSecurityRules) decimal.Decimal {
+	if p.IsFederal && p.RetirementSystem == domain.RetirementSystemCSRS {
+		return decimalZero // pure CSRS service is not covered by Social Security
+	}
+	return computeSSMonthlyBenefit(p, startAge, rules).Mul(decimalTwelve)
+}
+
+// computeSSMonthlyBenefit interpolates the claiming-age benefit and, when WEP applies,
+// scales it by the share of the full-retirement-age benefit that survives the reduction
+func computeSSMonthlyBenefit(p *domain.Participant, startAge int, rules domain.SocialSecurityRules) decimal.Decimal {
+	benefit := computeSSClaimingBenefit(p, startAge)
+	if reduction := WEPMonthlyReduction(p, rules); reduction.GreaterThan(decimalZero) && p.SSBenefitFRA.GreaterThan(decimalZero) {
+		remaining := p.SSBenefitFRA.Sub(reduction)
+		if remaining.LessThan(decimalZero) {
+			remaining = decimalZero
+		}
+		benefit = benefit.Mul(remaining).Div(p.SSBenefitFRA)
+	}
+	return benefit
 }
 
-func computeSSMonthlyBenefit(p *domain.Participant, startAge int) decimal.Decimal {
+func computeSSClaimingBenefit(p *domain.Participant, startAge int) decimal.Decimal {
 	if startAge <= 62 {
 		return p.SSBenefit62
 	}
@@ -1132,50 +2167,89 @@ func computeSSRetirementAdjustment(currentBenefit, fullAnnual decimal.Decimal, p
 }
 
 func calculateParticipantPension(p *domain.Participant, retirementDate time.Time) (decimal.Decimal, decimal.Decimal) {
+	return applyParticipantSurvivorElection(p, unreducedParticipantPension(p, retirementDate))
+}
+
+// unreducedParticipantPension computes an immediate annuity before any survivor reduction
+func unreducedParticipantPension(p *domain.Participant, retirementDate time.Time) decimal.Decimal {
 	if !p.IsFederal || p.High3Salary == nil || p.HireDate == nil {
-		return decimalZero, decimalZero
+		return decimalZero
 	}
 
+	// Sick leave credit adds to the computation but never to eligibility, including the
+	// 20 years needed for the 1.1% multiplier
 	serviceYears := p.YearsOfService(retirementDate)
+	creditableService := p.CreditableService(retirementDate)
 	retirementAge := p.Age(retirementDate)
 
+	if p.IsCSRS() {
+		return CalculateCSRSPension(*p.High3Salary, creditableService)
+	}
+	if p.IsSpecialProvision() && SpecialProvisionEligible(retirementAge, serviceYears) {
+		return SpecialProvisionPension(*p.High3Salary, creditableService)
+	}
+
 	multiplier := decimal.NewFromFloat(0.01)
 	if retirementAge >= 62 && serviceYears.GreaterThanOrEqual(decimal.NewFromInt(20)) {
 		multiplier = decimal.NewFromFloat(0.011)
 	}
 
-	pensionBase := p.High3Salary.Mul(serviceYears).Mul(multiplier)
+	return p.High3Salary.Mul(creditableService).Mul(multiplier)
+}
 
-	survivorElection := decimalZero
-	if p.SurvivorBenefitElectionPercent != nil {
-		survivorElection = *p.SurvivorBenefitElectionPercent
+// calculateDeferredParticipantPension computes a deferred FERS annuity for a participant
+// who separated on separationDate and begins the annuity at startAge. Service stops at
+// separation; the 1.1% multiplier and MRA+10 reduction use the age at commencement.
+func calculateDeferredParticipantPension(p *domain.Participant, separationDate time.Time, startAge int) (decimal.Decimal, decimal.Decimal) {
+	return applyParticipantSurvivorElection(p, unreducedDeferredParticipantPension(p, separationDate, startAge))
+}
+
+// unreducedDeferredParticipantPension computes a deferred annuity before any survivor reduction
+func unreducedDeferredParticipantPension(p *domain.Participant, separationDate time.Time, startAge int) decimal.Decimal {
+	if !p.IsFederal || p.High3Salary == nil || p.HireDate == nil {
+		return decimalZero
 	}
 
-	reduced := pensionBase
-	survivor := decimalZero
-	if survivorElection.GreaterThan(decimalZero) {
-		half := decimal.NewFromFloat(0.5)
-		quarter := decimal.NewFromFloat(0.25)
-		if survivorElection.GreaterThan(decimal.NewFromFloat(0.4)) {
-			survivorElection = half
-		} else if survivorElection.GreaterThan(decimal.NewFromFloat(0.20)) && survivorElection.LessThan(decimal.NewFromFloat(0.30)) {
-			survivorElection = quarter
+	serviceYears := p.YearsOfService(separationDate)
+	if p.IsCSRS() {
+		// CSRS deferred annuities begin unreduced at 62
+		if startAge < 62 {
+			return decimalZero
 		}
+		return CalculateCSRSPension(*p.High3Salary, serviceYears)
+	}
+	reduction, ok := DeferredAnnuityReduction(startAge, dateutil.MinimumRetirementAge(p.BirthDate), serviceYears)
+	if !ok {
+		return decimalZero
+	}
 
-		if survivorElection.Equals(half) {
-			reduced = pensionBase.Mul(decimal.NewFromFloat(0.90))
-			survivor = pensionBase.Mul(half)
-		} else if survivorElection.Equals(quarter) {
-			reduced = pensionBase.Mul(decimal.NewFromFloat(0.95))
-			survivor = pensionBase.Mul(quarter)
-		}
+	multiplier := decimal.NewFromFloat(0.01)
+	if startAge >= 62 && serviceYears.GreaterThanOrEqual(decimal.NewFromInt(20)) {
+		multiplier = decimal.NewFromFloat(0.011)
 	}
 
-	return reduced, survivor
+	return p.High3Salary.Mul(serviceYears).Mul(multiplier).Mul(decimalOne.Sub(reduction))
+}
+
+// addWithdrawalShortfall records an engine warning when a requested withdrawal was
+// truncated by the available balance
+func addWithdrawalShortfall(cf *domain.AnnualCashFlow, p *domain.Participant, year int, requested, withdrawn decimal.Decimal) {
+	shortfall := requested.Sub(withdrawn)
+	if shortfall.LessThan(decimal.NewFromInt(1)) {
+		return
+	}
+	cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+		Year:        year,
+		Participant: p.Name,
+		Code:        domain.WarningWithdrawalShortfall,
+		Amount:      shortfall,
+		Message: fmt.Sprintf("%s: requested withdrawal of $%s exceeded available balances; $%s short",
+			p.Label(), requested.StringFixed(0), shortfall.StringFixed(0)),
+	})
 }
 
 func applyParticipantFERSCOLA(currentPension decimal.Decimal, inflationRate decimal.Decimal, annuitantAge int) decimal.Decimal {
-	if annuitantAge < 62 {
+	if annuitantAge < 62 || inflationRate.LessThan(decimalZero) {
 		return currentPension
 	}
 
@@ -1226,3 +2300,19 @@ func aliveParticipantsForYear(h *domain.Household, deathYears map[string]*int, y
 }
 
 // Legacy two-person GenerateAnnualProjection removed; use GenerateAnnualProjectionGeneric.
+
+// taxableSocialSecurity returns the federally taxable share of the year's Social Security
+// benefits from provisional income: the rest of the AGI, traced by magiBreakdown, and
+// tax-exempt interest plus half the benefits
+func (ce *CalculationEngine) taxableSocialSecurity(cf *domain.AnnualCashFlow, otherTaxableIncome, taxExemptInterest decimal.Decimal, filingStatus string) decimal.Decimal {
+	benefits := cf.GetTotalSSBenefit()
+	if ce == nil || ce.TaxCalc == nil || !benefits.IsPositive() {
+		return benefits
+	}
+	otherIncome := magiBreakdown(cf, decimalZero, otherTaxableIncome, taxExemptInterest).AGI
+	provisional := ce.TaxCalc.SSTaxCalc.CalculateProvisionalIncome(otherIncome, taxExemptInterest, benefits)
+	if filingStatus == "single" {
+		return ce.TaxCalc.SSTaxCalc.CalculateTaxableSocialSecuritySingle(benefits, provisional)
+	}
+	return ce.TaxCalc.SSTaxCalc.CalculateTaxableSocialSecurity(benefits, provisional)
+}
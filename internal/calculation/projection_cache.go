@@ -0,0 +1,267 @@
+package calculation
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// maxProjectionCacheEntries bounds the number of households/assumption sets kept; the
+// cache is cleared when a new one would exceed it
+const maxProjectionCacheEntries = 64
+
+// ProjectionCache shares work between scenarios of the same household. Scenarios project
+// identical working years until their first scenario-specific event (a retirement or
+// separation, Social Security claim, death, part-time period, Roth conversion, QCD, tax
+// election, TSP or income annuity purchase, allocation schedule, Medicare coverage or Part
+// B enrollment age), so a projection resumes from the state another scenario reached in
+// the last shared year instead of recomputing it. Scenarios differing in any other setting
+// share nothing. It is safe for concurrent use.
+type ProjectionCache struct {
+	mu        sync.Mutex
+	prefixes  map[string]map[int]*projectionPrefix // cache key -> number of years -> prefix
+	baselines map[string]decimal.Decimal           // household key -> current net income
+}
+
+// projectionPrefix is the first years of a projection and the state at the end of them
+type projectionPrefix struct {
+	years          []domain.AnnualCashFlow
+	states         map[string]participantState
+	itemizedGrowth decimal.Decimal
+}
+
+// NewProjectionCache creates an empty projection cache
+func NewProjectionCache() *ProjectionCache {
+	return &ProjectionCache{
+		prefixes:  make(map[string]map[int]*projectionPrefix),
+		baselines: make(map[string]decimal.Decimal),
+	}
+}
+
+// Reset discards every cached projection year and baseline
+func (pc *ProjectionCache) Reset() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.prefixes = make(map[string]map[int]*projectionPrefix)
+	pc.baselines = make(map[string]decimal.Decimal)
+}
+
+// longestPrefix returns the longest cached prefix of at most maxYears years, or nil
+func (pc *ProjectionCache) longestPrefix(key string, maxYears int) *projectionPrefix {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	var best *projectionPrefix
+	for n, prefix := range pc.prefixes[key] {
+		if n <= maxYears && (best == nil || n > len(best.years)) {
+			best = prefix
+		}
+	}
+	return best
+}
+
+// storePrefix caches a prefix unless one of the same length is already cached
+func (pc *ProjectionCache) storePrefix(key string, prefix *projectionPrefix) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	byLength, ok := pc.prefixes[key]
+	if !ok {
+		if len(pc.prefixes) >= maxProjectionCacheEntries {
+			pc.prefixes = make(map[string]map[int]*projectionPrefix)
+		}
+		byLength = make(map[int]*projectionPrefix)
+		pc.prefixes[key] = byLength
+	}
+	if _, exists := byLength[len(prefix.years)]; !exists {
+		byLength[len(prefix.years)] = prefix
+	}
+}
+
+// baseline returns the cached value for key, computing and caching it on a miss
+func (pc *ProjectionCache) baseline(key string, compute func() decimal.Decimal) decimal.Decimal {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if v, ok := pc.baselines[key]; ok {
+		return v
+	}
+	if len(pc.baselines) >= maxProjectionCacheEntries {
+		pc.baselines = make(map[string]decimal.Decimal)
+	}
+	v := compute()
+	pc.baselines[key] = v
+	return v
+}
+
+// newProjectionPrefix snapshots the years projected so far and the participant states
+// entering the next year. The years are copied so later changes to the caller's
+// projection do not leak into the cache.
+func newProjectionPrefix(years []domain.AnnualCashFlow, states map[string]*participantState, itemizedGrowth decimal.Decimal, participantNames []string) *projectionPrefix {
+	index := domain.NewParticipantIndex(participantNames)
+	prefix := &projectionPrefix{
+		years:          make([]domain.AnnualCashFlow, len(years)),
+		states:         make(map[string]participantState, len(states)),
+		itemizedGrowth: itemizedGrowth,
+	}
+	for i := range years {
+		prefix.years[i] = years[i].CloneWithIndex(index)
+	}
+	for name, st := range states {
+		prefix.states[name] = *st
+	}
+	return prefix
+}
+
+// restore copies the prefix into projection and states and returns the number of years
+// restored. The scenario's own retirement timing, claiming age and pension election already
+// set in states are kept; they have not yet affected any of the restored years.
+func (pp *projectionPrefix) restore(projection []domain.AnnualCashFlow, states map[string]*participantState, index *domain.ParticipantIndex) int {
+	for i := range pp.years {
+		projection[i] = pp.years[i].CloneWithIndex(index)
+	}
+	for name, saved := range pp.states {
+		st := states[name]
+		saved.retirementYear, saved.retirementDate = st.retirementYear, st.retirementDate
+		saved.deferredAnnuityAge, saved.ssStartAge = st.deferredAnnuityAge, st.ssStartAge
+		saved.pensionLumpSum = st.pensionLumpSum
+		*st = saved
+	}
+	return len(pp.years)
+}
+
+// projectionCacheKey identifies the inputs a projection shares across scenarios: everything
+// but the scenario settings scenarioDivergenceYear dates. It is empty when the inputs cannot
+// be encoded, which disables caching for the projection.
+func projectionCacheKey(ce *CalculationEngine, household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions, federalRules domain.FederalRules) string {
+	data, err := json.Marshal(struct {
+		Household    *domain.Household
+		Scenario     *domain.GenericScenario
+		Assumptions  *domain.GlobalAssumptions
+		FederalRules domain.FederalRules
+		Data         string
+	}{household, undatedScenarioSettings(scenario), assumptions, federalRules, fmt.Sprintf("%p/%p", ce.HistoricalData, ce.LifecycleFundLoader)})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return string(sum[:])
+}
+
+// undatedScenarioSettings returns a copy of the scenario with the settings cleared whose
+// first effect scenarioDivergenceYear dates, directly or through the retirement they
+// follow. What remains, including any setting added later, keeps the scenarios that differ
+// in it from sharing years until scenarioDivergenceYear dates it too.
+func undatedScenarioSettings(scenario *domain.GenericScenario) *domain.GenericScenario {
+	if scenario == nil {
+		return nil
+	}
+	undated := *scenario
+	undated.Name = ""
+	undated.Mortality = nil // through the death years
+	undated.TaxElections = nil
+	undated.CashFlowEvents = nil
+	undated.SpendingPhases = nil
+	undated.MAGICeiling = nil // clips dated conversions and withdrawals in retirement
+	undated.ParticipantScenarios = make(map[string]domain.ParticipantScenario, len(scenario.ParticipantScenarios))
+	for name, ps := range scenario.ParticipantScenarios {
+		// Retirement timing and claiming ages are dated through the participant states;
+		// withdrawal settings and an annuity bought at retirement follow the retirement
+		ps.ParticipantName = ""
+		ps.RetirementDate, ps.SeparationDate, ps.AnnuityStartAge, ps.SSStartAge = nil, nil, 0, 0
+		ps.TSPWithdrawalStrategy, ps.TSPWithdrawalTargetMonthly, ps.TSPWithdrawalRate = "", nil, nil
+		ps.SEPPInterestRate, ps.VPWStockAllocation, ps.SpendingTarget = nil, nil, nil
+		ps.Guardrails, ps.Buckets = nil, nil
+		ps.RothConversions, ps.PartTimeWork, ps.QCDs = nil, nil, nil
+		ps.TSPAnnuity, ps.AnnuityPurchases, ps.TSPAllocationSchedule = nil, nil, nil
+		ps.MedicareCoverage, ps.PartBEnrollmentAge = "", 0
+		undated.ParticipantScenarios[name] = ps
+	}
+	return &undated
+}
+
+// householdCacheKey identifies a household for the baseline net income cache
+func householdCacheKey(household *domain.Household) string {
+	data, err := json.Marshal(household)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return string(sum[:])
+}
+
+// scenarioDivergenceYear returns the index of the first projection year whose results can
+// depend on the scenario. Earlier years are the same for every scenario of the household.
+// states must already hold the scenario's retirement timing and claiming ages.
+func scenarioDivergenceYear(household *domain.Household, scenario *domain.GenericScenario, states map[string]*participantState, deathYears map[string]*int, startYear, years int, additionalIncome map[int]decimal.Decimal) int {
+	first := years
+	earliest := func(idx int) {
+		if idx < first {
+			first = max(idx, 0)
+		}
+	}
+
+	for i := range household.Participants {
+		p := &household.Participants[i]
+		st := states[p.Name]
+		if st.retired {
+			earliest(0) // withdrawals follow the scenario from the first year
+		}
+		if st.retirementYear != nil {
+			earliest(*st.retirementYear)
+		}
+		earliest(p.BirthDate.Year() + st.ssStartAge - startYear)
+		if dy := deathYears[p.Name]; dy != nil {
+			earliest(*dy)
+		}
+
+		if scenario == nil {
+			continue
+		}
+		ps, ok := scenario.ParticipantScenarios[p.Name]
+		if !ok {
+			continue
+		}
+		if ps.PartTimeWork != nil {
+			earliest(ps.PartTimeWork.StartDate.Year() - startYear)
+			for _, period := range ps.PartTimeWork.Schedule {
+				earliest(period.PeriodStart.Year() - startYear)
+			}
+		}
+		if ps.RothConversions != nil {
+			for _, conversion := range ps.RothConversions.Conversions {
+				earliest(conversion.Year - startYear)
+			}
+		}
+		if ps.QCDs != nil {
+			earliest(p.BirthDate.Year() + 70 - startYear) // QCDs begin at 70½
+		}
+		if ps.TSPAnnuity != nil && ps.TSPAnnuity.PurchaseAge > 0 {
+			earliest(p.BirthDate.Year() + ps.TSPAnnuity.PurchaseAge - startYear)
+		}
+		for _, purchase := range ps.AnnuityPurchases {
+			earliest(p.BirthDate.Year() + purchase.PurchaseAge - startYear)
+		}
+		if ps.MedicareCoverage != "" || ps.PartBEnrollmentAge > 0 {
+			earliest(MedicareEligibilityDate(p.BirthDate).Year() - startYear) // Medicare choices apply from eligibility, even while working
+		}
+		if len(ps.TSPAllocationSchedule) > 0 {
+			earliest(0) // the schedule sets the allocation from the first year
+		}
+	}
+
+	if scenario != nil {
+		for _, election := range scenario.TaxElections {
+			earliest(election.Year - startYear)
+		}
+		for _, e := range scenario.CashFlowEvents {
+			earliest(e.Date.Year() - startYear)
+		}
+		earliest(spendingPhaseStartYear(household, scenario, startYear, years))
+	}
+	for year := range additionalIncome {
+		earliest(year - startYear)
+	}
+	return first
+}
@@ -0,0 +1,221 @@
+package calculation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func projectionCacheConfig() *domain.Configuration {
+	retire := func(year int) *time.Time {
+		d := time.Date(year, 6, 30, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	return &domain.Configuration{
+		Household: &domain.Household{
+			FilingStatus: "married_filing_jointly",
+			Participants: []domain.Participant{
+				{
+					Name:                    "Alex",
+					BirthDate:               time.Date(1975, 3, 1, 0, 0, 0, 0, time.UTC),
+					HireDate:                timePtr(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)),
+					CurrentSalary:           decimalPtr(decimal.NewFromInt(120000)),
+					High3Salary:             decimalPtr(decimal.NewFromInt(115000)),
+					TSPBalanceTraditional:   decimalPtr(decimal.NewFromInt(400000)),
+					TSPBalanceRoth:          decimalPtr(decimal.NewFromInt(50000)),
+					TSPContributionPercent:  decimalPtr(decimal.NewFromFloat(0.10)),
+					SSBenefitFRA:            decimal.NewFromInt(2800),
+					FEHBPremiumPerPayPeriod: decimalPtr(decimal.NewFromInt(300)),
+					IsPrimaryFEHBHolder:     true,
+					IsFederal:               true,
+				},
+				{
+					Name:                   "Blair",
+					BirthDate:              time.Date(1977, 9, 1, 0, 0, 0, 0, time.UTC),
+					HireDate:               timePtr(time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)),
+					CurrentSalary:          decimalPtr(decimal.NewFromInt(95000)),
+					High3Salary:            decimalPtr(decimal.NewFromInt(90000)),
+					TSPBalanceTraditional:  decimalPtr(decimal.NewFromInt(250000)),
+					TSPBalanceRoth:         decimalPtr(decimal.Zero),
+					TSPContributionPercent: decimalPtr(decimal.NewFromFloat(0.08)),
+					SSBenefitFRA:           decimal.NewFromInt(2200),
+					IsFederal:              true,
+				},
+			},
+		},
+		GlobalAssumptions: domain.GlobalAssumptions{
+			ProjectionYears:         30,
+			InflationRate:           decimal.NewFromFloat(0.025),
+			COLAGeneralRate:         decimal.NewFromFloat(0.025),
+			FEHBPremiumInflation:    decimal.NewFromFloat(0.05),
+			TSPReturnPreRetirement:  decimal.NewFromFloat(0.06),
+			TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+		},
+		Scenarios: []domain.GenericScenario{
+			{
+				Name: "retire 2032",
+				ParticipantScenarios: map[string]domain.ParticipantScenario{
+					"Alex":  {ParticipantName: "Alex", RetirementDate: retire(2032), SSStartAge: 67, TSPWithdrawalStrategy: "4_percent_rule"},
+					"Blair": {ParticipantName: "Blair", RetirementDate: retire(2034), SSStartAge: 67, TSPWithdrawalStrategy: "4_percent_rule"},
+				},
+			},
+			{
+				Name: "retire 2035",
+				ParticipantScenarios: map[string]domain.ParticipantScenario{
+					"Alex":  {ParticipantName: "Alex", RetirementDate: retire(2035), SSStartAge: 70, TSPWithdrawalStrategy: "4_percent_rule"},
+					"Blair": {ParticipantName: "Blair", RetirementDate: retire(2035), SSStartAge: 62, TSPWithdrawalStrategy: "4_percent_rule"},
+				},
+				TaxElections: []domain.TaxElection{{Year: 2036, Deduction: domain.DeductionItemized}},
+			},
+		},
+	}
+}
+
+func TestProjectionCache_ReusesSharedWorkingYears(t *testing.T) {
+	cfg := projectionCacheConfig()
+	ce := NewCalculationEngine()
+	uncached := NewCalculationEngine()
+	uncached.ProjectionCache = nil
+
+	for i := range cfg.Scenarios {
+		got, err := ce.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[i])
+		require.NoError(t, err)
+		want, err := uncached.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[i])
+		require.NoError(t, err)
+
+		gotJSON, _ := json.Marshal(got.Projection)
+		wantJSON, _ := json.Marshal(want.Projection)
+		assert.JSONEq(t, string(wantJSON), string(gotJSON), cfg.Scenarios[i].Name)
+	}
+
+	// The first scenario diverges at Alex's 2032 retirement (7 shared years) and the second
+	// at the 2035 retirements (10 shared years), resuming from the first scenario's prefix
+	key := projectionCacheKey(ce, cfg.Household, &cfg.Scenarios[0], &cfg.GlobalAssumptions, cfg.GlobalAssumptions.FederalRules)
+	require.Contains(t, ce.ProjectionCache.prefixes, key)
+	lengths := []int{}
+	for n := range ce.ProjectionCache.prefixes[key] {
+		lengths = append(lengths, n)
+	}
+	assert.ElementsMatch(t, []int{7, 10}, lengths)
+
+	// Changing a cached result must not leak into later projections
+	first, err := ce.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[0])
+	require.NoError(t, err)
+	first.Projection[0].Salaries.Set("Alex", decimal.Zero)
+	again, err := ce.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[0])
+	require.NoError(t, err)
+	assert.True(t, again.Projection[0].Salaries.Get("Alex").Equal(decimal.NewFromInt(120000)))
+}
+
+func TestProjectionCacheKey_UndatedScenarioSettings(t *testing.T) {
+	cfg := projectionCacheConfig()
+	ce := NewCalculationEngine()
+	key := func(scenario *domain.GenericScenario) string {
+		return projectionCacheKey(ce, cfg.Household, scenario, &cfg.GlobalAssumptions, cfg.GlobalAssumptions.FederalRules)
+	}
+
+	// Scenarios differing only in dated settings share a key
+	assert.Equal(t, key(&cfg.Scenarios[0]), key(&cfg.Scenarios[1]))
+
+	// A setting scenarioDivergenceYear doesn't date keeps scenarios apart
+	declined := cfg.Scenarios[0].DeepCopy()
+	alex := declined.ParticipantScenarios["Alex"]
+	alex.DeclineMilitaryDeposit = true
+	declined.ParticipantScenarios["Alex"] = alex
+	assert.NotEqual(t, key(&cfg.Scenarios[0]), key(declined))
+	assert.False(t, cfg.Scenarios[0].ParticipantScenarios["Alex"].DeclineMilitaryDeposit, "the scenario itself is left unchanged")
+}
+
+func TestProjectionCache_SkipsMarketPaths(t *testing.T) {
+	cfg := projectionCacheConfig()
+	cfg.GlobalAssumptions.MarketPath = []domain.MarketYear{{
+		InflationRate: decimal.NewFromFloat(0.03),
+		COLARate:      decimal.NewFromFloat(0.03),
+		FEHBInflation: decimal.NewFromFloat(0.05),
+	}}
+	ce := NewCalculationEngine()
+	_, err := ce.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[0])
+	require.NoError(t, err)
+	assert.Empty(t, ce.ProjectionCache.prefixes)
+}
+
+func TestScenarioDivergenceYear(t *testing.T) {
+	cfg := projectionCacheConfig()
+	household := cfg.Household
+	states := map[string]*participantState{
+		"Alex":  {ssStartAge: 67},
+		"Blair": {ssStartAge: 67},
+	}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{}}
+
+	// Alex claims Social Security at 67 in 2042
+	assert.Equal(t, 17, scenarioDivergenceYear(household, scenario, states, nil, 2025, 30, nil))
+
+	ry := 8
+	states["Blair"].retirementYear = &ry
+	assert.Equal(t, 8, scenarioDivergenceYear(household, scenario, states, nil, 2025, 30, nil))
+
+	death := 6
+	assert.Equal(t, 6, scenarioDivergenceYear(household, scenario, states, map[string]*int{"Alex": &death}, 2025, 30, nil))
+
+	scenario.TaxElections = []domain.TaxElection{{Year: 2030, Deduction: domain.DeductionStandard}}
+	assert.Equal(t, 5, scenarioDivergenceYear(household, scenario, states, nil, 2025, 30, nil))
+
+	scenario.ParticipantScenarios["Blair"] = domain.ParticipantScenario{
+		RothConversions: &domain.RothConversionSchedule{Conversions: []domain.RothConversion{{Year: 2027}}},
+	}
+	assert.Equal(t, 2, scenarioDivergenceYear(household, scenario, states, nil, 2025, 30, nil))
+
+	assert.Equal(t, 1, scenarioDivergenceYear(household, scenario, states, nil, 2025, 30, map[int]decimal.Decimal{2026: decimal.NewFromInt(1000)}))
+
+	// Participants already retired follow the scenario's withdrawal choices from the start
+	states["Alex"].retired = true
+	assert.Equal(t, 0, scenarioDivergenceYear(household, scenario, states, nil, 2025, 30, nil))
+}
+
+func TestProjectionCache_MedicareChoicesWhileWorking(t *testing.T) {
+	cfg := projectionCacheConfig()
+	retire := time.Date(2042, 6, 30, 0, 0, 0, 0, time.UTC)
+	scenario := func(name string, alex domain.ParticipantScenario) domain.GenericScenario {
+		alex.ParticipantName, alex.RetirementDate, alex.SSStartAge, alex.TSPWithdrawalStrategy = "Alex", &retire, 70, "4_percent_rule"
+		return domain.GenericScenario{
+			Name: name,
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Alex":  alex,
+				"Blair": {ParticipantName: "Blair", RetirementDate: &retire, SSStartAge: 70, TSPWithdrawalStrategy: "4_percent_rule"},
+			},
+		}
+	}
+	// Alex reaches Medicare in 2040, still working; the first scenario caches those years
+	cfg.Scenarios = []domain.GenericScenario{
+		scenario("FEHB", domain.ParticipantScenario{}),
+		scenario("Medigap", domain.ParticipantScenario{MedicareCoverage: domain.MedicareCoverageMedigap}),
+		scenario("Part B at 69", domain.ParticipantScenario{PartBEnrollmentAge: 69}),
+	}
+
+	ce := NewCalculationEngine()
+	uncached := NewCalculationEngine()
+	uncached.ProjectionCache = nil
+	var fehb []domain.AnnualCashFlow
+	for i := range cfg.Scenarios {
+		got, err := ce.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[i])
+		require.NoError(t, err)
+		want, err := uncached.RunGenericScenario(context.Background(), cfg, &cfg.Scenarios[i])
+		require.NoError(t, err)
+
+		gotJSON, _ := json.Marshal(got.Projection)
+		wantJSON, _ := json.Marshal(want.Projection)
+		assert.JSONEq(t, string(wantJSON), string(gotJSON), cfg.Scenarios[i].Name)
+		if i == 0 {
+			fehb = want.Projection
+		} else {
+			assert.False(t, want.Projection[15].NetIncome.Equal(fehb[15].NetIncome), "%s: expected 2040 income to differ from FEHB", cfg.Scenarios[i].Name)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package calculation
+
+import (
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// QCDAnnualLimit is the 2025 per-person cap on qualified charitable distributions
+var QCDAnnualLimit = decimal.NewFromInt(108000)
+
+// IsQCDEligible reports whether someone born on birthDate reaches age 70½ by yearEnd
+func IsQCDEligible(birthDate time.Time, yearEnd time.Time) bool {
+	return !birthDate.AddDate(70, 6, 0).After(yearEnd)
+}
+
+// qcdForYear returns the QCD a participant makes in a calendar year, capped at the
+// annual limit. Ineligible years return zero rather than an error so a schedule can
+// be set once without tracking the exact eligibility date.
+func qcdForYear(schedule *domain.QCDSchedule, birthDate time.Time, year int) decimal.Decimal {
+	if schedule == nil {
+		return decimalZero
+	}
+	yearEnd := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+	if !IsQCDEligible(birthDate, yearEnd) {
+		return decimalZero
+	}
+	amount := schedule.AmountForYear(year)
+	if amount.LessThan(decimalZero) {
+		return decimalZero
+	}
+	return decimal.Min(amount, QCDAnnualLimit)
+}
@@ -0,0 +1,115 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQCDForYear(t *testing.T) {
+	birth := time.Date(1955, 9, 15, 0, 0, 0, 0, time.UTC) // 70½ on 2026-03-15
+	schedule := &domain.QCDSchedule{
+		AnnualAmount: decimal.NewFromInt(10000),
+		Distributions: []domain.QCDDistribution{
+			{Year: 2027, Amount: decimal.NewFromInt(25000)},
+			{Year: 2028, Amount: decimal.NewFromInt(500000)},
+		},
+	}
+
+	assert.True(t, qcdForYear(schedule, birth, 2025).IsZero(), "not yet 70½")
+	assert.True(t, qcdForYear(schedule, birth, 2026).Equal(decimal.NewFromInt(10000)))
+	assert.True(t, qcdForYear(schedule, birth, 2027).Equal(decimal.NewFromInt(25000)))
+	assert.True(t, qcdForYear(schedule, birth, 2028).Equal(QCDAnnualLimit))
+	assert.True(t, qcdForYear(nil, birth, 2030).IsZero())
+}
+
+func TestProjectionQCDReducesTaxableIncomeAndMAGI(t *testing.T) {
+	retired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{{
+			Name:                  "Alice",
+			BirthDate:             time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC),
+			TSPBalanceTraditional: decimalPtr(decimal.NewFromInt(800000)),
+			TSPBalanceRoth:        decimalPtr(decimal.Zero),
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         2,
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.04),
+	}
+	scenario := func(qcds *domain.QCDSchedule) *domain.GenericScenario {
+		return &domain.GenericScenario{
+			Name: "qcd",
+			ParticipantScenarios: map[string]domain.ParticipantScenario{
+				"Alice": {ParticipantName: "Alice", RetirementDate: &retired, SSStartAge: 70, TSPWithdrawalStrategy: "4_percent_rule", QCDs: qcds},
+			},
+		}
+	}
+
+	ce := NewCalculationEngine()
+	without := ce.GenerateAnnualProjectionGeneric(household, scenario(nil), assumptions, domain.FederalRules{})
+	with := ce.GenerateAnnualProjectionGeneric(household, scenario(&domain.QCDSchedule{AnnualAmount: decimal.NewFromInt(15000)}), assumptions, domain.FederalRules{})
+	require.Len(t, with, 2)
+
+	base, qcd := without[0], with[0]
+	assert.True(t, qcd.QualifiedCharitableDistributions.Equal(decimal.NewFromInt(15000)))
+	assert.True(t, qcd.GetTotalTSPWithdrawal().Equal(base.GetTotalTSPWithdrawal()), "QCD is satisfied from the existing withdrawal")
+	assert.True(t, base.MAGI.Sub(qcd.MAGI).Equal(decimal.NewFromInt(15000)))
+	assert.True(t, qcd.FederalTax.LessThan(base.FederalTax))
+}
+
+func TestProjectionQCDComesFromPreTaxDistributions(t *testing.T) {
+	retired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	project := func(p domain.Participant, ps domain.ParticipantScenario) domain.AnnualCashFlow {
+		household := &domain.Household{FilingStatus: "single", Participants: []domain.Participant{p}}
+		ps.ParticipantName, ps.RetirementDate, ps.SSStartAge = p.Name, &retired, 70
+		scenario := &domain.GenericScenario{Name: "qcd", ParticipantScenarios: map[string]domain.ParticipantScenario{p.Name: ps}}
+		assumptions := &domain.GlobalAssumptions{ProjectionYears: 1, TSPReturnPostRetirement: decimal.NewFromFloat(0.04)}
+		return NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})[0]
+	}
+	qcds := &domain.QCDSchedule{AnnualAmount: decimal.NewFromInt(10000)}
+	rate := decimal.NewFromFloat(0.04)
+
+	// Roth withdrawals cannot fund a QCD, so a Roth-only TSP gives nothing and saves no tax
+	rothOnly := domain.Participant{
+		Name:                  "Robin",
+		BirthDate:             time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC),
+		TSPBalanceTraditional: decimalPtr(decimal.Zero),
+		TSPBalanceRoth:        decimalPtr(decimal.NewFromInt(500000)),
+	}
+	strategy := domain.ParticipantScenario{TSPWithdrawalStrategy: "variable_percentage", TSPWithdrawalRate: &rate}
+	base := project(rothOnly, strategy)
+	strategy.QCDs = qcds
+	cf := project(rothOnly, strategy)
+	assert.True(t, cf.QualifiedCharitableDistributions.IsZero())
+	assert.Equal(t, base.MAGI.String(), cf.MAGI.String())
+	assert.False(t, cf.MAGIBreakdown.RetirementDistributions.IsNegative())
+
+	// Nor can a conversion: the QCD is drawn on top of it, and the conversion stays taxable
+	converter := domain.Participant{
+		Name:                  "Casey",
+		BirthDate:             time.Date(1953, 6, 1, 0, 0, 0, 0, time.UTC), // 72, past 70½ and before RMDs
+		TSPBalanceTraditional: decimalPtr(decimal.NewFromInt(200000)),
+		TSPBalanceRoth:        decimalPtr(decimal.Zero),
+	}
+	cf = project(converter, domain.ParticipantScenario{
+		QCDs:            qcds,
+		RothConversions: &domain.RothConversionSchedule{Conversions: []domain.RothConversion{{Year: ProjectionBaseYear, Amount: decimal.NewFromInt(50000)}}},
+	})
+	assert.Equal(t, "10000", cf.QualifiedCharitableDistributions.String())
+	assert.Equal(t, "60000", cf.GetTotalTSPWithdrawal().String())
+	assert.Equal(t, "50000", cf.MAGIBreakdown.RetirementDistributions.String())
+
+	// A traditional IRA gives the QCD before the TSP
+	converter.IRABalanceTraditional = decimalPtr(decimal.NewFromInt(100000))
+	cf = project(converter, domain.ParticipantScenario{QCDs: qcds})
+	assert.Equal(t, "10000", cf.QualifiedCharitableDistributions.String())
+	assert.Equal(t, "10000", cf.GetTotalTraditionalIRAWithdrawal().String())
+	assert.True(t, cf.GetTotalTSPWithdrawal().IsZero())
+	assert.True(t, cf.MAGIBreakdown.RetirementDistributions.IsZero())
+}
@@ -0,0 +1,139 @@
+package calculation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// Statistical return distributions for MonteCarloSettings.ReturnDistribution
+const (
+	ReturnDistributionNormal              = "normal"
+	ReturnDistributionStudentT            = "student_t"
+	ReturnDistributionHistoricalResiduals = "historical_residuals"
+)
+
+// defaultDegreesOfFreedom gives Student-t draws tails close to those of annual stock returns
+const defaultDegreesOfFreedom = 5
+
+// marketShocks draws standardized shocks, with zero mean and unit variance, for the
+// market factors; the statistical generators scale them by each series' variability
+type marketShocks struct {
+	correlation *correlatedNormals
+	// degreesOfFreedom makes the shocks Student-t instead of normal when positive
+	degreesOfFreedom int
+	// residuals holds each historical year's standardized residuals when resampling them
+	residuals [][]float64
+}
+
+// defaultMarketShocks draws normal shocks with the built-in correlation matrix
+var defaultMarketShocks = &marketShocks{correlation: defaultCorrelatedNormals}
+
+// newMarketShocks returns the shocks of the configured return distribution. Resampling
+// historical residuals falls back to normal shocks without enough historical data.
+func newMarketShocks(settings domain.MonteCarloSettings, hdm *HistoricalDataManager) *marketShocks {
+	shocks := &marketShocks{correlation: marketCorrelation(settings.CorrelationMatrix, hdm)}
+	switch settings.ReturnDistribution {
+	case ReturnDistributionStudentT:
+		shocks.degreesOfFreedom = settings.DegreesOfFreedom
+		if shocks.degreesOfFreedom <= 2 {
+			shocks.degreesOfFreedom = defaultDegreesOfFreedom
+		}
+	case ReturnDistributionHistoricalResiduals:
+		if residuals, err := historicalResiduals(hdm); err == nil {
+			shocks.residuals = residuals
+		}
+	}
+	return shocks
+}
+
+// draw returns one shock per market factor for a simulated year. Normal and Student-t
+// shocks follow the correlation matrix; a resampled historical year keeps its own.
+func (m *marketShocks) draw(rng *rand.Rand) []float64 {
+	if len(m.residuals) > 0 {
+		return slices.Clone(m.residuals[rng.Intn(len(m.residuals))])
+	}
+	z := m.correlation.draw(rng)
+	if m.degreesOfFreedom > 0 {
+		scale := m.studentTScale(rng)
+		for i := range z {
+			z[i] *= scale
+		}
+	}
+	return z
+}
+
+// drawFactor returns a shock for one market factor drawn on its own
+func (m *marketShocks) drawFactor(factor int, rng *rand.Rand) float64 {
+	if len(m.residuals) > 0 {
+		return m.residuals[rng.Intn(len(m.residuals))][factor]
+	}
+	z := rng.NormFloat64()
+	if m.degreesOfFreedom > 0 {
+		z *= m.studentTScale(rng)
+	}
+	return z
+}
+
+// studentTScale draws the factor sqrt((ν-2)/χ²ν) that turns normal shocks into
+// Student-t shocks with ν degrees of freedom and unit variance
+func (m *marketShocks) studentTScale(rng *rand.Rand) float64 {
+	chiSquared := 0.0
+	for i := 0; i < m.degreesOfFreedom; i++ {
+		n := rng.NormFloat64()
+		chiSquared += n * n
+	}
+	return math.Sqrt(float64(m.degreesOfFreedom-2) / chiSquared)
+}
+
+// marketFactorIndex returns the position of a fund or inflation among the market factors
+func marketFactorIndex(factor string) int {
+	return slices.Index(domain.MarketFactors, factor)
+}
+
+// historicalResiduals returns every complete historical year's market factors as
+// standardized residuals: the distance from each series' mean in standard deviations
+func historicalResiduals(hdm *HistoricalDataManager) ([][]float64, error) {
+	if hdm == nil || !hdm.IsLoaded || hdm.TSPFunds.CFund == nil {
+		return nil, fmt.Errorf("historical data not loaded")
+	}
+
+	var samples [][]float64
+	for _, point := range hdm.TSPFunds.CFund.DataPoints {
+		if sample, ok := marketFactorSample(hdm, point.Year); ok {
+			samples = append(samples, sample)
+		}
+	}
+	if len(samples) < minCorrelationYears {
+		return nil, fmt.Errorf("need %d years of complete history to resample residuals, have %d", minCorrelationYears, len(samples))
+	}
+
+	n := len(domain.MarketFactors)
+	means := make([]float64, n)
+	for _, sample := range samples {
+		for i, v := range sample {
+			means[i] += v / float64(len(samples))
+		}
+	}
+	stdDevs := make([]float64, n)
+	for i := range stdDevs {
+		for _, sample := range samples {
+			stdDevs[i] += (sample[i] - means[i]) * (sample[i] - means[i])
+		}
+		stdDevs[i] = math.Sqrt(stdDevs[i] / float64(len(samples)-1))
+	}
+
+	residuals := make([][]float64, len(samples))
+	for y, sample := range samples {
+		residuals[y] = make([]float64, n)
+		for i, v := range sample {
+			if stdDevs[i] > 0 {
+				residuals[y][i] = (v - means[i]) / stdDevs[i]
+			}
+		}
+	}
+	return residuals, nil
+}
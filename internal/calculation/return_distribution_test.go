@@ -0,0 +1,87 @@
+package calculation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudentTShocksHaveFatTails(t *testing.T) {
+	normal := newMarketShocks(domain.MonteCarloSettings{}, nil)
+	studentT := newMarketShocks(domain.MonteCarloSettings{ReturnDistribution: ReturnDistributionStudentT, DegreesOfFreedom: 5}, nil)
+	require.Equal(t, 5, studentT.degreesOfFreedom)
+
+	tails := func(shocks *marketShocks) (variance, tailShare float64) {
+		rng := rand.New(rand.NewSource(8))
+		const n = 200000
+		beyond := 0
+		for i := 0; i < n; i++ {
+			z := shocks.drawFactor(marketFactorIndex("C"), rng)
+			variance += z * z / n
+			if math.Abs(z) > 3 {
+				beyond++
+			}
+		}
+		return variance, float64(beyond) / n
+	}
+
+	normalVariance, normalTails := tails(normal)
+	tVariance, tTails := tails(studentT)
+	// Both are scaled to unit variance, so the configured variability keeps its meaning
+	assert.InDelta(t, 1, normalVariance, 0.02)
+	assert.InDelta(t, 1, tVariance, 0.05)
+	// Three-standard-deviation years are several times likelier with fat tails
+	assert.Greater(t, tTails, 1.5*normalTails)
+
+	// Unset degrees of freedom use the default
+	assert.Equal(t, defaultDegreesOfFreedom, newMarketShocks(domain.MonteCarloSettings{ReturnDistribution: ReturnDistributionStudentT}, nil).degreesOfFreedom)
+}
+
+func TestHistoricalResidualShocks(t *testing.T) {
+	hdm := loadSyntheticHistory(t)
+	shocks := newMarketShocks(domain.MonteCarloSettings{ReturnDistribution: ReturnDistributionHistoricalResiduals}, hdm)
+	require.Len(t, shocks.residuals, 12)
+
+	// Each year's residuals are standardized deviations from the series' means
+	var sum, sumSquares float64
+	for _, year := range shocks.residuals {
+		sum += year[0]
+		sumSquares += year[0] * year[0]
+	}
+	assert.InDelta(t, 0, sum, 1e-9)
+	assert.InDelta(t, 11, sumSquares, 1e-9)
+
+	// A draw is a whole historical year, so the funds keep that year's co-movement
+	draw := shocks.draw(rand.New(rand.NewSource(4)))
+	assert.Contains(t, shocks.residuals, draw)
+
+	// Without enough history the draws fall back to normal shocks
+	assert.Nil(t, newMarketShocks(domain.MonteCarloSettings{ReturnDistribution: ReturnDistributionHistoricalResiduals}, nil).residuals)
+}
+
+func TestFERSMonteCarloEngine_StudentTReturns(t *testing.T) {
+	config := createTestConfig()
+	config.GlobalAssumptions.MonteCarloSettings.ReturnDistribution = ReturnDistributionStudentT
+	config.GlobalAssumptions.MonteCarloSettings.DegreesOfFreedom = 3
+	engine := NewFERSMonteCarloEngine(config, nil)
+	engine.config.UseHistorical = false
+	engine.config.TSPReturnVariability = decimal.NewFromFloat(0.15)
+
+	// Returns more than three standard deviations from the mean turn up far more often
+	// than the 0.27% of a normal distribution
+	rng := rand.New(rand.NewSource(21))
+	beyond := 0
+	const n = 20000
+	for i := 0; i < n; i++ {
+		r := engine.generateStatisticalTSPReturn("C", rng)
+		if r.Sub(statisticalTSPMeans["C"]).Abs().GreaterThan(decimal.NewFromFloat(0.45)) {
+			beyond++
+		}
+	}
+	assert.Greater(t, float64(beyond)/n, 0.005)
+}
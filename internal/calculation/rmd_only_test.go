@@ -0,0 +1,37 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRMDOnlyStrategyInProjection(t *testing.T) {
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(600000)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+			ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70, TSPWithdrawalStrategy: "rmd_only",
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 9}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 9)
+
+	// Nothing is withdrawn before Gail's RMDs start at 73 in 2032
+	for _, cf := range projection[:7] {
+		assert.True(t, cf.TSPWithdrawals.Get("Gail").IsZero(), cf.Date.Year())
+	}
+	// Then exactly the required minimum: the balance over the 26.5-year distribution period
+	rmd := balance.Div(decimal.NewFromFloat(26.5))
+	assert.True(t, projection[7].TSPWithdrawals.Get("Gail").Equal(rmd), projection[7].TSPWithdrawals.Get("Gail").String())
+	assert.True(t, projection[8].TSPWithdrawals.Get("Gail").IsPositive())
+}
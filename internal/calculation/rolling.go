@@ -0,0 +1,151 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// RollingWindow is a scenario's outcome with history replayed from one starting year
+type RollingWindow struct {
+	StartYear int `json:"startYear"`
+	EndYear   int `json:"endYear"`
+
+	TotalLifetimeIncome decimal.Decimal `json:"totalLifetimeIncome"`
+	FinalTSPBalance     decimal.Decimal `json:"finalTSPBalance"`
+	// LowestTSPBalance is the smallest year-end TSP balance after retirement begins
+	LowestTSPBalance decimal.Decimal `json:"lowestTSPBalance"`
+	TSPLongevity     int             `json:"tspLongevity"`
+	// Success is true when the TSP lasts the whole projection
+	Success bool `json:"success"`
+}
+
+// RollingPeriodResult holds a scenario's outcomes over every historical window of a
+// fixed length, and the distribution across them
+type RollingPeriodResult struct {
+	ScenarioName    string `json:"scenarioName"`
+	Window          int    `json:"window"`
+	ProjectionYears int    `json:"projectionYears"`
+
+	// Windows holds each window's outcome in order of starting year
+	Windows     []RollingWindow `json:"windows"`
+	SuccessRate decimal.Decimal `json:"successRate"`
+
+	// Percentiles across windows: 10th, 25th, 50th, 75th, 90th
+	LifetimeIncome  map[string]decimal.Decimal `json:"lifetimeIncome"`
+	FinalTSPBalance map[string]decimal.Decimal `json:"finalTSPBalance"`
+
+	// The windows ranked worst, median and best by how long the TSP lasts, then by
+	// the final TSP balance, then by lifetime income
+	Worst  RollingWindow `json:"worst"`
+	Median RollingWindow `json:"median"`
+	Best   RollingWindow `json:"best"`
+}
+
+// RunRollingPeriods projects the named scenario once for every run of window
+// consecutive years of history, replaying each from the first projection year. Years
+// the window doesn't reach use the baseline assumptions, as in a stress test; a window
+// of 0 covers the whole projection. Like a stress test, every run uses the fund-level
+// return model.
+func (ce *CalculationEngine) RunRollingPeriods(ctx context.Context, config *domain.Configuration, scenarioName string, window int) (*RollingPeriodResult, error) {
+	hdm := ce.HistoricalData
+	if hdm == nil || !hdm.IsLoaded {
+		return nil, fmt.Errorf("rolling periods need historical data")
+	}
+	base, scenario, years, err := marketReplayBase(config, scenarioName)
+	if err != nil {
+		return nil, err
+	}
+	if window <= 0 || window > years {
+		window = years
+	}
+	baselineYear := ce.baselineMarketYear(&base.GlobalAssumptions)
+
+	minYear, maxYear, err := hdm.GetAvailableYears()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RollingPeriodResult{
+		ScenarioName:    scenarioName,
+		Window:          window,
+		ProjectionYears: years,
+	}
+	for start := minYear; start+window-1 <= maxYear; start++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path, ok := rollingPath(hdm, start, window, years, baselineYear)
+		if !ok {
+			continue
+		}
+		replayed := *base
+		replayed.GlobalAssumptions.MarketPath = path
+		summary, err := ce.RunGenericScenario(ctx, &replayed, scenario)
+		if err != nil {
+			return nil, fmt.Errorf("window starting %d: %w", start, err)
+		}
+		w := RollingWindow{
+			StartYear:           start,
+			EndYear:             start + window - 1,
+			TotalLifetimeIncome: summary.TotalLifetimeIncome,
+			TSPLongevity:        summary.TSPLongevity,
+			Success:             summary.TSPLongevity >= len(summary.Projection),
+		}
+		w.FinalTSPBalance, w.LowestTSPBalance = tspBalanceRange(summary)
+		result.Windows = append(result.Windows, w)
+	}
+	if len(result.Windows) == 0 {
+		return nil, fmt.Errorf("no complete %d-year window of history between %d and %d", window, minYear, maxYear)
+	}
+
+	successes := 0
+	incomes := make([]decimal.Decimal, len(result.Windows))
+	finals := make([]decimal.Decimal, len(result.Windows))
+	for i, w := range result.Windows {
+		if w.Success {
+			successes++
+		}
+		incomes[i], finals[i] = w.TotalLifetimeIncome, w.FinalTSPBalance
+	}
+	result.SuccessRate = decimal.NewFromInt(int64(successes)).Div(decimal.NewFromInt(int64(len(result.Windows))))
+	result.LifetimeIncome = calculatePercentiles(incomes)
+	result.FinalTSPBalance = calculatePercentiles(finals)
+
+	ranked := slices.Clone(result.Windows)
+	slices.SortStableFunc(ranked, compareRollingWindows)
+	result.Worst, result.Median, result.Best = ranked[0], ranked[(len(ranked)-1)/2], ranked[len(ranked)-1]
+	return result, nil
+}
+
+// rollingPath returns the market path replaying window years of history from start,
+// then the baseline to the end of the projection, or false when a year lacks data
+func rollingPath(hdm *HistoricalDataManager, start, window, years int, baseline domain.MarketYear) ([]domain.MarketYear, bool) {
+	path := make([]domain.MarketYear, 0, years)
+	for year := start; year < start+window; year++ {
+		market, ok := historicalMarketYear(hdm, year, baseline)
+		if !ok {
+			return nil, false
+		}
+		path = append(path, market)
+	}
+	for len(path) < years {
+		path = append(path, baseline)
+	}
+	return path, true
+}
+
+// compareRollingWindows orders windows from worst to best: by how long the TSP lasts,
+// then the final TSP balance, then lifetime income
+func compareRollingWindows(a, b RollingWindow) int {
+	if a.TSPLongevity != b.TSPLongevity {
+		return a.TSPLongevity - b.TSPLongevity
+	}
+	if c := a.FinalTSPBalance.Cmp(b.FinalTSPBalance); c != 0 {
+		return c
+	}
+	return a.TotalLifetimeIncome.Cmp(b.TotalLifetimeIncome)
+}
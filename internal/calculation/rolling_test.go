@@ -0,0 +1,52 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRollingPeriods(t *testing.T) {
+	config := createTestConfig()
+	ce := NewCalculationEngine()
+	ctx := context.Background()
+
+	_, err := ce.RunRollingPeriods(ctx, config, "Test Scenario", 5)
+	assert.ErrorContains(t, err, "need historical data")
+
+	ce.HistoricalData = loadSyntheticHistory(t)
+	result, err := ce.RunRollingPeriods(ctx, config, "Test Scenario", 5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Window)
+
+	// Every five-year window of 2000-2011, in order of starting year
+	require.Len(t, result.Windows, 8)
+	for i, w := range result.Windows {
+		assert.Equal(t, 2000+i, w.StartYear)
+		assert.Equal(t, 2004+i, w.EndYear)
+	}
+
+	// Different histories lead to different outcomes
+	assert.False(t, result.Windows[0].FinalTSPBalance.Equal(result.Windows[4].FinalTSPBalance))
+
+	// Worst, median and best are windows, in rank order
+	assert.Contains(t, result.Windows, result.Worst)
+	assert.Contains(t, result.Windows, result.Median)
+	assert.Contains(t, result.Windows, result.Best)
+	assert.LessOrEqual(t, compareRollingWindows(result.Worst, result.Median), 0)
+	assert.LessOrEqual(t, compareRollingWindows(result.Median, result.Best), 0)
+	for _, w := range result.Windows {
+		assert.LessOrEqual(t, compareRollingWindows(result.Worst, w), 0)
+		assert.GreaterOrEqual(t, compareRollingWindows(result.Best, w), 0)
+	}
+	assert.True(t, result.FinalTSPBalance["10th"].LessThanOrEqual(result.FinalTSPBalance["90th"]))
+
+	// A window longer than the data has no complete run
+	_, err = ce.RunRollingPeriods(ctx, config, "Test Scenario", 13)
+	assert.ErrorContains(t, err, "no complete")
+
+	_, err = ce.RunRollingPeriods(ctx, config, "Missing", 5)
+	assert.Error(t, err)
+}
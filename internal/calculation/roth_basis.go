@@ -0,0 +1,158 @@
+package calculation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// rothEarlyDistributionRate is the additional tax on the taxable part of an early
+// distribution, and on conversions distributed within five years of being made
+var rothEarlyDistributionRate = decimal.NewFromFloat(0.10)
+
+// rothConversionLayer is a conversion still in a Roth account and the year it was made,
+// which starts its own five-year clock
+type rothConversionLayer struct {
+	year   int
+	amount decimal.Decimal
+}
+
+// rothLedger splits a Roth account by how a distribution of it is taxed: contributions,
+// which come out tax and penalty free, conversions, which come out tax free but penalized
+// before 59½ within five years of the conversion, and earnings, the rest of the balance.
+// The layers are replaced rather than changed in place so participant states copied into
+// the projection cache never share them.
+type rothLedger struct {
+	basis       decimal.Decimal
+	conversions []rothConversionLayer
+	startYear   int // first contribution year; 0 when the five-year clock is already met
+}
+
+// newRothLedger starts a ledger for a configured balance; without a configured basis the
+// whole balance is treated as contributions
+func newRothLedger(balance, basis *decimal.Decimal, startYear int) rothLedger {
+	ledger := rothLedger{startYear: startYear}
+	switch {
+	case basis != nil:
+		ledger.basis = *basis
+	case balance != nil:
+		ledger.basis = *balance
+	}
+	return ledger
+}
+
+// contribute adds after-tax money, such as a contribution or a spouse's rolled-over
+// balance, to the basis
+func (l *rothLedger) contribute(amount decimal.Decimal) {
+	l.basis = l.basis.Add(amount)
+}
+
+// convert adds a conversion made in year
+func (l *rothLedger) convert(year int, amount decimal.Decimal) {
+	layers := make([]rothConversionLayer, len(l.conversions), len(l.conversions)+1)
+	copy(layers, l.conversions)
+	l.conversions = append(layers, rothConversionLayer{year: year, amount: amount})
+}
+
+// rothDistribution is how a Roth distribution is taxed
+type rothDistribution struct {
+	taxable   decimal.Decimal // earnings taxed as ordinary income
+	penalized decimal.Decimal // amount subject to the additional tax on early distributions
+}
+
+// distribute removes amount from the ledger of an account holding balance before the
+// distribution. A Roth IRA distributes its contributions first, then its conversions
+// oldest first, then earnings; the Roth TSP distributes a pro rata share of each. Earnings
+// are taxed unless the distribution is qualified, and before 59½ earnings and conversions
+// younger than five years are penalized.
+func (l *rothLedger) distribute(amount, balance decimal.Decimal, year int, ordered, qualified, under59Half bool) rothDistribution {
+	var d rothDistribution
+	if !amount.IsPositive() || !balance.IsPositive() {
+		return d
+	}
+	amount = decimal.Min(amount, balance)
+	share := amount.Div(balance)
+	take := func(available decimal.Decimal, remaining *decimal.Decimal) decimal.Decimal {
+		taken := decimal.Min(available.Mul(share), available)
+		if ordered {
+			taken = decimal.Min(available, *remaining)
+		}
+		*remaining = remaining.Sub(taken)
+		return taken
+	}
+
+	remaining := amount
+	l.basis = l.basis.Sub(take(l.basis, &remaining))
+	layers := make([]rothConversionLayer, 0, len(l.conversions))
+	for _, layer := range l.conversions {
+		taken := take(layer.amount, &remaining)
+		if under59Half && year-layer.year < 5 {
+			d.penalized = d.penalized.Add(taken)
+		}
+		if layer.amount = layer.amount.Sub(taken); layer.amount.IsPositive() {
+			layers = append(layers, layer)
+		}
+	}
+	l.conversions = layers
+
+	if earnings := decimal.Max(remaining, decimalZero); earnings.IsPositive() && !qualified {
+		d.taxable = earnings
+		if under59Half {
+			d.penalized = d.penalized.Add(earnings)
+		}
+	}
+	return d
+}
+
+// qualified reports whether a distribution in year is qualified: the owner is 59½ and the
+// account's five-year clock has run
+func (l *rothLedger) qualified(year int, under59Half bool) bool {
+	return !under59Half && (l.startYear == 0 || year-l.startYear >= 5)
+}
+
+// under59Half reports whether the participant is younger than 59½ at the end of year
+func under59Half(p *domain.Participant, year int) bool {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).Before(p.BirthDate.AddDate(59, 6, 0))
+}
+
+// settleRothDistributions taxes the year's distributions from the participant's Roth TSP
+// and Roth IRA against their ledgers. Earnings of nonqualified distributions are ordinary
+// income; the additional tax on early distributions is added to the year's federal tax and
+// flagged. Separating from service in or after the year the participant turns 55 exempts
+// Roth TSP distributions from the additional tax, and a SEPP schedule exempts all of them.
+func (st *participantState) settleRothDistributions(cf *domain.AnnualCashFlow, p *domain.Participant, year int) {
+	young := under59Half(p, year)
+	exempt := st.seppAnnual.IsPositive() && !st.seppBroken
+	var taxable, penalized decimal.Decimal
+	if st.rothTSPDistributed.IsPositive() {
+		d := st.rothTSP.distribute(st.rothTSPDistributed, st.tspBalanceRoth.Add(st.rothTSPDistributed), year, false, st.rothTSP.qualified(year, young), young)
+		taxable = taxable.Add(d.taxable)
+		if st.retirementDate == nil || st.retirementDate.Year() < p.BirthDate.Year()+55 {
+			penalized = penalized.Add(d.penalized)
+		}
+	}
+	if st.rothIRADistributed.IsPositive() {
+		d := st.rothIRA.distribute(st.rothIRADistributed, st.iraBalanceRoth.Add(st.rothIRADistributed), year, true, st.rothIRA.qualified(year, young), young)
+		taxable = taxable.Add(d.taxable)
+		penalized = penalized.Add(d.penalized)
+	}
+	st.rothTSPDistributed, st.rothIRADistributed = decimalZero, decimalZero
+
+	cf.RothTaxableEarnings = cf.RothTaxableEarnings.Add(taxable)
+	if exempt {
+		penalized = decimalZero
+	}
+	if additional := penalized.Mul(rothEarlyDistributionRate); additional.GreaterThanOrEqual(decimalOne) {
+		cf.EarlyDistributionTax = cf.EarlyDistributionTax.Add(additional)
+		cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+			Year:        year,
+			Participant: p.Label(),
+			Code:        domain.WarningEarlyRothDistribution,
+			Amount:      additional,
+			Message: fmt.Sprintf("%s: $%s of Roth distributions before 59½ come from earnings or conversions less than five years old; $%s of 10%% additional tax is due",
+				p.Label(), penalized.StringFixed(0), additional.StringFixed(0)),
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRothLedgerDistribute(t *testing.T) {
+	d := func(v int64) decimal.Decimal { return decimal.NewFromInt(v) }
+
+	// A Roth IRA pays out contributions, then conversions oldest first, then earnings
+	ira := rothLedger{basis: d(10000)}
+	ira.convert(2020, d(5000))
+	ira.convert(2024, d(15000))
+	got := ira.distribute(d(25000), d(40000), 2026, true, false, true)
+	assert.Equal(t, "0", got.taxable.String())
+	assert.Equal(t, "10000", got.penalized.String()) // the 2024 conversion is not five years old
+	assert.True(t, ira.basis.IsZero())
+	require.Len(t, ira.conversions, 1)
+	assert.Equal(t, "5000", ira.conversions[0].amount.String())
+	got = ira.distribute(d(15000), d(15000), 2026, true, false, true)
+	assert.Equal(t, "10000", got.taxable.String())
+	assert.Equal(t, "15000", got.penalized.String())
+	assert.Empty(t, ira.conversions)
+
+	// The Roth TSP pays out a pro rata share of each
+	tsp := rothLedger{basis: d(10000)}
+	tsp.convert(2020, d(20000))
+	got = tsp.distribute(d(10000), d(40000), 2026, false, false, true)
+	assert.Equal(t, "2500", got.taxable.String())
+	assert.Equal(t, "2500", got.penalized.String())
+	assert.Equal(t, "7500", tsp.basis.String())
+	assert.Equal(t, "15000", tsp.conversions[0].amount.String())
+
+	// A qualified distribution is tax free
+	got = tsp.distribute(d(10000), d(30000), 2040, false, true, false)
+	assert.True(t, got.taxable.IsZero())
+	assert.True(t, got.penalized.IsZero())
+
+	// Qualification takes 59½ and five years from the first contribution
+	late := rothLedger{startYear: 2022}
+	assert.False(t, late.qualified(2026, false))
+	assert.True(t, late.qualified(2027, false))
+	assert.False(t, late.qualified(2027, true))
+	met := rothLedger{}
+	assert.True(t, met.qualified(2027, false))
+}
+
+func TestProjectionEarlyRothDistributions(t *testing.T) {
+	balance := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{
+			{Name: "Pat", BirthDate: time.Date(1975, 3, 1, 0, 0, 0, 0, time.UTC),
+				TSPBalanceTraditional: balance(0), TSPBalanceRoth: balance(400000), TSPRothBasis: balance(100000), TSPRothStartYear: 2015},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         12,
+		InflationRate:           decimal.NewFromFloat(0.025),
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	retire := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	monthly := decimal.NewFromInt(2000)
+	ps := domain.ParticipantScenario{ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67,
+		TSPWithdrawalStrategy: "need_based", TSPWithdrawalTargetMonthly: &monthly}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{"Pat": ps}}
+	ce := NewCalculationEngine()
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 12)
+
+	// Separating at 50, Pat's Roth TSP withdrawals are three-quarters earnings, taxed and
+	// penalized until 59½
+	early := projection[1]
+	require.True(t, early.WithdrawalRoth.IsPositive())
+	assert.InDelta(t, early.WithdrawalRoth.Mul(decimal.NewFromFloat(0.75)).InexactFloat64(), early.RothTaxableEarnings.InexactFloat64(), 1000)
+	assert.Equal(t, early.RothTaxableEarnings.Mul(rothEarlyDistributionRate).StringFixed(2), early.EarlyDistributionTax.StringFixed(2))
+	assert.True(t, early.FederalTax.GreaterThanOrEqual(early.EarlyDistributionTax))
+	require.Len(t, early.Warnings, 1)
+	assert.Equal(t, domain.WarningEarlyRothDistribution, early.Warnings[0].Code)
+
+	// From 2034, the year Pat turns 59½, withdrawals are qualified
+	for _, cf := range projection {
+		if cf.Date.Year() >= 2034 {
+			assert.True(t, cf.RothTaxableEarnings.IsZero(), "%d", cf.Date.Year())
+			assert.True(t, cf.EarlyDistributionTax.IsZero(), "%d", cf.Date.Year())
+		}
+	}
+
+	// Separating in the year Pat turns 55 spares the Roth TSP the additional tax, but not
+	// the tax on earnings
+	retire = time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)
+	ps.RetirementDate = &retire
+	scenario.ParticipantScenarios["Pat"] = ps
+	projection = ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	after55 := projection[6]
+	assert.True(t, after55.RothTaxableEarnings.IsPositive())
+	assert.True(t, after55.EarlyDistributionTax.IsZero())
+}
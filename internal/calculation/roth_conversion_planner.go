@@ -103,14 +103,15 @@ func (rcp *RothConversionPlanner) PlanRothConversions(
 
 	// 6. Create the plan
 	plan := &domain.RothConversionPlan{
-		Participant:      participant,
-		ConversionWindow: window,
-		TargetBracket:    targetBracket,
-		Objective:        objective,
-		Baseline:         baseline,
-		Recommended:      optimal,
-		Alternatives:     results,
-		Analysis:         analysis,
+		Participant:       participant,
+		ParticipantLabels: config.Household.ParticipantLabels(),
+		ConversionWindow:  window,
+		TargetBracket:     targetBracket,
+		Objective:         objective,
+		Baseline:          baseline,
+		Recommended:       optimal,
+		Alternatives:      results,
+		Analysis:          analysis,
 	}
 
 	return plan, nil
@@ -260,6 +261,7 @@ func (rcp *RothConversionPlanner) evaluateConversionStrategy(
 	// Calculate metrics
 	lifetimeTax := rcp.calculateLifetimeTax(projection)
 	lifetimeIRMAA := rcp.calculateLifetimeIRMAA(projection)
+	lifetimePTC := rcp.calculateLifetimePTC(projection)
 	finalBalances := rcp.calculateFinalBalances(projection, participant)
 
 	// Calculate net benefit vs baseline
@@ -268,7 +270,8 @@ func (rcp *RothConversionPlanner) evaluateConversionStrategy(
 
 	taxDifference := lifetimeTax.Sub(baselineTax)
 	irmaaDifference := lifetimeIRMAA.Sub(baselineIRMAA)
-	netBenefit := taxDifference.Add(irmaaDifference).Neg() // Negative because we want to minimize costs
+	ptcLost := rcp.calculateLifetimePTC(baseline).Sub(lifetimePTC)
+	netBenefit := taxDifference.Add(irmaaDifference).Add(ptcLost).Neg() // Negative because we want to minimize costs
 
 	// Calculate ROI (simplified)
 	roi := decimal.Zero
@@ -277,13 +280,14 @@ func (rcp *RothConversionPlanner) evaluateConversionStrategy(
 	}
 
 	return domain.ConversionOutcome{
-		Strategy:      strategy,
-		Projection:    projection,
-		LifetimeTax:   lifetimeTax,
-		LifetimeIRMAA: lifetimeIRMAA,
-		FinalBalances: finalBalances,
-		NetBenefit:    netBenefit,
-		ROI:           roi,
+		Strategy:                 strategy,
+		Projection:               projection,
+		LifetimeTax:              lifetimeTax,
+		LifetimeIRMAA:            lifetimeIRMAA,
+		LifetimePremiumTaxCredit: lifetimePTC,
+		FinalBalances:            finalBalances,
+		NetBenefit:               netBenefit,
+		ROI:                      roi,
 	}, nil
 }
 
@@ -343,11 +347,12 @@ func (rcp *RothConversionPlanner) generateConversionAnalysis(
 
 	totalTaxPaid := optimal.LifetimeTax.Sub(rcp.calculateLifetimeTax(baseline))
 	irmaaSavings := rcp.calculateLifetimeIRMAA(baseline).Sub(optimal.LifetimeIRMAA)
+	ptcLost := rcp.calculateLifetimePTC(baseline).Sub(optimal.LifetimePremiumTaxCredit)
 
 	// Calculate RMD tax reduction (simplified)
 	rmdTaxReduction := decimal.Zero // TODO: Implement RMD tax reduction calculation
 
-	netBenefit := irmaaSavings.Add(rmdTaxReduction).Sub(totalTaxPaid)
+	netBenefit := irmaaSavings.Add(rmdTaxReduction).Sub(totalTaxPaid).Sub(ptcLost)
 
 	// Calculate ROI
 	roi := decimal.Zero
@@ -368,14 +373,15 @@ func (rcp *RothConversionPlanner) generateConversionAnalysis(
 	}
 
 	return &domain.ConversionAnalysis{
-		TotalConversions:    totalConversions,
-		TotalTaxPaid:        totalTaxPaid,
-		IRMAASavings:        irmaaSavings,
-		RMDTaxReduction:     rmdTaxReduction,
-		NetBenefit:          netBenefit,
-		ROI:                 roi,
-		Recommendation:      recommendation,
-		SensitivityAnalysis: sensitivity,
+		TotalConversions:     totalConversions,
+		TotalTaxPaid:         totalTaxPaid,
+		IRMAASavings:         irmaaSavings,
+		PremiumTaxCreditLost: ptcLost,
+		RMDTaxReduction:      rmdTaxReduction,
+		NetBenefit:           netBenefit,
+		ROI:                  roi,
+		Recommendation:       recommendation,
+		SensitivityAnalysis:  sensitivity,
 	}
 }
 
@@ -388,8 +394,8 @@ func (rcp *RothConversionPlanner) generateRecommendation(
 ) string {
 
 	if netBenefit.GreaterThan(decimal.Zero) {
-		return fmt.Sprintf("✓ Execute Roth conversion strategy - Net benefit: $%s (%.1f%% ROI)",
-			netBenefit.StringFixed(0), roi.InexactFloat64())
+		return fmt.Sprintf("✓ Execute Roth conversion strategy - Net benefit: $%s (%s ROI)",
+			netBenefit.StringFixed(0), domain.FormatPercent(roi))
 	} else if netBenefit.LessThan(decimal.Zero) {
 		return fmt.Sprintf("⚠ Consider alternative strategies - Net cost: $%s",
 			netBenefit.Abs().StringFixed(0))
@@ -412,7 +418,15 @@ func (rcp *RothConversionPlanner) calculateLifetimeIRMAA(projection *domain.Scen
 	total := decimal.Zero
 	for _, year := range projection.Projection {
 		// IRMAA surcharge is monthly, convert to annual
-		total = total.Add(year.IRMAASurcharge.Mul(decimal.NewFromInt(12)))
+		total = total.Add(year.IRMAASurcharge.Add(year.IRMAASurchargePartD).Mul(decimal.NewFromInt(12)))
+	}
+	return total
+}
+
+func (rcp *RothConversionPlanner) calculateLifetimePTC(projection *domain.ScenarioSummary) decimal.Decimal {
+	total := decimal.Zero
+	for _, year := range projection.Projection {
+		total = total.Add(year.PremiumTaxCredit)
 	}
 	return total
 }
@@ -425,7 +439,7 @@ func (rcp *RothConversionPlanner) calculateFinalBalances(projection *domain.Scen
 	lastYear := projection.Projection[len(projection.Projection)-1]
 
 	// Extract participant-specific balances (simplified - assumes single participant)
-	totalTSP := lastYear.TSPBalances[participant]
+	totalTSP := lastYear.TSPBalances.Get(participant)
 
 	// For now, assume 50/50 split between Traditional and Roth
 	// TODO: Track Traditional vs Roth balances separately
@@ -0,0 +1,56 @@
+package calculation
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// ScenarioOptions adjusts a single scenario run without editing the configuration
+type ScenarioOptions struct {
+	// Assumptions replace the configuration's global assumptions when set
+	Assumptions *domain.GlobalAssumptions
+	// ProjectionYears overrides the scenario's horizon when positive
+	ProjectionYears int
+	// Seed, when nonzero, runs the scenario on one simulated market path drawn from it,
+	// the way monte-carlo draws its paths, instead of the assumed rates
+	Seed int64
+	// Debug enables detailed calculation logging for this run only
+	Debug bool
+}
+
+// RunScenario calculates one scenario of the configuration with the given options. Unlike
+// RunScenarios it neither runs the other scenarios nor builds a comparison against current
+// income, so it suits library callers and interactive use. Neither the engine nor the
+// configuration is modified, so runs may proceed concurrently.
+func (ce *CalculationEngine) RunScenario(ctx context.Context, config *domain.Configuration, scenario *domain.GenericScenario, opts ScenarioOptions) (*domain.ScenarioSummary, error) {
+	run := config
+	if opts.Assumptions != nil {
+		c := *config
+		c.GlobalAssumptions = *opts.Assumptions
+		run = &c
+	}
+	if opts.ProjectionYears > 0 {
+		s := *scenario
+		s.ProjectionYears = opts.ProjectionYears
+		scenario = &s
+	}
+	if opts.Seed != 0 {
+		fmce := &FERSMonteCarloEngine{
+			baseConfig:     run,
+			historicalData: ce.HistoricalData,
+			config:         defaultFERSMonteCarloConfig(run.GlobalAssumptions.MonteCarloSettings),
+			shocks:         newMarketShocks(run.GlobalAssumptions.MonteCarloSettings, ce.HistoricalData),
+		}
+		run = fmce.createModifiedConfig(fmce.generateMarketConditions(rand.New(rand.NewSource(opts.Seed))))
+	}
+
+	engine := ce
+	if opts.Debug && !ce.Debug {
+		debugEngine := *ce
+		debugEngine.Debug = true
+		engine = &debugEngine
+	}
+	return engine.RunGenericScenario(ctx, run, scenario)
+}
@@ -0,0 +1,45 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunScenarioOptions(t *testing.T) {
+	ctx := context.Background()
+	config := projectionCacheConfig()
+	scenario := &config.Scenarios[0]
+	ce := NewCalculationEngine()
+
+	base, err := ce.RunScenario(ctx, config, scenario, ScenarioOptions{})
+	require.NoError(t, err)
+	plain, err := ce.RunGenericScenario(ctx, config, scenario)
+	require.NoError(t, err)
+	assert.Equal(t, plain.FinalTSPBalance.String(), base.FinalTSPBalance.String())
+	assert.Len(t, base.Projection, 30)
+
+	short, err := ce.RunScenario(ctx, config, scenario, ScenarioOptions{ProjectionYears: 12})
+	require.NoError(t, err)
+	assert.Len(t, short.Projection, 12)
+	assert.Zero(t, scenario.ProjectionYears, "the scenario is not modified")
+
+	assumptions := config.GlobalAssumptions
+	assumptions.TSPReturnPostRetirement = decimal.NewFromFloat(0.02)
+	lean, err := ce.RunScenario(ctx, config, scenario, ScenarioOptions{Assumptions: &assumptions})
+	require.NoError(t, err)
+	assert.True(t, lean.FinalTSPBalance.LessThan(base.FinalTSPBalance))
+	assert.Equal(t, "0.05", config.GlobalAssumptions.TSPReturnPostRetirement.String(), "the configuration is not modified")
+
+	// A seed draws one simulated market path, the same one every time
+	seeded, err := ce.RunScenario(ctx, config, scenario, ScenarioOptions{Seed: 42, Debug: true})
+	require.NoError(t, err)
+	again, err := ce.RunScenario(ctx, config, scenario, ScenarioOptions{Seed: 42})
+	require.NoError(t, err)
+	assert.Equal(t, seeded.FinalTSPBalance.String(), again.FinalTSPBalance.String())
+	assert.NotEqual(t, base.FinalTSPBalance.String(), seeded.FinalTSPBalance.String())
+	assert.False(t, ce.Debug, "debug applies to the one run")
+}
@@ -253,7 +253,7 @@ func (sa *SensitivityAnalyzer) calculateSensitivityMetrics(projection []domain.A
 	// Calculate IRMAA total cost
 	irmaaTotalCost := decimal.Zero
 	for i := range projection {
-		irmaaTotalCost = irmaaTotalCost.Add(projection[i].IRMAASurcharge.Mul(decimal.NewFromInt(12)))
+		irmaaTotalCost = irmaaTotalCost.Add(projection[i].IRMAASurcharge.Add(projection[i].IRMAASurchargePartD).Mul(decimal.NewFromInt(12)))
 	}
 
 	return domain.SensitivityMetrics{
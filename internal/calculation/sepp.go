@@ -0,0 +1,83 @@
+package calculation
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// SEPPDefaultInterestRate is the 5% rate the IRS always allows for the amortization method
+var SEPPDefaultInterestRate = decimal.NewFromFloat(0.05)
+
+// seppRecaptureRate is the additional tax on early distributions, recaptured on every SEPP
+// payment taken before 59½ once the schedule is modified
+var seppRecaptureRate = decimal.NewFromFloat(0.10)
+
+// singleLifeTable is the IRS Single Life Table life expectancy from age 40 through 65
+var singleLifeTable = []float64{
+	45.7, 44.8, 43.8, 42.9, 41.9, 41.0, 40.0, 39.0, 38.1, 37.1, // 40-49
+	36.2, 35.3, 34.3, 33.4, 32.5, 31.6, 30.6, 29.8, 28.9, 28.0, // 50-59
+	27.1, 26.2, 25.4, 24.5, 23.7, 22.9, // 60-65
+}
+
+// SingleLifeExpectancy returns the IRS single life expectancy at age, clamped to ages 40-65
+func SingleLifeExpectancy(age int) float64 {
+	return singleLifeTable[min(max(age-40, 0), len(singleLifeTable)-1)]
+}
+
+// SEPPAmortizationPayment returns the fixed annual payment under the amortization method:
+// the balance amortized over the single life expectancy at age at the interest rate
+func SEPPAmortizationPayment(balance decimal.Decimal, age int, rate decimal.Decimal) decimal.Decimal {
+	n := SingleLifeExpectancy(age)
+	if !rate.IsPositive() {
+		return balance.Div(decimal.NewFromFloat(n))
+	}
+	discount := decimal.NewFromFloat(math.Pow(1+rate.InexactFloat64(), -n))
+	return balance.Mul(rate).Div(decimalOne.Sub(discount))
+}
+
+// startSEPP fixes the participant's SEPP payment on the current balance and locks the
+// schedule until five years after the first payment or age 59½, whichever is later
+func (st *participantState) startSEPP(p *domain.Participant, ps domain.ParticipantScenario, age int, firstPayment time.Time) {
+	rate := SEPPDefaultInterestRate
+	if ps.SEPPInterestRate != nil {
+		rate = *ps.SEPPInterestRate
+	}
+	st.seppAnnual = SEPPAmortizationPayment(st.tspBalance, age, rate)
+	st.seppLockEnd = firstPayment.AddDate(5, 0, 0)
+	if age59Half := p.BirthDate.AddDate(59, 6, 0); age59Half.After(st.seppLockEnd) {
+		st.seppLockEnd = age59Half
+	}
+}
+
+// checkSEPP compares the year's TSP distributions with the locked SEPP payment. Taking more
+// or less, or annuitizing part of the balance, modifies the schedule: the 10% additional
+// tax on the SEPP payments taken before 59½ is recaptured and the year flagged. Running
+// out of money is not a modification.
+func (st *participantState) checkSEPP(cf *domain.AnnualCashFlow, p *domain.Participant, yr, startYear int, yearDate time.Time, scheduled decimal.Decimal) {
+	if !st.seppAnnual.IsPositive() || st.seppBroken || !yearDate.Before(st.seppLockEnd) {
+		return
+	}
+	taken := cf.TSPWithdrawals.Get(p.Name)
+	if yearDate.Before(p.BirthDate.AddDate(59, 6, 0)) {
+		st.seppDistributions = st.seppDistributions.Add(decimal.Min(taken, scheduled))
+	}
+	exhausted := !st.tspBalance.IsPositive() && taken.LessThan(scheduled)
+	annuitized := st.tspAnnuityPurchased && st.tspAnnuityYear == yr
+	if !annuitized && (exhausted || taken.Sub(scheduled).Abs().LessThan(decimalOne)) {
+		return
+	}
+	st.seppBroken = true
+	recapture := st.seppDistributions.Mul(seppRecaptureRate)
+	cf.Warnings = append(cf.Warnings, domain.EngineWarning{
+		Year:        startYear + yr,
+		Participant: p.Name,
+		Code:        domain.WarningSEPPViolation,
+		Amount:      recapture,
+		Message: fmt.Sprintf("%s: TSP distributions of $%s modify the $%s SEPP schedule locked until %s; $%s of 10%% additional tax is recaptured",
+			p.Label(), taken.StringFixed(0), scheduled.StringFixed(0), st.seppLockEnd.Format("2006-01-02"), recapture.StringFixed(0)),
+	})
+}
@@ -0,0 +1,68 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEPPAmortizationPayment(t *testing.T) {
+	// $500,000 at 50 over 36.2 years at 5%
+	payment := SEPPAmortizationPayment(decimal.NewFromInt(500000), 50, SEPPDefaultInterestRate)
+	assert.InDelta(t, 30156, payment.InexactFloat64(), 1)
+
+	// A lower rate or an older start lowers and raises the payment
+	assert.True(t, SEPPAmortizationPayment(decimal.NewFromInt(500000), 50, decimal.NewFromFloat(0.03)).LessThan(payment))
+	assert.True(t, SEPPAmortizationPayment(decimal.NewFromInt(500000), 55, SEPPDefaultInterestRate).GreaterThan(payment))
+	assert.Equal(t, 45.7, SingleLifeExpectancy(30))
+}
+
+func TestProjectionSEPPWithdrawals(t *testing.T) {
+	balance := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{
+			{Name: "Pat", BirthDate: time.Date(1975, 3, 1, 0, 0, 0, 0, time.UTC), TSPBalanceTraditional: balance(500000), TSPBalanceRoth: balance(0)},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         12,
+		InflationRate:           decimal.NewFromFloat(0.025),
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	retire := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	ps := domain.ParticipantScenario{ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67, TSPWithdrawalStrategy: "sepp"}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{"Pat": ps}}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 12)
+
+	// The payment is fixed on the first year's balance and repeats every year
+	payment := projection[0].TSPWithdrawals.Get("Pat")
+	assert.InDelta(t, 30156, payment.InexactFloat64(), 1)
+	for _, cf := range projection {
+		assert.True(t, cf.TSPWithdrawals.Get("Pat").Equal(payment))
+		assert.Empty(t, cf.Warnings)
+	}
+
+	// A Roth conversion during the lock modifies the schedule: the additional tax on the
+	// four payments taken so far is recaptured, and the violation is reported once
+	ps.RothConversions = &domain.RothConversionSchedule{Conversions: []domain.RothConversion{{Year: 2028, Amount: decimal.NewFromInt(20000)}}}
+	scenario.ParticipantScenarios["Pat"] = ps
+	projection = ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection[3].Warnings, 1)
+	warning := projection[3].Warnings[0]
+	assert.Equal(t, domain.WarningSEPPViolation, warning.Code)
+	assert.Equal(t, payment.Mul(decimal.NewFromInt(4)).Mul(decimal.NewFromFloat(0.1)).StringFixed(2), warning.Amount.StringFixed(2))
+	// Once the schedule is broken, the converted Roth money paid out pro rata before 59½
+	// draws the additional tax of its own
+	require.Len(t, projection[4].Warnings, 1)
+	assert.Equal(t, domain.WarningEarlyRothDistribution, projection[4].Warnings[0].Code)
+}
@@ -0,0 +1,255 @@
+package calculation
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Orderings of a market path's years in a sequence-of-returns analysis
+const (
+	SequenceActual     = "actual"
+	SequenceWorstFirst = "worst_first"
+	SequenceBestFirst  = "best_first"
+	// SequenceAverage replaces every year with the path's average, leaving no sequence
+	SequenceAverage = "average"
+)
+
+// sequenceOrderings lists the orderings each path is run in
+var sequenceOrderings = []string{SequenceActual, SequenceWorstFirst, SequenceBestFirst, SequenceAverage}
+
+// SequenceRiskResult reports how much a scenario's outcome depends on the order its
+// market returns arrive in. Each simulated path is run as drawn, with its years sorted
+// worst-first and best-first, and with every year at the path's average. The reorderings
+// keep the path's average return, so their differences are sequencing alone.
+type SequenceRiskResult struct {
+	ScenarioName string `json:"scenarioName"`
+	NumPaths     int    `json:"numPaths"`
+	Seed         int64  `json:"seed"`
+
+	Orderings []SequenceOrderingOutcome `json:"orderings"`
+
+	// BestMinusWorst holds the percentiles across paths of the final TSP balance of the
+	// best-first ordering less that of the worst-first ordering
+	BestMinusWorst map[string]decimal.Decimal `json:"bestMinusWorst"`
+
+	// The variance of the final TSP balance across paths, split between the paths'
+	// average returns (the average ordering) and the sequence each path took (actual less
+	// average). The shares sum to one.
+	AverageReturnShare decimal.Decimal `json:"averageReturnShare"`
+	SequenceShare      decimal.Decimal `json:"sequenceShare"`
+}
+
+// SequenceOrderingOutcome summarizes the paths run in one ordering
+type SequenceOrderingOutcome struct {
+	Ordering             string                     `json:"ordering"`
+	FinalTSPBalance      map[string]decimal.Decimal `json:"finalTSPBalance"` // 10th, 25th, 50th, 75th, 90th percentiles
+	MedianLifetimeIncome decimal.Decimal            `json:"medianLifetimeIncome"`
+	// DepletionRate is the share of paths whose TSP runs out before the projection ends
+	DepletionRate decimal.Decimal `json:"depletionRate"`
+}
+
+// RunSequenceRisk draws one year-by-year market path per simulation and runs the
+// scenario on each path in every ordering
+func (fmce *FERSMonteCarloEngine) RunSequenceRisk(ctx context.Context, scenarioName string) (*SequenceRiskResult, error) {
+	scenario, err := fmce.findScenario(scenarioName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each path's orderings run side by side, so the simulations of path i are
+	// i*len(sequenceOrderings) onward in ordering order
+	allocation := fmce.sequenceAllocation()
+	conditions := make([]MarketCondition, 0, fmce.config.NumSimulations*len(sequenceOrderings))
+	for i := 0; i < fmce.config.NumSimulations; i++ {
+		path := fmce.yearByYearPath(rand.New(rand.NewSource(fmce.config.Seed + int64(i))))
+		for _, ordering := range sequenceOrderings {
+			conditions = append(conditions, pathCondition(orderPath(path, ordering, allocation)))
+		}
+	}
+	simulations, err := fmce.runScenarioOnPaths(ctx, scenario, conditions, fmce.progressReporter(0, len(conditions)))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SequenceRiskResult{
+		ScenarioName: scenarioName,
+		Seed:         fmce.config.Seed,
+	}
+	finals := make(map[string][]decimal.Decimal)
+	incomes := make(map[string][]decimal.Decimal)
+	depleted := make(map[string]int)
+	var spreads []decimal.Decimal
+	for start := 0; start < len(simulations); start += len(sequenceOrderings) {
+		runs := simulations[start : start+len(sequenceOrderings)]
+		if slices.ContainsFunc(runs, func(sim FERSMonteCarloSimulation) bool { return len(sim.ScenarioSummary.Projection) == 0 }) {
+			continue
+		}
+		result.NumPaths++
+		for i, ordering := range sequenceOrderings {
+			summary := runs[i].ScenarioSummary
+			final := summary.Projection[len(summary.Projection)-1].TotalTSPBalance()
+			finals[ordering] = append(finals[ordering], final)
+			incomes[ordering] = append(incomes[ordering], summary.TotalLifetimeIncome)
+			if summary.TSPLongevity < len(summary.Projection) {
+				depleted[ordering]++
+			}
+		}
+		best, worst := finals[SequenceBestFirst], finals[SequenceWorstFirst]
+		spreads = append(spreads, best[len(best)-1].Sub(worst[len(worst)-1]))
+	}
+
+	for _, ordering := range sequenceOrderings {
+		outcome := SequenceOrderingOutcome{
+			Ordering:             ordering,
+			FinalTSPBalance:      calculatePercentiles(slices.Clone(finals[ordering])),
+			MedianLifetimeIncome: calculatePercentiles(slices.Clone(incomes[ordering]))["50th"],
+			DepletionRate:        decimal.Zero,
+		}
+		if result.NumPaths > 0 {
+			outcome.DepletionRate = decimal.NewFromInt(int64(depleted[ordering])).Div(decimal.NewFromInt(int64(result.NumPaths)))
+		}
+		result.Orderings = append(result.Orderings, outcome)
+	}
+	result.BestMinusWorst = calculatePercentiles(spreads)
+
+	// Split the variance of the actual outcomes between average returns and sequence
+	sequenceEffects := make([]decimal.Decimal, result.NumPaths)
+	for i := range sequenceEffects {
+		sequenceEffects[i] = finals[SequenceActual][i].Sub(finals[SequenceAverage][i])
+	}
+	averageVariance, sequenceVariance := variance(finals[SequenceAverage]), variance(sequenceEffects)
+	if total := averageVariance.Add(sequenceVariance); total.IsPositive() {
+		result.AverageReturnShare = averageVariance.Div(total).Round(4)
+		result.SequenceShare = decimal.NewFromInt(1).Sub(result.AverageReturnShare)
+	}
+	return result, nil
+}
+
+// yearByYearPath draws a market path with its own conditions in every projection year:
+// a historical path in blocks of BlockLength years when sampling history, otherwise
+// independent statistical years
+func (fmce *FERSMonteCarloEngine) yearByYearPath(rng *rand.Rand) []domain.MarketYear {
+	years := make([]int, fmce.pathYears())
+	if fmce.config.UseHistorical && fmce.historicalData != nil {
+		if path, err := fmce.historicalData.SampleHistoricalPath(rng, len(years), fmce.config.BlockLength); err == nil {
+			years = path
+		}
+	}
+	return fmce.historicalPathConditions(years, rng).Path
+}
+
+// sequenceAllocation returns the TSP allocation years are ranked by: the configured
+// default allocation, otherwise the engine's
+func (fmce *FERSMonteCarloEngine) sequenceAllocation() domain.TSPAllocation {
+	if allocation := fmce.baseConfig.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation; allocation.Total().IsPositive() {
+		return allocation
+	}
+	return fmce.config.DefaultTSPAllocation
+}
+
+// orderPath returns a copy of path in the given ordering. Years are ranked by their
+// return on allocation and keep their own inflation, COLA and FEHB inflation.
+func orderPath(path []domain.MarketYear, ordering string, allocation domain.TSPAllocation) []domain.MarketYear {
+	ordered := slices.Clone(path)
+	byReturn := func(a, b domain.MarketYear) int {
+		return portfolioYearReturn(a, allocation).Cmp(portfolioYearReturn(b, allocation))
+	}
+	switch ordering {
+	case SequenceWorstFirst:
+		slices.SortStableFunc(ordered, byReturn)
+	case SequenceBestFirst:
+		slices.SortStableFunc(ordered, func(a, b domain.MarketYear) int { return byReturn(b, a) })
+	case SequenceAverage:
+		average := averageMarketYear(path)
+		for i := range ordered {
+			ordered[i] = average
+		}
+	}
+	return ordered
+}
+
+// portfolioYearReturn returns a market year's return on allocation
+func portfolioYearReturn(year domain.MarketYear, allocation domain.TSPAllocation) decimal.Decimal {
+	return weightedReturn(allocation, map[string]decimal.Decimal{
+		"C": year.FundReturns.CFund,
+		"S": year.FundReturns.SFund,
+		"I": year.FundReturns.IFund,
+		"F": year.FundReturns.FFund,
+		"G": year.FundReturns.GFund,
+	})
+}
+
+// averageMarketYear returns the arithmetic average of a path's years
+func averageMarketYear(path []domain.MarketYear) domain.MarketYear {
+	var sum domain.MarketYear
+	for _, year := range path {
+		sum.InflationRate = sum.InflationRate.Add(year.InflationRate)
+		sum.COLARate = sum.COLARate.Add(year.COLARate)
+		sum.FEHBInflation = sum.FEHBInflation.Add(year.FEHBInflation)
+		sum.FundReturns.CFund = sum.FundReturns.CFund.Add(year.FundReturns.CFund)
+		sum.FundReturns.SFund = sum.FundReturns.SFund.Add(year.FundReturns.SFund)
+		sum.FundReturns.IFund = sum.FundReturns.IFund.Add(year.FundReturns.IFund)
+		sum.FundReturns.FFund = sum.FundReturns.FFund.Add(year.FundReturns.FFund)
+		sum.FundReturns.GFund = sum.FundReturns.GFund.Add(year.FundReturns.GFund)
+	}
+	if len(path) == 0 {
+		return sum
+	}
+	n := decimal.NewFromInt(int64(len(path)))
+	return domain.MarketYear{
+		InflationRate: sum.InflationRate.Div(n),
+		COLARate:      sum.COLARate.Div(n),
+		FEHBInflation: sum.FEHBInflation.Div(n),
+		FundReturns: domain.TSPFundReturns{
+			CFund: sum.FundReturns.CFund.Div(n),
+			SFund: sum.FundReturns.SFund.Div(n),
+			IFund: sum.FundReturns.IFund.Div(n),
+			FFund: sum.FundReturns.FFund.Div(n),
+			GFund: sum.FundReturns.GFund.Div(n),
+		},
+	}
+}
+
+// pathCondition returns the market conditions of a simulation that follows path, with
+// the path's first year as its single-year conditions
+func pathCondition(path []domain.MarketYear) MarketCondition {
+	condition := MarketCondition{Path: path}
+	if len(path) > 0 {
+		first := path[0]
+		condition.InflationRate = first.InflationRate
+		condition.COLARate = first.COLARate
+		condition.FEHBInflation = first.FEHBInflation
+		condition.HistoricalYear = first.HistoricalYear
+		condition.TSPReturns = map[string]decimal.Decimal{
+			"C": first.FundReturns.CFund,
+			"S": first.FundReturns.SFund,
+			"I": first.FundReturns.IFund,
+			"F": first.FundReturns.FFund,
+			"G": first.FundReturns.GFund,
+		}
+	}
+	return condition
+}
+
+// variance returns the population variance of values
+func variance(values []decimal.Decimal) decimal.Decimal {
+	if len(values) == 0 {
+		return decimal.Zero
+	}
+	n := decimal.NewFromInt(int64(len(values)))
+	mean := decimal.Zero
+	for _, v := range values {
+		mean = mean.Add(v)
+	}
+	mean = mean.Div(n)
+	sum := decimal.Zero
+	for _, v := range values {
+		d := v.Sub(mean)
+		sum = sum.Add(d.Mul(d))
+	}
+	return sum.Div(n)
+}
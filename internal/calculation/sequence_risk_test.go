@@ -0,0 +1,58 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderPath(t *testing.T) {
+	year := func(c, g, inflation float64) domain.MarketYear {
+		return domain.MarketYear{
+			InflationRate: decimal.NewFromFloat(inflation),
+			FundReturns:   domain.TSPFundReturns{CFund: decimal.NewFromFloat(c), GFund: decimal.NewFromFloat(g)},
+		}
+	}
+	path := []domain.MarketYear{year(0.10, 0.02, 0.03), year(-0.20, 0.05, 0.01), year(0.30, 0.01, 0.04)}
+	// Half in C and half in G: returns of 6%, -7.5% and 15.5%
+	allocation := domain.TSPAllocation{CFund: decimal.NewFromFloat(0.5), GFund: decimal.NewFromFloat(0.5)}
+
+	worst := orderPath(path, SequenceWorstFirst, allocation)
+	assert.Equal(t, []domain.MarketYear{path[1], path[0], path[2]}, worst, "years keep their own inflation")
+	best := orderPath(path, SequenceBestFirst, allocation)
+	assert.Equal(t, []domain.MarketYear{path[2], path[0], path[1]}, best)
+	assert.Equal(t, path, orderPath(path, SequenceActual, allocation))
+	assert.Equal(t, year(0.10, 0.02, 0.03), path[0], "the drawn path is left as it was")
+
+	average := orderPath(path, SequenceAverage, allocation)
+	require.Len(t, average, 3)
+	assert.True(t, average[0].FundReturns.CFund.Equal(decimal.NewFromFloat(0.0666666666666667)), average[0].FundReturns.CFund.String())
+	assert.True(t, average[2].InflationRate.Equal(decimal.NewFromFloat(0.0266666666666667)), average[2].InflationRate.String())
+}
+
+func TestFERSMonteCarloEngine_RunSequenceRisk(t *testing.T) {
+	engine := NewFERSMonteCarloEngine(createTestConfig(), nil)
+	engine.SetSimulations(6)
+	engine.SetSeed(13)
+
+	result, err := engine.RunSequenceRisk(context.Background(), "Test Scenario")
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.NumPaths)
+	require.Len(t, result.Orderings, 4)
+	for i, ordering := range []string{SequenceActual, SequenceWorstFirst, SequenceBestFirst, SequenceAverage} {
+		assert.Equal(t, ordering, result.Orderings[i].Ordering)
+	}
+
+	// Reordering the same years changes the outcome
+	worst, best := result.Orderings[1].FinalTSPBalance["50th"], result.Orderings[2].FinalTSPBalance["50th"]
+	assert.False(t, worst.Equal(best))
+	assert.True(t, result.AverageReturnShare.Add(result.SequenceShare).Equal(decimal.NewFromInt(1)))
+	assert.True(t, result.SequenceShare.IsPositive())
+
+	_, err = engine.RunSequenceRisk(context.Background(), "Missing")
+	assert.Error(t, err)
+}
@@ -60,11 +60,11 @@ func TestMortalityFilingAffectsSSTaxation(t *testing.T) {
 	}
 	// Compare taxable SS (approx by difference in federal tax relative to non-SS incomes not rigorous, just ensure SS benefits not zero)
 	preSS := decimal.Zero
-	for _, s := range pre.SSBenefits {
+	for _, s := range pre.SSBenefits.Values() {
 		preSS = preSS.Add(s)
 	}
 	postSS := decimal.Zero
-	for _, s := range post.SSBenefits {
+	for _, s := range post.SSBenefits.Values() {
 		postSS = postSS.Add(s)
 	}
 	if preSS.IsZero() || postSS.IsZero() {
@@ -69,9 +69,15 @@ func CalculateMonthlySSBenefitAtAge(baseFRA decimal.Decimal, birthDate time.Time
 	return ssc.CalculateBenefitAtAge(claimingAge)
 }
 
-// ApplySSCOLA applies the annual Social Security COLA
+// SSCOLARate returns the Social Security COLA for a CPI change. Benefits are never
+// reduced, so a negative CPI change yields no COLA.
+func SSCOLARate(cpiChange decimal.Decimal) decimal.Decimal {
+	return decimal.Max(cpiChange, decimal.Zero)
+}
+
+// ApplySSCOLA applies the annual Social Security COLA, floored at 0%
 func ApplySSCOLA(currentBenefit decimal.Decimal, colaRate decimal.Decimal) decimal.Decimal {
-	return currentBenefit.Mul(decimal.NewFromFloat(1.0).Add(colaRate))
+	return currentBenefit.Mul(decimal.NewFromFloat(1.0).Add(SSCOLARate(colaRate)))
 }
 
 // ProjectSocialSecurityBenefits projects Social Security benefits over multiple years
@@ -251,3 +257,119 @@ func CalculateSSBenefitForYear(employee *domain.Employee, ssStartAge int, year i
 
 	return currentBenefit.Mul(decimal.NewFromInt(12)) // Convert to annual
 }
+
+// CalculateSpousalMonthlyBenefit returns the monthly spousal benefit payable on top of
+// a claimant's own benefit: the excess of half the spouse's PIA over the claimant's own
+// PIA. Claiming before FRA reduces the excess by 25/36 of 1% for each of the first 36
+// months early and 5/12 of 1% for each additional month; there is no delayed credit.
+func CalculateSpousalMonthlyBenefit(ownPIA, spousePIA decimal.Decimal, claimingAge, fra int) decimal.Decimal {
+	excess := spousePIA.Div(decimal.NewFromInt(2)).Sub(ownPIA)
+	if excess.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	if claimingAge >= fra {
+		return excess
+	}
+
+	monthsEarly := (fra - claimingAge) * 12
+	firstMonths := monthsEarly
+	if firstMonths > 36 {
+		firstMonths = 36
+	}
+	reduction := decimal.NewFromInt(int64(firstMonths)).Mul(decimal.NewFromInt(25)).Div(decimal.NewFromInt(3600))
+	if monthsEarly > 36 {
+		reduction = reduction.Add(decimal.NewFromInt(int64(monthsEarly - 36)).Mul(decimal.NewFromInt(5)).Div(decimal.NewFromInt(1200)))
+	}
+	return excess.Mul(decimal.NewFromInt(1).Sub(reduction))
+}
+
+// 2025 retirement earnings test exempt amounts used when none are configured
+var (
+	defaultEarningsTestExemptAmount        = decimal.NewFromInt(23400)
+	defaultEarningsTestFRAYearExemptAmount = decimal.NewFromInt(62160)
+)
+
+// EarningsTestWithholding returns the Social Security benefits withheld from an
+// annual benefit under the retirement earnings test. Before the year FRA is reached
+// $1 is withheld for every $2 of earnings above the annual exempt amount; in the FRA
+// year only earnings before the FRA birthday month count, at $1 for every $3 above
+// the higher exempt amount. indexFactor scales the exempt amounts for wage growth.
+// The benefit recalculation at FRA that credits withheld months is not modeled.
+func EarningsTestWithholding(annualBenefit, earnings decimal.Decimal, birthDate time.Time, year int, indexFactor decimal.Decimal, rules domain.SocialSecurityRules) decimal.Decimal {
+	if annualBenefit.LessThanOrEqual(decimal.Zero) || earnings.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	fra := dateutil.FullRetirementAge(birthDate)
+	fraYear := birthDate.Year() + fra
+	if year > fraYear {
+		return decimal.Zero
+	}
+
+	exempt, divisor := rules.EarningsTestExemptAmount, decimal.NewFromInt(2)
+	if exempt.LessThanOrEqual(decimal.Zero) {
+		exempt = defaultEarningsTestExemptAmount
+	}
+	if year == fraYear {
+		exempt, divisor = rules.EarningsTestFRAYearExemptAmount, decimal.NewFromInt(3)
+		if exempt.LessThanOrEqual(decimal.Zero) {
+			exempt = defaultEarningsTestFRAYearExemptAmount
+		}
+		monthsBeforeFRA := int(birthDate.Month()) - 1
+		earnings = earnings.Mul(decimal.NewFromInt(int64(monthsBeforeFRA))).Div(decimal.NewFromInt(12))
+	}
+	if indexFactor.GreaterThan(decimal.Zero) {
+		exempt = exempt.Mul(indexFactor)
+	}
+
+	excess := earnings.Sub(exempt)
+	if excess.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	return decimal.Min(excess.Div(divisor), annualBenefit)
+}
+
+// defaultWEPFirstBendPoint is the 2025 first PIA bend point used when none is configured
+var defaultWEPFirstBendPoint = decimal.NewFromInt(1226)
+
+// WEPMonthlyReduction returns the Windfall Elimination Provision reduction to a
+// participant's monthly benefit at full retirement age. The 90% factor on the first
+// bend point drops to 40% with 20 or fewer years of substantial covered earnings,
+// rising 5 points per additional year. The reduction never exceeds half of the
+// non-covered pension. It is zero unless rules.ApplyWEPGPO is set.
+func WEPMonthlyReduction(p *domain.Participant, rules domain.SocialSecurityRules) decimal.Decimal {
+	if !rules.ApplyWEPGPO || p == nil || p.NonCoveredPension == nil {
+		return decimal.Zero
+	}
+	years := p.NonCoveredPension.YearsOfSubstantialEarnings
+	if years >= 30 {
+		return decimal.Zero
+	}
+	factorPoints := 40
+	if years > 20 {
+		factorPoints += (years - 20) * 5
+	}
+
+	bendPoint := rules.WEPFirstBendPoint
+	if bendPoint.LessThanOrEqual(decimal.Zero) {
+		bendPoint = defaultWEPFirstBendPoint
+	}
+	reduction := bendPoint.Mul(decimal.NewFromInt(int64(90 - factorPoints))).Div(decimal.NewFromInt(100))
+
+	halfPension := p.NonCoveredPension.MonthlyBenefit.Div(decimal.NewFromInt(2))
+	return decimal.Min(reduction, halfPension)
+}
+
+// ApplyGPO reduces a monthly spousal or survivor benefit by two-thirds of the
+// recipient's non-covered pension (Government Pension Offset). The benefit is
+// returned unchanged unless rules.ApplyWEPGPO is set.
+func ApplyGPO(benefit decimal.Decimal, recipient *domain.Participant, rules domain.SocialSecurityRules) decimal.Decimal {
+	if !rules.ApplyWEPGPO || recipient == nil || recipient.NonCoveredPension == nil {
+		return benefit
+	}
+	offset := recipient.NonCoveredPension.MonthlyBenefit.Mul(decimal.NewFromInt(2)).Div(decimal.NewFromInt(3))
+	reduced := benefit.Sub(offset)
+	if reduced.LessThan(decimal.Zero) {
+		return decimal.Zero
+	}
+	return reduced
+}
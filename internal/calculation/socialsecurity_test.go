@@ -7,6 +7,7 @@ import (
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSocialSecurityOfficialExamples tests Social Security calculations using official SSA examples
@@ -239,6 +240,13 @@ func TestSocialSecurityCOLA(t *testing.T) {
 			expectedBenefit: decimal.NewFromInt(1800),
 			description:     "Years with no COLA adjustment",
 		},
+		{
+			name:            "Negative CPI: floored at 0%",
+			initialBenefit:  decimal.NewFromInt(1800),
+			colaRate:        decimal.NewFromFloat(-0.004), // 2009 CPI change; 2010 COLA was 0%
+			expectedBenefit: decimal.NewFromInt(1800),
+			description:     "Benefits are never reduced by a COLA",
+		},
 	}
 
 	for _, tt := range tests {
@@ -410,3 +418,241 @@ func TestInterpolateBenefits(t *testing.T) {
 		})
 	}
 }
+
+func TestWEPAndGPO(t *testing.T) {
+	p := &domain.Participant{
+		Name:         "Alice",
+		SSBenefit62:  decimal.NewFromInt(1400),
+		SSBenefitFRA: decimal.NewFromInt(2000),
+		SSBenefit70:  decimal.NewFromInt(2480),
+		NonCoveredPension: &domain.NonCoveredPension{
+			MonthlyBenefit:             decimal.NewFromInt(3000),
+			YearsOfSubstantialEarnings: 22,
+		},
+	}
+	preRepeal := domain.SocialSecurityRules{ApplyWEPGPO: true}
+
+	// Repealed regime (default): no reductions
+	assert.True(t, WEPMonthlyReduction(p, domain.SocialSecurityRules{}).IsZero())
+	assert.True(t, computeSSMonthlyBenefit(p, 67, domain.SocialSecurityRules{}).Equal(decimal.NewFromInt(2000)))
+	assert.True(t, ApplyGPO(decimal.NewFromInt(1000), p, domain.SocialSecurityRules{}).Equal(decimal.NewFromInt(1000)))
+
+	// 22 years of substantial earnings -> 50% factor, 40 points below 90% of the first bend point
+	assert.True(t, WEPMonthlyReduction(p, preRepeal).Equal(decimal.NewFromFloat(490.4)))
+	assert.True(t, computeSSMonthlyBenefit(p, 67, preRepeal).Equal(decimal.NewFromFloat(1509.6)))
+	// Claiming early scales the reduced benefit by the same proportion
+	assert.True(t, computeSSMonthlyBenefit(p, 62, preRepeal).Equal(decimal.NewFromFloat(1056.72)))
+
+	// Reduction is capped at half the non-covered pension
+	small := *p
+	small.NonCoveredPension = &domain.NonCoveredPension{MonthlyBenefit: decimal.NewFromInt(600), YearsOfSubstantialEarnings: 10}
+	assert.True(t, WEPMonthlyReduction(&small, preRepeal).Equal(decimal.NewFromInt(300)))
+
+	// 30 years of substantial earnings exempts the participant
+	exempt := *p
+	exempt.NonCoveredPension = &domain.NonCoveredPension{MonthlyBenefit: decimal.NewFromInt(3000), YearsOfSubstantialEarnings: 30}
+	assert.True(t, WEPMonthlyReduction(&exempt, preRepeal).IsZero())
+
+	// GPO offsets two-thirds of the non-covered pension, floored at zero
+	assert.True(t, ApplyGPO(decimal.NewFromInt(2500), p, preRepeal).Equal(decimal.NewFromInt(500)))
+	assert.True(t, ApplyGPO(decimal.NewFromInt(1000), p, preRepeal).IsZero())
+}
+
+func TestCalculateSpousalMonthlyBenefit(t *testing.T) {
+	own := decimal.NewFromInt(600)
+	spouse := decimal.NewFromInt(3000)
+
+	assert.True(t, CalculateSpousalMonthlyBenefit(own, spouse, 67, 67).Equal(decimal.NewFromInt(900)))
+	assert.True(t, CalculateSpousalMonthlyBenefit(own, spouse, 70, 67).Equal(decimal.NewFromInt(900)), "no delayed credits")
+	// 36 months early: 25% reduction
+	assert.True(t, CalculateSpousalMonthlyBenefit(own, spouse, 64, 67).Equal(decimal.NewFromInt(675)))
+	// 60 months early: 25% + 10% reduction
+	assert.True(t, CalculateSpousalMonthlyBenefit(own, spouse, 62, 67).Equal(decimal.NewFromInt(585)))
+	// Own PIA above half the spouse's PIA: nothing extra
+	assert.True(t, CalculateSpousalMonthlyBenefit(decimal.NewFromInt(1600), spouse, 67, 67).IsZero())
+}
+
+func TestProjectionSpousalBenefitStartsWhenBothHaveFiled(t *testing.T) {
+	retired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{
+				Name:                "Alice",
+				BirthDate:           time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+				SSBenefit62:         decimal.NewFromInt(420),
+				SSBenefitFRA:        decimal.NewFromInt(600),
+				SSBenefit70:         decimal.NewFromInt(744),
+				ClaimSpousalBenefit: true,
+			},
+			{
+				Name:         "Bob",
+				BirthDate:    time.Date(1959, 1, 1, 0, 0, 0, 0, time.UTC),
+				SSBenefit62:  decimal.NewFromInt(2100),
+				SSBenefitFRA: decimal.NewFromInt(3000),
+				SSBenefit70:  decimal.NewFromInt(3720),
+			},
+		},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "spousal",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", RetirementDate: &retired, SSStartAge: 67},
+			"Bob":   {ParticipantName: "Bob", RetirementDate: &retired, SSStartAge: 70},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 6}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 6)
+
+	// Alice files in 2027 but Bob not until 2029: no spousal benefit before then
+	for _, cf := range projection[:4] {
+		assert.True(t, cf.SpousalSSBenefits.Get("Alice").IsZero(), "year %d", cf.Date.Year())
+	}
+	assert.True(t, projection[2].SSBenefits.Get("Alice").GreaterThan(decimal.Zero))
+
+	// Alice claims the spousal excess at her FRA: $900/month, paid alongside Bob from 2029
+	spousalFirstYear := projection[4].SpousalSSBenefits.Get("Alice")
+	assert.True(t, spousalFirstYear.GreaterThan(decimal.Zero))
+	assert.True(t, spousalFirstYear.LessThanOrEqual(decimal.NewFromInt(10800)))
+	assert.True(t, projection[5].SpousalSSBenefits.Get("Alice").Equal(decimal.NewFromInt(10800)))
+	assert.True(t, projection[5].SSBenefits.Get("Alice").Equal(decimal.NewFromInt(7200+10800)))
+	assert.True(t, projection[5].SpousalSSBenefits.Get("Bob").IsZero(), "Bob has not elected spousal benefits")
+}
+
+func TestProjectionSurvivorStepsUpToDeceasedBenefit(t *testing.T) {
+	retired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	death := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{
+				Name:         "Alice",
+				BirthDate:    time.Date(1958, 1, 1, 0, 0, 0, 0, time.UTC),
+				SSBenefit62:  decimal.NewFromInt(420),
+				SSBenefitFRA: decimal.NewFromInt(600),
+				SSBenefit70:  decimal.NewFromInt(744),
+			},
+			{
+				Name:         "Bob",
+				BirthDate:    time.Date(1955, 1, 1, 0, 0, 0, 0, time.UTC),
+				SSBenefit62:  decimal.NewFromInt(2100),
+				SSBenefitFRA: decimal.NewFromInt(3000),
+				SSBenefit70:  decimal.NewFromInt(3720),
+			},
+		},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "survivor",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", RetirementDate: &retired, SSStartAge: 67},
+			"Bob":   {ParticipantName: "Bob", RetirementDate: &retired, SSStartAge: 70},
+		},
+		Mortality: &domain.GenericScenarioMortality{
+			Participants: map[string]*domain.MortalitySpec{"Bob": {DeathDate: &death}},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 4}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 4)
+
+	before, after := projection[1], projection[2]
+	assert.True(t, before.SSBenefits.Get("Alice").Equal(decimal.NewFromInt(7200)))
+	assert.True(t, before.SurvivorSSBenefits.Get("Alice").IsZero())
+
+	// Alice is past FRA, so she steps up to Bob's full benefit claimed at 70
+	assert.True(t, after.SSBenefits.Get("Bob").IsZero())
+	assert.True(t, after.SSBenefits.Get("Alice").Equal(decimal.NewFromInt(44640)))
+	assert.True(t, after.SurvivorSSBenefits.Get("Alice").Equal(decimal.NewFromInt(44640-7200)))
+	assert.True(t, projection[3].SSBenefits.Get("Alice").Equal(decimal.NewFromInt(44640)))
+}
+
+func TestProjectionNegativeCOLAFloorsSocialSecurityOnly(t *testing.T) {
+	retired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	salary := decimal.NewFromInt(100000)
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{
+				Name:         "Alice",
+				BirthDate:    time.Date(1955, 1, 1, 0, 0, 0, 0, time.UTC),
+				SSBenefit62:  decimal.NewFromInt(1400),
+				SSBenefitFRA: decimal.NewFromInt(2000),
+				SSBenefit70:  decimal.NewFromInt(2480),
+			},
+			{
+				Name:          "Bob",
+				BirthDate:     time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+				CurrentSalary: &salary,
+				SSBenefit62:   decimal.NewFromInt(1400),
+				SSBenefitFRA:  decimal.NewFromInt(2000),
+				SSBenefit70:   decimal.NewFromInt(2480),
+			},
+		},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "deflation",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", RetirementDate: &retired, SSStartAge: 70},
+			"Bob":   {ParticipantName: "Bob", SSStartAge: 67},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3, COLAGeneralRate: decimal.NewFromFloat(-0.02)}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 3)
+
+	assert.True(t, projection[2].SSBenefits.Get("Alice").Equal(projection[1].SSBenefits.Get("Alice")), "SS COLA floors at 0%")
+	assert.True(t, projection[2].Salaries.Get("Bob").LessThan(projection[1].Salaries.Get("Bob")), "salaries still follow a negative rate")
+}
+
+func TestEarningsTestWithholding(t *testing.T) {
+	birth := time.Date(1963, 7, 1, 0, 0, 0, 0, time.UTC) // FRA 67, reached July 2030
+	benefit := decimal.NewFromInt(20000)
+	rules := domain.SocialSecurityRules{}
+	one := decimal.NewFromInt(1)
+
+	// Before the FRA year: $1 per $2 over $23,400
+	assert.True(t, EarningsTestWithholding(benefit, decimal.NewFromInt(43400), birth, 2026, one, rules).Equal(decimal.NewFromInt(10000)))
+	assert.True(t, EarningsTestWithholding(benefit, decimal.NewFromInt(20000), birth, 2026, one, rules).IsZero())
+	// Withholding never exceeds the benefit
+	assert.True(t, EarningsTestWithholding(benefit, decimal.NewFromInt(200000), birth, 2026, one, rules).Equal(benefit))
+	// FRA year: only January-June earnings count, $1 per $3 over $62,160
+	assert.True(t, EarningsTestWithholding(benefit, decimal.NewFromInt(180000), birth, 2030, one, rules).Equal(decimal.NewFromInt(9280)))
+	// After the FRA year there is no test
+	assert.True(t, EarningsTestWithholding(benefit, decimal.NewFromInt(180000), birth, 2031, one, rules).IsZero())
+	// Exempt amounts scale with the index factor
+	assert.True(t, EarningsTestWithholding(benefit, decimal.NewFromInt(43400), birth, 2026, decimal.NewFromFloat(1.5), rules).Equal(decimal.NewFromInt(4150)))
+}
+
+func TestProjectionEarningsTestReducesEarlyBenefits(t *testing.T) {
+	salary := decimal.NewFromInt(40000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{
+			Name:          "Alice",
+			BirthDate:     time.Date(1962, 1, 1, 0, 0, 0, 0, time.UTC),
+			CurrentSalary: &salary,
+			SSBenefit62:   decimal.NewFromInt(1400),
+			SSBenefitFRA:  decimal.NewFromInt(2000),
+			SSBenefit70:   decimal.NewFromInt(2480),
+		}},
+	}
+	scenario := &domain.GenericScenario{
+		Name: "working claimer",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {ParticipantName: "Alice", SSStartAge: 63},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 2}
+
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, domain.FederalRules{})
+	require.Len(t, projection, 2)
+
+	// $40,000 of wages is $16,600 over the exempt amount: $8,300 of the $18,240 benefit withheld
+	cf := projection[0]
+	assert.True(t, cf.SSEarningsTestWithheld.Get("Alice").Equal(decimal.NewFromInt(8300)))
+	assert.True(t, cf.SSBenefits.Get("Alice").Equal(decimal.NewFromInt(18240-8300)))
+}
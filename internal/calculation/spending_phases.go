@@ -0,0 +1,54 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// spendingPhaseParticipant returns the participant whose age selects the spending phase:
+// the named one, or the first participant. It is nil without participants.
+func spendingPhaseParticipant(household *domain.Household, phases *domain.SpendingPhases) *domain.Participant {
+	for i := range household.Participants {
+		if household.Participants[i].Name == phases.Participant {
+			return &household.Participants[i]
+		}
+	}
+	if len(household.Participants) == 0 {
+		return nil
+	}
+	return &household.Participants[0]
+}
+
+// spendingPhaseAt returns the phase in effect at age and the cumulative change in real
+// spending from the first phase's start age up to age. Before the first phase the
+// factor is 1 and the name empty.
+func spendingPhaseAt(phases []domain.SpendingPhase, age int) (name string, factor decimal.Decimal) {
+	factor = decimalOne
+	for i, phase := range phases {
+		if age < phase.StartAge {
+			break
+		}
+		name = phase.Name
+		end := age
+		if i+1 < len(phases) {
+			end = min(age, phases[i+1].StartAge)
+		}
+		if years := end - phase.StartAge; years > 0 {
+			factor = factor.Mul(onePlus(phase.AnnualChange).Pow(decimal.NewFromInt(int64(years))))
+		}
+	}
+	return name, factor
+}
+
+// spendingPhaseStartYear returns the index of the projection year the first spending
+// phase begins, or years when there is none
+func spendingPhaseStartYear(household *domain.Household, scenario *domain.GenericScenario, startYear, years int) int {
+	if scenario == nil || scenario.SpendingPhases == nil || len(scenario.SpendingPhases.Phases) == 0 {
+		return years
+	}
+	p := spendingPhaseParticipant(household, scenario.SpendingPhases)
+	if p == nil {
+		return years
+	}
+	return p.BirthDate.Year() + scenario.SpendingPhases.Phases[0].StartAge - startYear
+}
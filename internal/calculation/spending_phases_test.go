@@ -0,0 +1,72 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spendingSmile() []domain.SpendingPhase {
+	return []domain.SpendingPhase{
+		{Name: "go-go", StartAge: 65},
+		{Name: "slow-go", StartAge: 75, AnnualChange: decimal.NewFromFloat(-0.01)},
+		{Name: "no-go", StartAge: 85, AnnualChange: decimal.NewFromFloat(0.02)},
+	}
+}
+
+func TestSpendingPhaseAt(t *testing.T) {
+	phases := spendingSmile()
+	for _, tc := range []struct {
+		age    int
+		name   string
+		factor string
+	}{
+		{60, "", "1"},
+		{70, "go-go", "1"},
+		{76, "slow-go", "0.99"},
+		{85, "no-go", "0.9044"},
+		{87, "no-go", "0.9409"},
+	} {
+		name, factor := spendingPhaseAt(phases, tc.age)
+		assert.Equal(t, tc.name, name, "age %d", tc.age)
+		assert.Equal(t, tc.factor, factor.Round(4).String(), "age %d", tc.age)
+	}
+}
+
+func TestSpendingPhasesScaleWithdrawals(t *testing.T) {
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(800000)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	target := decimal.NewFromInt(3000)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+			ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70,
+			TSPWithdrawalStrategy: "need_based", TSPWithdrawalTargetMonthly: &target,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 4}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	flat := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+
+	// Gail turns 67 in 2025; spending falls 5% a year from 68
+	scenario.SpendingPhases = &domain.SpendingPhases{Phases: []domain.SpendingPhase{
+		{Name: "slow-go", StartAge: 68, AnnualChange: decimal.NewFromFloat(-0.05)},
+	}}
+	phased := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, phased, 4)
+
+	assert.Empty(t, phased[0].SpendingPhase)
+	assert.Equal(t, flat[0].TSPWithdrawals.Get("Gail").String(), phased[0].TSPWithdrawals.Get("Gail").String())
+	assert.Equal(t, "slow-go", phased[1].SpendingPhase)
+	assert.Equal(t, "36000", phased[1].TSPWithdrawals.Get("Gail").String())
+	assert.Equal(t, "0.95", phased[2].SpendingFactor.String())
+	assert.Equal(t, "34200", phased[2].TSPWithdrawals.Get("Gail").String())
+	assert.Equal(t, "32490", phased[3].TSPWithdrawals.Get("Gail").String())
+	assert.True(t, phased[3].TSPBalances.Get("Gail").GreaterThan(flat[3].TSPBalances.Get("Gail")))
+}
@@ -0,0 +1,106 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// spendingTargetMaxPasses bounds how many times the spending target solver re-projects
+const spendingTargetMaxPasses = 25
+
+var (
+	// spendingTargetTolerance is how close, in dollars, a year's net income must come to
+	// its spending target
+	spendingTargetTolerance = decimal.NewFromInt(1)
+	// spendingTargetMinSlope is the least net income a dollar of withdrawal must add for
+	// the solver to keep adjusting the year
+	spendingTargetMinSlope = decimal.NewFromFloat(0.05)
+)
+
+// usesSpendingTarget reports whether a participant of the scenario withdraws from the TSP
+// by the spending_target strategy
+func usesSpendingTarget(scenario *domain.GenericScenario) bool {
+	return spendingTargetParticipant(scenario) != nil
+}
+
+// spendingTargetParticipant returns the scenario of the participant using the
+// spending_target strategy, or nil
+func spendingTargetParticipant(scenario *domain.GenericScenario) *domain.ParticipantScenario {
+	if scenario == nil {
+		return nil
+	}
+	for _, ps := range scenario.ParticipantScenarios {
+		if ps.TSPWithdrawalStrategy == "spending_target" {
+			return &ps
+		}
+	}
+	return nil
+}
+
+// configuredSpendingTarget returns the spending_target strategy's annual target in the first
+// projection year's dollars, or nil when the scenario has none and the household's expenses set it
+func configuredSpendingTarget(scenario *domain.GenericScenario) *decimal.Decimal {
+	if ps := spendingTargetParticipant(scenario); ps != nil {
+		return ps.SpendingTarget
+	}
+	return nil
+}
+
+// solveSpendingTarget projects a scenario whose TSP withdrawals follow the spending_target
+// strategy. Each year's withdrawal is whatever brings that year's net income, after the
+// taxes and IRMAA surcharges the withdrawal itself triggers, to the year's spending target.
+// Those taxes are only known once the year is projected, so the solver re-projects with
+// trial withdrawals and moves each year by a secant step on its own withdrawal-to-net
+// income slope until every year is within a dollar. A year whose net income stops
+// responding to its withdrawal, because the balances are exhausted or a required minimum
+// distribution already covers the target, keeps its last withdrawal.
+func (ce *CalculationEngine) solveSpendingTarget(household *domain.Household, scenario *domain.GenericScenario, assumptions *domain.GlobalAssumptions, federalRules domain.FederalRules) []domain.AnnualCashFlow {
+	type trial struct {
+		withdrawal, netIncome decimal.Decimal
+	}
+
+	solver := *ce
+	solver.solvedWithdrawals = make(map[int]decimal.Decimal)
+	previous := make(map[int]trial)
+	settled := make(map[int]bool)
+
+	projection := solver.projectWithinMAGICeiling(household, scenario, assumptions, federalRules)
+	for pass := 0; pass < spendingTargetMaxPasses; pass++ {
+		changed := false
+		for i := range projection {
+			cf := &projection[i]
+			year := cf.Date.Year()
+			if settled[year] || !cf.SpendingTarget.IsPositive() {
+				continue
+			}
+			gap := cf.SpendingTarget.Sub(cf.NetIncome)
+			if gap.Abs().LessThan(spendingTargetTolerance) {
+				continue
+			}
+
+			withdrawal := solver.solvedWithdrawals[year]
+			next := withdrawal.Add(gap)
+			if prev, ok := previous[year]; ok {
+				slope := cf.NetIncome.Sub(prev.netIncome).Div(withdrawal.Sub(prev.withdrawal))
+				if slope.LessThan(spendingTargetMinSlope) {
+					settled[year] = true
+					continue
+				}
+				next = withdrawal.Add(gap.Div(slope))
+			}
+			next = decimal.Max(next, decimalZero)
+			if next.Equal(withdrawal) {
+				continue
+			}
+
+			previous[year] = trial{withdrawal: withdrawal, netIncome: cf.NetIncome}
+			solver.solvedWithdrawals[year] = next
+			changed = true
+		}
+		if !changed {
+			break
+		}
+		projection = solver.projectWithinMAGICeiling(household, scenario, assumptions, federalRules)
+	}
+	return projection
+}
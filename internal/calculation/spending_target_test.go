@@ -0,0 +1,52 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func projectSpendingTarget(t *testing.T, tspBalance int64) []domain.AnnualCashFlow {
+	t.Helper()
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(tspBalance)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	target := decimal.NewFromInt(60000)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+			ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70,
+			TSPWithdrawalStrategy: "spending_target", SpendingTarget: &target,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 8, InflationRate: decimal.NewFromFloat(0.025)}
+	projection := NewCalculationEngine().GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 8)
+	return projection
+}
+
+func TestSpendingTargetSolvesWithdrawalsForNetIncome(t *testing.T) {
+	projection := projectSpendingTarget(t, 800000)
+
+	// The target rises with inflation, and each year's withdrawal covers it after taxes
+	assert.Equal(t, "60000", projection[0].SpendingTarget.String())
+	assert.Equal(t, "61500", projection[1].SpendingTarget.String())
+	for _, cf := range projection {
+		assert.True(t, cf.SpendingSurplus.Abs().LessThan(decimal.NewFromInt(1)), "%d: surplus %s", cf.Date.Year(), cf.SpendingSurplus)
+		assert.True(t, cf.FederalTax.IsPositive())
+		assert.True(t, cf.TSPWithdrawals.Get("Gail").GreaterThan(cf.SpendingTarget.Add(cf.FederalTax).Sub(decimal.NewFromInt(1))), cf.Date.Year())
+	}
+}
+
+func TestSpendingTargetStopsWhenBalancesRunOut(t *testing.T) {
+	projection := projectSpendingTarget(t, 150000)
+
+	assert.True(t, projection[0].SpendingSurplus.Abs().LessThan(decimal.NewFromInt(1)))
+	last := projection[len(projection)-1]
+	assert.True(t, last.TSPBalances.Get("Gail").IsZero())
+	assert.True(t, last.SpendingSurplus.IsNegative(), "an exhausted TSP cannot meet the target")
+}
@@ -0,0 +1,95 @@
+package calculation
+
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// residentStates returns each participant's normalized state of residence when at
+// least one participant resides outside the household's state, or nil when all of them
+// share it and the household-level state tax applies
+func residentStates(household *domain.Household, householdState string) map[string]string {
+	home, _ := domain.NormalizeState(householdState)
+	states := make(map[string]string, len(household.Participants))
+	split := false
+	for _, p := range household.Participants {
+		state := home
+		if name, ok := domain.NormalizeState(p.StateResidency); ok {
+			state = name
+		}
+		states[p.Name] = state
+		split = split || state != home
+	}
+	if !split {
+		return nil
+	}
+	return states
+}
+
+// normalizedStateRules keys state rules by normalized state name, skipping unknown states
+func normalizedStateRules(rules map[string]domain.StateRules) map[string]domain.StateRules {
+	normalized := make(map[string]domain.StateRules, len(rules))
+	for state, r := range rules {
+		if name, ok := domain.NormalizeState(state); ok {
+			normalized[name] = r
+		}
+	}
+	return normalized
+}
+
+// participantTaxableIncome is a participant's share of the household's taxable income.
+// Salary, pension (with any survivor annuity and FERS supplement), TSP withdrawal and
+// annuity, and Social Security amounts follow the participant's own payments, as is
+// self-employment income; joint income (other ordinary income, passive income, interest,
+// capital gains and dividends) is split evenly between the living participants.
+func participantTaxableIncome(cf *domain.AnnualCashFlow, name string, taxable domain.TaxableIncome, living int) domain.TaxableIncome {
+	share := func(total, part, whole decimal.Decimal) decimal.Decimal {
+		if whole.IsZero() {
+			return decimal.Zero
+		}
+		return total.Mul(part).Div(whole)
+	}
+	joint := decimal.NewFromInt(int64(max(living, 1)))
+	distributions := cf.TSPWithdrawals.Get(name).Add(cf.TSPAnnuities.Get(name)).Add(cf.IncomeAnnuities.Get(name)).Add(cf.IRAWithdrawalsTraditional.Get(name))
+	totalDistributions := cf.GetTotalTSPWithdrawal().Add(cf.GetTotalTSPAnnuity()).Add(cf.GetTotalIncomeAnnuity()).Add(cf.GetTotalTraditionalIRAWithdrawal())
+	pensions := cf.Pensions.Get(name).Add(cf.SurvivorPensions.Get(name)).Add(cf.FERSSupplements.Get(name))
+	totalPensions := cf.GetTotalPension().Add(cf.GetTotalSurvivorPension()).Add(cf.GetTotalFERSSupplement())
+	return domain.TaxableIncome{
+		Salary:               share(taxable.Salary, cf.Salaries.Get(name), cf.GetTotalSalary()),
+		FERSPension:          share(taxable.FERSPension, pensions, totalPensions),
+		TSPWithdrawalsTrad:   share(taxable.TSPWithdrawalsTrad, distributions, totalDistributions),
+		TaxableSSBenefits:    share(taxable.TaxableSSBenefits, cf.SSBenefits.Get(name), cf.GetTotalSSBenefit()),
+		OtherTaxableIncome:   taxable.OtherTaxableIncome.Div(joint),
+		WageIncome:           cf.Salaries.Get(name),
+		InterestIncome:       taxable.InterestIncome.Div(joint),
+		SelfEmploymentIncome: cf.SelfEmploymentIncome.Get(name),
+		PassiveIncome:        taxable.PassiveIncome.Div(joint),
+		LongTermCapitalGains: taxable.LongTermCapitalGains.Div(joint),
+		QualifiedDividends:   taxable.QualifiedDividends.Div(joint),
+	}
+}
+
+// residentStateTax returns the income tax a resident of state owes on income.
+// Pennsylvania uses its own calculator and states without an income tax levy nothing;
+// other states apply their flat rule. ok is false when no rule is known for the state.
+func (ctc *ComprehensiveTaxCalculator) residentStateTax(state string, rules map[string]domain.StateRules, income domain.TaxableIncome, isRetired bool) (decimal.Decimal, bool) {
+	if state == "pennsylvania" {
+		return ctc.StateTaxCalc.CalculateTax(income, isRetired), true
+	}
+	if domain.StateHasNoIncomeTax(state) {
+		return decimal.Zero, true
+	}
+	rule, ok := rules[state]
+	if !ok {
+		return decimal.Zero, false
+	}
+	base := income.Salary.Add(income.TSPWithdrawalsTrad).Add(income.OtherTaxableIncome).
+		Add(income.InterestIncome).Add(income.LongTermCapitalGains).Add(income.QualifiedDividends)
+	if !rule.PensionExemption {
+		base = base.Add(income.FERSPension)
+	}
+	if !rule.SocialSecurityExemption {
+		base = base.Add(income.TaxableSSBenefits)
+	}
+	return base.Mul(rule.Rate), true
+}
@@ -0,0 +1,82 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResidentStates(t *testing.T) {
+	household := &domain.Household{Participants: []domain.Participant{{Name: "Alex"}, {Name: "Blair", StateResidency: "Pennsylvania"}}}
+	assert.Nil(t, residentStates(household, "PA"))
+
+	household.Participants[1].StateResidency = "va"
+	assert.Equal(t, map[string]string{"Alex": "pennsylvania", "Blair": "virginia"}, residentStates(household, "PA"))
+}
+
+func TestResidentStateTax(t *testing.T) {
+	ctc := NewComprehensiveTaxCalculator()
+	rules := map[string]domain.StateRules{
+		"virginia": {Rate: decimal.NewFromFloat(0.05), SocialSecurityExemption: true},
+	}
+	income := domain.TaxableIncome{
+		FERSPension:        decimal.NewFromInt(40000),
+		TSPWithdrawalsTrad: decimal.NewFromInt(20000),
+		TaxableSSBenefits:  decimal.NewFromInt(10000),
+	}
+
+	tax, ok := ctc.residentStateTax("virginia", rules, income, true)
+	require.True(t, ok)
+	assert.Equal(t, "3000", tax.String())
+
+	// Pennsylvania exempts retirement income and Florida has no income tax
+	tax, ok = ctc.residentStateTax("pennsylvania", rules, income, true)
+	assert.True(t, ok && tax.IsZero())
+	tax, ok = ctc.residentStateTax("florida", rules, income, true)
+	assert.True(t, ok && tax.IsZero())
+
+	_, ok = ctc.residentStateTax("ohio", rules, income, true)
+	assert.False(t, ok)
+}
+
+func TestProjectionTaxesParticipantsByStateOfResidence(t *testing.T) {
+	salary := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{Name: "Alex", BirthDate: time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: salary(100000)},
+			{Name: "Blair", BirthDate: time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: salary(50000)},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears: 1,
+		CurrentLocation: domain.Location{State: "PA"},
+	}
+	assumptions.FederalRules.StateLocalTaxConfig.StateRules = map[string]domain.StateRules{
+		"VA": {Rate: decimal.NewFromFloat(0.05)},
+	}
+	ce := NewCalculationEngine()
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	together := projection[0]
+	assert.Equal(t, "4605.00", together.StateTax.StringFixed(2))
+	assert.Equal(t, "1500.00", together.LocalTax.StringFixed(2))
+
+	// Blair lives in Virginia: Pennsylvania and the local EIT tax only Alex's wages
+	household.Participants[1].StateResidency = "VA"
+	projection = ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	apart := projection[0]
+	assert.Equal(t, "1000.00", apart.LocalTax.StringFixed(2))
+	assert.Equal(t, "5570.00", apart.StateTax.StringFixed(2))
+	assert.Empty(t, apart.Warnings)
+
+	// A state without rules is reported rather than silently taxed
+	household.Participants[1].StateResidency = "OH"
+	projection = ce.GenerateAnnualProjectionGeneric(household, &domain.GenericScenario{}, assumptions, assumptions.FederalRules)
+	require.Len(t, projection[0].Warnings, 1)
+	assert.Equal(t, domain.WarningUnknownStateTax, projection[0].Warnings[0].Code)
+}
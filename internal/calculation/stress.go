@@ -0,0 +1,355 @@
+package calculation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// StressScenario is a market crisis replayed at the start of a projection: a window of
+// history, or synthetic shocks. The projection returns to the baseline assumptions
+// once the crisis years run out.
+type StressScenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// HistoricalStart replays history from this year until the data runs out
+	HistoricalStart int `json:"historicalStart,omitempty"`
+	// Shocks replace the baseline in the first years, in order
+	Shocks []StressShock `json:"shocks,omitempty"`
+}
+
+// StressShock sets the market for one or more consecutive years. Unset rates keep the
+// baseline; COLAs follow the shocked inflation.
+type StressShock struct {
+	Years     int              `json:"years"`
+	Equity    *decimal.Decimal `json:"equity,omitempty"` // C, S and I fund returns
+	Bonds     *decimal.Decimal `json:"bonds,omitempty"`  // F fund return
+	Cash      *decimal.Decimal `json:"cash,omitempty"`   // G fund return
+	Inflation *decimal.Decimal `json:"inflation,omitempty"`
+}
+
+// DefaultStressScenarios lists the stress tests run when none are named
+var DefaultStressScenarios = []string{"1973", "2000", "2008", "stagflation", "crash"}
+
+// namedStressScenarios returns the built-in stress scenarios by name
+func namedStressScenarios() map[string]StressScenario {
+	rate := func(v float64) *decimal.Decimal {
+		d := decimal.NewFromFloat(v)
+		return &d
+	}
+	return map[string]StressScenario{
+		"1973":        {Name: "1973", Description: "1973-74 bear market and the stagflation that followed", HistoricalStart: 1973},
+		"2000":        {Name: "2000", Description: "2000-02 dot-com bust and the lost decade", HistoricalStart: 2000},
+		"2008":        {Name: "2008", Description: "2008 financial crisis and recovery", HistoricalStart: 2008},
+		"stagflation": {Name: "stagflation", Description: "Five years of 9% inflation with flat stocks and falling bonds", Shocks: []StressShock{{Years: 5, Equity: rate(0), Bonds: rate(-0.02), Cash: rate(0.05), Inflation: rate(0.09)}}},
+		"crash":       {Name: "crash", Description: "Immediate -40% equity return with 8% inflation", Shocks: []StressShock{{Years: 1, Equity: rate(-0.40), Inflation: rate(0.08)}}},
+	}
+}
+
+// ParseStressScenario resolves a stress test by name: a built-in scenario, a year to
+// replay history from, or a synthetic shock such as "equity=-40%+inflation=8%" with
+// optional bonds, cash and years (default 1)
+func ParseStressScenario(spec string) (StressScenario, error) {
+	spec = strings.TrimSpace(spec)
+	if s, ok := namedStressScenarios()[strings.ToLower(spec)]; ok {
+		return s, nil
+	}
+	if year, err := strconv.Atoi(spec); err == nil {
+		if year < 1900 || year > 2100 {
+			return StressScenario{}, fmt.Errorf("stress year %d is out of range", year)
+		}
+		return StressScenario{Name: spec, Description: fmt.Sprintf("History replayed from %d", year), HistoricalStart: year}, nil
+	}
+	if !strings.Contains(spec, "=") {
+		return StressScenario{}, fmt.Errorf("unknown stress scenario %q (built in: %s; or a year, or a shock like equity=-40%%+inflation=8%%)", spec, strings.Join(DefaultStressScenarios, ", "))
+	}
+
+	shock := StressShock{Years: 1}
+	for _, term := range strings.Split(spec, "+") {
+		key, value, ok := strings.Cut(strings.TrimSpace(term), "=")
+		if !ok {
+			return StressScenario{}, fmt.Errorf("stress shock term %q is not key=value", term)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if key == "years" {
+			years, err := strconv.Atoi(value)
+			if err != nil || years < 1 {
+				return StressScenario{}, fmt.Errorf("stress shock years must be a positive whole number, got %q", value)
+			}
+			shock.Years = years
+			continue
+		}
+		r, err := parseStressRate(value)
+		if err != nil {
+			return StressScenario{}, fmt.Errorf("stress shock %s: %w", key, err)
+		}
+		switch key {
+		case "equity":
+			shock.Equity = &r
+		case "bonds":
+			shock.Bonds = &r
+		case "cash":
+			shock.Cash = &r
+		case "inflation":
+			shock.Inflation = &r
+		default:
+			return StressScenario{}, fmt.Errorf("unknown stress shock %q (valid: equity, bonds, cash, inflation, years)", key)
+		}
+	}
+	return StressScenario{Name: spec, Description: "Custom shock", Shocks: []StressShock{shock}}, nil
+}
+
+// parseStressRate parses a rate written as a percentage ("-40%") or a fraction ("-0.4")
+func parseStressRate(value string) (decimal.Decimal, error) {
+	percent := strings.HasSuffix(value, "%")
+	r, err := decimal.NewFromString(strings.TrimSuffix(value, "%"))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid rate %q", value)
+	}
+	if percent {
+		r = r.Div(decimal.NewFromInt(100))
+	}
+	return r, nil
+}
+
+// StressTestResult compares a scenario under each stress test with its baseline
+type StressTestResult struct {
+	ScenarioName string          `json:"scenarioName"`
+	Baseline     StressOutcome   `json:"baseline"`
+	Stresses     []StressOutcome `json:"stresses"`
+}
+
+// StressOutcome summarizes one run of the scenario
+type StressOutcome struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// StressYears is how many projection years the crisis replaced
+	StressYears int `json:"stressYears"`
+	// HistoricalYears lists the years of history replayed, if any
+	HistoricalYears []int `json:"historicalYears,omitempty"`
+
+	TotalLifetimeIncome decimal.Decimal `json:"totalLifetimeIncome"`
+	FinalTSPBalance     decimal.Decimal `json:"finalTSPBalance"`
+	// LowestTSPBalance is the smallest year-end TSP balance after retirement begins
+	LowestTSPBalance decimal.Decimal `json:"lowestTSPBalance"`
+	TSPLongevity     int             `json:"tspLongevity"`
+
+	// Differences from the baseline
+	LifetimeIncomeChange  decimal.Decimal `json:"lifetimeIncomeChange"`
+	FinalTSPBalanceChange decimal.Decimal `json:"finalTSPBalanceChange"`
+}
+
+// RunStressTests projects the named scenario once on the baseline assumptions and once
+// per stress test. Every run uses the fund-level return model so the crisis returns
+// reach the TSP, with the expected fund returns outside the crisis years.
+func (ce *CalculationEngine) RunStressTests(ctx context.Context, config *domain.Configuration, scenarioName string, stresses []StressScenario) (*StressTestResult, error) {
+	base, scenario, years, err := marketReplayBase(config, scenarioName)
+	if err != nil {
+		return nil, err
+	}
+	baselineYear := ce.baselineMarketYear(&base.GlobalAssumptions)
+
+	baseline, err := ce.RunGenericScenario(ctx, base, scenario)
+	if err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+	result := &StressTestResult{
+		ScenarioName: scenarioName,
+		Baseline:     stressOutcome(StressScenario{Name: "baseline", Description: "Expected returns and assumed inflation every year"}, baseline),
+	}
+
+	for _, stress := range stresses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path, historicalYears, err := ce.stressPath(stress, years, baselineYear)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stress.Name, err)
+		}
+		stressed := *base
+		stressed.GlobalAssumptions.MarketPath = path
+		summary, err := ce.RunGenericScenario(ctx, &stressed, scenario)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stress.Name, err)
+		}
+		outcome := stressOutcome(stress, summary)
+		outcome.HistoricalYears = historicalYears
+		outcome.StressYears = stressYears(stress, historicalYears, years)
+		outcome.LifetimeIncomeChange = outcome.TotalLifetimeIncome.Sub(result.Baseline.TotalLifetimeIncome)
+		outcome.FinalTSPBalanceChange = outcome.FinalTSPBalance.Sub(result.Baseline.FinalTSPBalance)
+		result.Stresses = append(result.Stresses, outcome)
+	}
+	return result, nil
+}
+
+// marketReplayBase returns a copy of config on the fund-level return model, which
+// replayed market paths need to reach the TSP, with the named scenario and its number
+// of projection years
+func marketReplayBase(config *domain.Configuration, scenarioName string) (*domain.Configuration, *domain.GenericScenario, int, error) {
+	var scenario *domain.GenericScenario
+	for i := range config.Scenarios {
+		if config.Scenarios[i].Name == scenarioName {
+			scenario = &config.Scenarios[i]
+		}
+	}
+	if scenario == nil {
+		return nil, nil, 0, fmt.Errorf("scenario '%s' not found", scenarioName)
+	}
+
+	base := *config
+	base.GlobalAssumptions.TSPReturnModel = TSPReturnModelFund
+	if !base.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation.Total().IsPositive() {
+		base.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation = defaultFERSMonteCarloConfig(base.GlobalAssumptions.MonteCarloSettings).DefaultTSPAllocation
+	}
+	years := base.GlobalAssumptions.ProjectionYears
+	if scenario.ProjectionYears > 0 {
+		years = scenario.ProjectionYears
+	}
+	return &base, scenario, years, nil
+}
+
+// baselineMarketYear returns the market of a year without stress: the assumed inflation,
+// COLA and FEHB inflation with the expected fund returns
+func (ce *CalculationEngine) baselineMarketYear(assumptions *domain.GlobalAssumptions) domain.MarketYear {
+	r := ce.expectedFundReturns(assumptions)
+	return domain.MarketYear{
+		InflationRate: assumptions.InflationRate,
+		COLARate:      assumptions.COLAGeneralRate,
+		FEHBInflation: assumptions.FEHBPremiumInflation,
+		FundReturns:   domain.TSPFundReturns{CFund: r["C"], SFund: r["S"], IFund: r["I"], FFund: r["F"], GFund: r["G"]},
+	}
+}
+
+// stressPath returns a market path covering every projection year: the crisis years,
+// then the baseline. It also returns the historical years a replay used.
+func (ce *CalculationEngine) stressPath(stress StressScenario, years int, baseline domain.MarketYear) ([]domain.MarketYear, []int, error) {
+	path := make([]domain.MarketYear, 0, years)
+	var historicalYears []int
+
+	if stress.HistoricalStart != 0 {
+		hdm := ce.HistoricalData
+		if hdm == nil || !hdm.IsLoaded {
+			return nil, nil, fmt.Errorf("replaying %d needs historical data", stress.HistoricalStart)
+		}
+		for year := stress.HistoricalStart; len(path) < years; year++ {
+			market, ok := historicalMarketYear(hdm, year, baseline)
+			if !ok {
+				break
+			}
+			path = append(path, market)
+			historicalYears = append(historicalYears, year)
+		}
+		if len(path) == 0 {
+			return nil, nil, fmt.Errorf("no historical stock returns and inflation for %d", stress.HistoricalStart)
+		}
+	}
+
+	for _, shock := range stress.Shocks {
+		for i := 0; i < shock.Years && len(path) < years; i++ {
+			path = append(path, shock.apply(baseline))
+		}
+	}
+
+	for len(path) < years {
+		path = append(path, baseline)
+	}
+	return path, historicalYears, nil
+}
+
+// historicalMarketYear returns a year of history, or false without C fund returns and
+// inflation for it. Funds and COLAs missing that year keep the baseline.
+func historicalMarketYear(hdm *HistoricalDataManager, year int, baseline domain.MarketYear) (domain.MarketYear, bool) {
+	cFund, err := hdm.GetTSPReturn("C", year)
+	if err != nil {
+		return domain.MarketYear{}, false
+	}
+	inflation, err := hdm.GetInflationRate(year)
+	if err != nil {
+		return domain.MarketYear{}, false
+	}
+
+	market := baseline
+	market.HistoricalYear = year
+	market.InflationRate = inflation
+	market.COLARate = inflation
+	if cola, err := hdm.GetCOLARate(year); err == nil {
+		market.COLARate = cola
+	}
+	market.FundReturns.CFund = cFund
+	for fund, r := range map[string]*decimal.Decimal{
+		"S": &market.FundReturns.SFund,
+		"I": &market.FundReturns.IFund,
+		"F": &market.FundReturns.FFund,
+		"G": &market.FundReturns.GFund,
+	} {
+		if historical, err := hdm.GetTSPReturn(fund, year); err == nil {
+			*r = historical
+		}
+	}
+	market.MonthlyFundReturns, _ = hdm.MonthlyFundReturns(year)
+	return market, true
+}
+
+// apply returns the baseline year with the shock's rates in place
+func (s StressShock) apply(baseline domain.MarketYear) domain.MarketYear {
+	market := baseline
+	if s.Equity != nil {
+		market.FundReturns.CFund, market.FundReturns.SFund, market.FundReturns.IFund = *s.Equity, *s.Equity, *s.Equity
+	}
+	if s.Bonds != nil {
+		market.FundReturns.FFund = *s.Bonds
+	}
+	if s.Cash != nil {
+		market.FundReturns.GFund = *s.Cash
+	}
+	if s.Inflation != nil {
+		market.InflationRate = *s.Inflation
+		market.COLARate = *s.Inflation
+	}
+	return market
+}
+
+// stressYears returns how many projection years a stress test replaced
+func stressYears(stress StressScenario, historicalYears []int, years int) int {
+	n := len(historicalYears)
+	for _, shock := range stress.Shocks {
+		n += shock.Years
+	}
+	return min(n, years)
+}
+
+// stressOutcome summarizes a projection of the scenario
+func stressOutcome(stress StressScenario, summary *domain.ScenarioSummary) StressOutcome {
+	outcome := StressOutcome{
+		Name:                stress.Name,
+		Description:         stress.Description,
+		TotalLifetimeIncome: summary.TotalLifetimeIncome,
+		TSPLongevity:        summary.TSPLongevity,
+	}
+	outcome.FinalTSPBalance, outcome.LowestTSPBalance = tspBalanceRange(summary)
+	return outcome
+}
+
+// tspBalanceRange returns the TSP balance at the end of a projection and the smallest
+// year-end balance after retirement begins
+func tspBalanceRange(summary *domain.ScenarioSummary) (final, lowest decimal.Decimal) {
+	if n := len(summary.Projection); n > 0 {
+		final = summary.Projection[n-1].TotalTSPBalance()
+	}
+	first := true
+	for _, year := range summary.Projection {
+		if !year.IsRetired {
+			continue
+		}
+		if balance := year.TotalTSPBalance(); first || balance.LessThan(lowest) {
+			lowest = balance
+			first = false
+		}
+	}
+	return final, lowest
+}
@@ -0,0 +1,75 @@
+package calculation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStressScenario(t *testing.T) {
+	crash, err := ParseStressScenario("Crash")
+	require.NoError(t, err)
+	require.Len(t, crash.Shocks, 1)
+	assert.True(t, crash.Shocks[0].Equity.Equal(decimal.NewFromFloat(-0.40)))
+
+	history, err := ParseStressScenario("1987")
+	require.NoError(t, err)
+	assert.Equal(t, 1987, history.HistoricalStart)
+
+	custom, err := ParseStressScenario("equity=-30% + inflation=0.06 + years=2")
+	require.NoError(t, err)
+	shock := custom.Shocks[0]
+	assert.Equal(t, 2, shock.Years)
+	assert.True(t, shock.Equity.Equal(decimal.NewFromFloat(-0.3)))
+	assert.True(t, shock.Inflation.Equal(decimal.NewFromFloat(0.06)))
+	assert.Nil(t, shock.Bonds)
+
+	for _, bad := range []string{"bogus", "1066", "equity=lots", "gold=10%", "equity=-10%+years=0", "equity"} {
+		_, err := ParseStressScenario(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRunStressTests(t *testing.T) {
+	config := createTestConfig()
+	ce := NewCalculationEngine()
+	ctx := context.Background()
+
+	// Historical crises need data
+	_, err := ce.RunStressTests(ctx, config, "Test Scenario", []StressScenario{{Name: "2000", HistoricalStart: 2000}})
+	assert.ErrorContains(t, err, "needs historical data")
+
+	ce.HistoricalData = loadSyntheticHistory(t)
+	crash, err := ParseStressScenario("crash")
+	require.NoError(t, err)
+	stresses := []StressScenario{
+		{Name: "none"},
+		{Name: "2004", HistoricalStart: 2004},
+		crash,
+	}
+	result, err := ce.RunStressTests(ctx, config, "Test Scenario", stresses)
+	require.NoError(t, err)
+	require.Len(t, result.Stresses, 3)
+
+	// A stress without crisis years is the baseline
+	none := result.Stresses[0]
+	assert.Equal(t, 0, none.StressYears)
+	assert.True(t, none.TotalLifetimeIncome.Equal(result.Baseline.TotalLifetimeIncome))
+	assert.True(t, none.FinalTSPBalance.Equal(result.Baseline.FinalTSPBalance))
+
+	// The replay runs until the data does, then returns to the baseline
+	replay := result.Stresses[1]
+	assert.Equal(t, []int{2004, 2005, 2006, 2007, 2008, 2009, 2010, 2011}, replay.HistoricalYears)
+	assert.Equal(t, 8, replay.StressYears)
+
+	// An immediate equity crash leaves less in the TSP
+	assert.Equal(t, 1, result.Stresses[2].StressYears)
+	assert.True(t, result.Stresses[2].FinalTSPBalanceChange.IsNegative(), result.Stresses[2].FinalTSPBalanceChange.String())
+	assert.True(t, result.Stresses[2].FinalTSPBalance.Sub(result.Baseline.FinalTSPBalance).Equal(result.Stresses[2].FinalTSPBalanceChange))
+
+	_, err = ce.RunStressTests(ctx, config, "Missing", stresses)
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package calculation
+
+import (
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// survivorShareOfReduced is the survivor benefit under an insurable interest election and
+// under CSRS spouse elections, as a share of the reduced (insurable interest) or base annuity
+var survivorShareOfReduced = decimal.NewFromFloat(0.55)
+
+// InsurableInterestReduction returns the annuity reduction for an insurable interest
+// survivor election by how many whole years younger the beneficiary is than the retiree:
+// 10% under 5 years, rising 5 points per 5 years to 40% at 30 or more.
+func InsurableInterestReduction(yearsYounger int) decimal.Decimal {
+	if yearsYounger < 0 {
+		yearsYounger = 0
+	}
+	steps := yearsYounger / 5
+	if steps > 6 {
+		steps = 6
+	}
+	return decimal.NewFromInt(int64(10 + 5*steps)).Div(decimal.NewFromInt(100))
+}
+
+// applyParticipantSurvivorElection reduces an unreduced annuity for the participant's
+// survivor election and returns the reduced annuity along with the survivor annuity
+func applyParticipantSurvivorElection(p *domain.Participant, pensionBase decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if p.SurvivorElection != nil && p.SurvivorElection.Type == domain.SurvivorElectionInsurableInterest {
+		reduced := pensionBase.Mul(decimalOne.Sub(InsurableInterestReduction(beneficiaryYearsYounger(p))))
+		return reduced, reduced.Mul(survivorShareOfReduced)
+	}
+	if p.IsCSRS() {
+		return applyCSRSSurvivorElection(p, pensionBase)
+	}
+	return applySurvivorElection(p, pensionBase)
+}
+
+// applySurvivorElection applies a FERS spouse election: 10% for the full 50% survivor
+// annuity, 5% for the partial 25% one
+func applySurvivorElection(p *domain.Participant, pensionBase decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	election := decimalZero
+	if p.SurvivorBenefitElectionPercent != nil {
+		election = *p.SurvivorBenefitElectionPercent
+	}
+
+	switch {
+	case election.GreaterThanOrEqual(decimal.NewFromFloat(0.5)):
+		return pensionBase.Mul(decimal.NewFromFloat(0.90)), pensionBase.Mul(decimal.NewFromFloat(0.5))
+	case election.IsPositive():
+		return pensionBase.Mul(decimal.NewFromFloat(0.95)), pensionBase.Mul(decimal.NewFromFloat(0.25))
+	default:
+		return pensionBase, decimalZero
+	}
+}
+
+// applyCSRSSurvivorElection applies the CSRS survivor reduction when any survivor benefit
+// is elected: 2.5% of the first $3,600 of the base plus 10% of the rest, for a survivor
+// annuity of 55% of the base. The base is the full annuity unless a partial base is set.
+func applyCSRSSurvivorElection(p *domain.Participant, pensionBase decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if p.SurvivorBenefitElectionPercent == nil || !p.SurvivorBenefitElectionPercent.GreaterThan(decimalZero) {
+		return pensionBase, decimalZero
+	}
+
+	base := pensionBase
+	if p.SurvivorElection != nil && p.SurvivorElection.BaseAmount.IsPositive() {
+		base = decimal.Min(base, p.SurvivorElection.BaseAmount)
+	}
+	threshold := decimal.NewFromInt(3600)
+	reduction := decimal.Min(base, threshold).Mul(decimal.NewFromFloat(0.025))
+	if base.GreaterThan(threshold) {
+		reduction = reduction.Add(base.Sub(threshold).Mul(decimal.NewFromFloat(0.10)))
+	}
+	return pensionBase.Sub(reduction), base.Mul(survivorShareOfReduced)
+}
+
+// applyExternalSurvivorElection applies an external plan's survivor election, which may be
+// any partial percentage with its plan-specific reduction
+func applyExternalSurvivorElection(pension *domain.ExternalPension) (decimal.Decimal, decimal.Decimal) {
+	annual := pension.MonthlyBenefit.Mul(decimalTwelve)
+	if !pension.SurvivorBenefit.IsPositive() {
+		return annual, decimalZero
+	}
+	return annual.Mul(decimalOne.Sub(pension.SurvivorReduction)), annual.Mul(pension.SurvivorBenefit)
+}
+
+// rollOverPensionLumpSum takes an external pension as its lump sum, rolled over tax free to
+// the participant's traditional IRA or TSP. No annuity or survivor annuity is paid.
+func (st *participantState) rollOverPensionLumpSum(pension *domain.ExternalPension) {
+	lumpSum := decimal.Max(pension.LumpSum, decimalZero)
+	if pension.LumpSumRollover == domain.PensionRolloverTSP {
+		st.tspBalanceTraditional = st.tspBalanceTraditional.Add(lumpSum)
+		st.tspBalance = st.tspBalance.Add(lumpSum)
+		st.tspWithdrawalBase = st.tspWithdrawalBase.Add(lumpSum)
+		return
+	}
+	st.iraBalanceTraditional = st.iraBalanceTraditional.Add(lumpSum)
+}
+
+// beneficiaryYearsYounger returns how many whole years younger an insurable interest
+// beneficiary is than the participant
+func beneficiaryYearsYounger(p *domain.Participant) int {
+	if p.SurvivorElection == nil || p.SurvivorElection.BeneficiaryBirthDate == nil {
+		return 0
+	}
+	beneficiary := *p.SurvivorElection.BeneficiaryBirthDate
+	years := beneficiary.Year() - p.BirthDate.Year()
+	if beneficiary.YearDay() < p.BirthDate.YearDay() {
+		years--
+	}
+	return years
+}
+
+// SurvivorElectionSummaries describes each federal or external pension's survivor election
+// in a scenario: what it costs the retiree and what it leaves the survivor
+func SurvivorElectionSummaries(household *domain.Household, scenario *domain.GenericScenario) []domain.SurvivorElectionSummary {
+	household = withoutDeclinedMilitaryDeposits(household, scenario)
+	var summaries []domain.SurvivorElectionSummary
+	for i := range household.Participants {
+		p := &household.Participants[i]
+		ps, ok := scenario.ParticipantScenarios[p.Name]
+		if !ok || p.IsAlreadyRetired() {
+			continue
+		}
+
+		var unreduced, reduced, survivor decimal.Decimal
+		switch {
+		case p.ExternalPension != nil && !p.IsFederal:
+			unreduced = p.ExternalPension.MonthlyBenefit.Mul(decimalTwelve)
+			reduced, survivor = applyExternalSurvivorElection(p.ExternalPension)
+		case ps.SeparationDate != nil && ps.AnnuityStartAge > 0:
+			unreduced = unreducedDeferredParticipantPension(p, *ps.SeparationDate, ps.AnnuityStartAge)
+			reduced, survivor = applyParticipantSurvivorElection(p, unreduced)
+		case ps.RetirementDate != nil:
+			unreduced = unreducedParticipantPension(p, *ps.RetirementDate)
+			reduced, survivor = applyParticipantSurvivorElection(p, unreduced)
+		}
+		if !unreduced.IsPositive() {
+			continue
+		}
+
+		summaries = append(summaries, domain.SurvivorElectionSummary{
+			Participant:      p.Name,
+			Election:         describeSurvivorElection(p),
+			UnreducedAnnuity: unreduced,
+			ReductionFactor:  decimalOne.Sub(reduced.Div(unreduced)).Round(4),
+			ReducedAnnuity:   reduced,
+			SurvivorAnnuity:  survivor,
+		})
+	}
+	return summaries
+}
+
+// describeSurvivorElection names a participant's survivor election for reports
+func describeSurvivorElection(p *domain.Participant) string {
+	if !p.IsFederal && p.ExternalPension != nil {
+		if !p.ExternalPension.SurvivorBenefit.IsPositive() {
+			return "none"
+		}
+		return fmt.Sprintf("%s%% survivor", p.ExternalPension.SurvivorBenefit.Mul(decimal.NewFromInt(100)).StringFixed(0))
+	}
+	if p.SurvivorElection != nil && p.SurvivorElection.Type == domain.SurvivorElectionInsurableInterest {
+		return fmt.Sprintf("insurable interest (beneficiary %d years younger)", beneficiaryYearsYounger(p))
+	}
+	if p.SurvivorBenefitElectionPercent == nil || !p.SurvivorBenefitElectionPercent.IsPositive() {
+		return "none"
+	}
+	if p.IsCSRS() {
+		if p.SurvivorElection != nil && p.SurvivorElection.BaseAmount.IsPositive() {
+			return fmt.Sprintf("partial spouse (55%% of $%s base)", p.SurvivorElection.BaseAmount.StringFixed(0))
+		}
+		return "full spouse (55%)"
+	}
+	if p.SurvivorBenefitElectionPercent.GreaterThanOrEqual(decimal.NewFromFloat(0.5)) {
+		return "full spouse (50%)"
+	}
+	return "partial spouse (25%)"
+}
@@ -0,0 +1,73 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func survivorTestParticipant(system string, percent float64) *domain.Participant {
+	return &domain.Participant{
+		Name:                           "Pat",
+		IsFederal:                      true,
+		RetirementSystem:               system,
+		BirthDate:                      time.Date(1960, 6, 1, 0, 0, 0, 0, time.UTC),
+		SurvivorBenefitElectionPercent: decimalPtr(decimal.NewFromFloat(percent)),
+	}
+}
+
+func TestInsurableInterestReduction(t *testing.T) {
+	cases := map[int]float64{-3: 0.10, 0: 0.10, 4: 0.10, 5: 0.15, 12: 0.20, 19: 0.25, 24: 0.30, 29: 0.35, 30: 0.40, 45: 0.40}
+	for years, want := range cases {
+		assert.True(t, InsurableInterestReduction(years).Equal(decimal.NewFromFloat(want)), "%d years younger", years)
+	}
+}
+
+func TestApplyParticipantSurvivorElection(t *testing.T) {
+	base := decimal.NewFromInt(40000)
+
+	t.Run("FERS full and partial spouse", func(t *testing.T) {
+		reduced, survivor := applyParticipantSurvivorElection(survivorTestParticipant(domain.RetirementSystemFERS, 0.5), base)
+		assert.True(t, reduced.Equal(decimal.NewFromInt(36000)))
+		assert.True(t, survivor.Equal(decimal.NewFromInt(20000)))
+
+		reduced, survivor = applyParticipantSurvivorElection(survivorTestParticipant(domain.RetirementSystemFERS, 0.25), base)
+		assert.True(t, reduced.Equal(decimal.NewFromInt(38000)))
+		assert.True(t, survivor.Equal(decimal.NewFromInt(10000)))
+	})
+
+	t.Run("CSRS partial base", func(t *testing.T) {
+		p := survivorTestParticipant(domain.RetirementSystemCSRS, 0.55)
+		p.SurvivorElection = &domain.SurvivorElection{BaseAmount: decimal.NewFromInt(10000)}
+		reduced, survivor := applyParticipantSurvivorElection(p, base)
+		// 2.5% of 3,600 + 10% of 6,400 = 730
+		assert.True(t, reduced.Equal(decimal.NewFromInt(39270)), reduced.String())
+		assert.True(t, survivor.Equal(decimal.NewFromInt(5500)), survivor.String())
+	})
+
+	t.Run("insurable interest", func(t *testing.T) {
+		p := survivorTestParticipant(domain.RetirementSystemFERS, 0)
+		p.SurvivorElection = &domain.SurvivorElection{
+			Type:                 domain.SurvivorElectionInsurableInterest,
+			BeneficiaryBirthDate: timePtr(time.Date(1982, 3, 1, 0, 0, 0, 0, time.UTC)),
+		}
+		reduced, survivor := applyParticipantSurvivorElection(p, base)
+		// 21 years younger: 30% reduction, survivor 55% of the reduced annuity
+		assert.True(t, reduced.Equal(decimal.NewFromInt(28000)), reduced.String())
+		assert.True(t, survivor.Equal(decimal.NewFromInt(15400)), survivor.String())
+	})
+}
+
+func TestApplyExternalSurvivorElection(t *testing.T) {
+	pension := &domain.ExternalPension{
+		MonthlyBenefit:    decimal.NewFromInt(2000),
+		SurvivorBenefit:   decimal.NewFromFloat(0.75),
+		SurvivorReduction: decimal.NewFromFloat(0.12),
+	}
+	annual, survivor := applyExternalSurvivorElection(pension)
+	assert.True(t, annual.Equal(decimal.NewFromInt(21120)), annual.String())
+	assert.True(t, survivor.Equal(decimal.NewFromInt(18000)), survivor.String())
+}
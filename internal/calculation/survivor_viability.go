@@ -107,6 +107,7 @@ func (sva *SurvivorViabilityAnalyzer) AnalyzeSurvivorViability(
 		ScenarioName:        scenario.Name,
 		DeceasedParticipant: deceasedName,
 		SurvivorParticipant: survivorName,
+		ParticipantLabels:   config.Household.ParticipantLabels(),
 		DeathYear:           deathYear,
 		DeathAge:            deathAge,
 		SurvivorAge:         survivorAge,
@@ -181,10 +182,10 @@ func (sva *SurvivorViabilityAnalyzer) analyzeYear(
 
 	// Calculate income sources
 	incomeSources := domain.SurvivorIncomeSources{
-		SurvivorPension:    yearData.Pensions[survivorName],
-		SurvivorSS:         yearData.SSBenefits[survivorName],
-		DeceasedSurvivorSS: decimal.Zero, // Will be calculated separately
-		TSPWithdrawals:     yearData.TSPWithdrawals[survivorName],
+		SurvivorPension:    yearData.Pensions.Get(survivorName),
+		SurvivorSS:         yearData.SSBenefits.Get(survivorName).Sub(yearData.SurvivorSSBenefits.Get(survivorName)),
+		DeceasedSurvivorSS: yearData.SurvivorSSBenefits.Get(survivorName), // step-up to the deceased's benefit
+		TSPWithdrawals:     yearData.TSPWithdrawals.Get(survivorName),
 		OtherIncome:        decimal.Zero,
 	}
 
@@ -197,9 +198,9 @@ func (sva *SurvivorViabilityAnalyzer) analyzeYear(
 
 	// Calculate TSP analysis
 	tspAnalysis := domain.SurvivorTSPAnalysis{
-		InitialBalance:   yearData.TSPBalances[survivorName],
-		FinalBalance:     yearData.TSPBalances[survivorName], // Same as initial for single year
-		AnnualWithdrawal: yearData.TSPWithdrawals[survivorName],
+		InitialBalance:   yearData.TSPBalances.Get(survivorName),
+		FinalBalance:     yearData.TSPBalances.Get(survivorName), // Same as initial for single year
+		AnnualWithdrawal: yearData.TSPWithdrawals.Get(survivorName),
 	}
 
 	if tspAnalysis.InitialBalance.GreaterThan(decimal.Zero) {
@@ -227,7 +228,7 @@ func (sva *SurvivorViabilityAnalyzer) analyzeYear(
 		IncomeSources:   incomeSources,
 		TSPAnalysis:     tspAnalysis,
 		IRMAARisk:       irmaaRisk,
-		IRMAACost:       yearData.IRMAASurcharge.Mul(decimal.NewFromInt(12)),
+		IRMAACost:       yearData.IRMAASurcharge.Add(yearData.IRMAASurchargePartD).Mul(decimal.NewFromInt(12)),
 	}, nil
 }
 
@@ -40,10 +40,45 @@ type FederalTaxCalculator struct {
 	Brackets                []TaxBracket
 	BracketsSingle          []TaxBracket
 	AdditionalStdDed        decimal.Decimal // For age 65+
+	// Long-term capital gains / qualified dividend brackets (0/15/20%)
+	CapitalGainsBrackets       []TaxBracket
+	CapitalGainsBracketsSingle []TaxBracket
+	// Net Investment Income Tax
+	NIITRate            decimal.Decimal
+	NIITThresholdMFJ    decimal.Decimal
+	NIITThresholdSingle decimal.Decimal
+	// Cap on deductible state and local taxes when itemizing
+	SALTCap decimal.Decimal
+}
+
+// defaultSALTCap is the 2025 state and local tax deduction limit
+var defaultSALTCap = decimal.NewFromInt(10000)
+
+// Statutory NIIT parameters (IRC §1411); thresholds are not inflation indexed
+var (
+	defaultNIITRate            = decimal.NewFromFloat(0.038)
+	defaultNIITThresholdMFJ    = decimal.NewFromInt(250000)
+	defaultNIITThresholdSingle = decimal.NewFromInt(200000)
+)
+
+// defaultCapitalGainsBrackets returns the 2025 MFJ and single 0/15/20% brackets
+func defaultCapitalGainsBrackets() (mfj, single []TaxBracket) {
+	mfj = []TaxBracket{
+		{decimal.Zero, decimal.NewFromInt(96700), decimal.Zero},
+		{decimal.NewFromInt(96701), decimal.NewFromInt(600050), decimal.NewFromFloat(0.15)},
+		{decimal.NewFromInt(600051), decimal.NewFromInt(999999999), decimal.NewFromFloat(0.20)},
+	}
+	single = []TaxBracket{
+		{decimal.Zero, decimal.NewFromInt(48350), decimal.Zero},
+		{decimal.NewFromInt(48351), decimal.NewFromInt(533400), decimal.NewFromFloat(0.15)},
+		{decimal.NewFromInt(533401), decimal.NewFromInt(999999999), decimal.NewFromFloat(0.20)},
+	}
+	return mfj, single
 }
 
 // NewFederalTaxCalculator2025 creates a new federal tax calculator for 2025
 func NewFederalTaxCalculator2025() *FederalTaxCalculator {
+	cgMFJ, cgSingle := defaultCapitalGainsBrackets()
 	return &FederalTaxCalculator{
 		Year:              2025,
 		StandardDeduction: decimal.NewFromInt(30000), // MFJ 2025 estimated
@@ -57,6 +92,12 @@ func NewFederalTaxCalculator2025() *FederalTaxCalculator {
 			{decimal.NewFromInt(487451), decimal.NewFromInt(731200), decimal.NewFromFloat(0.35)},
 			{decimal.NewFromInt(731201), decimal.NewFromInt(999999999), decimal.NewFromFloat(0.37)},
 		},
+		CapitalGainsBrackets:       cgMFJ,
+		CapitalGainsBracketsSingle: cgSingle,
+		NIITRate:                   defaultNIITRate,
+		NIITThresholdMFJ:           defaultNIITThresholdMFJ,
+		NIITThresholdSingle:        defaultNIITThresholdSingle,
+		SALTCap:                    defaultSALTCap,
 	}
 }
 
@@ -91,7 +132,112 @@ func NewFederalTaxCalculator(config domain.FederalTaxConfig) *FederalTaxCalculat
 			bracketsSingle = append(bracketsSingle, TaxBracket{Min: b.Min.Div(decimal.NewFromInt(2)), Max: b.Max.Div(decimal.NewFromInt(2)), Rate: b.Rate})
 		}
 	}
-	return &FederalTaxCalculator{Year: 2025, StandardDeduction: config.StandardDeductionMFJ, StandardDeductionSingle: stdSingle, AdditionalStdDed: config.AdditionalStandardDeduction, Brackets: bracketsMFJ, BracketsSingle: bracketsSingle}
+	cgMFJ, cgSingle := defaultCapitalGainsBrackets()
+	if len(config.CapitalGainsBrackets) > 0 {
+		cgMFJ = nil
+		for _, b := range config.CapitalGainsBrackets {
+			cgMFJ = append(cgMFJ, TaxBracket{Min: b.Min, Max: b.Max, Rate: b.Rate})
+		}
+	}
+	if len(config.CapitalGainsBracketsSingle) > 0 {
+		cgSingle = nil
+		for _, b := range config.CapitalGainsBracketsSingle {
+			cgSingle = append(cgSingle, TaxBracket{Min: b.Min, Max: b.Max, Rate: b.Rate})
+		}
+	}
+	niitRate, niitMFJ, niitSingle := config.NIITRate, config.NIITThresholdMFJ, config.NIITThresholdSingle
+	if niitRate.IsZero() {
+		niitRate = defaultNIITRate
+	}
+	if niitMFJ.IsZero() {
+		niitMFJ = defaultNIITThresholdMFJ
+	}
+	if niitSingle.IsZero() {
+		niitSingle = defaultNIITThresholdSingle
+	}
+	saltCap := config.SALTCap
+	if saltCap.IsZero() {
+		saltCap = defaultSALTCap
+	}
+	return &FederalTaxCalculator{Year: 2025, StandardDeduction: config.StandardDeductionMFJ, StandardDeductionSingle: stdSingle, AdditionalStdDed: config.AdditionalStandardDeduction, Brackets: bracketsMFJ, BracketsSingle: bracketsSingle, CapitalGainsBrackets: cgMFJ, CapitalGainsBracketsSingle: cgSingle, NIITRate: niitRate, NIITThresholdMFJ: niitMFJ, NIITThresholdSingle: niitSingle, SALTCap: saltCap}
+}
+
+// ItemizedDeduction totals Schedule A deductions, limiting state and local taxes to the SALT cap
+func (ftc *FederalTaxCalculator) ItemizedDeduction(income domain.TaxableIncome) decimal.Decimal {
+	salt := income.SALTPaid
+	if ftc.SALTCap.GreaterThan(decimal.Zero) {
+		salt = decimal.Min(salt, ftc.SALTCap)
+	}
+	return salt.Add(income.MortgageInterest).Add(income.CharitableContributions)
+}
+
+// chooseDeduction returns the larger of the standard and itemized deductions and whether itemizing won.
+// An election of "standard" or "itemized" forces that deduction regardless of which is larger.
+func (ftc *FederalTaxCalculator) chooseDeduction(standard decimal.Decimal, income domain.TaxableIncome, election string) (decimal.Decimal, bool) {
+	itemized := ftc.ItemizedDeduction(income)
+	switch election {
+	case domain.DeductionStandard:
+		return standard, false
+	case domain.DeductionItemized:
+		return itemized, true
+	}
+	if itemized.GreaterThan(standard) {
+		return itemized, true
+	}
+	return standard, false
+}
+
+// CalculateNIIT returns the Net Investment Income Tax: the rate applied to the lesser of
+// net investment income or MAGI above the filing-status threshold
+func (ftc *FederalTaxCalculator) CalculateNIIT(investmentIncome, magi decimal.Decimal, filingStatus string) decimal.Decimal {
+	if investmentIncome.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	threshold := ftc.NIITThresholdMFJ
+	if filingStatus == "single" {
+		threshold = ftc.NIITThresholdSingle
+	}
+	excess := magi.Sub(threshold)
+	if excess.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	return decimal.Min(investmentIncome, excess).Mul(ftc.NIITRate)
+}
+
+// CalculateNIITForIncome computes NIIT from taxable income components; MAGI is approximated
+// as ordinary income plus preferential (capital gain and qualified dividend) income
+func (ftc *FederalTaxCalculator) CalculateNIITForIncome(income domain.TaxableIncome, filingStatus string) decimal.Decimal {
+	investmentIncome := income.LongTermCapitalGains.Add(income.QualifiedDividends)
+	magi := income.Salary.Add(income.FERSPension).Add(income.TSPWithdrawalsTrad).Add(income.TaxableSSBenefits).Add(income.OtherTaxableIncome).Add(investmentIncome)
+	// Rents and royalties are net investment income too; MAGI already has them as ordinary income
+	return ftc.CalculateNIIT(investmentIncome.Add(income.PassiveIncome), magi, filingStatus)
+}
+
+// CalculateCapitalGainsTax taxes preferential income (long-term gains and qualified
+// dividends) stacked on top of ordinary taxable income using the 0/15/20% brackets
+func CalculateCapitalGainsTax(ordinaryTaxable, preferential decimal.Decimal, brackets []TaxBracket) decimal.Decimal {
+	if preferential.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	start := decimal.Max(ordinaryTaxable, decimal.Zero)
+	end := start.Add(preferential)
+	tax := decimal.Zero
+	for _, b := range brackets {
+		lo := decimal.Max(start, b.Min)
+		hi := decimal.Min(end, b.Max)
+		if hi.GreaterThan(lo) {
+			tax = tax.Add(hi.Sub(lo).Mul(b.Rate))
+		}
+	}
+	return tax
+}
+
+// splitTaxableIncome applies the deduction to ordinary income first and returns the
+// ordinary and preferential (LTCG + qualified dividend) portions of taxable income
+func splitTaxableIncome(ordinaryIncome, preferentialIncome, deduction decimal.Decimal) (ordinaryTaxable, preferentialTaxable decimal.Decimal) {
+	total := decimal.Max(ordinaryIncome.Add(preferentialIncome).Sub(deduction), decimal.Zero)
+	ordinaryTaxable = decimal.Min(decimal.Max(ordinaryIncome.Sub(deduction), decimal.Zero), total)
+	return ordinaryTaxable, total.Sub(ordinaryTaxable)
 }
 
 // CalculateFederalTax calculates federal income tax
@@ -156,8 +302,8 @@ func (ptc *PennsylvaniaTaxCalculator) CalculateTax(income domain.TaxableIncome,
 		return taxablePA.Mul(ptc.Rate)
 	}
 
-	// While working: tax wages at configured rate
-	return income.WageIncome.Mul(ptc.Rate)
+	// While working: tax wages, business profits and rents at configured rate
+	return income.WageIncome.Add(income.SelfEmploymentIncome).Add(income.PassiveIncome).Mul(ptc.Rate)
 }
 
 // UpperMakefieldEITCalculator handles Upper Makefield Township local tax calculations
@@ -294,6 +440,23 @@ func (fc *FICACalculator) CalculateFICA(wages decimal.Decimal, totalHouseholdWag
 	return ssTax.Add(medicareTax).Add(additionalMedicare)
 }
 
+// seEarningsFactor is the share of net self-employment earnings subject to self-employment
+// tax, which excludes the employer-equivalent half of the tax
+var seEarningsFactor = decimal.NewFromFloat(0.9235)
+
+// CalculateSelfEmploymentTax calculates the self-employment tax on a person's net earnings
+// from self-employment: both the employee and employer shares of Social Security and
+// Medicare on 92.35% of the earnings. Social Security applies only to the part of the wage
+// base the person's wages left unused. No tax is due on less than $400.
+func (fc *FICACalculator) CalculateSelfEmploymentTax(netEarnings, wages decimal.Decimal) decimal.Decimal {
+	base := netEarnings.Mul(seEarningsFactor)
+	if base.LessThan(decimal.NewFromInt(400)) {
+		return decimal.Zero
+	}
+	ssBase := decimal.Min(base, decimal.Max(fc.SSWageBase.Sub(wages), decimal.Zero))
+	return ssBase.Mul(fc.SSRate).Add(base.Mul(fc.MedicareRate)).Mul(decimal.NewFromInt(2))
+}
+
 // CalculateFICAWithProration calculates FICA taxes with proration for partial year work
 func (fc *FICACalculator) CalculateFICAWithProration(wages decimal.Decimal, totalHouseholdWages decimal.Decimal, workFraction decimal.Decimal) decimal.Decimal {
 	// Apply work fraction to wages first
@@ -386,11 +549,10 @@ func (ctc *ComprehensiveTaxCalculator) calculateFederalTaxWithInflation(taxableI
 		standardDeduction = standardDeduction.Add(ctc.FederalTaxCalc.AdditionalStdDed)
 	}
 
-	// Calculate adjusted gross income
-	agi := totalIncome.Sub(standardDeduction)
-	if agi.LessThan(decimal.Zero) {
-		agi = decimal.Zero
-	}
+	// Calculate taxable income; the deduction offsets ordinary income before capital gains
+	deduction, _ := ctc.FederalTaxCalc.chooseDeduction(standardDeduction, taxableIncome, "")
+	preferentialIncome := taxableIncome.LongTermCapitalGains.Add(taxableIncome.QualifiedDividends)
+	agi, preferentialTaxable := splitTaxableIncome(totalIncome, preferentialIncome, deduction)
 
 	// Apply inflation adjustment to tax brackets
 	// Note: For current tests and 2025 calculations, we do not adjust brackets
@@ -428,30 +590,55 @@ func (ctc *ComprehensiveTaxCalculator) calculateFederalTaxWithInflation(taxableI
 		}
 	}
 
-	return tax
+	tax = tax.Add(CalculateCapitalGainsTax(agi, preferentialTaxable, ctc.FederalTaxCalc.CapitalGainsBrackets))
+	return tax.Add(ctc.FederalTaxCalc.CalculateNIITForIncome(taxableIncome, "married_filing_jointly"))
+}
+
+// FederalTaxResult breaks down a federal income tax computation
+type FederalTaxResult struct {
+	Tax               decimal.Decimal
+	TaxableIncome     decimal.Decimal // after the deduction, ordinary plus preferential
+	StandardDeduction decimal.Decimal
+	ItemizedDeduction decimal.Decimal
+	Itemized          bool // true when the itemized deduction was taken
 }
 
 // calculateFederalTaxWithStatus allows specifying filing status ("mfj" or "single") and number of seniors 65+.
 func (ctc *ComprehensiveTaxCalculator) calculateFederalTaxWithStatus(agiComponents domain.TaxableIncome, filingStatus string, seniors int) decimal.Decimal {
+	return ctc.calculateFederalTaxDetailed(agiComponents, filingStatus, seniors).Tax
+}
+
+// calculateFederalTaxDetailed computes federal tax and reports which deduction was used
+func (ctc *ComprehensiveTaxCalculator) calculateFederalTaxDetailed(agiComponents domain.TaxableIncome, filingStatus string, seniors int) FederalTaxResult {
+	return ctc.calculateFederalTaxElected(agiComponents, filingStatus, seniors, "")
+}
+
+// calculateFederalTaxElected is calculateFederalTaxDetailed with a deduction election
+// ("standard", "itemized", or empty to take the larger)
+func (ctc *ComprehensiveTaxCalculator) calculateFederalTaxElected(agiComponents domain.TaxableIncome, filingStatus string, seniors int, deductionElection string) FederalTaxResult {
 	totalIncome := agiComponents.Salary.Add(agiComponents.FERSPension).Add(agiComponents.TSPWithdrawalsTrad).Add(agiComponents.TaxableSSBenefits).Add(agiComponents.OtherTaxableIncome)
 
 	// Standard deduction based on filing status
 	standardDed := ctc.FederalTaxCalc.StandardDeduction
 	brackets := ctc.FederalTaxCalc.Brackets
+	cgBrackets := ctc.FederalTaxCalc.CapitalGainsBrackets
 	if filingStatus == "single" {
 		standardDed = ctc.FederalTaxCalc.StandardDeductionSingle
 		if len(ctc.FederalTaxCalc.BracketsSingle) > 0 {
 			brackets = ctc.FederalTaxCalc.BracketsSingle
 		}
+		if len(ctc.FederalTaxCalc.CapitalGainsBracketsSingle) > 0 {
+			cgBrackets = ctc.FederalTaxCalc.CapitalGainsBracketsSingle
+		}
 	}
 	for i := 0; i < seniors; i++ {
 		standardDed = standardDed.Add(ctc.FederalTaxCalc.AdditionalStdDed)
 	}
+	itemizedDed := ctc.FederalTaxCalc.ItemizedDeduction(agiComponents)
+	deduction, itemized := ctc.FederalTaxCalc.chooseDeduction(standardDed, agiComponents, deductionElection)
 
-	agi := totalIncome.Sub(standardDed)
-	if agi.LessThan(decimal.Zero) {
-		agi = decimal.Zero
-	}
+	preferentialIncome := agiComponents.LongTermCapitalGains.Add(agiComponents.QualifiedDividends)
+	agi, preferentialTaxable := splitTaxableIncome(totalIncome, preferentialIncome, deduction)
 
 	inflationAdjustment := decimal.NewFromFloat(1.0)
 	remaining := agi
@@ -472,7 +659,15 @@ func (ctc *ComprehensiveTaxCalculator) calculateFederalTaxWithStatus(agiComponen
 			remaining = remaining.Sub(incomeInBracket)
 		}
 	}
-	return tax
+	tax = tax.Add(CalculateCapitalGainsTax(agi, preferentialTaxable, cgBrackets))
+	tax = tax.Add(ctc.FederalTaxCalc.CalculateNIITForIncome(agiComponents, filingStatus))
+	return FederalTaxResult{
+		Tax:               tax,
+		TaxableIncome:     agi.Add(preferentialTaxable),
+		StandardDeduction: standardDed,
+		ItemizedDeduction: itemizedDed,
+		Itemized:          itemized,
+	}
 }
 
 // CalculateTaxableIncome creates a TaxableIncome struct from cash flow data
@@ -481,43 +676,44 @@ func CalculateTaxableIncome(cashFlow domain.AnnualCashFlow, isRetired bool) doma
 	ferPension := decimal.Zero
 	// Sort participant names for deterministic processing order
 	var pensionNames []string
-	for name := range cashFlow.Pensions {
+	for _, name := range cashFlow.Pensions.Names() {
 		pensionNames = append(pensionNames, name)
 	}
 	sort.Strings(pensionNames)
 	for _, name := range pensionNames {
-		ferPension = ferPension.Add(cashFlow.Pensions[name])
+		ferPension = ferPension.Add(cashFlow.Pensions.Get(name))
 	}
 
 	var survivorNames []string
-	for name := range cashFlow.SurvivorPensions {
+	for _, name := range cashFlow.SurvivorPensions.Names() {
 		survivorNames = append(survivorNames, name)
 	}
 	sort.Strings(survivorNames)
 	for _, name := range survivorNames {
-		ferPension = ferPension.Add(cashFlow.SurvivorPensions[name])
+		ferPension = ferPension.Add(cashFlow.SurvivorPensions.Get(name))
 	}
 
 	withdrawals := decimal.Zero
 	var withdrawalNames []string
-	for name := range cashFlow.TSPWithdrawals {
+	for _, name := range cashFlow.TSPWithdrawals.Names() {
 		withdrawalNames = append(withdrawalNames, name)
 	}
 	sort.Strings(withdrawalNames)
 	for _, name := range withdrawalNames {
-		withdrawals = withdrawals.Add(cashFlow.TSPWithdrawals[name])
+		withdrawals = withdrawals.Add(cashFlow.TSPWithdrawals.Get(name))
 	}
 
 	ss := decimal.Zero
 	var ssNames []string
-	for name := range cashFlow.SSBenefits {
+	for _, name := range cashFlow.SSBenefits.Names() {
 		ssNames = append(ssNames, name)
 	}
 	sort.Strings(ssNames)
 	for _, name := range ssNames {
-		ss = ss.Add(cashFlow.SSBenefits[name])
+		ss = ss.Add(cashFlow.SSBenefits.Get(name))
 	}
-	return domain.TaxableIncome{Salary: decimal.Zero, FERSPension: ferPension, TSPWithdrawalsTrad: withdrawals, TaxableSSBenefits: ss, OtherTaxableIncome: decimal.Zero, WageIncome: decimal.Zero, InterestIncome: decimal.Zero}
+	withdrawals = withdrawals.Add(cashFlow.GetTotalTSPAnnuity()).Add(cashFlow.GetTaxableIncomeAnnuity())
+	return domain.TaxableIncome{Salary: decimal.Zero, FERSPension: ferPension, TSPWithdrawalsTrad: withdrawals, TaxableSSBenefits: ss, OtherTaxableIncome: decimal.Zero, WageIncome: decimal.Zero, InterestIncome: decimal.Zero, LongTermCapitalGains: cashFlow.CapitalGainsRealized}
 }
 
 // CalculateCurrentTaxableIncome calculates taxable income for current employment
@@ -508,3 +508,130 @@ func TestComprehensiveTaxCalculation(t *testing.T) {
 		})
 	}
 }
+
+// TestCapitalGainsStacking verifies LTCG and qualified dividends stack on top of ordinary income
+func TestCapitalGainsStacking(t *testing.T) {
+	calculator := NewComprehensiveTaxCalculator()
+	calculator.FederalTaxCalc.StandardDeductionSingle = decimal.NewFromInt(15000)
+
+	tests := []struct {
+		name        string
+		income      domain.TaxableIncome
+		status      string
+		expectedTax decimal.Decimal
+	}{
+		{
+			name:        "Gains fully in 0% bracket",
+			income:      domain.TaxableIncome{Salary: decimal.NewFromInt(50000), LongTermCapitalGains: decimal.NewFromInt(40000)},
+			status:      "mfj",
+			expectedTax: decimal.NewFromInt(2000), // ordinary 20000 * 0.10; gains 20000-60000 taxed at 0%
+		},
+		{
+			name:        "Gains straddle 0% and 15%",
+			income:      domain.TaxableIncome{FERSPension: decimal.NewFromInt(110000), QualifiedDividends: decimal.NewFromInt(30000)},
+			status:      "mfj",
+			expectedTax: decimal.NewFromFloat(11130.85), // 9136 ordinary + 13299 * 0.15
+		},
+		{
+			name:        "Deduction absorbs ordinary income first",
+			income:      domain.TaxableIncome{FERSPension: decimal.NewFromInt(10000), LongTermCapitalGains: decimal.NewFromInt(30000)},
+			status:      "mfj",
+			expectedTax: decimal.Zero,
+		},
+		{
+			name:        "Single filer uses single capital gains brackets",
+			income:      domain.TaxableIncome{FERSPension: decimal.NewFromInt(65000), LongTermCapitalGains: decimal.NewFromInt(20000)},
+			status:      "single",
+			expectedTax: decimal.NewFromInt(8536), // 5536 ordinary on 50000 + 20000 * 0.15 (above the 48,350 single threshold)
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculator.calculateFederalTaxWithStatus(tt.income, tt.status, 0)
+			assert.True(t, got.Sub(tt.expectedTax).Abs().LessThan(decimal.NewFromInt(1)),
+				"expected %s, got %s", tt.expectedTax.StringFixed(2), got.StringFixed(2))
+		})
+	}
+}
+
+func TestCalculateCapitalGainsTax(t *testing.T) {
+	mfj, _ := defaultCapitalGainsBrackets()
+
+	assert.True(t, CalculateCapitalGainsTax(decimal.NewFromInt(100000), decimal.Zero, mfj).IsZero())
+	assert.True(t, CalculateCapitalGainsTax(decimal.NewFromInt(100000), decimal.NewFromInt(50000), mfj).Equal(decimal.NewFromInt(7500)))
+	// 20% applies above 600,050
+	assert.True(t, CalculateCapitalGainsTax(decimal.NewFromInt(700000), decimal.NewFromInt(10000), mfj).Equal(decimal.NewFromInt(2000)))
+}
+
+func TestNetInvestmentIncomeTax(t *testing.T) {
+	ftc := NewFederalTaxCalculator2025()
+
+	tests := []struct {
+		name     string
+		nii      decimal.Decimal
+		magi     decimal.Decimal
+		status   string
+		expected decimal.Decimal
+	}{
+		{"below MFJ threshold", decimal.NewFromInt(50000), decimal.NewFromInt(240000), "married_filing_jointly", decimal.Zero},
+		{"excess MAGI smaller than NII", decimal.NewFromInt(50000), decimal.NewFromInt(270000), "married_filing_jointly", decimal.NewFromInt(760)},
+		{"NII smaller than excess MAGI", decimal.NewFromInt(10000), decimal.NewFromInt(400000), "married_filing_jointly", decimal.NewFromInt(380)},
+		{"single threshold", decimal.NewFromInt(50000), decimal.NewFromInt(220000), "single", decimal.NewFromInt(760)},
+		{"no investment income", decimal.Zero, decimal.NewFromInt(500000), "single", decimal.Zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ftc.CalculateNIIT(tt.nii, tt.magi, tt.status)
+			assert.True(t, got.Equal(tt.expected), "expected %s, got %s", tt.expected, got)
+		})
+	}
+
+	// Roth conversions (ordinary income) push MAGI over the threshold and expose gains to NIIT
+	income := domain.TaxableIncome{TSPWithdrawalsTrad: decimal.NewFromInt(240000), LongTermCapitalGains: decimal.NewFromInt(30000)}
+	assert.True(t, ftc.CalculateNIITForIncome(income, "married_filing_jointly").Equal(decimal.NewFromInt(760)))
+}
+
+func TestItemizedDeductionSelection(t *testing.T) {
+	calculator := NewComprehensiveTaxCalculator()
+
+	// SALT is capped at 10,000: 10,000 + 15,000 + 8,000 = 33,000 beats the 30,000 standard deduction
+	itemizing := domain.TaxableIncome{
+		FERSPension:             decimal.NewFromInt(150000),
+		SALTPaid:                decimal.NewFromInt(25000),
+		MortgageInterest:        decimal.NewFromInt(15000),
+		CharitableContributions: decimal.NewFromInt(8000),
+	}
+	result := calculator.calculateFederalTaxDetailed(itemizing, "married_filing_jointly", 0)
+	assert.True(t, result.Itemized)
+	assert.True(t, result.ItemizedDeduction.Equal(decimal.NewFromInt(33000)), "got %s", result.ItemizedDeduction)
+	assert.True(t, result.TaxableIncome.Equal(decimal.NewFromInt(117000)), "got %s", result.TaxableIncome)
+
+	// Small itemized totals fall back to the standard deduction
+	standard := itemizing
+	standard.MortgageInterest = decimal.Zero
+	result = calculator.calculateFederalTaxDetailed(standard, "married_filing_jointly", 0)
+	assert.False(t, result.Itemized)
+	assert.True(t, result.TaxableIncome.Equal(decimal.NewFromInt(120000)), "got %s", result.TaxableIncome)
+	assert.True(t, result.Tax.Equal(calculator.calculateFederalTaxWithStatus(standard, "married_filing_jointly", 0)))
+}
+
+func TestDeductionElection(t *testing.T) {
+	calculator := NewComprehensiveTaxCalculator()
+	income := domain.TaxableIncome{
+		FERSPension:             decimal.NewFromInt(150000),
+		SALTPaid:                decimal.NewFromInt(25000),
+		CharitableContributions: decimal.NewFromInt(8000),
+	}
+
+	// Itemizing (18,000) loses to the standard deduction unless elected, e.g. in a bunching year
+	result := calculator.calculateFederalTaxElected(income, "married_filing_jointly", 0, domain.DeductionItemized)
+	assert.True(t, result.Itemized)
+	assert.True(t, result.TaxableIncome.Equal(decimal.NewFromInt(132000)), "got %s", result.TaxableIncome)
+
+	income.MortgageInterest = decimal.NewFromInt(15000)
+	result = calculator.calculateFederalTaxElected(income, "married_filing_jointly", 0, domain.DeductionStandard)
+	assert.False(t, result.Itemized)
+	assert.True(t, result.TaxableIncome.Equal(decimal.NewFromInt(120000)), "got %s", result.TaxableIncome)
+}
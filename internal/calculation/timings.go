@@ -0,0 +1,98 @@
+package calculation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// Subsystem identifies the part of a scenario run a timing covers
+type Subsystem int
+
+const (
+	TimingTotal      Subsystem = iota // the whole scenario run, including its summary
+	TimingProjection                  // year-by-year projection, including taxes and healthcare
+	TimingTaxes                       // federal, state, local and FICA taxes within the projection
+	TimingHealthcare                  // FEHB, Medicare and marketplace costs within the projection
+)
+
+// ScenarioTiming is the time spent running one scenario. Taxes and healthcare are
+// measured inside the projection and are part of its time; repeated projections of the
+// same scenario, such as during break-even searches, accumulate.
+type ScenarioTiming struct {
+	Scenario   string        `json:"scenario"`
+	Total      time.Duration `json:"total"`
+	Projection time.Duration `json:"projection"`
+	Taxes      time.Duration `json:"taxes"`
+	Healthcare time.Duration `json:"healthcare"`
+}
+
+// field returns the duration a subsystem accumulates into
+func (st *ScenarioTiming) field(s Subsystem) *time.Duration {
+	switch s {
+	case TimingProjection:
+		return &st.Projection
+	case TimingTaxes:
+		return &st.Taxes
+	case TimingHealthcare:
+		return &st.Healthcare
+	default:
+		return &st.Total
+	}
+}
+
+// TimingRecorder accumulates scenario timings and is safe for concurrent use. A nil
+// recorder records nothing.
+type TimingRecorder struct {
+	mu        sync.Mutex
+	scenarios []*ScenarioTiming
+	byName    map[string]*ScenarioTiming
+}
+
+// NewTimingRecorder creates an empty timing recorder
+func NewTimingRecorder() *TimingRecorder {
+	return &TimingRecorder{byName: make(map[string]*ScenarioTiming)}
+}
+
+// Start begins timing a subsystem of scenario and returns the function that stops it
+func (tr *TimingRecorder) Start(scenario string, s Subsystem) func() {
+	if tr == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		st, ok := tr.byName[scenario]
+		if !ok {
+			st = &ScenarioTiming{Scenario: scenario}
+			tr.byName[scenario] = st
+			tr.scenarios = append(tr.scenarios, st)
+		}
+		*st.field(s) += elapsed
+	}
+}
+
+// Timings returns the recorded timings in the order scenarios were first timed
+func (tr *TimingRecorder) Timings() []ScenarioTiming {
+	if tr == nil {
+		return nil
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	timings := make([]ScenarioTiming, len(tr.scenarios))
+	for i, st := range tr.scenarios {
+		timings[i] = *st
+	}
+	return timings
+}
+
+// startTiming times a subsystem of scenario when the engine records timings
+func (ce *CalculationEngine) startTiming(scenario *domain.GenericScenario, s Subsystem) func() {
+	if ce == nil || scenario == nil {
+		return func() {}
+	}
+	return ce.Timings.Start(scenario.Name, s)
+}
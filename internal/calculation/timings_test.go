@@ -0,0 +1,52 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimingRecorder(t *testing.T) {
+	var disabled *TimingRecorder
+	disabled.Start("A", TimingTotal)()
+	assert.Nil(t, disabled.Timings())
+
+	tr := NewTimingRecorder()
+	tr.Start("B", TimingTaxes)()
+	stop := tr.Start("A", TimingTotal)
+	time.Sleep(time.Millisecond)
+	stop()
+	tr.Start("B", TimingTaxes)()
+
+	timings := tr.Timings()
+	require.Len(t, timings, 2)
+	assert.Equal(t, "B", timings[0].Scenario)
+	assert.Equal(t, "A", timings[1].Scenario)
+	assert.GreaterOrEqual(t, timings[1].Total, time.Millisecond)
+	assert.Zero(t, timings[0].Total)
+}
+
+func TestProjectionRecordsTimings(t *testing.T) {
+	salary := decimal.NewFromInt(90000)
+	household := &domain.Household{
+		FilingStatus: "single",
+		Participants: []domain.Participant{{Name: "Pat", BirthDate: time.Date(1965, 3, 1, 0, 0, 0, 0, time.UTC), CurrentSalary: &salary}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 3, InflationRate: decimal.NewFromFloat(0.025)}
+	scenario := &domain.GenericScenario{Name: "Working"}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	ce.Timings = NewTimingRecorder()
+
+	require.Len(t, ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules), 3)
+
+	timings := ce.Timings.Timings()
+	require.Len(t, timings, 1)
+	assert.Equal(t, "Working", timings[0].Scenario)
+	assert.Positive(t, timings[0].Projection)
+	assert.GreaterOrEqual(t, timings[0].Projection, timings[0].Taxes+timings[0].Healthcare)
+}
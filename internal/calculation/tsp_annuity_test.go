@@ -0,0 +1,74 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTSPAnnuityPayoutRate(t *testing.T) {
+	assert.Equal(t, "0.069", TSPAnnuityPayoutRate(60, decimal.Zero, false).String())
+	assert.Equal(t, "0.0738", TSPAnnuityPayoutRate(63, decimal.Zero, false).String())
+	assert.Equal(t, "0.058", TSPAnnuityPayoutRate(45, decimal.Zero, false).String())
+	assert.Equal(t, "0.16", TSPAnnuityPayoutRate(90, decimal.Zero, false).String())
+
+	// Survivor benefits and increasing payments lower the first year's payments
+	single := TSPAnnuityPayoutRate(65, decimal.Zero, false)
+	half := TSPAnnuityPayoutRate(65, decimal.NewFromFloat(0.5), false)
+	full := TSPAnnuityPayoutRate(65, decimal.NewFromInt(1), false)
+	increasing := TSPAnnuityPayoutRate(65, decimal.Zero, true)
+	assert.True(t, single.GreaterThan(half) && half.GreaterThan(full))
+	assert.True(t, increasing.LessThan(single))
+}
+
+func TestProjectionTSPAnnuityPurchase(t *testing.T) {
+	balance := func(v int64) *decimal.Decimal { d := decimal.NewFromInt(v); return &d }
+	household := &domain.Household{
+		FilingStatus: "married_filing_jointly",
+		Participants: []domain.Participant{
+			{Name: "Pat", BirthDate: time.Date(1962, 3, 1, 0, 0, 0, 0, time.UTC), TSPBalanceTraditional: balance(400000), TSPBalanceRoth: balance(100000)},
+			{Name: "Sam", BirthDate: time.Date(1963, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	assumptions := &domain.GlobalAssumptions{
+		ProjectionYears:         5,
+		InflationRate:           decimal.NewFromFloat(0.025),
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.05),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.05),
+	}
+	retire := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	death := time.Date(2028, 1, 15, 0, 0, 0, 0, time.UTC)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Pat": {ParticipantName: "Pat", RetirementDate: &retire, SSStartAge: 67, TSPAnnuity: &domain.TSPAnnuityPurchase{
+				Percentage:         decimal.NewFromFloat(0.5),
+				SurvivorPercent:    decimal.NewFromInt(1),
+				IncreasingPayments: true,
+			}},
+		},
+		Mortality: &domain.GenericScenarioMortality{Participants: map[string]*domain.MortalitySpec{"Pat": {DeathDate: &death}}},
+	}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 5)
+
+	// Half the balance buys a joint, increasing annuity at 63 paying from July
+	annual := decimal.NewFromInt(250000).Mul(TSPAnnuityPayoutRate(63, decimal.NewFromInt(1), true))
+	assert.Equal(t, "250000.00", projection[0].TSPAnnuityPurchases.StringFixed(2))
+	assert.Equal(t, annual.Div(decimal.NewFromInt(2)).StringFixed(2), projection[0].TSPAnnuities.Get("Pat").StringFixed(2))
+	assert.True(t, projection[0].GuaranteedIncome.Equal(projection[0].TSPAnnuities.Get("Pat")))
+
+	// Payments rise with inflation and continue in full to Sam after Pat's death
+	annual = annual.Mul(decimal.NewFromFloat(1.025))
+	assert.Equal(t, annual.StringFixed(2), projection[1].TSPAnnuities.Get("Pat").StringFixed(2))
+	annual = annual.Mul(decimal.NewFromFloat(1.025)).Mul(decimal.NewFromFloat(1.025))
+	assert.True(t, projection[3].TSPAnnuities.Get("Pat").IsZero())
+	assert.Equal(t, annual.StringFixed(2), projection[3].TSPAnnuities.Get("Sam").StringFixed(2))
+	assert.True(t, projection[3].FederalTax.IsPositive())
+}
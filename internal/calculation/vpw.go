@@ -0,0 +1,37 @@
+package calculation
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// Bogleheads VPW table assumptions: expected real returns of a global stock and bond
+// portfolio and the age the withdrawals are planned to last to
+var (
+	VPWDefaultStockAllocation = decimal.NewFromFloat(0.60)
+	vpwStockReturn            = 0.05
+	vpwBondReturn             = 0.019
+)
+
+const vpwFinalAge = 100
+
+// VPWPercentage returns the share of the balance withdrawn at age under the Bogleheads
+// Variable Percentage Withdrawal table for a portfolio holding stockAllocation in
+// stocks. The table amortizes the balance to age 100 at the allocation's expected real
+// return with payments at the start of each year, so the percentage rises with age, e.g.
+// 5.0% at 65 and 6.9% at 80 for 60% stocks. From 99 the whole balance is withdrawn.
+func VPWPercentage(age int, stockAllocation decimal.Decimal) decimal.Decimal {
+	years := vpwFinalAge - age
+	if years <= 1 {
+		return decimalOne
+	}
+	stocks := stockAllocation.InexactFloat64()
+	rate := stocks*vpwStockReturn + (1-stocks)*vpwBondReturn
+	n := float64(years)
+	if rate <= 0 {
+		return decimal.NewFromFloat(1 / n).Round(4)
+	}
+	annuityDue := (1 - math.Pow(1+rate, -n)) / rate * (1 + rate)
+	return decimal.NewFromFloat(1 / annuityDue).Round(4)
+}
@@ -0,0 +1,49 @@
+package calculation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVPWPercentage(t *testing.T) {
+	balanced := decimal.NewFromFloat(0.60)
+	// Bogleheads table values for 60% stocks, rising with age
+	assert.Equal(t, "0.05", VPWPercentage(65, balanced).String())
+	assert.Equal(t, "0.0694", VPWPercentage(80, balanced).String())
+	assert.Equal(t, "0.5092", VPWPercentage(98, balanced).String())
+	assert.Equal(t, "1", VPWPercentage(99, balanced).String())
+	assert.Equal(t, "1", VPWPercentage(103, balanced).String())
+
+	// More stocks assume a higher return and withdraw more
+	assert.True(t, VPWPercentage(65, decimal.NewFromInt(1)).GreaterThan(VPWPercentage(65, decimal.Zero)))
+}
+
+func TestVPWStrategyInProjection(t *testing.T) {
+	household := cashFlowEventHousehold()
+	balance := decimal.NewFromInt(600000)
+	household.Participants[0].TSPBalanceTraditional = &balance
+	retire := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	allocation := decimal.NewFromFloat(0.60)
+	scenario := &domain.GenericScenario{
+		ParticipantScenarios: map[string]domain.ParticipantScenario{"Gail": {
+			ParticipantName: "Gail", RetirementDate: &retire, SSStartAge: 70,
+			TSPWithdrawalStrategy: "vpw", VPWStockAllocation: &allocation,
+		}},
+	}
+	assumptions := &domain.GlobalAssumptions{ProjectionYears: 2}
+	ce := NewCalculationEngine()
+	ce.ProjectionCache = nil
+	projection := ce.GenerateAnnualProjectionGeneric(household, scenario, assumptions, assumptions.FederalRules)
+	require.Len(t, projection, 2)
+
+	// Gail is 66 at the start of 2025, then 67
+	first := projection[0].TSPWithdrawals.Get("Gail")
+	assert.True(t, first.Equal(balance.Mul(VPWPercentage(66, allocation))), first.String())
+	second := projection[1].TSPWithdrawals.Get("Gail")
+	assert.True(t, second.Equal(balance.Sub(first).Mul(VPWPercentage(67, allocation))), second.String())
+}
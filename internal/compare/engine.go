@@ -72,7 +72,7 @@ func (ce *CompareEngine) Compare(
 		}
 
 		// Apply template to create modified scenario
-		modifiedScenario, err := transform.ApplyTemplate(baseScenario, template)
+		modifiedScenario, changes, err := transform.ApplyTemplateWithChanges(baseScenario, template)
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply template %s: %w", templateName, err)
 		}
@@ -89,6 +89,7 @@ func (ce *CompareEngine) Compare(
 		// Calculate metrics and comparison
 		altResult := ce.MetricsCalculator.CalculateMetrics(altSummary)
 		altResult.Description = template.Description
+		altResult.Changes = changes
 		altResult = ce.MetricsCalculator.CalculateComparison(altResult, baseResult)
 
 		alternatives = append(alternatives, altResult)
@@ -116,9 +117,11 @@ func (ce *CompareEngine) CompareScenarios(
 ) (*ComparisonSet, error) {
 
 	// Find and calculate base scenario
+	var baseScenario *domain.GenericScenario
 	var baseSummary *domain.ScenarioSummary
 	for i := range config.Scenarios {
 		if config.Scenarios[i].Name == baseScenarioName {
+			baseScenario = &config.Scenarios[i]
 			summary, err := ce.CalcEngine.RunGenericScenario(ctx, config, &config.Scenarios[i])
 			if err != nil {
 				return nil, fmt.Errorf("failed to calculate base scenario: %w", err)
@@ -138,9 +141,11 @@ func (ce *CompareEngine) CompareScenarios(
 	alternatives := []ComparisonResult{}
 
 	for _, altName := range alternativeScenarioNames {
+		var altScenario *domain.GenericScenario
 		var altSummary *domain.ScenarioSummary
 		for i := range config.Scenarios {
 			if config.Scenarios[i].Name == altName {
+				altScenario = &config.Scenarios[i]
 				summary, err := ce.CalcEngine.RunGenericScenario(ctx, config, &config.Scenarios[i])
 				if err != nil {
 					return nil, fmt.Errorf("failed to calculate scenario %s: %w", altName, err)
@@ -154,7 +159,13 @@ func (ce *CompareEngine) CompareScenarios(
 			return nil, fmt.Errorf("alternative scenario %s not found", altName)
 		}
 
+		changes, err := transform.DiffScenarios(baseScenario, altScenario)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff scenario %s: %w", altName, err)
+		}
+
 		altResult := ce.MetricsCalculator.CalculateMetrics(altSummary)
+		altResult.Changes = changes
 		altResult = ce.MetricsCalculator.CalculateComparison(altResult, baseResult)
 
 		alternatives = append(alternatives, altResult)
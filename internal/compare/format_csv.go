@@ -4,6 +4,8 @@ import (
 	"encoding/csv"
 	"fmt"
 	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
 )
 
 // CSVFormatter formats comparison results as CSV
@@ -19,6 +21,7 @@ func (cf *CSVFormatter) Format(compSet *ComparisonSet) (string, error) {
 		"Scenario",
 		"Type",
 		"First Year Income",
+		"First Full Year Income",
 		"Lifetime Income",
 		"TSP Longevity (Years)",
 		"Final TSP Balance",
@@ -57,15 +60,16 @@ func (cf *CSVFormatter) formatRow(result *ComparisonResult, scenarioType string)
 	return []string{
 		result.ScenarioName,
 		scenarioType,
-		result.FirstYearNetIncome.StringFixed(2),
-		result.LifetimeIncome.StringFixed(2),
+		domain.FormatAmount(result.FirstYearNetIncome),
+		domain.FormatAmount(result.FirstFullYearNetIncome),
+		domain.FormatAmount(result.LifetimeIncome),
 		formatInt(result.TSPLongevity),
-		result.FinalTSPBalance.StringFixed(2),
-		result.LifetimeTaxes.StringFixed(2),
-		result.IncomeDiffFromBase.StringFixed(2),
+		domain.FormatAmount(result.FinalTSPBalance),
+		domain.FormatAmount(result.LifetimeTaxes),
+		domain.FormatAmount(result.IncomeDiffFromBase),
 		result.IncomePctFromBase.StringFixed(2),
 		formatInt(result.TSPLongevityDiff),
-		result.TaxDiffFromBase.StringFixed(2),
+		domain.FormatAmount(result.TaxDiffFromBase),
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 )
 
@@ -60,10 +61,10 @@ func (tf *TableFormatter) Format(compSet *ComparisonSet) string {
 
 			// Income difference
 			incomeSymbol := tf.deltaSymbol(alt.IncomeDiffFromBase)
-			sb.WriteString(fmt.Sprintf("  Lifetime Income:  %s$%s (%s%%)\n",
+			sb.WriteString(fmt.Sprintf("  Lifetime Income:  %s$%s (%s)\n",
 				incomeSymbol,
 				tf.formatDecimal(alt.IncomeDiffFromBase.Abs()),
-				alt.IncomePctFromBase.StringFixed(1)))
+				domain.FormatPercent(alt.IncomePctFromBase)))
 
 			// TSP longevity difference
 			if alt.TSPLongevityDiff != 0 {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/rgehrsitz/rpgo/internal/transform"
 	"github.com/shopspring/decimal"
 )
 
@@ -20,6 +21,9 @@ type ComparisonResult struct {
 	FinalTSPBalance    decimal.Decimal `json:"finalTSPBalance"`
 	LifetimeTaxes      decimal.Decimal `json:"lifetimeTaxes"`
 
+	// Net income in the first year spent fully retired, past any transition year
+	FirstFullYearNetIncome decimal.Decimal `json:"firstFullYearNetIncome"`
+
 	// Comparison to Base
 	IncomeDiffFromBase decimal.Decimal `json:"incomeDiffFromBase"`
 	IncomePctFromBase  decimal.Decimal `json:"incomePctFromBase"`
@@ -31,6 +35,9 @@ type ComparisonResult struct {
 	SSClaimAge            int    `json:"ssClaimAge,omitempty"`
 	TSPWithdrawalStrategy string `json:"tspWithdrawalStrategy,omitempty"`
 	TSPWithdrawalRate     string `json:"tspWithdrawalRate,omitempty"`
+
+	// Changes lists the parameter deltas this scenario applies to the base
+	Changes []transform.ParameterChange `json:"changes,omitempty"`
 }
 
 // ComparisonSet represents a collection of scenario comparisons
@@ -96,6 +103,8 @@ func (mc *MetricsCalculator) CalculateMetrics(summary *domain.ScenarioSummary) C
 		TSPLongevity:       summary.TSPLongevity,
 		FinalTSPBalance:    summary.FinalTSPBalance,
 		LifetimeTaxes:      mc.calculateLifetimeTaxes(summary),
+
+		FirstFullYearNetIncome: summary.FirstFullYearNetIncome,
 	}
 
 	// Extract scenario specifics from first projection year
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDataDirName is the repository-relative data directory used by development checkouts.
+const DefaultDataDirName = "data"
+
+// DataPathCandidates lists the standard directories searched for historical data,
+// in priority order: ./data, $XDG_DATA_HOME/rpgo (default ~/.local/share/rpgo),
+// each $XDG_DATA_DIRS entry + /rpgo (default /usr/local/share:/usr/share), and a
+// data directory next to the executable.
+func DataPathCandidates() []string {
+	candidates := []string{DefaultDataDirName}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+	if dataHome != "" {
+		candidates = append(candidates, filepath.Join(dataHome, "rpgo"))
+	}
+
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range strings.Split(dataDirs, string(os.PathListSeparator)) {
+		if dir != "" {
+			candidates = append(candidates, filepath.Join(dir, "rpgo"))
+		}
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), DefaultDataDirName))
+	}
+	return candidates
+}
+
+// ResolveDataPath picks the historical data directory. An explicit flag value wins,
+// then the configuration's historical_data_path (relative to configFile), then the
+// first existing standard location. Explicit paths that do not exist are an error;
+// an empty result with no error means no data directory was found.
+func ResolveDataPath(flagPath, configuredPath, configFile string) (string, error) {
+	if flagPath != "" {
+		if !isDir(flagPath) {
+			return "", fmt.Errorf("data path %q does not exist or is not a directory", flagPath)
+		}
+		return flagPath, nil
+	}
+
+	if configuredPath != "" {
+		path := configuredPath
+		if !filepath.IsAbs(path) && configFile != "" {
+			path = filepath.Join(filepath.Dir(configFile), path)
+		}
+		if !isDir(path) {
+			return "", fmt.Errorf("historical_data_path %q does not exist or is not a directory", path)
+		}
+		return path, nil
+	}
+
+	for _, candidate := range DataPathCandidates() {
+		if isDir(candidate) {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveDataPath(t *testing.T) {
+	root := t.TempDir()
+	explicit := filepath.Join(root, "explicit")
+	relative := filepath.Join(root, "plans", "hist")
+	xdgHome := filepath.Join(root, "xdg")
+	for _, dir := range []string{explicit, relative, filepath.Join(xdgHome, "rpgo")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configFile := filepath.Join(root, "plans", "config.yaml")
+
+	// Run from an empty directory so ./data does not exist
+	wd, _ := os.Getwd()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("XDG_DATA_HOME", xdgHome)
+	t.Setenv("XDG_DATA_DIRS", filepath.Join(root, "none"))
+
+	got, err := ResolveDataPath(explicit, "hist", configFile)
+	if err != nil || got != explicit {
+		t.Errorf("flag path: got %q, %v; want %q", got, err, explicit)
+	}
+
+	got, err = ResolveDataPath("", "hist", configFile)
+	if err != nil || got != relative {
+		t.Errorf("configured path: got %q, %v; want %q", got, err, relative)
+	}
+
+	got, err = ResolveDataPath("", "", configFile)
+	if want := filepath.Join(xdgHome, "rpgo"); err != nil || got != want {
+		t.Errorf("XDG fallback: got %q, %v; want %q", got, err, want)
+	}
+
+	if _, err := ResolveDataPath(filepath.Join(root, "missing"), "", ""); err == nil {
+		t.Error("expected error for missing flag path")
+	}
+	if _, err := ResolveDataPath("", "missing", configFile); err == nil {
+		t.Error("expected error for missing configured path")
+	}
+}
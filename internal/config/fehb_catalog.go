@@ -0,0 +1,86 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed fehb_plans.yaml
+var bundledFEHBPlans []byte
+
+// LoadFEHBPlanCatalog reads an FEHB plan catalog file, or the bundled catalog when path is
+// empty
+func LoadFEHBPlanCatalog(path string) (*domain.FEHBPlanCatalog, error) {
+	data := bundledFEHBPlans
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read FEHB plan catalog %s: %w", path, err)
+		}
+	}
+	var catalog domain.FEHBPlanCatalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse FEHB plan catalog: %w", err)
+	}
+	for _, p := range catalog.Plans {
+		switch p.Enrollment {
+		case domain.FEHBEnrollmentSelfOnly, domain.FEHBEnrollmentSelfPlusOne, domain.FEHBEnrollmentSelfAndFamily:
+		default:
+			return nil, fmt.Errorf("FEHB plan %s: enrollment must be self_only, self_plus_one or self_and_family", p.Code)
+		}
+		if p.BiweeklyPremium.IsNegative() || p.PremiumGrowth.IsNegative() {
+			return nil, fmt.Errorf("FEHB plan %s: premium and premium growth cannot be negative", p.Code)
+		}
+	}
+	return &catalog, nil
+}
+
+// resolveFEHBPlans fills in the premiums of participants who name an FEHB plan from the
+// plan catalog: the biweekly premium, the self only premium of the same plan and the
+// plan's premium growth, each unless the participant gives it. The catalog is the
+// configuration's fehb_plan_catalog, relative to configFile, or the bundled one.
+func resolveFEHBPlans(config *domain.Configuration, configFile string) error {
+	if config.Household == nil {
+		return nil
+	}
+	var catalog *domain.FEHBPlanCatalog
+	for i := range config.Household.Participants {
+		p := &config.Household.Participants[i]
+		if p.FEHBPlan == "" {
+			continue
+		}
+		if catalog == nil {
+			path := config.GlobalAssumptions.FEHBPlanCatalog
+			if path != "" && !filepath.IsAbs(path) && configFile != "" {
+				path = filepath.Join(filepath.Dir(configFile), path)
+			}
+			var err error
+			if catalog, err = LoadFEHBPlanCatalog(path); err != nil {
+				return err
+			}
+		}
+
+		plan, ok := catalog.Lookup(p.FEHBPlan)
+		if !ok {
+			return fmt.Errorf("participant %s: FEHB plan %q is not in the %d plan catalog", p.Name, p.FEHBPlan, catalog.PlanYear)
+		}
+		if p.FEHBPremiumPerPayPeriod == nil {
+			premium := plan.BiweeklyPremium
+			p.FEHBPremiumPerPayPeriod = &premium
+		}
+		if selfOnly, ok := catalog.SelfOnly(plan); ok && p.FEHBSelfOnlyPremiumPerPayPeriod == nil && plan.Enrollment != domain.FEHBEnrollmentSelfOnly {
+			premium := selfOnly.BiweeklyPremium
+			p.FEHBSelfOnlyPremiumPerPayPeriod = &premium
+		}
+		if p.FEHBPremiumGrowth == nil {
+			growth := plan.PremiumGrowth
+			p.FEHBPremiumGrowth = &growth
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFEHBPlanCatalog_Bundled(t *testing.T) {
+	catalog, err := LoadFEHBPlanCatalog("")
+	require.NoError(t, err)
+	assert.Equal(t, 2025, catalog.PlanYear)
+
+	byName, ok := catalog.Lookup("blue cross basic self+1")
+	require.True(t, ok)
+	byCode, ok := catalog.Lookup("113")
+	require.True(t, ok)
+	assert.Equal(t, byName, byCode)
+	assert.Equal(t, domain.FEHBEnrollmentSelfPlusOne, byName.Enrollment)
+
+	selfOnly, ok := catalog.SelfOnly(byName)
+	require.True(t, ok)
+	assert.Equal(t, "111", selfOnly.Code)
+
+	_, ok = catalog.Lookup("Blue Cross Platinum Self Only")
+	assert.False(t, ok)
+}
+
+func TestResolveFEHBPlans(t *testing.T) {
+	dir := t.TempDir()
+	catalog := `plan_year: 2026
+plans:
+  - { code: "901", plan: "Local HMO", enrollment: self_only, biweekly_premium: 90, premium_growth: 0.05 }
+  - { code: "903", plan: "Local HMO", enrollment: self_plus_one, biweekly_premium: 200, premium_growth: 0.05 }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plans.yaml"), []byte(catalog), 0o644))
+
+	premium := decimal.NewFromInt(250)
+	config := &domain.Configuration{
+		Household: &domain.Household{Participants: []domain.Participant{
+			{Name: "Pat", FEHBPlan: "Local HMO Self+1"},
+			{Name: "Sam", FEHBPlan: "901", FEHBPremiumPerPayPeriod: &premium},
+		}},
+		GlobalAssumptions: domain.GlobalAssumptions{FEHBPlanCatalog: "plans.yaml"},
+	}
+	require.NoError(t, resolveFEHBPlans(config, filepath.Join(dir, "config.yaml")))
+
+	// The catalog fills in the premiums and growth the participant leaves out
+	pat := config.Household.Participants[0]
+	assert.Equal(t, "200", pat.FEHBPremiumPerPayPeriod.String())
+	assert.Equal(t, "90", pat.FEHBSelfOnlyPremiumPerPayPeriod.String())
+	assert.Equal(t, "0.05", pat.FEHBPremiumGrowth.String())
+
+	// A premium given in the configuration wins
+	sam := config.Household.Participants[1]
+	assert.Equal(t, "250", sam.FEHBPremiumPerPayPeriod.String())
+	assert.Nil(t, sam.FEHBSelfOnlyPremiumPerPayPeriod)
+
+	config.Household.Participants[0].FEHBPlan = "Local PPO Self Only"
+	err := resolveFEHBPlans(config, filepath.Join(dir, "config.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in the 2026 plan catalog")
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatJSON, DetectFormat("plan.json", nil))
+	assert.Equal(t, FormatYAML, DetectFormat("plan.YML", []byte("{}")))
+	assert.Equal(t, FormatJSON, DetectFormat("", []byte("  \n{\"household\": {}}")))
+	assert.Equal(t, FormatYAML, DetectFormat("plan.cfg", []byte("household:\n")))
+}
+
+func TestParseFormat_JSONRoundTrip(t *testing.T) {
+	parser := NewInputParser()
+	original, err := parser.LoadFromFile("../../test/testdata/generic_example_config.yaml")
+	require.NoError(t, err)
+
+	jsonData, err := EncodeConfiguration(original, FormatJSON)
+	require.NoError(t, err)
+	fromJSON, err := parser.Parse(jsonData)
+	require.NoError(t, err)
+
+	yamlData, err := EncodeConfiguration(fromJSON, FormatYAML)
+	require.NoError(t, err)
+	fromYAML, err := parser.ParseFormat(yamlData, FormatYAML)
+	require.NoError(t, err)
+
+	// Compare encodings rather than structs; decimals differ in internal representation only
+	fromJSONData, err := EncodeConfiguration(fromJSON, FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(jsonData), string(fromJSONData))
+
+	fromYAMLData, err := EncodeConfiguration(fromYAML, FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, string(yamlData), string(fromYAMLData))
+	assert.Equal(t, original.Scenarios[0].Name, fromYAML.Scenarios[0].Name)
+	assert.True(t, original.Household.Participants[0].CurrentSalary.Equal(*fromYAML.Household.Participants[0].CurrentSalary))
+}
+
+func TestParseFormat_JSONRejectsUnknownFields(t *testing.T) {
+	_, err := NewInputParser().ParseFormat([]byte(`{"household": {"filing_status": "single", "participants": []}, "bogus": 1}`), FormatJSON)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse JSON")
+}
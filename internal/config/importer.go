@@ -0,0 +1,425 @@
+package config
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnsupportedImportFormat is returned when no importer is registered for a format
+var ErrUnsupportedImportFormat = errors.New("unsupported import format")
+
+// Importer converts data exported from another planning tool into a Configuration
+type Importer interface {
+	Import(r io.Reader) (*domain.Configuration, error)
+}
+
+// GetImporter returns the importer registered for the given format name
+func GetImporter(format string) (Importer, error) {
+	switch strings.ToLower(format) {
+	case "csv", "":
+		return NewCSVImporter(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s (valid: csv)", ErrUnsupportedImportFormat, format)
+	}
+}
+
+// ImportFromFile imports a configuration from a file using the named format and validates it
+func (ip *InputParser) ImportFromFile(filename, format string) (*domain.Configuration, error) {
+	importer, err := GetImporter(format)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	config, err := importer.Import(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import %s: %w", filename, err)
+	}
+
+	if err := ip.ValidateConfiguration(config); err != nil {
+		return nil, fmt.Errorf("imported configuration validation failed: %w", err)
+	}
+	ip.normalizeConfiguration(config)
+
+	return config, nil
+}
+
+// CSVImporter reads a generic long-form CSV of accounts and income streams.
+//
+// Each row is "section,name,field,value" where section is one of:
+//   - household:   filing_status
+//   - participant: any participant field (birth_date, current_salary, tsp_balance_traditional, ...)
+//   - assumptions: inflation_rate, cola_general_rate, projection_years, state, ...
+//   - scenario:    "<participant>.<field>" for retirement_date, ss_start_age, tsp_withdrawal_*
+//
+// A header row and blank or '#'-prefixed rows are ignored. Missing global
+// assumptions are filled with the same defaults the example configs use, and a
+// single "Imported" scenario is created when the file does not define one.
+type CSVImporter struct{}
+
+// NewCSVImporter creates a new CSV importer
+func NewCSVImporter() *CSVImporter {
+	return &CSVImporter{}
+}
+
+// Import parses the CSV data into a Configuration
+func (ci *CSVImporter) Import(r io.Reader) (*domain.Configuration, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	config := &domain.Configuration{
+		Household:         &domain.Household{FilingStatus: "single"},
+		GlobalAssumptions: defaultImportAssumptions(),
+	}
+	participants := map[string]*domain.Participant{}
+	var participantOrder []string
+	scenarios := map[string]*domain.GenericScenario{}
+	var scenarioOrder []string
+
+	for i, rec := range records {
+		line := i + 1
+		if len(rec) == 0 || (len(rec) == 1 && strings.TrimSpace(rec[0]) == "") {
+			continue
+		}
+		if len(rec) < 4 {
+			return nil, fmt.Errorf("line %d: expected 4 columns (section,name,field,value), got %d", line, len(rec))
+		}
+		section := strings.ToLower(strings.TrimSpace(rec[0]))
+		name := strings.TrimSpace(rec[1])
+		rawField := strings.TrimSpace(rec[2])
+		field := strings.ToLower(rawField)
+		value := strings.TrimSpace(rec[3])
+
+		switch section {
+		case "section":
+			// header row
+			continue
+		case "household":
+			if err := setHouseholdField(config.Household, field, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		case "participant":
+			if name == "" {
+				return nil, fmt.Errorf("line %d: participant name is required", line)
+			}
+			p, ok := participants[name]
+			if !ok {
+				p = &domain.Participant{Name: name}
+				participants[name] = p
+				participantOrder = append(participantOrder, name)
+			}
+			if err := setParticipantField(p, field, value); err != nil {
+				return nil, fmt.Errorf("line %d: participant %s: %w", line, name, err)
+			}
+		case "assumptions":
+			if err := setAssumptionField(&config.GlobalAssumptions, field, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		case "scenario":
+			if name == "" {
+				return nil, fmt.Errorf("line %d: scenario name is required", line)
+			}
+			s, ok := scenarios[name]
+			if !ok {
+				s = &domain.GenericScenario{Name: name, ParticipantScenarios: map[string]domain.ParticipantScenario{}}
+				scenarios[name] = s
+				scenarioOrder = append(scenarioOrder, name)
+			}
+			if err := setScenarioField(s, rawField, value); err != nil {
+				return nil, fmt.Errorf("line %d: scenario %s: %w", line, name, err)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unknown section %q (valid: household, participant, assumptions, scenario)", line, rec[0])
+		}
+	}
+
+	if len(participantOrder) == 0 {
+		return nil, fmt.Errorf("no participants found in CSV")
+	}
+	for _, name := range participantOrder {
+		config.Household.Participants = append(config.Household.Participants, *participants[name])
+	}
+
+	if len(scenarioOrder) == 0 {
+		s := &domain.GenericScenario{Name: "Imported", ParticipantScenarios: map[string]domain.ParticipantScenario{}}
+		scenarios[s.Name] = s
+		scenarioOrder = append(scenarioOrder, s.Name)
+	}
+	for _, name := range scenarioOrder {
+		s := scenarios[name]
+		// Every participant needs a participant scenario; default SS claiming to 67
+		for _, pName := range participantOrder {
+			ps, ok := s.ParticipantScenarios[pName]
+			if !ok {
+				ps = domain.ParticipantScenario{}
+			}
+			ps.ParticipantName = pName
+			if ps.SSStartAge == 0 {
+				ps.SSStartAge = 67
+			}
+			s.ParticipantScenarios[pName] = ps
+		}
+		config.Scenarios = append(config.Scenarios, *s)
+	}
+
+	return config, nil
+}
+
+// defaultImportAssumptions returns the baseline assumptions used for imported plans
+func defaultImportAssumptions() domain.GlobalAssumptions {
+	return domain.GlobalAssumptions{
+		InflationRate:           decimal.NewFromFloat(0.025),
+		FEHBPremiumInflation:    decimal.NewFromFloat(0.065),
+		TSPReturnPreRetirement:  decimal.NewFromFloat(0.07),
+		TSPReturnPostRetirement: decimal.NewFromFloat(0.06),
+		COLAGeneralRate:         decimal.NewFromFloat(0.025),
+		ProjectionYears:         30,
+	}
+}
+
+func setHouseholdField(h *domain.Household, field, value string) error {
+	switch field {
+	case "filing_status":
+		if value != "single" && value != "married_filing_jointly" {
+			return fmt.Errorf("filing_status must be 'single' or 'married_filing_jointly'")
+		}
+		h.FilingStatus = value
+	default:
+		return fmt.Errorf("unknown household field %q", field)
+	}
+	return nil
+}
+
+func setParticipantField(p *domain.Participant, field, value string) error {
+	switch field {
+	case "birth_date":
+		d, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		p.BirthDate = d
+	case "hire_date":
+		d, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		p.HireDate = &d
+	case "employment_end_date":
+		d, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		p.EmploymentEndDate = &d
+	case "is_federal":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean for %s: %q", field, value)
+		}
+		p.IsFederal = b
+	case "is_primary_fehb_holder":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean for %s: %q", field, value)
+		}
+		p.IsPrimaryFEHBHolder = b
+	case "ss_benefit_fra", "ss_benefit_62", "ss_benefit_70":
+		d, err := parseImportDecimal(field, value)
+		if err != nil {
+			return err
+		}
+		switch field {
+		case "ss_benefit_fra":
+			p.SSBenefitFRA = d
+		case "ss_benefit_62":
+			p.SSBenefit62 = d
+		default:
+			p.SSBenefit70 = d
+		}
+	default:
+		target := participantDecimalField(p, field)
+		if target == nil {
+			return fmt.Errorf("unknown participant field %q", field)
+		}
+		d, err := parseImportDecimal(field, value)
+		if err != nil {
+			return err
+		}
+		*target = &d
+	}
+	return nil
+}
+
+// participantDecimalField maps a CSV field name to the participant's optional decimal field
+func participantDecimalField(p *domain.Participant, field string) **decimal.Decimal {
+	switch field {
+	case "current_salary":
+		return &p.CurrentSalary
+	case "high_3_salary":
+		return &p.High3Salary
+	case "tsp_balance_traditional":
+		return &p.TSPBalanceTraditional
+	case "tsp_balance_roth":
+		return &p.TSPBalanceRoth
+	case "tsp_contribution_percent":
+		return &p.TSPContributionPercent
+	case "taxable_account_balance":
+		return &p.TaxableAccountBalance
+	case "taxable_account_basis":
+		return &p.TaxableAccountBasis
+	case "ira_balance_traditional":
+		return &p.IRABalanceTraditional
+	case "ira_balance_roth":
+		return &p.IRABalanceRoth
+	case "ira_contribution_traditional":
+		return &p.IRAContributionTraditional
+	case "ira_contribution_roth":
+		return &p.IRAContributionRoth
+	case "tsp_roth_basis":
+		return &p.TSPRothBasis
+	case "ira_roth_basis":
+		return &p.IRARothBasis
+	case "fehb_premium_per_pay_period":
+		return &p.FEHBPremiumPerPayPeriod
+	case "survivor_benefit_election_percent":
+		return &p.SurvivorBenefitElectionPercent
+	case "sick_leave_hours":
+		return &p.SickLeaveHours
+	}
+	return nil
+}
+
+func setAssumptionField(ga *domain.GlobalAssumptions, field, value string) error {
+	switch field {
+	case "projection_years":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer for %s: %q", field, value)
+		}
+		ga.ProjectionYears = n
+		return nil
+	case "state":
+		ga.CurrentLocation.State = value
+		return nil
+	case "county":
+		ga.CurrentLocation.County = value
+		return nil
+	case "municipality":
+		ga.CurrentLocation.Municipality = value
+		return nil
+	case "tsp_contrib_policy":
+		ga.TSPContribPolicy = value
+		return nil
+	}
+
+	var target *decimal.Decimal
+	switch field {
+	case "inflation_rate":
+		target = &ga.InflationRate
+	case "fehb_premium_inflation":
+		target = &ga.FEHBPremiumInflation
+	case "tsp_return_pre_retirement":
+		target = &ga.TSPReturnPreRetirement
+	case "tsp_return_post_retirement":
+		target = &ga.TSPReturnPostRetirement
+	case "cola_general_rate":
+		target = &ga.COLAGeneralRate
+	default:
+		return fmt.Errorf("unknown assumptions field %q", field)
+	}
+	d, err := parseImportDecimal(field, value)
+	if err != nil {
+		return err
+	}
+	*target = d
+	return nil
+}
+
+func setScenarioField(s *domain.GenericScenario, field, value string) error {
+	// Participant names are case-sensitive, so only the field suffix is lowercased
+	idx := strings.LastIndex(field, ".")
+	if idx <= 0 || idx == len(field)-1 {
+		return fmt.Errorf("scenario field %q must be of the form <participant>.<field>", field)
+	}
+	pName := field[:idx]
+	attr := strings.ToLower(field[idx+1:])
+
+	ps := s.ParticipantScenarios[pName]
+	ps.ParticipantName = pName
+	switch attr {
+	case "retirement_date":
+		d, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		ps.RetirementDate = &d
+	case "ss_start_age":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer for %s: %q", attr, value)
+		}
+		ps.SSStartAge = n
+	case "tsp_withdrawal_strategy":
+		ps.TSPWithdrawalStrategy = value
+	case "tsp_withdrawal_target_monthly":
+		d, err := parseImportDecimal(attr, value)
+		if err != nil {
+			return err
+		}
+		ps.TSPWithdrawalTargetMonthly = &d
+	case "tsp_withdrawal_rate":
+		d, err := parseImportDecimal(attr, value)
+		if err != nil {
+			return err
+		}
+		ps.TSPWithdrawalRate = &d
+	default:
+		return fmt.Errorf("unknown scenario field %q", attr)
+	}
+	s.ParticipantScenarios[pName] = ps
+	return nil
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "01/02/2006"} {
+		if d, err := time.Parse(layout, value); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", value)
+}
+
+// parseImportDecimal accepts plain numbers as well as spreadsheet-style "$1,234" and "5%" values
+func parseImportDecimal(field, value string) (decimal.Decimal, error) {
+	cleaned := strings.NewReplacer("$", "", ",", "").Replace(value)
+	percent := strings.HasSuffix(cleaned, "%")
+	cleaned = strings.TrimSuffix(cleaned, "%")
+	d, err := decimal.NewFromString(strings.TrimSpace(cleaned))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid number for %s: %q", field, value)
+	}
+	if percent {
+		d = d.Div(decimal.NewFromInt(100))
+	}
+	return d, nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleImportCSV = `section,name,field,value
+household,,filing_status,married_filing_jointly
+participant,Alice,birth_date,1965-03-15
+participant,Alice,is_federal,true
+participant,Alice,hire_date,1990-06-01
+participant,Alice,current_salary,"$145,000"
+participant,Alice,high_3_salary,140000
+participant,Alice,tsp_balance_traditional,800000
+participant,Alice,tsp_balance_roth,100000
+participant,Alice,tsp_contribution_percent,15%
+participant,Alice,survivor_benefit_election_percent,0
+participant,Alice,ss_benefit_62,2100
+participant,Alice,ss_benefit_fra,3000
+participant,Alice,ss_benefit_70,3700
+# spouse is not a federal employee
+participant,Bob,birth_date,1966-01-10
+participant,Bob,ss_benefit_62,1400
+participant,Bob,ss_benefit_fra,2000
+participant,Bob,ss_benefit_70,2480
+assumptions,,state,Pennsylvania
+assumptions,,projection_years,25
+scenario,Base,Alice.retirement_date,2027-06-30
+scenario,Base,Alice.ss_start_age,62
+scenario,Base,Alice.tsp_withdrawal_strategy,4_percent_rule
+`
+
+func TestCSVImporter_Import(t *testing.T) {
+	cfg, err := NewCSVImporter().Import(strings.NewReader(sampleImportCSV))
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Household.Participants, 2)
+	assert.Equal(t, "married_filing_jointly", cfg.Household.FilingStatus)
+
+	alice := cfg.Household.Participants[0]
+	assert.Equal(t, "Alice", alice.Name)
+	assert.True(t, alice.IsFederal)
+	require.NotNil(t, alice.CurrentSalary)
+	assert.True(t, alice.CurrentSalary.Equal(decimal.NewFromInt(145000)))
+	require.NotNil(t, alice.TSPContributionPercent)
+	assert.True(t, alice.TSPContributionPercent.Equal(decimal.NewFromFloat(0.15)))
+
+	assert.Equal(t, 25, cfg.GlobalAssumptions.ProjectionYears)
+	require.Len(t, cfg.Scenarios, 1)
+	base := cfg.Scenarios[0]
+	assert.Equal(t, "Base", base.Name)
+	require.Contains(t, base.ParticipantScenarios, "Bob")
+	assert.Equal(t, 67, base.ParticipantScenarios["Bob"].SSStartAge)
+	assert.Equal(t, 62, base.ParticipantScenarios["Alice"].SSStartAge)
+	require.NotNil(t, base.ParticipantScenarios["Alice"].RetirementDate)
+
+	assert.NoError(t, NewInputParser().ValidateConfiguration(cfg))
+}
+
+func TestCSVImporter_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want string
+	}{
+		{"unknown section", "bogus,,x,1\n", "unknown section"},
+		{"short row", "participant,Alice,birth_date\n", "expected 4 columns"},
+		{"bad number", "participant,Alice,current_salary,lots\n", "invalid number"},
+		{"unknown field", "participant,Alice,favorite_color,blue\n", "unknown participant field"},
+		{"bad scenario field", "participant,Alice,birth_date,1965-01-01\nscenario,Base,retirement_date,2027-01-01\n", "<participant>.<field>"},
+		{"no participants", "assumptions,,state,Pennsylvania\n", "no participants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCSVImporter().Import(strings.NewReader(tt.csv))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestImportFromFile_RoundTripsThroughYAML(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "plan.csv")
+	require.NoError(t, os.WriteFile(src, []byte(sampleImportCSV), 0o644))
+
+	parser := NewInputParser()
+	cfg, err := parser.ImportFromFile(src, "csv")
+	require.NoError(t, err)
+
+	data, err := EncodeConfiguration(cfg, FormatYAML)
+	require.NoError(t, err)
+	dst := filepath.Join(dir, "plan.yaml")
+	require.NoError(t, os.WriteFile(dst, data, 0o644))
+
+	loaded, err := parser.LoadFromFile(dst)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Household.Participants, 2)
+	// Alice's zero survivor election is kept, and Bob gets no federal fields at all
+	assert.Equal(t, "0", loaded.Household.Participants[0].SurvivorBenefitElectionPercent.String())
+	assert.Nil(t, loaded.Household.Participants[1].TSPBalanceTraditional)
+	assert.NotContains(t, string(data), "null")
+
+	_, err = GetImporter("quicken")
+	assert.ErrorIs(t, err, ErrUnsupportedImportFormat)
+}
@@ -1,9 +1,16 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
@@ -11,13 +18,38 @@ import (
 )
 
 // InputParser handles parsing of input configuration files
-type InputParser struct{}
+type InputParser struct {
+	// Warnings from the last parse about values that are valid but look mistaken, such as
+	// a rate written as 4 instead of 0.04
+	Warnings []string
+}
 
 // NewInputParser creates a new input parser
 func NewInputParser() *InputParser {
 	return &InputParser{}
 }
 
+// Supported configuration encodings
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// DetectFormat chooses the configuration encoding from the file extension, falling
+// back to the content: a document whose first non-space character is '{' is JSON.
+func DetectFormat(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
 // LoadFromFile loads configuration from a YAML or JSON file
 func (ip *InputParser) LoadFromFile(filename string) (*domain.Configuration, error) {
 	data, err := os.ReadFile(filename)
@@ -25,9 +57,46 @@ func (ip *InputParser) LoadFromFile(filename string) (*domain.Configuration, err
 		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
 	}
 
+	return ip.parse(data, DetectFormat(filename, data), filename)
+}
+
+// Parse loads configuration from in-memory YAML or JSON, detected from the content
+func (ip *InputParser) Parse(data []byte) (*domain.Configuration, error) {
+	return ip.ParseFormat(data, DetectFormat("", data))
+}
+
+// ParseFormat loads configuration from in-memory data in the given encoding.
+// JSON documents use the configuration's json field names and reject unknown keys.
+func (ip *InputParser) ParseFormat(data []byte, format string) (*domain.Configuration, error) {
+	return ip.parse(data, format, "")
+}
+
+// parse loads configuration from data read from configFile, against which relative paths
+// in it resolve ("" for the working directory)
+func (ip *InputParser) parse(data []byte, format, configFile string) (*domain.Configuration, error) {
 	var config domain.Configuration
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", jsonPercentHint(err))
+		}
+	case FormatYAML:
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		normalizePercentScalars(&root, reflect.TypeOf(config))
+		if err := root.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported configuration format: %s", format)
+	}
+
+	if err := resolveFEHBPlans(&config, configFile); err != nil {
+		return nil, err
 	}
 
 	// Validate the configuration
@@ -37,10 +106,85 @@ func (ip *InputParser) LoadFromFile(filename string) (*domain.Configuration, err
 
 	// Ensure deterministic order for all maps in the configuration
 	ip.normalizeConfiguration(&config)
+	ip.Warnings = suspiciousRateWarnings(&config)
 
 	return &config, nil
 }
 
+// EncodeConfiguration serializes a configuration as YAML or JSON, using the same
+// field names ParseFormat reads so conversions round-trip.
+func EncodeConfiguration(config *domain.Configuration, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		return encodeYAMLConfiguration(config)
+	default:
+		return nil, fmt.Errorf("unsupported configuration format: %s", format)
+	}
+}
+
+// encodeYAMLConfiguration writes a configuration as YAML. The required federal TSP and
+// survivor fields are written even when zero, which yaml omitempty would otherwise drop
+// (decimal zero counts as empty), so the file loads back: a participant with a zero Roth
+// balance or no survivor election still has one.
+func encodeYAMLConfiguration(config *domain.Configuration) ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if config.Household != nil {
+		participants := mappingValue(mappingValue(&root, "household"), "participants")
+		for i, p := range config.Household.Participants {
+			if participants == nil || i >= len(participants.Content) || !p.IsFederal {
+				continue
+			}
+			node := participants.Content[i]
+			required := []struct {
+				key   string
+				value *decimal.Decimal
+			}{
+				{"tsp_balance_traditional", p.TSPBalanceTraditional},
+				{"tsp_balance_roth", p.TSPBalanceRoth},
+				{"tsp_contribution_percent", p.TSPContributionPercent},
+				{"survivor_benefit_election_percent", p.SurvivorBenefitElectionPercent},
+			}
+			for _, field := range required {
+				if field.value != nil && mappingValue(node, field.key) == nil {
+					node.Content = append(node.Content,
+						&yaml.Node{Kind: yaml.ScalarNode, Value: field.key},
+						&yaml.Node{Kind: yaml.ScalarNode, Value: field.value.String()})
+				}
+			}
+		}
+	}
+	data, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return data, nil
+}
+
+// mappingValue returns the value of key in a YAML mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // normalizeConfiguration ensures deterministic order for all maps in the configuration
 func (ip *InputParser) normalizeConfiguration(config *domain.Configuration) {
 	// Normalize scenario participant scenarios
@@ -85,6 +229,33 @@ func (ip *InputParser) ValidateConfiguration(config *domain.Configuration) error
 	if err := ip.validateGlobalAssumptions(&config.GlobalAssumptions); err != nil {
 		return fmt.Errorf("global assumptions validation failed: %w", err)
 	}
+	if config.Hooks != nil {
+		for i, hook := range config.Hooks.PostRun {
+			if err := validatePostRunHook(hook); err != nil {
+				return fmt.Errorf("post-run hook %d (%s) validation failed: %w", i, hook.Label(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePostRunHook checks a hook runs a command or calls an http(s) URL, not both
+func validatePostRunHook(hook domain.PostRunHook) error {
+	if (hook.Command == "") == (hook.URL == "") {
+		return fmt.Errorf("exactly one of command or url is required")
+	}
+	if hook.URL != "" {
+		u, err := url.Parse(hook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url must be an http or https URL")
+		}
+	}
+	if len(hook.Headers) > 0 && hook.URL == "" {
+		return fmt.Errorf("headers only apply to url hooks")
+	}
+	if hook.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout_seconds cannot be negative")
+	}
 	return nil
 }
 
@@ -115,6 +286,41 @@ func (ip *InputParser) validateGenericConfiguration(config *domain.Configuration
 		return fmt.Errorf("only one participant can be the primary FEHB holder")
 	}
 
+	if len(config.Household.Participants) != 2 {
+		for _, participant := range config.Household.Participants {
+			if participant.ClaimSpousalBenefit {
+				return fmt.Errorf("participant %s: spousal benefits require a household of two participants", participant.Name)
+			}
+		}
+	}
+
+	if itemized := config.Household.ItemizedDeductions; itemized != nil {
+		if itemized.MortgageInterest.LessThan(decimal.Zero) || itemized.PropertyTaxes.LessThan(decimal.Zero) || itemized.CharitableContributions.LessThan(decimal.Zero) {
+			return fmt.Errorf("itemized deduction amounts cannot be negative")
+		}
+	}
+	if config.Household.EssentialExpenses.LessThan(decimal.Zero) {
+		return fmt.Errorf("essential expenses cannot be negative")
+	}
+	if config.Household.TaxExemptInterest.LessThan(decimal.Zero) {
+		return fmt.Errorf("tax-exempt interest cannot be negative")
+	}
+	for year, magi := range config.Household.MAGIHistory {
+		if magi.LessThan(decimal.Zero) {
+			return fmt.Errorf("MAGI history for %d cannot be negative", year)
+		}
+	}
+	seenExpenses := make(map[string]bool)
+	for _, e := range config.Household.Expenses {
+		if err := validateExpenseCategory(e); err != nil {
+			return fmt.Errorf("expense %q: %w", e.Name, err)
+		}
+		if seenExpenses[e.Name] {
+			return fmt.Errorf("expense %q is listed more than once", e.Name)
+		}
+		seenExpenses[e.Name] = true
+	}
+
 	// Validate scenarios
 	if len(config.Scenarios) == 0 {
 		return fmt.Errorf("no scenarios provided")
@@ -124,6 +330,13 @@ func (ip *InputParser) validateGenericConfiguration(config *domain.Configuration
 		if err := ip.validateGenericScenario(i, &scenario, config.Household); err != nil {
 			return fmt.Errorf("scenario %d validation failed: %w", i, err)
 		}
+		if config.GlobalAssumptions.TSPReturnModel != "fund" {
+			for name, ps := range scenario.ParticipantScenarios {
+				if len(ps.TSPAllocationSchedule) > 0 {
+					return fmt.Errorf("scenario %d: participant %s: tsp_allocation_schedule requires tsp_return_model: fund", i, name)
+				}
+			}
+		}
 	}
 
 	return nil
@@ -172,6 +385,24 @@ func (ip *InputParser) validateParticipant(index int, participant *domain.Partic
 		}
 	}
 
+	if hc := participant.Healthcare; hc != nil {
+		if err := validateHealthcare(hc); err != nil {
+			return fmt.Errorf("healthcare validation failed: %w", err)
+		}
+	}
+
+	if participant.EmployerPlan != nil {
+		if err := ip.validateEmployerPlan(participant); err != nil {
+			return fmt.Errorf("employer plan validation failed: %w", err)
+		}
+	}
+
+	for _, stream := range participant.OtherIncome {
+		if err := validateIncomeStream(stream); err != nil {
+			return fmt.Errorf("other income %s: %w", stream.Label(), err)
+		}
+	}
+
 	// Taxable account validations (optional fields)
 	if participant.TaxableAccountBalance != nil {
 		if participant.TaxableAccountBalance.LessThan(decimal.Zero) {
@@ -190,6 +421,62 @@ func (ip *InputParser) validateParticipant(index int, participant *domain.Partic
 		return fmt.Errorf("taxable account basis provided without taxable account balance")
 	}
 
+	// IRA validations (optional fields)
+	for _, field := range []struct {
+		name  string
+		value *decimal.Decimal
+	}{
+		{"IRA traditional balance", participant.IRABalanceTraditional},
+		{"IRA Roth balance", participant.IRABalanceRoth},
+		{"IRA traditional contribution", participant.IRAContributionTraditional},
+		{"IRA Roth contribution", participant.IRAContributionRoth},
+		{"TSP Roth basis", participant.TSPRothBasis},
+		{"IRA Roth basis", participant.IRARothBasis},
+		{"FEHB premium growth", participant.FEHBPremiumGrowth},
+	} {
+		if field.value != nil && field.value.LessThan(decimal.Zero) {
+			return fmt.Errorf("%s cannot be negative", field.name)
+		}
+	}
+	for _, basis := range []struct {
+		name           string
+		basis, balance *decimal.Decimal
+	}{
+		{"TSP Roth basis", participant.TSPRothBasis, participant.TSPBalanceRoth},
+		{"IRA Roth basis", participant.IRARothBasis, participant.IRABalanceRoth},
+	} {
+		if basis.basis != nil && (basis.balance == nil || basis.basis.GreaterThan(*basis.balance)) {
+			return fmt.Errorf("%s cannot exceed the Roth balance", basis.name)
+		}
+	}
+
+	if participant.StateResidency != "" {
+		if _, ok := domain.NormalizeState(participant.StateResidency); !ok {
+			return fmt.Errorf("unknown state residency %q", participant.StateResidency)
+		}
+	}
+
+	if participant.TSPAllocation != nil {
+		if err := validateTSPAllocation(*participant.TSPAllocation); err != nil {
+			return err
+		}
+	}
+
+	if lf := participant.TSPLifecycleFund; lf != nil {
+		if _, ok := lf.TargetYear(); !ok {
+			return fmt.Errorf("unknown TSP lifecycle fund %q (expected L2030 through L2070 or L Income)", lf.FundName)
+		}
+	}
+
+	if ncp := participant.NonCoveredPension; ncp != nil {
+		if ncp.MonthlyBenefit.LessThan(decimal.Zero) {
+			return fmt.Errorf("non-covered pension monthly benefit cannot be negative")
+		}
+		if ncp.YearsOfSubstantialEarnings < 0 {
+			return fmt.Errorf("years of substantial earnings cannot be negative")
+		}
+	}
+
 	return nil
 }
 
@@ -199,6 +486,9 @@ func (ip *InputParser) validateFederalParticipant(participant *domain.Participan
 	if participant.HireDate == nil {
 		return fmt.Errorf("hire date is required for federal employees")
 	}
+	if cr := participant.CurrentRetirement; cr != nil {
+		return ip.validateCurrentRetirement(participant, cr)
+	}
 	if participant.CurrentSalary == nil {
 		return fmt.Errorf("current salary is required for federal employees")
 	}
@@ -242,6 +532,42 @@ func (ip *InputParser) validateFederalParticipant(participant *domain.Participan
 	if participant.BirthDate.After(*participant.HireDate) {
 		return fmt.Errorf("birth date cannot be after hire date")
 	}
+	if participant.FEHBCoverageStartDate != nil && participant.FEHBCoverageStartDate.Before(*participant.HireDate) {
+		return fmt.Errorf("FEHB coverage start date cannot be before hire date")
+	}
+
+	switch participant.RetirementSystem {
+	case "", domain.RetirementSystemFERS, domain.RetirementSystemCSRS, domain.RetirementSystemCSRSOffset:
+	default:
+		return fmt.Errorf("retirement system must be one of: fers, csrs, csrs_offset")
+	}
+	if participant.SpecialProvision != "" && participant.IsCSRS() {
+		return fmt.Errorf("special provision retirement is only supported for FERS participants")
+	}
+
+	switch participant.SpecialProvision {
+	case "", domain.SpecialProvisionLawEnforcement, domain.SpecialProvisionFirefighter, domain.SpecialProvisionAirTrafficController:
+	default:
+		return fmt.Errorf("special provision must be one of: law_enforcement, firefighter, air_traffic_controller")
+	}
+
+	if md := participant.MilitaryDeposit; md != nil {
+		if !md.Years.IsPositive() {
+			return fmt.Errorf("military deposit years must be positive")
+		}
+		if md.Amount.LessThan(decimal.Zero) {
+			return fmt.Errorf("military deposit amount cannot be negative")
+		}
+		switch md.PaidFrom {
+		case "", domain.MilitaryDepositFromTaxable, domain.MilitaryDepositFromTSP:
+		default:
+			return fmt.Errorf("military deposit paid_from must be one of: taxable, tsp")
+		}
+	}
+
+	if err := ip.validateSurvivorElection(participant); err != nil {
+		return err
+	}
 
 	// FEHB validation
 	if participant.IsPrimaryFEHBHolder {
@@ -251,8 +577,69 @@ func (ip *InputParser) validateFederalParticipant(participant *domain.Participan
 		if participant.FEHBPremiumPerPayPeriod.LessThan(decimal.Zero) {
 			return fmt.Errorf("FEHB premium per pay period cannot be negative")
 		}
+		if selfOnly := participant.FEHBSelfOnlyPremiumPerPayPeriod; selfOnly != nil && (selfOnly.LessThan(decimal.Zero) || selfOnly.GreaterThan(*participant.FEHBPremiumPerPayPeriod)) {
+			return fmt.Errorf("FEHB self only premium must be between zero and the enrolled premium")
+		}
+	}
+
+	return nil
+}
+
+// validateCurrentRetirement validates a federal participant who is already retired. Salary,
+// high-3 and contribution fields are not needed since no annuity is computed.
+func (ip *InputParser) validateCurrentRetirement(participant *domain.Participant, cr *domain.CurrentRetirement) error {
+	if cr.RetirementDate.IsZero() {
+		return fmt.Errorf("current retirement requires a retirement date")
+	}
+	if cr.RetirementDate.Before(*participant.HireDate) {
+		return fmt.Errorf("current retirement date cannot be before hire date")
+	}
+	if cr.AnnualAnnuity.LessThan(decimal.Zero) || cr.SurvivorAnnuity.LessThan(decimal.Zero) ||
+		cr.FERSSupplement.LessThan(decimal.Zero) || cr.SSMonthlyBenefit.LessThan(decimal.Zero) {
+		return fmt.Errorf("current retirement benefit amounts cannot be negative")
+	}
+	if cr.FERSSupplement.IsPositive() && participant.IsCSRS() {
+		return fmt.Errorf("CSRS annuitants do not receive the FERS supplement")
+	}
+	if participant.TSPBalanceTraditional != nil && participant.TSPBalanceTraditional.LessThan(decimal.Zero) {
+		return fmt.Errorf("TSP traditional balance cannot be negative")
+	}
+	if participant.TSPBalanceRoth != nil && participant.TSPBalanceRoth.LessThan(decimal.Zero) {
+		return fmt.Errorf("TSP Roth balance cannot be negative")
+	}
+	if participant.IsPrimaryFEHBHolder && participant.FEHBPremiumPerPayPeriod == nil {
+		return fmt.Errorf("FEHB premium per pay period is required for primary FEHB holder")
+	}
+	return nil
+}
+
+// validateSurvivorElection validates the survivor election type and the inputs it depends on.
+// FERS spouse elections are limited to the statutory 25% and 50% options.
+func (ip *InputParser) validateSurvivorElection(participant *domain.Participant) error {
+	election := participant.SurvivorElection
+	if election == nil || election.Type == "" || election.Type == domain.SurvivorElectionSpouse {
+		if !participant.IsCSRS() {
+			pct := *participant.SurvivorBenefitElectionPercent
+			if !pct.IsZero() && !pct.Equal(decimal.NewFromFloat(0.25)) && !pct.Equal(decimal.NewFromFloat(0.5)) {
+				return fmt.Errorf("FERS survivor benefit election percent must be 0, 0.25 or 0.50")
+			}
+		}
+	} else if election.Type != domain.SurvivorElectionInsurableInterest {
+		return fmt.Errorf("survivor election type must be one of: spouse, insurable_interest")
+	}
+	if election == nil {
+		return nil
 	}
 
+	if election.Type == domain.SurvivorElectionInsurableInterest && election.BeneficiaryBirthDate == nil {
+		return fmt.Errorf("insurable interest survivor election requires a beneficiary birth date")
+	}
+	if election.BaseAmount.LessThan(decimal.Zero) {
+		return fmt.Errorf("survivor election base amount cannot be negative")
+	}
+	if election.BaseAmount.IsPositive() && (!participant.IsCSRS() || election.Type == domain.SurvivorElectionInsurableInterest) {
+		return fmt.Errorf("survivor election base amount only applies to CSRS spouse elections")
+	}
 	return nil
 }
 
@@ -270,6 +657,289 @@ func (ip *InputParser) validateExternalPension(pension *domain.ExternalPension)
 	if pension.SurvivorBenefit.LessThan(decimal.Zero) || pension.SurvivorBenefit.GreaterThan(decimal.NewFromFloat(1.0)) {
 		return fmt.Errorf("survivor benefit must be between 0 and 1")
 	}
+	if pension.SurvivorReduction.LessThan(decimal.Zero) || pension.SurvivorReduction.GreaterThan(decimal.NewFromFloat(1.0)) {
+		return fmt.Errorf("survivor reduction must be between 0 and 1")
+	}
+	if pension.LumpSum.LessThan(decimal.Zero) {
+		return fmt.Errorf("lump sum cannot be negative")
+	}
+	switch pension.LumpSumRollover {
+	case "", domain.PensionRolloverIRA, domain.PensionRolloverTSP:
+	default:
+		return fmt.Errorf("lump sum rollover must be 'ira' or 'tsp'")
+	}
+	if pension.Election != "" {
+		return validatePensionElection(pension, pension.Election)
+	}
+	return nil
+}
+
+// validateHealthcare checks a participant's healthcare coverage and cost assumptions
+func validateHealthcare(hc *domain.HealthcareConfig) error {
+	if hc.MedicareCoverage != "" {
+		if err := validateMedicareCoverage(hc.MedicareCoverage); err != nil {
+			return err
+		}
+	}
+	if err := validatePartBEnrollmentAge(hc.PartBEnrollmentAge); err != nil {
+		return err
+	}
+	for _, field := range []struct {
+		name  string
+		value decimal.Decimal
+	}{
+		{"pre-Medicare monthly premium", hc.PreMedicareMonthlyPremium},
+		{"marketplace benchmark premium", hc.MarketplaceBenchmarkPremium},
+		{"drug costs", hc.DrugCosts},
+		{"FEHB out-of-pocket costs", hc.OutOfPocketFEHB},
+		{"Medigap out-of-pocket costs", hc.OutOfPocketMedigap},
+	} {
+		if field.value.LessThan(decimal.Zero) {
+			return fmt.Errorf("%s cannot be negative", field.name)
+		}
+	}
+	return nil
+}
+
+// validateMedicareCoverage checks a choice of coverage alongside Medicare
+func validateMedicareCoverage(coverage string) error {
+	switch coverage {
+	case domain.MedicareCoverageFEHBSecondary, domain.MedicareCoverageMedigap:
+		return nil
+	}
+	return fmt.Errorf("medicare coverage must be 'fehb_secondary' or 'medigap'")
+}
+
+// validatePartBEnrollmentAge checks the age Part B enrollment is delayed to, if any
+func validatePartBEnrollmentAge(age int) error {
+	if age != 0 && (age < 65 || age > 100) {
+		return fmt.Errorf("Part B enrollment age must be between 65 and 100")
+	}
+	return nil
+}
+
+// validateIncomeAnnuityPurchase checks an income annuity purchase
+func validateIncomeAnnuityPurchase(a domain.IncomeAnnuityPurchase) error {
+	if !a.Premium.IsPositive() {
+		return fmt.Errorf("premium must be positive")
+	}
+	if a.Source != domain.AnnuitySourceTaxable && a.Source != domain.AnnuitySourceTraditional {
+		return fmt.Errorf("source must be %q or %q", domain.AnnuitySourceTaxable, domain.AnnuitySourceTraditional)
+	}
+	if a.PurchaseAge < 40 || a.PurchaseAge > 90 {
+		return fmt.Errorf("purchase age must be between 40 and 90")
+	}
+	if a.IncomeStartAge != 0 && (a.IncomeStartAge < a.PurchaseAge || a.IncomeStartAge > 90) {
+		return fmt.Errorf("income start age must be between the purchase age and 90")
+	}
+	if !a.PayoutRate.IsPositive() || a.PayoutRate.GreaterThan(decimal.NewFromFloat(0.5)) {
+		return fmt.Errorf("payout rate must be between 0 and 50%%")
+	}
+	if a.COLA.IsNegative() || a.COLA.GreaterThan(decimal.NewFromFloat(0.1)) {
+		return fmt.Errorf("cola must be between 0 and 10%%")
+	}
+	if a.SurvivorPercent.IsNegative() || a.SurvivorPercent.GreaterThan(decimal.NewFromInt(1)) {
+		return fmt.Errorf("survivor percent must be between 0 and 1")
+	}
+	return nil
+}
+
+// validateCashFlowEvent checks a one-time cash flow event. Inflows are only deposited to
+// the taxable account; outflows are taxed by the account that pays them.
+func validateCashFlowEvent(e domain.CashFlowEvent, household *domain.Household) error {
+	if e.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if e.Date.IsZero() {
+		return fmt.Errorf("date is required")
+	}
+	if !e.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive")
+	}
+	if e.Participant != "" {
+		found := false
+		for _, p := range household.Participants {
+			found = found || p.Name == e.Participant
+		}
+		if !found {
+			return fmt.Errorf("unknown participant %q", e.Participant)
+		}
+	}
+	switch e.Type {
+	case domain.CashFlowInflow:
+		if e.Account != "" && e.Account != domain.CashFlowAccountTaxable {
+			return fmt.Errorf("inflows are deposited to the taxable account")
+		}
+		switch e.TaxTreatment {
+		case "", domain.CashFlowTaxFree, domain.CashFlowOrdinary, domain.CashFlowCapitalGain:
+		default:
+			return fmt.Errorf("tax treatment must be 'tax_free', 'ordinary' or 'capital_gain'")
+		}
+		if e.TaxableAmount != nil && (e.TaxableAmount.IsNegative() || e.TaxableAmount.GreaterThan(e.Amount)) {
+			return fmt.Errorf("taxable amount must be between 0 and the amount")
+		}
+	case domain.CashFlowOutflow:
+		switch e.Account {
+		case "", domain.CashFlowAccountTaxable, domain.CashFlowAccountTSP, domain.CashFlowAccountIRA:
+		default:
+			return fmt.Errorf("account must be 'taxable', 'tsp' or 'ira'")
+		}
+		if e.TaxTreatment != "" || e.TaxableAmount != nil {
+			return fmt.Errorf("an outflow is taxed by the account that pays it; remove tax_treatment and taxable_amount")
+		}
+	default:
+		return fmt.Errorf("type must be 'inflow' or 'outflow'")
+	}
+	return nil
+}
+
+// validatePensionElection checks an external pension election, which may only take a lump
+// sum the plan offers
+func validatePensionElection(pension *domain.ExternalPension, election string) error {
+	switch election {
+	case domain.PensionElectionAnnuity:
+		if pension == nil {
+			return fmt.Errorf("pension election requires an external pension")
+		}
+	case domain.PensionElectionLumpSum:
+		if pension == nil || !pension.LumpSum.IsPositive() {
+			return fmt.Errorf("lump_sum pension election requires an external pension with a lump_sum offer")
+		}
+	default:
+		return fmt.Errorf("pension election must be 'annuity' or 'lump_sum'")
+	}
+	return nil
+}
+
+// validateIncomeStream validates a rental, self-employment or other income stream
+func validateIncomeStream(stream domain.IncomeStream) error {
+	switch stream.Type {
+	case domain.IncomeStreamRental, domain.IncomeStreamConsulting, domain.IncomeStreamBusiness, domain.IncomeStreamRoyalty, domain.IncomeStreamOther:
+	default:
+		return fmt.Errorf("type must be rental, consulting, business, royalty or other")
+	}
+	switch stream.TaxCharacter {
+	case "", domain.TaxCharacterEarned, domain.TaxCharacterPassive:
+	default:
+		return fmt.Errorf("tax_character must be %q or %q", domain.TaxCharacterEarned, domain.TaxCharacterPassive)
+	}
+	if !stream.AnnualAmount.IsPositive() {
+		return fmt.Errorf("annual amount must be positive")
+	}
+	if stream.StartDate != nil && stream.EndDate != nil && stream.EndDate.Before(*stream.StartDate) {
+		return fmt.Errorf("end date cannot be before the start date")
+	}
+	if g := stream.GrowthRate; g != nil && (g.LessThan(decimal.NewFromFloat(-0.5)) || g.GreaterThan(decimal.NewFromFloat(0.5))) {
+		return fmt.Errorf("growth rate must be between -50%% and 50%%")
+	}
+	return nil
+}
+
+// validateExpenseCategory validates one category of the household's spending budget
+func validateExpenseCategory(e domain.ExpenseCategory) error {
+	if strings.TrimSpace(e.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if e.AnnualAmount.LessThan(decimal.Zero) {
+		return fmt.Errorf("annual amount cannot be negative")
+	}
+	if r := e.InflationRate; r != nil && (r.LessThan(decimal.NewFromFloat(-0.1)) || r.GreaterThan(decimal.NewFromFloat(0.2))) {
+		return fmt.Errorf("inflation rate must be between -10%% and 20%%")
+	}
+	if e.StartYear != 0 && e.EndYear != 0 && e.EndYear < e.StartYear {
+		return fmt.Errorf("end year cannot be before the start year")
+	}
+	return nil
+}
+
+// validateGuardrailRules validates the decision rules of the guardrails strategy
+func validateGuardrailRules(rules *domain.GuardrailRules) error {
+	for _, field := range []struct {
+		name  string
+		value decimal.Decimal
+	}{
+		{"upper band", rules.UpperBand},
+		{"lower band", rules.LowerBand},
+		{"cut percent", rules.CutPercent},
+		{"raise percent", rules.RaisePercent},
+	} {
+		if field.value.LessThan(decimal.Zero) || field.value.GreaterThan(decimal.NewFromFloat(0.5)) {
+			return fmt.Errorf("%s must be between 0 and 50%%", field.name)
+		}
+	}
+	return nil
+}
+
+// validateBucketStrategy validates the bucket sizes of the buckets strategy
+func validateBucketStrategy(buckets *domain.BucketStrategy) error {
+	if buckets.CashYears < 0 || buckets.CashYears > 10 {
+		return fmt.Errorf("cash years must be between 0 and 10")
+	}
+	if buckets.BondYears < 0 || buckets.BondYears > 20 {
+		return fmt.Errorf("bond years must be between 0 and 20")
+	}
+	return nil
+}
+
+// validateSpendingPhases validates a scenario's age-based spending phases
+func validateSpendingPhases(phases *domain.SpendingPhases, household *domain.Household) error {
+	if phases.Participant != "" {
+		found := false
+		for _, p := range household.Participants {
+			found = found || p.Name == phases.Participant
+		}
+		if !found {
+			return fmt.Errorf("unknown participant %q", phases.Participant)
+		}
+	}
+	if len(phases.Phases) == 0 {
+		return fmt.Errorf("at least one phase is required")
+	}
+	for i, phase := range phases.Phases {
+		if phase.StartAge < 0 || phase.StartAge > 120 {
+			return fmt.Errorf("phase %d: start age must be between 0 and 120", i+1)
+		}
+		if i > 0 && phase.StartAge <= phases.Phases[i-1].StartAge {
+			return fmt.Errorf("phase %d: start ages must increase", i+1)
+		}
+		if phase.AnnualChange.LessThan(decimal.NewFromFloat(-0.1)) || phase.AnnualChange.GreaterThan(decimal.NewFromFloat(0.1)) {
+			return fmt.Errorf("phase %d: annual change must be between -10%% and 10%%", i+1)
+		}
+	}
+	return nil
+}
+
+// validateEmployerPlan validates a non-federal employer plan
+func (ip *InputParser) validateEmployerPlan(participant *domain.Participant) error {
+	plan := participant.EmployerPlan
+	if plan.BalanceTraditional.LessThan(decimal.Zero) || plan.BalanceRoth.LessThan(decimal.Zero) || plan.BalanceEmployer.LessThan(decimal.Zero) {
+		return fmt.Errorf("balances cannot be negative")
+	}
+	if plan.BalanceEmployer.GreaterThan(plan.BalanceTraditional) {
+		return fmt.Errorf("employer balance cannot exceed the traditional balance it is part of")
+	}
+	one := decimal.NewFromInt(1)
+	if plan.ContributionPercent.LessThan(decimal.Zero) || plan.RothContributionPercent.LessThan(decimal.Zero) ||
+		plan.ContributionPercent.Add(plan.RothContributionPercent).GreaterThan(one) {
+		return fmt.Errorf("contribution percents must be between 0 and 1 in total")
+	}
+	for i, tier := range plan.Match {
+		if tier.Rate.LessThan(decimal.Zero) || tier.PercentOfPay.LessThan(decimal.Zero) || tier.PercentOfPay.GreaterThan(one) {
+			return fmt.Errorf("match tier %d must have a non-negative rate and a percent of pay between 0 and 1", i+1)
+		}
+	}
+	switch plan.Vesting {
+	case "", domain.VestingImmediate:
+	case domain.VestingCliff, domain.VestingGraded:
+		if plan.VestingYears <= 0 {
+			return fmt.Errorf("%s vesting requires vesting_years", plan.Vesting)
+		}
+		if plan.ServiceStartDate == nil && participant.HireDate == nil {
+			return fmt.Errorf("%s vesting requires service_start_date or hire_date", plan.Vesting)
+		}
+	default:
+		return fmt.Errorf("unknown vesting schedule %q (expected immediate, cliff or graded)", plan.Vesting)
+	}
 	return nil
 }
 
@@ -282,6 +952,9 @@ func (ip *InputParser) validateGenericScenario(index int, scenario *domain.Gener
 	if len(scenario.ParticipantScenarios) == 0 {
 		return fmt.Errorf("at least one participant scenario is required")
 	}
+	if scenario.ProjectionYears < 0 || scenario.ProjectionYears > 50 {
+		return fmt.Errorf("projection years must be between 1 and 50")
+	}
 
 	// Validate each participant scenario
 	// Sort participant names for deterministic processing order
@@ -294,20 +967,42 @@ func (ip *InputParser) validateGenericScenario(index int, scenario *domain.Gener
 	for _, name := range scenarioNames {
 		participantScenario := scenario.ParticipantScenarios[name]
 		// Check that participant exists in household
-		found := false
-		for _, p := range household.Participants {
-			if p.Name == name {
-				found = true
+		var participant *domain.Participant
+		for i := range household.Participants {
+			if household.Participants[i].Name == name {
+				participant = &household.Participants[i]
 				break
 			}
 		}
-		if !found {
+		if participant == nil {
 			return fmt.Errorf("participant scenario references unknown participant: %s", name)
 		}
 
 		if err := ip.validateParticipantScenario(name, &participantScenario); err != nil {
 			return fmt.Errorf("participant scenario %s validation failed: %w", name, err)
 		}
+		if election := participantScenario.PensionElection; election != "" {
+			if err := validatePensionElection(participant.ExternalPension, election); err != nil {
+				return fmt.Errorf("participant scenario %s validation failed: %w", name, err)
+			}
+		}
+		if coverage := participantScenario.MedicareCoverage; coverage != "" {
+			if err := validateMedicareCoverage(coverage); err != nil {
+				return fmt.Errorf("participant scenario %s validation failed: %w", name, err)
+			}
+		}
+		if err := validatePartBEnrollmentAge(participantScenario.PartBEnrollmentAge); err != nil {
+			return fmt.Errorf("participant scenario %s validation failed: %w", name, err)
+		}
+	}
+
+	if err := validateSpendingTargetStrategy(scenario, household); err != nil {
+		return err
+	}
+	if scenario.MAGICeiling != nil {
+		if err := validateMAGICeiling(scenario.MAGICeiling); err != nil {
+			return fmt.Errorf("magi_ceiling: %w", err)
+		}
 	}
 
 	// Validate mortality if present
@@ -348,6 +1043,39 @@ func (ip *InputParser) validateGenericScenario(index int, scenario *domain.Gener
 			if scenario.Mortality.Assumptions.FilingStatusSwitch != "" && scenario.Mortality.Assumptions.FilingStatusSwitch != "next_year" && scenario.Mortality.Assumptions.FilingStatusSwitch != "immediate" {
 				return fmt.Errorf("filing_status_switch must be 'next_year' or 'immediate'")
 			}
+			switch scenario.Mortality.Assumptions.FEHBSurvivorEnrollment {
+			case "", domain.FEHBSurvivorEnrollmentSelfOnly, domain.FEHBSurvivorEnrollmentUnchanged:
+			default:
+				return fmt.Errorf("fehb_survivor_enrollment must be 'self_only' or 'unchanged'")
+			}
+		}
+	}
+
+	// Validate tax elections: one per year, with a known deduction
+	electionYears := make(map[int]bool, len(scenario.TaxElections))
+	for _, election := range scenario.TaxElections {
+		if electionYears[election.Year] {
+			return fmt.Errorf("duplicate tax election for year %d", election.Year)
+		}
+		electionYears[election.Year] = true
+		if election.Deduction != "" && election.Deduction != domain.DeductionStandard && election.Deduction != domain.DeductionItemized {
+			return fmt.Errorf("tax election for %d: deduction must be 'standard' or 'itemized'", election.Year)
+		}
+	}
+
+	for i, e := range scenario.CashFlowEvents {
+		if err := validateCashFlowEvent(e, household); err != nil {
+			name := e.Name
+			if name == "" {
+				name = fmt.Sprintf("%d", i+1)
+			}
+			return fmt.Errorf("cash flow event %s: %w", name, err)
+		}
+	}
+
+	if scenario.SpendingPhases != nil {
+		if err := validateSpendingPhases(scenario.SpendingPhases, household); err != nil {
+			return fmt.Errorf("spending phases: %w", err)
 		}
 	}
 
@@ -365,7 +1093,7 @@ func (ip *InputParser) validateGenericScenario(index int, scenario *domain.Gener
 			if len(ws.CustomSequence) == 0 {
 				return fmt.Errorf("custom sequence required when strategy=custom")
 			}
-			allowed := map[string]bool{"taxable": true, "traditional": true, "roth": true}
+			allowed := map[string]bool{"taxable": true, "traditional": true, "roth": true, "ira_traditional": true, "ira_roth": true}
 			seen := map[string]bool{}
 			for _, src := range ws.CustomSequence {
 				if !allowed[src] {
@@ -388,6 +1116,9 @@ func (ip *InputParser) validateGenericScenario(index int, scenario *domain.Gener
 				return fmt.Errorf("bracket_buffer cannot be negative")
 			}
 		}
+		if err := validateAutoRothConversions(ws, scenario, household); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -406,15 +1137,33 @@ func (ip *InputParser) validateParticipantScenario(participantName string, scena
 		return fmt.Errorf("social security start age must be between 62 and 70")
 	}
 
+	if (scenario.SeparationDate != nil) != (scenario.AnnuityStartAge != 0) {
+		return fmt.Errorf("separation_date and annuity_start_age must be specified together")
+	}
+	if scenario.SeparationDate != nil {
+		if scenario.RetirementDate != nil {
+			return fmt.Errorf("specify either retirement_date or separation_date, not both")
+		}
+		if scenario.AnnuityStartAge < 55 || scenario.AnnuityStartAge > 70 {
+			return fmt.Errorf("annuity_start_age must be between 55 and 70")
+		}
+	}
+
 	// TSP withdrawal validation (only for federal employees)
 	if scenario.TSPWithdrawalStrategy != "" {
 		validStrategies := map[string]bool{
 			"4_percent_rule":      true,
 			"need_based":          true,
 			"variable_percentage": true,
+			"guardrails":          true,
+			"buckets":             true,
+			"vpw":                 true,
+			"rmd_only":            true,
+			"spending_target":     true,
+			"sepp":                true,
 		}
 		if !validStrategies[scenario.TSPWithdrawalStrategy] {
-			return fmt.Errorf("TSP withdrawal strategy must be '4_percent_rule', 'need_based', or 'variable_percentage'")
+			return fmt.Errorf("TSP withdrawal strategy must be '4_percent_rule', 'need_based', 'variable_percentage', 'guardrails', 'buckets', 'vpw', 'rmd_only', 'spending_target', or 'sepp'")
 		}
 
 		if scenario.TSPWithdrawalStrategy == "need_based" && scenario.TSPWithdrawalTargetMonthly == nil {
@@ -423,14 +1172,181 @@ func (ip *InputParser) validateParticipantScenario(participantName string, scena
 		if scenario.TSPWithdrawalStrategy == "variable_percentage" && scenario.TSPWithdrawalRate == nil {
 			return fmt.Errorf("TSP withdrawal rate is required for variable_percentage strategy")
 		}
+		if scenario.TSPWithdrawalStrategy == "guardrails" && scenario.TSPWithdrawalRate == nil {
+			return fmt.Errorf("TSP withdrawal rate is required for guardrails strategy (the initial rate)")
+		}
+		if scenario.Guardrails != nil {
+			if err := validateGuardrailRules(scenario.Guardrails); err != nil {
+				return fmt.Errorf("guardrails: %w", err)
+			}
+		}
+		if scenario.TSPWithdrawalStrategy == "buckets" && scenario.TSPWithdrawalRate == nil {
+			return fmt.Errorf("TSP withdrawal rate is required for buckets strategy (the initial rate)")
+		}
+		if scenario.Buckets != nil {
+			if err := validateBucketStrategy(scenario.Buckets); err != nil {
+				return fmt.Errorf("buckets: %w", err)
+			}
+		}
+		if t := scenario.SpendingTarget; t != nil && !t.IsPositive() {
+			return fmt.Errorf("spending target must be positive")
+		}
+		if a := scenario.VPWStockAllocation; a != nil && (a.LessThan(decimal.Zero) || a.GreaterThan(decimal.NewFromInt(1))) {
+			return fmt.Errorf("VPW stock allocation must be between 0 and 100%%")
+		}
 		if scenario.TSPWithdrawalTargetMonthly != nil && scenario.TSPWithdrawalTargetMonthly.LessThanOrEqual(decimal.Zero) {
 			return fmt.Errorf("TSP withdrawal target monthly must be positive")
 		}
 		if scenario.TSPWithdrawalRate != nil && (scenario.TSPWithdrawalRate.LessThan(decimal.Zero) || scenario.TSPWithdrawalRate.GreaterThan(decimal.NewFromFloat(0.2))) {
 			return fmt.Errorf("TSP withdrawal rate must be between 0 and 20%%")
 		}
+		if scenario.SEPPInterestRate != nil && (!scenario.SEPPInterestRate.IsPositive() || scenario.SEPPInterestRate.GreaterThan(decimal.NewFromFloat(0.1))) {
+			return fmt.Errorf("SEPP interest rate must be greater than 0 and at most 10%%")
+		}
+	}
+
+	if scenario.QCDs != nil {
+		if scenario.QCDs.AnnualAmount.LessThan(decimal.Zero) {
+			return fmt.Errorf("QCD annual amount cannot be negative")
+		}
+		for _, d := range scenario.QCDs.Distributions {
+			if d.Amount.LessThan(decimal.Zero) {
+				return fmt.Errorf("QCD amount for %d cannot be negative", d.Year)
+			}
+		}
+	}
+
+	if a := scenario.TSPAnnuity; a != nil {
+		if a.Amount.IsPositive() == a.Percentage.IsPositive() {
+			return fmt.Errorf("TSP annuity requires exactly one of amount or percentage")
+		}
+		if a.Amount.IsPositive() && a.Amount.LessThan(decimal.NewFromInt(3500)) {
+			return fmt.Errorf("TSP annuity purchase must be at least $3,500")
+		}
+		if a.Percentage.GreaterThan(decimal.NewFromInt(1)) {
+			return fmt.Errorf("TSP annuity percentage must be between 0 and 1")
+		}
+		if a.PurchaseAge != 0 && (a.PurchaseAge < 50 || a.PurchaseAge > 90) {
+			return fmt.Errorf("TSP annuity purchase age must be between 50 and 90")
+		}
+		if !a.SurvivorPercent.IsZero() && !a.SurvivorPercent.Equal(decimal.NewFromFloat(0.5)) && !a.SurvivorPercent.Equal(decimal.NewFromInt(1)) {
+			return fmt.Errorf("TSP annuity survivor percent must be 0, 0.5 or 1")
+		}
+		if r := a.AnnualPayoutRate; r != nil && (!r.IsPositive() || r.GreaterThan(decimal.NewFromFloat(0.5))) {
+			return fmt.Errorf("TSP annuity payout rate must be between 0 and 50%%")
+		}
+	}
+
+	for _, a := range scenario.AnnuityPurchases {
+		if err := validateIncomeAnnuityPurchase(a); err != nil {
+			return fmt.Errorf("%s: %w", a.Label(), err)
+		}
+	}
+
+	for i, step := range scenario.TSPAllocationSchedule {
+		if step.AtRetirement == (step.Age != 0) {
+			return fmt.Errorf("TSP allocation schedule step %d must set exactly one of age or at_retirement", i)
+		}
+		if step.Age < 0 || step.Age > 120 {
+			return fmt.Errorf("TSP allocation schedule step %d: age must be between 0 and 120", i)
+		}
+		if err := validateTSPAllocation(step.Allocation); err != nil {
+			return fmt.Errorf("TSP allocation schedule step %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateSpendingTargetStrategy checks that at most one participant solves withdrawals for
+// the spending target and that exactly one of their spending_target and the household's
+// expenses sets it
+func validateSpendingTargetStrategy(scenario *domain.GenericScenario, household *domain.Household) error {
+	var solvers []string
+	for name, ps := range scenario.ParticipantScenarios {
+		if ps.TSPWithdrawalStrategy == "spending_target" {
+			solvers = append(solvers, name)
+		}
+	}
+	if len(solvers) == 0 {
+		return nil
+	}
+	if len(solvers) > 1 {
+		sort.Strings(solvers)
+		return fmt.Errorf("only one participant can use the spending_target strategy, got %s", strings.Join(solvers, " and "))
+	}
+	target := scenario.ParticipantScenarios[solvers[0]].SpendingTarget
+	if target == nil && len(household.Expenses) == 0 {
+		return fmt.Errorf("participant scenario %s: spending_target strategy requires spending_target or household expenses", solvers[0])
+	}
+	if target != nil && len(household.Expenses) > 0 {
+		return fmt.Errorf("participant scenario %s: spending_target cannot be combined with household expenses, which already set the target", solvers[0])
+	}
+	return nil
+}
+
+// validateAutoRothConversions checks that automatic conversions come with the bracket_fill
+// strategy and convert for a participant of the scenario who schedules no conversions
+func validateAutoRothConversions(ws *domain.WithdrawalSequencingConfig, scenario *domain.GenericScenario, household *domain.Household) error {
+	if !ws.AutoRothConversions {
+		if ws.ConversionParticipant != "" {
+			return fmt.Errorf("conversion_participant requires auto_roth_conversions")
+		}
+		return nil
+	}
+	if ws.Strategy != "bracket_fill" {
+		return fmt.Errorf("auto_roth_conversions requires the bracket_fill strategy")
+	}
+	participant := ws.ConversionParticipant
+	if participant == "" {
+		for _, p := range household.Participants {
+			if _, ok := scenario.ParticipantScenarios[p.Name]; ok {
+				participant = p.Name
+				break
+			}
+		}
+	}
+	ps, ok := scenario.ParticipantScenarios[participant]
+	if !ok {
+		return fmt.Errorf("conversion_participant %s has no participant scenario", participant)
+	}
+	if ps.RothConversions != nil && len(ps.RothConversions.Conversions) > 0 {
+		return fmt.Errorf("participant scenario %s: roth_conversions cannot be combined with auto_roth_conversions", participant)
+	}
+	return nil
+}
+
+// validateMAGICeiling checks that a MAGI ceiling is set by exactly one of a positive
+// amount and an IRMAA tier, less a non-negative buffer
+func validateMAGICeiling(ceiling *domain.MAGICeiling) error {
+	if ceiling.Amount == nil && ceiling.BelowIRMAATier == 0 {
+		return fmt.Errorf("amount or below_irmaa_tier is required")
+	}
+	if ceiling.Amount != nil && ceiling.BelowIRMAATier != 0 {
+		return fmt.Errorf("amount and below_irmaa_tier cannot both be set")
+	}
+	if ceiling.Amount != nil && !ceiling.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive")
+	}
+	if ceiling.BelowIRMAATier < 0 || ceiling.BelowIRMAATier > 5 {
+		return fmt.Errorf("below_irmaa_tier must be between 1 and 5")
 	}
+	if ceiling.Buffer.IsNegative() {
+		return fmt.Errorf("buffer cannot be negative")
+	}
+	return nil
+}
 
+// validateTSPAllocation checks that fund shares are non-negative and sum to 1
+func validateTSPAllocation(a domain.TSPAllocation) error {
+	for _, share := range []decimal.Decimal{a.CFund, a.SFund, a.IFund, a.FFund, a.GFund} {
+		if share.LessThan(decimal.Zero) {
+			return fmt.Errorf("TSP allocation shares cannot be negative")
+		}
+	}
+	if a.Total().Sub(decimal.NewFromInt(1)).Abs().GreaterThan(decimal.NewFromFloat(0.001)) {
+		return fmt.Errorf("TSP allocation must sum to 1, got %s", a.Total().String())
+	}
 	return nil
 }
 
@@ -454,16 +1370,75 @@ func (ip *InputParser) validateGlobalAssumptions(assumptions *domain.GlobalAssum
 	if assumptions.ProjectionYears <= 0 || assumptions.ProjectionYears > 50 {
 		return fmt.Errorf("projection years must be between 1 and 50")
 	}
+	switch assumptions.ProjectionGranularity {
+	case "", "annual", "semi_annual", "quarterly", "monthly":
+	default:
+		return fmt.Errorf("projection granularity must be annual, semi_annual, quarterly, or monthly")
+	}
+	if assumptions.SubAnnualYears < 0 {
+		return fmt.Errorf("sub-annual years cannot be negative")
+	}
+	switch assumptions.TSPReturnModel {
+	case "", "flat", "fund":
+	default:
+		return fmt.Errorf("TSP return model must be flat or fund")
+	}
+	if r := assumptions.TSPFundReturns; r != nil {
+		for _, rate := range []decimal.Decimal{r.CFund, r.SFund, r.IFund, r.FFund, r.GFund} {
+			if rate.LessThanOrEqual(decimal.NewFromInt(-1)) {
+				return fmt.Errorf("TSP fund returns must be greater than -100%%")
+			}
+		}
+	}
+	mc := assumptions.MonteCarloSettings
+	for _, check := range []struct {
+		name   string
+		bounds *domain.RateBounds
+	}{
+		{"TSP return", mc.TSPReturnBounds},
+		{"inflation", mc.InflationBounds},
+		{"COLA", mc.COLABounds},
+		{"FEHB inflation", mc.FEHBInflationBounds},
+	} {
+		name, bounds := check.name, check.bounds
+		if bounds != nil && bounds.Floor != nil && bounds.Ceiling != nil && bounds.Floor.GreaterThan(*bounds.Ceiling) {
+			return fmt.Errorf("monte carlo %s floor cannot exceed its ceiling", name)
+		}
+	}
+	if mc.BlockLength < 0 {
+		return fmt.Errorf("monte carlo block length cannot be negative")
+	}
+	switch mc.ReturnDistribution {
+	case "", "normal", "student_t", "historical_residuals":
+	default:
+		return fmt.Errorf("monte carlo return distribution must be normal, student_t, or historical_residuals")
+	}
+	if mc.DegreesOfFreedom != 0 && mc.DegreesOfFreedom <= 2 {
+		return fmt.Errorf("monte carlo degrees of freedom must be greater than 2")
+	}
+	if len(mc.CorrelationMatrix) > 0 {
+		if err := mc.CorrelationMatrix.Validate(); err != nil {
+			return fmt.Errorf("monte carlo %w", err)
+		}
+	}
 
 	// Validate location
 	if assumptions.CurrentLocation.State == "" {
 		return fmt.Errorf("state is required")
 	}
+	for state, rules := range assumptions.FederalRules.StateLocalTaxConfig.StateRules {
+		if _, ok := domain.NormalizeState(state); !ok {
+			return fmt.Errorf("unknown state %q in state tax rules", state)
+		}
+		if rules.Rate.LessThan(decimal.Zero) || rules.Rate.GreaterThan(decimal.NewFromFloat(0.2)) {
+			return fmt.Errorf("state tax rate for %s must be between 0 and 20%%", state)
+		}
+	}
 
 	return nil
 }
 
-// LoadRegulatoryConfig loads regulatory configuration from regulatory.yaml
+// LoadRegulatoryConfig loads regulatory configuration from regulatory.yaml (or an equivalent JSON file)
 func (ip *InputParser) LoadRegulatoryConfig(filename string) (*domain.RegulatoryConfig, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -471,7 +1446,11 @@ func (ip *InputParser) LoadRegulatoryConfig(filename string) (*domain.Regulatory
 	}
 
 	var regConfig domain.RegulatoryConfig
-	if err := yaml.Unmarshal(data, &regConfig); err != nil {
+	if DetectFormat(filename, data) == FormatJSON {
+		if err := json.Unmarshal(data, &regConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse regulatory JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal(data, &regConfig); err != nil {
 		return nil, fmt.Errorf("failed to parse regulatory YAML: %w", err)
 	}
 
@@ -497,10 +1476,13 @@ func (ip *InputParser) LoadFromFileWithRegulatory(scenarioFile, regulatoryFile s
 		return nil, fmt.Errorf("failed to load scenario config: %w", err)
 	}
 
-	// Merge regulatory config into global assumptions
+	// Merge regulatory config into global assumptions, noting values it replaces
+	before := config.GlobalAssumptions
+	before.FederalRules.StateLocalTaxConfig.StateRules = maps.Clone(before.FederalRules.StateLocalTaxConfig.StateRules)
 	if err := ip.mergeRegulatoryIntoConfig(regConfig, config); err != nil {
 		return nil, fmt.Errorf("failed to merge regulatory config: %w", err)
 	}
+	ip.Warnings = append(ip.Warnings, regulatoryMergeConflicts(&before, &config.GlobalAssumptions, regConfig)...)
 
 	return config, nil
 }
@@ -536,6 +1518,23 @@ func (ip *InputParser) mergeRegulatoryIntoConfig(regConfig *domain.RegulatoryCon
 	config.GlobalAssumptions.FederalRules.FederalTaxConfig.StandardDeductionMFJ = regConfig.FederalTax.StandardDeduction.MarriedFilingJointly
 	config.GlobalAssumptions.FederalRules.FederalTaxConfig.AdditionalStandardDeduction = regConfig.FederalTax.AdditionalDeduction65Plus
 	config.GlobalAssumptions.FederalRules.FederalTaxConfig.TaxBrackets2025 = regConfig.FederalTax.BracketsMFJ
+	if len(regConfig.FederalTax.BracketsSingle) > 0 {
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.TaxBrackets2025Single = regConfig.FederalTax.BracketsSingle
+	}
+	if len(regConfig.FederalTax.CapitalGainsBracketsMFJ) > 0 {
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.CapitalGainsBrackets = regConfig.FederalTax.CapitalGainsBracketsMFJ
+	}
+	if len(regConfig.FederalTax.CapitalGainsBracketsSingle) > 0 {
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.CapitalGainsBracketsSingle = regConfig.FederalTax.CapitalGainsBracketsSingle
+	}
+	if regConfig.FederalTax.SALTCap.GreaterThan(decimal.Zero) {
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.SALTCap = regConfig.FederalTax.SALTCap
+	}
+	if niit := regConfig.FederalTax.NetInvestmentIncomeTax; niit.Rate.GreaterThan(decimal.Zero) {
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.NIITRate = niit.Rate
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.NIITThresholdMFJ = niit.ThresholdMFJ
+		config.GlobalAssumptions.FederalRules.FederalTaxConfig.NIITThresholdSingle = niit.ThresholdSingle
+	}
 
 	// FICA Config
 	config.GlobalAssumptions.FederalRules.FICATaxConfig.SocialSecurityWageBase = regConfig.FICA.SocialSecurity.WageBase
@@ -546,12 +1545,25 @@ func (ip *InputParser) mergeRegulatoryIntoConfig(regConfig *domain.RegulatoryCon
 
 	// Medicare Config
 	config.GlobalAssumptions.FederalRules.MedicareConfig.BasePremium2025 = regConfig.Medicare.PartBBasePremium
+	config.GlobalAssumptions.FederalRules.MedicareConfig.PartDBasePremium2025 = regConfig.Medicare.PartDBasePremium
 	config.GlobalAssumptions.FederalRules.MedicareConfig.IRMAAThresholds = regConfig.Medicare.IRMAAThresholds
 
 	// Social Security Rules
 	config.GlobalAssumptions.FederalRules.SocialSecurityRules.EarlyRetirementReduction.First36MonthsRate = regConfig.SocialSecurity.BenefitAdjustments.EarlyRetirementReduction.First36MonthsRate
 	config.GlobalAssumptions.FederalRules.SocialSecurityRules.EarlyRetirementReduction.AdditionalMonthsRate = regConfig.SocialSecurity.BenefitAdjustments.EarlyRetirementReduction.AdditionalMonthsRate
 	config.GlobalAssumptions.FederalRules.SocialSecurityRules.DelayedRetirementCredit = regConfig.SocialSecurity.BenefitAdjustments.DelayedRetirementCredit
+	if wep := regConfig.SocialSecurity.BenefitAdjustments.WEPGPO; wep.Repealed != nil {
+		config.GlobalAssumptions.FederalRules.SocialSecurityRules.ApplyWEPGPO = !*wep.Repealed
+	}
+	if !regConfig.SocialSecurity.BenefitAdjustments.WEPGPO.FirstBendPoint.IsZero() {
+		config.GlobalAssumptions.FederalRules.SocialSecurityRules.WEPFirstBendPoint = regConfig.SocialSecurity.BenefitAdjustments.WEPGPO.FirstBendPoint
+	}
+	if earningsTest := regConfig.SocialSecurity.BenefitAdjustments.EarningsTest; !earningsTest.AnnualExemptAmount.IsZero() {
+		config.GlobalAssumptions.FederalRules.SocialSecurityRules.EarningsTestExemptAmount = earningsTest.AnnualExemptAmount
+	}
+	if earningsTest := regConfig.SocialSecurity.BenefitAdjustments.EarningsTest; !earningsTest.FRAYearExemptAmount.IsZero() {
+		config.GlobalAssumptions.FederalRules.SocialSecurityRules.EarningsTestFRAYearExemptAmount = earningsTest.FRAYearExemptAmount
+	}
 
 	// Social Security Tax Thresholds
 	config.GlobalAssumptions.FederalRules.SocialSecurityTaxThresholds.MarriedFilingJointly.Threshold1 = regConfig.SocialSecurity.TaxationThresholds.MarriedFilingJointly.Threshold1
@@ -572,6 +1584,23 @@ func (ip *InputParser) mergeRegulatoryIntoConfig(regConfig *domain.RegulatoryCon
 	if paRules, exists := regConfig.States["pennsylvania"]; exists {
 		config.GlobalAssumptions.FederalRules.StateLocalTaxConfig.PennsylvaniaRate = paRules.Rate
 	}
+	// Other states' rules, for participants residing outside the household's state;
+	// rules given in the scenario configuration take precedence
+	stateTaxConfig := &config.GlobalAssumptions.FederalRules.StateLocalTaxConfig
+	configured := map[string]bool{}
+	for state := range stateTaxConfig.StateRules {
+		name, _ := domain.NormalizeState(state)
+		configured[name] = true
+	}
+	for state, rules := range regConfig.States {
+		if name, ok := domain.NormalizeState(state); !ok || configured[name] {
+			continue
+		}
+		if stateTaxConfig.StateRules == nil {
+			stateTaxConfig.StateRules = make(map[string]domain.StateRules)
+		}
+		stateTaxConfig.StateRules[state] = rules
+	}
 
 	// TSP Statistical Models
 	config.GlobalAssumptions.TSPStatisticalModels.CFund.Mean = regConfig.TSPFunds.CFund.Mean
@@ -613,5 +1642,19 @@ func (ip *InputParser) mergeRegulatoryIntoConfig(regConfig *domain.RegulatoryCon
 	config.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation.FFund = regConfig.MonteCarlo.DefaultTSPAllocation.FFund
 	config.GlobalAssumptions.MonteCarloSettings.DefaultTSPAllocation.GFund = regConfig.MonteCarlo.DefaultTSPAllocation.GFund
 
+	// Bounds only override the defaults when configured
+	if regConfig.MonteCarlo.TSPReturnBounds != nil {
+		config.GlobalAssumptions.MonteCarloSettings.TSPReturnBounds = regConfig.MonteCarlo.TSPReturnBounds
+	}
+	if regConfig.MonteCarlo.InflationBounds != nil {
+		config.GlobalAssumptions.MonteCarloSettings.InflationBounds = regConfig.MonteCarlo.InflationBounds
+	}
+	if regConfig.MonteCarlo.COLABounds != nil {
+		config.GlobalAssumptions.MonteCarloSettings.COLABounds = regConfig.MonteCarlo.COLABounds
+	}
+	if regConfig.MonteCarlo.FEHBInflationBounds != nil {
+		config.GlobalAssumptions.MonteCarloSettings.FEHBInflationBounds = regConfig.MonteCarlo.FEHBInflationBounds
+	}
+
 	return nil
 }
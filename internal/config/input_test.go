@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -73,3 +74,538 @@ func TestParticipantValidation(t *testing.T) {
 }
 
 // (Legacy conversion test removed)
+
+func TestParticipantValidation_AlreadyRetired(t *testing.T) {
+	hireDate := time.Date(1988, 1, 1, 0, 0, 0, 0, time.UTC)
+	retiree := domain.Participant{
+		Name:                  "Pat",
+		IsFederal:             true,
+		BirthDate:             time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+		HireDate:              &hireDate,
+		TSPBalanceTraditional: &[]decimal.Decimal{decimal.NewFromInt(300000)}[0],
+		TSPBalanceRoth:        &[]decimal.Decimal{decimal.Zero}[0],
+		SSBenefitFRA:          decimal.NewFromInt(2500),
+		SSBenefit62:           decimal.NewFromInt(1750),
+		SSBenefit70:           decimal.NewFromInt(3100),
+		CurrentRetirement: &domain.CurrentRetirement{
+			RetirementDate: time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC),
+			AnnualAnnuity:  decimal.NewFromInt(36000),
+			FERSSupplement: decimal.NewFromInt(9000),
+		},
+	}
+
+	parser := NewInputParser()
+	// No salary, high-3 or contribution fields are needed once retired
+	if err := parser.validateParticipant(0, &retiree); err != nil {
+		t.Errorf("expected retiree to validate, got %v", err)
+	}
+
+	retiree.CurrentRetirement.AnnualAnnuity = decimal.NewFromInt(-1)
+	if err := parser.validateParticipant(0, &retiree); err == nil {
+		t.Error("expected error for negative annuity")
+	}
+}
+
+func TestParticipantValidation_SurvivorElection(t *testing.T) {
+	parser := NewInputParser()
+
+	fers := &domain.Participant{IsFederal: true, RetirementSystem: domain.RetirementSystemFERS, SurvivorBenefitElectionPercent: &[]decimal.Decimal{decimal.NewFromFloat(0.3)}[0]}
+	if err := parser.validateSurvivorElection(fers); err == nil {
+		t.Error("expected error for a FERS election other than 0, 25% or 50%")
+	}
+	*fers.SurvivorBenefitElectionPercent = decimal.NewFromFloat(0.25)
+	if err := parser.validateSurvivorElection(fers); err != nil {
+		t.Errorf("expected 25%% FERS election to validate, got %v", err)
+	}
+
+	fers.SurvivorElection = &domain.SurvivorElection{Type: domain.SurvivorElectionInsurableInterest}
+	if err := parser.validateSurvivorElection(fers); err == nil {
+		t.Error("expected error for insurable interest without a beneficiary birth date")
+	}
+
+	fers.SurvivorElection = &domain.SurvivorElection{BaseAmount: decimal.NewFromInt(10000)}
+	if err := parser.validateSurvivorElection(fers); err == nil {
+		t.Error("expected error for a partial base outside CSRS")
+	}
+
+	csrs := &domain.Participant{
+		IsFederal:                      true,
+		RetirementSystem:               domain.RetirementSystemCSRS,
+		SurvivorBenefitElectionPercent: &[]decimal.Decimal{decimal.NewFromFloat(0.55)}[0],
+		SurvivorElection:               &domain.SurvivorElection{BaseAmount: decimal.NewFromInt(10000)},
+	}
+	if err := parser.validateSurvivorElection(csrs); err != nil {
+		t.Errorf("expected CSRS partial election to validate, got %v", err)
+	}
+}
+
+func TestParticipantValidation_TSPAllocation(t *testing.T) {
+	parser := NewInputParser()
+	p := &domain.Participant{
+		Name:                  "Pat",
+		BirthDate:             time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		TSPBalanceTraditional: &[]decimal.Decimal{decimal.NewFromInt(100000)}[0],
+		TSPBalanceRoth:        &[]decimal.Decimal{decimal.Zero}[0],
+		SSBenefitFRA:          decimal.NewFromInt(2500),
+		SSBenefit62:           decimal.NewFromInt(1750),
+		SSBenefit70:           decimal.NewFromInt(3100),
+		TSPAllocation: &domain.TSPAllocation{
+			CFund: decimal.NewFromFloat(0.6),
+			FFund: decimal.NewFromFloat(0.3),
+			GFund: decimal.NewFromFloat(0.1),
+		},
+	}
+	if err := parser.validateParticipant(0, p); err != nil {
+		t.Errorf("expected allocation summing to 1 to validate, got %v", err)
+	}
+
+	p.TSPAllocation.GFund = decimal.NewFromFloat(0.2)
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for an allocation summing to 1.1")
+	}
+
+	p.TSPAllocation.GFund = decimal.NewFromFloat(0.2)
+	p.TSPAllocation.FFund = decimal.NewFromFloat(-0.1)
+	p.TSPAllocation.CFund = decimal.NewFromFloat(0.9)
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for a negative fund share")
+	}
+}
+
+func TestParticipantScenarioValidation_TSPAllocationSchedule(t *testing.T) {
+	parser := NewInputParser()
+	ps := &domain.ParticipantScenario{
+		ParticipantName: "Pat",
+		SSStartAge:      67,
+		TSPAllocationSchedule: []domain.AllocationStep{
+			{AtRetirement: true, Allocation: domain.TSPAllocation{CFund: decimal.NewFromFloat(0.7), GFund: decimal.NewFromFloat(0.3)}},
+			{Age: 70, Allocation: domain.TSPAllocation{CFund: decimal.NewFromFloat(0.5), GFund: decimal.NewFromFloat(0.5)}},
+		},
+	}
+	if err := parser.validateParticipantScenario("Pat", ps); err != nil {
+		t.Errorf("expected schedule to validate, got %v", err)
+	}
+
+	ps.TSPAllocationSchedule[1].AtRetirement = true
+	if err := parser.validateParticipantScenario("Pat", ps); err == nil {
+		t.Error("expected error for a step with both age and at_retirement")
+	}
+
+	ps.TSPAllocationSchedule[1].AtRetirement = false
+	ps.TSPAllocationSchedule[1].Allocation.GFund = decimal.NewFromFloat(0.6)
+	if err := parser.validateParticipantScenario("Pat", ps); err == nil {
+		t.Error("expected error for a step allocation summing to 1.1")
+	}
+}
+
+func TestParticipantValidation_StateResidency(t *testing.T) {
+	parser := NewInputParser()
+	p := &domain.Participant{
+		Name:           "Pat",
+		BirthDate:      time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		SSBenefitFRA:   decimal.NewFromInt(2500),
+		SSBenefit62:    decimal.NewFromInt(1750),
+		SSBenefit70:    decimal.NewFromInt(3100),
+		StateResidency: "Virginia",
+	}
+	if err := parser.validateParticipant(0, p); err != nil {
+		t.Errorf("expected a known state to validate, got %v", err)
+	}
+
+	p.StateResidency = "Atlantis"
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for an unknown state")
+	}
+}
+
+func TestParticipantValidation_RothBasis(t *testing.T) {
+	parser := NewInputParser()
+	balance, basis := decimal.NewFromInt(50000), decimal.NewFromInt(20000)
+	p := &domain.Participant{
+		Name:           "Pat",
+		BirthDate:      time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		SSBenefitFRA:   decimal.NewFromInt(2500),
+		SSBenefit62:    decimal.NewFromInt(1750),
+		SSBenefit70:    decimal.NewFromInt(3100),
+		IRABalanceRoth: &balance,
+		IRARothBasis:   &basis,
+	}
+	if err := parser.validateParticipant(0, p); err != nil {
+		t.Errorf("expected a basis within the balance to validate, got %v", err)
+	}
+
+	over := decimal.NewFromInt(60000)
+	p.IRARothBasis = &over
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for a basis above the Roth balance")
+	}
+
+	p.IRARothBasis = nil
+	p.TSPRothBasis = &basis
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for a basis without a Roth balance")
+	}
+}
+
+func TestParticipantValidation_MedicareCoverage(t *testing.T) {
+	parser := NewInputParser()
+	healthcare := domain.DefaultHealthcareConfig()
+	healthcare.MedicareCoverage = domain.MedicareCoverageFEHBSecondary
+	healthcare.OutOfPocketFEHB = decimal.NewFromInt(1500)
+	p := &domain.Participant{
+		Name:         "Pat",
+		BirthDate:    time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		SSBenefitFRA: decimal.NewFromInt(2500),
+		SSBenefit62:  decimal.NewFromInt(1750),
+		SSBenefit70:  decimal.NewFromInt(3100),
+		Healthcare:   &healthcare,
+	}
+	if err := parser.validateParticipant(0, p); err != nil {
+		t.Errorf("expected FEHB as secondary coverage to validate, got %v", err)
+	}
+
+	healthcare.MedicareCoverage = "medicare_advantage"
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for an unknown Medicare coverage")
+	}
+
+	healthcare.MedicareCoverage = domain.MedicareCoverageMedigap
+	healthcare.OutOfPocketMedigap = decimal.NewFromInt(-1)
+	if err := parser.validateParticipant(0, p); err == nil {
+		t.Error("expected error for negative out-of-pocket costs")
+	}
+}
+
+func TestParticipantScenarioValidation_TSPAnnuity(t *testing.T) {
+	parser := NewInputParser()
+	ps := &domain.ParticipantScenario{
+		ParticipantName: "Pat",
+		SSStartAge:      67,
+		TSPAnnuity:      &domain.TSPAnnuityPurchase{Amount: decimal.NewFromInt(100000), SurvivorPercent: decimal.NewFromFloat(0.5)},
+	}
+	if err := parser.validateParticipantScenario("Pat", ps); err != nil {
+		t.Errorf("expected annuity purchase to validate, got %v", err)
+	}
+
+	ps.TSPAnnuity.Percentage = decimal.NewFromFloat(0.5)
+	if err := parser.validateParticipantScenario("Pat", ps); err == nil {
+		t.Error("expected error when both amount and percentage are set")
+	}
+
+	ps.TSPAnnuity.Percentage = decimal.Zero
+	ps.TSPAnnuity.SurvivorPercent = decimal.NewFromFloat(0.75)
+	if err := parser.validateParticipantScenario("Pat", ps); err == nil {
+		t.Error("expected error for a 75% survivor benefit")
+	}
+}
+
+func TestParticipantScenarioValidation_SEPP(t *testing.T) {
+	parser := NewInputParser()
+	rate := decimal.NewFromFloat(0.045)
+	ps := &domain.ParticipantScenario{ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "sepp", SEPPInterestRate: &rate}
+	if err := parser.validateParticipantScenario("Pat", ps); err != nil {
+		t.Errorf("expected sepp strategy to validate, got %v", err)
+	}
+
+	rate = decimal.NewFromFloat(0.15)
+	if err := parser.validateParticipantScenario("Pat", ps); err == nil {
+		t.Error("expected error for a 15% SEPP interest rate")
+	}
+}
+
+func TestParticipantScenarioValidation_VPW(t *testing.T) {
+	parser := NewInputParser()
+	ps := &domain.ParticipantScenario{ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "vpw"}
+	if err := parser.validateParticipantScenario("Pat", ps); err != nil {
+		t.Errorf("expected vpw strategy to validate, got %v", err)
+	}
+
+	allocation := decimal.NewFromFloat(1.2)
+	ps.VPWStockAllocation = &allocation
+	if err := parser.validateParticipantScenario("Pat", ps); err == nil {
+		t.Error("expected error for a 120% stock allocation")
+	}
+}
+
+func TestParticipantScenarioValidation_RMDOnly(t *testing.T) {
+	ps := &domain.ParticipantScenario{ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "rmd_only"}
+	if err := NewInputParser().validateParticipantScenario("Pat", ps); err != nil {
+		t.Errorf("expected rmd_only strategy to validate, got %v", err)
+	}
+}
+
+func TestValidateSpendingTargetStrategy(t *testing.T) {
+	target := decimal.NewFromInt(80000)
+	solver := domain.ParticipantScenario{ParticipantName: "Pat", SSStartAge: 67, TSPWithdrawalStrategy: "spending_target", SpendingTarget: &target}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{"Pat": solver}}
+	household := &domain.Household{}
+	if err := validateSpendingTargetStrategy(scenario, household); err != nil {
+		t.Errorf("expected a configured spending target to validate, got %v", err)
+	}
+
+	withExpenses := &domain.Household{Expenses: []domain.ExpenseCategory{{Name: "Housing", AnnualAmount: decimal.NewFromInt(30000)}}}
+	if err := validateSpendingTargetStrategy(scenario, withExpenses); err == nil {
+		t.Error("expected an error when both spending_target and household expenses set the target")
+	}
+
+	solver.SpendingTarget = nil
+	scenario.ParticipantScenarios["Pat"] = solver
+	if err := validateSpendingTargetStrategy(scenario, household); err == nil {
+		t.Error("expected an error without a spending target")
+	}
+	if err := validateSpendingTargetStrategy(scenario, withExpenses); err != nil {
+		t.Errorf("expected household expenses to set the target, got %v", err)
+	}
+
+	scenario.ParticipantScenarios["Sam"] = domain.ParticipantScenario{ParticipantName: "Sam", TSPWithdrawalStrategy: "spending_target"}
+	if err := validateSpendingTargetStrategy(scenario, withExpenses); err == nil {
+		t.Error("expected an error when two participants solve for the target")
+	}
+}
+
+func TestValidateAutoRothConversions(t *testing.T) {
+	bracket := 22
+	household := &domain.Household{Participants: []domain.Participant{{Name: "Pat"}, {Name: "Sam"}}}
+	scenario := &domain.GenericScenario{ParticipantScenarios: map[string]domain.ParticipantScenario{
+		"Pat": {ParticipantName: "Pat"},
+		"Sam": {ParticipantName: "Sam", RothConversions: &domain.RothConversionSchedule{Conversions: []domain.RothConversion{{Year: 2027, Amount: decimal.NewFromInt(10000)}}}},
+	}}
+	ws := &domain.WithdrawalSequencingConfig{Strategy: "bracket_fill", TargetBracket: &bracket, AutoRothConversions: true}
+	if err := validateAutoRothConversions(ws, scenario, household); err != nil {
+		t.Errorf("expected conversions for the first participant to validate, got %v", err)
+	}
+
+	ws.ConversionParticipant = "Sam"
+	if err := validateAutoRothConversions(ws, scenario, household); err == nil {
+		t.Error("expected an error when the participant also schedules conversions")
+	}
+	ws.ConversionParticipant = "Lee"
+	if err := validateAutoRothConversions(ws, scenario, household); err == nil {
+		t.Error("expected an error for a participant without a scenario")
+	}
+	ws.ConversionParticipant = ""
+	ws.Strategy = "standard"
+	if err := validateAutoRothConversions(ws, scenario, household); err == nil {
+		t.Error("expected an error without the bracket_fill strategy")
+	}
+}
+
+func TestValidateMAGICeiling(t *testing.T) {
+	amount := decimal.NewFromInt(200000)
+	valid := []domain.MAGICeiling{
+		{Amount: &amount},
+		{BelowIRMAATier: 1, Buffer: decimal.NewFromInt(2000)},
+		{BelowIRMAATier: 5},
+	}
+	for _, ceiling := range valid {
+		if err := validateMAGICeiling(&ceiling); err != nil {
+			t.Errorf("expected %+v to validate, got %v", ceiling, err)
+		}
+	}
+
+	zero := decimal.Zero
+	invalid := []domain.MAGICeiling{
+		{},
+		{Amount: &amount, BelowIRMAATier: 1},
+		{Amount: &zero},
+		{BelowIRMAATier: 6},
+		{BelowIRMAATier: 1, Buffer: decimal.NewFromInt(-1)},
+	}
+	for _, ceiling := range invalid {
+		if err := validateMAGICeiling(&ceiling); err == nil {
+			t.Errorf("expected %+v to fail validation", ceiling)
+		}
+	}
+}
+
+func TestPostRunHookValidation(t *testing.T) {
+	valid := []domain.PostRunHook{
+		{Command: "./publish.sh"},
+		{URL: "https://dashboard.example.com/rpgo", Headers: map[string]string{"Authorization": "Bearer $TOKEN"}, TimeoutSeconds: 10},
+	}
+	for _, hook := range valid {
+		if err := validatePostRunHook(hook); err != nil {
+			t.Errorf("expected %s to validate, got %v", hook.Label(), err)
+		}
+	}
+
+	invalid := []domain.PostRunHook{
+		{},
+		{Command: "./publish.sh", URL: "https://dashboard.example.com"},
+		{URL: "ftp://dashboard.example.com"},
+		{Command: "./publish.sh", Headers: map[string]string{"X-Key": "1"}},
+		{Command: "./publish.sh", TimeoutSeconds: -1},
+	}
+	for _, hook := range invalid {
+		if err := validatePostRunHook(hook); err == nil {
+			t.Errorf("expected an error for %+v", hook)
+		}
+	}
+}
+
+func TestIncomeAnnuityPurchaseValidation(t *testing.T) {
+	spia := domain.IncomeAnnuityPurchase{
+		Premium: decimal.NewFromInt(200000), Source: domain.AnnuitySourceTaxable, PurchaseAge: 65, PayoutRate: decimal.NewFromFloat(0.07),
+	}
+	if err := validateIncomeAnnuityPurchase(spia); err != nil {
+		t.Errorf("expected the SPIA to validate, got %v", err)
+	}
+	dia := spia
+	dia.Source, dia.IncomeStartAge, dia.SurvivorPercent = domain.AnnuitySourceTraditional, 80, decimal.NewFromFloat(0.5)
+	if err := validateIncomeAnnuityPurchase(dia); err != nil {
+		t.Errorf("expected the deferred annuity to validate, got %v", err)
+	}
+
+	for _, mutate := range []func(*domain.IncomeAnnuityPurchase){
+		func(a *domain.IncomeAnnuityPurchase) { a.Premium = decimal.Zero },
+		func(a *domain.IncomeAnnuityPurchase) { a.Source = "roth" },
+		func(a *domain.IncomeAnnuityPurchase) { a.IncomeStartAge = 60 },
+		func(a *domain.IncomeAnnuityPurchase) { a.PayoutRate = decimal.Zero },
+		func(a *domain.IncomeAnnuityPurchase) { a.SurvivorPercent = decimal.NewFromFloat(1.5) },
+	} {
+		invalid := spia
+		mutate(&invalid)
+		if err := validateIncomeAnnuityPurchase(invalid); err == nil {
+			t.Errorf("expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestIncomeStreamValidation(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)
+	growth := decimal.NewFromFloat(0.03)
+	valid := []domain.IncomeStream{
+		{Type: domain.IncomeStreamRental, AnnualAmount: decimal.NewFromInt(24000), GrowthRate: &growth},
+		{Type: domain.IncomeStreamConsulting, AnnualAmount: decimal.NewFromInt(40000), StartDate: &start, EndDate: &end},
+		{Type: domain.IncomeStreamOther, AnnualAmount: decimal.NewFromInt(5000), TaxCharacter: domain.TaxCharacterEarned},
+	}
+	for _, stream := range valid {
+		if err := validateIncomeStream(stream); err != nil {
+			t.Errorf("expected %s to validate, got %v", stream.Label(), err)
+		}
+	}
+
+	invalid := []domain.IncomeStream{
+		{Type: "pension", AnnualAmount: decimal.NewFromInt(1000)},
+		{Type: domain.IncomeStreamRental},
+		{Type: domain.IncomeStreamRental, AnnualAmount: decimal.NewFromInt(1000), TaxCharacter: "capital"},
+		{Type: domain.IncomeStreamRental, AnnualAmount: decimal.NewFromInt(1000), StartDate: &end, EndDate: &start},
+	}
+	for _, stream := range invalid {
+		if err := validateIncomeStream(stream); err == nil {
+			t.Errorf("expected an error for %+v", stream)
+		}
+	}
+}
+
+func TestCashFlowEventValidation(t *testing.T) {
+	household := &domain.Household{Participants: []domain.Participant{{Name: "Ann"}, {Name: "Ben"}}}
+	date := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	gain := decimal.NewFromInt(100000)
+	inheritance := domain.CashFlowEvent{Name: "Inheritance", Date: date, Type: domain.CashFlowInflow, Amount: decimal.NewFromInt(250000)}
+	valid := []domain.CashFlowEvent{
+		inheritance,
+		{Name: "Home sale", Date: date, Type: domain.CashFlowInflow, Amount: decimal.NewFromInt(600000), TaxTreatment: domain.CashFlowCapitalGain, TaxableAmount: &gain},
+		{Name: "New roof", Date: date, Type: domain.CashFlowOutflow, Amount: decimal.NewFromInt(30000), Participant: "Ben", Account: domain.CashFlowAccountIRA},
+	}
+	for _, e := range valid {
+		if err := validateCashFlowEvent(e, household); err != nil {
+			t.Errorf("expected %s to validate, got %v", e.Name, err)
+		}
+	}
+
+	for _, mutate := range []func(*domain.CashFlowEvent){
+		func(e *domain.CashFlowEvent) { e.Date = time.Time{} },
+		func(e *domain.CashFlowEvent) { e.Amount = decimal.NewFromInt(-5) },
+		func(e *domain.CashFlowEvent) { e.Type = "gift" },
+		func(e *domain.CashFlowEvent) { e.Participant = "Carl" },
+		func(e *domain.CashFlowEvent) { e.Account = domain.CashFlowAccountTSP },
+		func(e *domain.CashFlowEvent) { e.TaxTreatment = "qualified_dividend" },
+		func(e *domain.CashFlowEvent) {
+			e.Type, e.TaxTreatment = domain.CashFlowOutflow, domain.CashFlowOrdinary
+		},
+	} {
+		invalid := inheritance
+		mutate(&invalid)
+		if err := validateCashFlowEvent(invalid, household); err == nil {
+			t.Errorf("expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestExpenseCategoryValidation(t *testing.T) {
+	rate := decimal.NewFromFloat(0.05)
+	valid := domain.ExpenseCategory{Name: "healthcare", AnnualAmount: decimal.NewFromInt(9000), InflationRate: &rate, StartYear: 2026, EndYear: 2040}
+	if err := validateExpenseCategory(valid); err != nil {
+		t.Fatalf("expected %s to validate, got %v", valid.Name, err)
+	}
+
+	steep := decimal.NewFromFloat(0.35)
+	for _, mutate := range []func(*domain.ExpenseCategory){
+		func(e *domain.ExpenseCategory) { e.Name = " " },
+		func(e *domain.ExpenseCategory) { e.AnnualAmount = decimal.NewFromInt(-1) },
+		func(e *domain.ExpenseCategory) { e.InflationRate = &steep },
+		func(e *domain.ExpenseCategory) { e.EndYear = 2025 },
+	} {
+		e := valid
+		mutate(&e)
+		if err := validateExpenseCategory(e); err == nil {
+			t.Errorf("expected an error for %+v", e)
+		}
+	}
+}
+
+func TestSpendingPhasesValidation(t *testing.T) {
+	household := &domain.Household{Participants: []domain.Participant{{Name: "Ann"}, {Name: "Ben"}}}
+	valid := domain.SpendingPhases{Participant: "Ben", Phases: []domain.SpendingPhase{
+		{Name: "go-go", StartAge: 62},
+		{Name: "slow-go", StartAge: 75, AnnualChange: decimal.NewFromFloat(-0.01)},
+		{Name: "no-go", StartAge: 85, AnnualChange: decimal.NewFromFloat(0.01)},
+	}}
+	if err := validateSpendingPhases(&valid, household); err != nil {
+		t.Fatalf("expected phases to validate, got %v", err)
+	}
+
+	for _, mutate := range []func(*domain.SpendingPhases){
+		func(sp *domain.SpendingPhases) { sp.Participant = "Carl" },
+		func(sp *domain.SpendingPhases) { sp.Phases = nil },
+		func(sp *domain.SpendingPhases) { sp.Phases[2].StartAge = 75 },
+		func(sp *domain.SpendingPhases) { sp.Phases[1].AnnualChange = decimal.NewFromFloat(-0.25) },
+	} {
+		sp := valid
+		sp.Phases = slices.Clone(valid.Phases)
+		mutate(&sp)
+		if err := validateSpendingPhases(&sp, household); err == nil {
+			t.Errorf("expected an error for %+v", sp)
+		}
+	}
+}
+
+func TestGuardrailRulesValidation(t *testing.T) {
+	if err := validateGuardrailRules(&domain.GuardrailRules{UpperBand: decimal.NewFromFloat(0.25), CutPercent: decimal.NewFromFloat(0.1)}); err != nil {
+		t.Fatalf("expected rules to validate, got %v", err)
+	}
+	for _, rules := range []domain.GuardrailRules{
+		{UpperBand: decimal.NewFromFloat(-0.1)},
+		{RaisePercent: decimal.NewFromFloat(0.75)},
+	} {
+		if err := validateGuardrailRules(&rules); err == nil {
+			t.Errorf("expected an error for %+v", rules)
+		}
+	}
+}
+
+func TestBucketStrategyValidation(t *testing.T) {
+	if err := validateBucketStrategy(&domain.BucketStrategy{CashYears: 3, BondYears: 7}); err != nil {
+		t.Fatalf("expected bucket sizes to validate, got %v", err)
+	}
+	for _, buckets := range []domain.BucketStrategy{
+		{CashYears: -1},
+		{BondYears: 25},
+	} {
+		if err := validateBucketStrategy(&buckets); err == nil {
+			t.Errorf("expected an error for %+v", buckets)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+
+// regulatoryMergeConflicts compares global assumptions before and after regulatory.yaml
+// was merged in and reports every value the configuration set that the merge replaced.
+// Regulatory values win except for state rules, where the configuration's take precedence.
+func regulatoryMergeConflicts(before, after *domain.GlobalAssumptions, regConfig *domain.RegulatoryConfig) []string {
+	var warnings []string
+	diffAssumption("global_assumptions", reflect.ValueOf(*before), reflect.ValueOf(*after), func(path, configured, merged string) {
+		warnings = append(warnings, fmt.Sprintf("%s is %s in the configuration and %s in regulatory.yaml; using regulatory.yaml",
+			path, configured, merged))
+	})
+
+	configured := make(map[string]domain.StateRules, len(before.FederalRules.StateLocalTaxConfig.StateRules))
+	for state, rules := range before.FederalRules.StateLocalTaxConfig.StateRules {
+		name, _ := domain.NormalizeState(state)
+		configured[name] = rules
+	}
+	for _, state := range slices.Sorted(maps.Keys(regConfig.States)) {
+		name, _ := domain.NormalizeState(state)
+		rules, ok := configured[name]
+		if !ok || sameStateRules(rules, regConfig.States[state]) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("state rules for %s differ between the configuration (%s) and regulatory.yaml (%s); using the configuration",
+			name, describeStateRules(rules), describeStateRules(regConfig.States[state])))
+	}
+	return warnings
+}
+
+// diffAssumption walks before and after in step and calls report for each value that was
+// set before the merge and changed by it. Values left unset in the configuration are
+// filled from regulatory.yaml without comment, and maps are compared by the caller.
+func diffAssumption(path string, before, after reflect.Value, report func(path, configured, merged string)) {
+	if before.IsZero() {
+		return
+	}
+	switch {
+	case before.Type() == decimalType:
+		b, a := before.Interface().(decimal.Decimal), after.Interface().(decimal.Decimal)
+		if !b.Equal(a) {
+			report(path, b.String(), a.String())
+		}
+	case before.Kind() == reflect.Struct:
+		for i := 0; i < before.NumField(); i++ {
+			field := before.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffAssumption(path+"."+fieldName(field), before.Field(i), after.Field(i), report)
+		}
+	case before.Kind() == reflect.Pointer:
+		if after.IsNil() {
+			report(path, "set", "unset")
+			return
+		}
+		diffAssumption(path, before.Elem(), after.Elem(), report)
+	case before.Kind() == reflect.Slice:
+		if !equalSlices(before, after) {
+			report(path, fmt.Sprintf("a %d-entry list", before.Len()), fmt.Sprintf("a different %d-entry list", after.Len()))
+		}
+	case before.Kind() == reflect.Map:
+		return
+	default:
+		if !reflect.DeepEqual(before.Interface(), after.Interface()) {
+			report(path, fmt.Sprint(before.Interface()), fmt.Sprint(after.Interface()))
+		}
+	}
+}
+
+// equalSlices reports whether two slices hold the same values, comparing decimals by value
+func equalSlices(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	equal := true
+	differ := func(string, string, string) { equal = false }
+	for i := 0; i < a.Len() && equal; i++ {
+		// Both directions, since values unset on one side are skipped
+		diffAssumption("", a.Index(i), b.Index(i), differ)
+		diffAssumption("", b.Index(i), a.Index(i), differ)
+	}
+	return equal
+}
+
+// fieldName returns a field's YAML key, falling back to its Go name
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+func sameStateRules(a, b domain.StateRules) bool {
+	return a.Rate.Equal(b.Rate) && a.PensionExemption == b.PensionExemption && a.SocialSecurityExemption == b.SocialSecurityExemption
+}
+
+func describeStateRules(r domain.StateRules) string {
+	return fmt.Sprintf("rate %s, pension exempt %t, Social Security exempt %t", r.Rate.String(), r.PensionExemption, r.SocialSecurityExemption)
+}
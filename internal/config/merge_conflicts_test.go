@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegulatoryMergeConflicts(t *testing.T) {
+	d := decimal.RequireFromString
+	config := &domain.Configuration{}
+	rules := &config.GlobalAssumptions.FederalRules
+	rules.FEHBConfig.PayPeriodsPerYear = 24
+	rules.FERSRules.TSPMatchingRate = d("0.050")
+	rules.FederalTaxConfig.TaxBrackets2025 = []domain.TaxBracket{{Min: d("0"), Max: d("23200"), Rate: d("0.10")}}
+	rules.StateLocalTaxConfig.StateRules = map[string]domain.StateRules{"VA": {Rate: d("0.05")}}
+
+	regConfig := &domain.RegulatoryConfig{
+		FederalTax: domain.FederalTaxRules{BracketsMFJ: []domain.TaxBracket{{Min: d("0"), Max: d("23850"), Rate: d("0.10")}}},
+		FERS:       domain.FERSRules{TSPMatchingRate: d("0.05")},
+		FEHB:       domain.FEHBRules{PayPeriodsPerYear: 26},
+		States: map[string]domain.StateRules{
+			"virginia": {Rate: d("0.0575")},
+			"ohio":     {Rate: d("0.035")},
+		},
+	}
+
+	before := config.GlobalAssumptions
+	before.FederalRules.StateLocalTaxConfig.StateRules = map[string]domain.StateRules{"VA": {Rate: d("0.05")}}
+	require.NoError(t, NewInputParser().mergeRegulatoryIntoConfig(regConfig, config))
+	warnings := regulatoryMergeConflicts(&before, &config.GlobalAssumptions, regConfig)
+
+	// The equal matching rate and values only regulatory.yaml sets are not conflicts
+	require.Len(t, warnings, 3)
+	assert.Contains(t, warnings[0], "global_assumptions.federal_rules.federal_tax_config.tax_brackets_2025 is a 1-entry list")
+	assert.Contains(t, warnings[1], "global_assumptions.federal_rules.fehb_config.pay_periods_per_year is 24 in the configuration and 26 in regulatory.yaml; using regulatory.yaml")
+	assert.Contains(t, warnings[2], "state rules for virginia differ")
+	assert.Contains(t, warnings[2], "using the configuration")
+	assert.Equal(t, 26, config.GlobalAssumptions.FederalRules.FEHBConfig.PayPeriodsPerYear)
+}
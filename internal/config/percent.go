@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// percentValue matches a scalar written as a percentage, such as "4%" or "-0.5 %"
+var percentValue = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)\s*%$`)
+
+// normalizePercentScalars rewrites percent-suffixed scalars that decode into a decimal
+// field of t as the fractions the configuration expects, so "4%" decodes exactly like
+// 0.04. The document is walked alongside t by yaml field name, so strings such as a
+// scenario named "4%" are left as written.
+func normalizePercentScalars(node *yaml.Node, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			normalizePercentScalars(child, t)
+		}
+	case yaml.ScalarNode:
+		value := strings.TrimSpace(node.Value)
+		if t != decimalType || !percentValue.MatchString(value) {
+			return
+		}
+		d, err := decimal.NewFromString(strings.TrimSpace(strings.TrimSuffix(value, "%")))
+		if err == nil {
+			node.Value = d.Div(decimal.NewFromInt(100)).String()
+			node.Tag = "!!float"
+			node.Style = 0
+		}
+	case yaml.SequenceNode:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, child := range node.Content {
+				normalizePercentScalars(child, t.Elem())
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			switch t.Kind() {
+			case reflect.Map:
+				normalizePercentScalars(node.Content[i+1], t.Elem())
+			case reflect.Struct:
+				if field, ok := yamlField(t, node.Content[i].Value); ok {
+					normalizePercentScalars(node.Content[i+1], field)
+				}
+			}
+		}
+	}
+}
+
+// yamlField returns the type of the field of struct t decoded from yaml key
+func yamlField(t reflect.Type, key string) (reflect.Type, bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if name == key {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
+
+// jsonPercentHint adds to a JSON decoding error, when a rate was written as a percent
+// string, that JSON configurations need fractions
+func jsonPercentHint(err error) error {
+	if jsonPercentError.MatchString(err.Error()) {
+		return fmt.Errorf("%w (JSON rates are fractions such as 0.04; percent strings like \"4%%\" are only read from YAML)", err)
+	}
+	return err
+}
+
+// jsonPercentError matches decimal's error for a percent string
+var jsonPercentError = regexp.MustCompile(`can't convert [^ ]*% to decimal`)
+
+// rateCheck is a rate with the range outside of which it was probably entered on the
+// wrong scale, such as 4 for 4% or 0.0015 for 15%
+type rateCheck struct {
+	name  string
+	value decimal.Decimal
+	low   decimal.Decimal
+	high  decimal.Decimal
+}
+
+// suspiciousRateWarnings reports rates that look like a percentage written as a
+// fraction or the reverse. Zero means unset and is never reported.
+func suspiciousRateWarnings(config *domain.Configuration) []string {
+	d := decimal.RequireFromString
+	ga := config.GlobalAssumptions
+	checks := []rateCheck{
+		{"global_assumptions.inflation_rate", ga.InflationRate, d("0.001"), d("0.15")},
+		{"global_assumptions.fehb_premium_inflation", ga.FEHBPremiumInflation, d("0.001"), d("0.25")},
+		{"global_assumptions.tsp_return_pre_retirement", ga.TSPReturnPreRetirement, d("0.001"), d("0.20")},
+		{"global_assumptions.tsp_return_post_retirement", ga.TSPReturnPostRetirement, d("0.001"), d("0.20")},
+		{"global_assumptions.cola_general_rate", ga.COLAGeneralRate, d("0.001"), d("0.15")},
+	}
+	if config.Household != nil {
+		for _, p := range config.Household.Participants {
+			if p.TSPContributionPercent != nil {
+				checks = append(checks, rateCheck{fmt.Sprintf("%s tsp_contribution_percent", p.Name), *p.TSPContributionPercent, d("0.01"), d("0.5")})
+			}
+		}
+	}
+	for _, s := range config.Scenarios {
+		for _, name := range slices.Sorted(maps.Keys(s.ParticipantScenarios)) {
+			if ps := s.ParticipantScenarios[name]; ps.TSPWithdrawalRate != nil {
+				checks = append(checks, rateCheck{fmt.Sprintf("scenario %q %s tsp_withdrawal_rate", s.Name, name), *ps.TSPWithdrawalRate, d("0.005"), d("0.15")})
+			}
+		}
+	}
+
+	var warnings []string
+	for _, c := range checks {
+		abs := c.value.Abs()
+		if abs.IsZero() || (abs.GreaterThanOrEqual(c.low) && abs.LessThanOrEqual(c.high)) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s is %s, which means %s%%; rates are fractions (0.04) or, in YAML, percent strings (\"4%%\")",
+			c.name, c.value.String(), c.value.Mul(decimal.NewFromInt(100)).String()))
+	}
+	return warnings
+}
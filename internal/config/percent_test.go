@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat_PercentStrings(t *testing.T) {
+	data, err := os.ReadFile("../../test/testdata/generic_example_config.yaml")
+	require.NoError(t, err)
+	yaml := strings.NewReplacer(
+		"inflation_rate: 0.025", `inflation_rate: "2.5%"`,
+		"tsp_contribution_percent: 0.12", "tsp_contribution_percent: 12%",
+	).Replace(string(data))
+
+	parser := NewInputParser()
+	config, err := parser.ParseFormat([]byte(yaml), FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "0.025", config.GlobalAssumptions.InflationRate.String())
+	assert.Equal(t, "0.12", config.Household.Participants[0].TSPContributionPercent.String())
+	assert.Empty(t, parser.Warnings)
+}
+
+func TestParseFormat_PercentStringsOnlyForDecimals(t *testing.T) {
+	data, err := os.ReadFile("../../test/testdata/generic_example_config.yaml")
+	require.NoError(t, err)
+	yaml := strings.NewReplacer(
+		`- name: "Retire 2026"`, `- name: "4%"`,
+		`tsp_withdrawal_target_monthly: 3500`, `tsp_withdrawal_target_monthly: 3500
+        tsp_withdrawal_rate: 4.5%`,
+	).Replace(string(data))
+
+	config, err := NewInputParser().ParseFormat([]byte(yaml), FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "4%", config.Scenarios[0].Name, "strings are left as written")
+	assert.Equal(t, "0.045", config.Scenarios[1].ParticipantScenarios["Alex"].TSPWithdrawalRate.String())
+}
+
+func TestParseFormat_JSONPercentStrings(t *testing.T) {
+	data, err := os.ReadFile("../../test/testdata/generic_example_config.yaml")
+	require.NoError(t, err)
+	config, err := NewInputParser().ParseFormat(data, FormatYAML)
+	require.NoError(t, err)
+	encoded, err := EncodeConfiguration(config, FormatJSON)
+	require.NoError(t, err)
+	json := strings.Replace(string(encoded), `"inflation_rate": "0.025"`, `"inflation_rate": "2.5%"`, 1)
+	require.NotEqual(t, string(encoded), json)
+
+	_, err = NewInputParser().ParseFormat([]byte(json), FormatJSON)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JSON rates are fractions")
+}
+
+func TestParseFormat_SuspiciousRateWarnings(t *testing.T) {
+	data, err := os.ReadFile("../../test/testdata/generic_example_config.yaml")
+	require.NoError(t, err)
+	yaml := strings.NewReplacer(
+		"cola_general_rate: 0.025", "cola_general_rate: 2.5",
+		"tsp_contribution_percent: 0.10", `tsp_contribution_percent: "0.15%"`,
+	).Replace(string(data))
+
+	parser := NewInputParser()
+	_, err = parser.ParseFormat([]byte(yaml), FormatYAML)
+	require.NoError(t, err)
+	require.Len(t, parser.Warnings, 2)
+	assert.Contains(t, parser.Warnings[0], "cola_general_rate is 2.5, which means 250%")
+	assert.Contains(t, parser.Warnings[1], "tsp_contribution_percent is 0.0015, which means 0.15%")
+}
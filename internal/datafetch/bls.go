@@ -0,0 +1,151 @@
+package datafetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// BLSAPIURL is the BLS public data API's time series endpoint
+const BLSAPIURL = "https://api.bls.gov/publicAPI/v2/timeseries/data/"
+
+// CPISeries is CPI-U, all items in U.S. city average, not seasonally adjusted
+const CPISeries = "CUUR0000SA0"
+
+// InflationFile is the inflation series' file, relative to the data path
+var InflationFile = filepath.Join("inflation", "cpi-annual.csv")
+
+// blsYearsPerRequest is the span of years the BLS API returns per request without and
+// with a registration key
+var blsYearsPerRequest = map[bool]int{false: 10, true: 20}
+
+// blsRequest is the BLS API's request body
+type blsRequest struct {
+	SeriesID        []string `json:"seriesid"`
+	StartYear       string   `json:"startyear"`
+	EndYear         string   `json:"endyear"`
+	AnnualAverage   bool     `json:"annualaverage"`
+	RegistrationKey string   `json:"registrationkey,omitempty"`
+}
+
+// blsResponse is the part of the BLS API's response the fetcher reads
+type blsResponse struct {
+	Status  string   `json:"status"`
+	Message []string `json:"message"`
+	Results struct {
+		Series []struct {
+			SeriesID string `json:"seriesID"`
+			Data     []struct {
+				Year   string `json:"year"`
+				Period string `json:"period"`
+				Value  string `json:"value"`
+			} `json:"data"`
+		} `json:"series"`
+	} `json:"Results"`
+}
+
+// FetchCPI downloads CPI-U's annual average index for startYear through endYear from the
+// BLS API, in as many requests as the API's span of years needs. The key is the
+// optional BLS registration key, which allows longer spans and more requests a day.
+func FetchCPI(ctx context.Context, client *http.Client, apiURL, key string, startYear, endYear int) (map[int]decimal.Decimal, error) {
+	span := blsYearsPerRequest[key != ""]
+	averages := map[int]decimal.Decimal{}
+	for from := startYear; from <= endYear; from += span {
+		to := min(from+span-1, endYear)
+		body, err := json.Marshal(blsRequest{
+			SeriesID:        []string{CPISeries},
+			StartYear:       strconv.Itoa(from),
+			EndYear:         strconv.Itoa(to),
+			AnnualAverage:   true,
+			RegistrationKey: key,
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		response, err := send(client, req, "CPI-U")
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := ParseCPIResponse(response)
+		if err != nil {
+			return nil, fmt.Errorf("reading CPI-U from %s: %w", req.URL.Host, err)
+		}
+		for year, average := range chunk {
+			averages[year] = average
+		}
+	}
+	return averages, nil
+}
+
+// ParseCPIResponse reads a BLS API response for CPI-U and returns each year's annual
+// average index. A year without BLS's annual average (period M13) but with all twelve
+// months gets their mean; a year still under way gets nothing.
+func ParseCPIResponse(body []byte) (map[int]decimal.Decimal, error) {
+	var response blsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("not a BLS API response: %w", err)
+	}
+	if response.Status != "REQUEST_SUCCEEDED" {
+		return nil, fmt.Errorf("BLS API request not processed (%s): %s", response.Status, strings.Join(response.Message, "; "))
+	}
+
+	averages := map[int]decimal.Decimal{}
+	monthly := map[int][]decimal.Decimal{}
+	for _, series := range response.Results.Series {
+		if series.SeriesID != CPISeries {
+			continue
+		}
+		for _, point := range series.Data {
+			year, err := strconv.Atoi(point.Year)
+			if err != nil {
+				continue
+			}
+			value, err := decimal.NewFromString(point.Value)
+			if err != nil {
+				continue // BLS marks missing values with a dash
+			}
+			switch {
+			case point.Period == "M13":
+				averages[year] = value
+			case strings.HasPrefix(point.Period, "M"):
+				monthly[year] = append(monthly[year], value)
+			}
+		}
+	}
+	for year, months := range monthly {
+		if _, ok := averages[year]; ok || len(months) != 12 {
+			continue
+		}
+		averages[year] = decimal.Sum(months[0], months[1:]...).Div(decimal.NewFromInt(12))
+	}
+	if len(averages) == 0 {
+		return nil, fmt.Errorf("no CPI-U annual averages in the response")
+	}
+	return averages, nil
+}
+
+// InflationRates converts CPI-U annual averages to each year's inflation rate: its
+// average over the year before's, as the inflation series holds
+func InflationRates(averages map[int]decimal.Decimal) map[int]decimal.Decimal {
+	rates := map[int]decimal.Decimal{}
+	for year, average := range averages {
+		previous, ok := averages[year-1]
+		if !ok || previous.IsZero() {
+			continue
+		}
+		rates[year] = average.Div(previous).Sub(decimal.NewFromInt(1))
+	}
+	return rates
+}
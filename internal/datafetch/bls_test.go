@@ -0,0 +1,83 @@
+package datafetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blsBody builds a BLS API response with the given annual averages, and every month of
+// monthlyYear at the given index
+func blsBody(averages map[int]string, monthlyYear int, monthly string) string {
+	var data []string
+	for year, value := range averages {
+		data = append(data, fmt.Sprintf(`{"year":"%d","period":"M13","periodName":"Annual","value":"%s","footnotes":[{}]}`, year, value))
+	}
+	for month := 1; monthlyYear != 0 && month <= 12; month++ {
+		data = append(data, fmt.Sprintf(`{"year":"%d","period":"M%02d","value":"%s"}`, monthlyYear, month, monthly))
+	}
+	return fmt.Sprintf(`{"status":"REQUEST_SUCCEEDED","message":[],"Results":{"series":[{"seriesID":"%s","data":[%s]}]}}`,
+		CPISeries, strings.Join(data, ","))
+}
+
+func TestParseCPIResponse(t *testing.T) {
+	averages, err := ParseCPIResponse([]byte(blsBody(map[int]string{2021: "270.970", 2022: "292.655"}, 2023, "304.702")))
+	require.NoError(t, err)
+	require.Len(t, averages, 3)
+	assert.True(t, averages[2022].Equal(decimal.RequireFromString("292.655")))
+	// A year without an annual average gets the mean of its months
+	assert.True(t, averages[2023].Equal(decimal.RequireFromString("304.702")))
+
+	rates := InflationRates(averages)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "0.080", rates[2022].StringFixed(3))
+	assert.Equal(t, "0.041", rates[2023].StringFixed(3))
+
+	_, err = ParseCPIResponse([]byte(`{"status":"REQUEST_NOT_PROCESSED","message":["daily threshold reached"]}`))
+	assert.ErrorContains(t, err, "daily threshold reached")
+
+	_, err = ParseCPIResponse([]byte("<html>maintenance</html>"))
+	assert.ErrorContains(t, err, "not a BLS API response")
+}
+
+func TestFetchCPI(t *testing.T) {
+	var requests []blsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request blsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests = append(requests, request)
+		start, _ := strconv.Atoi(request.StartYear)
+		end, _ := strconv.Atoi(request.EndYear)
+		averages := map[int]string{}
+		for year := start; year <= end; year++ {
+			averages[year] = strconv.Itoa(100 + year - 2000)
+		}
+		_, _ = w.Write([]byte(blsBody(averages, 0, "")))
+	}))
+	defer server.Close()
+
+	// Without a key the years are requested ten at a time
+	averages, err := FetchCPI(context.Background(), server.Client(), server.URL, "", 2000, 2024)
+	require.NoError(t, err)
+	assert.Len(t, averages, 25)
+	require.Len(t, requests, 3)
+	assert.Equal(t, "2010", requests[1].StartYear)
+	assert.Equal(t, "2019", requests[1].EndYear)
+	assert.True(t, requests[0].AnnualAverage)
+	assert.Equal(t, []string{CPISeries}, requests[0].SeriesID)
+
+	requests = nil
+	_, err = FetchCPI(context.Background(), server.Client(), server.URL, "key", 2000, 2024)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "key", requests[0].RegistrationKey)
+}
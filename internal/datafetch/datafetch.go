@@ -0,0 +1,190 @@
+// Package datafetch downloads the historical series kept in rpgo's data directory from
+// their official sources: TSP share prices from tsp.gov, CPI-U from the BLS API and
+// COLAs from SSA. It turns each into annual rates and merges them into the CSV files the
+// historical data manager reads.
+package datafetch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultTimeout bounds a download that sets no timeout
+const DefaultTimeout = 60 * time.Second
+
+// maxResponseBytes bounds the response a download reads
+const maxResponseBytes = 32 << 20
+
+// SeriesUpdate reports the changes made to one series' file
+type SeriesUpdate struct {
+	Series string `json:"series"`
+	File   string `json:"file"`
+	// Added holds the years that were not in the file before
+	Added []int `json:"added"`
+	// Updated holds the years whose rate was replaced
+	Updated  []int `json:"updated"`
+	LastYear int   `json:"lastYear"`
+}
+
+// Changed reports whether the update added or replaced any year
+func (u SeriesUpdate) Changed() bool {
+	return len(u.Added)+len(u.Updated) > 0
+}
+
+// UpdateSeries merges annual rates into a Year,Value file, creating it if it is
+// missing. Years already in the file keep their rate unless replace is set. The file is
+// written whole, so a failure leaves it unchanged.
+func UpdateSeries(series, file string, rates map[int]decimal.Decimal, replace bool) (SeriesUpdate, error) {
+	update := SeriesUpdate{Series: series, File: file}
+	rows, header, err := readSeries(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return update, err
+	}
+	if header == nil {
+		header = []string{"Year", "Return"}
+	}
+
+	for year, rate := range rates {
+		rate = rate.Round(4)
+		existing, found := rows[year]
+		switch {
+		case !found:
+			update.Added = append(update.Added, year)
+		case replace && !existing.Equal(rate):
+			update.Updated = append(update.Updated, year)
+		default:
+			continue
+		}
+		rows[year] = rate
+	}
+	slices.Sort(update.Added)
+	slices.Sort(update.Updated)
+	if len(rows) > 0 {
+		update.LastYear = slices.Max(slices.Collect(maps.Keys(rows)))
+	}
+
+	if update.Changed() {
+		if err := writeSeries(file, header, rows); err != nil {
+			return update, err
+		}
+	}
+	return update, nil
+}
+
+// SeriesLastYear returns the last year in a Year,Value file, or 0 when it is missing or
+// empty
+func SeriesLastYear(file string) int {
+	rows, _, err := readSeries(file)
+	if err != nil || len(rows) == 0 {
+		return 0
+	}
+	return slices.Max(slices.Collect(maps.Keys(rows)))
+}
+
+// readSeries reads a Year,Value file, skipping rows that don't parse as the historical
+// data loader does
+func readSeries(file string) (map[int]decimal.Decimal, []string, error) {
+	rows := map[int]decimal.Decimal{}
+	f, err := os.Open(file)
+	if err != nil {
+		return rows, nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return rows, nil, nil
+	}
+	if err != nil {
+		return rows, nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if len(record) < 2 {
+			continue
+		}
+		year, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			continue
+		}
+		value, err := decimal.NewFromString(strings.TrimSpace(record[1]))
+		if err != nil {
+			continue
+		}
+		rows[year] = value
+	}
+	return rows, header, nil
+}
+
+// writeSeries writes a Year,Value file in year order through a temporary file, so a
+// reader never sees it half written
+func writeSeries(file string, header []string, rows map[int]decimal.Decimal) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".tmp-"+filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := csv.NewWriter(tmp)
+	writer.Write(header)
+	for _, year := range slices.Sorted(maps.Keys(rows)) {
+		writer.Write([]string{strconv.Itoa(year), rows[year].String()})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// send performs a request and returns the response body, or an error naming what was
+// being downloaded when the request fails or the response isn't a success
+func send(client *http.Client, req *http.Request, what string) ([]byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	req.Header.Set("User-Agent", "rpgo")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s returned %s", what, req.URL.Host, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", what, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package datafetch
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// SSACOLAURL is SSA's page of cost-of-living adjustments by year
+const SSACOLAURL = "https://www.ssa.gov/oact/COLA/colaseries.html"
+
+// COLAFile is the COLA series' file, relative to the data path
+var COLAFile = filepath.Join("cola", "ss-cola-annual.csv")
+
+var (
+	tableRowPattern  = regexp.MustCompile(`(?is)<tr[^>]*>(.*?)</tr>`)
+	tableCellPattern = regexp.MustCompile(`(?is)<t[dh][^>]*>(.*?)</t[dh]>`)
+	tagPattern       = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// FetchCOLA downloads SSA's COLA announcements and returns each year's COLA as a rate
+func FetchCOLA(ctx context.Context, client *http.Client, colaURL string) (map[int]decimal.Decimal, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, colaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := send(client, req, "COLAs")
+	if err != nil {
+		return nil, err
+	}
+	colas, err := ParseCOLATable(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("reading COLAs from %s: %w", req.URL.Host, err)
+	}
+	return colas, nil
+}
+
+// ParseCOLATable reads the COLAs from the tables of SSA's COLA page: rows whose first
+// cell is a year and second a percentage. Years are as SSA lists them, the year the
+// COLA was announced and took effect for December benefits.
+func ParseCOLATable(page string) (map[int]decimal.Decimal, error) {
+	colas := map[int]decimal.Decimal{}
+	for _, row := range tableRowPattern.FindAllStringSubmatch(page, -1) {
+		cells := tableCellPattern.FindAllStringSubmatch(row[1], -1)
+		if len(cells) < 2 {
+			continue
+		}
+		year, err := strconv.Atoi(cellText(cells[0][1]))
+		if err != nil || year < 1950 || year > 2200 {
+			continue
+		}
+		percent, err := decimal.NewFromString(strings.TrimSuffix(cellText(cells[1][1]), "%"))
+		if err != nil {
+			continue
+		}
+		colas[year] = percent.Div(decimal.NewFromInt(100))
+	}
+	if len(colas) == 0 {
+		return nil, fmt.Errorf("no COLA table found")
+	}
+	return colas, nil
+}
+
+// cellText returns a table cell's text without markup, footnote marks or spacing
+func cellText(cell string) string {
+	text := html.UnescapeString(tagPattern.ReplaceAllString(cell, ""))
+	text = strings.TrimRight(strings.TrimSpace(text), "*†")
+	return strings.Join(strings.Fields(text), "")
+}
@@ -0,0 +1,58 @@
+package datafetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const colaPage = `<html><body>
+<h2>Special COLAs</h2>
+<table>
+<tr><th>Date</th><th>COLA</th></tr>
+<tr><td>Sept 1950</td><td>77.0</td></tr>
+</table>
+<h2>Automatic COLAs</h2>
+<table class="t-border">
+<tr><th>Year</th><th>COLA</th></tr>
+<tr><td>2021</td><td>5.9</td></tr>
+<tr>
+  <td class="year">2022</td>
+  <td>8.7</td>
+</tr>
+<tr><td><b>2023</b></td><td>3.2&nbsp;%</td></tr>
+<tr><td>2024</td><td>2.5*</td></tr>
+</table>
+</body></html>`
+
+func TestParseCOLATable(t *testing.T) {
+	colas, err := ParseCOLATable(colaPage)
+	require.NoError(t, err)
+	require.Len(t, colas, 4)
+	assert.True(t, colas[2022].Equal(decimal.NewFromFloat(0.087)))
+	assert.True(t, colas[2023].Equal(decimal.NewFromFloat(0.032)))
+	assert.True(t, colas[2024].Equal(decimal.NewFromFloat(0.025)))
+
+	_, err = ParseCOLATable("<html><body>Page not found</body></html>")
+	assert.ErrorContains(t, err, "no COLA table")
+}
+
+func TestFetchCOLA(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(colaPage))
+	}))
+	defer server.Close()
+
+	colas, err := FetchCOLA(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+	assert.True(t, colas[2021].Equal(decimal.NewFromFloat(0.059)))
+
+	server.Close()
+	_, err = FetchCOLA(context.Background(), nil, server.URL)
+	assert.ErrorContains(t, err, "downloading COLAs")
+}
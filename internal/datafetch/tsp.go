@@ -0,0 +1,213 @@
+package datafetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TSPPriceURL is tsp.gov's share price history download
+const TSPPriceURL = "https://www.tsp.gov/data/fund-price-history.csv"
+
+// Funds are the TSP individual funds whose returns the data directory holds
+var Funds = []string{"C", "S", "I", "F", "G"}
+
+// PricePoint is a fund's share price on one day
+type PricePoint struct {
+	Date  time.Time
+	Price decimal.Decimal
+}
+
+// FundFile returns the return file of a fund, relative to the data path
+func FundFile(fund string) string {
+	return filepath.Join("tsp-returns", strings.ToLower(fund)+"-fund-annual.csv")
+}
+
+// FetchTSPPrices downloads the share prices of the individual funds from start to end
+func FetchTSPPrices(ctx context.Context, client *http.Client, priceURL string, start, end time.Time) (map[string][]PricePoint, error) {
+	u, err := url.Parse(priceURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price history URL %q: %w", priceURL, err)
+	}
+	query := u.Query()
+	query.Set("startdate", start.Format("2006-01-02"))
+	query.Set("enddate", end.Format("2006-01-02"))
+	query.Set("Lfunds", "0")
+	query.Set("InvFunds", "1")
+	query.Set("download", "1")
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/csv")
+	body, err := send(client, req, "share prices")
+	if err != nil {
+		return nil, err
+	}
+	prices, err := ParsePrices(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("reading share prices from %s: %w", u.Host, err)
+	}
+	return prices, nil
+}
+
+// ParsePrices reads a tsp.gov price history: a Date column followed by a column of
+// prices per fund. It returns each individual fund's prices in date order; days without
+// a price for a fund are skipped.
+func ParsePrices(r io.Reader) (map[string][]PricePoint, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	dateColumn := -1
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name == "DATE" {
+			dateColumn = i
+			continue
+		}
+		for _, fund := range Funds {
+			if name == fund+" FUND" {
+				columns[fund] = i
+			}
+		}
+	}
+	if dateColumn < 0 || len(columns) == 0 {
+		return nil, fmt.Errorf("not a TSP price history: expected a Date column and fund columns, got %q", strings.Join(header, ","))
+	}
+
+	prices := map[string][]PricePoint{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read price row: %w", err)
+		}
+		if dateColumn >= len(record) {
+			continue
+		}
+		date, err := parseDate(record[dateColumn])
+		if err != nil {
+			continue // Skip rows with invalid date
+		}
+		for fund, column := range columns {
+			if column >= len(record) {
+				continue
+			}
+			price, err := decimal.NewFromString(strings.TrimSpace(record[column]))
+			if err != nil || !price.IsPositive() {
+				continue // Skip days the fund has no price
+			}
+			prices[fund] = append(prices[fund], PricePoint{Date: date, Price: price})
+		}
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no share prices found")
+	}
+	for fund := range prices {
+		slices.SortFunc(prices[fund], func(a, b PricePoint) int { return a.Date.Compare(b.Date) })
+	}
+	return prices, nil
+}
+
+// parseDate reads the date formats tsp.gov has used in its price history
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02", "Jan 2, 2006", "01/02/2006", "1/2/2006"} {
+		if date, err := time.Parse(layout, s); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
+
+// AnnualReturns converts a fund's share prices to calendar-year returns, each year's
+// last price over the year before's. Share prices are net of fund expenses, as TSP's
+// published returns are. A year counts only once the prices reach its last weekday, or
+// a later year.
+func AnnualReturns(prices []PricePoint) map[int]decimal.Decimal {
+	yearEnd := map[int]PricePoint{}
+	for _, point := range prices {
+		if last, ok := yearEnd[point.Date.Year()]; !ok || !point.Date.Before(last.Date) {
+			yearEnd[point.Date.Year()] = point
+		}
+	}
+	latest := 0
+	for year := range yearEnd {
+		latest = max(latest, year)
+	}
+
+	returns := map[int]decimal.Decimal{}
+	for year, end := range yearEnd {
+		previous, ok := yearEnd[year-1]
+		if !ok {
+			continue
+		}
+		if year == latest && end.Date.Before(lastWeekday(year)) {
+			continue // The year is still under way
+		}
+		returns[year] = end.Price.Div(previous.Price).Sub(decimal.NewFromInt(1))
+	}
+	return returns
+}
+
+// lastWeekday returns the last Monday-to-Friday day of the year
+func lastWeekday(year int) time.Time {
+	day := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// FundsLastYear returns the year through which every fund's return file under dataPath
+// has returns, or 0 when any file is missing or empty
+func FundsLastYear(dataPath string) int {
+	last := 0
+	for i, fund := range Funds {
+		year := SeriesLastYear(filepath.Join(dataPath, FundFile(fund)))
+		if year == 0 {
+			return 0
+		}
+		if i == 0 || year < last {
+			last = year
+		}
+	}
+	return last
+}
+
+// UpdateReturns merges annual returns into the funds' return files under dataPath, as
+// UpdateSeries does for each
+func UpdateReturns(dataPath string, returns map[string]map[int]decimal.Decimal, replace bool) ([]SeriesUpdate, error) {
+	var updates []SeriesUpdate
+	for _, fund := range Funds {
+		fundReturns, ok := returns[fund]
+		if !ok {
+			continue
+		}
+		update, err := UpdateSeries(fund+" Fund", filepath.Join(dataPath, FundFile(fund)), fundReturns, replace)
+		if err != nil {
+			return updates, err
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
@@ -0,0 +1,135 @@
+package datafetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const priceHistory = `Date,L Income,G Fund,F Fund,C Fund,S Fund,I Fund
+2024-12-31,25.10,18.50,20.10,90.00,85.00,42.00
+2024-12-30,25.00,18.49,20.05,89.00,84.00,41.50
+2023-12-29,24.00,17.70,19.80,72.00,74.00,40.00
+2023-06-30,23.00,17.50,19.50,70.00,,39.00
+2025-06-30,25.50,18.90,20.50,95.00,86.00,47.00
+`
+
+func TestParsePrices(t *testing.T) {
+	prices, err := ParsePrices(strings.NewReader("\ufeff" + priceHistory))
+	require.NoError(t, err)
+	assert.Len(t, prices, 5)
+
+	// Each fund's prices come back in date order
+	c := prices["C"]
+	require.Len(t, c, 5)
+	assert.Equal(t, time.Date(2023, time.June, 30, 0, 0, 0, 0, time.UTC), c[0].Date)
+	assert.True(t, c[4].Price.Equal(decimal.NewFromInt(95)))
+
+	// Days without a price are skipped
+	assert.Len(t, prices["S"], 4)
+
+	_, err = ParsePrices(strings.NewReader("<html><body>Service unavailable</body></html>"))
+	assert.ErrorContains(t, err, "not a TSP price history")
+}
+
+func TestAnnualReturns(t *testing.T) {
+	prices, err := ParsePrices(strings.NewReader(priceHistory))
+	require.NoError(t, err)
+
+	// 2024 runs from the 2023 year-end price; 2025 is still under way
+	returns := AnnualReturns(prices["C"])
+	require.Len(t, returns, 1)
+	assert.True(t, returns[2024].Equal(decimal.NewFromFloat(0.25)), returns[2024].String())
+
+	// A year whose prices reach its last weekday is complete
+	returns = AnnualReturns([]PricePoint{
+		{Date: time.Date(2021, time.December, 31, 0, 0, 0, 0, time.UTC), Price: decimal.NewFromInt(100)},
+		{Date: time.Date(2022, time.December, 30, 0, 0, 0, 0, time.UTC), Price: decimal.NewFromInt(80)},
+	})
+	assert.True(t, returns[2022].Equal(decimal.NewFromFloat(-0.2)), returns[2022].String())
+}
+
+func TestUpdateReturns(t *testing.T) {
+	dataPath := t.TempDir()
+	cFile := filepath.Join(dataPath, FundFile("C"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(cFile), 0755))
+	require.NoError(t, os.WriteFile(cFile, []byte("Year,Return\n2022,-0.182\n2023,0.264\n"), 0644))
+
+	returns := map[string]map[int]decimal.Decimal{
+		"C": {2023: decimal.NewFromFloat(0.2629), 2024: decimal.NewFromFloat(0.25)},
+		"G": {2024: decimal.NewFromFloat(0.0452)},
+	}
+	updates, err := UpdateReturns(dataPath, returns, false)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+
+	// Years on file keep their return; new years are added in order
+	assert.Equal(t, []int{2024}, updates[0].Added)
+	assert.Empty(t, updates[0].Updated)
+	assert.Equal(t, 2024, updates[0].LastYear)
+	assert.Equal(t, "C Fund", updates[0].Series)
+	content, err := os.ReadFile(cFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Year,Return\n2022,-0.182\n2023,0.264\n2024,0.25\n", string(content))
+
+	// A missing fund file is created
+	content, err = os.ReadFile(filepath.Join(dataPath, FundFile("G")))
+	require.NoError(t, err)
+	assert.Equal(t, "Year,Return\n2024,0.0452\n", string(content))
+
+	// Replacing rewrites years whose return differs
+	updates, err = UpdateReturns(dataPath, returns, true)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2023}, updates[0].Updated)
+	assert.Empty(t, updates[0].Added)
+	assert.Empty(t, updates[1].Updated)
+	content, err = os.ReadFile(cFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Year,Return\n2022,-0.182\n2023,0.2629\n2024,0.25\n", string(content))
+
+	// Every fund needs a file for the data to have a last year
+	assert.Equal(t, 0, FundsLastYear(dataPath))
+	for _, fund := range []string{"S", "I", "F"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dataPath, FundFile(fund)), []byte("Year,Return\n2023,0.1\n"), 0644))
+	}
+	assert.Equal(t, 2023, FundsLastYear(dataPath))
+}
+
+func TestFetchTSPPrices(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte(priceHistory))
+	}))
+	defer server.Close()
+
+	start := time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	prices, err := FetchTSPPrices(context.Background(), server.Client(), server.URL, start, end)
+	require.NoError(t, err)
+	assert.Len(t, prices["G"], 5)
+	assert.Equal(t, []string{"2023-12-01"}, query["startdate"])
+	assert.Equal(t, []string{"2025-07-01"}, query["enddate"])
+
+	// Failed downloads are errors, not empty data
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	_, err = FetchTSPPrices(context.Background(), failing.Client(), failing.URL, start, end)
+	assert.ErrorContains(t, err, "503")
+
+	failing.Close()
+	_, err = FetchTSPPrices(context.Background(), nil, failing.URL, start, end)
+	assert.ErrorContains(t, err, "downloading share prices")
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// Roth conversion optimizer goals
+const (
+	ConversionGoalAfterTaxWealth = "after_tax_wealth"
+	ConversionGoalLifetimeTax    = "lifetime_tax"
+)
+
+// ConversionOptimizerOptions bounds the search for a multi-year Roth conversion schedule.
+// Conversions fill at most the TargetBracket (in percent, e.g. 22) of each year through
+// Horizon and stop short of the next IRMAA tier. TerminalTaxRate is the tax assumed on
+// traditional balances left at the end of the projection when valuing after-tax wealth.
+type ConversionOptimizerOptions struct {
+	Participant     string
+	TargetBracket   int
+	Horizon         int
+	Goal            string
+	Step            decimal.Decimal
+	TerminalTaxRate decimal.Decimal
+}
+
+// ConversionOptimization is the conversion schedule the optimizer recommends, with the
+// scenario's outcome without conversions and with the schedule
+type ConversionOptimization struct {
+	Scenario      string                  `json:"scenario"`
+	Participant   string                  `json:"participant"`
+	Goal          string                  `json:"goal"`
+	TargetBracket int                     `json:"targetBracket"`
+	FirstYear     int                     `json:"firstYear"`
+	Horizon       int                     `json:"horizon"`
+	Schedule      RothConversionSchedule  `json:"schedule"`
+	Baseline      ConversionOutcomeTotals `json:"baseline"`
+	Optimized     ConversionOutcomeTotals `json:"optimized"`
+	Projections   int                     `json:"projections"` // projections run by the search
+}
+
+// ConversionOutcomeTotals summarizes a projection for the conversion optimizer.
+// AfterTaxWealth is lifetime net income plus ending Roth and taxable balances and the
+// ending traditional balances net of the terminal tax rate.
+type ConversionOutcomeTotals struct {
+	AfterTaxWealth           decimal.Decimal `json:"afterTaxWealth"`
+	LifetimeTax              decimal.Decimal `json:"lifetimeTax"` // federal, state, local, FICA and IRMAA
+	LifetimeIRMAA            decimal.Decimal `json:"lifetimeIrmaa"`
+	LifetimePremiumTaxCredit decimal.Decimal `json:"lifetimePremiumTaxCredit"` // marketplace credits before Medicare, which conversions can lose
+	TotalConversions         decimal.Decimal `json:"totalConversions"`
+	EndingTraditional        decimal.Decimal `json:"endingTraditional"`
+	EndingRoth               decimal.Decimal `json:"endingRoth"`
+	EndingTaxable            decimal.Decimal `json:"endingTaxable"`
+}
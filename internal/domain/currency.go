@@ -0,0 +1,42 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// Currency rounding modes
+const (
+	CurrencyRoundingHalfUp  = "half_up" // halves round away from zero, e.g. $0.125 to $0.13
+	CurrencyRoundingBankers = "bankers" // halves round to the even cent, e.g. $0.125 to $0.12
+)
+
+// CurrencyPlaces is the number of decimal places dollar amounts are shown with
+const CurrencyPlaces = 2
+
+// CurrencyRounding is how every report and command rounds dollar amounts; the
+// --currency-rounding flag sets it. Calculations and the totals built from them keep
+// full precision, and amounts are rounded only when they are presented, so a summary
+// total and the detailed rows it sums round from the same values.
+var CurrencyRounding = CurrencyRoundingHalfUp
+
+// ValidCurrencyRounding reports whether mode is a currency rounding mode
+func ValidCurrencyRounding(mode string) bool {
+	return mode == CurrencyRoundingHalfUp || mode == CurrencyRoundingBankers
+}
+
+// RoundCurrency rounds a dollar amount to cents under CurrencyRounding
+func RoundCurrency(amount decimal.Decimal) decimal.Decimal {
+	if CurrencyRounding == CurrencyRoundingBankers {
+		return amount.RoundBank(CurrencyPlaces)
+	}
+	return amount.Round(CurrencyPlaces)
+}
+
+// FormatAmount formats a dollar amount in cents without a currency sign, e.g. 1234.5 as
+// "1234.50", for CSV and other machine-readable output
+func FormatAmount(amount decimal.Decimal) string {
+	return RoundCurrency(amount).StringFixed(CurrencyPlaces)
+}
+
+// FormatCurrency formats a dollar amount in cents, e.g. 1234.5 as "$1234.50"
+func FormatCurrency(amount decimal.Decimal) string {
+	return "$" + FormatAmount(amount)
+}
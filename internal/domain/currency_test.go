@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCurrency(t *testing.T) {
+	assert.Equal(t, "$1234.50", FormatCurrency(decimal.NewFromFloat(1234.5)))
+	assert.Equal(t, "0.13", FormatAmount(decimal.NewFromFloat(0.125)), "halves round away from zero")
+	assert.Equal(t, "-0.13", FormatAmount(decimal.NewFromFloat(-0.125)))
+	assert.Equal(t, "0.13", FormatAmount(decimal.NewFromFloat(0.1250001)))
+
+	defer func(mode string) { CurrencyRounding = mode }(CurrencyRounding)
+	CurrencyRounding = CurrencyRoundingBankers
+	assert.Equal(t, "0.12", FormatAmount(decimal.NewFromFloat(0.125)), "halves round to the even cent")
+	assert.Equal(t, "0.14", FormatAmount(decimal.NewFromFloat(0.135)))
+	assert.Equal(t, "0.13", FormatAmount(decimal.NewFromFloat(0.1250001)), "only exact halves are affected")
+	assert.Equal(t, "$-0.12", FormatCurrency(decimal.NewFromFloat(-0.125)))
+
+	assert.True(t, ValidCurrencyRounding("bankers"))
+	assert.False(t, ValidCurrencyRounding("truncate"))
+}
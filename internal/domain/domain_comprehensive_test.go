@@ -18,12 +18,17 @@ func TestGenericScenario_DeepCopy(t *testing.T) {
 				SSStartAge:                 62,
 				TSPWithdrawalStrategy:      "fixed_amount",
 				TSPWithdrawalTargetMonthly: &[]decimal.Decimal{decimal.NewFromInt(3000)}[0],
+				PensionElection:            "lump_sum",
+				MedicareCoverage:           "medigap",
+				PartBEnrollmentAge:         67,
 			},
 			"Bob": {
 				ParticipantName:       "Bob",
 				SSStartAge:            67,
-				TSPWithdrawalStrategy: "fixed_percentage",
+				TSPWithdrawalStrategy: "guardrails",
 				TSPWithdrawalRate:     &[]decimal.Decimal{decimal.NewFromFloat(0.04)}[0],
+				Guardrails:            &GuardrailRules{UpperBand: decimal.NewFromFloat(0.25)},
+				Buckets:               &BucketStrategy{CashYears: 3},
 			},
 		},
 		Mortality: &GenericScenarioMortality{
@@ -41,6 +46,9 @@ func TestGenericScenario_DeepCopy(t *testing.T) {
 			TargetBracket: &[]int{22}[0],
 			BracketBuffer: &[]int{1000}[0],
 		},
+		CashFlowEvents:  []CashFlowEvent{{Name: "New roof", Type: CashFlowOutflow, Amount: decimal.NewFromInt(30000)}},
+		SpendingPhases:  &SpendingPhases{Phases: []SpendingPhase{{Name: "slow-go", StartAge: 75}}},
+		ProjectionYears: 40,
 	}
 
 	// Test deep copy
@@ -54,6 +62,9 @@ func TestGenericScenario_DeepCopy(t *testing.T) {
 	assert.Equal(t, len(original.ParticipantScenarios), len(copied.ParticipantScenarios))
 	assert.Equal(t, original.ParticipantScenarios["Alice"].ParticipantName, copied.ParticipantScenarios["Alice"].ParticipantName)
 	assert.Equal(t, original.ParticipantScenarios["Alice"].SSStartAge, copied.ParticipantScenarios["Alice"].SSStartAge)
+	assert.Equal(t, "lump_sum", copied.ParticipantScenarios["Alice"].PensionElection)
+	assert.Equal(t, "medigap", copied.ParticipantScenarios["Alice"].MedicareCoverage)
+	assert.Equal(t, 67, copied.ParticipantScenarios["Alice"].PartBEnrollmentAge)
 
 	// Verify mortality is copied
 	assert.NotSame(t, original.Mortality, copied.Mortality)
@@ -64,6 +75,17 @@ func TestGenericScenario_DeepCopy(t *testing.T) {
 	assert.Equal(t, original.WithdrawalSequencing.Strategy, copied.WithdrawalSequencing.Strategy)
 	assert.Equal(t, *original.WithdrawalSequencing.TargetBracket, *copied.WithdrawalSequencing.TargetBracket)
 
+	// Verify guardrails and buckets are copied
+	assert.NotSame(t, original.ParticipantScenarios["Bob"].Guardrails, copied.ParticipantScenarios["Bob"].Guardrails)
+	assert.Equal(t, original.ParticipantScenarios["Bob"].Guardrails, copied.ParticipantScenarios["Bob"].Guardrails)
+	assert.NotSame(t, original.ParticipantScenarios["Bob"].Buckets, copied.ParticipantScenarios["Bob"].Buckets)
+
+	// Verify cash flow events, spending phases and the horizon are copied
+	assert.Equal(t, original.CashFlowEvents, copied.CashFlowEvents)
+	assert.NotSame(t, original.SpendingPhases, copied.SpendingPhases)
+	assert.Equal(t, original.SpendingPhases, copied.SpendingPhases)
+	assert.Equal(t, 40, copied.ProjectionYears)
+
 	// Test that modifications to copy don't affect original
 	copied.Name = "Modified Scenario"
 	// Note: Can't modify map values directly, so we'll test a different approach
@@ -376,10 +398,10 @@ func TestGlobalAssumptions_GenerateAssumptions(t *testing.T) {
 
 	// Should generate a list of assumption strings
 	assert.NotEmpty(t, generated)
-	assert.Contains(t, generated, "General COLA (FERS pension & SS): 2.0% annually")
-	assert.Contains(t, generated, "FEHB premium inflation: 6.0% annually")
-	assert.Contains(t, generated, "TSP growth pre-retirement: 6.0% annually")
-	assert.Contains(t, generated, "TSP growth post-retirement: 4.0% annually")
+	assert.Contains(t, generated, "General COLA (FERS pension & SS): 2.00% annually")
+	assert.Contains(t, generated, "FEHB premium inflation: 6.00% annually")
+	assert.Contains(t, generated, "TSP growth pre-retirement: 6.00% annually")
+	assert.Contains(t, generated, "TSP growth post-retirement: 4.00% annually")
 	assert.Contains(t, generated, "Social Security wage base indexing: ~5% annually (2025 est: $168,600)")
 	assert.Contains(t, generated, "Tax brackets: 2025 levels held constant (no inflation indexing)")
 }
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -38,8 +41,6 @@ type Employee struct {
 	SSNLast4     string `yaml:"ssn_last4,omitempty" json:"ssn_last4,omitempty"`
 }
 
-// (Legacy Robert/Dawn specific structs removed in generic refactor)
-
 // MortalitySpec defines a deterministic death event by date or by age (one may be supplied)
 type MortalitySpec struct {
 	DeathDate *time.Time `yaml:"death_date,omitempty" json:"death_date,omitempty"`
@@ -49,10 +50,17 @@ type MortalitySpec struct {
 // MortalityAssumptions defines how to treat finances after a death event (Phase 1 limited subset)
 type MortalityAssumptions struct {
 	SurvivorSpendingFactor decimal.Decimal `yaml:"survivor_spending_factor" json:"survivor_spending_factor"`
-	TSPSpousalTransfer     string          `yaml:"tsp_spousal_transfer" json:"tsp_spousal_transfer"` // merge|separate (Phase 1 supports only merge & separate=ignore merge)
-	FilingStatusSwitch     string          `yaml:"filing_status_switch" json:"filing_status_switch"` // next_year|immediate (not yet applied in Phase 1)
+	TSPSpousalTransfer     string          `yaml:"tsp_spousal_transfer" json:"tsp_spousal_transfer"`                             // merge|separate (Phase 1 supports only merge & separate=ignore merge)
+	FilingStatusSwitch     string          `yaml:"filing_status_switch" json:"filing_status_switch"`                             // next_year|immediate (not yet applied in Phase 1)
+	FEHBSurvivorEnrollment string          `yaml:"fehb_survivor_enrollment,omitempty" json:"fehb_survivor_enrollment,omitempty"` // self_only (default) | unchanged
 }
 
+// Survivor FEHB enrollment after the first death
+const (
+	FEHBSurvivorEnrollmentSelfOnly  = "self_only"
+	FEHBSurvivorEnrollmentUnchanged = "unchanged"
+)
+
 // GlobalAssumptions contains all the global parameters for calculations
 type GlobalAssumptions struct {
 	InflationRate           decimal.Decimal `yaml:"inflation_rate" json:"inflation_rate"`
@@ -63,9 +71,35 @@ type GlobalAssumptions struct {
 	ProjectionYears         int             `yaml:"projection_years" json:"projection_years"`
 	CurrentLocation         Location        `yaml:"current_location" json:"current_location"`
 
+	// Projection step size for the first SubAnnualYears years ("annual", "semi_annual",
+	// "quarterly" or "monthly"); later years are always projected annually. SubAnnualYears
+	// defaults to 5. Only TSP growth is stepped: salary and pension proration, RMD timing
+	// and the IRMAA lookback stay annual whatever the granularity.
+	ProjectionGranularity string `yaml:"projection_granularity,omitempty" json:"projection_granularity,omitempty"`
+	SubAnnualYears        int    `yaml:"sub_annual_years,omitempty" json:"sub_annual_years,omitempty"`
+
+	// TSP growth model: "flat" (default) grows every balance at the pre/post-retirement
+	// returns above; "fund" weights each fund's expected return by the participant's
+	// allocation or Lifecycle fund glide path for the year
+	TSPReturnModel string `yaml:"tsp_return_model,omitempty" json:"tsp_return_model,omitempty"`
+	// Expected annual return of each fund under the "fund" model. When unset, historical
+	// means are used if data is loaded, otherwise the statistical model means.
+	TSPFundReturns *TSPFundReturns `yaml:"tsp_fund_returns,omitempty" json:"tsp_fund_returns,omitempty"`
+	// Simulated market conditions for each projection year, replacing the rates above in
+	// the years it covers; set by the FERS Monte Carlo to run a multi-year market path
+	MarketPath []MarketYear `yaml:"-" json:"market_path,omitempty"`
+
 	// TSP Contribution Policy Configuration
 	TSPContribPolicy string `yaml:"tsp_contrib_policy" json:"tsp_contrib_policy"` // "continue_until_retirement" or "zero_in_retirement_view"
 
+	// Directory holding historical and lifecycle fund data; relative paths resolve
+	// against the configuration file's directory. Empty uses the standard search path.
+	HistoricalDataPath string `yaml:"historical_data_path,omitempty" json:"historical_data_path,omitempty"`
+
+	// FEHB plan catalog replacing the bundled one; a relative path resolves against the
+	// configuration file's directory
+	FEHBPlanCatalog string `yaml:"fehb_plan_catalog,omitempty" json:"fehb_plan_catalog,omitempty"`
+
 	// Monte Carlo Configuration
 	MonteCarloSettings MonteCarloSettings `yaml:"monte_carlo_settings" json:"monte_carlo_settings"`
 
@@ -82,10 +116,10 @@ type GlobalAssumptions struct {
 // GenerateAssumptions creates dynamic assumptions list from actual config values
 func (ga *GlobalAssumptions) GenerateAssumptions() []string {
 	return []string{
-		fmt.Sprintf("General COLA (FERS pension & SS): %.1f%% annually", ga.COLAGeneralRate.Mul(decimal.NewFromInt(100)).InexactFloat64()),
-		fmt.Sprintf("FEHB premium inflation: %.1f%% annually", ga.FEHBPremiumInflation.Mul(decimal.NewFromInt(100)).InexactFloat64()),
-		fmt.Sprintf("TSP growth pre-retirement: %.1f%% annually", ga.TSPReturnPreRetirement.Mul(decimal.NewFromInt(100)).InexactFloat64()),
-		fmt.Sprintf("TSP growth post-retirement: %.1f%% annually", ga.TSPReturnPostRetirement.Mul(decimal.NewFromInt(100)).InexactFloat64()),
+		fmt.Sprintf("General COLA (FERS pension & SS): %s annually", FormatRate(ga.COLAGeneralRate)),
+		fmt.Sprintf("FEHB premium inflation: %s annually", FormatRate(ga.FEHBPremiumInflation)),
+		fmt.Sprintf("TSP growth pre-retirement: %s annually", FormatRate(ga.TSPReturnPreRetirement)),
+		fmt.Sprintf("TSP growth post-retirement: %s annually", FormatRate(ga.TSPReturnPostRetirement)),
 		"Social Security wage base indexing: ~5% annually (2025 est: $168,600)",
 		"Tax brackets: 2025 levels held constant (no inflation indexing)",
 	}
@@ -111,6 +145,116 @@ type MonteCarloSettings struct {
 
 	// Default TSP asset allocation (used when individual allocations not specified)
 	DefaultTSPAllocation TSPAllocation `yaml:"default_tsp_allocation" json:"default_tsp_allocation"`
+
+	// Bounds on generated values; unset bounds use the defaults
+	TSPReturnBounds     *RateBounds `yaml:"tsp_return_bounds,omitempty" json:"tsp_return_bounds,omitempty"`         // Default: floor -0.50
+	InflationBounds     *RateBounds `yaml:"inflation_bounds,omitempty" json:"inflation_bounds,omitempty"`           // Default: floor -0.05
+	COLABounds          *RateBounds `yaml:"cola_bounds,omitempty" json:"cola_bounds,omitempty"`                     // Default: floor -0.02 (SS COLA is never negative: use floor 0)
+	FEHBInflationBounds *RateBounds `yaml:"fehb_inflation_bounds,omitempty" json:"fehb_inflation_bounds,omitempty"` // Default: floor 0
+
+	// Consecutive historical years drawn together (block bootstrap) to preserve serial
+	// correlation; 0 or 1 draws every year independently
+	BlockLength int `yaml:"block_length,omitempty" json:"block_length,omitempty"`
+
+	// Correlations between the statistically drawn series, ordered as MarketFactors.
+	// When unset it is estimated from loaded historical data, or a built-in matrix is used.
+	CorrelationMatrix CorrelationMatrix `yaml:"correlation_matrix,omitempty" json:"correlation_matrix,omitempty"`
+
+	// Distribution of statistical draws: "normal" (default), "student_t" for fat tails with
+	// DegreesOfFreedom (default 5, must exceed 2), or "historical_residuals" to resample
+	// standardized deviations of whole historical years
+	ReturnDistribution string `yaml:"return_distribution,omitempty" json:"return_distribution,omitempty"`
+	DegreesOfFreedom   int    `yaml:"degrees_of_freedom,omitempty" json:"degrees_of_freedom,omitempty"`
+}
+
+// MarketFactors orders the rows and columns of a market correlation matrix
+var MarketFactors = []string{"C", "S", "I", "F", "G", "inflation"}
+
+// CorrelationMatrix holds the pairwise correlations of the market factors
+type CorrelationMatrix [][]decimal.Decimal
+
+// Validate checks that the matrix is a square, symmetric correlation matrix over the
+// market factors that can be factored for drawing correlated values
+func (m CorrelationMatrix) Validate() error {
+	if len(m) != len(MarketFactors) {
+		return fmt.Errorf("correlation matrix must have %d rows (%s), got %d", len(MarketFactors), strings.Join(MarketFactors, ", "), len(m))
+	}
+	one := decimal.NewFromInt(1)
+	for i, row := range m {
+		if len(row) != len(MarketFactors) {
+			return fmt.Errorf("correlation matrix row %s must have %d values, got %d", MarketFactors[i], len(MarketFactors), len(row))
+		}
+		if !row[i].Equal(one) {
+			return fmt.Errorf("correlation of %s with itself must be 1", MarketFactors[i])
+		}
+		for j, v := range row {
+			if v.Abs().GreaterThan(one) {
+				return fmt.Errorf("correlation of %s and %s must be between -1 and 1", MarketFactors[i], MarketFactors[j])
+			}
+			if !v.Equal(m[j][i]) {
+				return fmt.Errorf("correlation matrix must be symmetric: %s/%s differs from %s/%s", MarketFactors[i], MarketFactors[j], MarketFactors[j], MarketFactors[i])
+			}
+		}
+	}
+	if _, err := m.Cholesky(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Cholesky returns the lower-triangular factor L with L·Lᵀ equal to the matrix, which
+// turns independent standard normal draws into correlated ones
+func (m CorrelationMatrix) Cholesky() ([][]float64, error) {
+	n := len(m)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := m[i][j].InexactFloat64()
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, fmt.Errorf("correlation matrix is not positive definite")
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, nil
+}
+
+// MarketYear holds one year of simulated market conditions
+type MarketYear struct {
+	InflationRate decimal.Decimal `json:"inflation_rate"`
+	COLARate      decimal.Decimal `json:"cola_rate"`
+	FEHBInflation decimal.Decimal `json:"fehb_inflation"`
+	FundReturns   TSPFundReturns  `json:"fund_returns"`
+	// MonthlyFundReturns are the year's fund returns month by month, January first, when
+	// history has all twelve; a projection with monthly granularity grows balances by them
+	MonthlyFundReturns []TSPFundReturns `json:"monthly_fund_returns,omitempty"`
+	// HistoricalYear is the year of history the conditions come from (zero for statistical draws)
+	HistoricalYear int `json:"historical_year,omitempty"`
+}
+
+// RateBounds limits a generated rate; a nil floor or ceiling leaves that side unbounded
+type RateBounds struct {
+	Floor   *decimal.Decimal `yaml:"floor,omitempty" json:"floor,omitempty"`
+	Ceiling *decimal.Decimal `yaml:"ceiling,omitempty" json:"ceiling,omitempty"`
+}
+
+// Clamp returns rate limited to the bounds
+func (b RateBounds) Clamp(rate decimal.Decimal) decimal.Decimal {
+	if b.Floor != nil && rate.LessThan(*b.Floor) {
+		return *b.Floor
+	}
+	if b.Ceiling != nil && rate.GreaterThan(*b.Ceiling) {
+		return *b.Ceiling
+	}
+	return rate
 }
 
 // TSPAllocation represents asset allocation across TSP funds
@@ -122,12 +266,46 @@ type TSPAllocation struct {
 	GFund decimal.Decimal `yaml:"g_fund" json:"g_fund"` // Default: 0.00 (0% - Government Securities)
 }
 
+// Total returns the sum of the fund shares
+func (a TSPAllocation) Total() decimal.Decimal {
+	return a.CFund.Add(a.SFund).Add(a.IFund).Add(a.FFund).Add(a.GFund)
+}
+
+// TSPFundReturns holds an annual return for each TSP fund
+type TSPFundReturns struct {
+	CFund decimal.Decimal `yaml:"c_fund" json:"c_fund"`
+	SFund decimal.Decimal `yaml:"s_fund" json:"s_fund"`
+	IFund decimal.Decimal `yaml:"i_fund" json:"i_fund"`
+	FFund decimal.Decimal `yaml:"f_fund" json:"f_fund"`
+	GFund decimal.Decimal `yaml:"g_fund" json:"g_fund"`
+}
+
 // TSPLifecycleFund represents a TSP Lifecycle Fund with age-based allocation changes
 type TSPLifecycleFund struct {
 	FundName       string                              `yaml:"fund_name" json:"fund_name"`             // e.g., "L2030", "L2035", "L2040", "L Income"
 	AllocationData map[string][]TSPAllocationDataPoint `yaml:"allocation_data" json:"allocation_data"` // Quarterly allocation data
 }
 
+// LifecycleIncomeFund is the name of the TSP L Income fund
+const LifecycleIncomeFund = "L Income"
+
+// TargetYear returns the fund's target date year, or zero for L Income. ok is
+// false when FundName is not an L fund ("L2030" through "L2070" or "L Income").
+func (f *TSPLifecycleFund) TargetYear() (year int, ok bool) {
+	name := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(f.FundName), " ", ""))
+	if name == "LINCOME" {
+		return 0, true
+	}
+	if len(name) != 5 || name[0] != 'L' {
+		return 0, false
+	}
+	year, err := strconv.Atoi(name[1:])
+	if err != nil || year < 2030 || year > 2070 || year%5 != 0 {
+		return 0, false
+	}
+	return year, true
+}
+
 // TSPAllocationDataPoint represents allocation at a specific date
 type TSPAllocationDataPoint struct {
 	Date       string        `yaml:"date" json:"date"` // Format: "YYYY-MM-DD"
@@ -185,6 +363,16 @@ type SocialSecurityRules struct {
 
 	// Delayed retirement credit: 2/3 of 1% per month (8% per year)
 	DelayedRetirementCredit decimal.Decimal `yaml:"delayed_retirement_credit" json:"delayed_retirement_credit"` // Default: 0.0066667 (2/3 of 1%)
+
+	// Windfall Elimination Provision and Government Pension Offset. The Social Security
+	// Fairness Act repealed both for benefits payable after December 2023; set ApplyWEPGPO
+	// to model the pre-repeal rules for participants with a non-covered pension.
+	ApplyWEPGPO       bool            `yaml:"apply_wep_gpo" json:"apply_wep_gpo"`
+	WEPFirstBendPoint decimal.Decimal `yaml:"wep_first_bend_point" json:"wep_first_bend_point"` // Default: 1226 (2025 PIA first bend point)
+
+	// Retirement earnings test for benefits claimed before FRA
+	EarningsTestExemptAmount        decimal.Decimal `yaml:"earnings_test_exempt_amount" json:"earnings_test_exempt_amount"`                   // Default: 23400 ($1 withheld per $2 above)
+	EarningsTestFRAYearExemptAmount decimal.Decimal `yaml:"earnings_test_fra_year_exempt_amount" json:"earnings_test_fra_year_exempt_amount"` // Default: 62160 ($1 per $3 above, in the year FRA is reached)
 }
 
 // FERSRules contains FERS-specific rules and matching rates
@@ -204,6 +392,18 @@ type FederalTaxConfig struct {
 	// Tax brackets for 2025 (updated annually)
 	TaxBrackets2025       []TaxBracket `yaml:"tax_brackets_2025" json:"tax_brackets_2025"`
 	TaxBrackets2025Single []TaxBracket `yaml:"tax_brackets_2025_single" json:"tax_brackets_2025_single"`
+
+	// Long-term capital gains and qualified dividend brackets (0/15/20% stack on top of ordinary income)
+	CapitalGainsBrackets       []TaxBracket `yaml:"capital_gains_brackets" json:"capital_gains_brackets"`
+	CapitalGainsBracketsSingle []TaxBracket `yaml:"capital_gains_brackets_single" json:"capital_gains_brackets_single"`
+
+	// Net Investment Income Tax on the lesser of investment income or MAGI above the threshold
+	NIITRate            decimal.Decimal `yaml:"niit_rate" json:"niit_rate"`                         // Default: 0.038
+	NIITThresholdMFJ    decimal.Decimal `yaml:"niit_threshold_mfj" json:"niit_threshold_mfj"`       // Default: 250000 (not inflation indexed)
+	NIITThresholdSingle decimal.Decimal `yaml:"niit_threshold_single" json:"niit_threshold_single"` // Default: 200000 (not inflation indexed)
+
+	// State and local tax deduction cap applied when itemizing
+	SALTCap decimal.Decimal `yaml:"salt_cap" json:"salt_cap"` // Default: 10000
 }
 
 // TaxBracket represents a federal tax bracket
@@ -220,6 +420,11 @@ type StateLocalTaxConfig struct {
 
 	// Upper Makefield Township EIT (local tax)
 	UpperMakefieldEITRate decimal.Decimal `yaml:"upper_makefield_eit_rate" json:"upper_makefield_eit_rate"` // Default: 0.01 (1% on earned income)
+
+	// Flat income tax rules for other states, keyed by state name or postal code. Used
+	// for participants residing outside the household's state; regulatory.yaml states
+	// are merged in.
+	StateRules map[string]StateRules `yaml:"state_rules,omitempty" json:"state_rules,omitempty"`
 }
 
 // FICATaxConfig contains FICA tax configuration (updated annually)
@@ -241,15 +446,19 @@ type MedicareConfig struct {
 	// Base Part B premium
 	BasePremium2025 decimal.Decimal `yaml:"base_premium_2025" json:"base_premium_2025"` // Default: 185.00 (2025)
 
+	// Part D base beneficiary premium, the national average monthly plan premium
+	PartDBasePremium2025 decimal.Decimal `yaml:"part_d_base_premium_2025" json:"part_d_base_premium_2025"` // Default: 36.78 (2025)
+
 	// IRMAA (Income-Related Monthly Adjustment Amount) thresholds
 	IRMAAThresholds []MedicareIRMAAThreshold `yaml:"irmaa_thresholds" json:"irmaa_thresholds"`
 }
 
 // MedicareIRMAAThreshold represents an IRMAA income threshold and corresponding surcharge
 type MedicareIRMAAThreshold struct {
-	IncomeThresholdSingle decimal.Decimal `yaml:"income_threshold_single" json:"income_threshold_single"` // For single filers
-	IncomeThresholdJoint  decimal.Decimal `yaml:"income_threshold_joint" json:"income_threshold_joint"`   // For married filing jointly
-	MonthlySurcharge      decimal.Decimal `yaml:"monthly_surcharge" json:"monthly_surcharge"`             // Additional monthly premium per person
+	IncomeThresholdSingle decimal.Decimal `yaml:"income_threshold_single" json:"income_threshold_single"`   // For single filers
+	IncomeThresholdJoint  decimal.Decimal `yaml:"income_threshold_joint" json:"income_threshold_joint"`     // For married filing jointly
+	MonthlySurcharge      decimal.Decimal `yaml:"monthly_surcharge" json:"monthly_surcharge"`               // Additional monthly premium per person
+	MonthlySurchargePartD decimal.Decimal `yaml:"monthly_surcharge_part_d" json:"monthly_surcharge_part_d"` // Additional monthly Part D premium per person
 }
 
 // FEHBConfig contains FEHB (Federal Employees Health Benefits) configuration
@@ -288,6 +497,7 @@ type Configuration struct {
 	Household         *Household        `yaml:"household" json:"household"`
 	Scenarios         []GenericScenario `yaml:"scenarios" json:"scenarios"`
 	GlobalAssumptions GlobalAssumptions `yaml:"global_assumptions" json:"global_assumptions"`
+	Hooks             *HooksConfig      `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
 // Age calculates the age of the employee at a given date
@@ -413,8 +623,9 @@ func (e *Employee) TotalAnnualTSPContribution() decimal.Decimal {
 // Participant represents a generic participant in retirement planning (replaces Employee)
 // This struct supports both federal and non-federal employees
 type Participant struct {
-	Name      string    `yaml:"name" json:"name"`
-	BirthDate time.Time `yaml:"birth_date" json:"birth_date"`
+	Name        string    `yaml:"name" json:"name"`                                     // stable key used by scenarios and per-participant maps
+	DisplayName string    `yaml:"display_name,omitempty" json:"display_name,omitempty"` // optional label shown in reports
+	BirthDate   time.Time `yaml:"birth_date" json:"birth_date"`
 
 	// Federal employment fields (optional for non-federal employees)
 	IsFederal     bool             `yaml:"is_federal" json:"is_federal"`
@@ -433,28 +644,90 @@ type Participant struct {
 	TaxableAccountBalance *decimal.Decimal `yaml:"taxable_account_balance,omitempty" json:"taxable_account_balance,omitempty"`
 	TaxableAccountBasis   *decimal.Decimal `yaml:"taxable_account_basis,omitempty" json:"taxable_account_basis,omitempty"` // Cost basis used to approximate capital gains
 
+	// Traditional and Roth IRAs held outside the TSP (optional, any participant). Annual
+	// contributions are made in years with salary, traditional first, up to the IRA limit.
+	IRABalanceTraditional      *decimal.Decimal `yaml:"ira_balance_traditional,omitempty" json:"ira_balance_traditional,omitempty"`
+	IRABalanceRoth             *decimal.Decimal `yaml:"ira_balance_roth,omitempty" json:"ira_balance_roth,omitempty"`
+	IRAContributionTraditional *decimal.Decimal `yaml:"ira_contribution_traditional,omitempty" json:"ira_contribution_traditional,omitempty"` // assumed deductible
+	IRAContributionRoth        *decimal.Decimal `yaml:"ira_contribution_roth,omitempty" json:"ira_contribution_roth,omitempty"`
+
+	// Roth contribution basis and five-year clocks (optional). The basis is the part of a
+	// Roth balance that was contributed, withdrawn tax and penalty free at any age (default:
+	// the whole balance). The start year is the year of the first Roth contribution, which
+	// starts the five-year clock for qualified distributions (default: already met).
+	TSPRothBasis     *decimal.Decimal `yaml:"tsp_roth_basis,omitempty" json:"tsp_roth_basis,omitempty"`
+	TSPRothStartYear int              `yaml:"tsp_roth_start_year,omitempty" json:"tsp_roth_start_year,omitempty"`
+	IRARothBasis     *decimal.Decimal `yaml:"ira_roth_basis,omitempty" json:"ira_roth_basis,omitempty"`
+	IRARothStartYear int              `yaml:"ira_roth_start_year,omitempty" json:"ira_roth_start_year,omitempty"`
+
+	// Employer defined contribution plan outside federal service, such as a 401(k) or
+	// 403(b) (optional, typically for non-federal participants)
+	EmployerPlan *EmployerPlan `yaml:"employer_plan,omitempty" json:"employer_plan,omitempty"`
+
+	// Recurring income outside employment and retirement plans, such as rent, consulting
+	// fees or royalties (optional)
+	OtherIncome []IncomeStream `yaml:"other_income,omitempty" json:"other_income,omitempty"`
+
 	// Social Security (all participants should have this)
 	SSBenefitFRA decimal.Decimal `yaml:"ss_benefit_fra" json:"ss_benefit_fra"`
 	SSBenefit62  decimal.Decimal `yaml:"ss_benefit_62" json:"ss_benefit_62"`
 	SSBenefit70  decimal.Decimal `yaml:"ss_benefit_70" json:"ss_benefit_70"`
+	// ClaimSpousalBenefit tops up this participant's own benefit to as much as half of
+	// the spouse's benefit at FRA once both have filed
+	ClaimSpousalBenefit bool `yaml:"claim_spousal_benefit,omitempty" json:"claim_spousal_benefit,omitempty"`
 
 	// FEHB fields (only for federal employees)
 	FEHBPremiumPerPayPeriod *decimal.Decimal `yaml:"fehb_premium_per_pay_period,omitempty" json:"fehb_premium_per_pay_period,omitempty"`
 	IsPrimaryFEHBHolder     bool             `yaml:"is_primary_fehb_holder" json:"is_primary_fehb_holder"`
+	// Self only premium for the same plan, paid once the enrollment drops to self only at a
+	// spouse's death (optional; defaults to a typical share of the enrolled premium)
+	FEHBSelfOnlyPremiumPerPayPeriod *decimal.Decimal `yaml:"fehb_self_only_premium_per_pay_period,omitempty" json:"fehb_self_only_premium_per_pay_period,omitempty"`
+	// When FEHB coverage began (optional; defaults to the hire date). FEHB continues into
+	// retirement only after five years of coverage, or coverage since first eligible.
+	FEHBCoverageStartDate *time.Time `yaml:"fehb_coverage_start_date,omitempty" json:"fehb_coverage_start_date,omitempty"`
+	// FEHBPlan names the plan enrollment in the FEHB plan catalog, by enrollment code or
+	// name (e.g. "Blue Cross Basic Self+1"), which supplies the premiums above when they are
+	// not given and the premium growth below
+	FEHBPlan string `yaml:"fehb_plan,omitempty" json:"fehb_plan,omitempty"`
+	// Yearly growth of this participant's FEHB premium, in place of the FEHB premium
+	// inflation assumption
+	FEHBPremiumGrowth *decimal.Decimal `yaml:"fehb_premium_growth,omitempty" json:"fehb_premium_growth,omitempty"`
 
 	// FERS pension fields (only for federal employees)
 	SurvivorBenefitElectionPercent *decimal.Decimal `yaml:"survivor_benefit_election_percent,omitempty" json:"survivor_benefit_election_percent,omitempty"`
-	SickLeaveHours                 *decimal.Decimal `yaml:"sick_leave_hours,omitempty" json:"sick_leave_hours,omitempty"`
+	// SurvivorElection refines SurvivorBenefitElectionPercent with the election type and
+	// the inputs CSRS partial and insurable interest reductions depend on (optional)
+	SurvivorElection *SurvivorElection `yaml:"survivor_election,omitempty" json:"survivor_election,omitempty"`
+	SickLeaveHours   *decimal.Decimal  `yaml:"sick_leave_hours,omitempty" json:"sick_leave_hours,omitempty"`
+	// CurrentRetirement marks a participant already retired when the projection starts.
+	// Salary, TSP contributions and the annuity computation are skipped in favor of the
+	// benefits actually being paid.
+	CurrentRetirement *CurrentRetirement `yaml:"current_retirement,omitempty" json:"current_retirement,omitempty"`
+	// MilitaryDeposit credits military service bought back with a deposit (optional)
+	MilitaryDeposit *MilitaryDeposit `yaml:"military_deposit,omitempty" json:"military_deposit,omitempty"`
+	// RetirementSystem is fers (default), csrs, or csrs_offset
+	RetirementSystem string `yaml:"retirement_system,omitempty" json:"retirement_system,omitempty"`
+	// SpecialProvision marks special category service (law_enforcement, firefighter,
+	// air_traffic_controller) retiring under the enhanced FERS rules
+	SpecialProvision string `yaml:"special_provision,omitempty" json:"special_provision,omitempty"`
 
 	// External pension for non-federal employees
 	ExternalPension *ExternalPension `yaml:"external_pension,omitempty" json:"external_pension,omitempty"`
 
+	// Pension from work not covered by Social Security (CSRS, CSRS Offset, some state plans).
+	// Only affects benefits when SocialSecurityRules.ApplyWEPGPO is set.
+	NonCoveredPension *NonCoveredPension `yaml:"non_covered_pension,omitempty" json:"non_covered_pension,omitempty"`
+
 	// Employment end date (for scenarios where someone stops working but hasn't retired)
 	EmploymentEndDate *time.Time `yaml:"employment_end_date,omitempty" json:"employment_end_date,omitempty"`
 
 	// Healthcare configuration (optional)
 	Healthcare *HealthcareConfig `yaml:"healthcare,omitempty" json:"healthcare,omitempty"`
 
+	// StateResidency is the state this participant is taxed as a resident of, as a postal
+	// code or name (optional; defaults to global_assumptions.current_location.state)
+	StateResidency string `yaml:"state_residency,omitempty" json:"state_residency,omitempty"`
+
 	// Optional fields for additional context
 	PayPlanGrade string `yaml:"pay_plan_grade,omitempty" json:"pay_plan_grade,omitempty"`
 	SSNLast4     string `yaml:"ssn_last4,omitempty" json:"ssn_last4,omitempty"`
@@ -466,12 +739,304 @@ type ExternalPension struct {
 	StartAge        int             `yaml:"start_age" json:"start_age"`
 	COLAAdjustment  decimal.Decimal `yaml:"cola_adjustment" json:"cola_adjustment"`
 	SurvivorBenefit decimal.Decimal `yaml:"survivor_benefit" json:"survivor_benefit"` // Percentage (0-1)
+	// Plan reduction taken from the retiree's benefit to pay for SurvivorBenefit (0-1)
+	SurvivorReduction decimal.Decimal `yaml:"survivor_reduction,omitempty" json:"survivor_reduction,omitempty"`
+
+	// LumpSum is the plan's lump-sum offer in place of the annuity (optional). Election picks
+	// annuity (default) or lump_sum; a scenario may override it. The lump sum is rolled over
+	// at retirement to the IRA (default) or the TSP, as LumpSumRollover says.
+	LumpSum         decimal.Decimal `yaml:"lump_sum,omitempty" json:"lump_sum,omitempty"`
+	Election        string          `yaml:"election,omitempty" json:"election,omitempty"`
+	LumpSumRollover string          `yaml:"lump_sum_rollover,omitempty" json:"lump_sum_rollover,omitempty"`
+}
+
+// External pension elections and lump-sum rollover destinations
+const (
+	PensionElectionAnnuity = "annuity"
+	PensionElectionLumpSum = "lump_sum"
+
+	PensionRolloverIRA = "ira"
+	PensionRolloverTSP = "tsp"
+)
+
+// ElectsLumpSum reports whether the pension is taken as a lump sum, with a scenario's
+// election, if any, overriding the configured one
+func (ep *ExternalPension) ElectsLumpSum(scenarioElection string) bool {
+	election := ep.Election
+	if scenarioElection != "" {
+		election = scenarioElection
+	}
+	return election == PensionElectionLumpSum
+}
+
+// EmployerPlan is a non-federal employer defined contribution plan. Employee deferrals
+// share the elective deferral limit with any TSP contributions; the employer matches
+// them tier by tier. At separation the vested balance is rolled over to the
+// participant's IRAs and any unvested employer money is forfeited.
+type EmployerPlan struct {
+	BalanceTraditional decimal.Decimal `yaml:"balance_traditional" json:"balance_traditional"`
+	BalanceRoth        decimal.Decimal `yaml:"balance_roth,omitempty" json:"balance_roth,omitempty"`
+	// Employer contributions already in BalanceTraditional that are subject to vesting
+	BalanceEmployer decimal.Decimal `yaml:"balance_employer,omitempty" json:"balance_employer,omitempty"`
+
+	ContributionPercent     decimal.Decimal `yaml:"contribution_percent" json:"contribution_percent"`                               // pre-tax deferral, share of salary
+	RothContributionPercent decimal.Decimal `yaml:"roth_contribution_percent,omitempty" json:"roth_contribution_percent,omitempty"` // Roth deferral, share of salary
+
+	// Match tiers apply in order to successive slices of the deferral percent, e.g. 100%
+	// of the first 3% of pay and 50% of the next 2%
+	Match []EmployerMatchTier `yaml:"match,omitempty" json:"match,omitempty"`
+
+	// Vesting of employer money: immediate (default), cliff (fully vested after
+	// VestingYears of service) or graded (an equal share vests each full year of service
+	// until VestingYears). Service runs from ServiceStartDate, or hire_date when unset.
+	Vesting          string     `yaml:"vesting,omitempty" json:"vesting,omitempty"`
+	VestingYears     int        `yaml:"vesting_years,omitempty" json:"vesting_years,omitempty"`
+	ServiceStartDate *time.Time `yaml:"service_start_date,omitempty" json:"service_start_date,omitempty"`
+}
+
+// EmployerMatchTier matches Rate of each dollar deferred on the next PercentOfPay of salary
+type EmployerMatchTier struct {
+	Rate         decimal.Decimal `yaml:"rate" json:"rate"`
+	PercentOfPay decimal.Decimal `yaml:"percent_of_pay" json:"percent_of_pay"`
+}
+
+// Employer plan vesting schedules
+const (
+	VestingImmediate = "immediate"
+	VestingCliff     = "cliff"
+	VestingGraded    = "graded"
+)
+
+// IncomeStream is recurring net income from rental property, self-employment, royalties
+// or similar sources. Earned income is subject to self-employment tax and local earned
+// income tax and stops at the participant's death; passive income continues to the
+// survivor.
+type IncomeStream struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	Type string `yaml:"type" json:"type"` // rental, consulting, business, royalty or other
+	// AnnualAmount is the yearly net income in the first projection year's dollars
+	AnnualAmount decimal.Decimal `yaml:"annual_amount" json:"annual_amount"`
+	StartDate    *time.Time      `yaml:"start_date,omitempty" json:"start_date,omitempty"` // default: already under way
+	EndDate      *time.Time      `yaml:"end_date,omitempty" json:"end_date,omitempty"`     // default: for life
+	// GrowthRate is the yearly change in the income (default: the inflation rate)
+	GrowthRate *decimal.Decimal `yaml:"growth_rate,omitempty" json:"growth_rate,omitempty"`
+	// TaxCharacter is earned or passive; consulting and business income default to
+	// earned, the other types to passive
+	TaxCharacter string `yaml:"tax_character,omitempty" json:"tax_character,omitempty"`
+}
+
+// Income stream types
+const (
+	IncomeStreamRental     = "rental"
+	IncomeStreamConsulting = "consulting"
+	IncomeStreamBusiness   = "business"
+	IncomeStreamRoyalty    = "royalty"
+	IncomeStreamOther      = "other"
+)
+
+// Tax character of an income stream
+const (
+	TaxCharacterEarned  = "earned"
+	TaxCharacterPassive = "passive"
+)
+
+// Earned reports whether the stream is earned income rather than passive income
+func (s IncomeStream) Earned() bool {
+	if s.TaxCharacter != "" {
+		return s.TaxCharacter == TaxCharacterEarned
+	}
+	return s.Type == IncomeStreamConsulting || s.Type == IncomeStreamBusiness
+}
+
+// Label names the stream for messages
+func (s IncomeStream) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Type + " income"
+}
+
+// CurrentRetirement describes benefits a participant is already receiving, as annual
+// amounts at the start of the projection. Each is COLA-adjusted from the second year.
+type CurrentRetirement struct {
+	RetirementDate  time.Time       `yaml:"retirement_date" json:"retirement_date"`
+	AnnualAnnuity   decimal.Decimal `yaml:"annual_annuity" json:"annual_annuity"`                         // gross annuity after any survivor reduction
+	SurvivorAnnuity decimal.Decimal `yaml:"survivor_annuity,omitempty" json:"survivor_annuity,omitempty"` // payable to the spouse after death
+	FERSSupplement  decimal.Decimal `yaml:"fers_supplement,omitempty" json:"fers_supplement,omitempty"`   // paid until 62 (0 = none)
+	// Monthly Social Security benefit already being received (0 = not yet claimed; the
+	// scenario's ss_start_age then applies)
+	SSMonthlyBenefit decimal.Decimal `yaml:"ss_monthly_benefit,omitempty" json:"ss_monthly_benefit,omitempty"`
+}
+
+// IsAlreadyRetired reports whether the participant was retired before the projection starts
+func (p *Participant) IsAlreadyRetired() bool {
+	return p.CurrentRetirement != nil
+}
+
+// SurvivorElection describes how a retiree's annuity is reduced to provide a survivor benefit.
+// Spouse elections use SurvivorBenefitElectionPercent: 0.25 or 0.50 under FERS; under CSRS
+// any positive percent elects the full 55% benefit unless BaseAmount makes it partial.
+type SurvivorElection struct {
+	Type string `yaml:"type,omitempty" json:"type,omitempty"` // spouse (default) or insurable_interest
+
+	// CSRS partial spouse election: the annual base, at most the full annuity, that the
+	// reduction and the 55% survivor benefit are computed on
+	BaseAmount decimal.Decimal `yaml:"base_amount,omitempty" json:"base_amount,omitempty"`
+
+	// Insurable interest beneficiary's birth date: the reduction grows with how much
+	// younger the beneficiary is than the retiree
+	BeneficiaryBirthDate *time.Time `yaml:"beneficiary_birth_date,omitempty" json:"beneficiary_birth_date,omitempty"`
+}
+
+// Survivor election types
+const (
+	SurvivorElectionSpouse            = "spouse"
+	SurvivorElectionInsurableInterest = "insurable_interest"
+)
+
+// MilitaryDeposit describes post-1956 military service made creditable by paying a deposit.
+// The years count toward both eligibility and the annuity computation.
+type MilitaryDeposit struct {
+	Years       decimal.Decimal `yaml:"years" json:"years"`
+	Amount      decimal.Decimal `yaml:"amount,omitempty" json:"amount,omitempty"`             // deposit cost (0 = already paid)
+	PaymentYear int             `yaml:"payment_year,omitempty" json:"payment_year,omitempty"` // calendar year paid (0 = first projection year)
+	PaidFrom    string          `yaml:"paid_from,omitempty" json:"paid_from,omitempty"`       // "taxable" (default) or "tsp"
+}
+
+// Military deposit funding sources
+const (
+	MilitaryDepositFromTaxable = "taxable"
+	MilitaryDepositFromTSP     = "tsp"
+)
+
+// NonCoveredPension describes a pension earned without paying Social Security tax
+type NonCoveredPension struct {
+	MonthlyBenefit             decimal.Decimal `yaml:"monthly_benefit" json:"monthly_benefit"`
+	YearsOfSubstantialEarnings int             `yaml:"years_of_substantial_earnings" json:"years_of_substantial_earnings"` // covered years; 30+ means no WEP reduction
 }
 
 // Household represents a household of participants for retirement planning
 type Household struct {
-	Participants []Participant `yaml:"participants" json:"participants"`
-	FilingStatus string        `yaml:"filing_status" json:"filing_status"` // "married_filing_jointly", "single"
+	Participants       []Participant       `yaml:"participants" json:"participants"`
+	FilingStatus       string              `yaml:"filing_status" json:"filing_status"` // "married_filing_jointly", "single"
+	ItemizedDeductions *ItemizedDeductions `yaml:"itemized_deductions,omitempty" json:"itemized_deductions,omitempty"`
+
+	// Annual essential (non-discretionary) expenses in today's dollars, inflation adjusted.
+	// Used to measure how much of the floor guaranteed income covers; zero disables the metric.
+	EssentialExpenses decimal.Decimal `yaml:"essential_expenses,omitempty" json:"essential_expenses,omitempty"`
+
+	// Annual tax-exempt interest, such as from municipal bonds, in today's dollars and
+	// inflation adjusted. It is not taxed but counts toward MAGI and the taxation of Social
+	// Security; the interest itself is part of the taxable account's return.
+	TaxExemptInterest decimal.Decimal `yaml:"tax_exempt_interest,omitempty" json:"tax_exempt_interest,omitempty"`
+
+	// MAGIHistory is the household's MAGI by calendar year for the two years before the
+	// projection, which set the IRMAA surcharges of its first two years (optional)
+	MAGIHistory map[int]decimal.Decimal `yaml:"magi_history,omitempty" json:"magi_history,omitempty"`
+
+	// Expenses is an optional spending budget by category. Each year's categories add up
+	// to the household's spending target, which net income either funds or falls short of.
+	Expenses []ExpenseCategory `yaml:"expenses,omitempty" json:"expenses,omitempty"`
+}
+
+// ExpenseCategory is one line of the household's spending budget, such as housing,
+// healthcare or travel. Net income is already after taxes and FEHB and Medicare
+// premiums, so a healthcare category covers out-of-pocket costs only.
+type ExpenseCategory struct {
+	Name string `yaml:"name" json:"name"`
+	// AnnualAmount is the yearly spending in the first projection year's dollars
+	AnnualAmount decimal.Decimal `yaml:"annual_amount" json:"annual_amount"`
+	// InflationRate is the category's yearly price growth (default: the inflation rate)
+	InflationRate *decimal.Decimal `yaml:"inflation_rate,omitempty" json:"inflation_rate,omitempty"`
+	StartYear     int              `yaml:"start_year,omitempty" json:"start_year,omitempty"` // default: already under way
+	EndYear       int              `yaml:"end_year,omitempty" json:"end_year,omitempty"`     // last year of spending, e.g. a mortgage payoff (0 = for life)
+	// Essential categories make up the dignity floor when essential_expenses is not set
+	Essential bool `yaml:"essential,omitempty" json:"essential,omitempty"`
+}
+
+// ActiveIn reports whether the category's spending falls in year
+func (e ExpenseCategory) ActiveIn(year int) bool {
+	return (e.StartYear == 0 || year >= e.StartYear) && (e.EndYear == 0 || year <= e.EndYear)
+}
+
+// ItemizedDeductions holds annual Schedule A amounts in today's dollars. Each projection year
+// uses the larger of the standard deduction and the itemized total.
+type ItemizedDeductions struct {
+	MortgageInterest        decimal.Decimal `yaml:"mortgage_interest" json:"mortgage_interest"`
+	MortgagePayoffYear      int             `yaml:"mortgage_payoff_year,omitempty" json:"mortgage_payoff_year,omitempty"` // no mortgage interest after this year (0 = never paid off)
+	PropertyTaxes           decimal.Decimal `yaml:"property_taxes" json:"property_taxes"`                                 // inflation adjusted; counts toward the SALT cap with state/local income tax
+	CharitableContributions decimal.Decimal `yaml:"charitable_contributions" json:"charitable_contributions"`             // inflation adjusted
+}
+
+// GuardrailRules are the Guyton-Klinger decision rules of the guardrails withdrawal
+// strategy. When the current withdrawal rate rises above the initial rate by more than
+// UpperBand, e.g. 0.20 for 20%, the withdrawal is cut by CutPercent; when it falls below
+// the initial rate by more than LowerBand, it is raised by RaisePercent. Zero values take
+// the defaults of 20% bands and 10% adjustments.
+type GuardrailRules struct {
+	UpperBand    decimal.Decimal `yaml:"upper_band,omitempty" json:"upper_band,omitempty"`
+	LowerBand    decimal.Decimal `yaml:"lower_band,omitempty" json:"lower_band,omitempty"`
+	CutPercent   decimal.Decimal `yaml:"cut_percent,omitempty" json:"cut_percent,omitempty"`
+	RaisePercent decimal.Decimal `yaml:"raise_percent,omitempty" json:"raise_percent,omitempty"`
+}
+
+// Default guardrail bands and adjustments
+var (
+	DefaultGuardrailBand       = decimal.NewFromFloat(0.20)
+	DefaultGuardrailAdjustment = decimal.NewFromFloat(0.10)
+)
+
+// WithDefaults returns the rules with unset values replaced by the defaults; nil rules
+// are all defaults
+func (g *GuardrailRules) WithDefaults() GuardrailRules {
+	var rules GuardrailRules
+	if g != nil {
+		rules = *g
+	}
+	if rules.UpperBand.IsZero() {
+		rules.UpperBand = DefaultGuardrailBand
+	}
+	if rules.LowerBand.IsZero() {
+		rules.LowerBand = DefaultGuardrailBand
+	}
+	if rules.CutPercent.IsZero() {
+		rules.CutPercent = DefaultGuardrailAdjustment
+	}
+	if rules.RaisePercent.IsZero() {
+		rules.RaisePercent = DefaultGuardrailAdjustment
+	}
+	return rules
+}
+
+// BucketStrategy sizes the buckets of the buckets withdrawal strategy in years of
+// withdrawals: CashYears are held in cash (the G fund), BondYears in bonds (the F fund)
+// and the rest of the TSP in equities. Zero values take the defaults of 2 years of cash
+// and 5 of bonds.
+type BucketStrategy struct {
+	CashYears int `yaml:"cash_years,omitempty" json:"cash_years,omitempty"`
+	BondYears int `yaml:"bond_years,omitempty" json:"bond_years,omitempty"`
+}
+
+// Default bucket sizes, in years of withdrawals
+const (
+	DefaultBucketCashYears = 2
+	DefaultBucketBondYears = 5
+)
+
+// WithDefaults returns the bucket sizes with unset values replaced by the defaults; a nil
+// strategy is all defaults
+func (b *BucketStrategy) WithDefaults() BucketStrategy {
+	var sizes BucketStrategy
+	if b != nil {
+		sizes = *b
+	}
+	if sizes.CashYears == 0 {
+		sizes.CashYears = DefaultBucketCashYears
+	}
+	if sizes.BondYears == 0 {
+		sizes.BondYears = DefaultBucketBondYears
+	}
+	return sizes
 }
 
 // ParticipantScenario represents a retirement scenario for a single participant
@@ -482,6 +1047,28 @@ type ParticipantScenario struct {
 	TSPWithdrawalStrategy      string           `yaml:"tsp_withdrawal_strategy,omitempty" json:"tsp_withdrawal_strategy,omitempty"`
 	TSPWithdrawalTargetMonthly *decimal.Decimal `yaml:"tsp_withdrawal_target_monthly,omitempty" json:"tsp_withdrawal_target_monthly,omitempty"`
 	TSPWithdrawalRate          *decimal.Decimal `yaml:"tsp_withdrawal_rate,omitempty" json:"tsp_withdrawal_rate,omitempty"`
+	// SEPPInterestRate is the amortization rate for the sepp strategy (optional; defaults
+	// to 5%, the rate the IRS always allows)
+	SEPPInterestRate *decimal.Decimal `yaml:"sepp_interest_rate,omitempty" json:"sepp_interest_rate,omitempty"`
+	// VPWStockAllocation is the share of stocks the vpw strategy's withdrawal table assumes
+	// (optional; defaults to 60%)
+	VPWStockAllocation *decimal.Decimal `yaml:"vpw_stock_allocation,omitempty" json:"vpw_stock_allocation,omitempty"`
+	// SpendingTarget is the annual after-tax spending, in the first projection year's
+	// dollars, that the spending_target strategy solves withdrawals for (optional; defaults
+	// to the household's expenses)
+	SpendingTarget *decimal.Decimal `yaml:"spending_target,omitempty" json:"spending_target,omitempty"`
+	// Guardrails tunes the guardrails strategy, whose initial rate is TSPWithdrawalRate
+	// (optional; defaults to 20% bands and 10% adjustments)
+	Guardrails *GuardrailRules `yaml:"guardrails,omitempty" json:"guardrails,omitempty"`
+	// Buckets sizes the buckets strategy's cash and bond buckets, whose initial rate is
+	// TSPWithdrawalRate (optional; defaults to 2 years of cash and 5 of bonds)
+	Buckets *BucketStrategy `yaml:"buckets,omitempty" json:"buckets,omitempty"`
+
+	// Deferred retirement (optional): leave federal service on SeparationDate, before
+	// retirement eligibility, and begin the deferred FERS annuity at AnnuityStartAge
+	// (MRA, 60 or 62). Deferred annuitants cannot continue FEHB and get no FERS supplement.
+	SeparationDate  *time.Time `yaml:"separation_date,omitempty" json:"separation_date,omitempty"`
+	AnnuityStartAge int        `yaml:"annuity_start_age,omitempty" json:"annuity_start_age,omitempty"`
 
 	// Roth conversion schedule (optional)
 	RothConversions *RothConversionSchedule `yaml:"roth_conversions,omitempty" json:"roth_conversions,omitempty"`
@@ -489,16 +1076,211 @@ type ParticipantScenario struct {
 	// Part-time work schedule (optional)
 	PartTimeWork *PartTimeWorkSchedule `yaml:"part_time_work,omitempty" json:"partTimeWork,omitempty"`
 
+	// Qualified charitable distributions after age 70½ (optional)
+	QCDs *QCDSchedule `yaml:"qcds,omitempty" json:"qcds,omitempty"`
+
+	// DeclineMilitaryDeposit projects this scenario as if the participant's military
+	// deposit were never paid: no service credit and no deposit cost
+	DeclineMilitaryDeposit bool `yaml:"decline_military_deposit,omitempty" json:"decline_military_deposit,omitempty"`
+
+	// TSPAnnuity converts part of the TSP balance into a TSP life annuity (optional)
+	TSPAnnuity *TSPAnnuityPurchase `yaml:"tsp_annuity,omitempty" json:"tsp_annuity,omitempty"`
+
+	// TSPAllocationSchedule glides the participant's TSP allocation over time under the
+	// fund-level return model, overriding tsp_allocation and any Lifecycle fund (optional)
+	TSPAllocationSchedule []AllocationStep `yaml:"tsp_allocation_schedule,omitempty" json:"tsp_allocation_schedule,omitempty"`
+
+	// PensionElection overrides the external pension's election for this scenario:
+	// annuity or lump_sum (optional)
+	PensionElection string `yaml:"pension_election,omitempty" json:"pension_election,omitempty"`
+
+	// MedicareCoverage overrides the participant's healthcare coverage alongside Medicare
+	// for this scenario: fehb_secondary or medigap (optional)
+	MedicareCoverage string `yaml:"medicare_coverage,omitempty" json:"medicare_coverage,omitempty"`
+
+	// PartBEnrollmentAge overrides the age the participant enrolls in Medicare Part B for
+	// this scenario (optional)
+	PartBEnrollmentAge int `yaml:"part_b_enrollment_age,omitempty" json:"part_b_enrollment_age,omitempty"`
+
+	// AnnuityPurchases buy commercial income annuities with taxable or traditional
+	// savings (optional)
+	AnnuityPurchases []IncomeAnnuityPurchase `yaml:"annuity_purchases,omitempty" json:"annuity_purchases,omitempty"`
+
 	// Optional: per-participant override of sequencing (future use)
 	// (Typically sequencing is household-level; keeping placeholder for extensibility)
 }
 
+// TSPAnnuityPurchase buys a TSP life annuity (provided by MetLife) with part of the
+// participant's TSP balance, at retirement unless PurchaseAge is set. Set either Amount
+// or Percentage. The purchase is drawn pro rata from the traditional and Roth balances.
+type TSPAnnuityPurchase struct {
+	Amount      decimal.Decimal `yaml:"amount,omitempty" json:"amount,omitempty"`             // dollars to annuitize
+	Percentage  decimal.Decimal `yaml:"percentage,omitempty" json:"percentage,omitempty"`     // share of the balance to annuitize (0-1)
+	PurchaseAge int             `yaml:"purchase_age,omitempty" json:"purchase_age,omitempty"` // default: at retirement
+	// SurvivorPercent is 0 for a single life annuity, or 0.5 or 1 for a joint life
+	// annuity continuing that share of the payment to the surviving spouse
+	SurvivorPercent decimal.Decimal `yaml:"survivor_percent,omitempty" json:"survivor_percent,omitempty"`
+	// IncreasingPayments starts lower and rises each year with inflation, up to 3%
+	IncreasingPayments bool `yaml:"increasing_payments,omitempty" json:"increasing_payments,omitempty"`
+	// AnnualPayoutRate overrides the built-in annuity factor: the first year's payments
+	// per dollar of purchase (optional)
+	AnnualPayoutRate *decimal.Decimal `yaml:"annual_payout_rate,omitempty" json:"annual_payout_rate,omitempty"`
+}
+
+// Sources of an income annuity's premium
+const (
+	AnnuitySourceTaxable     = "taxable"     // sold from the taxable account: a nonqualified annuity
+	AnnuitySourceTraditional = "traditional" // traditional IRA, then traditional TSP money: a qualified annuity
+)
+
+// IncomeAnnuityPurchase buys a single-premium immediate annuity (SPIA) or, with
+// IncomeStartAge, a deferred income annuity (DIA) on the birthday at PurchaseAge.
+// Payments from a qualified annuity are fully taxable; a nonqualified annuity returns
+// the premium tax free over the annuitant's expected payout period.
+type IncomeAnnuityPurchase struct {
+	Name        string          `yaml:"name,omitempty" json:"name,omitempty"`
+	Premium     decimal.Decimal `yaml:"premium" json:"premium"`
+	Source      string          `yaml:"source" json:"source"` // taxable or traditional
+	PurchaseAge int             `yaml:"purchase_age" json:"purchase_age"`
+	// IncomeStartAge defers the first payment to that birthday (default: the month after
+	// the purchase)
+	IncomeStartAge int `yaml:"income_start_age,omitempty" json:"income_start_age,omitempty"`
+	// PayoutRate is the first full year's payments per dollar of premium, as quoted
+	PayoutRate decimal.Decimal `yaml:"payout_rate" json:"payout_rate"`
+	// COLA is a fixed yearly increase in the payments (optional)
+	COLA decimal.Decimal `yaml:"cola,omitempty" json:"cola,omitempty"`
+	// SurvivorPercent is the share of the payment continuing to the surviving spouse
+	// (0 for a single life annuity)
+	SurvivorPercent decimal.Decimal `yaml:"survivor_percent,omitempty" json:"survivor_percent,omitempty"`
+}
+
+// Label names the annuity for messages
+func (a IncomeAnnuityPurchase) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	if a.IncomeStartAge > a.PurchaseAge {
+		return fmt.Sprintf("deferred income annuity at %d", a.IncomeStartAge)
+	}
+	return fmt.Sprintf("income annuity at %d", a.PurchaseAge)
+}
+
+// AllocationStep anchors an allocation schedule at an age or at retirement. Before the
+// first step the first allocation holds, after the last the last one does, and in
+// between the allocation moves in equal annual steps from one anchor to the next.
+type AllocationStep struct {
+	Age          int           `yaml:"age,omitempty" json:"age,omitempty"`
+	AtRetirement bool          `yaml:"at_retirement,omitempty" json:"at_retirement,omitempty"`
+	Allocation   TSPAllocation `yaml:"allocation" json:"allocation"`
+}
+
 // GenericScenario represents a complete retirement scenario for a household
 type GenericScenario struct {
 	Name                 string                         `yaml:"name" json:"name"`
 	ParticipantScenarios map[string]ParticipantScenario `yaml:"participant_scenarios" json:"participant_scenarios"`
 	Mortality            *GenericScenarioMortality      `yaml:"mortality,omitempty" json:"mortality,omitempty"`
 	WithdrawalSequencing *WithdrawalSequencingConfig    `yaml:"withdrawal_sequencing,omitempty" json:"withdrawal_sequencing,omitempty"`
+	TaxElections         []TaxElection                  `yaml:"tax_elections,omitempty" json:"tax_elections,omitempty"`
+	CashFlowEvents       []CashFlowEvent                `yaml:"cash_flow_events,omitempty" json:"cash_flow_events,omitempty"`
+	SpendingPhases       *SpendingPhases                `yaml:"spending_phases,omitempty" json:"spending_phases,omitempty"`
+	MAGICeiling          *MAGICeiling                   `yaml:"magi_ceiling,omitempty" json:"magi_ceiling,omitempty"`
+	// ProjectionYears overrides global_assumptions.projection_years for this scenario,
+	// e.g. a longevity stress test projected to age 100 (0 = the global horizon)
+	ProjectionYears int `yaml:"projection_years,omitempty" json:"projection_years,omitempty"`
+}
+
+// CashFlowEvent is a dated one-time inflow, such as an inheritance or home sale proceeds,
+// or outflow, such as a new roof, a wedding or a car. Inflows are deposited to the
+// taxable account. Outflows are paid from the taxable account, selling investments, or
+// from the TSP or IRA, traditional money first, which is ordinary income.
+type CashFlowEvent struct {
+	Name        string          `yaml:"name" json:"name"`
+	Date        time.Time       `yaml:"date" json:"date"`
+	Type        string          `yaml:"type" json:"type"` // inflow or outflow
+	Amount      decimal.Decimal `yaml:"amount" json:"amount"`
+	Participant string          `yaml:"participant,omitempty" json:"participant,omitempty"` // account owner (default: first participant; a survivor after their death)
+	Account     string          `yaml:"account,omitempty" json:"account,omitempty"`         // taxable (default), tsp or ira
+	// TaxTreatment of an inflow: tax_free (default, e.g. an inheritance), ordinary or
+	// capital_gain. TaxableAmount is the part of the inflow taxed that way (default: all
+	// of it), e.g. the gain on a home sale above the exclusion.
+	TaxTreatment  string           `yaml:"tax_treatment,omitempty" json:"tax_treatment,omitempty"`
+	TaxableAmount *decimal.Decimal `yaml:"taxable_amount,omitempty" json:"taxable_amount,omitempty"`
+}
+
+// Cash flow event types, accounts and inflow tax treatments
+const (
+	CashFlowInflow  = "inflow"
+	CashFlowOutflow = "outflow"
+
+	CashFlowAccountTaxable = "taxable"
+	CashFlowAccountTSP     = "tsp"
+	CashFlowAccountIRA     = "ira"
+
+	CashFlowTaxFree     = "tax_free"
+	CashFlowOrdinary    = "ordinary"
+	CashFlowCapitalGain = "capital_gain"
+)
+
+// Inflow reports whether the event brings money in
+func (e CashFlowEvent) Inflow() bool {
+	return e.Type == CashFlowInflow
+}
+
+// TaxedAmount returns the part of an inflow taxed under its tax treatment
+func (e CashFlowEvent) TaxedAmount() decimal.Decimal {
+	if !e.Inflow() || e.TaxTreatment == "" || e.TaxTreatment == CashFlowTaxFree {
+		return decimal.Zero
+	}
+	if e.TaxableAmount != nil {
+		return decimal.Min(*e.TaxableAmount, e.Amount)
+	}
+	return e.Amount
+}
+
+// SpendingPhases shape real retirement spending by age, such as the "spending smile":
+// spending that drifts down through the slow-go years and rises again for healthcare in
+// the no-go years. Each phase runs from its start age to the next phase's and changes
+// spending by its annual change every year; the changes compound. They scale
+// 4_percent_rule and need_based withdrawals and the household's spending target.
+type SpendingPhases struct {
+	// Participant whose age selects the phase (default: the first participant)
+	Participant string          `yaml:"participant,omitempty" json:"participant,omitempty"`
+	Phases      []SpendingPhase `yaml:"phases" json:"phases"`
+}
+
+// SpendingPhase is one phase of retirement spending, e.g. go-go, slow-go or no-go
+type SpendingPhase struct {
+	Name     string `yaml:"name,omitempty" json:"name,omitempty"`
+	StartAge int    `yaml:"start_age" json:"start_age"`
+	// AnnualChange is the yearly change in real spending, e.g. -0.01 for 1% less each year
+	AnnualChange decimal.Decimal `yaml:"annual_change" json:"annual_change"`
+}
+
+// TaxElection pins a federal return election for one calendar year of a scenario,
+// e.g. itemizing in a charitable bunching year. Years without an election take
+// the larger of the standard and itemized deductions.
+type TaxElection struct {
+	Year      int    `yaml:"year" json:"year"`
+	Deduction string `yaml:"deduction,omitempty" json:"deduction,omitempty"` // standard | itemized
+}
+
+// Federal deduction elections
+const (
+	DeductionStandard = "standard"
+	DeductionItemized = "itemized"
+)
+
+// TaxElectionFor returns the scenario's election for the given year, if any
+func (gs *GenericScenario) TaxElectionFor(year int) *TaxElection {
+	if gs == nil {
+		return nil
+	}
+	for i := range gs.TaxElections {
+		if gs.TaxElections[i].Year == year {
+			return &gs.TaxElections[i]
+		}
+	}
+	return nil
 }
 
 // WithdrawalSequencingConfig defines strategy and parameters for tax-smart withdrawal ordering
@@ -507,6 +1289,22 @@ type WithdrawalSequencingConfig struct {
 	CustomSequence []string `yaml:"custom_sequence,omitempty" json:"custom_sequence,omitempty"` // e.g. ["taxable","roth","traditional"]
 	TargetBracket  *int     `yaml:"target_bracket,omitempty" json:"target_bracket,omitempty"`   // e.g. 22 means fill up to 22% bracket
 	BracketBuffer  *int     `yaml:"bracket_buffer,omitempty" json:"bracket_buffer,omitempty"`   // dollar buffer below bracket edge
+	// AutoRothConversions has bracket_fill also convert traditional TSP money to Roth each
+	// year, up to the target bracket less the buffer, from ConversionParticipant's TSP
+	// (default: the first household participant in the scenario)
+	AutoRothConversions   bool   `yaml:"auto_roth_conversions,omitempty" json:"auto_roth_conversions,omitempty"`
+	ConversionParticipant string `yaml:"conversion_participant,omitempty" json:"conversion_participant,omitempty"`
+}
+
+// MAGICeiling is a hard cap on the household's MAGI each year. Roth conversions and
+// sequenced withdrawals from traditional accounts and taxable gains are clipped so MAGI
+// stays at or below it; a clipped withdrawal is made up from Roth balances where it can.
+// The cap is either a dollar Amount or the threshold of an IRMAA tier (BelowIRMAATier 1
+// is the first surcharge tier) for the household's filing status, less Buffer.
+type MAGICeiling struct {
+	Amount         *decimal.Decimal `yaml:"amount,omitempty" json:"amount,omitempty"`
+	BelowIRMAATier int              `yaml:"below_irmaa_tier,omitempty" json:"below_irmaa_tier,omitempty"`
+	Buffer         decimal.Decimal  `yaml:"buffer,omitempty" json:"buffer,omitempty"`
 }
 
 // GenericScenarioMortality groups mortality specifications for participants
@@ -530,9 +1328,14 @@ func (gs *GenericScenario) DeepCopy() *GenericScenario {
 	// Deep copy participant scenarios
 	for name, ps := range gs.ParticipantScenarios {
 		psCopy := ParticipantScenario{
-			ParticipantName:       ps.ParticipantName,
-			SSStartAge:            ps.SSStartAge,
-			TSPWithdrawalStrategy: ps.TSPWithdrawalStrategy,
+			ParticipantName:        ps.ParticipantName,
+			SSStartAge:             ps.SSStartAge,
+			TSPWithdrawalStrategy:  ps.TSPWithdrawalStrategy,
+			AnnuityStartAge:        ps.AnnuityStartAge,
+			DeclineMilitaryDeposit: ps.DeclineMilitaryDeposit,
+			PensionElection:        ps.PensionElection,
+			MedicareCoverage:       ps.MedicareCoverage,
+			PartBEnrollmentAge:     ps.PartBEnrollmentAge,
 		}
 
 		// Copy pointer fields
@@ -540,6 +1343,10 @@ func (gs *GenericScenario) DeepCopy() *GenericScenario {
 			dateCopy := *ps.RetirementDate
 			psCopy.RetirementDate = &dateCopy
 		}
+		if ps.SeparationDate != nil {
+			dateCopy := *ps.SeparationDate
+			psCopy.SeparationDate = &dateCopy
+		}
 		if ps.TSPWithdrawalTargetMonthly != nil {
 			valCopy := *ps.TSPWithdrawalTargetMonthly
 			psCopy.TSPWithdrawalTargetMonthly = &valCopy
@@ -548,6 +1355,26 @@ func (gs *GenericScenario) DeepCopy() *GenericScenario {
 			valCopy := *ps.TSPWithdrawalRate
 			psCopy.TSPWithdrawalRate = &valCopy
 		}
+		if ps.SEPPInterestRate != nil {
+			valCopy := *ps.SEPPInterestRate
+			psCopy.SEPPInterestRate = &valCopy
+		}
+		if ps.VPWStockAllocation != nil {
+			valCopy := *ps.VPWStockAllocation
+			psCopy.VPWStockAllocation = &valCopy
+		}
+		if ps.SpendingTarget != nil {
+			valCopy := *ps.SpendingTarget
+			psCopy.SpendingTarget = &valCopy
+		}
+		if ps.Guardrails != nil {
+			rulesCopy := *ps.Guardrails
+			psCopy.Guardrails = &rulesCopy
+		}
+		if ps.Buckets != nil {
+			bucketsCopy := *ps.Buckets
+			psCopy.Buckets = &bucketsCopy
+		}
 		if ps.RothConversions != nil {
 			rcCopy := &RothConversionSchedule{
 				Conversions: make([]RothConversion, len(ps.RothConversions.Conversions)),
@@ -564,6 +1391,30 @@ func (gs *GenericScenario) DeepCopy() *GenericScenario {
 			copy(ptwCopy.Schedule, ps.PartTimeWork.Schedule)
 			psCopy.PartTimeWork = ptwCopy
 		}
+		if ps.QCDs != nil {
+			qcdCopy := &QCDSchedule{
+				AnnualAmount:  ps.QCDs.AnnualAmount,
+				Distributions: make([]QCDDistribution, len(ps.QCDs.Distributions)),
+			}
+			copy(qcdCopy.Distributions, ps.QCDs.Distributions)
+			psCopy.QCDs = qcdCopy
+		}
+		if ps.TSPAnnuity != nil {
+			annuityCopy := *ps.TSPAnnuity
+			if ps.TSPAnnuity.AnnualPayoutRate != nil {
+				rateCopy := *ps.TSPAnnuity.AnnualPayoutRate
+				annuityCopy.AnnualPayoutRate = &rateCopy
+			}
+			psCopy.TSPAnnuity = &annuityCopy
+		}
+		if ps.AnnuityPurchases != nil {
+			psCopy.AnnuityPurchases = make([]IncomeAnnuityPurchase, len(ps.AnnuityPurchases))
+			copy(psCopy.AnnuityPurchases, ps.AnnuityPurchases)
+		}
+		if ps.TSPAllocationSchedule != nil {
+			psCopy.TSPAllocationSchedule = make([]AllocationStep, len(ps.TSPAllocationSchedule))
+			copy(psCopy.TSPAllocationSchedule, ps.TSPAllocationSchedule)
+		}
 
 		gc.ParticipantScenarios[name] = psCopy
 	}
@@ -594,13 +1445,18 @@ func (gs *GenericScenario) DeepCopy() *GenericScenario {
 				TSPSpousalTransfer:     gs.Mortality.Assumptions.TSPSpousalTransfer,
 				FilingStatusSwitch:     gs.Mortality.Assumptions.FilingStatusSwitch,
 				SurvivorSpendingFactor: gs.Mortality.Assumptions.SurvivorSpendingFactor,
+				FEHBSurvivorEnrollment: gs.Mortality.Assumptions.FEHBSurvivorEnrollment,
 			}
 		}
 	}
 
 	// Deep copy withdrawal sequencing if present
 	if gs.WithdrawalSequencing != nil {
-		ws := &WithdrawalSequencingConfig{Strategy: gs.WithdrawalSequencing.Strategy}
+		ws := &WithdrawalSequencingConfig{
+			Strategy:              gs.WithdrawalSequencing.Strategy,
+			AutoRothConversions:   gs.WithdrawalSequencing.AutoRothConversions,
+			ConversionParticipant: gs.WithdrawalSequencing.ConversionParticipant,
+		}
 		if len(gs.WithdrawalSequencing.CustomSequence) > 0 {
 			seqCopy := make([]string, len(gs.WithdrawalSequencing.CustomSequence))
 			copy(seqCopy, gs.WithdrawalSequencing.CustomSequence)
@@ -617,9 +1473,79 @@ func (gs *GenericScenario) DeepCopy() *GenericScenario {
 		gc.WithdrawalSequencing = ws
 	}
 
+	if gs.MAGICeiling != nil {
+		ceiling := *gs.MAGICeiling
+		if ceiling.Amount != nil {
+			amount := *ceiling.Amount
+			ceiling.Amount = &amount
+		}
+		gc.MAGICeiling = &ceiling
+	}
+
+	if len(gs.TaxElections) > 0 {
+		gc.TaxElections = make([]TaxElection, len(gs.TaxElections))
+		copy(gc.TaxElections, gs.TaxElections)
+	}
+	if len(gs.CashFlowEvents) > 0 {
+		gc.CashFlowEvents = make([]CashFlowEvent, len(gs.CashFlowEvents))
+		copy(gc.CashFlowEvents, gs.CashFlowEvents)
+	}
+	if gs.SpendingPhases != nil {
+		gc.SpendingPhases = &SpendingPhases{
+			Participant: gs.SpendingPhases.Participant,
+			Phases:      make([]SpendingPhase, len(gs.SpendingPhases.Phases)),
+		}
+		copy(gc.SpendingPhases.Phases, gs.SpendingPhases.Phases)
+	}
+	gc.ProjectionYears = gs.ProjectionYears
+
 	return gc
 }
 
+// Federal retirement systems
+const (
+	RetirementSystemFERS       = "fers"
+	RetirementSystemCSRS       = "csrs"
+	RetirementSystemCSRSOffset = "csrs_offset"
+)
+
+// IsCSRS reports whether the participant is covered by CSRS or CSRS Offset rather than FERS
+func (p *Participant) IsCSRS() bool {
+	return p.IsFederal && (p.RetirementSystem == RetirementSystemCSRS || p.RetirementSystem == RetirementSystemCSRSOffset)
+}
+
+// FERS special provision categories
+const (
+	SpecialProvisionLawEnforcement       = "law_enforcement"
+	SpecialProvisionFirefighter          = "firefighter"
+	SpecialProvisionAirTrafficController = "air_traffic_controller"
+)
+
+// IsSpecialProvision reports whether the participant is covered by FERS special provisions
+func (p *Participant) IsSpecialProvision() bool {
+	return p.IsFederal && p.SpecialProvision != ""
+}
+
+// MandatoryRetirementAge returns the mandatory separation age for special provision
+// employees (56 for air traffic controllers, 57 otherwise), or 0 when none applies
+func (p *Participant) MandatoryRetirementAge() int {
+	if !p.IsSpecialProvision() {
+		return 0
+	}
+	if p.SpecialProvision == SpecialProvisionAirTrafficController {
+		return 56
+	}
+	return 57
+}
+
+// Label returns the display name, falling back to the participant key
+func (p *Participant) Label() string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
+	return p.Name
+}
+
 // Age calculates the age of the participant at a given date
 func (p *Participant) Age(atDate time.Time) int {
 	age := atDate.Year() - p.BirthDate.Year()
@@ -629,7 +1555,9 @@ func (p *Participant) Age(atDate time.Time) int {
 	return age
 }
 
-// YearsOfService calculates years of service for federal employees
+// YearsOfService calculates years of service for federal employees: civilian service plus
+// any military service bought back by deposit. Unused sick leave is excluded: it never
+// counts toward retirement eligibility.
 func (p *Participant) YearsOfService(atDate time.Time) decimal.Decimal {
 	if !p.IsFederal || p.HireDate == nil {
 		return decimal.Zero
@@ -637,17 +1565,53 @@ func (p *Participant) YearsOfService(atDate time.Time) decimal.Decimal {
 
 	serviceDuration := atDate.Sub(*p.HireDate)
 	years := decimal.NewFromFloat(serviceDuration.Hours() / 24 / 365.25)
-
-	// Add sick leave credit if available
-	if p.SickLeaveHours != nil && p.SickLeaveHours.GreaterThan(decimal.Zero) {
-		sickLeaveDays := p.SickLeaveHours.Div(decimal.NewFromInt(8))
-		sickLeaveYears := sickLeaveDays.Div(decimal.NewFromFloat(365.25))
-		years = years.Add(sickLeaveYears)
+	if p.MilitaryDeposit != nil && p.MilitaryDeposit.Years.IsPositive() {
+		years = years.Add(p.MilitaryDeposit.Years)
 	}
 
 	return years.Round(4)
 }
 
+// CreditableService returns the years of service used in the annuity computation at an
+// immediate retirement: actual service plus unused sick leave credit, with any odd days
+// left over after whole months dropped as OPM does.
+func (p *Participant) CreditableService(atDate time.Time) decimal.Decimal {
+	years := p.YearsOfService(atDate)
+	if p.SickLeaveHours == nil || !p.SickLeaveHours.IsPositive() {
+		return years
+	}
+
+	months, days := SickLeaveServiceCredit(*p.SickLeaveHours)
+	totalDays := years.Mul(decimal.NewFromInt(360)).Add(decimal.NewFromInt(int64(months*30 + days)))
+	wholeMonths := totalDays.Div(decimal.NewFromInt(30)).Floor()
+	return wholeMonths.Div(decimal.NewFromInt(12)).Round(4)
+}
+
+// SickLeaveHoursPerYear is the work year OPM's sick leave conversion chart is based on
+const SickLeaveHoursPerYear = 2087
+
+// SickLeaveServiceCredit converts unused sick leave hours to service credit using OPM's
+// chart, which is based on a 2087-hour work year: 173.917 hours make a month and 5.797
+// hours make a day (30-day months, 360-day years).
+func SickLeaveServiceCredit(hours decimal.Decimal) (months, days int) {
+	if !hours.IsPositive() {
+		return 0, 0
+	}
+	perYear := decimal.NewFromInt(SickLeaveHoursPerYear)
+	twelve := decimal.NewFromInt(12)
+
+	// Work in twelfths of an hour so whole months come out exact
+	months = int(hours.Mul(twelve).Div(perYear).Floor().IntPart())
+	remaining := hours.Mul(twelve).Sub(perYear.Mul(decimal.NewFromInt(int64(months)))).Div(twelve)
+	// The chart grants a day once the remainder reaches its rounded hours-per-day value
+	hoursPerDay := perYear.Div(decimal.NewFromInt(360)).Round(3)
+	days = int(remaining.Div(hoursPerDay).Floor().IntPart())
+	if days >= 30 {
+		months, days = months+1, days-30
+	}
+	return months, days
+}
+
 // TotalTSPBalance returns combined TSP balance for federal employees
 func (p *Participant) TotalTSPBalance() decimal.Decimal {
 	if !p.IsFederal || p.TSPBalanceTraditional == nil || p.TSPBalanceRoth == nil {
@@ -686,6 +1650,24 @@ func (h *Household) GetFederalParticipants() []Participant {
 	return federal
 }
 
+// ParticipantLabels maps participant keys to display names for those that set one
+func (h *Household) ParticipantLabels() ParticipantLabels {
+	if h == nil {
+		return nil
+	}
+	var labels ParticipantLabels
+	for _, p := range h.Participants {
+		if p.DisplayName == "" {
+			continue
+		}
+		if labels == nil {
+			labels = ParticipantLabels{}
+		}
+		labels[p.Name] = p.DisplayName
+	}
+	return labels
+}
+
 // AgesAt returns a map of participant names to their ages at the given date
 func (h *Household) AgesAt(date time.Time) map[string]int {
 	ages := make(map[string]int)
@@ -718,5 +1700,3 @@ func (h *Household) Survivors(atYear int, mortality map[string]*MortalitySpec) [
 	}
 	return survivors
 }
-
-// (Legacy conversion helpers removed)
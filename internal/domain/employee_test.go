@@ -105,6 +105,38 @@ func TestEmployee_YearsOfService_WithSickLeave(t *testing.T) {
 	assert.Equal(t, expected, years.StringFixed(4))
 }
 
+func TestSickLeaveServiceCredit(t *testing.T) {
+	testCases := []struct {
+		hours  float64
+		months int
+		days   int
+	}{
+		{0, 0, 0},
+		{5.797, 0, 1},
+		{174, 1, 0},
+		{1057.5, 6, 2},
+		{2087, 12, 0},
+	}
+
+	for _, tc := range testCases {
+		months, days := SickLeaveServiceCredit(decimal.NewFromFloat(tc.hours))
+		assert.Equal(t, tc.months, months, "months for %.3f hours", tc.hours)
+		assert.Equal(t, tc.days, days, "days for %.3f hours", tc.hours)
+	}
+}
+
+func TestParticipant_CreditableService(t *testing.T) {
+	hireDate := time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	sickLeave := decimal.NewFromInt(2087)
+	participant := &Participant{IsFederal: true, HireDate: &hireDate, SickLeaveHours: &sickLeave}
+	retirementDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Eligibility service excludes sick leave; the computation adds a full year and
+	// drops the odd days
+	assert.Equal(t, "30.0014", participant.YearsOfService(retirementDate).StringFixed(4))
+	assert.Equal(t, "31.0000", participant.CreditableService(retirementDate).StringFixed(4))
+}
+
 func TestEmployee_FullRetirementAge(t *testing.T) {
 	testCases := []struct {
 		birthYear int
@@ -326,6 +358,17 @@ func TestMonteCarloSettings_DefaultValues(t *testing.T) {
 	assert.True(t, settings.MaxReasonableIncome.Equal(decimal.NewFromInt(5000000)))
 }
 
+func TestRateBounds_Clamp(t *testing.T) {
+	floor := decimal.Zero
+	ceiling := decimal.NewFromFloat(0.08)
+	bounds := RateBounds{Floor: &floor, Ceiling: &ceiling}
+
+	assert.True(t, bounds.Clamp(decimal.NewFromFloat(-0.01)).Equal(floor))
+	assert.True(t, bounds.Clamp(decimal.NewFromFloat(0.12)).Equal(ceiling))
+	assert.True(t, bounds.Clamp(decimal.NewFromFloat(0.03)).Equal(decimal.NewFromFloat(0.03)))
+	assert.True(t, RateBounds{}.Clamp(decimal.NewFromInt(-5)).Equal(decimal.NewFromInt(-5)), "unbounded")
+}
+
 func TestTSPLifecycleFund_Validation(t *testing.T) {
 	fund := TSPLifecycleFund{
 		FundName: "L2030",
@@ -401,3 +444,70 @@ func TestTSPFundStats_Validation(t *testing.T) {
 	assert.Equal(t, "TSP.gov 1988-2024", stats.DataSource)
 	assert.Equal(t, "2024-01-01", stats.LastUpdated)
 }
+
+func TestHousehold_ParticipantLabels(t *testing.T) {
+	household := &Household{
+		Participants: []Participant{
+			{Name: "person_a", DisplayName: "Robert"},
+			{Name: "person_b"},
+		},
+	}
+
+	assert.Equal(t, "Robert", household.Participants[0].Label())
+	assert.Equal(t, "person_b", household.Participants[1].Label())
+
+	labels := household.ParticipantLabels()
+	assert.Equal(t, ParticipantLabels{"person_a": "Robert"}, labels)
+	assert.Equal(t, "Robert", labels.Label("person_a"))
+	assert.Equal(t, "person_b", labels.Label("person_b"))
+
+	var none *Household
+	assert.Nil(t, none.ParticipantLabels())
+	assert.Equal(t, "person_a", ParticipantLabels(nil).Label("person_a"))
+}
+
+func TestCorrelationMatrix_Validate(t *testing.T) {
+	matrix := func(rows ...[]float64) CorrelationMatrix {
+		m := make(CorrelationMatrix, len(rows))
+		for i, row := range rows {
+			for _, v := range row {
+				m[i] = append(m[i], decimal.NewFromFloat(v))
+			}
+		}
+		return m
+	}
+	valid := matrix(
+		[]float64{1, 0.9, 0.8, 0.1, 0, -0.1},
+		[]float64{0.9, 1, 0.75, 0.05, 0, 0},
+		[]float64{0.8, 0.75, 1, 0, 0, 0},
+		[]float64{0.1, 0.05, 0, 1, 0.3, -0.2},
+		[]float64{0, 0, 0, 0.3, 1, 0.4},
+		[]float64{-0.1, 0, 0, -0.2, 0.4, 1},
+	)
+	assert.NoError(t, valid.Validate())
+
+	factor, err := valid.Cholesky()
+	assert.NoError(t, err)
+	// L·Lᵀ reproduces the C/S correlation
+	assert.InDelta(t, 0.9, factor[1][0]*factor[0][0]+factor[1][1]*factor[0][1], 1e-9)
+
+	assert.Error(t, valid[:5].Validate(), "wrong size")
+
+	asymmetric := make(CorrelationMatrix, len(valid))
+	for i := range valid {
+		asymmetric[i] = append([]decimal.Decimal(nil), valid[i]...)
+	}
+	asymmetric[0][1] = decimal.NewFromFloat(0.5)
+	assert.Error(t, asymmetric.Validate())
+
+	// C and S perfectly correlated with each other but not with I cannot be factored
+	inconsistent := matrix(
+		[]float64{1, 0.99, -0.99, 0, 0, 0},
+		[]float64{0.99, 1, 0.99, 0, 0, 0},
+		[]float64{-0.99, 0.99, 1, 0, 0, 0},
+		[]float64{0, 0, 0, 1, 0, 0},
+		[]float64{0, 0, 0, 0, 1, 0},
+		[]float64{0, 0, 0, 0, 0, 1},
+	)
+	assert.ErrorContains(t, inconsistent.Validate(), "positive definite")
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// FEHBPlanCatalog lists FEHB plan premiums for a plan year, so participants can name their
+// plan instead of entering a premium
+type FEHBPlanCatalog struct {
+	PlanYear int        `yaml:"plan_year" json:"plan_year"`
+	Plans    []FEHBPlan `yaml:"plans" json:"plans"`
+}
+
+// FEHBPlan is one enrollment option of an FEHB plan: the enrollee's biweekly share of the
+// premium and how fast the plan's premiums are expected to grow each year
+type FEHBPlan struct {
+	Code            string          `yaml:"code" json:"code"`             // enrollment code, e.g. "112"
+	Plan            string          `yaml:"plan" json:"plan"`             // e.g. "Blue Cross Basic"
+	Enrollment      string          `yaml:"enrollment" json:"enrollment"` // self_only | self_plus_one | self_and_family
+	BiweeklyPremium decimal.Decimal `yaml:"biweekly_premium" json:"biweekly_premium"`
+	PremiumGrowth   decimal.Decimal `yaml:"premium_growth" json:"premium_growth"`
+}
+
+// FEHB enrollment types
+const (
+	FEHBEnrollmentSelfOnly      = "self_only"
+	FEHBEnrollmentSelfPlusOne   = "self_plus_one"
+	FEHBEnrollmentSelfAndFamily = "self_and_family"
+)
+
+// fehbEnrollmentLabels name each enrollment type the way plan codes are written
+var fehbEnrollmentLabels = map[string]string{
+	FEHBEnrollmentSelfOnly:      "Self Only",
+	FEHBEnrollmentSelfPlusOne:   "Self+1",
+	FEHBEnrollmentSelfAndFamily: "Self & Family",
+}
+
+// Name returns the plan and enrollment, e.g. "Blue Cross Basic Self+1"
+func (p FEHBPlan) Name() string {
+	return p.Plan + " " + fehbEnrollmentLabels[p.Enrollment]
+}
+
+// Lookup finds the plan enrollment named by ref: its enrollment code or its name, in any
+// case
+func (c *FEHBPlanCatalog) Lookup(ref string) (*FEHBPlan, bool) {
+	ref = strings.TrimSpace(ref)
+	for i := range c.Plans {
+		p := &c.Plans[i]
+		if p.Code == ref || strings.EqualFold(p.Name(), ref) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// SelfOnly returns the self only enrollment of the same plan as p
+func (c *FEHBPlanCatalog) SelfOnly(p *FEHBPlan) (*FEHBPlan, bool) {
+	for i := range c.Plans {
+		if q := &c.Plans[i]; q.Plan == p.Plan && q.Enrollment == FEHBEnrollmentSelfOnly {
+			return q, true
+		}
+	}
+	return nil, false
+}
@@ -8,7 +8,11 @@ import (
 type HealthcareConfig struct {
 	// Pre-Medicare (before age 65)
 	PreMedicareCoverage       string          `yaml:"pre_medicare_coverage" json:"pre_medicare_coverage"`               // fehb | cobra | marketplace | retiree_plan
-	PreMedicareMonthlyPremium decimal.Decimal `yaml:"pre_medicare_monthly_premium" json:"pre_medicare_monthly_premium"` // If not FEHB
+	PreMedicareMonthlyPremium decimal.Decimal `yaml:"pre_medicare_monthly_premium" json:"pre_medicare_monthly_premium"` // If not FEHB, or once FEHB is lost
+
+	// Monthly premium of the marketplace benchmark (second-lowest-cost silver) plan in 2025
+	// dollars, which sets the premium tax credit; unset, the plan bought is the benchmark
+	MarketplaceBenchmarkPremium decimal.Decimal `yaml:"marketplace_benchmark_premium,omitempty" json:"marketplace_benchmark_premium,omitempty"`
 
 	// Medicare (age 65+)
 	MedicarePartB     bool   `yaml:"medicare_part_b" json:"medicare_part_b"`           // Default true
@@ -16,8 +20,52 @@ type HealthcareConfig struct {
 	MedicarePartDPlan string `yaml:"medicare_part_d_plan" json:"medicare_part_d_plan"` // standard | enhanced
 	MedigapPlan       string `yaml:"medigap_plan" json:"medigap_plan"`                 // A-N, or none
 
+	// Age Part B enrollment is delayed to, past Medicare eligibility at 65 (optional). Each
+	// full 12 months without Part B or coverage through current employment adds a 10%
+	// lifetime penalty to the Part B premium. A scenario may override it.
+	PartBEnrollmentAge int `yaml:"part_b_enrollment_age,omitempty" json:"part_b_enrollment_age,omitempty"`
+
+	// Part B late enrollment penalty as a share of the standard premium, set by the
+	// projection from the enrollment age and FEHB coverage through employment
+	PartBLatePenalty decimal.Decimal `yaml:"-" json:"-"`
+
+	// Expected yearly out-of-pocket prescription drug costs on Medicare, in 2025 dollars
+	// (deductible, copays and coinsurance); capped by Part D's out-of-pocket maximum
+	DrugCosts decimal.Decimal `yaml:"drug_costs" json:"drug_costs"`
+
 	// Transition
 	DropFEHBAt65 bool `yaml:"drop_fehb_at_65" json:"drop_fehb_at_65"` // Stop FEHB when Medicare eligible
+
+	// Coverage alongside Part B once on Medicare: fehb_secondary keeps FEHB, which then pays
+	// after Medicare and covers drugs in place of Part D; medigap drops FEHB for a Medigap
+	// plan and Part D. Unset, the fields above decide. A scenario may override it.
+	MedicareCoverage string `yaml:"medicare_coverage,omitempty" json:"medicare_coverage,omitempty"`
+
+	// Expected yearly out-of-pocket medical costs on Medicare under each coverage, in 2025
+	// dollars: deductibles, copays and coinsurance the coverage leaves to the participant
+	OutOfPocketFEHB    decimal.Decimal `yaml:"out_of_pocket_fehb,omitempty" json:"out_of_pocket_fehb,omitempty"`
+	OutOfPocketMedigap decimal.Decimal `yaml:"out_of_pocket_medigap,omitempty" json:"out_of_pocket_medigap,omitempty"`
+}
+
+// Coverage alongside Medicare
+const (
+	MedicareCoverageFEHBSecondary = "fehb_secondary"
+	MedicareCoverageMedigap       = "medigap"
+)
+
+// Coverage returns the participant's coverage alongside Medicare, with a scenario's
+// choice, if any, overriding the configured one: fehb_secondary when FEHB is kept and no
+// Medigap plan is bought, otherwise medigap
+func (hc *HealthcareConfig) Coverage(scenarioCoverage string) string {
+	switch {
+	case scenarioCoverage != "":
+		return scenarioCoverage
+	case hc.MedicareCoverage != "":
+		return hc.MedicareCoverage
+	case !hc.DropFEHBAt65 && hc.MedigapPlan == "":
+		return MedicareCoverageFEHBSecondary
+	}
+	return MedicareCoverageMedigap
 }
 
 // HealthcareCostBreakdown provides detailed breakdown of healthcare costs
@@ -25,7 +73,10 @@ type HealthcareCostBreakdown struct {
 	FEHBPremium        decimal.Decimal `json:"fehbPremium"`        // FEHB premium (if applicable)
 	MarketplacePremium decimal.Decimal `json:"marketplacePremium"` // Marketplace/COBRA premium
 	MedicarePartB      decimal.Decimal `json:"medicarePartB"`      // Medicare Part B premium + IRMAA
+	PartBLatePenalty   decimal.Decimal `json:"partBLatePenalty"`   // Late enrollment penalty, included in MedicarePartB
 	MedicarePartD      decimal.Decimal `json:"medicarePartD"`      // Medicare Part D premium + IRMAA
+	DrugCosts          decimal.Decimal `json:"drugCosts"`          // Out-of-pocket prescription drug costs
+	OutOfPocket        decimal.Decimal `json:"outOfPocket"`        // Out-of-pocket medical costs on Medicare
 	Medigap            decimal.Decimal `json:"medigap"`            // Medigap premium
 	Total              decimal.Decimal `json:"total"`              // Total healthcare cost
 }
@@ -43,13 +94,7 @@ type HealthcareInflationRates struct {
 type MedicarePartDCosts struct {
 	StandardBasePremium decimal.Decimal `yaml:"standard_base_premium" json:"standard_base_premium"` // ~$35/month
 	EnhancedBasePremium decimal.Decimal `yaml:"enhanced_base_premium" json:"enhanced_base_premium"` // ~$50/month
-}
-
-// MedicarePartDIRMAA represents Medicare Part D IRMAA surcharges (same thresholds as Part B)
-type MedicarePartDIRMAA struct {
-	IncomeThresholdSingle decimal.Decimal `yaml:"income_threshold_single" json:"income_threshold_single"`
-	IncomeThresholdJoint  decimal.Decimal `yaml:"income_threshold_joint" json:"income_threshold_joint"`
-	MonthlySurcharge      decimal.Decimal `yaml:"monthly_surcharge" json:"monthly_surcharge"`
+	OutOfPocketMax      decimal.Decimal `yaml:"out_of_pocket_max" json:"out_of_pocket_max"`         // yearly cap on covered drug costs
 }
 
 // MedigapCosts represents Medigap plan costs by plan type and age
@@ -88,37 +133,7 @@ func DefaultMedicarePartDCosts() MedicarePartDCosts {
 	return MedicarePartDCosts{
 		StandardBasePremium: decimal.NewFromFloat(35.0), // $35/month
 		EnhancedBasePremium: decimal.NewFromFloat(50.0), // $50/month
-	}
-}
-
-// DefaultMedicarePartDIRMAA returns default Medicare Part D IRMAA thresholds for 2025
-func DefaultMedicarePartDIRMAA() []MedicarePartDIRMAA {
-	return []MedicarePartDIRMAA{
-		{
-			IncomeThresholdSingle: decimal.NewFromInt(103000),
-			IncomeThresholdJoint:  decimal.NewFromInt(206000),
-			MonthlySurcharge:      decimal.NewFromFloat(12.90),
-		},
-		{
-			IncomeThresholdSingle: decimal.NewFromInt(129000),
-			IncomeThresholdJoint:  decimal.NewFromInt(258000),
-			MonthlySurcharge:      decimal.NewFromFloat(33.20),
-		},
-		{
-			IncomeThresholdSingle: decimal.NewFromInt(161000),
-			IncomeThresholdJoint:  decimal.NewFromInt(322000),
-			MonthlySurcharge:      decimal.NewFromFloat(53.50),
-		},
-		{
-			IncomeThresholdSingle: decimal.NewFromInt(193000),
-			IncomeThresholdJoint:  decimal.NewFromInt(386000),
-			MonthlySurcharge:      decimal.NewFromFloat(73.80),
-		},
-		{
-			IncomeThresholdSingle: decimal.NewFromInt(500000),
-			IncomeThresholdJoint:  decimal.NewFromInt(750000),
-			MonthlySurcharge:      decimal.NewFromFloat(81.90),
-		},
+		OutOfPocketMax:      decimal.NewFromInt(2000),   // $2,000/year
 	}
 }
 
@@ -0,0 +1,29 @@
+package domain
+
+// HooksConfig lists hooks to run around a calculation
+type HooksConfig struct {
+	PostRun []PostRunHook `yaml:"post_run,omitempty" json:"post_run,omitempty"`
+}
+
+// PostRunHook hands a completed run's results to something outside rpgo: either a
+// command, which reads the results JSON on standard input, or a URL the results are
+// POSTed to. Exactly one of Command and URL is set.
+type PostRunHook struct {
+	Name           string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Command        string            `yaml:"command,omitempty" json:"command,omitempty"`
+	URL            string            `yaml:"url,omitempty" json:"url,omitempty"`
+	Headers        map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"` // extra request headers for URL hooks; $VAR values expand from the environment
+	TimeoutSeconds int               `yaml:"timeout_seconds,omitempty" json:"timeout_seconds,omitempty"`
+}
+
+// Label returns the hook's name, or what it runs or calls when it has none
+func (h PostRunHook) Label() string {
+	switch {
+	case h.Name != "":
+		return h.Name
+	case h.Command != "":
+		return h.Command
+	default:
+		return h.URL
+	}
+}
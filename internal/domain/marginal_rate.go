@@ -0,0 +1,28 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// MarginalRateCurve is the tax cost of adding ordinary income to one projection year,
+// swept in fixed steps. It sizes Roth conversions and other discretionary income.
+type MarginalRateCurve struct {
+	Scenario     string              `json:"scenario"`
+	Year         int                 `json:"year"`
+	Step         decimal.Decimal     `json:"step"`
+	FilingStatus string              `json:"filingStatus"`
+	Points       []MarginalRatePoint `json:"points"`
+}
+
+// MarginalRatePoint is the year's tax picture with AdditionalIncome added. MarginalRate is
+// the cost of the step ending at this point; AverageRate is the cost of all added income.
+type MarginalRatePoint struct {
+	AdditionalIncome      decimal.Decimal `json:"additionalIncome"`
+	FederalTax            decimal.Decimal `json:"federalTax"`
+	StateLocalTax         decimal.Decimal `json:"stateLocalTax"`
+	TaxableSocialSecurity decimal.Decimal `json:"taxableSocialSecurity"`
+	MAGI                  decimal.Decimal `json:"magi"`
+	IRMAATier             string          `json:"irmaaTier"`
+	IRMAACost             decimal.Decimal `json:"irmaaCost"` // annual surcharges for the household
+	TotalCost             decimal.Decimal `json:"totalCost"`
+	MarginalRate          decimal.Decimal `json:"marginalRate"`
+	AverageRate           decimal.Decimal `json:"averageRate"`
+}
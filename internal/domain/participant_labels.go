@@ -0,0 +1,13 @@
+package domain
+
+// ParticipantLabels maps stable participant keys (e.g. "person_a") to the display
+// names used in reports. Keys without an entry display as themselves.
+type ParticipantLabels map[string]string
+
+// Label returns the display name for a participant key
+func (pl ParticipantLabels) Label(name string) string {
+	if label, ok := pl[name]; ok && label != "" {
+		return label
+	}
+	return name
+}
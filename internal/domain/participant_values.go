@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"encoding/json"
+	"iter"
+)
+
+// ParticipantIndex assigns each participant name a stable position so per-year
+// values can live in slices instead of maps. A single index is shared by every
+// AnnualCashFlow in a projection, so the name lookup is allocated only once.
+type ParticipantIndex struct {
+	names     []string
+	positions map[string]int
+}
+
+// NewParticipantIndex creates an index preserving the order of names
+func NewParticipantIndex(names []string) *ParticipantIndex {
+	pi := &ParticipantIndex{
+		names:     make([]string, 0, len(names)),
+		positions: make(map[string]int, len(names)),
+	}
+	for _, name := range names {
+		pi.add(name)
+	}
+	return pi
+}
+
+// Names returns participant names in index order
+func (pi *ParticipantIndex) Names() []string {
+	if pi == nil {
+		return nil
+	}
+	return pi.names
+}
+
+// Len returns the number of indexed participants
+func (pi *ParticipantIndex) Len() int {
+	if pi == nil {
+		return 0
+	}
+	return len(pi.names)
+}
+
+// Position returns the slice position for a participant name
+func (pi *ParticipantIndex) Position(name string) (int, bool) {
+	if pi == nil {
+		return 0, false
+	}
+	pos, ok := pi.positions[name]
+	return pos, ok
+}
+
+// add returns the position for name, appending it if not yet indexed.
+// Positions never change once assigned, so values sharing the index stay valid.
+func (pi *ParticipantIndex) add(name string) int {
+	if pos, ok := pi.positions[name]; ok {
+		return pos
+	}
+	pi.positions[name] = len(pi.names)
+	pi.names = append(pi.names, name)
+	return len(pi.names) - 1
+}
+
+// ParticipantValues stores one value per participant, positioned by a shared
+// ParticipantIndex. It serializes to JSON as a name -> value object.
+type ParticipantValues[T any] struct {
+	index  *ParticipantIndex
+	values []T
+}
+
+// NewParticipantValues creates zero-valued storage for every indexed participant
+func NewParticipantValues[T any](index *ParticipantIndex) ParticipantValues[T] {
+	return ParticipantValues[T]{index: index, values: make([]T, index.Len())}
+}
+
+// newParticipantValuesWithBacking uses a caller-provided slice (e.g. carved from a slab)
+func newParticipantValuesWithBacking[T any](index *ParticipantIndex, backing []T) ParticipantValues[T] {
+	return ParticipantValues[T]{index: index, values: backing[:index.Len():index.Len()]}
+}
+
+// ParticipantValuesFromMap builds values from a name -> value map with names in sorted order
+func ParticipantValuesFromMap[T any](m map[string]T) ParticipantValues[T] {
+	names := SortedMapKeys(m)
+	pv := NewParticipantValues[T](NewParticipantIndex(names))
+	for i, name := range names {
+		pv.values[i] = m[name]
+	}
+	return pv
+}
+
+// Get returns the value for a participant, or the zero value if absent
+func (pv ParticipantValues[T]) Get(name string) T {
+	var zero T
+	pos, ok := pv.index.Position(name)
+	if !ok || pos >= len(pv.values) {
+		return zero
+	}
+	return pv.values[pos]
+}
+
+// Set stores the value for a participant, indexing the name if needed
+func (pv *ParticipantValues[T]) Set(name string, value T) {
+	if pv.index == nil {
+		pv.index = NewParticipantIndex(nil)
+	}
+	pos := pv.index.add(name)
+	if pos >= len(pv.values) {
+		grown := make([]T, pos+1)
+		copy(grown, pv.values)
+		pv.values = grown
+	}
+	pv.values[pos] = value
+}
+
+// withIndex copies the values into fresh storage positioned by index
+func (pv ParticipantValues[T]) withIndex(index *ParticipantIndex) ParticipantValues[T] {
+	copied := NewParticipantValues[T](index)
+	for name, value := range pv.All() {
+		copied.Set(name, value)
+	}
+	return copied
+}
+
+// Len returns the number of participants with storage
+func (pv ParticipantValues[T]) Len() int {
+	return len(pv.values)
+}
+
+// Names returns participant names that have storage, in index order
+func (pv ParticipantValues[T]) Names() []string {
+	return pv.index.Names()[:len(pv.values)]
+}
+
+// Values returns the stored values in index order
+func (pv ParticipantValues[T]) Values() []T {
+	return pv.values
+}
+
+// All iterates name/value pairs in index order
+func (pv ParticipantValues[T]) All() iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for i, name := range pv.Names() {
+			if !yield(name, pv.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ToMap returns a name -> value copy
+func (pv ParticipantValues[T]) ToMap() map[string]T {
+	m := make(map[string]T, len(pv.values))
+	for i, name := range pv.Names() {
+		m[name] = pv.values[i]
+	}
+	return m
+}
+
+// MarshalJSON encodes the values as a name -> value object
+func (pv ParticipantValues[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pv.ToMap())
+}
+
+// UnmarshalJSON decodes a name -> value object
+func (pv *ParticipantValues[T]) UnmarshalJSON(data []byte) error {
+	var m map[string]T
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*pv = ParticipantValuesFromMap(m)
+	return nil
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParticipantValues_GetSet(t *testing.T) {
+	index := NewParticipantIndex([]string{"Alice", "Bob"})
+	pv := NewParticipantValues[decimal.Decimal](index)
+
+	pv.Set("Bob", decimal.NewFromInt(10))
+	assert.True(t, pv.Get("Bob").Equal(decimal.NewFromInt(10)))
+	assert.True(t, pv.Get("Alice").IsZero())
+	assert.True(t, pv.Get("Nobody").IsZero())
+
+	// Unknown names are appended to the index
+	pv.Set("Carol", decimal.NewFromInt(5))
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, pv.Names())
+	assert.Equal(t, 3, index.Len())
+
+	// Zero-value storage is usable without an index
+	var flags ParticipantValues[bool]
+	flags.Set("Alice", true)
+	assert.True(t, flags.Get("Alice"))
+}
+
+func TestParticipantValues_JSONMatchesMapShape(t *testing.T) {
+	pv := ParticipantValuesFromMap(map[string]int{"Bob": 62, "Alice": 60})
+
+	data, err := json.Marshal(pv)
+	require.NoError(t, err)
+	expected, _ := json.Marshal(map[string]int{"Alice": 60, "Bob": 62})
+	assert.JSONEq(t, string(expected), string(data))
+
+	var decoded ParticipantValues[int]
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 62, decoded.Get("Bob"))
+
+	names := []string{}
+	for name := range decoded.All() {
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"Alice", "Bob"}, names)
+}
+
+func TestNewAnnualCashFlowWithIndex_FieldsAreIndependent(t *testing.T) {
+	index := NewParticipantIndex([]string{"Alice", "Bob"})
+	acf := NewAnnualCashFlowWithIndex(2025, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), index)
+
+	acf.Salaries.Set("Alice", decimal.NewFromInt(100))
+	acf.Pensions.Set("Bob", decimal.NewFromInt(50))
+	acf.FERSSupplementReduction.Set("Bob", decimal.NewFromInt(7))
+	acf.IsDeceased.Set("Alice", true)
+
+	assert.True(t, acf.GetTotalSalary().Equal(decimal.NewFromInt(100)))
+	assert.True(t, acf.GetTotalPension().Equal(decimal.NewFromInt(50)))
+	assert.True(t, acf.PartTimeTSPContributions.Get("Bob").IsZero())
+	assert.False(t, acf.IsPartTime.Get("Alice"))
+	assert.Equal(t, []string{"Bob"}, acf.GetLivingParticipants())
+}
+
+func BenchmarkNewAnnualCashFlowWithIndex(b *testing.B) {
+	index := NewParticipantIndex([]string{"Alice", "Bob"})
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewAnnualCashFlowWithIndex(2025, date, index)
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// DefaultPercentPrecision is the number of decimal places percentages are shown with
+const DefaultPercentPrecision = 2
+
+// PercentPrecision is the number of decimal places every report and command shows
+// percentages with; the --percent-precision flag sets it
+var PercentPrecision int32 = DefaultPercentPrecision
+
+var percentScale = decimal.NewFromInt(100)
+
+// FormatPercent formats an amount already in percent, e.g. 12.3456 as "12.35%", at
+// PercentPrecision decimals. Halves round away from zero.
+func FormatPercent(percent decimal.Decimal) string {
+	return percent.StringFixed(PercentPrecision) + "%"
+}
+
+// FormatRate formats a fraction as a percentage, e.g. 0.025 as "2.50%"
+func FormatRate(rate decimal.Decimal) string {
+	return FormatPercent(rate.Mul(percentScale))
+}
+
+// FormatSignedPercent formats an amount in percent with its sign, e.g. 10 as "+10.00%"
+func FormatSignedPercent(percent decimal.Decimal) string {
+	if percent.Round(PercentPrecision).IsPositive() {
+		return "+" + FormatPercent(percent)
+	}
+	return FormatPercent(percent)
+}
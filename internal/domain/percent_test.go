@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPercent(t *testing.T) {
+	assert.Equal(t, "2.50%", FormatRate(decimal.NewFromFloat(0.025)))
+	assert.Equal(t, "12.35%", FormatPercent(decimal.NewFromFloat(12.345)), "halves round away from zero")
+	assert.Equal(t, "-12.35%", FormatPercent(decimal.NewFromFloat(-12.345)))
+	assert.Equal(t, "+10.00%", FormatSignedPercent(decimal.NewFromInt(10)))
+	assert.Equal(t, "0.00%", FormatSignedPercent(decimal.NewFromFloat(0.001)))
+
+	defer func(places int32) { PercentPrecision = places }(PercentPrecision)
+	PercentPrecision = 1
+	assert.Equal(t, "2.5%", FormatRate(decimal.NewFromFloat(0.025)))
+	PercentPrecision = 0
+	assert.Equal(t, "3%", FormatRate(decimal.NewFromFloat(0.025)))
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"maps"
+	"slices"
 	"sort"
 	"time"
 
@@ -32,54 +34,137 @@ type AnnualCashFlow struct {
 	Year int       `json:"year"`
 	Date time.Time `json:"date"`
 
-	// Participant-based data using maps (participant name -> value)
-	Ages                        map[string]int             `json:"ages"`                        // participantName -> age
-	Salaries                    map[string]decimal.Decimal `json:"salaries"`                    // participantName -> salary
-	Pensions                    map[string]decimal.Decimal `json:"pensions"`                    // participantName -> pension
-	SurvivorPensions            map[string]decimal.Decimal `json:"survivorPensions"`            // participantName -> survivor pension
-	TSPWithdrawals              map[string]decimal.Decimal `json:"tspWithdrawals"`              // participantName -> TSP withdrawal
-	SSBenefits                  map[string]decimal.Decimal `json:"ssBenefits"`                  // participantName -> Social Security benefits
-	FERSSupplements             map[string]decimal.Decimal `json:"fersSupplements"`             // participantName -> FERS supplement
-	TSPBalances                 map[string]decimal.Decimal `json:"tspBalances"`                 // participantName -> total TSP balance
-	ParticipantTSPContributions map[string]decimal.Decimal `json:"participantTspContributions"` // participantName -> TSP contributions
-	IsDeceased                  map[string]bool            `json:"isDeceased"`                  // participantName -> deceased status
+	// Participant-based data stored by participant position (serialized as participant name -> value)
+	Ages                        ParticipantValues[int]             `json:"ages"`                        // participantName -> age
+	Salaries                    ParticipantValues[decimal.Decimal] `json:"salaries"`                    // participantName -> salary
+	Pensions                    ParticipantValues[decimal.Decimal] `json:"pensions"`                    // participantName -> pension
+	SurvivorPensions            ParticipantValues[decimal.Decimal] `json:"survivorPensions"`            // participantName -> survivor pension
+	TSPWithdrawals              ParticipantValues[decimal.Decimal] `json:"tspWithdrawals"`              // participantName -> TSP withdrawal
+	SSBenefits                  ParticipantValues[decimal.Decimal] `json:"ssBenefits"`                  // participantName -> Social Security benefits
+	SpousalSSBenefits           ParticipantValues[decimal.Decimal] `json:"spousalSsBenefits"`           // participantName -> spousal portion included in SSBenefits
+	SurvivorSSBenefits          ParticipantValues[decimal.Decimal] `json:"survivorSsBenefits"`          // participantName -> survivor step-up included in SSBenefits
+	SSEarningsTestWithheld      ParticipantValues[decimal.Decimal] `json:"ssEarningsTestWithheld"`      // participantName -> benefits withheld under the earnings test
+	FERSSupplements             ParticipantValues[decimal.Decimal] `json:"fersSupplements"`             // participantName -> FERS supplement
+	TSPAnnuities                ParticipantValues[decimal.Decimal] `json:"tspAnnuities"`                // participantName -> TSP life annuity payments, including survivor payments
+	IncomeAnnuities             ParticipantValues[decimal.Decimal] `json:"incomeAnnuities"`             // participantName -> purchased income annuity payments, including survivor payments
+	OtherIncome                 ParticipantValues[decimal.Decimal] `json:"otherIncome"`                 // participantName -> rental, self-employment and other income streams, including passive income from a deceased spouse
+	SelfEmploymentIncome        ParticipantValues[decimal.Decimal] `json:"selfEmploymentIncome"`        // participantName -> earned share of OtherIncome
+	TSPBalances                 ParticipantValues[decimal.Decimal] `json:"tspBalances"`                 // participantName -> total TSP balance
+	ParticipantTSPContributions ParticipantValues[decimal.Decimal] `json:"participantTspContributions"` // participantName -> TSP contributions
+	IRAWithdrawalsTraditional   ParticipantValues[decimal.Decimal] `json:"iraWithdrawalsTraditional"`   // participantName -> traditional IRA distributions, taxed as ordinary income
+	IRAWithdrawalsRoth          ParticipantValues[decimal.Decimal] `json:"iraWithdrawalsRoth"`          // participantName -> Roth IRA distributions, tax free
+	IRABalances                 ParticipantValues[decimal.Decimal] `json:"iraBalances"`                 // participantName -> total traditional and Roth IRA balance
+	IRAContributions            ParticipantValues[decimal.Decimal] `json:"iraContributions"`            // participantName -> IRA contributions
+	EmployerPlanContributions   ParticipantValues[decimal.Decimal] `json:"employerPlanContributions"`   // participantName -> employee deferrals to a non-federal employer plan
+	EmployerPlanBalances        ParticipantValues[decimal.Decimal] `json:"employerPlanBalances"`        // participantName -> employer plan balance, vested and unvested
+	IsDeceased                  ParticipantValues[bool]            `json:"isDeceased"`                  // participantName -> deceased status
 
 	// Part-time work tracking
-	IsPartTime               map[string]bool            `json:"isPartTime"`               // participantName -> part-time status
-	PartTimeSalary           map[string]decimal.Decimal `json:"partTimeSalary"`           // participantName -> part-time salary
-	PartTimeTSPContributions map[string]decimal.Decimal `json:"partTimeTspContributions"` // participantName -> part-time TSP contributions
-	FERSSupplementReduction  map[string]decimal.Decimal `json:"fersSupplementReduction"`  // participantName -> FERS supplement reduction
+	IsPartTime               ParticipantValues[bool]            `json:"isPartTime"`               // participantName -> part-time status
+	PartTimeSalary           ParticipantValues[decimal.Decimal] `json:"partTimeSalary"`           // participantName -> part-time salary
+	PartTimeTSPContributions ParticipantValues[decimal.Decimal] `json:"partTimeTspContributions"` // participantName -> part-time TSP contributions
+	FERSSupplementReduction  ParticipantValues[decimal.Decimal] `json:"fersSupplementReduction"`  // participantName -> FERS supplement reduction
 
 	// Household-level totals and taxes
-	TotalGrossIncome         decimal.Decimal `json:"totalGrossIncome"`
-	FederalTax               decimal.Decimal `json:"federalTax"`
-	FederalTaxableIncome     decimal.Decimal `json:"federalTaxableIncome"`
-	FederalStandardDeduction decimal.Decimal `json:"federalStandardDeduction"`
-	FederalFilingStatus      string          `json:"federalFilingStatus"`
-	FederalSeniors65Plus     int             `json:"federalSeniors65Plus"`
-	StateTax                 decimal.Decimal `json:"stateTax"`
-	LocalTax                 decimal.Decimal `json:"localTax"`
-	FICATax                  decimal.Decimal `json:"ficaTax"`
-	TotalTSPContributions    decimal.Decimal `json:"totalTspContributions"` // Sum of all participant TSP contributions
-	FEHBPremium              decimal.Decimal `json:"fehbPremium"`
-	MedicarePremium          decimal.Decimal `json:"medicarePremium"`
+	TotalGrossIncome            decimal.Decimal `json:"totalGrossIncome"`
+	FederalTax                  decimal.Decimal `json:"federalTax"`
+	NetInvestmentIncomeTax      decimal.Decimal `json:"netInvestmentIncomeTax"` // 3.8% NIIT, included in FederalTax
+	SaversCredit                decimal.Decimal `json:"saversCredit"`           // credit for TSP contributions, already deducted from FederalTax
+	PremiumTaxCredit            decimal.Decimal `json:"premiumTaxCredit"`       // credit toward marketplace premiums, already deducted from HealthcareCosts.Total
+	ACAMAGI                     decimal.Decimal `json:"acaMagi"`                // MAGI plus untaxed Social Security, which sets the premium tax credit
+	ACACliffDistance            decimal.Decimal `json:"acaCliffDistance"`       // ACA MAGI left before the credit ends at 400% of the poverty line (zero without a credit)
+	FederalTaxableIncome        decimal.Decimal `json:"federalTaxableIncome"`
+	TaxableSocialSecurity       decimal.Decimal `json:"taxableSocialSecurity"` // share of Social Security benefits taxed, from provisional income
+	FederalStandardDeduction    decimal.Decimal `json:"federalStandardDeduction"`
+	FederalItemizedDeduction    decimal.Decimal `json:"federalItemizedDeduction"`
+	FederalDeductionMethod      string          `json:"federalDeductionMethod"` // "standard" or "itemized"
+	FederalFilingStatus         string          `json:"federalFilingStatus"`
+	FederalSeniors65Plus        int             `json:"federalSeniors65Plus"`
+	StateTax                    decimal.Decimal `json:"stateTax"`
+	LocalTax                    decimal.Decimal `json:"localTax"`
+	FICATax                     decimal.Decimal `json:"ficaTax"`
+	SelfEmploymentTax           decimal.Decimal `json:"selfEmploymentTax"`           // Social Security and Medicare tax on self-employment income, included in FICATax
+	TotalTSPContributions       decimal.Decimal `json:"totalTspContributions"`       // Sum of all participant TSP contributions
+	IRATraditionalContributions decimal.Decimal `json:"iraTraditionalContributions"` // traditional IRA share of IRAContributions, deducted from taxable income
+	EmployerPlanPreTax          decimal.Decimal `json:"employerPlanPreTax"`          // pre-tax share of EmployerPlanContributions, excluded from taxable wages
+	FEHBPremium                 decimal.Decimal `json:"fehbPremium"`
+	FEHBPreTaxPremium           decimal.Decimal `json:"fehbPreTaxPremium"`      // part of FEHBPremium paid from salary through premium conversion
+	FEHBRetroactivePremium      decimal.Decimal `json:"fehbRetroactivePremium"` // interim-pay premiums collected from the first annuity payments, included in FEHBPremium
+	MedicarePremium             decimal.Decimal `json:"medicarePremium"`
 
 	// Healthcare cost breakdown
 	HealthcareCosts HealthcareCostBreakdown `json:"healthcareCosts"`
 
 	NetIncome decimal.Decimal `json:"netIncome"`
 
+	// Dignity floor: guaranteed income (pensions, survivor annuities, Social Security and
+	// FERS supplements) against essential expenses. FloorCoverage is their ratio, zero when
+	// no essential expenses are configured.
+	GuaranteedIncome  decimal.Decimal `json:"guaranteedIncome"`
+	EssentialExpenses decimal.Decimal `json:"essentialExpenses"`
+	FloorCoverage     decimal.Decimal `json:"floorCoverage"`
+
+	// Spending plan: the year's expense categories, their total and what net income has
+	// left over after paying them (negative for a shortfall). All zero without expenses.
+	Expenses        map[string]decimal.Decimal `json:"expenses,omitempty"`
+	SpendingTarget  decimal.Decimal            `json:"spendingTarget"`
+	SpendingSurplus decimal.Decimal            `json:"spendingSurplus"`
+
+	// Spending phase in effect and its cumulative change in real spending (1 before the
+	// first phase, zero without spending phases)
+	SpendingPhase  string          `json:"spendingPhase,omitempty"`
+	SpendingFactor decimal.Decimal `json:"spendingFactor"`
+
+	// TSP balances of participants using the buckets withdrawal strategy, split across
+	// their buckets at the end of the year; empty when no participant uses it
+	Buckets map[string]BucketBalances `json:"buckets,omitempty"`
+
+	// Taxes paid from the first projection year through this one, by type
+	CumulativeTaxes TaxTotals `json:"cumulativeTaxes"`
+
 	// IRMAA-related fields
+	// IRMAA surcharges are based on MAGI from two years earlier, so this year's MAGI sets the
+	// tier paid two years later
 	MAGI                decimal.Decimal `json:"magi"`                // Modified Adjusted Gross Income for IRMAA
-	IRMAASurcharge      decimal.Decimal `json:"irmaaSurcharge"`      // Monthly IRMAA surcharge per person
-	IRMAALevel          string          `json:"irmaaLevel"`          // "None", "Tier1", "Tier2", etc.
+	MAGIBreakdown       MAGIBreakdown   `json:"magiBreakdown"`       // How the year's MAGI adds up
+	IRMAAMAGI           decimal.Decimal `json:"irmaaMagi"`           // MAGI from two years earlier that sets this year's surcharges
+	IRMAASurcharge      decimal.Decimal `json:"irmaaSurcharge"`      // Monthly IRMAA surcharge per person paid this year
+	IRMAASurchargePartD decimal.Decimal `json:"irmaaSurchargePartD"` // Monthly Part D IRMAA surcharge per person paid this year
+	IRMAALevel          string          `json:"irmaaLevel"`          // Tier this year's MAGI sets: "None", "Tier1", "Tier2", etc.
 	IRMAARiskStatus     string          `json:"irmaaRiskStatus"`     // "Safe", "Warning", "Breach"
 	IRMAADistanceToNext decimal.Decimal `json:"irmaaDistanceToNext"` // Distance to next IRMAA threshold
+	MAGICeiling         decimal.Decimal `json:"magiCeiling"`         // Scenario's MAGI ceiling (zero when it sets none)
 
 	// Withdrawal sequencing breakdown (household-level aggregates)
 	WithdrawalTaxable     decimal.Decimal `json:"withdrawalTaxable"`
 	WithdrawalTraditional decimal.Decimal `json:"withdrawalTraditional"`
 	WithdrawalRoth        decimal.Decimal `json:"withdrawalRoth"`
+	CapitalGainsRealized  decimal.Decimal `json:"capitalGainsRealized"`  // Long-term gains realized by taxable account withdrawals
+	MilitaryDeposits      decimal.Decimal `json:"militaryDeposits"`      // military service deposits paid from TSP or taxable balances
+	TSPAnnuityPurchases   decimal.Decimal `json:"tspAnnuityPurchases"`   // TSP balances converted to life annuities
+	IncomeAnnuityPremiums decimal.Decimal `json:"incomeAnnuityPremiums"` // savings paid for income annuities
+	IncomeAnnuityTaxFree  decimal.Decimal `json:"incomeAnnuityTaxFree"`  // nonqualified annuity payments returning premium tax free
+	OneTimeInflows        decimal.Decimal `json:"oneTimeInflows"`        // cash flow events deposited to savings
+	OneTimeOutflows       decimal.Decimal `json:"oneTimeOutflows"`       // cash flow events paid from savings
+
+	// Qualified charitable distributions, included in TSPWithdrawals but paid to charity
+	QualifiedCharitableDistributions decimal.Decimal `json:"qualifiedCharitableDistributions"`
+
+	// Roth conversions, included in TSPWithdrawals for taxes but moved to the Roth balance
+	// rather than paid out
+	RothConversions decimal.Decimal `json:"rothConversions"`
+
+	// Roth distributions before the accounts' five-year rules and 59½ are met: earnings
+	// taxed as ordinary income, and the 10% additional tax, included in FederalTax
+	RothTaxableEarnings  decimal.Decimal `json:"rothTaxableEarnings"`
+	EarlyDistributionTax decimal.Decimal `json:"earlyDistributionTax"`
+
+	// Year-end household savings by tax treatment: traditional TSP, IRA and employer plan
+	// balances are taxed when withdrawn, Roth balances are not, and the taxable account
+	// owes tax only on its gains
+	TraditionalBalance decimal.Decimal `json:"traditionalBalance"`
+	RothBalance        decimal.Decimal `json:"rothBalance"`
+	TaxableBalance     decimal.Decimal `json:"taxableBalance"`
 
 	// Additional Information
 	IsRetired          bool            `json:"isRetired"`
@@ -87,8 +172,101 @@ type AnnualCashFlow struct {
 	IsRMDYear          bool            `json:"isRmdYear"`
 	RMDAmount          decimal.Decimal `json:"rmdAmount"`
 	FilingStatusSingle bool            `json:"filingStatusSingle"` // true once survivor filing status applies
+
+	// Household TSP balance at the end of each sub-period when the year is
+	// projected at semi-annual, quarterly or monthly granularity (empty for annual steps)
+	PeriodTSPBalances []decimal.Decimal `json:"periodTspBalances,omitempty"`
+
+	// Engine warnings raised while projecting this year
+	Warnings []EngineWarning `json:"warnings,omitempty"`
 }
 
+// BucketBalances is a TSP balance split across the cash, bond and equity buckets of the
+// buckets withdrawal strategy
+type BucketBalances struct {
+	Cash     decimal.Decimal `json:"cash"`
+	Bonds    decimal.Decimal `json:"bonds"`
+	Equities decimal.Decimal `json:"equities"`
+}
+
+// Total returns the balance across all three buckets
+func (b BucketBalances) Total() decimal.Decimal {
+	return b.Cash.Add(b.Bonds).Add(b.Equities)
+}
+
+// TaxTotals breaks taxes down by type. Federal includes the net investment income tax,
+// FICA includes self-employment tax and IRMAA is the household's annual Medicare
+// surcharge; Total is their sum.
+type TaxTotals struct {
+	Federal decimal.Decimal `json:"federal"`
+	State   decimal.Decimal `json:"state"`
+	Local   decimal.Decimal `json:"local"`
+	FICA    decimal.Decimal `json:"fica"`
+	IRMAA   decimal.Decimal `json:"irmaa"`
+	Total   decimal.Decimal `json:"total"`
+}
+
+// Add returns the sum of two tax breakdowns
+func (t TaxTotals) Add(o TaxTotals) TaxTotals {
+	return TaxTotals{
+		Federal: t.Federal.Add(o.Federal),
+		State:   t.State.Add(o.State),
+		Local:   t.Local.Add(o.Local),
+		FICA:    t.FICA.Add(o.FICA),
+		IRMAA:   t.IRMAA.Add(o.IRMAA),
+		Total:   t.Total.Add(o.Total),
+	}
+}
+
+// MAGIBreakdown traces a year's modified adjusted gross income: adjusted gross income by
+// source, plus the tax-exempt interest MAGI adds back
+type MAGIBreakdown struct {
+	Wages                   decimal.Decimal `json:"wages"`                   // salaries less pre-tax FEHB premiums and plan deferrals
+	Pensions                decimal.Decimal `json:"pensions"`                // annuities, survivor annuities and the FERS supplement
+	RetirementDistributions decimal.Decimal `json:"retirementDistributions"` // taxable TSP, IRA and annuity income less QCDs
+	TaxableSocialSecurity   decimal.Decimal `json:"taxableSocialSecurity"`
+	CapitalGains            decimal.Decimal `json:"capitalGains"`
+	OtherIncome             decimal.Decimal `json:"otherIncome"`  // cash flow events, self-employment and passive income
+	IRADeduction            decimal.Decimal `json:"iraDeduction"` // deductible traditional IRA contributions, subtracted
+	AGI                     decimal.Decimal `json:"agi"`
+	TaxExemptInterest       decimal.Decimal `json:"taxExemptInterest"`
+	UntaxedSocialSecurity   decimal.Decimal `json:"untaxedSocialSecurity"` // excluded from MAGI for IRMAA, added back for the ACA
+	MAGI                    decimal.Decimal `json:"magi"`                  // AGI plus tax-exempt interest
+}
+
+// ACAMAGI returns the MAGI that sets the premium tax credit: MAGI plus untaxed Social
+// Security
+func (b MAGIBreakdown) ACAMAGI() decimal.Decimal {
+	return b.MAGI.Add(b.UntaxedSocialSecurity)
+}
+
+// EngineWarning is a structured notice raised by the projection engine, such as a
+// withdrawal the available balance could not cover
+type EngineWarning struct {
+	Year        int             `json:"year"`
+	Participant string          `json:"participant,omitempty"`
+	Code        string          `json:"code"`
+	Amount      decimal.Decimal `json:"amount"` // unfunded amount for shortfall warnings, the excess over a limit, or a guardrail cut
+	Message     string          `json:"message"`
+}
+
+// Engine warning codes
+const (
+	WarningWithdrawalShortfall   = "withdrawal_shortfall"
+	WarningDepositShortfall      = "deposit_shortfall"
+	WarningUnknownStateTax       = "unknown_state_tax"
+	WarningContributionLimit     = "contribution_limit"
+	WarningSEPPViolation         = "sepp_violation"
+	WarningVestingForfeiture     = "vesting_forfeiture"
+	WarningHorizonTruncated      = "horizon_truncated"
+	WarningPremiumShortfall      = "premium_shortfall"
+	WarningOutflowShortfall      = "outflow_shortfall"
+	WarningGuardrailCut          = "guardrail_cut"
+	WarningEarlyRothDistribution = "early_roth_distribution"
+	WarningFEHBFiveYearRule      = "fehb_five_year_rule"
+	WarningPartBLatePenalty      = "part_b_late_penalty"
+)
+
 // ScenarioSummary provides a summary of key metrics for a retirement scenario
 type ScenarioSummary struct {
 	Name                string           `json:"name"`
@@ -102,6 +280,13 @@ type ScenarioSummary struct {
 	FinalTSPBalance     decimal.Decimal  `json:"finalTspBalance"`
 	Projection          []AnnualCashFlow `json:"projection"`
 
+	// The retirement year, when it still carries part-year salary, and the first year spent
+	// fully retired. Net income from the full year is the representative retirement income;
+	// the fields are zero when the projection has no such year.
+	TransitionYear          int             `json:"transitionYear,omitempty"`
+	FirstFullRetirementYear int             `json:"firstFullRetirementYear,omitempty"`
+	FirstFullYearNetIncome  decimal.Decimal `json:"firstFullYearNetIncome"`
+
 	// Absolute calendar year comparisons for apples-to-apples analysis
 	NetIncome2030        decimal.Decimal `json:"netIncome2030"`
 	NetIncome2035        decimal.Decimal `json:"netIncome2035"`
@@ -112,6 +297,66 @@ type ScenarioSummary struct {
 
 	// IRMAA Risk Analysis
 	IRMAAAnalysis *IRMAAAnalysis `json:"irmaaAnalysis,omitempty"` // IRMAA risk analysis for this scenario
+
+	// Engine warnings from every projection year, in year order
+	Warnings []EngineWarning `json:"warnings,omitempty"`
+
+	// Key events that fall after the projection ends, and the projection_years that would
+	// include every key event of the scenario (zero when none falls outside)
+	HorizonWarnings        []EngineWarning `json:"horizonWarnings,omitempty"`
+	MinimumProjectionYears int             `json:"minimumProjectionYears,omitempty"`
+
+	// Guaranteed income against essential expenses; nil when no essentials are configured
+	DignityFloor *DignityFloorSummary `json:"dignityFloor,omitempty"`
+
+	// Net income against the spending target; nil when no expenses are configured
+	Spending *SpendingSummary `json:"spending,omitempty"`
+
+	// Taxes paid across the whole projection, by type
+	LifetimeTaxes TaxTotals `json:"lifetimeTaxes"`
+
+	// Survivor annuity election cost and benefit for each participant's pension
+	SurvivorElections []SurvivorElectionSummary `json:"survivorElections,omitempty"`
+}
+
+// SurvivorElectionSummary shows the reduction a survivor election applies to a pension.
+// ReductionFactor is the share of the unreduced annuity given up, e.g. 0.10 for a FERS full election.
+type SurvivorElectionSummary struct {
+	Participant      string          `json:"participant"`
+	Election         string          `json:"election"`
+	UnreducedAnnuity decimal.Decimal `json:"unreducedAnnuity"`
+	ReductionFactor  decimal.Decimal `json:"reductionFactor"`
+	ReducedAnnuity   decimal.Decimal `json:"reducedAnnuity"`
+	SurvivorAnnuity  decimal.Decimal `json:"survivorAnnuity"`
+}
+
+// DignityFloorSummary summarizes how well guaranteed income covers essential expenses
+// across the retired years of a projection
+type DignityFloorSummary struct {
+	YearsCovered    int             `json:"yearsCovered"`
+	YearsProjected  int             `json:"yearsProjected"`
+	MinCoverage     decimal.Decimal `json:"minCoverage"`
+	MinCoverageYear int             `json:"minCoverageYear"`
+
+	// First year from which the floor stays covered through the end of the projection,
+	// with each participant's age that year; zero when the final year is not covered
+	CoveredFromYear int            `json:"coveredFromYear,omitempty"`
+	CoveredFromAges map[string]int `json:"coveredFromAges,omitempty"`
+}
+
+// SpendingSummary summarizes how well net income funds the spending target across a
+// projection
+type SpendingSummary struct {
+	YearsFunded    int             `json:"yearsFunded"`
+	YearsProjected int             `json:"yearsProjected"`
+	TotalTarget    decimal.Decimal `json:"totalTarget"`
+	TotalShortfall decimal.Decimal `json:"totalShortfall"`
+
+	// First shortfall year, and the largest shortfall and its year; zero when every year
+	// is funded
+	FirstShortfallYear   int             `json:"firstShortfallYear,omitempty"`
+	LargestShortfall     decimal.Decimal `json:"largestShortfall"`
+	LargestShortfallYear int             `json:"largestShortfallYear,omitempty"`
 }
 
 // ScenarioComparison provides a comparison of all scenarios
@@ -121,6 +366,7 @@ type ScenarioComparison struct {
 	ImmediateImpact    ImpactAnalysis    `json:"immediateImpact"`
 	LongTermProjection LongTermAnalysis  `json:"longTermProjection"`
 	Assumptions        []string          `json:"assumptions"` // Dynamic assumptions from config
+	ParticipantLabels  ParticipantLabels `json:"participantLabels,omitempty"`
 }
 
 // ImpactAnalysis provides analysis of the immediate impact of retirement
@@ -202,6 +448,20 @@ type TaxableIncome struct {
 	OtherTaxableIncome decimal.Decimal `json:"otherTaxableIncome"`
 	WageIncome         decimal.Decimal `json:"wageIncome"`
 	InterestIncome     decimal.Decimal `json:"interestIncome"`
+
+	// Income streams included in OtherTaxableIncome, by tax character: net earnings from
+	// self-employment, and passive income such as rent and royalties
+	SelfEmploymentIncome decimal.Decimal `json:"selfEmploymentIncome"`
+	PassiveIncome        decimal.Decimal `json:"passiveIncome"`
+
+	// Preferential income taxed at the 0/15/20% capital gains rates
+	LongTermCapitalGains decimal.Decimal `json:"longTermCapitalGains"`
+	QualifiedDividends   decimal.Decimal `json:"qualifiedDividends"`
+
+	// Itemized deduction inputs, used when they exceed the standard deduction
+	SALTPaid                decimal.Decimal `json:"saltPaid"` // state/local income and property taxes before the SALT cap
+	MortgageInterest        decimal.Decimal `json:"mortgageInterest"`
+	CharitableContributions decimal.Decimal `json:"charitableContributions"`
 }
 
 // IRMAARisk represents the IRMAA risk status for a given year
@@ -225,159 +485,220 @@ type IRMAAAnalysis struct {
 
 // IRMAAYearRisk provides detailed IRMAA risk information for a single year
 type IRMAAYearRisk struct {
-	Year                int             `json:"year"`
-	MAGI                decimal.Decimal `json:"magi"`
-	Threshold           decimal.Decimal `json:"threshold"`
-	DistanceToThreshold decimal.Decimal `json:"distanceToThreshold"` // Negative if over, positive if under
-	RiskStatus          IRMAARisk       `json:"riskStatus"`
-	TierLevel           string          `json:"tierLevel"`
-	MonthlySurcharge    decimal.Decimal `json:"monthlySurcharge"`
-	AnnualCost          decimal.Decimal `json:"annualCost"`
+	Year                  int             `json:"year"`
+	SurchargeYear         int             `json:"surchargeYear"` // year the surcharge is paid, two after the MAGI
+	MAGI                  decimal.Decimal `json:"magi"`
+	Threshold             decimal.Decimal `json:"threshold"`
+	DistanceToThreshold   decimal.Decimal `json:"distanceToThreshold"` // Negative if over, positive if under
+	RiskStatus            IRMAARisk       `json:"riskStatus"`
+	TierLevel             string          `json:"tierLevel"`
+	MonthlySurcharge      decimal.Decimal `json:"monthlySurcharge"`
+	MonthlySurchargePartD decimal.Decimal `json:"monthlySurchargePartD"`
+	AnnualCost            decimal.Decimal `json:"annualCost"` // Part B and Part D surcharges
 }
 
-// NewAnnualCashFlow creates a new AnnualCashFlow with initialized participant maps
+// cashFlowDecimalFields is the number of per-participant decimal fields carved from one slab
+const cashFlowDecimalFields = 24
+
+// NewAnnualCashFlow creates a new AnnualCashFlow with zeroed per-participant values
 func NewAnnualCashFlow(year int, date time.Time, participantNames []string) *AnnualCashFlow {
-	acf := &AnnualCashFlow{
+	return NewAnnualCashFlowWithIndex(year, date, NewParticipantIndex(participantNames))
+}
+
+// NewAnnualCashFlowWithIndex creates a new AnnualCashFlow sharing an existing participant index.
+// Per-participant storage is carved from three slabs rather than one map per field, which keeps
+// long multi-scenario and Monte Carlo runs from allocating dozens of maps per projected year.
+func NewAnnualCashFlowWithIndex(year int, date time.Time, index *ParticipantIndex) *AnnualCashFlow {
+	n := index.Len()
+	decimals := make([]decimal.Decimal, cashFlowDecimalFields*n)
+	flags := make([]bool, 2*n)
+	next := func() []decimal.Decimal {
+		field := decimals[:n:n]
+		decimals = decimals[n:]
+		return field
+	}
+
+	return &AnnualCashFlow{
 		Year:                        year,
 		Date:                        date,
-		Ages:                        make(map[string]int),
-		Salaries:                    make(map[string]decimal.Decimal),
-		Pensions:                    make(map[string]decimal.Decimal),
-		SurvivorPensions:            make(map[string]decimal.Decimal),
-		TSPWithdrawals:              make(map[string]decimal.Decimal),
-		SSBenefits:                  make(map[string]decimal.Decimal),
-		FERSSupplements:             make(map[string]decimal.Decimal),
-		TSPBalances:                 make(map[string]decimal.Decimal),
-		ParticipantTSPContributions: make(map[string]decimal.Decimal),
-		IsDeceased:                  make(map[string]bool),
-		IsPartTime:                  make(map[string]bool),
-		PartTimeSalary:              make(map[string]decimal.Decimal),
-		PartTimeTSPContributions:    make(map[string]decimal.Decimal),
-		FERSSupplementReduction:     make(map[string]decimal.Decimal),
+		Ages:                        NewParticipantValues[int](index),
+		Salaries:                    newParticipantValuesWithBacking(index, next()),
+		Pensions:                    newParticipantValuesWithBacking(index, next()),
+		SurvivorPensions:            newParticipantValuesWithBacking(index, next()),
+		TSPWithdrawals:              newParticipantValuesWithBacking(index, next()),
+		SSBenefits:                  newParticipantValuesWithBacking(index, next()),
+		SpousalSSBenefits:           newParticipantValuesWithBacking(index, next()),
+		SurvivorSSBenefits:          newParticipantValuesWithBacking(index, next()),
+		SSEarningsTestWithheld:      newParticipantValuesWithBacking(index, next()),
+		FERSSupplements:             newParticipantValuesWithBacking(index, next()),
+		TSPAnnuities:                newParticipantValuesWithBacking(index, next()),
+		IncomeAnnuities:             newParticipantValuesWithBacking(index, next()),
+		OtherIncome:                 newParticipantValuesWithBacking(index, next()),
+		SelfEmploymentIncome:        newParticipantValuesWithBacking(index, next()),
+		TSPBalances:                 newParticipantValuesWithBacking(index, next()),
+		ParticipantTSPContributions: newParticipantValuesWithBacking(index, next()),
+		IRAWithdrawalsTraditional:   newParticipantValuesWithBacking(index, next()),
+		IRAWithdrawalsRoth:          newParticipantValuesWithBacking(index, next()),
+		IRABalances:                 newParticipantValuesWithBacking(index, next()),
+		IRAContributions:            newParticipantValuesWithBacking(index, next()),
+		EmployerPlanContributions:   newParticipantValuesWithBacking(index, next()),
+		EmployerPlanBalances:        newParticipantValuesWithBacking(index, next()),
+		IsDeceased:                  newParticipantValuesWithBacking(index, flags[:n:n]),
+		IsPartTime:                  newParticipantValuesWithBacking(index, flags[n:]),
+		PartTimeSalary:              newParticipantValuesWithBacking(index, next()),
+		PartTimeTSPContributions:    newParticipantValuesWithBacking(index, next()),
+		FERSSupplementReduction:     newParticipantValuesWithBacking(index, next()),
 		WithdrawalTaxable:           decimal.Zero,
 		WithdrawalTraditional:       decimal.Zero,
 		WithdrawalRoth:              decimal.Zero,
+		CapitalGainsRealized:        decimal.Zero,
 		HealthcareCosts:             HealthcareCostBreakdown{},
 	}
+}
 
-	// Initialize all participants with zero values
-	for _, name := range participantNames {
-		acf.Ages[name] = 0
-		acf.Salaries[name] = decimal.Zero
-		acf.Pensions[name] = decimal.Zero
-		acf.SurvivorPensions[name] = decimal.Zero
-		acf.TSPWithdrawals[name] = decimal.Zero
-		acf.SSBenefits[name] = decimal.Zero
-		acf.FERSSupplements[name] = decimal.Zero
-		acf.TSPBalances[name] = decimal.Zero
-		acf.ParticipantTSPContributions[name] = decimal.Zero
-		acf.IsDeceased[name] = false
-		acf.IsPartTime[name] = false
-		acf.PartTimeSalary[name] = decimal.Zero
-		acf.PartTimeTSPContributions[name] = decimal.Zero
-		acf.FERSSupplementReduction[name] = decimal.Zero
-	}
-
-	return acf
+// CloneWithIndex returns a deep copy of the cash flow whose per-participant values are
+// positioned by index, so a year computed for one projection can be reused by another
+func (acf *AnnualCashFlow) CloneWithIndex(index *ParticipantIndex) AnnualCashFlow {
+	c := *acf
+	c.Ages = acf.Ages.withIndex(index)
+	c.Salaries = acf.Salaries.withIndex(index)
+	c.Pensions = acf.Pensions.withIndex(index)
+	c.SurvivorPensions = acf.SurvivorPensions.withIndex(index)
+	c.TSPWithdrawals = acf.TSPWithdrawals.withIndex(index)
+	c.SSBenefits = acf.SSBenefits.withIndex(index)
+	c.SpousalSSBenefits = acf.SpousalSSBenefits.withIndex(index)
+	c.SurvivorSSBenefits = acf.SurvivorSSBenefits.withIndex(index)
+	c.SSEarningsTestWithheld = acf.SSEarningsTestWithheld.withIndex(index)
+	c.FERSSupplements = acf.FERSSupplements.withIndex(index)
+	c.TSPAnnuities = acf.TSPAnnuities.withIndex(index)
+	c.IncomeAnnuities = acf.IncomeAnnuities.withIndex(index)
+	c.OtherIncome = acf.OtherIncome.withIndex(index)
+	c.SelfEmploymentIncome = acf.SelfEmploymentIncome.withIndex(index)
+	c.TSPBalances = acf.TSPBalances.withIndex(index)
+	c.ParticipantTSPContributions = acf.ParticipantTSPContributions.withIndex(index)
+	c.IRAWithdrawalsTraditional = acf.IRAWithdrawalsTraditional.withIndex(index)
+	c.IRAWithdrawalsRoth = acf.IRAWithdrawalsRoth.withIndex(index)
+	c.IRABalances = acf.IRABalances.withIndex(index)
+	c.IRAContributions = acf.IRAContributions.withIndex(index)
+	c.EmployerPlanContributions = acf.EmployerPlanContributions.withIndex(index)
+	c.EmployerPlanBalances = acf.EmployerPlanBalances.withIndex(index)
+	c.IsDeceased = acf.IsDeceased.withIndex(index)
+	c.IsPartTime = acf.IsPartTime.withIndex(index)
+	c.PartTimeSalary = acf.PartTimeSalary.withIndex(index)
+	c.PartTimeTSPContributions = acf.PartTimeTSPContributions.withIndex(index)
+	c.FERSSupplementReduction = acf.FERSSupplementReduction.withIndex(index)
+	c.PeriodTSPBalances = slices.Clone(acf.PeriodTSPBalances)
+	c.Warnings = slices.Clone(acf.Warnings)
+	c.Expenses = maps.Clone(acf.Expenses)
+	c.Buckets = maps.Clone(acf.Buckets)
+	return c
 }
 
-// SyncLegacyFields syncs the participant maps to legacy fields for backward compatibility
-// This method assumes "robert" and "dawn" are the first two participants if they exist
-// (Legacy SyncLegacyFields removed: scalar convenience fields deprecated.)
+// sumAmounts totals per-participant amounts
+func sumAmounts(pv ParticipantValues[decimal.Decimal]) decimal.Decimal {
+	total := decimal.Zero
+	for _, v := range pv.Values() {
+		total = total.Add(v)
+	}
+	return total
+}
 
 // GetParticipantNames returns all participant names from the cash flow
 func (acf *AnnualCashFlow) GetParticipantNames() []string {
-	names := make([]string, 0, len(acf.Ages))
-	for name := range acf.Ages {
-		names = append(names, name)
-	}
-	return names
+	return append([]string(nil), acf.Ages.Names()...)
 }
 
 // GetTotalSalary returns the sum of all participant salaries
 func (acf *AnnualCashFlow) GetTotalSalary() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.Salaries)
-	for _, name := range names {
-		total = total.Add(acf.Salaries[name])
-	}
-	return total
+	return sumAmounts(acf.Salaries)
 }
 
 // GetTotalPension returns the sum of all participant pensions
 func (acf *AnnualCashFlow) GetTotalPension() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.Pensions)
-	for _, name := range names {
-		total = total.Add(acf.Pensions[name])
-	}
-	return total
+	return sumAmounts(acf.Pensions)
 }
 
 // GetTotalSurvivorPension returns the sum of all survivor pension amounts
 func (acf *AnnualCashFlow) GetTotalSurvivorPension() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.SurvivorPensions)
-	for _, name := range names {
-		total = total.Add(acf.SurvivorPensions[name])
-	}
-	return total
+	return sumAmounts(acf.SurvivorPensions)
 }
 
 // GetTotalTSPWithdrawal returns the sum of all participant TSP withdrawals
 func (acf *AnnualCashFlow) GetTotalTSPWithdrawal() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.TSPWithdrawals)
-	for _, name := range names {
-		total = total.Add(acf.TSPWithdrawals[name])
-	}
-	return total
+	return sumAmounts(acf.TSPWithdrawals)
+}
+
+// GetTotalIRAWithdrawal returns the sum of all traditional and Roth IRA distributions
+func (acf *AnnualCashFlow) GetTotalIRAWithdrawal() decimal.Decimal {
+	return acf.GetTotalTraditionalIRAWithdrawal().Add(sumAmounts(acf.IRAWithdrawalsRoth))
+}
+
+// GetTotalTraditionalIRAWithdrawal returns the sum of all traditional IRA distributions
+func (acf *AnnualCashFlow) GetTotalTraditionalIRAWithdrawal() decimal.Decimal {
+	return sumAmounts(acf.IRAWithdrawalsTraditional)
+}
+
+// GetTotalIRABalance returns the sum of all participant IRA balances
+func (acf *AnnualCashFlow) GetTotalIRABalance() decimal.Decimal {
+	return sumAmounts(acf.IRABalances)
 }
 
 // GetTotalSSBenefit returns the sum of all participant Social Security benefits
 func (acf *AnnualCashFlow) GetTotalSSBenefit() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.SSBenefits)
-	for _, name := range names {
-		total = total.Add(acf.SSBenefits[name])
-	}
-	return total
+	return sumAmounts(acf.SSBenefits)
 }
 
 // GetTotalFERSSupplement returns the sum of all participant FERS supplements
 func (acf *AnnualCashFlow) GetTotalFERSSupplement() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.FERSSupplements)
-	for _, name := range names {
-		total = total.Add(acf.FERSSupplements[name])
-	}
-	return total
+	return sumAmounts(acf.FERSSupplements)
+}
+
+// GetTotalTSPAnnuity returns the sum of all TSP life annuity payments
+func (acf *AnnualCashFlow) GetTotalTSPAnnuity() decimal.Decimal {
+	return sumAmounts(acf.TSPAnnuities)
+}
+
+// GetTotalIncomeAnnuity returns the sum of all purchased income annuity payments
+func (acf *AnnualCashFlow) GetTotalIncomeAnnuity() decimal.Decimal {
+	return sumAmounts(acf.IncomeAnnuities)
+}
+
+// GetTaxableIncomeAnnuity returns the income annuity payments subject to income tax: all
+// of a qualified annuity's and the part of a nonqualified annuity's that is not a return
+// of premium
+func (acf *AnnualCashFlow) GetTaxableIncomeAnnuity() decimal.Decimal {
+	return acf.GetTotalIncomeAnnuity().Sub(acf.IncomeAnnuityTaxFree)
+}
+
+// GetTotalOtherIncome returns the sum of all rental, self-employment and other income streams
+func (acf *AnnualCashFlow) GetTotalOtherIncome() decimal.Decimal {
+	return sumAmounts(acf.OtherIncome)
+}
+
+// GetGuaranteedIncome returns income that does not depend on portfolio balances: pensions,
+// survivor annuities, Social Security, FERS supplements and TSP and purchased life annuities
+func (acf *AnnualCashFlow) GetGuaranteedIncome() decimal.Decimal {
+	return acf.GetTotalPension().
+		Add(acf.GetTotalSurvivorPension()).
+		Add(acf.GetTotalSSBenefit()).
+		Add(acf.GetTotalFERSSupplement()).
+		Add(acf.GetTotalTSPAnnuity()).
+		Add(acf.GetTotalIncomeAnnuity())
 }
 
 // GetTotalTSPBalance returns the sum of all participant TSP balances
 func (acf *AnnualCashFlow) GetTotalTSPBalance() decimal.Decimal {
-	total := decimal.Zero
-	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.TSPBalances)
-	for _, name := range names {
-		total = total.Add(acf.TSPBalances[name])
-	}
-	return total
+	return sumAmounts(acf.TSPBalances)
 }
 
 // GetLivingParticipants returns a list of living participants
 func (acf *AnnualCashFlow) GetLivingParticipants() []string {
 	living := make([]string, 0)
 	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.IsDeceased)
+	names := append([]string(nil), acf.IsDeceased.Names()...)
+	sort.Strings(names)
 	for _, name := range names {
-		if !acf.IsDeceased[name] {
+		if !acf.IsDeceased.Get(name) {
 			living = append(living, name)
 		}
 	}
@@ -388,9 +709,10 @@ func (acf *AnnualCashFlow) GetLivingParticipants() []string {
 func (acf *AnnualCashFlow) GetDeceasedParticipants() []string {
 	deceased := make([]string, 0)
 	// Sort participant names for deterministic processing order
-	names := SortedMapKeys(acf.IsDeceased)
+	names := append([]string(nil), acf.IsDeceased.Names()...)
+	sort.Strings(names)
 	for _, name := range names {
-		if acf.IsDeceased[name] {
+		if acf.IsDeceased.Get(name) {
 			deceased = append(deceased, name)
 		}
 	}
@@ -404,14 +726,19 @@ func (acf *AnnualCashFlow) CalculateTotalIncome() decimal.Decimal {
 		Add(acf.GetTotalSurvivorPension()).
 		Add(acf.GetTotalTSPWithdrawal()).
 		Add(acf.GetTotalSSBenefit()).
-		Add(acf.GetTotalFERSSupplement())
+		Add(acf.GetTotalFERSSupplement()).
+		Add(acf.GetTotalTSPAnnuity()).
+		Add(acf.GetTotalIncomeAnnuity()).
+		Add(acf.GetTotalOtherIncome()).
+		Add(acf.GetTotalIRAWithdrawal()).
+		Add(acf.WithdrawalTaxable)
 }
 
 // CalculateTotalDeductions calculates the total deductions for the year
 func (acf *AnnualCashFlow) CalculateTotalDeductions() decimal.Decimal {
 	return acf.FederalTax.Add(acf.StateTax).Add(acf.LocalTax).Add(acf.FICATax).
-		Add(acf.TotalTSPContributions).Add(acf.FEHBPremium).Add(acf.MedicarePremium).
-		Add(acf.HealthcareCosts.Total)
+		Add(acf.TotalTSPContributions).Add(sumAmounts(acf.IRAContributions)).Add(sumAmounts(acf.EmployerPlanContributions)).Add(acf.FEHBPremium).Add(acf.MedicarePremium).
+		Add(acf.HealthcareCosts.Total).Add(acf.QualifiedCharitableDistributions).Add(acf.RothConversions)
 }
 
 // CalculateNetIncome calculates the net income for the year
@@ -429,3 +756,21 @@ func (acf *AnnualCashFlow) TotalTSPBalance() decimal.Decimal {
 func (acf *AnnualCashFlow) IsTSPDepleted() bool {
 	return acf.TotalTSPBalance().LessThanOrEqual(decimal.Zero)
 }
+
+// IsTransitionYear reports whether the household retired this year while still drawing
+// part of a year's salary. Such a year mixes working and retirement income and is not
+// representative of either.
+func (acf *AnnualCashFlow) IsTransitionYear() bool {
+	return acf.IsRetired && acf.GetTotalSalary().IsPositive()
+}
+
+// FirstFullRetirementYear returns the index of the first projection year the household
+// spends entirely retired, or -1 when the projection has none
+func FirstFullRetirementYear(projection []AnnualCashFlow) int {
+	for i := range projection {
+		if projection[i].IsRetired && !projection[i].IsTransitionYear() {
+			return i
+		}
+	}
+	return -1
+}
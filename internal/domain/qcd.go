@@ -0,0 +1,34 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// QCDSchedule describes qualified charitable distributions (QCDs) a participant
+// sends directly from traditional retirement savings to charity once age 70½.
+// QCDs count toward the RMD but are excluded from taxable income and MAGI.
+// TSP cannot make QCDs itself, so this assumes the traditional balance has been
+// (or will be) rolled to an IRA for the purpose.
+type QCDSchedule struct {
+	// AnnualAmount applies to every eligible year without an explicit entry
+	AnnualAmount decimal.Decimal `yaml:"annual_amount,omitempty" json:"annual_amount,omitempty"`
+	// Distributions override AnnualAmount for specific calendar years
+	Distributions []QCDDistribution `yaml:"distributions,omitempty" json:"distributions,omitempty"`
+}
+
+// QCDDistribution is the QCD amount for one calendar year
+type QCDDistribution struct {
+	Year   int             `yaml:"year" json:"year"`
+	Amount decimal.Decimal `yaml:"amount" json:"amount"`
+}
+
+// AmountForYear returns the scheduled QCD for a calendar year
+func (qs *QCDSchedule) AmountForYear(year int) decimal.Decimal {
+	if qs == nil {
+		return decimal.Zero
+	}
+	for _, d := range qs.Distributions {
+		if d.Year == year {
+			return d.Amount
+		}
+	}
+	return qs.AnnualAmount
+}
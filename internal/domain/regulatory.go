@@ -31,6 +31,18 @@ type FederalTaxRules struct {
 	StandardDeduction        StandardDeductions `yaml:"standard_deduction" json:"standard_deduction"`
 	AdditionalDeduction65Plus decimal.Decimal   `yaml:"additional_deduction_65_plus" json:"additional_deduction_65_plus"`
 	BracketsMFJ              []TaxBracket       `yaml:"brackets_married_filing_jointly" json:"brackets_married_filing_jointly"`
+	BracketsSingle           []TaxBracket       `yaml:"brackets_single" json:"brackets_single"`
+	CapitalGainsBracketsMFJ    []TaxBracket     `yaml:"capital_gains_brackets_married_filing_jointly" json:"capital_gains_brackets_married_filing_jointly"`
+	CapitalGainsBracketsSingle []TaxBracket     `yaml:"capital_gains_brackets_single" json:"capital_gains_brackets_single"`
+	NetInvestmentIncomeTax     NIITRules        `yaml:"net_investment_income_tax" json:"net_investment_income_tax"`
+	SALTCap                    decimal.Decimal  `yaml:"salt_cap" json:"salt_cap"`
+}
+
+// NIITRules contains the Net Investment Income Tax rate and MAGI thresholds
+type NIITRules struct {
+	Rate            decimal.Decimal `yaml:"rate" json:"rate"`
+	ThresholdMFJ    decimal.Decimal `yaml:"threshold_mfj" json:"threshold_mfj"`
+	ThresholdSingle decimal.Decimal `yaml:"threshold_single" json:"threshold_single"`
 }
 
 // StandardDeductions contains standard deduction amounts by filing status
@@ -59,9 +71,10 @@ type MedicareFICA struct {
 	HighIncomeThresholdMFJ  decimal.Decimal `yaml:"high_income_threshold_mfj" json:"high_income_threshold_mfj"`
 }
 
-// MedicareRules contains Medicare Part B premium rules
+// MedicareRules contains Medicare Part B and Part D premium rules
 type MedicareRules struct {
 	PartBBasePremium decimal.Decimal           `yaml:"part_b_base_premium" json:"part_b_base_premium"`
+	PartDBasePremium decimal.Decimal           `yaml:"part_d_base_premium" json:"part_d_base_premium"`
 	IRMAAThresholds  []MedicareIRMAAThreshold `yaml:"irmaa_tiers" json:"irmaa_tiers"`
 }
 
@@ -104,6 +117,10 @@ type MonteCarloDefaults struct {
 	FEHBVariability      decimal.Decimal       `yaml:"fehb_variability" json:"fehb_variability"`
 	MaxReasonableIncome  decimal.Decimal       `yaml:"max_reasonable_income" json:"max_reasonable_income"`
 	DefaultTSPAllocation DefaultTSPAllocation  `yaml:"default_tsp_allocation" json:"default_tsp_allocation"`
+	TSPReturnBounds      *RateBounds           `yaml:"tsp_return_bounds,omitempty" json:"tsp_return_bounds,omitempty"`
+	InflationBounds      *RateBounds           `yaml:"inflation_bounds,omitempty" json:"inflation_bounds,omitempty"`
+	COLABounds           *RateBounds           `yaml:"cola_bounds,omitempty" json:"cola_bounds,omitempty"`
+	FEHBInflationBounds  *RateBounds           `yaml:"fehb_inflation_bounds,omitempty" json:"fehb_inflation_bounds,omitempty"`
 }
 
 // DefaultTSPAllocation contains default TSP fund allocation
@@ -137,6 +154,20 @@ type TaxThreshold struct {
 type SocialSecurityBenefitRules struct {
 	EarlyRetirementReduction EarlyRetirementRates `yaml:"early_retirement_reduction" json:"early_retirement_reduction"`
 	DelayedRetirementCredit  decimal.Decimal      `yaml:"delayed_retirement_credit" json:"delayed_retirement_credit"`
+	WEPGPO                   WEPGPORules          `yaml:"wep_gpo" json:"wep_gpo"`
+	EarningsTest             EarningsTestRules    `yaml:"earnings_test" json:"earnings_test"`
+}
+
+// EarningsTestRules contains the retirement earnings test exempt amounts
+type EarningsTestRules struct {
+	AnnualExemptAmount  decimal.Decimal `yaml:"annual_exempt_amount" json:"annual_exempt_amount"`
+	FRAYearExemptAmount decimal.Decimal `yaml:"fra_year_exempt_amount" json:"fra_year_exempt_amount"`
+}
+
+// WEPGPORules selects the Windfall Elimination Provision / Government Pension Offset regime
+type WEPGPORules struct {
+	Repealed       *bool           `yaml:"repealed,omitempty" json:"repealed,omitempty"` // nil leaves the scenario setting unchanged
+	FirstBendPoint decimal.Decimal `yaml:"first_bend_point" json:"first_bend_point"`
 }
 
 // EarlyRetirementRates contains the rates for early retirement reduction
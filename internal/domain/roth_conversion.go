@@ -25,6 +25,8 @@ type RothConversionPlan struct {
 	TargetBracket    int                   `json:"targetBracket"`
 	Objective        OptimizationObjective `json:"objective"`
 
+	ParticipantLabels ParticipantLabels `json:"participantLabels,omitempty"`
+
 	Baseline     *ScenarioSummary    `json:"baseline"`
 	Recommended  *ConversionOutcome  `json:"recommended"`
 	Alternatives []ConversionOutcome `json:"alternatives"`
@@ -73,13 +75,14 @@ type ConversionStrategy struct {
 
 // ConversionOutcome represents the results of applying a conversion strategy
 type ConversionOutcome struct {
-	Strategy      ConversionStrategy `json:"strategy"`
-	Projection    *ScenarioSummary   `json:"projection"`
-	LifetimeTax   decimal.Decimal    `json:"lifetimeTax"`
-	LifetimeIRMAA decimal.Decimal    `json:"lifetimeIrmaa"`
-	FinalBalances FinalBalances      `json:"finalBalances"`
-	NetBenefit    decimal.Decimal    `json:"netBenefit"`
-	ROI           decimal.Decimal    `json:"roi"`
+	Strategy                 ConversionStrategy `json:"strategy"`
+	Projection               *ScenarioSummary   `json:"projection"`
+	LifetimeTax              decimal.Decimal    `json:"lifetimeTax"`
+	LifetimeIRMAA            decimal.Decimal    `json:"lifetimeIrmaa"`
+	LifetimePremiumTaxCredit decimal.Decimal    `json:"lifetimePremiumTaxCredit"` // marketplace premium tax credits before Medicare
+	FinalBalances            FinalBalances      `json:"finalBalances"`
+	NetBenefit               decimal.Decimal    `json:"netBenefit"`
+	ROI                      decimal.Decimal    `json:"roi"`
 }
 
 // FinalBalances represents account balances at the end of projection
@@ -91,14 +94,15 @@ type FinalBalances struct {
 
 // ConversionAnalysis provides detailed analysis of conversion strategies
 type ConversionAnalysis struct {
-	TotalConversions    decimal.Decimal     `json:"totalConversions"`
-	TotalTaxPaid        decimal.Decimal     `json:"totalTaxPaid"`
-	IRMAASavings        decimal.Decimal     `json:"irmaaSavings"`
-	RMDTaxReduction     decimal.Decimal     `json:"rmdTaxReduction"`
-	NetBenefit          decimal.Decimal     `json:"netBenefit"`
-	ROI                 decimal.Decimal     `json:"roi"`
-	Recommendation      string              `json:"recommendation"`
-	SensitivityAnalysis SensitivityAnalysis `json:"sensitivityAnalysis"`
+	TotalConversions     decimal.Decimal     `json:"totalConversions"`
+	TotalTaxPaid         decimal.Decimal     `json:"totalTaxPaid"`
+	IRMAASavings         decimal.Decimal     `json:"irmaaSavings"`
+	PremiumTaxCreditLost decimal.Decimal     `json:"premiumTaxCreditLost"` // premium tax credits the conversions give up
+	RMDTaxReduction      decimal.Decimal     `json:"rmdTaxReduction"`
+	NetBenefit           decimal.Decimal     `json:"netBenefit"`
+	ROI                  decimal.Decimal     `json:"roi"`
+	Recommendation       string              `json:"recommendation"`
+	SensitivityAnalysis  SensitivityAnalysis `json:"sensitivityAnalysis"`
 }
 
 // SensitivityAnalysis shows how results change with different conversion amounts
@@ -0,0 +1,45 @@
+package domain
+
+import "strings"
+
+// stateNames maps postal codes to the lowercase state names used as state rule keys
+var stateNames = map[string]string{
+	"AL": "alabama", "AK": "alaska", "AZ": "arizona", "AR": "arkansas", "CA": "california",
+	"CO": "colorado", "CT": "connecticut", "DE": "delaware", "DC": "district of columbia",
+	"FL": "florida", "GA": "georgia", "HI": "hawaii", "ID": "idaho", "IL": "illinois",
+	"IN": "indiana", "IA": "iowa", "KS": "kansas", "KY": "kentucky", "LA": "louisiana",
+	"ME": "maine", "MD": "maryland", "MA": "massachusetts", "MI": "michigan", "MN": "minnesota",
+	"MS": "mississippi", "MO": "missouri", "MT": "montana", "NE": "nebraska", "NV": "nevada",
+	"NH": "new hampshire", "NJ": "new jersey", "NM": "new mexico", "NY": "new york",
+	"NC": "north carolina", "ND": "north dakota", "OH": "ohio", "OK": "oklahoma", "OR": "oregon",
+	"PA": "pennsylvania", "RI": "rhode island", "SC": "south carolina", "SD": "south dakota",
+	"TN": "tennessee", "TX": "texas", "UT": "utah", "VT": "vermont", "VA": "virginia",
+	"WA": "washington", "WV": "west virginia", "WI": "wisconsin", "WY": "wyoming",
+}
+
+// noIncomeTaxStates levy no tax on wages or retirement income
+var noIncomeTaxStates = map[string]bool{
+	"alaska": true, "florida": true, "nevada": true, "new hampshire": true, "south dakota": true,
+	"tennessee": true, "texas": true, "washington": true, "wyoming": true,
+}
+
+// NormalizeState returns the lowercase name of a state given as a postal code or a
+// name in any case ("PA", "Pennsylvania"); ok is false for an unknown state
+func NormalizeState(state string) (name string, ok bool) {
+	state = strings.TrimSpace(state)
+	if name, ok := stateNames[strings.ToUpper(state)]; ok {
+		return name, true
+	}
+	name = strings.ToLower(state)
+	for _, known := range stateNames {
+		if known == name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// StateHasNoIncomeTax reports whether a normalized state levies no income tax
+func StateHasNoIncomeTax(name string) bool {
+	return noIncomeTaxStates[name]
+}
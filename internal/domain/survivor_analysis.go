@@ -13,6 +13,9 @@ type SurvivorViabilityAnalysis struct {
 	DeathAge            int    `json:"deathAge"`
 	SurvivorAge         int    `json:"survivorAge"`
 
+	// Display names for the participant keys above
+	ParticipantLabels ParticipantLabels `json:"participantLabels,omitempty"`
+
 	// Pre-death analysis (year before death)
 	PreDeathAnalysis SurvivorYearAnalysis `json:"preDeathAnalysis"`
 
@@ -145,7 +148,7 @@ func GenerateSurvivorRecommendations(assessment SurvivorViabilityAssessment) []s
 	var recommendations []string
 
 	if assessment.ShortfallPercentage.GreaterThan(decimal.NewFromFloat(0.10)) {
-		recommendations = append(recommendations, "⚠️ Survivor income falls short of target by "+assessment.ShortfallPercentage.StringFixed(1)+"%")
+		recommendations = append(recommendations, "⚠️ Survivor income falls short of target by "+FormatPercent(assessment.ShortfallPercentage))
 	}
 
 	if assessment.TSPLongevityChange < -5 {
@@ -0,0 +1,116 @@
+// Package hooks runs the post-run hooks a configuration declares, handing each the
+// results of a completed run so rpgo can feed dashboards and other automation.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// DefaultTimeout bounds a hook that sets no timeout_seconds
+const DefaultTimeout = 30 * time.Second
+
+// Payload is the JSON document every hook receives
+type Payload struct {
+	InputFile   string                     `json:"inputFile"`
+	CompletedAt time.Time                  `json:"completedAt"`
+	Results     *domain.ScenarioComparison `json:"results"`
+}
+
+// HookError reports a hook that failed
+type HookError struct {
+	Hook string
+	Err  error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("post-run hook %s failed: %v", e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error { return e.Err }
+
+// RunPostRun runs the hooks in order, each with the payload, and returns an error for
+// every hook that failed. A failing hook does not stop the ones after it.
+func RunPostRun(ctx context.Context, hooks []domain.PostRunHook, payload Payload) []error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return []error{fmt.Errorf("encoding post-run hook payload: %w", err)}
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		timeout := DefaultTimeout
+		if hook.TimeoutSeconds > 0 {
+			timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+		if hook.URL != "" {
+			err = postResults(hookCtx, hook, body)
+		} else {
+			err = runCommand(hookCtx, hook, payload.InputFile, body)
+		}
+		cancel()
+		if err != nil {
+			errs = append(errs, &HookError{Hook: hook.Label(), Err: err})
+		}
+	}
+	return errs
+}
+
+// runCommand runs the hook's command through the shell with the payload on standard
+// input. The command's output passes through to rpgo's standard error so it does not mix
+// with the report.
+func runCommand(ctx context.Context, hook domain.PostRunHook, inputFile string, body []byte) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, hook.Command)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "RPGO_INPUT_FILE="+inputFile, "RPGO_HOOK_NAME="+hook.Label())
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("timed out: %w", ctx.Err())
+		}
+		return err
+	}
+	return nil
+}
+
+// postResults POSTs the payload to the hook's URL. Any non-2xx response is a failure.
+func postResults(ctx context.Context, hook domain.PostRunHook, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rpgo")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", hook.URL, resp.Status)
+	}
+	return nil
+}
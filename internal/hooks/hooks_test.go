@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPayload() Payload {
+	return Payload{
+		InputFile:   "config.yaml",
+		CompletedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Results:     &domain.ScenarioComparison{Scenarios: []domain.ScenarioSummary{{Name: "Base"}}},
+	}
+}
+
+func TestRunPostRunPostsResults(t *testing.T) {
+	t.Setenv("DASHBOARD_TOKEN", "secret")
+	var received Payload
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	errs := RunPostRun(context.Background(), []domain.PostRunHook{
+		{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer ${DASHBOARD_TOKEN}"}},
+	}, testPayload())
+	assert.Empty(t, errs)
+	assert.Equal(t, "Bearer secret", auth)
+	assert.Equal(t, "config.yaml", received.InputFile)
+	require.Len(t, received.Results.Scenarios, 1)
+	assert.Equal(t, "Base", received.Results.Scenarios[0].Name)
+}
+
+func TestRunPostRunReportsEveryFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	errs := RunPostRun(context.Background(), []domain.PostRunHook{
+		{Name: "dashboard", URL: server.URL},
+		{Name: "second", URL: server.URL},
+	}, testPayload())
+	require.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], "post-run hook dashboard failed")
+	assert.ErrorContains(t, errs[0], "502")
+}
+
+func TestRunPostRunCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "results.json")
+	errs := RunPostRun(context.Background(), []domain.PostRunHook{
+		{Command: `cat > "` + out + `" && test "$RPGO_INPUT_FILE" = config.yaml`},
+		{Name: "failing", Command: "exit 3"},
+	}, testPayload())
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "post-run hook failing failed")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var received Payload
+	require.NoError(t, json.Unmarshal(data, &received))
+	assert.Equal(t, "Base", received.Results.Scenarios[0].Name)
+}
@@ -16,6 +16,8 @@ type Recommendation struct {
 }
 
 // AnalyzeScenarios determines the scenario with highest first-year retirement net income.
+// The first full year of retirement is used where there is one, so a transition year's
+// part-year salary does not favour later retirement dates.
 // Extracted from embedded console logic for testability.
 func AnalyzeScenarios(results *domain.ScenarioComparison) Recommendation {
 	baseline := results.BaselineNetIncome
@@ -26,10 +28,14 @@ func AnalyzeScenarios(results *domain.ScenarioComparison) Recommendation {
 	var ranks []ranked
 	for _, sc := range results.Scenarios {
 		var yrIncome decimal.Decimal
-		for _, y := range sc.Projection {
-			if y.IsRetired {
-				yrIncome = y.NetIncome
-				break
+		if idx := domain.FirstFullRetirementYear(sc.Projection); idx >= 0 {
+			yrIncome = sc.Projection[idx].NetIncome
+		} else {
+			for _, y := range sc.Projection {
+				if y.IsRetired {
+					yrIncome = y.NetIncome
+					break
+				}
 			}
 		}
 		ranks = append(ranks, ranked{sc.Name, yrIncome})
@@ -43,3 +43,29 @@ func TestAnalyzeScenarios_SelectsHighestFirstRetirementYearIncome(t *testing.T)
 		return
 	}
 }
+
+func TestAnalyzeScenarios_UsesFirstFullRetirementYear(t *testing.T) {
+	transition := func(net, salary int64) domain.AnnualCashFlow {
+		cf := domain.NewAnnualCashFlow(1, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), []string{"Pat"})
+		cf.Salaries.Set("Pat", decimal.NewFromInt(salary))
+		cf.NetIncome = decimal.NewFromInt(net)
+		cf.IsRetired = true
+		return *cf
+	}
+	comparison := &domain.ScenarioComparison{
+		BaselineNetIncome: decimal.NewFromInt(100000),
+		Scenarios: []domain.ScenarioSummary{
+			// Retiring late in the year keeps most of a salary in the transition year
+			{Name: "Retire December", Projection: []domain.AnnualCashFlow{transition(120000, 90000), makeCashFlow(2, decimal.NewFromInt(80000), true)}},
+			{Name: "Retire June", Projection: []domain.AnnualCashFlow{transition(95000, 45000), makeCashFlow(2, decimal.NewFromInt(85000), true)}},
+		},
+	}
+
+	rec := AnalyzeScenarios(comparison)
+	if rec.ScenarioName != "Retire June" {
+		t.Fatalf("Expected the higher first full year to win, got %s", rec.ScenarioName)
+	}
+	if !rec.FirstRetirementNet.Equal(decimal.NewFromInt(85000)) {
+		t.Errorf("Expected first full year net 85000, got %s", rec.FirstRetirementNet)
+	}
+}
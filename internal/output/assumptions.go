@@ -3,16 +3,15 @@ package output
 import (
 	"fmt"
 	"github.com/rgehrsitz/rpgo/internal/domain"
-	"github.com/shopspring/decimal"
 )
 
 // DefaultAssumptions lists key modeling assumptions rendered in detailed outputs.
 // Future: could be loaded from configuration or generated dynamically.
 var DefaultAssumptions = []string{
-	"General COLA (FERS pension & SS): 2.5% annually",
-	"FEHB premium inflation: 4.0% annually",
-	"TSP growth pre-retirement: 7.0% annually",
-	"TSP growth post-retirement: 5.0% annually",
+	"General COLA (FERS pension & SS): 2.50% annually",
+	"FEHB premium inflation: 4.00% annually",
+	"TSP growth pre-retirement: 7.00% annually",
+	"TSP growth post-retirement: 5.00% annually",
 	"Social Security wage base indexing: ~5% annually (2025 est: $168,600)",
 	"Tax brackets: 2025 levels held constant (no inflation indexing)",
 }
@@ -20,13 +19,11 @@ var DefaultAssumptions = []string{
 // GenerateAssumptions creates dynamic assumptions list from actual config values
 func GenerateAssumptions(assumptions *domain.GlobalAssumptions) []string {
 	return []string{
-		fmt.Sprintf("General COLA (FERS pension & SS): %.1f%% annually", assumptions.COLAGeneralRate.Mul(decimalHundred).InexactFloat64()),
-		fmt.Sprintf("FEHB premium inflation: %.1f%% annually", assumptions.FEHBPremiumInflation.Mul(decimalHundred).InexactFloat64()),
-		fmt.Sprintf("TSP growth pre-retirement: %.1f%% annually", assumptions.TSPReturnPreRetirement.Mul(decimalHundred).InexactFloat64()),
-		fmt.Sprintf("TSP growth post-retirement: %.1f%% annually", assumptions.TSPReturnPostRetirement.Mul(decimalHundred).InexactFloat64()),
+		fmt.Sprintf("General COLA (FERS pension & SS): %s annually", domain.FormatRate(assumptions.COLAGeneralRate)),
+		fmt.Sprintf("FEHB premium inflation: %s annually", domain.FormatRate(assumptions.FEHBPremiumInflation)),
+		fmt.Sprintf("TSP growth pre-retirement: %s annually", domain.FormatRate(assumptions.TSPReturnPreRetirement)),
+		fmt.Sprintf("TSP growth post-retirement: %s annually", domain.FormatRate(assumptions.TSPReturnPostRetirement)),
 		"Social Security wage base indexing: ~5% annually (2025 est: $168,600)",
 		"Tax brackets: 2025 levels held constant (no inflation indexing)",
 	}
 }
-
-var decimalHundred = decimal.NewFromInt(100)
@@ -37,7 +37,36 @@ func (c ConsoleFormatter) Format(results *domain.ScenarioComparison) ([]byte, er
 			FormatCurrency(sc.Year10NetIncome),
 			sc.TSPLongevity,
 		)
-		fmt.Fprintf(&buf, "  FirstRetiredNet=%s LifetimePV=%s\n", FormatCurrency(retiredNet), FormatCurrency(sc.TotalLifetimeIncome))
+		fmt.Fprintf(&buf, "  FirstRetiredNet=%s", FormatCurrency(retiredNet))
+		if sc.TransitionYear > 0 && sc.FirstFullRetirementYear > 0 {
+			// The first retired year still has salary; show the first full year as well
+			fmt.Fprintf(&buf, " (transition %d) FirstFullYearNet=%s (%d)", sc.TransitionYear, FormatCurrency(sc.FirstFullYearNetIncome), sc.FirstFullRetirementYear)
+		}
+		fmt.Fprintf(&buf, " LifetimePV=%s", FormatCurrency(sc.TotalLifetimeIncome))
+		if sc.LifetimeTaxes.Total.IsPositive() {
+			fmt.Fprintf(&buf, " LifetimeTaxes=%s", FormatCurrency(sc.LifetimeTaxes.Total))
+		}
+		fmt.Fprintln(&buf)
+		if floor := sc.DignityFloor; floor != nil {
+			fmt.Fprintf(&buf, "  FloorCovered=%d/%d years MinCoverage=%s", floor.YearsCovered, floor.YearsProjected, FormatPercentage(floor.MinCoverage.Mul(decimal.NewFromInt(100))))
+			if floor.CoveredFromYear > 0 {
+				fmt.Fprintf(&buf, " CoveredFrom=%d", floor.CoveredFromYear)
+			}
+			fmt.Fprintln(&buf)
+		}
+		if spending := sc.Spending; spending != nil {
+			fmt.Fprintf(&buf, "  SpendingFunded=%d/%d years", spending.YearsFunded, spending.YearsProjected)
+			if spending.FirstShortfallYear > 0 {
+				fmt.Fprintf(&buf, " Shortfall=%s FirstShortfall=%d", FormatCurrency(spending.TotalShortfall), spending.FirstShortfallYear)
+			}
+			fmt.Fprintln(&buf)
+		}
+		if len(sc.Warnings) > 0 {
+			fmt.Fprintf(&buf, "  Warnings=%d (first %d: %s)\n", len(sc.Warnings), sc.Warnings[0].Year, sc.Warnings[0].Message)
+		}
+		if len(sc.HorizonWarnings) > 0 {
+			fmt.Fprintf(&buf, "  BeyondHorizon=%d (set projection_years: %d)\n", len(sc.HorizonWarnings), sc.MinimumProjectionYears)
+		}
 	}
 	rec := AnalyzeScenarios(results)
 	if rec.ScenarioName != "" {
@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
@@ -72,23 +73,27 @@ func (c ConsoleVerboseFormatter) Format(results *domain.ScenarioComparison) ([]b
 		if found {
 			actualYear := 2025 + firstRetirementYearIndex
 			fmt.Fprintf(&buf, "FIRST RETIREMENT YEAR (%d) INCOME BREAKDOWN:\n", actualYear)
-			fmt.Fprintln(&buf, "(Note: Amounts shown are current-year cash received - may be partial year)")
+			if firstRetirementYear.IsTransitionYear() {
+				fmt.Fprintln(&buf, "(Note: Transition year - part-year salary and part-year retirement income)")
+			} else {
+				fmt.Fprintln(&buf, "(Note: Amounts shown are current-year cash received - may be partial year)")
+			}
 			fmt.Fprintln(&buf, "----------------------------------------")
 			fmt.Fprintln(&buf, "INCOME SOURCES:")
 			// Display income for each participant dynamically
-			for participantName, salary := range firstRetirementYear.Salaries {
+			for participantName, salary := range firstRetirementYear.Salaries.All() {
 				if !salary.IsZero() {
-					fmt.Fprintf(&buf, "  %s's Salary:        %s\n", participantName, FormatCurrency(salary))
+					fmt.Fprintf(&buf, "  %s's Salary:        %s\n", results.ParticipantLabels.Label(participantName), FormatCurrency(salary))
 				}
 			}
-			for participantName, pension := range firstRetirementYear.Pensions {
+			for participantName, pension := range firstRetirementYear.Pensions.All() {
 				if !pension.IsZero() {
-					fmt.Fprintf(&buf, "  %s's FERS Pension:  %s\n", participantName, FormatCurrency(pension))
+					fmt.Fprintf(&buf, "  %s's FERS Pension:  %s\n", results.ParticipantLabels.Label(participantName), FormatCurrency(pension))
 				}
 			}
-			for participantName, tspWithdrawal := range firstRetirementYear.TSPWithdrawals {
+			for participantName, tspWithdrawal := range firstRetirementYear.TSPWithdrawals.All() {
 				if !tspWithdrawal.IsZero() {
-					fmt.Fprintf(&buf, "  %s's TSP Withdrawal: %s\n", participantName, FormatCurrency(tspWithdrawal))
+					fmt.Fprintf(&buf, "  %s's TSP Withdrawal: %s\n", results.ParticipantLabels.Label(participantName), FormatCurrency(tspWithdrawal))
 				}
 			}
 
@@ -121,20 +126,23 @@ func (c ConsoleVerboseFormatter) Format(results *domain.ScenarioComparison) ([]b
 					fmt.Fprintf(&buf, "  Roth TSP:             %s\n", FormatCurrency(withdrawalBreakdownYear.WithdrawalRoth))
 				}
 			}
-			for participantName, ssBenefit := range firstRetirementYear.SSBenefits {
+			for participantName, ssBenefit := range firstRetirementYear.SSBenefits.All() {
 				if !ssBenefit.IsZero() {
-					fmt.Fprintf(&buf, "  %s's Social Security: %s\n", participantName, FormatCurrency(ssBenefit))
+					fmt.Fprintf(&buf, "  %s's Social Security: %s\n", results.ParticipantLabels.Label(participantName), FormatCurrency(ssBenefit))
 				}
 			}
-			for participantName, fersSupplement := range firstRetirementYear.FERSSupplements {
+			for participantName, fersSupplement := range firstRetirementYear.FERSSupplements.All() {
 				if !fersSupplement.IsZero() {
-					fmt.Fprintf(&buf, "  %s's FERS SRS:       %s\n", participantName, FormatCurrency(fersSupplement))
+					fmt.Fprintf(&buf, "  %s's FERS SRS:       %s\n", results.ParticipantLabels.Label(participantName), FormatCurrency(fersSupplement))
 				}
 			}
 			fmt.Fprintf(&buf, "  TOTAL GROSS INCOME:      %s\n", FormatCurrency(firstRetirementYear.TotalGrossIncome))
 			fmt.Fprintln(&buf)
 			fmt.Fprintln(&buf, "DEDUCTIONS & TAXES:")
 			fmt.Fprintf(&buf, "  Federal Tax:            %s\n", FormatCurrency(firstRetirementYear.FederalTax))
+			if firstRetirementYear.FederalDeductionMethod == "itemized" {
+				fmt.Fprintf(&buf, "    (itemized deduction:  %s)\n", FormatCurrency(firstRetirementYear.FederalItemizedDeduction))
+			}
 			fmt.Fprintf(&buf, "  State Tax:              %s\n", FormatCurrency(firstRetirementYear.StateTax))
 			fmt.Fprintf(&buf, "  Local Tax:              %s\n", FormatCurrency(firstRetirementYear.LocalTax))
 			fmt.Fprintf(&buf, "  FICA Tax:               %s\n", FormatCurrency(firstRetirementYear.FICATax))
@@ -157,6 +165,9 @@ func (c ConsoleVerboseFormatter) Format(results *domain.ScenarioComparison) ([]b
 				if firstRetirementYear.HealthcareCosts.MedicarePartD.GreaterThan(decimal.Zero) {
 					fmt.Fprintf(&buf, "    Medicare Part D:      %s\n", FormatCurrency(firstRetirementYear.HealthcareCosts.MedicarePartD))
 				}
+				if firstRetirementYear.HealthcareCosts.DrugCosts.GreaterThan(decimal.Zero) {
+					fmt.Fprintf(&buf, "    Drug Costs:           %s\n", FormatCurrency(firstRetirementYear.HealthcareCosts.DrugCosts))
+				}
 				if firstRetirementYear.HealthcareCosts.Medigap.GreaterThan(decimal.Zero) {
 					fmt.Fprintf(&buf, "    Medigap:              %s\n", FormatCurrency(firstRetirementYear.HealthcareCosts.Medigap))
 				}
@@ -182,13 +193,31 @@ func (c ConsoleVerboseFormatter) Format(results *domain.ScenarioComparison) ([]b
 			} else {
 				fmt.Fprintf(&buf, "  Monthly Change: %s\n", FormatCurrency(monthlyChange))
 			}
+			if firstRetirementYear.IsTransitionYear() && scenario.FirstFullRetirementYear > 0 {
+				fullChange := scenario.FirstFullYearNetIncome.Sub(results.BaselineNetIncome)
+				sign := ""
+				if fullChange.GreaterThan(decimal.Zero) {
+					sign = "+"
+				}
+				fmt.Fprintf(&buf, "  First Full Year (%d):   %s\n", scenario.FirstFullRetirementYear, FormatCurrency(scenario.FirstFullYearNetIncome))
+				fmt.Fprintf(&buf, "  FULL-YEAR CHANGE: %s%s (%s%s)\n", sign, FormatCurrency(fullChange), sign,
+					FormatPercentage(fullChange.Div(results.BaselineNetIncome).Mul(decimal.NewFromInt(100))))
+			}
+			if firstRetirementYear.SpendingTarget.IsPositive() {
+				fmt.Fprintf(&buf, "  Spending Target:        %s\n", FormatCurrency(firstRetirementYear.SpendingTarget))
+				if firstRetirementYear.SpendingSurplus.IsNegative() {
+					fmt.Fprintf(&buf, "  SHORTFALL:              %s\n", FormatCurrency(firstRetirementYear.SpendingSurplus.Neg()))
+				} else {
+					fmt.Fprintf(&buf, "  Surplus:                %s\n", FormatCurrency(firstRetirementYear.SpendingSurplus))
+				}
+			}
 			fmt.Fprintln(&buf, "RETIREMENT STATUS:")
 			fmt.Fprintf(&buf, "  Is Retired:             %t\n", firstRetirementYear.IsRetired)
 			fmt.Fprintf(&buf, "  Medicare Eligible:      %t\n", firstRetirementYear.IsMedicareEligible)
 			fmt.Fprintf(&buf, "  RMD Year:               %t\n", firstRetirementYear.IsRMDYear)
 			// Display ages for each participant dynamically
-			for participantName, age := range firstRetirementYear.Ages {
-				fmt.Fprintf(&buf, "  %s's Age:           %d\n", participantName, age)
+			for participantName, age := range firstRetirementYear.Ages.All() {
+				fmt.Fprintf(&buf, "  %s's Age:           %d\n", results.ParticipantLabels.Label(participantName), age)
 			}
 			fmt.Fprintln(&buf)
 		}
@@ -202,11 +231,35 @@ func (c ConsoleVerboseFormatter) Format(results *domain.ScenarioComparison) ([]b
 		fmt.Fprintf(&buf, "  Total Lifetime Income:   %s\n", FormatCurrency(scenario.TotalLifetimeIncome))
 		fmt.Fprintln(&buf)
 
+		if scenario.LifetimeTaxes.Total.IsPositive() {
+			writeLifetimeTaxes(&buf, scenario.LifetimeTaxes)
+		}
+
 		// IRMAA Risk Analysis
 		if scenario.IRMAAAnalysis != nil {
 			writeIRMAAAnalysis(&buf, scenario.IRMAAAnalysis)
 		}
 
+		if scenario.DignityFloor != nil {
+			writeDignityFloor(&buf, scenario.DignityFloor)
+		}
+
+		if scenario.Spending != nil {
+			writeSpending(&buf, scenario.Spending)
+		}
+
+		if len(scenario.SurvivorElections) > 0 {
+			writeSurvivorElections(&buf, scenario.SurvivorElections)
+		}
+
+		if len(scenario.Warnings) > 0 {
+			writeEngineWarnings(&buf, scenario.Warnings)
+		}
+
+		if len(scenario.HorizonWarnings) > 0 {
+			writeHorizonWarnings(&buf, scenario.HorizonWarnings, scenario.MinimumProjectionYears)
+		}
+
 		fmt.Fprintln(&buf)
 	}
 
@@ -229,11 +282,17 @@ func writeDetailedComparison(buf *bytes.Buffer, results *domain.ScenarioComparis
 	fmt.Fprintln(buf, "DETAILED INCOME VALIDATION: WORKING vs RETIREMENT")
 	fmt.Fprintln(buf, "=================================================================================")
 	for i, scenario := range results.Scenarios {
+		// Compare against the first full year of retirement; a transition year still
+		// carries salary and would understate the change
 		var firstRetirementYear *domain.AnnualCashFlow
-		for _, y := range scenario.Projection {
-			if y.IsRetired {
-				firstRetirementYear = &y
-				break
+		if idx := domain.FirstFullRetirementYear(scenario.Projection); idx >= 0 {
+			firstRetirementYear = &scenario.Projection[idx]
+		} else {
+			for _, y := range scenario.Projection {
+				if y.IsRetired {
+					firstRetirementYear = &y
+					break
+				}
 			}
 		}
 		if firstRetirementYear == nil {
@@ -265,10 +324,10 @@ func writeDetailedComparison(buf *bytes.Buffer, results *domain.ScenarioComparis
 
 		// Show part-time work information if applicable
 		partTimeSalary := decimal.Zero
-		for participantName, salary := range firstRetirementYear.PartTimeSalary {
+		for participantName, salary := range firstRetirementYear.PartTimeSalary.All() {
 			if salary.GreaterThan(decimal.Zero) {
 				partTimeSalary = partTimeSalary.Add(salary)
-				fmt.Fprintf(buf, "    %s (Part-Time): %s\n", participantName, FormatCurrency(salary))
+				fmt.Fprintf(buf, "    %s (Part-Time): %s\n", results.ParticipantLabels.Label(participantName), FormatCurrency(salary))
 			}
 		}
 
@@ -276,6 +335,18 @@ func writeDetailedComparison(buf *bytes.Buffer, results *domain.ScenarioComparis
 		cmpLine(buf, "  TSP Withdrawals", decimal.Zero, firstRetirementYear.GetTotalTSPWithdrawal())
 		cmpLine(buf, "  Social Security", decimal.Zero, firstRetirementYear.GetTotalSSBenefit())
 		cmpLine(buf, "  FERS Supplement", decimal.Zero, firstRetirementYear.GetTotalFERSSupplement())
+		if annuity := firstRetirementYear.GetTotalTSPAnnuity(); annuity.IsPositive() {
+			cmpLine(buf, "  TSP Annuity", decimal.Zero, annuity)
+		}
+		if annuity := firstRetirementYear.GetTotalIncomeAnnuity(); annuity.IsPositive() {
+			cmpLine(buf, "  Income Annuities", decimal.Zero, annuity)
+		}
+		if other := firstRetirementYear.GetTotalOtherIncome(); other.IsPositive() {
+			cmpLine(buf, "  Other Income", decimal.Zero, other)
+		}
+		if ira := firstRetirementYear.GetTotalIRAWithdrawal(); ira.IsPositive() {
+			cmpLine(buf, "  IRA Withdrawals", decimal.Zero, ira)
+		}
 		fmt.Fprintln(buf, strings.Repeat("-", 80))
 		cmpLine(buf, "TOTAL GROSS INCOME", workingGross, firstRetirementYear.TotalGrossIncome)
 		fmt.Fprintln(buf)
@@ -327,6 +398,98 @@ func cmpLine(buf *bytes.Buffer, label string, working, retirement decimal.Decima
 	fmt.Fprintf(buf, "%-35s %15s %15s %15s\n", label, FormatCurrency(working), FormatCurrency(retirement), FormatCurrency(diff))
 }
 
+// writeEngineWarnings lists projection warnings such as withdrawal shortfalls
+func writeEngineWarnings(buf *bytes.Buffer, warnings []domain.EngineWarning) {
+	fmt.Fprintln(buf, "ENGINE WARNINGS:")
+	fmt.Fprintln(buf, "----------------")
+	for _, w := range warnings {
+		fmt.Fprintf(buf, "  %d  %s\n", w.Year, w.Message)
+	}
+	fmt.Fprintln(buf)
+}
+
+// writeHorizonWarnings lists key events the projection ends too early to include
+func writeHorizonWarnings(buf *bytes.Buffer, warnings []domain.EngineWarning, minimumYears int) {
+	fmt.Fprintln(buf, "BEYOND THE PROJECTION HORIZON:")
+	fmt.Fprintln(buf, "------------------------------")
+	for _, w := range warnings {
+		fmt.Fprintf(buf, "  %d  %s\n", w.Year, w.Message)
+	}
+	fmt.Fprintf(buf, "  Set projection_years to at least %d to include every event\n", minimumYears)
+	fmt.Fprintln(buf)
+}
+
+// writeDignityFloor summarizes guaranteed income coverage of essential expenses
+func writeDignityFloor(buf *bytes.Buffer, floor *domain.DignityFloorSummary) {
+	fmt.Fprintln(buf, "DIGNITY FLOOR (Guaranteed Income vs Essential Expenses):")
+	fmt.Fprintln(buf, "--------------------------------------------------------")
+	fmt.Fprintf(buf, "  Years Covered:           %d of %d\n", floor.YearsCovered, floor.YearsProjected)
+	fmt.Fprintf(buf, "  Lowest Coverage:         %s (%d)\n", FormatPercentage(floor.MinCoverage.Mul(decimal.NewFromInt(100))), floor.MinCoverageYear)
+	if floor.CoveredFromYear > 0 {
+		fmt.Fprintf(buf, "  Covered From:            %d onward (%s)\n", floor.CoveredFromYear, formatAges(floor.CoveredFromAges))
+	} else {
+		fmt.Fprintln(buf, "  Covered From:            not covered by the end of the projection")
+	}
+	fmt.Fprintln(buf)
+}
+
+// writeSpending summarizes how well net income funds the spending target
+func writeSpending(buf *bytes.Buffer, spending *domain.SpendingSummary) {
+	fmt.Fprintln(buf, "SPENDING PLAN (Net Income vs Spending Target):")
+	fmt.Fprintln(buf, "----------------------------------------------")
+	fmt.Fprintf(buf, "  Years Funded:            %d of %d\n", spending.YearsFunded, spending.YearsProjected)
+	fmt.Fprintf(buf, "  Total Spending Target:   %s\n", FormatCurrency(spending.TotalTarget))
+	if spending.FirstShortfallYear > 0 {
+		fmt.Fprintf(buf, "  Total Shortfall:         %s\n", FormatCurrency(spending.TotalShortfall))
+		fmt.Fprintf(buf, "  First Shortfall:         %d\n", spending.FirstShortfallYear)
+		fmt.Fprintf(buf, "  Largest Shortfall:       %s (%d)\n", FormatCurrency(spending.LargestShortfall), spending.LargestShortfallYear)
+	} else {
+		fmt.Fprintln(buf, "  Shortfall:               none")
+	}
+	fmt.Fprintln(buf)
+}
+
+// writeLifetimeTaxes breaks down the taxes paid over the projection by type
+func writeLifetimeTaxes(buf *bytes.Buffer, taxes domain.TaxTotals) {
+	fmt.Fprintln(buf, "LIFETIME TAXES:")
+	fmt.Fprintln(buf, "---------------")
+	fmt.Fprintf(buf, "  Federal:                 %s\n", FormatCurrency(taxes.Federal))
+	fmt.Fprintf(buf, "  State:                   %s\n", FormatCurrency(taxes.State))
+	fmt.Fprintf(buf, "  Local:                   %s\n", FormatCurrency(taxes.Local))
+	fmt.Fprintf(buf, "  FICA:                    %s\n", FormatCurrency(taxes.FICA))
+	fmt.Fprintf(buf, "  IRMAA Surcharges:        %s\n", FormatCurrency(taxes.IRMAA))
+	fmt.Fprintf(buf, "  Total:                   %s\n", FormatCurrency(taxes.Total))
+	fmt.Fprintln(buf)
+}
+
+// writeSurvivorElections shows what each survivor election costs and provides
+func writeSurvivorElections(buf *bytes.Buffer, elections []domain.SurvivorElectionSummary) {
+	fmt.Fprintln(buf, "SURVIVOR ELECTIONS:")
+	fmt.Fprintln(buf, "-------------------")
+	for _, e := range elections {
+		fmt.Fprintf(buf, "  %s: %s\n", e.Participant, e.Election)
+		fmt.Fprintf(buf, "    Unreduced Annuity:     %s\n", FormatCurrency(e.UnreducedAnnuity))
+		fmt.Fprintf(buf, "    Reduction:             %s\n", FormatPercentage(e.ReductionFactor.Mul(decimal.NewFromInt(100))))
+		fmt.Fprintf(buf, "    Reduced Annuity:       %s\n", FormatCurrency(e.ReducedAnnuity))
+		fmt.Fprintf(buf, "    Survivor Annuity:      %s\n", FormatCurrency(e.SurvivorAnnuity))
+	}
+	fmt.Fprintln(buf)
+}
+
+// formatAges renders participant ages in name order, e.g. "Alex 70, Blake 68"
+func formatAges(ages map[string]int) string {
+	names := make([]string, 0, len(ages))
+	for name := range ages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, ages[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
 // writeIRMAAAnalysis formats IRMAA risk analysis for console output
 func writeIRMAAAnalysis(buf *bytes.Buffer, analysis *domain.IRMAAAnalysis) {
 	fmt.Fprintln(buf, "IRMAA RISK ANALYSIS (Medicare Premium Surcharges):")
@@ -352,8 +515,8 @@ func writeIRMAAAnalysis(buf *bytes.Buffer, analysis *domain.IRMAAAnalysis) {
 	if len(analysis.HighRiskYears) > 0 {
 		fmt.Fprintln(buf)
 		fmt.Fprintln(buf, "High Risk Years:")
-		fmt.Fprintf(buf, "%-6s  %-12s  %-10s  %-8s  %s\n", "Year", "MAGI", "Status", "Tier", "Annual Cost")
-		fmt.Fprintln(buf, strings.Repeat("-", 65))
+		fmt.Fprintf(buf, "%-6s  %-12s  %-10s  %-8s  %-6s  %s\n", "Year", "MAGI", "Status", "Tier", "Paid", "Annual Cost")
+		fmt.Fprintln(buf, strings.Repeat("-", 73))
 
 		for _, yr := range analysis.HighRiskYears {
 			statusIcon := "✓"
@@ -363,12 +526,13 @@ func writeIRMAAAnalysis(buf *bytes.Buffer, analysis *domain.IRMAAAnalysis) {
 				statusIcon = "⚠"
 			}
 
-			fmt.Fprintf(buf, "%-6d  %-12s  %s %-8s  %-8s  %s\n",
+			fmt.Fprintf(buf, "%-6d  %-12s  %s %-8s  %-8s  %-6d  %s\n",
 				yr.Year,
 				FormatCurrency(yr.MAGI),
 				statusIcon,
 				string(yr.RiskStatus),
 				yr.TierLevel,
+				yr.SurchargeYear,
 				FormatCurrency(yr.AnnualCost),
 			)
 		}
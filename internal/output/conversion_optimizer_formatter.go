@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// FormatConversionOptimization reports the recommended conversion schedule, how it
+// compares with converting nothing, and the roth_conversions block to paste into the
+// participant's scenario
+func FormatConversionOptimization(opt *domain.ConversionOptimization) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "ROTH CONVERSION OPTIMIZATION: %s, %s\n", opt.Scenario, opt.Participant)
+	fmt.Fprintln(&buf, strings.Repeat("=", 80))
+	goal := "maximize ending after-tax wealth"
+	if opt.Goal == domain.ConversionGoalLifetimeTax {
+		goal = "minimize lifetime tax"
+	}
+	fmt.Fprintf(&buf, "Goal: %s. Conversions %d-%d fill at most the %d%% bracket and stay below the next IRMAA tier.\n",
+		goal, opt.FirstYear, opt.Horizon, opt.TargetBracket)
+	fmt.Fprintf(&buf, "Searched %d projections.\n\n", opt.Projections)
+
+	if len(opt.Schedule.Conversions) == 0 {
+		fmt.Fprintln(&buf, "No conversion schedule improves on converting nothing.")
+	} else {
+		fmt.Fprintln(&buf, "RECOMMENDED CONVERSIONS")
+		fmt.Fprintln(&buf, strings.Repeat("-", 30))
+		for _, c := range opt.Schedule.Conversions {
+			fmt.Fprintf(&buf, "  %d  %14s\n", c.Year, FormatCurrency(c.Amount))
+		}
+	}
+	fmt.Fprintln(&buf)
+
+	fmt.Fprintf(&buf, "%-28s %16s %16s %16s\n", "", "No Conversions", "Recommended", "Difference")
+	rows := []struct {
+		label            string
+		baseline, result decimal.Decimal
+	}{
+		{"After-tax wealth", opt.Baseline.AfterTaxWealth, opt.Optimized.AfterTaxWealth},
+		{"Lifetime tax", opt.Baseline.LifetimeTax, opt.Optimized.LifetimeTax},
+		{"  of which IRMAA", opt.Baseline.LifetimeIRMAA, opt.Optimized.LifetimeIRMAA},
+		{"Premium tax credits", opt.Baseline.LifetimePremiumTaxCredit, opt.Optimized.LifetimePremiumTaxCredit},
+		{"Total conversions", opt.Baseline.TotalConversions, opt.Optimized.TotalConversions},
+		{"Ending traditional", opt.Baseline.EndingTraditional, opt.Optimized.EndingTraditional},
+		{"Ending Roth", opt.Baseline.EndingRoth, opt.Optimized.EndingRoth},
+		{"Ending taxable", opt.Baseline.EndingTaxable, opt.Optimized.EndingTaxable},
+	}
+	for _, r := range rows {
+		fmt.Fprintf(&buf, "%-28s %16s %16s %16s\n", r.label, FormatCurrency(r.baseline), FormatCurrency(r.result), FormatCurrency(r.result.Sub(r.baseline)))
+	}
+
+	if len(opt.Schedule.Conversions) > 0 {
+		fmt.Fprintln(&buf)
+		fmt.Fprintf(&buf, "Add to the participant scenario for %s:\n\n", opt.Participant)
+		buf.WriteString(FormatRothConversionsYAML(opt.Schedule))
+	}
+	return buf.String()
+}
+
+// FormatRothConversionsYAML writes a conversion schedule as the roth_conversions block of
+// a participant scenario
+func FormatRothConversionsYAML(schedule domain.RothConversionSchedule) string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "roth_conversions:")
+	if len(schedule.Conversions) == 0 {
+		fmt.Fprintln(&buf, "  conversions: []")
+		return buf.String()
+	}
+	fmt.Fprintln(&buf, "  conversions:")
+	for _, c := range schedule.Conversions {
+		fmt.Fprintf(&buf, "    - year: %d\n", c.Year)
+		fmt.Fprintf(&buf, "      amount: %s\n", c.Amount.StringFixed(0))
+		fmt.Fprintf(&buf, "      source: %s\n", c.Source)
+	}
+	return buf.String()
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFormatRothConversionsYAML(t *testing.T) {
+	schedule := domain.RothConversionSchedule{Conversions: []domain.RothConversion{
+		{Year: 2027, Amount: decimal.NewFromInt(45000), Source: "traditional_tsp"},
+		{Year: 2028, Amount: decimal.NewFromInt(38000), Source: "traditional_tsp"},
+	}}
+	out := FormatRothConversionsYAML(schedule)
+	assert.Contains(t, out, "    - year: 2027\n      amount: 45000\n      source: traditional_tsp\n")
+
+	// The block parses back into a participant scenario's schedule
+	var ps domain.ParticipantScenario
+	require.NoError(t, yaml.Unmarshal([]byte(out), &ps))
+	require.NotNil(t, ps.RothConversions)
+	assert.Len(t, ps.RothConversions.Conversions, 2)
+	assert.True(t, ps.RothConversions.Conversions[1].Amount.Equal(decimal.NewFromInt(38000)))
+
+	assert.Equal(t, "roth_conversions:\n  conversions: []\n", FormatRothConversionsYAML(domain.RothConversionSchedule{}))
+}
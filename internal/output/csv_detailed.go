@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
 )
 
 // CSVDetailedExporter provides raw annual projection detail per scenario/year.
@@ -17,7 +18,7 @@ func (c CSVDetailedExporter) Name() string { return "detailed-csv" }
 func (c CSVDetailedExporter) Format(results *domain.ScenarioComparison) ([]byte, error) {
 	buf := &bytes.Buffer{}
 	w := csv.NewWriter(buf)
-	header := []string{"Scenario", "Year", "ActualYear", "NetIncome", "TotalGrossIncome", "TSPBalance", "IsRetired"}
+	header := []string{"Scenario", "Year", "ActualYear", "NetIncome", "TotalGrossIncome", "TSPBalance", "IsRetired", "WithdrawalShortfall", "GuaranteedIncome", "EssentialExpenses", "FloorCoverage", "BucketCash", "BucketBonds", "BucketEquities", "AGI", "TaxExemptInterest", "MAGI"}
 	if err := w.Write(header); err != nil {
 		return nil, err
 	}
@@ -25,14 +26,25 @@ func (c CSVDetailedExporter) Format(results *domain.ScenarioComparison) ([]byte,
 	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
 	for _, sc := range scenarios {
 		for _, yr := range sc.Projection {
+			buckets := householdBuckets(yr.Buckets)
 			row := []string{
 				sc.Name,
 				intToString(yr.Year),
 				intToString(yr.Date.Year()),
-				yr.NetIncome.StringFixed(2),
-				yr.TotalGrossIncome.StringFixed(2),
-				yr.TotalTSPBalance().StringFixed(2),
+				domain.FormatAmount(yr.NetIncome),
+				domain.FormatAmount(yr.TotalGrossIncome),
+				domain.FormatAmount(yr.TotalTSPBalance()),
 				boolToString(yr.IsRetired),
+				domain.FormatAmount(withdrawalShortfall(yr.Warnings)),
+				domain.FormatAmount(yr.GuaranteedIncome),
+				domain.FormatAmount(yr.EssentialExpenses),
+				yr.FloorCoverage.StringFixed(4),
+				domain.FormatAmount(buckets.Cash),
+				domain.FormatAmount(buckets.Bonds),
+				domain.FormatAmount(buckets.Equities),
+				domain.FormatAmount(yr.MAGIBreakdown.AGI),
+				domain.FormatAmount(yr.MAGIBreakdown.TaxExemptInterest),
+				domain.FormatAmount(yr.MAGI),
 			}
 			if err := w.Write(row); err != nil {
 				return nil, err
@@ -42,3 +54,25 @@ func (c CSVDetailedExporter) Format(results *domain.ScenarioComparison) ([]byte,
 	w.Flush()
 	return buf.Bytes(), nil
 }
+
+// withdrawalShortfall totals the withdrawal shortfall warnings for a year
+func withdrawalShortfall(warnings []domain.EngineWarning) decimal.Decimal {
+	total := decimal.Zero
+	for _, w := range warnings {
+		if w.Code == domain.WarningWithdrawalShortfall {
+			total = total.Add(w.Amount)
+		}
+	}
+	return total
+}
+
+// householdBuckets totals the participants' bucket balances for a year
+func householdBuckets(buckets map[string]domain.BucketBalances) domain.BucketBalances {
+	var total domain.BucketBalances
+	for _, b := range buckets {
+		total.Cash = total.Cash.Add(b.Cash)
+		total.Bonds = total.Bonds.Add(b.Bonds)
+		total.Equities = total.Equities.Add(b.Equities)
+	}
+	return total
+}
@@ -16,7 +16,7 @@ func (c CSVSummarizer) Name() string { return "csv" }
 func (c CSVSummarizer) Format(results *domain.ScenarioComparison) ([]byte, error) {
 	buf := &bytes.Buffer{}
 	w := csv.NewWriter(buf)
-	header := []string{"Scenario", "FirstYearNetIncome", "Year5NetIncome", "Year10NetIncome", "TSPLongevity", "TotalLifetimeIncomePV", "InitialTSPBalance", "FinalTSPBalance", "NetIncome2030", "NetIncome2035", "NetIncome2040", "PreRetirementNet2030", "PreRetirementNet2035", "PreRetirementNet2040"}
+	header := []string{"Scenario", "FirstYearNetIncome", "Year5NetIncome", "Year10NetIncome", "TSPLongevity", "TotalLifetimeIncomePV", "InitialTSPBalance", "FinalTSPBalance", "NetIncome2030", "NetIncome2035", "NetIncome2040", "PreRetirementNet2030", "PreRetirementNet2035", "PreRetirementNet2040", "FirstFullRetirementYear", "FirstFullYearNetIncome", "LifetimeTaxes", "Warnings"}
 	if err := w.Write(header); err != nil {
 		return nil, err
 	}
@@ -25,19 +25,23 @@ func (c CSVSummarizer) Format(results *domain.ScenarioComparison) ([]byte, error
 	for _, sc := range scenarios {
 		row := []string{
 			sc.Name,
-			sc.FirstYearNetIncome.StringFixed(2),
-			sc.Year5NetIncome.StringFixed(2),
-			sc.Year10NetIncome.StringFixed(2),
+			domain.FormatAmount(sc.FirstYearNetIncome),
+			domain.FormatAmount(sc.Year5NetIncome),
+			domain.FormatAmount(sc.Year10NetIncome),
 			intToString(sc.TSPLongevity),
-			sc.TotalLifetimeIncome.StringFixed(2),
-			sc.InitialTSPBalance.StringFixed(2),
-			sc.FinalTSPBalance.StringFixed(2),
-			sc.NetIncome2030.StringFixed(2),
-			sc.NetIncome2035.StringFixed(2),
-			sc.NetIncome2040.StringFixed(2),
-			sc.PreRetirementNet2030.StringFixed(2),
-			sc.PreRetirementNet2035.StringFixed(2),
-			sc.PreRetirementNet2040.StringFixed(2),
+			domain.FormatAmount(sc.TotalLifetimeIncome),
+			domain.FormatAmount(sc.InitialTSPBalance),
+			domain.FormatAmount(sc.FinalTSPBalance),
+			domain.FormatAmount(sc.NetIncome2030),
+			domain.FormatAmount(sc.NetIncome2035),
+			domain.FormatAmount(sc.NetIncome2040),
+			domain.FormatAmount(sc.PreRetirementNet2030),
+			domain.FormatAmount(sc.PreRetirementNet2035),
+			domain.FormatAmount(sc.PreRetirementNet2040),
+			intToString(sc.FirstFullRetirementYear),
+			domain.FormatAmount(sc.FirstFullYearNetIncome),
+			domain.FormatAmount(sc.LifetimeTaxes.Total),
+			intToString(len(sc.Warnings)),
 		}
 		if err := w.Write(row); err != nil {
 			return nil, err
@@ -1,10 +1,13 @@
 package output
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
 
-// FormatCurrency formats a decimal as USD currency with 2 decimals.
+// FormatCurrency formats a decimal as USD currency in cents under domain.CurrencyRounding.
 // Kept here so it can be reused by multiple formatters and unit tested in isolation.
-func FormatCurrency(amount decimal.Decimal) string { return "$" + amount.StringFixed(2) }
+func FormatCurrency(amount decimal.Decimal) string { return domain.FormatCurrency(amount) }
 
-// FormatPercentage formats a decimal as a percentage with 2 decimals.
-func FormatPercentage(amount decimal.Decimal) string { return amount.StringFixed(2) + "%" }
+// FormatPercentage formats an amount already in percent at domain.PercentPrecision decimals.
+func FormatPercentage(amount decimal.Decimal) string { return domain.FormatPercent(amount) }
@@ -1,8 +1,10 @@
 package output
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 )
 
@@ -15,6 +17,24 @@ func TestFormatCurrency(t *testing.T) {
 	}
 }
 
+func TestCurrencyRoundingAppliesToCSV(t *testing.T) {
+	defer func(mode string) { domain.CurrencyRounding = mode }(domain.CurrencyRounding)
+	domain.CurrencyRounding = domain.CurrencyRoundingBankers
+	results := &domain.ScenarioComparison{Scenarios: []domain.ScenarioSummary{
+		{Name: "Early", FirstYearNetIncome: decimal.NewFromFloat(90000.125)},
+	}}
+	data, err := CSVSummarizer{}.Format(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row := strings.Split(string(data), "\n")[1]; !strings.HasPrefix(row, "Early,90000.12,") {
+		t.Errorf("summary row %q does not use bankers rounding", row)
+	}
+	if got := FormatCurrency(decimal.NewFromFloat(90000.125)); got != "$90000.12" {
+		t.Errorf("FormatCurrency = %q, want $90000.12", got)
+	}
+}
+
 func TestFormatPercentage(t *testing.T) {
 	v := decimal.NewFromFloat(12.3456)
 	got := FormatPercentage(v)
@@ -1,7 +1,9 @@
 package output
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -33,8 +35,18 @@ type FormatterFunc struct {
 func (ff FormatterFunc) Format(r *domain.ScenarioComparison) ([]byte, error) { return ff.F(r) }
 func (ff FormatterFunc) Name() string                                        { return ff.ID }
 
+// StreamingFormatter is implemented by formatters that can render directly to a
+// writer, avoiding an in-memory copy of large reports.
+type StreamingFormatter interface {
+	Formatter
+	Stream(w io.Writer, results *domain.ScenarioComparison) (int64, error)
+}
+
 // WriteFormatted runs a formatter and writes output to timestamped file with extension.
 func WriteFormatted(f Formatter, results *domain.ScenarioComparison, ext string) (string, error) {
+	if sf, ok := f.(StreamingFormatter); ok {
+		return writeStreamed(sf, results, ext)
+	}
 	data, err := f.Format(results)
 	if err != nil {
 		return "", err
@@ -46,6 +58,31 @@ func WriteFormatted(f Formatter, results *domain.ScenarioComparison, ext string)
 	return filename, nil
 }
 
+func writeStreamed(f StreamingFormatter, results *domain.ScenarioComparison, ext string) (string, error) {
+	filename := fmt.Sprintf("retirement_report_%s.%s", time.Now().Format("20060102_150405"), ext)
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(file)
+	size, err := f.Stream(w, results)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	if hf, ok := f.(HTMLFormatter); ok {
+		if warning := hf.SizeWarning(size); warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s (%s)\n", warning, filename)
+		}
+	}
+	return filename, nil
+}
+
 // builtInFormatters stores available formatters (extended incrementally).
 var builtInFormatters = []Formatter{
 	ConsoleVerboseFormatter{},
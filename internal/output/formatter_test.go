@@ -190,6 +190,93 @@ func TestHTMLShowsLifetimeAndSuccessRate(t *testing.T) {
 	}
 }
 
+func buildLongWithdrawalComparison(years int) *domain.ScenarioComparison {
+	projection := make([]domain.AnnualCashFlow, years)
+	for i := range projection {
+		projection[i] = domain.AnnualCashFlow{
+			Year:                  i + 1,
+			Date:                  time.Date(2025+i, 1, 1, 0, 0, 0, 0, time.UTC),
+			NetIncome:             decimal.NewFromInt(90000),
+			WithdrawalTraditional: decimal.NewFromInt(20000),
+			IsRetired:             true,
+		}
+	}
+	return &domain.ScenarioComparison{
+		Scenarios: []domain.ScenarioSummary{{Name: "Long", Projection: projection}},
+	}
+}
+
+func TestHTMLCollapsesLongYearTables(t *testing.T) {
+	out, err := HTMLFormatter{CollapseAfterYears: 10}.Format(buildLongWithdrawalComparison(25))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := string(out)
+	if got := strings.Count(content, `<details class="year-page">`); got != 2 {
+		t.Fatalf("expected 2 collapsible year sections, got %d", got)
+	}
+	if !strings.Contains(content, "Years 2035&ndash;2044") || !strings.Contains(content, "Years 2045&ndash;2049") {
+		t.Fatalf("expected collapsible section year ranges in HTML")
+	}
+
+	out, err = HTMLFormatter{CollapseAfterYears: -1}.Format(buildLongWithdrawalComparison(25))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(out), `<details class="year-page">`) {
+		t.Fatalf("expected no collapsible sections when collapsing is disabled")
+	}
+}
+
+func TestHTMLStreamMatchesFormatAndWarnsOnSize(t *testing.T) {
+	results := buildLongWithdrawalComparison(5)
+	f := HTMLFormatter{SizeWarningBytes: 1024}
+	var sb strings.Builder
+	size, err := f.Stream(&sb, results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	formatted, err := f.Format(results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sb.String() != string(formatted) || size != int64(len(formatted)) {
+		t.Fatalf("expected streamed output (%d bytes) to match Format output (%d bytes)", size, len(formatted))
+	}
+	if f.SizeWarning(size) == "" {
+		t.Fatalf("expected size warning for %d byte report with 1KB limit", size)
+	}
+	if (HTMLFormatter{}).SizeWarning(size) != "" {
+		t.Fatalf("expected no warning under the default limit")
+	}
+	if (HTMLFormatter{SizeWarningBytes: -1}).SizeWarning(1<<30) != "" {
+		t.Fatalf("expected no warning when the guardrail is disabled")
+	}
+}
+
+func TestHTMLScenarioTabsDeepLink(t *testing.T) {
+	cmp := buildTestComparison()
+	cmp.Scenarios[0].Name = "Retire at 62 (Feb 2027)"
+	cmp.Scenarios[1].Name = "Retire at 62 - Feb 2027"
+	cmp.Scenarios = append(cmp.Scenarios, domain.ScenarioSummary{Name: "Working Longer"}, domain.ScenarioSummary{Name: "Scénario ?"})
+	out, err := HTMLFormatter{}.Format(cmp)
+	if err != nil {
+		t.Fatalf("html format error: %v", err)
+	}
+	content := string(out)
+	for _, id := range []string{"scenario-retire-at-62-feb-2027", "scenario-retire-at-62-feb-2027-2", "scenario-working-longer", "scenario-sc-nario"} {
+		if !strings.Contains(content, `href="#`+id+`"`) || !strings.Contains(content, `id="`+id+`"`) {
+			t.Fatalf("expected a tab and panel linked by anchor %q", id)
+		}
+		if !strings.Contains(content, `"id":"`+id+`"`) {
+			t.Fatalf("expected chart data for anchor %q", id)
+		}
+	}
+	if strings.Contains(content, "Scenario 1") || strings.Contains(content, "incomeSourcesChart") {
+		t.Fatalf("expected scenario headers and charts to come from the scenario data")
+	}
+}
+
 func firstLine(s string) string {
 	if i := strings.IndexByte(s, '\n'); i >= 0 {
 		return s[:i]
@@ -224,3 +311,22 @@ func TestUnknownFormatErrorIncludesSuggestions(t *testing.T) {
 		t.Fatalf("error message missing suggestions: %s", msg)
 	}
 }
+
+func TestConsoleVerboseFormatterUsesDisplayNames(t *testing.T) {
+	comparison := buildTestComparison()
+	comparison.ParticipantLabels = domain.ParticipantLabels{"person_a": "Robert"}
+	pensions := domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"person_a": decimal.NewFromInt(50000)})
+	comparison.Scenarios[0].Projection[0].Pensions = pensions
+
+	out, err := ConsoleVerboseFormatter{}.Format(comparison)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := string(out)
+	if !strings.Contains(content, "Robert's FERS Pension") {
+		t.Fatalf("expected display name in output, got: %s", content)
+	}
+	if strings.Contains(content, "person_a") {
+		t.Fatalf("participant key leaked into output: %s", content)
+	}
+}
@@ -3,14 +3,28 @@ package output
 import (
 	"bytes"
 	_ "embed"
+	"fmt"
 	"html/template"
+	"io"
 
 	"github.com/rgehrsitz/rpgo/internal/domain"
 	"github.com/shopspring/decimal"
 )
 
-// HTMLFormatter produces an HTML report (current implementation ports legacy static HTML).
-type HTMLFormatter struct{}
+// Defaults for HTML report guardrails.
+const (
+	DefaultHTMLCollapseAfterYears = 30
+	DefaultHTMLSizeWarningBytes   = 5 << 20
+)
+
+// HTMLFormatter produces an HTML report with a deep-linkable tab per scenario.
+// Year-by-year tables longer than CollapseAfterYears are split into collapsible
+// sections; reports larger than SizeWarningBytes trigger a warning. Zero values
+// use the defaults and negative values disable the respective guardrail.
+type HTMLFormatter struct {
+	CollapseAfterYears int
+	SizeWarningBytes   int64
+}
 
 func (h HTMLFormatter) Name() string { return "html" }
 
@@ -18,17 +32,12 @@ func (h HTMLFormatter) Name() string { return "html" }
 var htmlTemplateSource string
 
 var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
-	"curr":   FormatCurrency,
-	"pct":    FormatPercentage,
-	"minus1": func(i int) int { return i - 1 },
-	"add":    func(a, b decimal.Decimal) decimal.Decimal { return a.Add(b) },
-	"addInt": func(a, b int) int { return a + b },
-	"slice": func(items []domain.ScenarioSummary, start int) []domain.ScenarioSummary {
-		if start >= len(items) {
-			return []domain.ScenarioSummary{}
-		}
-		return items[start:]
+	"curr": FormatCurrency,
+	"pct":  FormatPercentage,
+	"ratioPct": func(ratio decimal.Decimal) string {
+		return FormatPercentage(ratio.Mul(decimal.NewFromInt(100)))
 	},
+	"add": func(a, b decimal.Decimal) decimal.Decimal { return a.Add(b) },
 	"hasWithdrawalSequencing": func(scenario domain.ScenarioSummary) bool {
 		if scenario.Projection == nil {
 			return false
@@ -42,6 +51,7 @@ var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
 		}
 		return false
 	},
+	"paginateYears": paginateYears,
 	"getWithdrawalYears": func(scenario domain.ScenarioSummary) []domain.AnnualCashFlow {
 		var withdrawalYears []domain.AnnualCashFlow
 		if scenario.Projection == nil {
@@ -83,8 +93,83 @@ var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
 	},
 }).Parse(htmlTemplateSource))
 
-func (h HTMLFormatter) Format(results *domain.ScenarioComparison) ([]byte, error) {
-	var buf bytes.Buffer
+// yearPage is a contiguous run of projection years rendered as one table section.
+type yearPage struct {
+	Years     []domain.AnnualCashFlow
+	FirstYear int
+	LastYear  int
+}
+
+// paginateYears splits years into pages of at most size entries. A non-positive
+// size keeps everything on a single page.
+func paginateYears(years []domain.AnnualCashFlow, size int) []yearPage {
+	if len(years) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = len(years)
+	}
+	var pages []yearPage
+	for start := 0; start < len(years); start += size {
+		end := start + size
+		if end > len(years) {
+			end = len(years)
+		}
+		chunk := years[start:end]
+		pages = append(pages, yearPage{
+			Years:     chunk,
+			FirstYear: chunk[0].Date.Year(),
+			LastYear:  chunk[len(chunk)-1].Date.Year(),
+		})
+	}
+	return pages
+}
+
+func (h HTMLFormatter) collapseAfterYears() int {
+	if h.CollapseAfterYears == 0 {
+		return DefaultHTMLCollapseAfterYears
+	}
+	return h.CollapseAfterYears
+}
+
+// SizeLimit returns the effective size warning threshold in bytes (0 when disabled).
+func (h HTMLFormatter) SizeLimit() int64 {
+	switch {
+	case h.SizeWarningBytes < 0:
+		return 0
+	case h.SizeWarningBytes == 0:
+		return DefaultHTMLSizeWarningBytes
+	default:
+		return h.SizeWarningBytes
+	}
+}
+
+// SizeWarning returns a human-readable warning when size exceeds the configured
+// threshold, or an empty string otherwise.
+func (h HTMLFormatter) SizeWarning(size int64) string {
+	limit := h.SizeLimit()
+	if limit == 0 || size <= limit {
+		return ""
+	}
+	return fmt.Sprintf("HTML report is %.1f MB, exceeding the %.1f MB guardrail; consider fewer scenarios or a shorter projection",
+		float64(size)/(1<<20), float64(limit)/(1<<20))
+}
+
+// countingWriter tracks the number of bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Stream renders the report directly to w without buffering the whole document
+// and returns the number of bytes written.
+func (h HTMLFormatter) Stream(w io.Writer, results *domain.ScenarioComparison) (int64, error) {
 	rec := AnalyzeScenarios(results)
 
 	// Use assumptions from results if available, otherwise fall back to defaults
@@ -93,12 +178,25 @@ func (h HTMLFormatter) Format(results *domain.ScenarioComparison) ([]byte, error
 		assumptions = DefaultAssumptions
 	}
 
+	views, charts := buildHTMLScenarios(results.Scenarios)
 	data := struct {
 		*domain.ScenarioComparison
-		Recommendation Recommendation
-		Assumptions    []string
-	}{results, rec, assumptions}
-	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		Recommendation     Recommendation
+		Assumptions        []string
+		CollapseAfterYears int
+		ScenarioViews      []htmlScenario
+		Charts             []scenarioChartData
+	}{results, rec, assumptions, h.collapseAfterYears(), views, charts}
+	cw := &countingWriter{w: w}
+	if err := htmlTemplate.Execute(cw, data); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+func (h HTMLFormatter) Format(results *domain.ScenarioComparison) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := h.Stream(&buf, results); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
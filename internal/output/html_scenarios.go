@@ -0,0 +1,178 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// htmlScenario is one scenario's tab in the HTML report. ID is the unique anchor that
+// deep-links to the tab (report.html#scenario-early-retirement).
+type htmlScenario struct {
+	domain.ScenarioSummary
+	ID           string
+	Participants []string
+}
+
+// htmlYearPage is a page of a scenario's year-by-year table with a column per participant
+type htmlYearPage struct {
+	yearPage
+	Participants []string
+}
+
+// YearPages splits the scenario's projection into table pages of at most size years
+func (s htmlScenario) YearPages(size int) []htmlYearPage {
+	var pages []htmlYearPage
+	for _, page := range paginateYears(s.Projection, size) {
+		pages = append(pages, htmlYearPage{yearPage: page, Participants: s.Participants})
+	}
+	return pages
+}
+
+// chartSeries is a labeled series of yearly amounts
+type chartSeries struct {
+	Label  string    `json:"label"`
+	Values []float64 `json:"values"`
+}
+
+// scenarioChartData carries the series behind one scenario's charts; the report's script
+// builds every chart from it, so nothing in the script depends on the scenario count
+type scenarioChartData struct {
+	ID                string        `json:"id"`
+	Name              string        `json:"name"`
+	Years             []int         `json:"years"`
+	NetIncome         []float64     `json:"netIncome"`
+	TSPBalance        []float64     `json:"tspBalance"`
+	Sources           []chartSeries `json:"sources"`
+	ParticipantTSP    []chartSeries `json:"participantTsp"`
+	ParticipantIncome []chartSeries `json:"participantIncome"`
+}
+
+// incomeSources are the household income streams charted per scenario
+var incomeSources = []struct {
+	label string
+	total func(*domain.AnnualCashFlow) decimal.Decimal
+}{
+	{"Salary", (*domain.AnnualCashFlow).GetTotalSalary},
+	{"FERS Pension", (*domain.AnnualCashFlow).GetTotalPension},
+	{"Survivor Pension", (*domain.AnnualCashFlow).GetTotalSurvivorPension},
+	{"FERS Supplement", (*domain.AnnualCashFlow).GetTotalFERSSupplement},
+	{"Social Security", (*domain.AnnualCashFlow).GetTotalSSBenefit},
+	{"TSP Withdrawals", (*domain.AnnualCashFlow).GetTotalTSPWithdrawal},
+	{"TSP Annuity", (*domain.AnnualCashFlow).GetTotalTSPAnnuity},
+	{"Income Annuities", (*domain.AnnualCashFlow).GetTotalIncomeAnnuity},
+	{"Other Income", (*domain.AnnualCashFlow).GetTotalOtherIncome},
+	{"IRA Withdrawals", (*domain.AnnualCashFlow).GetTotalIRAWithdrawal},
+}
+
+// participantIncome is a participant's gross income from all sources in a year
+func participantIncome(cf *domain.AnnualCashFlow, name string) decimal.Decimal {
+	return cf.Salaries.Get(name).Add(cf.Pensions.Get(name)).Add(cf.SurvivorPensions.Get(name)).
+		Add(cf.FERSSupplements.Get(name)).Add(cf.SSBenefits.Get(name)).
+		Add(cf.TSPWithdrawals.Get(name)).Add(cf.TSPAnnuities.Get(name)).Add(cf.IncomeAnnuities.Get(name)).Add(cf.OtherIncome.Get(name)).
+		Add(cf.IRAWithdrawalsTraditional.Get(name)).Add(cf.IRAWithdrawalsRoth.Get(name))
+}
+
+// buildHTMLScenarios assigns each scenario a unique anchor and collects its chart series
+func buildHTMLScenarios(scenarios []domain.ScenarioSummary) ([]htmlScenario, []scenarioChartData) {
+	views := make([]htmlScenario, 0, len(scenarios))
+	charts := make([]scenarioChartData, 0, len(scenarios))
+	used := make(map[string]bool, len(scenarios))
+	for i, s := range scenarios {
+		view := htmlScenario{ScenarioSummary: s, ID: scenarioAnchor(s.Name, i, used), Participants: projectionParticipants(s.Projection)}
+		views = append(views, view)
+		charts = append(charts, scenarioCharts(view))
+	}
+	return views, charts
+}
+
+// scenarioAnchor derives a URL fragment from the scenario name, falling back to the
+// scenario's position and adding a suffix when two names reduce to the same anchor
+func scenarioAnchor(name string, index int, used map[string]bool) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	slug := b.String()
+	if slug == "" {
+		slug = strconv.Itoa(index + 1)
+	}
+	id := "scenario-" + slug
+	for n := 2; used[id]; n++ {
+		id = "scenario-" + slug + "-" + strconv.Itoa(n)
+	}
+	used[id] = true
+	return id
+}
+
+// projectionParticipants lists the participants appearing in a projection in order of
+// first appearance
+func projectionParticipants(projection []domain.AnnualCashFlow) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := range projection {
+		for _, name := range projection[i].GetParticipantNames() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// scenarioCharts collects a scenario's chart series. Income sources that are zero in
+// every year are left out.
+func scenarioCharts(view htmlScenario) scenarioChartData {
+	years := view.Projection
+	data := scenarioChartData{
+		ID:         view.ID,
+		Name:       view.Name,
+		Years:      make([]int, len(years)),
+		NetIncome:  make([]float64, len(years)),
+		TSPBalance: make([]float64, len(years)),
+	}
+	for i := range years {
+		data.Years[i] = years[i].Date.Year()
+		data.NetIncome[i] = roundedFloat(years[i].NetIncome)
+		data.TSPBalance[i] = roundedFloat(years[i].TotalTSPBalance())
+	}
+	for _, source := range incomeSources {
+		if series, ok := yearlySeries(source.label, years, source.total); ok {
+			data.Sources = append(data.Sources, series)
+		}
+	}
+	for _, name := range view.Participants {
+		tsp, _ := yearlySeries(name, years, func(cf *domain.AnnualCashFlow) decimal.Decimal { return cf.TSPBalances.Get(name) })
+		income, _ := yearlySeries(name, years, func(cf *domain.AnnualCashFlow) decimal.Decimal { return participantIncome(cf, name) })
+		data.ParticipantTSP = append(data.ParticipantTSP, tsp)
+		data.ParticipantIncome = append(data.ParticipantIncome, income)
+	}
+	return data
+}
+
+// yearlySeries evaluates value for every year; ok is false when it is zero throughout
+func yearlySeries(label string, years []domain.AnnualCashFlow, value func(*domain.AnnualCashFlow) decimal.Decimal) (chartSeries, bool) {
+	series := chartSeries{Label: label, Values: make([]float64, len(years))}
+	ok := false
+	for i := range years {
+		series.Values[i] = roundedFloat(value(&years[i]))
+		ok = ok || series.Values[i] != 0
+	}
+	return series, ok
+}
+
+func roundedFloat(d decimal.Decimal) float64 {
+	return d.Round(0).InexactFloat64()
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// marginalRateBarScale is the marginal rate each chart column stands for (2%)
+var marginalRateBarScale = decimal.NewFromFloat(0.02)
+
+// marginalRateBarWidth caps chart bars so IRMAA cliffs stay on one line
+const marginalRateBarWidth = 50
+
+// FormatMarginalRateCSV writes one row per sweep point
+func FormatMarginalRateCSV(curve *domain.MarginalRateCurve) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"Year", "AdditionalIncome", "FederalTax", "StateLocalTax", "TaxableSocialSecurity", "MAGI", "IRMAATier", "IRMAACost", "TotalCost", "MarginalRate", "AverageRate"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, p := range curve.Points {
+		row := []string{
+			fmt.Sprintf("%d", curve.Year),
+			p.AdditionalIncome.StringFixed(0),
+			domain.FormatAmount(p.FederalTax),
+			domain.FormatAmount(p.StateLocalTax),
+			domain.FormatAmount(p.TaxableSocialSecurity),
+			domain.FormatAmount(p.MAGI),
+			p.IRMAATier,
+			domain.FormatAmount(p.IRMAACost),
+			domain.FormatAmount(p.TotalCost),
+			p.MarginalRate.StringFixed(4),
+			p.AverageRate.StringFixed(4),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// FormatMarginalRateChart draws the curve as text bars, one per run of additional income
+// taxed at the same marginal rate, marking where IRMAA tiers change
+func FormatMarginalRateChart(curve *domain.MarginalRateCurve) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "EFFECTIVE MARGINAL RATE CURVE: %s, %d (%s)\n", curve.Scenario, curve.Year, curve.FilingStatus)
+	fmt.Fprintln(&buf, strings.Repeat("=", 80))
+	fmt.Fprintf(&buf, "Federal, state and local tax plus IRMAA surcharges on each $%s of added ordinary income\n", curve.Step.StringFixed(0))
+	fmt.Fprintln(&buf)
+
+	points := curve.Points
+	for start := 1; start < len(points); {
+		end := start
+		for end+1 < len(points) && points[end+1].MarginalRate.Equal(points[start].MarginalRate) && points[end+1].IRMAATier == points[start].IRMAATier {
+			end++
+		}
+
+		rate := points[start].MarginalRate
+		bars := int(rate.Div(marginalRateBarScale).Round(0).IntPart())
+		if bars < 0 {
+			bars = 0
+		}
+		bar := strings.Repeat("#", bars)
+		if bars > marginalRateBarWidth {
+			bar = strings.Repeat("#", marginalRateBarWidth) + "+"
+		}
+		note := ""
+		if points[start].IRMAATier != points[start-1].IRMAATier {
+			note = "  IRMAA " + points[start].IRMAATier
+		}
+		fmt.Fprintf(&buf, "  $%7s - $%7s  %7s  %s%s\n",
+			points[start-1].AdditionalIncome.StringFixed(0), points[end].AdditionalIncome.StringFixed(0),
+			FormatPercentage(rate.Mul(decimal.NewFromInt(100))), bar, note)
+		start = end + 1
+	}
+
+	if n := len(points); n > 1 {
+		last := points[n-1]
+		fmt.Fprintln(&buf)
+		fmt.Fprintf(&buf, "Adding %s costs %s (average rate %s)\n", FormatCurrency(last.AdditionalIncome),
+			FormatCurrency(last.TotalCost.Sub(points[0].TotalCost)), FormatPercentage(last.AverageRate.Mul(decimal.NewFromInt(100))))
+	}
+	return buf.String()
+}
@@ -40,10 +40,10 @@ func (m *MonteCarloCSVReport) GenerateSummaryCSV(outputPath string) error {
 
 	// Write summary data
 	summaryData := [][]string{
-		{"Success Rate", fmt.Sprintf("%.2f%%", m.Result.SuccessRate.Mul(decimal.NewFromFloat(100)).InexactFloat64()), "Percentage of successful simulations"},
+		{"Success Rate", domain.FormatRate(m.Result.SuccessRate), "Percentage of successful simulations"},
 		{"Median Net Income", fmt.Sprintf("$%s", m.Result.MedianNetIncome.StringFixed(0)), "Median annual net income across all simulations"},
 		{"Income Volatility", fmt.Sprintf("$%s", m.Result.IncomeVolatility.StringFixed(0)), "Standard deviation of net income"},
-		{"TSP Depletion Rate", fmt.Sprintf("%.2f%%", m.Result.TSPDepletionRate.Mul(decimal.NewFromFloat(100)).InexactFloat64()), "Percentage of simulations where TSP was depleted"},
+		{"TSP Depletion Rate", domain.FormatRate(m.Result.TSPDepletionRate), "Percentage of simulations where TSP was depleted"},
 		{"Median TSP Longevity", fmt.Sprintf("%s years", m.Result.TSPLongevityPercentiles.P50.StringFixed(0)), "Median years until TSP depletion"},
 		{"10th Percentile Income", fmt.Sprintf("$%s", m.Result.NetIncomePercentiles.P10.StringFixed(0)), "10th percentile of net income"},
 		{"25th Percentile Income", fmt.Sprintf("$%s", m.Result.NetIncomePercentiles.P25.StringFixed(0)), "25th percentile of net income"},
@@ -226,7 +226,7 @@ func (m *MonteCarloHTMLReport) generateHTMLContent() string {
             <div class="summary-grid">
                 <div class="summary-card %s">
                     <h3>Success Rate</h3>
-                    <div class="value">%.1f%%</div>
+                    <div class="value">%s</div>
                 </div>
                 <div class="summary-card">
                     <h3>Median Net Income</h3>
@@ -638,7 +638,7 @@ func (m *MonteCarloHTMLReport) generateHTMLContent() string {
 </body>
 </html>`,
 		m.getSuccessRateClass(),
-		m.Result.SuccessRate.Mul(decimal.NewFromFloat(100)).InexactFloat64(),
+		domain.FormatRate(m.Result.SuccessRate),
 		m.formatCurrency(m.Result.MedianNetIncome),
 		m.Config.NumSimulations,
 		m.getRiskLevel(),
@@ -0,0 +1,127 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// nonDollarFields are the result fields that hold rates, ratios, percentages or names
+// rather than dollars; redaction leaves them, and everything below them, unchanged
+var nonDollarFields = map[string]bool{
+	"name":             true,
+	"scenarioName":     true,
+	"successRate":      true,
+	"percentageChange": true,
+	"reductionFactor":  true,
+	"floorCoverage":    true,
+	"minCoverage":      true,
+	"traditionalPct":   true,
+	"rothPct":          true,
+	"return":           true,
+	"age":              true,
+	"spendingFactor":   true,
+}
+
+// dollarText matches dollar amounts in report text such as "$1,234", "$12.50" or "$1.2M"
+var dollarText = regexp.MustCompile(`\$(-?[0-9][0-9,]*(?:\.[0-9]+)?)([KkMm]?)`)
+
+// RandomRedactionFactor returns an undisclosed scale for RedactResults between 0.5 and 2
+func RandomRedactionFactor() decimal.Decimal {
+	return decimal.NewFromFloat(0.5 + 1.5*rand.Float64()).Round(4)
+}
+
+// RedactResults returns a copy of the results with every dollar amount, including those
+// in warnings and recommendations, multiplied by factor. Ratios between amounts, rates,
+// percentages, ages and years are unchanged, so a redacted report can be shared without
+// revealing the household's finances. The original results are not modified.
+func RedactResults(results *domain.ScenarioComparison, factor decimal.Decimal) (*domain.ScenarioComparison, error) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("redacting results: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("redacting results: %w", err)
+	}
+	if data, err = json.Marshal(redactValue(doc, factor, true)); err != nil {
+		return nil, fmt.Errorf("redacting results: %w", err)
+	}
+	var redacted domain.ScenarioComparison
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return nil, fmt.Errorf("redacting results: %w", err)
+	}
+	return &redacted, nil
+}
+
+// redactValue scales the dollar amounts in a decoded JSON value. Decimals are encoded as
+// strings; plain JSON numbers are counts, ages and years and are kept.
+func redactValue(v any, factor decimal.Decimal, dollars bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = redactValue(child, factor, dollars && !nonDollarFields[key])
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactValue(child, factor, dollars)
+		}
+	case string:
+		if amount, err := decimal.NewFromString(v); err == nil {
+			if dollars {
+				return amount.Mul(factor).Round(2).String()
+			}
+			return v
+		}
+		return redactText(v, factor)
+	}
+	return v
+}
+
+// redactText scales the dollar amounts in a sentence, keeping their format
+func redactText(s string, factor decimal.Decimal) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	return dollarText.ReplaceAllStringFunc(s, func(match string) string {
+		parts := dollarText.FindStringSubmatch(match)
+		amount, err := decimal.NewFromString(strings.ReplaceAll(parts[1], ",", ""))
+		if err != nil {
+			return match
+		}
+		places := int32(0)
+		if dot := strings.IndexByte(parts[1], '.'); dot >= 0 {
+			places = int32(len(parts[1]) - dot - 1)
+		}
+		scaled := amount.Mul(factor).StringFixed(places)
+		if strings.Contains(parts[1], ",") {
+			scaled = groupThousands(scaled)
+		}
+		return "$" + scaled + parts[2]
+	})
+}
+
+// groupThousands inserts commas between the thousands of a formatted number
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasFrac {
+		return sign + b.String() + "." + frac
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactResults(t *testing.T) {
+	cf := domain.AnnualCashFlow{Year: 2027}
+	cf.NetIncome = decimal.NewFromInt(90000)
+	cf.TSPBalances = domain.ParticipantValuesFromMap(map[string]decimal.Decimal{"Ann": decimal.NewFromInt(400000)})
+	cf.Ages = domain.ParticipantValuesFromMap(map[string]int{"Ann": 63})
+	cf.FloorCoverage = decimal.NewFromFloat(1.25)
+	results := &domain.ScenarioComparison{
+		BaselineNetIncome: decimal.NewFromInt(100000),
+		Scenarios: []domain.ScenarioSummary{{
+			Name:         "Retire at 62",
+			SuccessRate:  decimal.NewFromFloat(0.9),
+			TSPLongevity: 30,
+			Projection:   []domain.AnnualCashFlow{cf},
+			Warnings: []domain.EngineWarning{{Year: 2027, Code: domain.WarningOutflowShortfall, Amount: decimal.NewFromInt(1500),
+				Message: "Ann: New roof of $30000 exceeded the IRA balance; $1,500.00 unpaid"}},
+		}},
+	}
+
+	redacted, err := RedactResults(results, decimal.NewFromFloat(1.5))
+	require.NoError(t, err)
+	assert.Equal(t, "150000", redacted.BaselineNetIncome.String())
+	s := redacted.Scenarios[0]
+	assert.Equal(t, "Retire at 62", s.Name)
+	assert.Equal(t, "0.9", s.SuccessRate.String(), "rates are not dollars")
+	assert.Equal(t, 30, s.TSPLongevity)
+	assert.Equal(t, "135000", s.Projection[0].NetIncome.String())
+	assert.Equal(t, "600000", s.Projection[0].TSPBalances.Get("Ann").String())
+	assert.Equal(t, 63, s.Projection[0].Ages.Get("Ann"))
+	assert.Equal(t, "1.25", s.Projection[0].FloorCoverage.String())
+	assert.Equal(t, "2250", s.Warnings[0].Amount.String())
+	assert.Equal(t, "Ann: New roof of $45000 exceeded the IRA balance; $2,250.00 unpaid", s.Warnings[0].Message)
+
+	assert.Equal(t, "100000", results.BaselineNetIncome.String(), "the original results are unchanged")
+}
+
+func TestRandomRedactionFactor(t *testing.T) {
+	for range 100 {
+		f := RandomRedactionFactor()
+		assert.True(t, f.GreaterThanOrEqual(decimal.NewFromFloat(0.5)) && f.LessThanOrEqual(decimal.NewFromInt(2)), f.String())
+	}
+}
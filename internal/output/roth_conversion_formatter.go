@@ -43,7 +43,7 @@ func (f *RothConversionTableFormatter) FormatRothConversionPlan(plan *domain.Rot
 	// Header
 	output.WriteString("ROTH CONVERSION ANALYSIS\n")
 	output.WriteString("=================================================================\n")
-	output.WriteString(fmt.Sprintf("Participant: %s\n", plan.Participant))
+	output.WriteString(fmt.Sprintf("Participant: %s\n", plan.ParticipantLabels.Label(plan.Participant)))
 	output.WriteString(fmt.Sprintf("Conversion Window: %s\n", plan.ConversionWindow.String()))
 	output.WriteString(fmt.Sprintf("Target Bracket: %d%%\n", plan.TargetBracket))
 	output.WriteString(fmt.Sprintf("Objective: %s\n\n", plan.Objective.String()))
@@ -84,9 +84,12 @@ func (f *RothConversionTableFormatter) FormatRothConversionPlan(plan *domain.Rot
 		output.WriteString("NET BENEFIT OVER 30 YEARS:\n")
 		output.WriteString(fmt.Sprintf("  Tax Cost:      %s (paid upfront)\n", FormatCurrency(plan.Analysis.TotalTaxPaid)))
 		output.WriteString(fmt.Sprintf("  IRMAA Savings: %s\n", FormatCurrency(plan.Analysis.IRMAASavings)))
+		if !plan.Analysis.PremiumTaxCreditLost.IsZero() {
+			output.WriteString(fmt.Sprintf("  ACA Credits:   %s lost (premium tax credits before Medicare)\n", FormatCurrency(plan.Analysis.PremiumTaxCreditLost)))
+		}
 		output.WriteString(fmt.Sprintf("  RMD Reduction: %s (lower taxes on smaller RMDs)\n", FormatCurrency(plan.Analysis.RMDTaxReduction)))
 		output.WriteString(fmt.Sprintf("  Total Benefit: %s (NET SAVINGS)\n", FormatCurrency(plan.Analysis.NetBenefit)))
-		output.WriteString(fmt.Sprintf("  ROI: %.1f%% return on conversion tax paid\n\n", plan.Analysis.ROI.InexactFloat64()))
+		output.WriteString(fmt.Sprintf("  ROI: %s return on conversion tax paid\n\n", domain.FormatPercent(plan.Analysis.ROI)))
 
 		output.WriteString(fmt.Sprintf("RECOMMENDATION: %s\n\n", plan.Analysis.Recommendation))
 
@@ -124,7 +127,7 @@ func (f *RothConversionJSONFormatter) FormatRothConversionPlan(plan *domain.Roth
     "amount": "%s"
   },
   "netBenefit": "%s",
-  "roi": "%.1f%%"
+  "roi": "%s"
 }`,
 		plan.Participant,
 		plan.ConversionWindow.String(),
@@ -133,5 +136,5 @@ func (f *RothConversionJSONFormatter) FormatRothConversionPlan(plan *domain.Roth
 		plan.Recommended.Strategy.Year,
 		plan.Recommended.Strategy.Amount.String(),
 		plan.Analysis.NetBenefit.String(),
-		plan.Analysis.ROI.InexactFloat64()), nil
+		domain.FormatPercent(plan.Analysis.ROI)), nil
 }
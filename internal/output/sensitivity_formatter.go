@@ -42,10 +42,10 @@ func (scf SensitivityConsoleFormatter) formatSingleAnalysis(buf *bytes.Buffer, a
 
 	fmt.Fprintf(buf, "SENSITIVITY ANALYSIS: %s\n", strings.ToUpper(strings.ReplaceAll(param.Name, "_", " ")))
 	fmt.Fprintf(buf, "=================================================================\n")
-	fmt.Fprintf(buf, "Base Case: %s = %.1f%%\n", param.Name, param.BaseValue.Mul(decimal.NewFromInt(100)).InexactFloat64())
-	fmt.Fprintf(buf, "Range: %.1f%% to %.1f%% (%d steps)\n",
-		param.MinValue.Mul(decimal.NewFromInt(100)).InexactFloat64(),
-		param.MaxValue.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+	fmt.Fprintf(buf, "Base Case: %s = %s\n", param.Name, domain.FormatRate(param.BaseValue))
+	fmt.Fprintf(buf, "Range: %s to %s (%d steps)\n",
+		domain.FormatRate(param.MinValue),
+		domain.FormatRate(param.MaxValue),
 		param.Steps)
 	fmt.Fprintf(buf, "Description: %s\n", param.Description)
 	fmt.Fprintln(buf)
@@ -75,7 +75,7 @@ func (scf SensitivityConsoleFormatter) formatSingleAnalysis(buf *bytes.Buffer, a
 		paramValue := result.ParameterValues[param.Name]
 		isBaseCase := paramValue.Equal(param.BaseValue)
 
-		paramValueStr := fmt.Sprintf("%.1f%%", paramValue.Mul(decimal.NewFromInt(100)).InexactFloat64())
+		paramValueStr := fmt.Sprintf("%s", domain.FormatRate(paramValue))
 		if isBaseCase {
 			paramValueStr += " ← BASE"
 		}
@@ -112,11 +112,11 @@ func (scf SensitivityConsoleFormatter) formatSingleAnalysis(buf *bytes.Buffer, a
 			maxSensitivity = sensitivityScore
 		}
 
-		fmt.Fprintf(buf, "  %+.1f%% %s → %s Year 5 income (%+.1f%%)\n",
-			paramChange.InexactFloat64(),
+		fmt.Fprintf(buf, "  %s %s → %s Year 5 income (%s)\n",
+			domain.FormatSignedPercent(paramChange),
 			param.Name,
 			FormatCurrency(netIncomeChange),
-			netIncomeChangePct.InexactFloat64())
+			domain.FormatSignedPercent(netIncomeChangePct))
 	}
 
 	// TSP longevity sensitivity
@@ -133,8 +133,8 @@ func (scf SensitivityConsoleFormatter) formatSingleAnalysis(buf *bytes.Buffer, a
 			longevitySensitivity = longevityChange.Abs()
 		}
 
-		fmt.Fprintf(buf, "  %+.1f%% %s → %+d years TSP longevity\n",
-			paramChange.InexactFloat64(),
+		fmt.Fprintf(buf, "  %s %s → %+d years TSP longevity\n",
+			domain.FormatSignedPercent(paramChange),
 			param.Name,
 			result.KeyMetrics.TSPLongevity-baseResult.KeyMetrics.TSPLongevity)
 	}
@@ -170,21 +170,21 @@ func (scf SensitivityConsoleFormatter) formatSingleAnalysis(buf *bytes.Buffer, a
 func (scf SensitivityConsoleFormatter) formatMatrixAnalysis(buf *bytes.Buffer, matrix *domain.SensitivityMatrix) (string, error) {
 	fmt.Fprintf(buf, "SENSITIVITY MATRIX ANALYSIS\n")
 	fmt.Fprintf(buf, "=================================================================\n")
-	fmt.Fprintf(buf, "Parameter 1: %s (%.1f%% to %.1f%%)\n",
+	fmt.Fprintf(buf, "Parameter 1: %s (%s to %s)\n",
 		matrix.Parameter1.Name,
-		matrix.Parameter1.MinValue.Mul(decimal.NewFromInt(100)).InexactFloat64(),
-		matrix.Parameter1.MaxValue.Mul(decimal.NewFromInt(100)).InexactFloat64())
-	fmt.Fprintf(buf, "Parameter 2: %s (%.1f%% to %.1f%%)\n",
+		domain.FormatRate(matrix.Parameter1.MinValue),
+		domain.FormatRate(matrix.Parameter1.MaxValue))
+	fmt.Fprintf(buf, "Parameter 2: %s (%s to %s)\n",
 		matrix.Parameter2.Name,
-		matrix.Parameter2.MinValue.Mul(decimal.NewFromInt(100)).InexactFloat64(),
-		matrix.Parameter2.MaxValue.Mul(decimal.NewFromInt(100)).InexactFloat64())
+		domain.FormatRate(matrix.Parameter2.MinValue),
+		domain.FormatRate(matrix.Parameter2.MaxValue))
 	fmt.Fprintln(buf)
 
 	// Matrix table
 	fmt.Fprintf(buf, "%-12s", matrix.Parameter2.Name)
 	for j := range matrix.MatrixResults[0] {
 		param2Value := matrix.MatrixResults[0][j].ParameterValues[matrix.Parameter2.Name]
-		fmt.Fprintf(buf, " %-10s", fmt.Sprintf("%.1f%%", param2Value.Mul(decimal.NewFromInt(100)).InexactFloat64()))
+		fmt.Fprintf(buf, " %-10s", fmt.Sprintf("%s", domain.FormatRate(param2Value)))
 	}
 	fmt.Fprintln(buf)
 
@@ -198,7 +198,7 @@ func (scf SensitivityConsoleFormatter) formatMatrixAnalysis(buf *bytes.Buffer, m
 
 	for i := range matrix.MatrixResults {
 		param1Value := matrix.MatrixResults[i][0].ParameterValues[matrix.Parameter1.Name]
-		fmt.Fprintf(buf, "%-12s", fmt.Sprintf("%.1f%%", param1Value.Mul(decimal.NewFromInt(100)).InexactFloat64()))
+		fmt.Fprintf(buf, "%-12s", fmt.Sprintf("%s", domain.FormatRate(param1Value)))
 
 		for j := range matrix.MatrixResults[i] {
 			result := matrix.MatrixResults[i][j]
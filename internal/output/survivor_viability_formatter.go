@@ -21,8 +21,8 @@ func (svf SurvivorViabilityConsoleFormatter) FormatSurvivorViabilityAnalysis(ana
 	fmt.Fprintln(&buf, "SURVIVOR VIABILITY ANALYSIS")
 	fmt.Fprintln(&buf, strings.Repeat("=", 80))
 	fmt.Fprintf(&buf, "Scenario: %s\n", analysis.ScenarioName)
-	fmt.Fprintf(&buf, "Deceased: %s (age %d in %d)\n", analysis.DeceasedParticipant, analysis.DeathAge, analysis.DeathYear)
-	fmt.Fprintf(&buf, "Survivor: %s (age %d at time of death)\n", analysis.SurvivorParticipant, analysis.SurvivorAge)
+	fmt.Fprintf(&buf, "Deceased: %s (age %d in %d)\n", analysis.ParticipantLabels.Label(analysis.DeceasedParticipant), analysis.DeathAge, analysis.DeathYear)
+	fmt.Fprintf(&buf, "Survivor: %s (age %d at time of death)\n", analysis.ParticipantLabels.Label(analysis.SurvivorParticipant), analysis.SurvivorAge)
 	fmt.Fprintln(&buf)
 
 	// Pre-death analysis
@@ -36,13 +36,13 @@ func (svf SurvivorViabilityConsoleFormatter) FormatSurvivorViabilityAnalysis(ana
 
 	// Post-death analysis
 	fmt.Fprintf(&buf, "POST-DEATH (%s, %d+):\n", analysis.PostDeathAnalysis.FilingStatus, analysis.PostDeathAnalysis.Year)
-	fmt.Fprintf(&buf, "  %s's Net Income:       %s/year (%.1f%%)\n",
-		analysis.SurvivorParticipant,
+	fmt.Fprintf(&buf, "  %s's Net Income:       %s/year (%s)\n",
+		analysis.ParticipantLabels.Label(analysis.SurvivorParticipant),
 		FormatCurrency(analysis.PostDeathAnalysis.NetIncome),
-		analysis.PostDeathAnalysis.NetIncome.Div(analysis.PreDeathAnalysis.NetIncome).Mul(decimal.NewFromInt(100)).InexactFloat64())
-	fmt.Fprintf(&buf, "  Monthly:                %s (%.1f%%)\n",
+		domain.FormatRate(analysis.PostDeathAnalysis.NetIncome.Div(analysis.PreDeathAnalysis.NetIncome)))
+	fmt.Fprintf(&buf, "  Monthly:                %s (%s)\n",
 		FormatCurrency(analysis.PostDeathAnalysis.MonthlyIncome),
-		analysis.PostDeathAnalysis.MonthlyIncome.Div(analysis.PreDeathAnalysis.MonthlyIncome).Mul(decimal.NewFromInt(100)).InexactFloat64())
+		domain.FormatRate(analysis.PostDeathAnalysis.MonthlyIncome.Div(analysis.PreDeathAnalysis.MonthlyIncome)))
 	fmt.Fprintf(&buf, "  Healthcare Costs:       %s/year\n", FormatCurrency(analysis.PostDeathAnalysis.HealthcareCosts))
 	fmt.Fprintf(&buf, "  Tax Impact:             %s/year\n", FormatCurrency(analysis.PostDeathAnalysis.TaxImpact))
 	fmt.Fprintf(&buf, "  IRMAA Risk:             %s\n", analysis.PostDeathAnalysis.IRMAARisk)
@@ -50,9 +50,9 @@ func (svf SurvivorViabilityConsoleFormatter) FormatSurvivorViabilityAnalysis(ana
 
 	// Income sources breakdown
 	fmt.Fprintf(&buf, "  Income Sources:\n")
-	fmt.Fprintf(&buf, "    %s's Pension:        %s\n", analysis.SurvivorParticipant, FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.SurvivorPension))
-	fmt.Fprintf(&buf, "    %s's SS:             %s\n", analysis.SurvivorParticipant, FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.SurvivorSS))
-	fmt.Fprintf(&buf, "    %s's Survivor SS:    %s\n", analysis.DeceasedParticipant, FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.DeceasedSurvivorSS))
+	fmt.Fprintf(&buf, "    %s's Pension:        %s\n", analysis.ParticipantLabels.Label(analysis.SurvivorParticipant), FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.SurvivorPension))
+	fmt.Fprintf(&buf, "    %s's SS:             %s\n", analysis.ParticipantLabels.Label(analysis.SurvivorParticipant), FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.SurvivorSS))
+	fmt.Fprintf(&buf, "    %s's Survivor SS:    %s\n", analysis.ParticipantLabels.Label(analysis.DeceasedParticipant), FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.DeceasedSurvivorSS))
 	fmt.Fprintf(&buf, "    TSP Withdrawal:       %s\n", FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.TSPWithdrawals))
 	fmt.Fprintf(&buf, "    Other Income:         %s\n", FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.OtherIncome))
 	fmt.Fprintf(&buf, "    Total Income:         %s\n", FormatCurrency(analysis.PostDeathAnalysis.IncomeSources.TotalIncome))
@@ -69,9 +69,9 @@ func (svf SurvivorViabilityConsoleFormatter) FormatSurvivorViabilityAnalysis(ana
 		analysis.ViabilityAssessment.TargetIncome.Div(analysis.PreDeathAnalysis.NetIncome).Mul(decimal.NewFromInt(100)).InexactFloat64())
 	fmt.Fprintf(&buf, "    Target:    %s\n", FormatCurrency(analysis.ViabilityAssessment.TargetIncome))
 	fmt.Fprintf(&buf, "    Actual:    %s\n", FormatCurrency(analysis.ViabilityAssessment.ActualIncome))
-	fmt.Fprintf(&buf, "    Shortfall: %s (%.1f%%)  %s %s\n",
+	fmt.Fprintf(&buf, "    Shortfall: %s (%s)  %s %s\n",
 		FormatCurrency(analysis.ViabilityAssessment.IncomeShortfall),
-		analysis.ViabilityAssessment.ShortfallPercentage.InexactFloat64(),
+		domain.FormatPercent(analysis.ViabilityAssessment.ShortfallPercentage),
 		analysis.ViabilityAssessment.ViabilityColor,
 		analysis.ViabilityAssessment.ViabilityScore)
 	fmt.Fprintln(&buf)
@@ -119,8 +119,8 @@ func (svf SurvivorViabilityConsoleFormatter) FormatSurvivorViabilityAnalysis(ana
 func (svf SurvivorViabilityConsoleFormatter) FormatSurvivorViabilityAnalysisJSON(analysis *domain.SurvivorViabilityAnalysis) (string, error) {
 	// This would use json.Marshal in a real implementation
 	// For now, return a simple string representation
-	return fmt.Sprintf("Survivor Viability Analysis: %s -> %s (%.1f%% shortfall)",
-		analysis.DeceasedParticipant,
-		analysis.SurvivorParticipant,
-		analysis.ViabilityAssessment.ShortfallPercentage.InexactFloat64()), nil
+	return fmt.Sprintf("Survivor Viability Analysis: %s -> %s (%s shortfall)",
+		analysis.ParticipantLabels.Label(analysis.DeceasedParticipant),
+		analysis.ParticipantLabels.Label(analysis.SurvivorParticipant),
+		domain.FormatPercent(analysis.ViabilityAssessment.ShortfallPercentage)), nil
 }
@@ -3,7 +3,7 @@ package sequencing
 import "github.com/shopspring/decimal"
 
 // BracketFillStrategy attempts to fill a target marginal tax bracket with ordinary income
-// from traditional sources, sourcing remainder from tax-free (Roth) then taxable if needed.
+// from traditional TSP and IRA sources, sourcing remainder from tax-free (Roth) then taxable if needed.
 // Order inside logic: satisfy RMD first (if flagged) then fill bracket, then use Roth, then taxable.
 
 type BracketFillStrategy struct{}
@@ -45,8 +45,9 @@ func (s *BracketFillStrategy) Plan(sources []WithdrawalSource, ctx StrategyConte
 		}
 	}
 
-	// 2. Fill bracket with additional traditional withdrawals if bracket target defined
-	if remaining.GreaterThan(decimal.Zero) && ctx.TargetBracketPercent != nil && trad != nil && trad.Balance.GreaterThan(decimal.Zero) {
+	// 2. Fill bracket with additional traditional withdrawals if bracket target defined,
+	// drawing the TSP before the IRA
+	if remaining.GreaterThan(decimal.Zero) && ctx.TargetBracketPercent != nil {
 		// Determine bracket headroom: targetEdge - buffer - currentOrdinary
 		// We approximate targetEdge using bracket edges slice by searching for the edge whose marginal rate == target (not stored directly).
 		// For now treat TargetBracketPercent as cap on ordinary income total (CurrentOrdinaryIncome + fill <= pseudoEdge).
@@ -63,40 +64,50 @@ func (s *BracketFillStrategy) Plan(sources []WithdrawalSource, ctx StrategyConte
 				headroom = decimal.Zero
 			}
 		}
-		// Withdraw min of remaining, headroom, trad balance
-		fill := remaining
-		if !headroom.IsZero() && headroom.LessThan(fill) {
-			fill = headroom
-		}
-		if trad.Balance.LessThan(fill) {
-			fill = trad.Balance
-		}
-		if fill.GreaterThan(decimal.Zero) {
-			alloc := WithdrawalAllocation{Source: "traditional", Gross: fill, OrdinaryPortion: fill, MAGIImpact: fill}
-			plan.Allocations = append(plan.Allocations, alloc)
-			plan.TotalSourced = plan.TotalSourced.Add(fill)
-			plan.TraditionalUsed = plan.TraditionalUsed.Add(fill)
-			plan.EstimatedOrdinaryIncome = plan.EstimatedOrdinaryIncome.Add(fill)
-			plan.EstimatedMAGIImpact = plan.EstimatedMAGIImpact.Add(fill)
-			trad.Balance = trad.Balance.Sub(fill)
-			remaining = remaining.Sub(fill)
-			plan.BracketFilled = headroom.Sub(fill).LessThanOrEqual(decimal.Zero)
+		filled := decimal.Zero
+		for _, name := range []string{"traditional", "ira_traditional"} {
+			src := lookup[name]
+			if src == nil || src.Balance.LessThanOrEqual(decimal.Zero) || remaining.LessThanOrEqual(decimal.Zero) {
+				continue
+			}
+			// Withdraw min of remaining, headroom left, source balance
+			fill := remaining
+			if !headroom.IsZero() && headroom.Sub(filled).LessThan(fill) {
+				fill = headroom.Sub(filled)
+			}
+			if src.Balance.LessThan(fill) {
+				fill = src.Balance
+			}
+			if fill.GreaterThan(decimal.Zero) {
+				alloc := WithdrawalAllocation{Source: name, Gross: fill, OrdinaryPortion: fill, MAGIImpact: fill}
+				plan.Allocations = append(plan.Allocations, alloc)
+				plan.TotalSourced = plan.TotalSourced.Add(fill)
+				plan.TraditionalUsed = plan.TraditionalUsed.Add(fill)
+				plan.EstimatedOrdinaryIncome = plan.EstimatedOrdinaryIncome.Add(fill)
+				plan.EstimatedMAGIImpact = plan.EstimatedMAGIImpact.Add(fill)
+				src.Balance = src.Balance.Sub(fill)
+				remaining = remaining.Sub(fill)
+				filled = filled.Add(fill)
+				plan.BracketFilled = headroom.Sub(filled).LessThanOrEqual(decimal.Zero)
+			}
 		}
 	}
 
-	// 3. Use Roth for remainder
-	if remaining.GreaterThan(decimal.Zero) {
-		if roth := lookup["roth"]; roth != nil && roth.Balance.GreaterThan(decimal.Zero) {
-			withdraw := roth.Balance
-			if withdraw.GreaterThan(remaining) {
-				withdraw = remaining
-			}
-			alloc := WithdrawalAllocation{Source: "roth", Gross: withdraw, TaxFreePortion: withdraw}
-			plan.Allocations = append(plan.Allocations, alloc)
-			plan.TotalSourced = plan.TotalSourced.Add(withdraw)
-			plan.RothUsed = plan.RothUsed.Add(withdraw)
-			remaining = remaining.Sub(withdraw)
+	// 3. Use Roth for remainder, TSP before IRA
+	for _, name := range []string{"roth", "ira_roth"} {
+		roth := lookup[name]
+		if remaining.LessThanOrEqual(decimal.Zero) || roth == nil || roth.Balance.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+		withdraw := roth.Balance
+		if withdraw.GreaterThan(remaining) {
+			withdraw = remaining
 		}
+		alloc := WithdrawalAllocation{Source: name, Gross: withdraw, TaxFreePortion: withdraw}
+		plan.Allocations = append(plan.Allocations, alloc)
+		plan.TotalSourced = plan.TotalSourced.Add(withdraw)
+		plan.RothUsed = plan.RothUsed.Add(withdraw)
+		remaining = remaining.Sub(withdraw)
 	}
 
 	// 4. Use taxable last
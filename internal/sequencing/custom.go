@@ -3,7 +3,8 @@ package sequencing
 import "github.com/shopspring/decimal"
 
 // CustomStrategy executes withdrawals in a user-specified ordered list of sources.
-// Valid source names: taxable, traditional, roth. If sequence invalid, falls back to standard.
+// Valid source names: taxable, traditional, roth, ira_traditional, ira_roth. Sources left out
+// of the sequence are not drawn. If sequence invalid, falls back to standard.
 type CustomStrategy struct {
 	Sequence []string
 }
@@ -17,7 +18,7 @@ func (s *CustomStrategy) Plan(sources []WithdrawalSource, ctx StrategyContext) W
 	remaining := ctx.NeedAmount
 
 	// Validate sequence
-	allowed := map[string]bool{"taxable": true, "traditional": true, "roth": true}
+	allowed := map[string]bool{"taxable": true, "traditional": true, "roth": true, "ira_traditional": true, "ira_roth": true}
 	seen := map[string]bool{}
 	valid := true
 	for _, name := range s.Sequence {
@@ -83,9 +84,9 @@ func (s *CustomStrategy) Plan(sources []WithdrawalSource, ctx StrategyContext) W
 		plan.TotalSourced = plan.TotalSourced.Add(withdraw)
 		remaining = remaining.Sub(withdraw)
 		switch name {
-		case "traditional":
+		case "traditional", "ira_traditional":
 			plan.TraditionalUsed = plan.TraditionalUsed.Add(withdraw)
-		case "roth":
+		case "roth", "ira_roth":
 			plan.RothUsed = plan.RothUsed.Add(withdraw)
 		case "taxable":
 			plan.TaxableUsed = plan.TaxableUsed.Add(withdraw)
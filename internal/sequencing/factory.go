@@ -104,6 +104,16 @@ func CreateWithdrawalSources(
 		})
 	}
 
+	// Add traditional IRA; its RMD is computed separately and enforced by the projection
+	if participant.IRABalanceTraditional != nil && participant.IRABalanceTraditional.GreaterThan(decimal.Zero) {
+		sources = append(sources, WithdrawalSource{
+			Name:         "ira_traditional",
+			Balance:      *participant.IRABalanceTraditional,
+			TaxTreatment: OrdinaryIncome,
+			Priority:     3,
+		})
+	}
+
 	// Add Roth TSP
 	if rothBalance.GreaterThan(decimal.Zero) {
 		sources = append(sources, WithdrawalSource{
@@ -112,7 +122,17 @@ func CreateWithdrawalSources(
 			Basis:        decimal.Zero, // Roth has no basis for qualified distributions
 			TaxTreatment: TaxFree,
 			RMDRequired:  false,
-			Priority:     3,
+			Priority:     4,
+		})
+	}
+
+	// Add Roth IRA
+	if participant.IRABalanceRoth != nil && participant.IRABalanceRoth.GreaterThan(decimal.Zero) {
+		sources = append(sources, WithdrawalSource{
+			Name:         "ira_roth",
+			Balance:      *participant.IRABalanceRoth,
+			TaxTreatment: TaxFree,
+			Priority:     5,
 		})
 	}
 
@@ -0,0 +1,104 @@
+package sequencing
+
+import "github.com/shopspring/decimal"
+
+// LimitMAGI clips a plan so its allocations add at most headroom to MAGI. Allocations
+// are kept in plan order until the headroom is used up; the one that crosses it is cut
+// back to the remaining headroom and later allocations that add MAGI are dropped, except
+// that a source's pending RMD is always withdrawn. What was clipped is made up from the
+// Roth sources' remaining balances, TSP before IRA; whatever they cannot cover is left as
+// unmet need.
+func LimitMAGI(plan WithdrawalPlan, sources []WithdrawalSource, headroom decimal.Decimal) WithdrawalPlan {
+	if plan.EstimatedMAGIImpact.LessThanOrEqual(headroom) {
+		return plan
+	}
+	headroom = decimal.Max(headroom, decimal.Zero)
+	lookup := map[string]*WithdrawalSource{}
+	for i := range sources {
+		lookup[sources[i].Name] = &sources[i]
+	}
+
+	limited := WithdrawalPlan{
+		Requested:     plan.Requested,
+		StrategyUsed:  plan.StrategyUsed,
+		Notes:         plan.Notes,
+		BracketFilled: plan.BracketFilled,
+		RMDSatisfied:  plan.RMDSatisfied,
+		BracketTarget: plan.BracketTarget,
+		BracketBuffer: plan.BracketBuffer,
+		Allocations:   []WithdrawalAllocation{},
+	}
+	used := map[string]decimal.Decimal{}
+	magi := decimal.Zero
+	clipped := decimal.Zero
+	for _, alloc := range plan.Allocations {
+		if alloc.MAGIImpact.IsPositive() && magi.Add(alloc.MAGIImpact).GreaterThan(headroom) {
+			keep := decimal.Max(headroom.Sub(magi), decimal.Zero).Mul(alloc.Gross).Div(alloc.MAGIImpact)
+			if src, ok := lookup[alloc.Source]; ok && src.PendingRMD.IsPositive() {
+				keep = decimal.Max(keep, decimal.Min(src.PendingRMD, alloc.Gross))
+			}
+			scaled := scaleAllocation(alloc, keep)
+			clipped = clipped.Add(alloc.Gross.Sub(scaled.Gross))
+			alloc = scaled
+		}
+		if alloc.Gross.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+		magi = magi.Add(alloc.MAGIImpact)
+		used[alloc.Source] = used[alloc.Source].Add(alloc.Gross)
+		limited.Allocations = append(limited.Allocations, alloc)
+	}
+
+	// Make up the clipped amount from Roth balances the plan left untouched
+	for _, name := range []string{"roth", "ira_roth"} {
+		if !clipped.IsPositive() {
+			break
+		}
+		src, ok := lookup[name]
+		if !ok {
+			continue
+		}
+		withdraw := decimal.Min(src.Balance.Sub(used[name]), clipped)
+		if !withdraw.IsPositive() {
+			continue
+		}
+		limited.Allocations = append(limited.Allocations, WithdrawalAllocation{Source: name, Gross: withdraw, TaxFreePortion: withdraw})
+		clipped = clipped.Sub(withdraw)
+	}
+
+	for _, alloc := range limited.Allocations {
+		limited.TotalSourced = limited.TotalSourced.Add(alloc.Gross)
+		switch alloc.Source {
+		case "traditional", "ira_traditional":
+			limited.TraditionalUsed = limited.TraditionalUsed.Add(alloc.Gross)
+		case "roth", "ira_roth":
+			limited.RothUsed = limited.RothUsed.Add(alloc.Gross)
+		case "taxable":
+			limited.TaxableUsed = limited.TaxableUsed.Add(alloc.Gross)
+		}
+		limited.EstimatedOrdinaryIncome = limited.EstimatedOrdinaryIncome.Add(alloc.OrdinaryPortion)
+		limited.EstimatedCapitalGains = limited.EstimatedCapitalGains.Add(alloc.CapitalGainsPortion)
+		limited.EstimatedMAGIImpact = limited.EstimatedMAGIImpact.Add(alloc.MAGIImpact)
+	}
+	limited.RemainingNeed = decimal.Max(limited.Requested.Sub(limited.TotalSourced), decimal.Zero)
+	limited.Notes = append(limited.Notes, "withdrawals clipped to stay below the MAGI ceiling")
+	if limited.RemainingNeed.IsPositive() {
+		limited.Notes = append(limited.Notes, "insufficient Roth balances to replace clipped withdrawals")
+	}
+	return limited
+}
+
+// scaleAllocation returns the allocation cut back to gross, its portions in proportion
+func scaleAllocation(alloc WithdrawalAllocation, gross decimal.Decimal) WithdrawalAllocation {
+	scale := func(d decimal.Decimal) decimal.Decimal {
+		return d.Mul(gross).Div(alloc.Gross)
+	}
+	return WithdrawalAllocation{
+		Source:              alloc.Source,
+		Gross:               gross,
+		OrdinaryPortion:     scale(alloc.OrdinaryPortion),
+		CapitalGainsPortion: scale(alloc.CapitalGainsPortion),
+		TaxFreePortion:      scale(alloc.TaxFreePortion),
+		MAGIImpact:          scale(alloc.MAGIImpact),
+	}
+}
@@ -353,6 +353,94 @@ func TestCustomStrategy(t *testing.T) {
 	}
 }
 
+func TestIRASources(t *testing.T) {
+	participant := &domain.Participant{
+		IRABalanceTraditional: decimalPtr(decimal.NewFromInt(40000)),
+		IRABalanceRoth:        decimalPtr(decimal.NewFromInt(25000)),
+	}
+	sources := CreateWithdrawalSources(participant, decimal.NewFromInt(10000), decimal.NewFromInt(5000), false, decimal.Zero)
+
+	var names []string
+	for _, src := range sources {
+		names = append(names, src.Name)
+	}
+	want := []string{"traditional", "ira_traditional", "roth", "ira_roth"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected sources %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Expected sources %v, got %v", want, names)
+		}
+	}
+
+	ctx := StrategyContext{NeedAmount: decimal.NewFromInt(30000)}
+
+	// Standard drains the traditional TSP, then the traditional IRA
+	plan := NewStandardStrategy().Plan(sources, ctx)
+	if len(plan.Allocations) != 2 || plan.Allocations[1].Source != "ira_traditional" || !plan.Allocations[1].Gross.Equal(decimal.NewFromInt(20000)) {
+		t.Errorf("Expected $20,000 from the traditional IRA after the TSP, got %+v", plan.Allocations)
+	}
+	if !plan.TraditionalUsed.Equal(decimal.NewFromInt(30000)) {
+		t.Errorf("Expected traditional used 30000, got %v", plan.TraditionalUsed)
+	}
+
+	// Tax efficient spends both Roth accounts before any traditional money
+	plan = NewTaxEfficientStrategy().Plan(sources, ctx)
+	if plan.Allocations[0].Source != "roth" || plan.Allocations[1].Source != "ira_roth" || !plan.RothUsed.Equal(decimal.NewFromInt(30000)) {
+		t.Errorf("Expected Roth TSP then Roth IRA, got %+v", plan.Allocations)
+	}
+
+	// Bracket fill fills with traditional IRA money once the TSP is exhausted
+	bracket := 22
+	plan = NewBracketFillStrategy().Plan(sources, StrategyContext{NeedAmount: ctx.NeedAmount, TargetBracketPercent: &bracket, MarginalBracketEdges: []decimal.Decimal{decimal.NewFromInt(100000)}})
+	if !plan.TraditionalUsed.Equal(decimal.NewFromInt(30000)) || plan.Allocations[1].Source != "ira_traditional" {
+		t.Errorf("Expected the bracket filled from the TSP then the IRA, got %+v", plan.Allocations)
+	}
+
+	// A custom sequence may name the IRAs explicitly
+	plan = NewCustomStrategy([]string{"ira_roth", "traditional"}).Plan(sources, ctx)
+	if plan.StrategyUsed != "custom" || plan.Allocations[0].Source != "ira_roth" || !plan.Allocations[0].Gross.Equal(decimal.NewFromInt(25000)) {
+		t.Errorf("Expected the Roth IRA first, got %s %+v", plan.StrategyUsed, plan.Allocations)
+	}
+}
+
+func TestLimitMAGI(t *testing.T) {
+	sources := []WithdrawalSource{
+		{Name: "taxable", Balance: decimal.NewFromInt(20000), Basis: decimal.NewFromInt(10000), TaxTreatment: CapitalGains},
+		{Name: "traditional", Balance: decimal.NewFromInt(100000), TaxTreatment: OrdinaryIncome, PendingRMD: decimal.NewFromInt(5000)},
+		{Name: "roth", Balance: decimal.NewFromInt(12000), TaxTreatment: TaxFree},
+		{Name: "ira_roth", Balance: decimal.NewFromInt(50000), TaxTreatment: TaxFree},
+	}
+	plan := NewStandardStrategy().Plan(sources, StrategyContext{NeedAmount: decimal.NewFromInt(50000)})
+
+	// Within the headroom the plan is unchanged
+	if got := LimitMAGI(plan, sources, decimal.NewFromInt(40000)); len(got.Allocations) != len(plan.Allocations) || len(got.Notes) != len(plan.Notes) {
+		t.Errorf("Expected the plan unchanged, got %+v", got.Allocations)
+	}
+
+	// $10,000 of gains and $10,000 of traditional fit; the other $20,000 comes from the Roth TSP then the Roth IRA
+	limited := LimitMAGI(plan, sources, decimal.NewFromInt(20000))
+	if !limited.EstimatedMAGIImpact.Equal(decimal.NewFromInt(20000)) {
+		t.Errorf("Expected MAGI impact 20000, got %v", limited.EstimatedMAGIImpact)
+	}
+	if !limited.TraditionalUsed.Equal(decimal.NewFromInt(10000)) || !limited.RothUsed.Equal(decimal.NewFromInt(20000)) || !limited.TotalSourced.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("Expected 10000 traditional and 20000 Roth of 50000, got %v, %v of %v", limited.TraditionalUsed, limited.RothUsed, limited.TotalSourced)
+	}
+	if len(limited.Allocations) != 4 || limited.Allocations[2].Source != "roth" || !limited.Allocations[2].Gross.Equal(decimal.NewFromInt(12000)) || limited.Allocations[3].Source != "ira_roth" {
+		t.Errorf("Expected the Roth TSP drained before the Roth IRA, got %+v", limited.Allocations)
+	}
+
+	// The pending RMD is withdrawn even with no headroom left
+	limited = LimitMAGI(plan, sources, decimal.Zero)
+	if !limited.TraditionalUsed.Equal(decimal.NewFromInt(5000)) || limited.TaxableUsed.IsPositive() {
+		t.Errorf("Expected only the 5000 RMD from traditional, got %v traditional, %v taxable", limited.TraditionalUsed, limited.TaxableUsed)
+	}
+	if !limited.RemainingNeed.Equal(decimal.NewFromInt(0)) || !limited.RothUsed.Equal(decimal.NewFromInt(45000)) {
+		t.Errorf("Expected 45000 from Roth balances, got %v with %v unmet", limited.RothUsed, limited.RemainingNeed)
+	}
+}
+
 // Helper function to create decimal pointer
 func decimalPtr(d decimal.Decimal) *decimal.Decimal {
 	return &d
@@ -2,7 +2,7 @@ package sequencing
 
 import "github.com/shopspring/decimal"
 
-// StandardStrategy: taxable -> traditional -> roth
+// StandardStrategy: taxable -> traditional -> roth, TSP before IRA within each
 // Prioritizes spending taxable assets first (common for tax deferral) then traditional, preserving Roth for last.
 type StandardStrategy struct{}
 
@@ -14,8 +14,8 @@ func (s *StandardStrategy) Plan(sources []WithdrawalSource, ctx StrategyContext)
 	plan := WithdrawalPlan{Requested: ctx.NeedAmount, StrategyUsed: s.Name(), Allocations: []WithdrawalAllocation{}}
 	remaining := ctx.NeedAmount
 
-	// Order sources by implied priority: taxable (1), traditional (2), roth (3)
-	order := []string{"taxable", "traditional", "roth"}
+	// Order sources by implied priority: taxable (1), traditional TSP then IRA (2-3), Roth TSP then IRA (4-5)
+	order := []string{"taxable", "traditional", "ira_traditional", "roth", "ira_roth"}
 	lookup := map[string]*WithdrawalSource{}
 	for i := range sources {
 		lookup[sources[i].Name] = &sources[i]
@@ -67,9 +67,9 @@ func (s *StandardStrategy) Plan(sources []WithdrawalSource, ctx StrategyContext)
 		remaining = remaining.Sub(withdraw)
 		// Track convenience totals
 		switch name {
-		case "traditional":
+		case "traditional", "ira_traditional":
 			plan.TraditionalUsed = plan.TraditionalUsed.Add(withdraw)
-		case "roth":
+		case "roth", "ira_roth":
 			plan.RothUsed = plan.RothUsed.Add(withdraw)
 		case "taxable":
 			plan.TaxableUsed = plan.TaxableUsed.Add(withdraw)
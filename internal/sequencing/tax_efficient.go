@@ -5,7 +5,7 @@ import "github.com/shopspring/decimal"
 // TaxEfficientStrategy: roth -> traditional -> taxable (variant rationale: reduce future RMDs while still using taxable last)
 // However common advice often spends taxable first; this variant intentionally prioritizes Roth to illustrate contrast.
 // We'll adapt: actual definition from planning doc: Roth first then Traditional; taxable not modeled fully yet for RMD impact difference.
-// Here we keep order: roth -> traditional -> taxable.
+// Here we keep order: roth -> traditional -> taxable, drawing the TSP before the IRA within each.
 
 type TaxEfficientStrategy struct{}
 
@@ -17,7 +17,7 @@ func (s *TaxEfficientStrategy) Plan(sources []WithdrawalSource, ctx StrategyCont
 	plan := WithdrawalPlan{Requested: ctx.NeedAmount, StrategyUsed: s.Name(), Allocations: []WithdrawalAllocation{}}
 	remaining := ctx.NeedAmount
 
-	order := []string{"roth", "traditional", "taxable"}
+	order := []string{"roth", "ira_roth", "traditional", "ira_traditional", "taxable"}
 	lookup := map[string]*WithdrawalSource{}
 	for i := range sources {
 		lookup[sources[i].Name] = &sources[i]
@@ -67,9 +67,9 @@ func (s *TaxEfficientStrategy) Plan(sources []WithdrawalSource, ctx StrategyCont
 		plan.TotalSourced = plan.TotalSourced.Add(withdraw)
 		remaining = remaining.Sub(withdraw)
 		switch name {
-		case "traditional":
+		case "traditional", "ira_traditional":
 			plan.TraditionalUsed = plan.TraditionalUsed.Add(withdraw)
-		case "roth":
+		case "roth", "ira_roth":
 			plan.RothUsed = plan.RothUsed.Add(withdraw)
 		case "taxable":
 			plan.TaxableUsed = plan.TaxableUsed.Add(withdraw)
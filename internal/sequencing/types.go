@@ -30,7 +30,7 @@ func (tt TaxTreatment) String() string {
 }
 
 // WithdrawalSource represents an available pool for withdrawals
-// Name: semantic identifier (taxable | traditional | roth)
+// Name: semantic identifier (taxable | traditional | roth | ira_traditional | ira_roth)
 // Balance: current available balance
 // Basis: for taxable accounts to approximate gains (optional / zero for non-taxable)
 // TaxTreatment: how withdrawals impact taxes/MAGI
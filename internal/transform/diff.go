@@ -0,0 +1,131 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+)
+
+// ParameterChange records a single scenario field that differs from the base.
+// Path uses the scenario's JSON field names joined with dots
+// (e.g. "participant_scenarios.Alice.ss_start_age"). OldValue is nil when the
+// field was added and NewValue is nil when it was removed.
+type ParameterChange struct {
+	Transform string      `json:"transform,omitempty"`
+	Path      string      `json:"path"`
+	OldValue  interface{} `json:"oldValue"`
+	NewValue  interface{} `json:"newValue"`
+}
+
+// DiffScenarios returns the parameter deltas between two scenarios, sorted by path.
+// The scenario name is ignored since alternatives are always renamed.
+func DiffScenarios(base, modified *domain.GenericScenario) ([]ParameterChange, error) {
+	baseTree, err := scenarioTree(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode base scenario: %w", err)
+	}
+	modifiedTree, err := scenarioTree(modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode modified scenario: %w", err)
+	}
+	delete(baseTree, "name")
+	delete(modifiedTree, "name")
+
+	changes := []ParameterChange{}
+	diffValues("", baseTree, modifiedTree, &changes)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// ApplyTemplateWithChanges applies a template and reports the parameter deltas
+// each of its transforms made, attributed to the transform that made them.
+func ApplyTemplateWithChanges(base *domain.GenericScenario, template Template) (*domain.GenericScenario, []ParameterChange, error) {
+	if base == nil {
+		return nil, nil, fmt.Errorf("base scenario cannot be nil")
+	}
+
+	current := base.DeepCopy()
+	changes := []ParameterChange{}
+	for _, t := range template.Transforms {
+		next, err := ApplyTransforms(current, []ScenarioTransform{t})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stepChanges, err := DiffScenarios(current, next)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range stepChanges {
+			stepChanges[i].Transform = t.Name()
+		}
+		changes = append(changes, stepChanges...)
+		current = next
+	}
+
+	return current, changes, nil
+}
+
+// scenarioTree converts a scenario to its generic JSON representation
+func scenarioTree(scenario *domain.GenericScenario) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	if scenario == nil {
+		return tree, nil
+	}
+	data, err := json.Marshal(scenario)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// diffValues walks two decoded JSON values, recording leaf differences.
+// Objects are compared key by key; arrays and scalars are compared whole.
+func diffValues(path string, oldValue, newValue interface{}, changes *[]ParameterChange) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		for key, ov := range oldMap {
+			diffValues(joinPath(path, key), ov, newMap[key], changes)
+		}
+		for key, nv := range newMap {
+			if _, ok := oldMap[key]; !ok {
+				diffValues(joinPath(path, key), nil, nv, changes)
+			}
+		}
+		return
+	}
+
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+
+	// Expand added or removed objects so each leaf gets its own path
+	if oldIsMap && newValue == nil {
+		for key, ov := range oldMap {
+			diffValues(joinPath(path, key), ov, nil, changes)
+		}
+		return
+	}
+	if newIsMap && oldValue == nil {
+		for key, nv := range newMap {
+			diffValues(joinPath(path, key), nil, nv, changes)
+		}
+		return
+	}
+
+	*changes = append(*changes, ParameterChange{Path: path, OldValue: oldValue, NewValue: newValue})
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
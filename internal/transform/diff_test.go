@@ -0,0 +1,74 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgehrsitz/rpgo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTemplateWithChanges(t *testing.T) {
+	retirementDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := &domain.GenericScenario{
+		Name: "Base",
+		ParticipantScenarios: map[string]domain.ParticipantScenario{
+			"Alice": {
+				ParticipantName: "Alice",
+				RetirementDate:  &retirementDate,
+				SSStartAge:      62,
+			},
+		},
+	}
+
+	template := Template{
+		Name: "test",
+		Transforms: []ScenarioTransform{
+			&PostponeRetirement{Participant: "Alice", Months: 12},
+			&DelaySSClaim{Participant: "Alice", NewAge: 70},
+		},
+	}
+
+	result, changes, err := ApplyTemplateWithChanges(base, template)
+	require.NoError(t, err)
+	assert.Equal(t, 70, result.ParticipantScenarios["Alice"].SSStartAge)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, ParameterChange{
+		Transform: "postpone_retirement",
+		Path:      "participant_scenarios.Alice.retirement_date",
+		OldValue:  "2025-01-01T00:00:00Z",
+		NewValue:  "2026-01-01T00:00:00Z",
+	}, changes[0])
+	assert.Equal(t, ParameterChange{
+		Transform: "delay_ss_claim",
+		Path:      "participant_scenarios.Alice.ss_start_age",
+		OldValue:  float64(62),
+		NewValue:  float64(70),
+	}, changes[1])
+}
+
+func TestDiffScenarios_AddedFieldsAndIgnoredName(t *testing.T) {
+	base := &domain.GenericScenario{Name: "Base"}
+	target := 22
+	modified := &domain.GenericScenario{
+		Name: "Other",
+		WithdrawalSequencing: &domain.WithdrawalSequencingConfig{
+			Strategy:      "bracket_fill",
+			TargetBracket: &target,
+		},
+	}
+
+	changes, err := DiffScenarios(base, modified)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "withdrawal_sequencing.strategy", changes[0].Path)
+	assert.Nil(t, changes[0].OldValue)
+	assert.Equal(t, "bracket_fill", changes[0].NewValue)
+	assert.Equal(t, "withdrawal_sequencing.target_bracket", changes[1].Path)
+
+	none, err := DiffScenarios(modified, modified.DeepCopy())
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
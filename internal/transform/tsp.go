@@ -8,7 +8,7 @@ import (
 )
 
 // ModifyTSPStrategy changes the TSP withdrawal strategy for a participant.
-// Valid strategies: "4_percent_rule", "variable_percentage", "need_based", "fixed_amount"
+// Valid strategies: "4_percent_rule", "variable_percentage", "need_based", "fixed_amount", "guardrails", "buckets", "vpw", "rmd_only", "spending_target", "sepp"
 type ModifyTSPStrategy struct {
 	Participant   string // Name of the participant
 	NewStrategy   string // New withdrawal strategy
@@ -32,7 +32,13 @@ func (mts *ModifyTSPStrategy) Validate(base *domain.GenericScenario) error {
 		"4_percent_rule":     true,
 		"variable_percentage": true,
 		"need_based":         true,
+		"guardrails":         true,
+		"buckets":            true,
+		"vpw":                true,
+		"rmd_only":           true,
+		"spending_target":    true,
 		"fixed_amount":       true,
+		"sepp":               true,
 	}
 
 	if !validStrategies[mts.NewStrategy] {
@@ -108,7 +114,7 @@ func (atr *AdjustTSPRate) Validate(base *domain.GenericScenario) error {
 	}
 
 	// Check if strategy is compatible with rates
-	if ps.TSPWithdrawalStrategy != "variable_percentage" && ps.TSPWithdrawalStrategy != "4_percent_rule" {
+	if ps.TSPWithdrawalStrategy != "variable_percentage" && ps.TSPWithdrawalStrategy != "4_percent_rule" && ps.TSPWithdrawalStrategy != "guardrails" && ps.TSPWithdrawalStrategy != "buckets" {
 		return NewTransformError(atr.Name(), "validate", fmt.Sprintf("TSP rate only applicable to percentage-based strategies, current strategy is %s", ps.TSPWithdrawalStrategy), nil)
 	}
 
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -101,39 +102,12 @@ func loadConfigCmd(path string) tea.Cmd {
 // calculateScenarioCmd returns a command that calculates a scenario
 func calculateScenarioCmd(scenario *domain.GenericScenario, cfg *domain.Configuration) tea.Cmd {
 	return func() tea.Msg {
-		// Create a temporary config with just this scenario
-		tempConfig := &domain.Configuration{
-			GlobalAssumptions: cfg.GlobalAssumptions,
-			Household:         cfg.Household,
-			Scenarios:         []domain.GenericScenario{*scenario},
-		}
-
-		// Create calculation engine
 		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
-
-		// Run calculation
-		results, err := engine.RunScenarios(tempConfig)
-		if err != nil {
-			return CalculationCompleteMsg{
-				ScenarioName: scenario.Name,
-				Results:      nil,
-				Err:          err,
-			}
-		}
-
-		// Extract the single scenario result
-		if len(results.Scenarios) > 0 {
-			return CalculationCompleteMsg{
-				ScenarioName: scenario.Name,
-				Results:      &results.Scenarios[0],
-				Err:          nil,
-			}
-		}
-
+		summary, err := engine.RunScenario(context.Background(), cfg, scenario, calculation.ScenarioOptions{})
 		return CalculationCompleteMsg{
 			ScenarioName: scenario.Name,
-			Results:      nil,
-			Err:          fmt.Errorf("no results returned from calculation"),
+			Results:      summary,
+			Err:          err,
 		}
 	}
 }
@@ -142,6 +116,7 @@ func calculateScenarioCmd(scenario *domain.GenericScenario, cfg *domain.Configur
 func calculateMultipleScenariosCmd(scenarioNames []string, cfg *domain.Configuration) tea.Cmd {
 	return func() tea.Msg {
 		results := make(map[string]*domain.ScenarioSummary)
+		engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
 
 		// Find and calculate each scenario
 		for _, name := range scenarioNames {
@@ -158,20 +133,9 @@ func calculateMultipleScenariosCmd(scenarioNames []string, cfg *domain.Configura
 				continue
 			}
 
-			// Create temporary config with just this scenario
-			tempConfig := &domain.Configuration{
-				GlobalAssumptions: cfg.GlobalAssumptions,
-				Household:         cfg.Household,
-				Scenarios:         []domain.GenericScenario{*scenario},
-			}
-
-			// Create calculation engine
-			engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
-
-			// Run calculation
-			projResults, err := engine.RunScenarios(tempConfig)
-			if err == nil && len(projResults.Scenarios) > 0 {
-				results[name] = &projResults.Scenarios[0]
+			summary, err := engine.RunScenario(context.Background(), cfg, scenario, calculation.ScenarioOptions{})
+			if err == nil {
+				results[name] = summary
 			}
 		}
 
@@ -118,7 +118,7 @@ func (m *HomeModel) renderConfigOverview() string {
 		// List participants
 		for _, participant := range m.config.Household.Participants {
 			content.WriteString(labelStyle.Render("    • "))
-			content.WriteString(valueStyle.Render(participant.Name))
+			content.WriteString(valueStyle.Render(participant.Label()))
 			if !participant.BirthDate.IsZero() {
 				age := calculateAge(participant.BirthDate)
 				content.WriteString(labelStyle.Render(fmt.Sprintf(" (age %d)", age)))
@@ -18,6 +18,7 @@ import (
 type ParametersModel struct {
 	scenario          *domain.GenericScenario
 	participants      []string
+	labels            domain.ParticipantLabels
 	selectedParticipant int
 	sliders           []*components.ParameterSlider
 	focusedSlider     int
@@ -37,6 +38,11 @@ func NewParametersModel() *ParametersModel {
 	}
 }
 
+// SetParticipantLabels sets the display names shown for participant keys
+func (m *ParametersModel) SetParticipantLabels(labels domain.ParticipantLabels) {
+	m.labels = labels
+}
+
 // SetScenario updates the scenario being edited
 func (m *ParametersModel) SetScenario(scenario *domain.GenericScenario) {
 	if scenario == nil {
@@ -284,7 +290,7 @@ func (m *ParametersModel) View() string {
 	}
 
 	// Build header with participant selector
-	header := renderParticipantSelector(m.participants, m.selectedParticipant)
+	header := renderParticipantSelector(m.participants, m.labels, m.selectedParticipant)
 
 	// Build sliders section
 	slidersView := renderSliders(m.sliders)
@@ -320,7 +326,7 @@ Press ESC to return to home.`
 }
 
 // renderParticipantSelector renders the participant selection header
-func renderParticipantSelector(participants []string, selected int) string {
+func renderParticipantSelector(participants []string, labels domain.ParticipantLabels, selected int) string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(tuistyles.ColorPrimary).
@@ -341,9 +347,9 @@ func renderParticipantSelector(participants []string, selected int) string {
 	var tabs []string
 	for i, name := range participants {
 		if i == selected {
-			tabs = append(tabs, selectedStyle.Render(name))
+			tabs = append(tabs, selectedStyle.Render(labels.Label(name)))
 		} else {
-			tabs = append(tabs, participantStyle.Render(name))
+			tabs = append(tabs, participantStyle.Render(labels.Label(name)))
 		}
 	}
 
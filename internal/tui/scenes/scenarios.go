@@ -18,6 +18,7 @@ import (
 // ScenariosModel represents the scenarios browsing scene
 type ScenariosModel struct {
 	scenarios     []domain.GenericScenario
+	labels        domain.ParticipantLabels
 	selectedIndex int
 	cards         []*components.ScenarioCard
 	width         int
@@ -33,6 +34,11 @@ func NewScenariosModel() *ScenariosModel {
 	}
 }
 
+// SetParticipantLabels sets the display names shown for participant keys
+func (m *ScenariosModel) SetParticipantLabels(labels domain.ParticipantLabels) {
+	m.labels = labels
+}
+
 // SetScenarios updates the scenarios list
 func (m *ScenariosModel) SetScenarios(scenarios []domain.GenericScenario) {
 	m.scenarios = scenarios
@@ -48,7 +54,7 @@ func (m *ScenariosModel) SetScenarios(scenarios []domain.GenericScenario) {
 		if len(scenario.ParticipantScenarios) > 0 {
 			// Get first participant as primary
 			for participantName := range scenario.ParticipantScenarios {
-				card.WithParticipant(participantName)
+				card.WithParticipant(m.labels.Label(participantName))
 				break
 			}
 		}
@@ -162,7 +168,7 @@ func (m *ScenariosModel) View() string {
 
 	// Split view: list on left, details on right
 	leftPane := renderScenarioList(m.cards, m.selectedIndex)
-	rightPane := renderScenarioDetails(m.scenarios[m.selectedIndex])
+	rightPane := renderScenarioDetails(m.scenarios[m.selectedIndex], m.labels)
 
 	// Join horizontally
 	content := lipgloss.JoinHorizontal(
@@ -229,7 +235,7 @@ func renderScenarioList(cards []*components.ScenarioCard, selectedIndex int) str
 }
 
 // renderScenarioDetails renders detailed information about a scenario
-func renderScenarioDetails(scenario domain.GenericScenario) string {
+func renderScenarioDetails(scenario domain.GenericScenario, labels domain.ParticipantLabels) string {
 	detailStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(tuistyles.ColorPrimary).
@@ -262,7 +268,7 @@ func renderScenarioDetails(scenario domain.GenericScenario) string {
 		content.WriteString("\n")
 	} else {
 		for participantName, participant := range scenario.ParticipantScenarios {
-			content.WriteString(valueStyle.Render(fmt.Sprintf("  • %s", participantName)))
+			content.WriteString(valueStyle.Render(fmt.Sprintf("  • %s", labels.Label(participantName))))
 			content.WriteString("\n")
 
 			// Show key details
@@ -66,6 +66,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.homeModel.SetSize(m.width, m.height)
 			}
 			if m.scenariosModel != nil {
+				m.scenariosModel.SetParticipantLabels(msg.Config.Household.ParticipantLabels())
 				m.scenariosModel.SetScenarios(msg.Config.Scenarios)
 				m.scenariosModel.SetSize(m.width, m.height)
 			}
@@ -89,6 +90,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if scenario.Name == msg.ScenarioName {
 					// Make a copy to avoid modifying the original
 					scenarioCopy := scenario.DeepCopy()
+					m.parametersModel.SetParticipantLabels(m.config.Household.ParticipantLabels())
 					m.parametersModel.SetScenario(scenarioCopy)
 					m.parametersModel.SetSize(m.width, m.height)
 					break
@@ -0,0 +1,29 @@
+// Package webcalc exposes the calculation engine through a byte-in/byte-out API
+// suitable for embedding in a browser via WebAssembly. It performs no file or
+// network I/O so plans can be evaluated entirely client-side.
+package webcalc
+
+import (
+	"fmt"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/rgehrsitz/rpgo/internal/output"
+)
+
+// Calculate parses a JSON (or YAML) configuration, runs every scenario and
+// returns the scenario comparison encoded as JSON
+func Calculate(configJSON []byte) ([]byte, error) {
+	cfg, err := config.NewInputParser().Parse(configJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := calculation.NewCalculationEngineWithConfig(cfg.GlobalAssumptions.FederalRules)
+	results, err := engine.RunScenarios(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("calculation failed: %w", err)
+	}
+
+	return output.JSONFormatter{}.Format(results)
+}
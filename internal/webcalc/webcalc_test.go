@@ -0,0 +1,67 @@
+package webcalc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleConfigJSON = `{
+  "household": {
+    "filing_status": "single",
+    "participants": [{
+      "name": "Alice",
+      "is_federal": true,
+      "birth_date": "1965-03-15T00:00:00Z",
+      "hire_date": "1990-06-01T00:00:00Z",
+      "current_salary": 120000,
+      "high_3_salary": 118000,
+      "tsp_balance_traditional": 600000,
+      "tsp_balance_roth": 50000,
+      "tsp_contribution_percent": 0.10,
+      "survivor_benefit_election_percent": 0,
+      "ss_benefit_62": 2000,
+      "ss_benefit_fra": 2800,
+      "ss_benefit_70": 3500
+    }]
+  },
+  "global_assumptions": {
+    "inflation_rate": "0.025",
+    "fehb_premium_inflation": "0.065",
+    "tsp_return_pre_retirement": "0.07",
+    "tsp_return_post_retirement": "0.06",
+    "cola_general_rate": "0.025",
+    "projection_years": 20,
+    "current_location": {"state": "Pennsylvania", "county": "Bucks", "municipality": "Upper Makefield"}
+  },
+  "scenarios": [{
+    "name": "Base",
+    "participant_scenarios": {
+      "Alice": {
+        "participant_name": "Alice",
+        "retirement_date": "2027-06-30T00:00:00Z",
+        "ss_start_age": 67,
+        "tsp_withdrawal_strategy": "4_percent_rule"
+      }
+    }
+  }]
+}`
+
+func TestCalculate(t *testing.T) {
+	out, err := Calculate([]byte(sampleConfigJSON))
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Contains(t, decoded, "scenarios")
+}
+
+func TestCalculate_InvalidConfig(t *testing.T) {
+	_, err := Calculate([]byte(`{"household": {"participants": []}}`))
+	assert.Error(t, err)
+
+	_, err = Calculate([]byte(`{not json`))
+	assert.Error(t, err)
+}
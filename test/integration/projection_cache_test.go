@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rgehrsitz/rpgo/internal/calculation"
+	"github.com/rgehrsitz/rpgo/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProjectionCacheMatchesUncached runs every example scenario, plus variants that
+// move claiming ages and retirement dates, through a caching engine and checks each
+// projection matches one computed from scratch
+func TestProjectionCacheMatchesUncached(t *testing.T) {
+	files := []string{
+		"../testdata/generic_example_config.yaml",
+		"../../example_config_comprehensive.yaml",
+		"../../example_config_variant1.yaml",
+		"../../example_config_variant2.yaml",
+		"../../golden_config.yaml",
+	}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			cfg, err := config.NewInputParser().LoadFromFile(file)
+			require.NoError(t, err)
+
+			scenarios := cfg.Scenarios
+			for _, scenario := range cfg.Scenarios {
+				for _, age := range []int{62, 70} {
+					variant := *scenario.DeepCopy()
+					for name, ps := range variant.ParticipantScenarios {
+						ps.SSStartAge = age
+						variant.ParticipantScenarios[name] = ps
+					}
+					scenarios = append(scenarios, variant)
+				}
+				for _, delay := range []int{2, 4} {
+					variant := *scenario.DeepCopy()
+					for name, ps := range variant.ParticipantScenarios {
+						if ps.RetirementDate != nil {
+							later := ps.RetirementDate.AddDate(delay, 0, 0)
+							ps.RetirementDate = &later
+						}
+						variant.ParticipantScenarios[name] = ps
+					}
+					scenarios = append(scenarios, variant)
+				}
+			}
+
+			cached := calculation.NewCalculationEngine()
+			uncached := calculation.NewCalculationEngine()
+			uncached.ProjectionCache = nil
+			for i := range scenarios {
+				want, err := uncached.RunGenericScenario(context.Background(), cfg, &scenarios[i])
+				if err != nil {
+					continue // infeasible variants fail the same way with or without the cache
+				}
+				got, err := cached.RunGenericScenario(context.Background(), cfg, &scenarios[i])
+				require.NoError(t, err)
+
+				wantJSON, err := json.Marshal(want.Projection)
+				require.NoError(t, err)
+				gotJSON, err := json.Marshal(got.Projection)
+				require.NoError(t, err)
+				require.JSONEq(t, string(wantJSON), string(gotJSON), "scenario %q", scenarios[i].Name)
+			}
+		})
+	}
+}
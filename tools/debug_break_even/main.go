@@ -97,9 +97,9 @@ func main() {
 	}
 }
 
-func sumValues(values map[string]decimal.Decimal) decimal.Decimal {
+func sumValues(values domain.ParticipantValues[decimal.Decimal]) decimal.Decimal {
 	total := decimal.Zero
-	for _, v := range values {
+	for _, v := range values.Values() {
 		total = total.Add(v)
 	}
 	return total
@@ -20,8 +20,8 @@ func main() {
 		return
 	}
 	row := ssProjection[0]
-	fmt.Printf("SSBenefits[person_a]: %s\n", row.SSBenefits["person_a"].StringFixed(2))
-	fmt.Printf("SSBenefits[person_b]: %s\n", row.SSBenefits["person_b"].StringFixed(2))
+	fmt.Printf("SSBenefits[person_a]: %s\n", row.SSBenefits.Get("person_a").StringFixed(2))
+	fmt.Printf("SSBenefits[person_b]: %s\n", row.SSBenefits.Get("person_b").StringFixed(2))
 
 	full := calculation.CalculateSSBenefitForYear(ssEmployee(), 62, 0, decimal.Zero)
 	fmt.Printf("Full-year SS (calc): %s\n", full.StringFixed(2))
@@ -33,8 +33,8 @@ func main() {
 		return
 	}
 	rmdRow := rmdProjection[0]
-	fmt.Printf("TSPWithdrawals[person_a]: %s\n", rmdRow.TSPWithdrawals["person_a"].StringFixed(2))
-	fmt.Printf("TSPBalances[person_a]: %s\n", rmdRow.TSPBalances["person_a"].StringFixed(2))
+	fmt.Printf("TSPWithdrawals[person_a]: %s\n", rmdRow.TSPWithdrawals.Get("person_a").StringFixed(2))
+	fmt.Printf("TSPBalances[person_a]: %s\n", rmdRow.TSPBalances.Get("person_a").StringFixed(2))
 
 	fullRMD := calculation.CalculateRMD(decimal.RequireFromString("500000"), 1953, dateutil.GetRMDAge(1953))
 	fmt.Printf("Full RMD: %s\n", fullRMD.StringFixed(2))